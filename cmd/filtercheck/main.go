@@ -0,0 +1,415 @@
+// Command filtercheck validates the product filters used by the resources
+// against the pricing API. It introspects the GraphQL schema to check that the
+// filter fields are supported and queries the API to check that every service
+// name and attribute key referenced by a resource matches at least one product.
+// It exits with a non-zero status if any filter is invalid so it can be used to
+// fail the build.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+var (
+	sourceDirs = []string{"internal/resources", "internal/providers"}
+
+	introspectionQuery = `{
+  productFilter: __type(name: "ProductFilter") { inputFields { name } }
+  attributeFilter: __type(name: "AttributeFilter") { inputFields { name } }
+}`
+
+	productsQuery = `query($filter: ProductFilter!) { products(filter: $filter) { prices { priceHash } } }`
+)
+
+// filter is a ProductFilter literal found in the source. Only fields that are
+// set to string literals are recorded, all others are left empty and are not
+// validated.
+type filter struct {
+	pos           token.Position
+	vendorName    string
+	service       string
+	attributeKeys []string
+}
+
+type serviceKey struct {
+	vendorName string
+	service    string
+}
+
+type graphQLQuery struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+type client struct {
+	endpoint string
+	apiKey   string
+}
+
+func main() {
+	endpoint := os.Getenv("INFRACOST_PRICING_API_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "https://pricing.api.infracost.io"
+	}
+
+	c := &client{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		apiKey:   os.Getenv("INFRACOST_API_KEY"),
+	}
+
+	filters, err := findFilters(sourceDirs)
+	if err != nil {
+		log.Fatalf("error finding product filters %s", err)
+	}
+
+	if len(filters) == 0 {
+		log.Fatal("error finding product filters, no filters found")
+	}
+
+	var problems []string
+
+	fieldProblems, err := checkFilterFields(c)
+	if err != nil {
+		log.Fatalf("error introspecting pricing API schema %s", err)
+	}
+	problems = append(problems, fieldProblems...)
+
+	valueProblems, err := checkFilterValues(c, filters)
+	if err != nil {
+		log.Fatalf("error querying pricing API %s", err)
+	}
+	problems = append(problems, valueProblems...)
+
+	if len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Fprintln(os.Stderr, p)
+		}
+
+		log.Fatalf("found %d invalid product filters", len(problems))
+	}
+
+	log.Printf("checked %d product filters", len(filters))
+}
+
+// checkFilterFields checks that the fields of schema.ProductFilter and
+// schema.AttributeFilter are input fields of the matching GraphQL types.
+func checkFilterFields(c *client) ([]string, error) {
+	results, err := c.query([]graphQLQuery{{Query: introspectionQuery}})
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+
+	types := map[string]reflect.Type{
+		"productFilter":   reflect.TypeOf(schema.ProductFilter{}),
+		"attributeFilter": reflect.TypeOf(schema.AttributeFilter{}),
+	}
+
+	for name, t := range types {
+		fields := results[0].Get(fmt.Sprintf("data.%s.inputFields.#.name", name)).Array()
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("type %s not found in schema", t.Name())
+		}
+
+		supported := make(map[string]bool, len(fields))
+		for _, f := range fields {
+			supported[f.String()] = true
+		}
+
+		for i := 0; i < t.NumField(); i++ {
+			tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if !supported[tag] {
+				problems = append(problems, fmt.Sprintf("schema.%s.%s: field %q is not supported by the pricing API", t.Name(), t.Field(i).Name, tag))
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+// checkFilterValues checks that every service and every attribute key of a
+// service referenced by the filters matches at least one product.
+func checkFilterValues(c *client, filters []filter) ([]string, error) {
+	services := map[serviceKey][]filter{}
+	for _, f := range filters {
+		if f.vendorName == "" || f.service == "" {
+			continue
+		}
+
+		k := serviceKey{f.vendorName, f.service}
+		services[k] = append(services[k], f)
+	}
+
+	keys := make([]serviceKey, 0, len(services))
+	for k := range services {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].vendorName != keys[j].vendorName {
+			return keys[i].vendorName < keys[j].vendorName
+		}
+		return keys[i].service < keys[j].service
+	})
+
+	var problems []string
+
+	for _, k := range keys {
+		found, err := c.hasProducts(map[string]interface{}{
+			"vendorName": k.vendorName,
+			"service":    k.service,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			for _, f := range services[k] {
+				problems = append(problems, fmt.Sprintf("%s: service %q not found for vendor %q", f.pos, k.service, k.vendorName))
+			}
+			continue
+		}
+
+		checked := map[string]bool{}
+		for _, f := range services[k] {
+			for _, attr := range f.attributeKeys {
+				valid, ok := checked[attr]
+				if !ok {
+					valid, err = c.hasProducts(map[string]interface{}{
+						"vendorName": k.vendorName,
+						"service":    k.service,
+						"attributeFilters": []map[string]string{
+							{"key": attr, "value_regex": "/.+/"},
+						},
+					})
+					if err != nil {
+						return nil, err
+					}
+
+					checked[attr] = valid
+				}
+
+				if !valid {
+					problems = append(problems, fmt.Sprintf("%s: attribute %q not found for service %q", f.pos, attr, k.service))
+				}
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+func (c *client) hasProducts(f map[string]interface{}) (bool, error) {
+	results, err := c.query([]graphQLQuery{{
+		Query:     productsQuery,
+		Variables: map[string]interface{}{"filter": f},
+	}})
+	if err != nil {
+		return false, err
+	}
+
+	return len(results[0].Get("data.products").Array()) > 0, nil
+}
+
+func (c *client) query(queries []graphQLQuery) ([]gjson.Result, error) {
+	body, err := json.Marshal(queries)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.endpoint+"/graphql", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", c.apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("invalid response %s: %s", resp.Status, respBody)
+	}
+
+	results := gjson.ParseBytes(respBody).Array()
+	if len(results) != len(queries) {
+		return nil, fmt.Errorf("expected %d results, got %d", len(queries), len(results))
+	}
+
+	for _, r := range results {
+		if errs := r.Get("errors"); errs.Exists() {
+			return nil, fmt.Errorf("query error: %s", errs.Raw)
+		}
+	}
+
+	return results, nil
+}
+
+// findFilters parses all non-test Go files in dirs and returns the
+// ProductFilter composite literals found in them.
+func findFilters(dirs []string) ([]filter, error) {
+	var filters []filter
+
+	fset := token.NewFileSet()
+
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return nil
+			}
+
+			f, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				return err
+			}
+
+			ast.Inspect(f, func(n ast.Node) bool {
+				lit, ok := n.(*ast.CompositeLit)
+				if !ok || !isProductFilter(lit.Type) {
+					return true
+				}
+
+				filters = append(filters, parseFilter(fset.Position(lit.Pos()), lit))
+				return true
+			})
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return filters, nil
+}
+
+func isProductFilter(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.SelectorExpr:
+		return t.Sel.Name == "ProductFilter"
+	case *ast.Ident:
+		return t.Name == "ProductFilter"
+	}
+
+	return false
+}
+
+func parseFilter(pos token.Position, lit *ast.CompositeLit) filter {
+	f := filter{pos: pos}
+
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		switch key.Name {
+		case "VendorName":
+			f.vendorName = stringValue(kv.Value)
+		case "Service":
+			f.service = stringValue(kv.Value)
+		case "AttributeFilters":
+			f.attributeKeys = attributeKeys(kv.Value)
+		}
+	}
+
+	return f
+}
+
+// attributeKeys returns the literal keys of an []*schema.AttributeFilter
+// composite literal.
+func attributeKeys(expr ast.Expr) []string {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+
+	var keys []string
+
+	for _, elt := range lit.Elts {
+		if u, ok := elt.(*ast.UnaryExpr); ok {
+			elt = u.X
+		}
+
+		attr, ok := elt.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+
+		for _, e := range attr.Elts {
+			kv, ok := e.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			if k, ok := kv.Key.(*ast.Ident); ok && k.Name == "Key" {
+				if v := stringValue(kv.Value); v != "" {
+					keys = append(keys, v)
+				}
+			}
+		}
+	}
+
+	return keys
+}
+
+// stringValue returns the value of a string literal or a strPtr("...") call.
+// An empty string is returned for any other expression.
+func stringValue(expr ast.Expr) string {
+	if call, ok := expr.(*ast.CallExpr); ok && len(call.Args) == 1 {
+		fn, ok := call.Fun.(*ast.Ident)
+		if !ok || fn.Name != "strPtr" {
+			return ""
+		}
+
+		expr = call.Args[0]
+	}
+
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+
+	return s
+}
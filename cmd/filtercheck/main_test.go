@@ -0,0 +1,185 @@
+package main
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindFilters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		src  string
+		want []filter
+	}{
+		{
+			name: "selector filter with strPtr values and attribute filters",
+			src: `package aws
+
+func costComponent() *schema.CostComponent {
+	return &schema.CostComponent{
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Region:        strPtr(region),
+			Service:       strPtr("AmazonEC2"),
+			ProductFamily: strPtr("Compute Instance"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "instanceType", Value: strPtr(instanceType)},
+				{Key: "tenancy", Value: strPtr("Shared")},
+			},
+		},
+	}
+}
+`,
+			want: []filter{
+				{vendorName: "aws", service: "AmazonEC2", attributeKeys: []string{"instanceType", "tenancy"}},
+			},
+		},
+		{
+			name: "ident filter with string literals and pointer attribute filters",
+			src: `package schema
+
+var f = ProductFilter{
+	VendorName: "gcp",
+	Service:    "Compute Engine",
+	AttributeFilters: []*AttributeFilter{
+		&AttributeFilter{Key: "machineType", ValueRegex: strPtr("/n1/")},
+	},
+}
+`,
+			want: []filter{
+				{vendorName: "gcp", service: "Compute Engine", attributeKeys: []string{"machineType"}},
+			},
+		},
+		{
+			name: "non-literal values are left empty",
+			src: `package azure
+
+func filter(service string) *schema.ProductFilter {
+	return &schema.ProductFilter{
+		VendorName: strPtr("azure"),
+		Service:    strPtr(service),
+		AttributeFilters: []*schema.AttributeFilter{
+			{Key: keyName, Value: strPtr("x")},
+			{Key: "skuName", Value: strPtr("x")},
+		},
+	}
+}
+`,
+			want: []filter{
+				{vendorName: "azure", attributeKeys: []string{"skuName"}},
+			},
+		},
+		{
+			name: "values from other functions are not matched",
+			src: `package google
+
+var f = &schema.ProductFilter{
+	VendorName: strPtr("gcp"),
+	Service:    serviceName("Cloud Storage"),
+	AttributeFilters: attributeFilters(),
+}
+`,
+			want: []filter{
+				{vendorName: "gcp"},
+			},
+		},
+		{
+			name: "other composite literals are ignored",
+			src: `package aws
+
+var f = &schema.PriceFilter{
+	PurchaseOption: strPtr("on_demand"),
+}
+
+var c = &schema.CostComponent{
+	Name: "Instance usage",
+}
+`,
+		},
+		{
+			name: "multiple filters are found in order",
+			src: `package aws
+
+var a = &schema.ProductFilter{VendorName: strPtr("aws"), Service: strPtr("AmazonS3")}
+var b = &schema.ProductFilter{VendorName: strPtr("aws"), Service: strPtr("AmazonRDS")}
+`,
+			want: []filter{
+				{vendorName: "aws", service: "AmazonS3"},
+				{vendorName: "aws", service: "AmazonRDS"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			err := os.WriteFile(filepath.Join(dir, "resource.go"), []byte(tt.src), 0600)
+			require.NoError(t, err)
+
+			got, err := findFilters([]string{dir})
+			require.NoError(t, err)
+
+			for i := range got {
+				assert.Equal(t, filepath.Join(dir, "resource.go"), got[i].pos.Filename)
+				assert.Greater(t, got[i].pos.Line, 0)
+				got[i].pos = token.Position{}
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFindFiltersSkipsTestFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := []byte(`package aws
+
+var f = &schema.ProductFilter{VendorName: strPtr("aws"), Service: strPtr("AmazonS3")}
+`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "resource_test.go"), src, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), src, 0600))
+
+	got, err := findFilters([]string{dir})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+// TestFindFiltersInSource guards against refactors of the resources that the
+// AST matching no longer recognizes.
+func TestFindFiltersInSource(t *testing.T) {
+	t.Parallel()
+
+	dirs := make([]string, 0, len(sourceDirs))
+	for _, d := range sourceDirs {
+		dirs = append(dirs, filepath.Join("..", "..", d))
+	}
+
+	filters, err := findFilters(dirs)
+	require.NoError(t, err)
+
+	var withService, withAttributes int
+	for _, f := range filters {
+		if f.vendorName != "" && f.service != "" {
+			withService++
+		}
+		if len(f.attributeKeys) > 0 {
+			withAttributes++
+		}
+	}
+
+	assert.Greater(t, len(filters), 400)
+	assert.Greater(t, withService, len(filters)*8/10, "most filters should have a literal vendor name and service")
+	assert.Greater(t, withAttributes, len(filters)*8/10, "most filters should have literal attribute keys")
+}
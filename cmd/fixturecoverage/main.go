@@ -0,0 +1,334 @@
+// Command fixturecoverage reports which resource types of the Terraform
+// registries lack golden test fixtures. Given a coverage profile of the
+// provider tests it also lists, for each resource type, the code paths that no
+// fixture runs, e.g. the cases of a switch over storage replication types or
+// tiers, so contributors can see at a glance which fixtures to add.
+//
+// It must be run from the root of the repository:
+//
+//	go test -coverpkg=./internal/resources/...,./internal/providers/terraform/... -coverprofile=fixtures.cover ./internal/providers/terraform/...
+//	go run ./cmd/fixturecoverage/main.go --coverprofile fixtures.cover
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
+	"github.com/infracost/infracost/internal/providers/terraform/azure"
+	"github.com/infracost/infracost/internal/providers/terraform/google"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const modulePath = "github.com/infracost/infracost/"
+
+var registries = []struct {
+	provider string
+	items    []*schema.RegistryItem
+}{
+	{"aws", aws.ResourceRegistry},
+	{"azure", azure.ResourceRegistry},
+	{"google", google.ResourceRegistry},
+}
+
+// block is a block of statements in a coverage profile.
+type block struct {
+	startLine int
+	endLine   int
+	stmts     int
+	covered   bool
+}
+
+// resourceReport is the fixture coverage of a resource type.
+type resourceReport struct {
+	name string
+	// files are the provider and core resource files of the resource type,
+	// relative to the root of the repository.
+	files []string
+	// goldens are the golden test fixtures that include the resource type.
+	goldens    []string
+	stmts      int
+	covered    int
+	uncovered  []string
+	hasProfile bool
+}
+
+func main() {
+	var profilePath, provider string
+	var missingOnly bool
+	flag.StringVar(&profilePath, "coverprofile", "", "Coverage profile of the provider tests, used to list the code paths that no fixture runs")
+	flag.StringVar(&provider, "provider", "", "Only report the resource types of this provider: aws, azure or google")
+	flag.BoolVar(&missingOnly, "missing-only", false, "Only report resource types that have no fixtures or uncovered code paths")
+	flag.Parse()
+
+	var profile map[string][]block
+	if profilePath != "" {
+		var err error
+		profile, err = loadProfile(profilePath)
+		if err != nil {
+			log.Fatalf("error loading coverage profile %s", err)
+		}
+	}
+
+	var reports []resourceReport
+	for _, r := range registries {
+		if provider != "" && r.provider != provider {
+			continue
+		}
+
+		goldens, err := loadGoldens(filepath.Join("internal/providers/terraform", r.provider, "testdata"))
+		if err != nil {
+			log.Fatalf("error loading %s golden files %s", r.provider, err)
+		}
+
+		for _, item := range r.items {
+			reports = append(reports, newResourceReport(r.provider, item, goldens, profile))
+		}
+	}
+
+	if len(reports) == 0 {
+		log.Fatalf("no resource types found for provider %q", provider)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].name < reports[j].name
+	})
+
+	writeReports(os.Stdout, reports, missingOnly)
+}
+
+func newResourceReport(provider string, item *schema.RegistryItem, goldens map[string]string, profile map[string][]block) resourceReport {
+	r := resourceReport{name: item.Name, hasProfile: profile != nil}
+
+	providerFile := funcFile(item.RFunc)
+	if providerFile == "" {
+		providerFile = funcFile(item.CoreRFunc)
+	}
+
+	if providerFile != "" {
+		r.files = append(r.files, providerFile)
+
+		// Core resources are in a file with the same name as the provider file.
+		resourceFile := filepath.Join("internal/resources", provider, filepath.Base(providerFile))
+		if _, err := os.Stat(resourceFile); err == nil {
+			r.files = append(r.files, resourceFile)
+		}
+	}
+
+	re := regexp.MustCompile(`(^|[^a-z0-9_])` + regexp.QuoteMeta(item.Name) + `\.`)
+	for dir, content := range goldens {
+		if re.MatchString(content) {
+			r.goldens = append(r.goldens, dir)
+		}
+	}
+	sort.Strings(r.goldens)
+
+	for _, f := range r.files {
+		lines := readLines(f)
+
+		for _, b := range profile[f] {
+			r.stmts += b.stmts
+			if b.covered {
+				r.covered += b.stmts
+				continue
+			}
+
+			source := ""
+			if b.startLine-1 < len(lines) {
+				source = strings.TrimSpace(lines[b.startLine-1])
+			}
+
+			r.uncovered = append(r.uncovered, fmt.Sprintf("%s:%d: %s", f, b.startLine, source))
+		}
+	}
+
+	return r
+}
+
+// funcFile returns the file that the function is declared in, relative to the
+// root of the repository.
+func funcFile(fn interface{}) string {
+	v := reflect.ValueOf(fn)
+	if !v.IsValid() || v.IsNil() {
+		return ""
+	}
+
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return ""
+	}
+
+	file, _ := f.FileLine(f.Entry())
+
+	i := strings.Index(file, "internal/providers/")
+	if i == -1 {
+		return ""
+	}
+
+	return file[i:]
+}
+
+// loadGoldens returns the content of the golden files in dir by the directory
+// of the golden test they belong to.
+func loadGoldens(dir string) (map[string]string, error) {
+	goldens := make(map[string]string)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || filepath.Ext(path) != ".golden" {
+			return nil
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		goldens[filepath.Dir(path)] += string(b)
+		return nil
+	})
+
+	return goldens, err
+}
+
+// loadProfile returns the blocks of a coverage profile by the file they're in,
+// relative to the root of the repository. Profiles generated with -coverpkg
+// can have the same block several times, it's covered if any of them is.
+func loadProfile(path string) (map[string][]block, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	type blockKey struct {
+		file  string
+		block string
+	}
+
+	blocks := make(map[blockKey]*block)
+	var keys []blockKey
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "mode:") || line == "" {
+			continue
+		}
+
+		// Lines are in the format: file:startLine.startCol,endLine.endCol numStmts count
+		i := strings.LastIndex(line, ":")
+		fields := strings.Fields(line[i+1:])
+		if i == -1 || len(fields) != 3 {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+
+		file := strings.TrimPrefix(line[:i], modulePath)
+		pos := strings.Split(fields[0], ",")
+		if len(pos) != 2 {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+
+		startLine, err1 := strconv.Atoi(strings.Split(pos[0], ".")[0])
+		endLine, err2 := strconv.Atoi(strings.Split(pos[1], ".")[0])
+		stmts, err3 := strconv.Atoi(fields[1])
+		count, err4 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+
+		k := blockKey{file, fields[0]}
+		b, ok := blocks[k]
+		if !ok {
+			b = &block{startLine: startLine, endLine: endLine, stmts: stmts}
+			blocks[k] = b
+			keys = append(keys, k)
+		}
+
+		b.covered = b.covered || count > 0
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	profile := make(map[string][]block)
+	for _, k := range keys {
+		profile[k.file] = append(profile[k.file], *blocks[k])
+	}
+
+	for _, bs := range profile {
+		sort.Slice(bs, func(i, j int) bool {
+			return bs[i].startLine < bs[j].startLine
+		})
+	}
+
+	return profile, nil
+}
+
+func readLines(path string) []string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	return strings.Split(string(b), "\n")
+}
+
+func writeReports(w io.Writer, reports []resourceReport, missingOnly bool) {
+	var noFixtures, uncovered int
+
+	for _, r := range reports {
+		if len(r.goldens) == 0 {
+			noFixtures++
+		}
+		if len(r.uncovered) > 0 {
+			uncovered++
+		}
+
+		if missingOnly && len(r.goldens) > 0 && len(r.uncovered) == 0 {
+			continue
+		}
+
+		fmt.Fprintln(w, r.name)
+
+		if len(r.goldens) == 0 {
+			fmt.Fprintln(w, "  Fixtures: none")
+		} else {
+			fmt.Fprintf(w, "  Fixtures: %s\n", strings.Join(r.goldens, ", "))
+		}
+
+		if r.hasProfile {
+			percent := 0.0
+			if r.stmts > 0 {
+				percent = float64(r.covered) / float64(r.stmts) * 100
+			}
+
+			fmt.Fprintf(w, "  Coverage: %.1f%% of %d statements in %s\n", percent, r.stmts, strings.Join(r.files, ", "))
+
+			for _, u := range r.uncovered {
+				fmt.Fprintf(w, "    not run by any fixture: %s\n", u)
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d of %d resource types have no fixtures", noFixtures, len(reports))
+	if len(reports) > 0 && reports[0].hasProfile {
+		fmt.Fprintf(w, ", %d have code paths that no fixture runs", uncovered)
+	}
+	fmt.Fprintln(w)
+}
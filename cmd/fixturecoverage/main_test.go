@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/providers/terraform/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestLoadProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures.cover")
+	err := os.WriteFile(path, []byte(`mode: set
+github.com/infracost/infracost/internal/resources/azure/storage_account.go:10.2,12.3 2 0
+github.com/infracost/infracost/internal/resources/azure/storage_account.go:20.2,21.3 1 0
+github.com/infracost/infracost/internal/resources/azure/storage_account.go:10.2,12.3 2 1
+`), 0600)
+	require.NoError(t, err)
+
+	profile, err := loadProfile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]block{
+		"internal/resources/azure/storage_account.go": {
+			{startLine: 10, endLine: 12, stmts: 2, covered: true},
+			{startLine: 20, endLine: 21, stmts: 1, covered: false},
+		},
+	}, profile)
+}
+
+func TestNewResourceReport(t *testing.T) {
+	var item *schema.RegistryItem
+	for _, i := range azure.ResourceRegistry {
+		if i.Name == "azurerm_storage_account" {
+			item = i
+		}
+	}
+	require.NotNil(t, item)
+
+	// The tests run in the directory of the package, so the paths of the
+	// profile are made relative to the root of the repository.
+	require.NoError(t, os.Chdir("../.."))
+	t.Cleanup(func() { _ = os.Chdir("cmd/fixturecoverage") })
+
+	goldens := map[string]string{
+		"testdata/storage_account_test": " azurerm_storage_account.standard_lrs\n",
+		"testdata/other_test":           " azurerm_storage_account_network_rules.rules\n",
+	}
+	profile := map[string][]block{
+		"internal/resources/azure/storage_account.go": {
+			{startLine: 1, endLine: 1, stmts: 3, covered: true},
+			{startLine: 1, endLine: 1, stmts: 1, covered: false},
+		},
+	}
+
+	r := newResourceReport("azure", item, goldens, profile)
+	assert.Equal(t, []string{"internal/providers/terraform/azure/storage_account.go", "internal/resources/azure/storage_account.go"}, r.files)
+	assert.Equal(t, []string{"testdata/storage_account_test"}, r.goldens)
+	assert.Equal(t, 4, r.stmts)
+	assert.Equal(t, 3, r.covered)
+	assert.Equal(t, []string{"internal/resources/azure/storage_account.go:1: package azure"}, r.uncovered)
+}
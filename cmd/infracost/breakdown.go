@@ -20,11 +20,17 @@ func breakdownCmd(ctx *config.RunContext) *cobra.Command {
 
       terraform plan -out tfplan.binary
       terraform show -json tfplan.binary > plan.json
-      infracost breakdown --path plan.json`,
+      infracost breakdown --path plan.json
+
+  Push monthly cost gauges to a Prometheus Pushgateway:
+
+      infracost breakdown --path /code --format prometheus | curl --data-binary @- http://pushgateway:9091/metrics/job/infracost`,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
-				return err
+			if !isOffline(cmd, ctx.Config) {
+				if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+					return err
+				}
 			}
 
 			err := loadRunFlags(ctx.Config, cmd)
@@ -34,6 +40,11 @@ func breakdownCmd(ctx *config.RunContext) *cobra.Command {
 
 			ctx.SetContextValue("outputFormat", ctx.Config.Format)
 
+			if err := checkOutFileFormat(cmd, ctx.Config.Format); err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
 			err = checkRunConfig(cmd.ErrOrStderr(), ctx.Config)
 			if err != nil {
 				ui.PrintUsage(cmd)
@@ -48,8 +59,25 @@ func breakdownCmd(ctx *config.RunContext) *cobra.Command {
 
 	cmd.Flags().String("out-file", "", "Save output to a file, helpful with format flag")
 	cmd.Flags().Bool("terraform-use-state", false, "Use Terraform state instead of generating a plan. Applicable with --terraform-force-cli")
-	newEnumFlag(cmd, "format", "table", "Output format", []string{"json", "table", "html"})
+	newEnumFlag(cmd, "format", "table", "Output format", []string{"json", "table", "html", "csv", "xlsx", "junit", "prometheus"})
 	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,price,monthlyQuantity,unit,hourlyCost,monthlyCost.\nSupported by table and html output formats")
+	cmd.Flags().Bool("show-resource-summary", false, "Show resource counts by type, provider, coverage and cost. Supported by table and json output formats")
+	cmd.Flags().Bool("show-shared-costs", false, "Split the cost of projects between the projects that consume them, set with consumes_projects in the config file. Supported by table and json output formats")
+	cmd.Flags().Bool("group-by-scope", false, "Subtotal costs by AWS account, Azure resource group or GCP project. Supported by table and json output formats")
+	addGroupByFlag(cmd)
+	addMaxUntaggedCostFlag(cmd)
+	addPricingCoverageFlag(cmd)
+	addTagPolicyFlags(cmd)
+	addPolicyFlags(cmd)
+	addCostHistoryFlags(cmd)
+	addShowEmissionsFlag(cmd)
+	addShowQuickWinsFlag(cmd)
+	addShowGranularityFlag(cmd)
+	addFilterFlag(cmd)
+	addStrictPricingFlag(cmd)
+	addMissingUsageFlag(cmd)
+	addUnknownInputsFlag(cmd)
+	addExpandForeachFlag(cmd)
 
 	// This is deprecated and will show a warning if used without --terraform-force-cli
 	_ = cmd.Flags().MarkHidden("terraform-use-state")
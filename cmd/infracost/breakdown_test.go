@@ -1,6 +1,8 @@
 package main_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,9 +13,12 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	main "github.com/infracost/infracost/cmd/infracost"
 	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/testutil"
 )
 
@@ -203,6 +208,11 @@ func TestBreakdownTerraformDirectoryWithRecursiveModules(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"breakdown", "--path", dir}, &GoldenFileOptions{RunTerraformCLI: true})
 }
 
+func TestBreakdownSharedCostModule(t *testing.T) {
+	dir := path.Join("./testdata", testutil.CalcGoldenFileTestdataDirName())
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"breakdown", "--path", dir}, nil)
+}
+
 func TestBreakdownTerraformFieldsAll(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"breakdown", "--path", "./testdata/example_plan.json", "--usage-file", "./testdata/example_usage.yml", "--fields", "all"}, nil)
 }
@@ -394,6 +404,49 @@ func TestBreakdownTerragruntExtraArgs(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"breakdown", "--path", path.Join("./testdata", testutil.CalcGoldenFileTestdataDirName())}, nil)
 }
 
+// TestBreakdownTerragruntDependencyError checks that the error of a dependency
+// that fails to evaluate is in the warnings of the projects that depend on it.
+func TestBreakdownTerragruntDependencyError(t *testing.T) {
+	pricingAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer pricingAPI.Close()
+
+	outBuf := bytes.NewBuffer([]byte{})
+	main.Run(func(c *config.RunContext) {
+		enableCloud := false
+		c.Config.EnableCloud = &enableCloud
+		c.Config.EventsDisabled = true
+		c.Config.APIKey = "test"
+		c.Config.PricingAPIEndpoint = pricingAPI.URL
+		c.Config.NoColor = true
+		c.ErrWriter = bytes.NewBuffer([]byte{})
+		c.OutWriter = outBuf
+		c.Exit = func(code int) {}
+	}, &[]string{"breakdown", "--path", "./testdata/breakdown_terragrunt_dependency_error", "--format", "json"})
+
+	var out struct {
+		Projects []struct {
+			Metadata schema.ProjectMetadata `json:"metadata"`
+		} `json:"projects"`
+	}
+	require.NoError(t, json.Unmarshal(outBuf.Bytes(), &out))
+
+	diags := map[string][]schema.ProjectDiag{}
+	for _, p := range out.Projects {
+		diags[filepath.Base(p.Metadata.Path)] = append(p.Metadata.Errors, p.Metadata.Warnings...)
+	}
+
+	require.Len(t, diags["broken"], 1)
+	assert.Equal(t, schema.DiagTerragruntEvaluationFailure, diags["broken"][0].Code)
+	assert.Contains(t, diags["broken"][0].Message, "Cannot have less than 1 max retry, but you specified 0")
+
+	require.Len(t, diags["dev"], 1)
+	assert.Equal(t, schema.DiagTerragruntDependencyEvaluationFailure, diags["dev"][0].Code)
+	assert.Contains(t, diags["dev"][0].Message, "could not evaluate dependency broken at dir")
+	assert.Contains(t, diags["dev"][0].Message, "Cannot have less than 1 max retry, but you specified 0")
+}
+
 func TestInstanceWithAttachmentBeforeDeploy(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"breakdown", "--path", "./testdata/instance_with_attachment_before_deploy.json"}, nil)
 }
@@ -1,14 +1,11 @@
 package main
 
 import (
-	"context"
 	"errors"
 	"fmt"
-	"os"
 	"strconv"
 
-	"github.com/open-policy-agent/opa/ast"
-	"github.com/open-policy-agent/opa/rego"
+	"github.com/shopspring/decimal"
 	"github.com/spf13/cobra"
 
 	"github.com/infracost/infracost/internal/clierror"
@@ -21,7 +18,25 @@ func commentCmd(ctx *config.RunContext) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "comment",
 		Short: "Post an Infracost comment to GitHub, GitLab, Azure Repos or Bitbucket",
-		Long:  "Post an Infracost comment to GitHub, GitLab, Azure Repos or Bitbucket",
+		Long: `Post an Infracost comment to GitHub, GitLab, Azure Repos or Bitbucket.
+
+Cost changes smaller than --min-cost-change or --min-percent-change are hidden,
+and increases of at least --warning-cost-increase or --critical-cost-increase
+are highlighted. Defaults for these can be saved with 'infracost configure set',
+e.g. infracost configure set comment_min_cost_change 25.
+
+The comment can be rendered with your own Go template using --template-path.
+The template is executed with the same data as the default comment, e.g.
+{{ .Root.Projects }} and {{ .Options.PolicyChecks }}, and can use the sprig
+functions and these helpers:
+
+  formatCost, formatCost2DP, formatPrice   format costs in the output currency
+  formatCostChange PAST COST               format the change between two costs
+  diffArrow COST                           ↑ for increases and ↓ for decreases
+  projectsAbove THRESHOLD PROJECTS         projects with a cost change of at least THRESHOLD
+  resourcesAbove THRESHOLD RESOURCES       resources with a cost of at least THRESHOLD
+  costChangeSeverity PAST COST             warning or critical if the increase crosses the thresholds
+  largeCostIncreases                       resources whose increases cross the thresholds`,
 		Example: `  Update the Infracost comment on a GitHub pull request:
 
       infracost comment github --repo my-org/my-repo --pull-request 3 --path infracost.json --behavior update --github-token $GITHUB_TOKEN
@@ -32,7 +47,11 @@ func commentCmd(ctx *config.RunContext) *cobra.Command {
 
   Post a new comment to an Azure Repos pull request:
 
-      infracost comment azure-repos --repo-url https://dev.azure.com/my-org/my-project/_git/my-repo --pull-request 3 --path infracost.json --behavior new --azure-access-token $AZURE_ACCESS_TOKEN`,
+      infracost comment azure-repos --repo-url https://dev.azure.com/my-org/my-project/_git/my-repo --pull-request 3 --path infracost.json --behavior new --azure-access-token $AZURE_ACCESS_TOKEN
+
+  Post a comment rendered with a custom template:
+
+      infracost comment github --repo my-org/my-repo --pull-request 3 --path infracost.json --template-path comment.tmpl --github-token $GITHUB_TOKEN`,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
@@ -42,9 +61,16 @@ func commentCmd(ctx *config.RunContext) *cobra.Command {
 	cmds := []*cobra.Command{commentGitHubCmd(ctx), commentGitLabCmd(ctx), commentAzureReposCmd(ctx), commentBitbucketCmd(ctx)}
 	for _, subCmd := range cmds {
 		subCmd.Flags().StringArray("policy-path", nil, "Path to Infracost policy files, glob patterns need quotes (experimental)")
+		addIgnoresFileFlag(subCmd)
+		subCmd.Flags().String("template-path", "", "Path to a Go template file to render the comment with instead of the default, see 'infracost comment --help' for the helper functions")
+		_ = subCmd.MarkFlagFilename("template-path", "tmpl")
 		subCmd.Flags().Bool("show-all-projects", false, "Show all projects in the table of the comment output")
 		subCmd.Flags().Bool("show-changed", false, "Show only projects in the table that have code changes")
 		_ = subCmd.Flags().MarkHidden("show-changed")
+		addFilterFlag(subCmd)
+		addCommentThresholdFlags(subCmd)
+		addShowQuickWinsFlag(subCmd)
+		addExcludeProjectPathFlag(subCmd)
 		subCmd.Flags().Bool("skip-no-diff", false, "Skip posting comment if there are no resource changes. Only applies to update, hide-and-new, and delete-and-new behaviors")
 		_ = subCmd.Flags().MarkHidden("skip-no-diff")
 		subCmd.Flags().String("guardrail-check-path", "", "Path to Infracost guardrail data (experimental)")
@@ -56,19 +82,29 @@ func commentCmd(ctx *config.RunContext) *cobra.Command {
 	return cmd
 }
 
-func buildCommentBody(cmd *cobra.Command, ctx *config.RunContext, paths []string, mdOpts output.MarkdownOptions) ([]byte, bool, error) {
+// buildCommentBody returns the markdown of the comment for the Infracost JSON
+// files at paths, whether they have any resource or cost changes and the
+// fingerprint of the material content of the comment.
+func buildCommentBody(cmd *cobra.Command, ctx *config.RunContext, paths []string, mdOpts output.MarkdownOptions) ([]byte, bool, string, error) {
 	hasDiff := false
 
 	inputs, err := output.LoadPaths(paths)
 	if err != nil {
-		return nil, hasDiff, err
+		return nil, hasDiff, "", err
 	}
 
 	combined, err := output.Combine(inputs)
 	if errors.As(err, &clierror.WarningError{}) {
 		ui.PrintWarningf(cmd.ErrOrStderr(), err.Error())
 	} else if err != nil {
-		return nil, hasDiff, err
+		return nil, hasDiff, "", err
+	}
+
+	filters, _ := cmd.Flags().GetStringArray("filter")
+	excludePaths, _ := cmd.Flags().GetStringSlice("exclude-path")
+	err = applyFilters(&combined, filters, excludePaths)
+	if err != nil {
+		return nil, hasDiff, "", err
 	}
 
 	hasDiff = combined.HasDiff()
@@ -90,16 +126,22 @@ func buildCommentBody(cmd *cobra.Command, ctx *config.RunContext, paths []string
 	if guardrailCheckPath != "" {
 		guardrailCheck, err = output.LoadGuardrailCheck(guardrailCheckPath)
 		if err != nil {
-			return nil, hasDiff, fmt.Errorf("Error loading %s used by --guardrail-check-path flag. %s", guardrailCheckPath, err)
+			return nil, hasDiff, "", fmt.Errorf("Error loading %s used by --guardrail-check-path flag. %s", guardrailCheckPath, err)
 		}
 	}
 
 	var policyChecks output.PolicyCheck
 	policyPaths, _ := cmd.Flags().GetStringArray("policy-path")
 	if len(policyPaths) > 0 {
-		policyChecks, err = queryPolicy(policyPaths, combined)
+		ignoresFile, _ := cmd.Flags().GetString("ignores-file")
+		ignores, err := output.LoadIgnores(ignoresFile)
+		if err != nil {
+			return nil, hasDiff, "", err
+		}
+
+		policyChecks, err = queryPolicy(policyPaths, ignores, combined)
 		if err != nil {
-			return nil, hasDiff, err
+			return nil, hasDiff, "", err
 		}
 
 		ctx.SetContextValue("passedPolicyCount", len(policyChecks.Passed))
@@ -115,113 +157,108 @@ func buildCommentBody(cmd *cobra.Command, ctx *config.RunContext, paths []string
 	}
 	opts.ShowAllProjects, _ = cmd.Flags().GetBool("show-all-projects")
 	opts.ShowOnlyChanges, _ = cmd.Flags().GetBool("show-changed")
+	opts.TemplatePath, _ = cmd.Flags().GetString("template-path")
+	opts.ShowQuickWins, _ = cmd.Flags().GetBool("show-quick-wins")
+	opts.CommentThresholds, err = commentThresholds(cmd, ctx)
+	if err != nil {
+		return nil, hasDiff, "", err
+	}
 
 	b, err := output.ToMarkdown(combined, opts, mdOpts)
 	if err != nil {
-		return nil, hasDiff, err
+		return nil, hasDiff, "", err
 	}
 
+	fingerprint := output.Fingerprint(combined, opts)
+
 	if policyChecks.HasFailed() {
-		return b, hasDiff, policyChecks.Failures
+		return b, hasDiff, fingerprint, policyChecks.Failures
 	}
 	if len(guardrailCheck.BlockingFailures()) > 0 {
-		return b, hasDiff, guardrailCheck.BlockingFailures()
+		return b, hasDiff, fingerprint, guardrailCheck.BlockingFailures()
 	}
 
-	return b, hasDiff, nil
+	return b, hasDiff, fingerprint, nil
 }
 
-type PRNumber int
-
-func (p *PRNumber) Set(value string) error {
-	if value == "" {
-		return nil
-	}
-
-	v, err := strconv.Atoi(value)
-	*p = PRNumber(v)
-
-	if err != nil {
-		return errors.New("must be integer")
-	}
-
-	return nil
+// addCommentThresholdFlags adds the flags that hide small cost changes and
+// highlight large cost increases in comments.
+func addCommentThresholdFlags(cmd *cobra.Command) {
+	cmd.Flags().Float64("min-cost-change", 0, "Hide projects and resources with a monthly cost change smaller than this in the comment, e.g. 25")
+	cmd.Flags().Float64("min-percent-change", 0, "Hide projects and resources with a monthly cost change smaller than this percentage in the comment, e.g. 5")
+	cmd.Flags().Float64("warning-cost-increase", 0, "Highlight monthly cost increases of at least this as warnings in the comment, e.g. 100")
+	cmd.Flags().Float64("critical-cost-increase", 0, "Highlight monthly cost increases of at least this as critical in the comment, e.g. 1000")
 }
 
-func (p *PRNumber) String() string {
-	return fmt.Sprintf("%d", *p)
-}
+// commentThresholds returns the comment thresholds from the flags, using the
+// values saved in the global configuration for flags that aren't set.
+func commentThresholds(cmd *cobra.Command, ctx *config.RunContext) (output.CostChangeThresholds, error) {
+	value := func(flag string, saved *float64) (float64, error) {
+		v := 0.0
+		if cmd.Flags().Changed(flag) {
+			v, _ = cmd.Flags().GetFloat64(flag)
+		} else if saved != nil {
+			v = *saved
+		}
 
-func (p *PRNumber) Type() string {
-	return "int"
-}
+		if v < 0 {
+			return 0, fmt.Errorf("--%s must not be negative", flag)
+		}
 
-func queryPolicy(policyPaths []string, input output.Root) (output.PolicyCheck, error) {
-	checks := output.PolicyCheck{
-		Enabled: true,
+		return v, nil
 	}
 
-	inputValue, err := ast.InterfaceToValue(input)
-	if err != nil {
-		return checks, fmt.Errorf("Unable to process Infracost output into Rego input: %s", err.Error())
-	}
-
-	ctx := context.Background()
-	r := rego.New(
-		rego.Query("data.infracost.deny"),
-		rego.ParsedInput(inputValue),
-		rego.Load(policyPaths, func(abspath string, info os.FileInfo, depth int) bool {
-			return false
-		}),
-	)
-	pq, err := r.PrepareForEval(ctx)
+	conf := ctx.Config.Configuration
+
+	minCostChange, err := value("min-cost-change", conf.CommentMinCostChange)
 	if err != nil {
-		return checks, fmt.Errorf("Unable to query provided policies: %s", err.Error())
+		return output.CostChangeThresholds{}, err
 	}
 
-	res, err := pq.Eval(ctx)
+	minPercentChange, err := value("min-percent-change", conf.CommentMinPercentChange)
 	if err != nil {
-		return checks, err
+		return output.CostChangeThresholds{}, err
 	}
 
-	if len(res) == 0 {
-		return checks, fmt.Errorf("The provided polices returned no valid data.infracost.deny rules. Please check that the policies are formatted correctly.")
+	warningCostIncrease, err := value("warning-cost-increase", conf.CommentWarningCostIncrease)
+	if err != nil {
+		return output.CostChangeThresholds{}, err
 	}
 
-	for _, e := range res[0].Expressions {
-		switch v := e.Value.(type) {
-		case map[string]interface{}:
-			readPolicyOut(v, &checks)
-		case []interface{}:
-			for _, ii := range v {
-				if m, ok := ii.(map[string]interface{}); ok {
-					readPolicyOut(m, &checks)
-				}
-			}
-		}
+	criticalCostIncrease, err := value("critical-cost-increase", conf.CommentCriticalCostIncrease)
+	if err != nil {
+		return output.CostChangeThresholds{}, err
 	}
 
-	return checks, nil
+	return output.CostChangeThresholds{
+		MinCostChange:        decimal.NewFromFloat(minCostChange),
+		MinPercentChange:     minPercentChange,
+		WarningCostIncrease:  decimal.NewFromFloat(warningCostIncrease),
+		CriticalCostIncrease: decimal.NewFromFloat(criticalCostIncrease),
+	}, nil
 }
 
-func readPolicyOut(v map[string]interface{}, checks *output.PolicyCheck) {
-	if _, ok := v["msg"]; !ok {
-		checks.Failures = append(checks.Failures, "Policy rule invalid as it did not contain {msg: string} property in output object. Please edit rule output object.")
-		return
-	}
-	msg := v["msg"].(string)
+type PRNumber int
 
-	if _, ok := v["failed"]; !ok {
-		checks.Failures = append(checks.Failures, fmt.Sprintf("Policy rule: [%s] did not contain {failed: bool} output property. Please edit rule output object.", msg))
-		return
+func (p *PRNumber) Set(value string) error {
+	if value == "" {
+		return nil
 	}
 
-	failed, _ := v["failed"].(bool)
+	v, err := strconv.Atoi(value)
+	*p = PRNumber(v)
 
-	if failed {
-		checks.Failures = append(checks.Failures, msg)
-		return
+	if err != nil {
+		return errors.New("must be integer")
 	}
 
-	checks.Passed = append(checks.Passed, msg)
+	return nil
+}
+
+func (p *PRNumber) String() string {
+	return fmt.Sprintf("%d", *p)
+}
+
+func (p *PRNumber) Type() string {
+	return "int"
 }
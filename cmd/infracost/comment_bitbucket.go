@@ -15,7 +15,7 @@ import (
 	"github.com/infracost/infracost/internal/ui"
 )
 
-var validCommentBitbucketBehaviors = []string{"update", "new", "delete-and-new"}
+var validCommentBitbucketBehaviors = []string{"update", "new", "hide-and-new", "delete-and-new"}
 
 func commentBitbucketCmd(ctx *config.RunContext) *cobra.Command {
 	cmd := &cobra.Command{
@@ -79,7 +79,7 @@ func commentBitbucketCmd(ctx *config.RunContext) *cobra.Command {
 
 			paths, _ := cmd.Flags().GetStringArray("path")
 
-			body, hasDiff, err := buildCommentBody(cmd, ctx, paths, output.MarkdownOptions{
+			body, hasDiff, fingerprint, err := buildCommentBody(cmd, ctx, paths, output.MarkdownOptions{
 				WillUpdate:          prNumber != 0 && behavior == "update",
 				WillReplace:         prNumber != 0 && behavior == "delete-and-new",
 				IncludeFeedbackLink: !ctx.Config.IsSelfHosted(),
@@ -102,6 +102,7 @@ func commentBitbucketCmd(ctx *config.RunContext) *cobra.Command {
 			if !dryRun {
 				skipNoDiff, _ := cmd.Flags().GetBool("skip-no-diff")
 
+				commentHandler.Fingerprint = fingerprint
 				posted, err := commentHandler.CommentWithBehavior(ctx.Context(), !hasDiff && skipNoDiff, behavior, string(body))
 				if err != nil {
 					return err
@@ -137,6 +138,7 @@ func commentBitbucketCmd(ctx *config.RunContext) *cobra.Command {
 	cmd.Flags().String("behavior", "update", `Behavior when posting comment, one of:
   update (default)  Update latest comment
   new               Create a new comment
+  hide-and-new      Mark previous matching comments as outdated and create a new comment
   delete-and-new    Delete previous matching comments and create a new comment`)
 	_ = cmd.RegisterFlagCompletionFunc("behavior", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return validCommentBitbucketBehaviors, cobra.ShellCompDirectiveDefault
@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
@@ -31,14 +32,33 @@ func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
 
   Post a new comment to a commit:
 
-      infracost comment github --repo my-org/my-repo --commit 2ca7182 --path infracost.json --behavior hide-and-new --github-token $GITHUB_TOKEN`,
+      infracost comment github --repo my-org/my-repo --commit 2ca7182 --path infracost.json --behavior hide-and-new --github-token $GITHUB_TOKEN
+
+  Update comment on a GitHub Enterprise Server pull request:
+
+      infracost comment github --github-api-url https://github.example.com/api/v3 --repo my-org/my-repo --pull-request 3 --path infracost.json --github-token $GITHUB_TOKEN`,
 		ValidArgs: []string{"--", "-"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// The token can be set in the environment instead, e.g. by the
+			// GitHub App server, so it isn't visible in the arguments of the
+			// process.
+			if envToken := os.Getenv("INFRACOST_GITHUB_TOKEN"); envToken != "" && !cmd.Flags().Changed("github-token") {
+				return cmd.Flags().Set("github-token", envToken)
+			}
+
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx.SetContextValue("platform", "github")
 
 			var err error
 
 			apiURL, _ := cmd.Flags().GetString("github-api-url")
+			if envAPIURL := os.Getenv("GITHUB_API_URL"); envAPIURL != "" && !cmd.Flags().Changed("github-api-url") {
+				// GitHub Actions sets this to the API of the GitHub Enterprise
+				// Server that the workflow runs on.
+				apiURL = envAPIURL
+			}
 			token, _ := cmd.Flags().GetString("github-token")
 			tag, _ := cmd.Flags().GetString("tag")
 
@@ -104,7 +124,7 @@ func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
 
 			paths, _ := cmd.Flags().GetStringArray("path")
 
-			body, hasDiff, err := buildCommentBody(cmd, ctx, paths, output.MarkdownOptions{
+			body, hasDiff, fingerprint, err := buildCommentBody(cmd, ctx, paths, output.MarkdownOptions{
 				WillUpdate:          prNumber != 0 && behavior == "update",
 				WillReplace:         prNumber != 0 && behavior == "delete-and-new",
 				IncludeFeedbackLink: !ctx.Config.IsSelfHosted(),
@@ -126,6 +146,7 @@ func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
 			if !dryRun {
 				skipNoDiff, _ := cmd.Flags().GetBool("skip-no-diff")
 
+				commentHandler.Fingerprint = fingerprint
 				posted, err := commentHandler.CommentWithBehavior(ctx.Context(), !hasDiff && skipNoDiff, behavior, string(body))
 				if err != nil {
 					return err
@@ -169,8 +190,8 @@ func commentGitHubCmd(ctx *config.RunContext) *cobra.Command {
 		return validCommentGitHubBehaviors, cobra.ShellCompDirectiveDefault
 	})
 	cmd.Flags().String("commit", "", "Commit SHA to post comment on, mutually exclusive with pull-request")
-	cmd.Flags().String("github-api-url", "https://api.github.com", "GitHub API URL")
-	cmd.Flags().String("github-token", "", "GitHub token")
+	cmd.Flags().String("github-api-url", "https://api.github.com", "GitHub API URL, defaults to GITHUB_API_URL if set. GitHub Enterprise Server URLs can end in /api/v3")
+	cmd.Flags().String("github-token", "", "GitHub token, defaults to INFRACOST_GITHUB_TOKEN if set")
 	_ = cmd.MarkFlagRequired("github-token")
 	cmd.Flags().String("github-tls-cert-file", "", "Path to optional client certificate file when communicating with GitHub Enterprise API")
 	cmd.Flags().String("github-tls-key-file", "", "Path to optional client key file when communicating with GitHub Enterprise API")
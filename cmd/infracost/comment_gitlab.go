@@ -15,7 +15,7 @@ import (
 	"github.com/infracost/infracost/internal/ui"
 )
 
-var validCommentGitLabBehaviors = []string{"update", "new", "delete-and-new"}
+var validCommentGitLabBehaviors = []string{"update", "new", "hide-and-new", "delete-and-new"}
 
 func commentGitLabCmd(ctx *config.RunContext) *cobra.Command {
 	cmd := &cobra.Command{
@@ -77,7 +77,7 @@ func commentGitLabCmd(ctx *config.RunContext) *cobra.Command {
 
 			paths, _ := cmd.Flags().GetStringArray("path")
 
-			body, hasDiff, err := buildCommentBody(cmd, ctx, paths, output.MarkdownOptions{
+			body, hasDiff, fingerprint, err := buildCommentBody(cmd, ctx, paths, output.MarkdownOptions{
 				WillUpdate:          mrNumber != 0 && behavior == "update",
 				WillReplace:         mrNumber != 0 && behavior == "delete-and-new",
 				IncludeFeedbackLink: !ctx.Config.IsSelfHosted(),
@@ -98,6 +98,7 @@ func commentGitLabCmd(ctx *config.RunContext) *cobra.Command {
 			if !dryRun {
 				skipNoDiff, _ := cmd.Flags().GetBool("skip-no-diff")
 
+				commentHandler.Fingerprint = fingerprint
 				posted, err := commentHandler.CommentWithBehavior(ctx.Context(), !hasDiff && skipNoDiff, behavior, string(body))
 				if err != nil {
 					return err
@@ -133,6 +134,7 @@ func commentGitLabCmd(ctx *config.RunContext) *cobra.Command {
 	cmd.Flags().String("behavior", "update", `Behavior when posting comment, one of:
   update (default)  Update latest comment
   new               Create a new comment
+  hide-and-new      Collapse previous matching comments and create a new comment
   delete-and-new    Delete previous matching comments and create a new comment`)
 	_ = cmd.RegisterFlagCompletionFunc("behavior", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return validCommentGitLabBehaviors, cobra.ShellCompDirectiveDefault
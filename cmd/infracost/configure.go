@@ -21,6 +21,12 @@ var supportedConfigureKeys = map[string]struct{}{
 	"disable_hcl":              {},
 	"tls_insecure_skip_verify": {},
 	"tls_ca_cert_file":         {},
+	"include_free_tier":        {},
+
+	"comment_min_cost_change":        {},
+	"comment_min_percent_change":     {},
+	"comment_warning_cost_increase":  {},
+	"comment_critical_cost_increase": {},
 }
 
 func configureCmd(ctx *config.RunContext) *cobra.Command {
@@ -104,6 +110,14 @@ func configureSetCmd(ctx *config.RunContext) *cobra.Command {
 			case "currency":
 				ctx.Config.Configuration.Currency = value
 				saveConfiguration = true
+			case "include_free_tier":
+				b, err := strconv.ParseBool(value)
+				if err != nil {
+					return errors.New("Invalid value, must be true or false")
+				}
+
+				ctx.Config.Configuration.IncludeFreeTier = &b
+				saveConfiguration = true
 			case "disable_hcl":
 				b, err := strconv.ParseBool(value)
 				if err != nil {
@@ -138,6 +152,19 @@ func configureSetCmd(ctx *config.RunContext) *cobra.Command {
 
 				ctx.Config.Configuration.EnableCloud = &b
 				saveConfiguration = true
+			case "comment_min_cost_change", "comment_min_percent_change", "comment_warning_cost_increase", "comment_critical_cost_increase":
+				var f *float64
+				if value != "" {
+					v, err := strconv.ParseFloat(value, 64)
+					if err != nil || v < 0 {
+						return errors.New("Invalid value, must be a number that isn't negative")
+					}
+
+					f = &v
+				}
+
+				*commentThresholdSetting(&ctx.Config.Configuration, key) = f
+				saveConfiguration = true
 			}
 
 			if saveCredentials {
@@ -264,6 +291,16 @@ func configureGetCmd(ctx *config.RunContext) *cobra.Command {
 				} else {
 					value = strconv.FormatBool(*ctx.Config.Configuration.EnableCloud)
 				}
+			case "include_free_tier":
+				if ctx.Config.Configuration.IncludeFreeTier == nil {
+					value = ""
+				} else {
+					value = strconv.FormatBool(*ctx.Config.Configuration.IncludeFreeTier)
+				}
+			case "comment_min_cost_change", "comment_min_percent_change", "comment_warning_cost_increase", "comment_critical_cost_increase":
+				if f := *commentThresholdSetting(&ctx.Config.Configuration, key); f != nil {
+					value = strconv.FormatFloat(*f, 'f', -1, 64)
+				}
 			}
 
 			if value != "" {
@@ -277,6 +314,21 @@ func configureGetCmd(ctx *config.RunContext) *cobra.Command {
 	return cmd
 }
 
+// commentThresholdSetting returns the configuration field of the comment
+// threshold key.
+func commentThresholdSetting(conf *config.Configuration, key string) **float64 {
+	switch key {
+	case "comment_min_percent_change":
+		return &conf.CommentMinPercentChange
+	case "comment_warning_cost_increase":
+		return &conf.CommentWarningCostIncrease
+	case "comment_critical_cost_increase":
+		return &conf.CommentCriticalCostIncrease
+	}
+
+	return &conf.CommentMinCostChange
+}
+
 func isValidConfigureKey(key string) bool {
 	_, ok := supportedConfigureKeys[key]
 
@@ -291,6 +343,11 @@ Supported settings:
   - currency: convert output from USD to your preferred currency
   - tls_insecure_skip_verify: skip TLS certificate checks for a self-hosted Cloud Pricing API
   - tls_ca_cert_file: verify certificate of a self-hosted Cloud Pricing API using this CA certificate
+  - include_free_tier: deduct always-free allowances of cloud providers, e.g. the first 1M Lambda requests, from costs
+  - comment_min_cost_change: hide monthly cost changes smaller than this in comments
+  - comment_min_percent_change: hide monthly cost changes smaller than this percentage in comments
+  - comment_warning_cost_increase: highlight monthly cost increases of at least this as warnings in comments
+  - comment_critical_cost_increase: highlight monthly cost increases of at least this as critical in comments
 `
 
 	return fmt.Sprintf("%s.\n%s", description, settings)
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
 	"github.com/spf13/cobra"
 
 	"github.com/infracost/infracost/internal/apiclient"
@@ -12,6 +13,7 @@ import (
 	"github.com/infracost/infracost/internal/logging"
 	"github.com/infracost/infracost/internal/output"
 	"github.com/infracost/infracost/internal/providers"
+	"github.com/infracost/infracost/internal/providers/terraform"
 	"github.com/infracost/infracost/internal/ui"
 )
 
@@ -30,11 +32,17 @@ func diffCmd(ctx *config.RunContext) *cobra.Command {
 
       terraform plan -out tfplan.binary
       terraform show -json tfplan.binary > plan.json
-      infracost diff --path plan.json`,
+      infracost diff --path plan.json
+
+  Include a change to the expected usage, where --usage-file is the usage after the change:
+
+      infracost diff --path plan.json --usage-file-before usage-before.yml --usage-file usage-after.yml`,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
-				return err
+			if !isOffline(cmd, ctx.Config) {
+				if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+					return err
+				}
 			}
 
 			err := loadRunFlags(ctx.Config, cmd)
@@ -60,9 +68,22 @@ func diffCmd(ctx *config.RunContext) *cobra.Command {
 
 	addRunFlags(cmd)
 
-	cmd.Flags().String("compare-to", "", "Path to Infracost JSON file to compare against")
-	newEnumFlag(cmd, "format", "diff", "Output format", []string{"json", "diff"})
+	cmd.Flags().String("usage-file-before", "", "Path to Infracost usage file for the current state, so usage changes are included in the diff. --usage-file is used for the planned state, and for both states if this isn't set")
+	_ = cmd.MarkFlagFilename("usage-file-before", "yml")
+
+	cmd.Flags().String("compare-to", "", "Path to Infracost JSON file to compare against, e.g. a breakdown or a snapshot saved by 'infracost snapshot save'")
+	newEnumFlag(cmd, "format", "diff", "Output format", []string{"json", "diff", "sarif"})
 	cmd.Flags().String("out-file", "", "Save output to a file")
+	addOwnershipFlags(cmd)
+	addPricingCoverageFlag(cmd)
+	addTagPolicyFlags(cmd)
+	addPolicyFlags(cmd)
+	addFilterFlag(cmd)
+	addStrictPricingFlag(cmd)
+	addMissingUsageFlag(cmd)
+	addUnknownInputsFlag(cmd)
+	addExpandForeachFlag(cmd)
+	addCostIncreaseThresholdFlag(cmd)
 
 	return cmd
 }
@@ -96,13 +117,43 @@ func runCompare(cmd *cobra.Command, ctx *config.RunContext, current output.Root)
 		return err
 	}
 
+	err = applyFilters(&combined, ctx.Config.Filters, nil)
+	if err != nil {
+		return err
+	}
+
+	var coverageErr error
+	if ctx.Config.MinPricingCoverage > 0 {
+		coverageErr = output.CheckPricingCoverage(&combined, ctx.Config.MinPricingCoverage)
+	}
+
+	tagPolicyErr := checkTagPolicy(&combined, ctx.Config.TagPolicyPath, ctx.Config.FailOnTagPolicy)
+
+	var policyChecks output.PolicyCheck
+	if len(ctx.Config.PolicyPaths) > 0 {
+		var ignores []output.Ignore
+		ignores, err = output.LoadIgnores(ctx.Config.IgnoresFile)
+		if err != nil {
+			return err
+		}
+
+		policyChecks, err = queryPolicy(ctx.Config.PolicyPaths, ignores, combined)
+		if err != nil {
+			return err
+		}
+	}
+
 	format, _ := cmd.Flags().GetString("format")
 	b, err := output.FormatOutput(strings.ToLower(format), combined, output.Options{
-		DashboardEndpoint: ctx.Config.DashboardEndpoint,
-		ShowSkipped:       ctx.Config.ShowSkipped,
-		NoColor:           ctx.Config.NoColor,
-		Fields:            ctx.Config.Fields,
-		CurrencyFormat:    ctx.Config.CurrencyFormat,
+		DashboardEndpoint:       ctx.Config.DashboardEndpoint,
+		ShowSkipped:             ctx.Config.ShowSkipped,
+		NoColor:                 ctx.Config.NoColor,
+		Fields:                  ctx.Config.Fields,
+		CurrencyFormat:          ctx.Config.CurrencyFormat,
+		OwnershipTag:            ctx.Config.OwnershipTag,
+		OwnershipDriftThreshold: decimal.NewFromFloat(ctx.Config.OwnershipDriftThreshold),
+		CostIncreaseThreshold:   decimal.NewFromFloat(ctx.Config.CostIncreaseThreshold),
+		PolicyChecks:            policyChecks,
 	})
 	if err != nil {
 		return err
@@ -115,20 +166,212 @@ func runCompare(cmd *cobra.Command, ctx *config.RunContext, current output.Root)
 	}
 
 	if outFile, _ := cmd.Flags().GetString("out-file"); outFile != "" {
-		return saveOutFile(ctx, cmd, outFile, b)
+		err = saveOutFile(ctx, cmd, outFile, b)
+		if err != nil {
+			return err
+		}
+	} else {
+		cmd.Println(string(b))
+	}
+
+	if coverageErr != nil {
+		return coverageErr
+	}
+
+	if tagPolicyErr != nil {
+		return tagPolicyErr
+	}
+
+	if policyChecks.HasFailed() {
+		return policyChecks.Failures
 	}
 
-	cmd.Println(string(b))
 	return nil
 }
 
+// addOwnershipFlags adds the flags that report cost that moves between the
+// owners of resources in diffs.
+func addOwnershipFlags(cmd *cobra.Command) {
+	cmd.Flags().String("ownership-tag", "", "Tag that resources are owned by, e.g. team. Reports cost that moves from one owner to another. Supported by diff and json output formats")
+	cmd.Flags().Float64("ownership-drift-threshold", 0, "Minimum monthly cost that moves between owners to be reported, used with ownership-tag")
+}
+
+// addCostHistoryFlags adds the flags that compare the costs with the costs of
+// previous runs to find anomalies.
+func addCostHistoryFlags(cmd *cobra.Command) {
+	cmd.Flags().String("history-path", "", "Path to a cost history file, or an s3://bucket/key URL, to flag resources whose costs changed unusually since previous runs. The costs of the run are added to it")
+	cmd.Flags().Int("history-size", 30, "Number of runs kept in the cost history")
+	cmd.Flags().Float64("anomaly-std-devs", 3, "Flag resources whose cost is more than this many standard deviations from the mean of the cost history, 0 to disable")
+	cmd.Flags().Float64("anomaly-percent", 50, "Flag resources whose cost changed more than this percentage since the previous run, 0 to disable")
+}
+
+// addShowEmissionsFlag adds the flag that estimates the carbon footprint of
+// the compute and storage resources.
+func addShowEmissionsFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("show-emissions", false, "Estimate the carbon footprint of compute and storage resources in kgCO2e. Supported by table and json output formats")
+}
+
+// addShowQuickWinsFlag adds the flag that lists the quick wins, e.g. gp3
+// instead of gp2 volumes. They're always included in the JSON output.
+func addShowQuickWinsFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("show-quick-wins", false, "List cost quick wins, e.g. gp3 instead of gp2 volumes or unattached Elastic IPs, with their savings. Supported by table and comment output formats")
+}
+
+// addShowGranularityFlag adds the flag that shows the costs in the table
+// output for other periods of time than a month, e.g. per hour.
+func addShowGranularityFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("show-granularity", nil, "Comma separated list of periods to show costs for: hourly,daily,monthly,annual. Supported by table output format")
+}
+
+// addPricingCoverageFlag adds the flag that fails the command when too little
+// of the projects is priced.
+func addPricingCoverageFlag(cmd *cobra.Command) {
+	cmd.Flags().Float64("min-pricing-coverage", 0, "Fail if less than this percentage of resources or cost components are priced, e.g. 95")
+}
+
+// addStrictPricingFlag adds the flag that fails the command when any cost
+// component has no price or several prices. It's only added to the commands
+// that look up prices as the failures aren't saved in the Infracost JSON.
+func addStrictPricingFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("strict-pricing", false, "Fail if any cost component has no price or matches several prices. Failures are listed in the JSON output")
+}
+
+// addGroupByFlag adds the flag that subtotals costs across projects by a
+// property of the resources.
+func addGroupByFlag(cmd *cobra.Command) {
+	cmd.Flags().String("group-by", "", "Subtotal costs across projects: module, module:<depth> for the top depth nested modules, or tag:<key>, e.g. tag:team. Supported by table and json output formats")
+}
+
+// addMaxUntaggedCostFlag adds the flag that fails the command when too much of
+// the cost isn't tagged with the tag that costs are grouped by.
+func addMaxUntaggedCostFlag(cmd *cobra.Command) {
+	cmd.Flags().Float64("max-untagged-cost-percent", 0, "Fail if more than this percentage of the monthly cost isn't tagged with the key of --group-by tag:<key>, e.g. 10")
+}
+
+// checkGroupByFlags returns an error if the group-by flag is invalid or the
+// max-untagged-cost-percent flag is used without grouping by a tag.
+func checkGroupByFlags(groupBy string, maxUntaggedCostPercent float64) error {
+	if groupBy != "" {
+		if err := output.ValidateGroupBy(groupBy); err != nil {
+			return err
+		}
+	}
+
+	if maxUntaggedCostPercent > 0 && !strings.HasPrefix(groupBy, "tag:") {
+		return errors.New("--max-untagged-cost-percent can only be used with --group-by tag:<key>")
+	}
+
+	return nil
+}
+
+// addTagPolicyFlags adds the flags that check the tags of resources against a
+// tag policy file.
+func addTagPolicyFlags(cmd *cobra.Command) {
+	cmd.Flags().String("tag-policy-path", "", "Path to a tag policy file listing the tags resources must have. Violations are shown in the table, JSON and comment output formats")
+	cmd.Flags().Bool("fail-on-tag-policy", false, "Fail if any resource doesn't follow the tag policy set with --tag-policy-path")
+}
+
+// addPolicyFlags adds the flags that evaluate Rego and cost policies against
+// the Infracost JSON of the run, and that accept reviewed failures.
+func addPolicyFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("policy-path", nil, "Path to Rego policy files with data.infracost.deny and data.infracost.warn rules, or to YAML cost policy files with CEL conditions and git URLs of policy packs. Glob patterns need quotes. Fails if any deny policy matches")
+	addIgnoresFileFlag(cmd)
+}
+
+// addIgnoresFileFlag adds the flag of the file of policy and budget failures
+// that don't fail the run.
+func addIgnoresFileFlag(cmd *cobra.Command) {
+	cmd.Flags().String("ignores-file", "", "Path to a file of policy and budget failures that don't fail the run, each with a reason and an optional expiry date")
+	_ = cmd.MarkFlagFilename("ignores-file", "yml", "yaml")
+}
+
+// checkTagPolicy adds the violations of the tag policy at policyPath to r. The
+// violations are only returned as an error if fail is set, so they can be
+// shown without failing the run.
+func checkTagPolicy(r *output.Root, policyPath string, fail bool) error {
+	if policyPath == "" {
+		return nil
+	}
+
+	p, err := output.LoadTagPolicy(policyPath)
+	if err != nil {
+		return err
+	}
+
+	err = output.CheckTagPolicy(r, p)
+	if err != nil && fail {
+		return err
+	}
+
+	return nil
+}
+
+// addFilterFlag adds the flag that only shows the resources that match filter
+// expressions.
+func addFilterFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArray("filter", nil, "Only show resources that match the filter, e.g. 'monthlyCost>100', 'monthlyCostChange>=10' or 'resourceType=azurerm_*'. Repeat to match all filters")
+}
+
+// addExcludeProjectPathFlag adds the flag that removes projects from the
+// output of commands that read Infracost JSON files.
+func addExcludeProjectPathFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("exclude-path", nil, "Paths of projects to exclude from the output, glob patterns need quotes")
+}
+
+// applyFilters removes the projects that match excludePaths and the resources
+// that don't match the filters from r.
+func applyFilters(r *output.Root, filters []string, excludePaths []string) error {
+	resourceFilters, err := output.ParseResourceFilters(filters)
+	if err != nil {
+		return err
+	}
+
+	output.ExcludeProjects(r, excludePaths)
+	output.FilterResources(r, resourceFilters)
+
+	return nil
+}
+
+// addCostIncreaseThresholdFlag adds the flag that sets the monthly cost
+// increase of a resource that is reported as a warning in the SARIF output.
+func addCostIncreaseThresholdFlag(cmd *cobra.Command) {
+	cmd.Flags().Float64("cost-increase-threshold", 0, "Minimum monthly cost increase of a resource to be reported as a warning. Supported by sarif output format")
+}
+
+// addMissingUsageFlag adds the flag that lists the costs that depend on usage
+// that isn't set. The usage keys that aren't set are only known to the commands
+// that build the resources, but the output command shows them if they're in the
+// Infracost JSON.
+func addMissingUsageFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("show-missing-usage", false, "Show the usage file keys to set for costs that depend on usage. Supported by table, diff and json output formats")
+}
+
+// addExpandForeachFlag adds the flag that sets if the instances of resources
+// that use count or for_each are shown separately or rolled up into one resource.
+func addExpandForeachFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("expand-foreach", true, "Show each instance of resources that use count or for_each, set to false to roll them up into one resource")
+}
+
+// addUnknownInputsFlag adds the flag that lists the resources with costs that
+// depend on values that are unknown until apply, such as values from data
+// sources or computed by the provider.
+func addUnknownInputsFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("show-unknown-inputs", false, "Show the resources with costs that depend on values only known after apply. Supported by table, diff and json output formats")
+}
+
 func checkDiffConfig(cfg *config.Config) error {
 	for _, projectConfig := range cfg.Projects {
 		if projectConfig.TerraformUseState {
 			return errors.New("terraform_use_state cannot be used with `infracost diff` as the Terraform state only contains the current state")
 		}
 
-		projectType := providers.DetectProjectType(projectConfig.Path, projectConfig.TerraformForceCLI)
+		// The plans of Terraform Cloud runs have the prior state, so they show a
+		// diff without a baseline.
+		if terraform.IsCloudPlanProject(projectConfig) {
+			continue
+		}
+
+		projectType := providers.DetectProjectType(projectConfig.Path, projectConfig.TerraformForceCLI, projectConfig.Ansible)
 		if (projectType == "terraform_dir" || projectType == "terragrunt_dir") && cfg.CompareTo == "" {
 			examplePath := "/code"
 			if projectConfig.Path != "" {
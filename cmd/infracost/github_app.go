@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/githubapp"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+func githubAppCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "github-app",
+		Short: "Run a GitHub App server that posts cost estimates to pull requests",
+		Long: `Run a GitHub App server that posts cost estimates to pull requests.
+
+The server receives the pull_request webhooks of a GitHub App. When a pull
+request is opened or updated, its base and head are checked out and the cost
+of each directory with changed Terraform files is compared between them. The
+cost estimate is posted as a comment on the pull request.
+
+Pull requests from forks are skipped unless --allow-forks is set. Their code
+isn't trusted, so when they're estimated their Terragrunt projects aren't
+evaluated, since Terragrunt config can run commands on the server.
+
+The GitHub App needs read access to contents and pull requests, and write access
+to pull requests to post comments. The webhook URL of the App is the /webhook
+path of the server.`,
+		Example: `  Run the server for a GitHub App:
+
+      export INFRACOST_GITHUB_APP_WEBHOOK_SECRET=my-secret
+      infracost github-app --app-id 12345 --private-key-file app.private-key.pem`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appID, _ := cmd.Flags().GetInt64("app-id")
+			if appID == 0 {
+				ui.PrintUsage(cmd)
+				return errors.New("--app-id is required")
+			}
+
+			privateKeyFile, _ := cmd.Flags().GetString("private-key-file")
+			b, err := os.ReadFile(privateKeyFile)
+			if err != nil {
+				return fmt.Errorf("Error reading private key file: %w", err)
+			}
+
+			privateKey, err := githubapp.ParsePrivateKey(b)
+			if err != nil {
+				return fmt.Errorf("Error loading private key file: %w", err)
+			}
+
+			webhookSecret := os.Getenv("INFRACOST_GITHUB_APP_WEBHOOK_SECRET")
+			if webhookSecret == "" {
+				ui.PrintUsage(cmd)
+				return errors.New("INFRACOST_GITHUB_APP_WEBHOOK_SECRET environment variable is required")
+			}
+
+			behavior, _ := cmd.Flags().GetString("behavior")
+			if !contains(validCommentGitHubBehaviors, behavior) {
+				ui.PrintUsage(cmd)
+				return fmt.Errorf("--behavior only supports %s", strings.Join(validCommentGitHubBehaviors, ", "))
+			}
+
+			executable, err := os.Executable()
+			if err != nil {
+				return err
+			}
+
+			apiURL, _ := cmd.Flags().GetString("github-api-url")
+			addr, _ := cmd.Flags().GetString("addr")
+			workers, _ := cmd.Flags().GetInt("workers")
+			allowForks, _ := cmd.Flags().GetBool("allow-forks")
+
+			runner := &githubapp.Runner{
+				App: &githubapp.App{
+					ID:         appID,
+					PrivateKey: privateKey,
+					APIURL:     apiURL,
+				},
+				Infracost: func(ctx context.Context, dir string, env []string, args ...string) error {
+					c := exec.CommandContext(ctx, executable, args...)
+					c.Dir = dir
+					c.Env = append(githubAppChildEnv(), env...)
+					out, err := c.CombinedOutput()
+					if err != nil {
+						return fmt.Errorf("infracost %s failed: %w\n%s", args[0], err, out)
+					}
+					return nil
+				},
+				Behavior:   behavior,
+				AllowForks: allowForks,
+			}
+
+			server := githubapp.NewServer([]byte(webhookSecret), 100)
+
+			mux := http.NewServeMux()
+			mux.Handle("/webhook", server)
+			mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			httpServer := &http.Server{
+				Addr:              addr,
+				Handler:           mux,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			runCtx, stop := signal.NotifyContext(ctx.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			done := make(chan struct{})
+			go func() {
+				server.Run(runCtx, workers, runner.Run)
+				close(done)
+			}()
+
+			go func() {
+				<-runCtx.Done()
+
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+
+				err := httpServer.Shutdown(shutdownCtx)
+				if err != nil {
+					logging.Logger.WithError(err).Error("could not shut down GitHub App server")
+				}
+			}()
+
+			cmd.Printf("Listening for GitHub App webhooks on %s\n", addr)
+
+			err = httpServer.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+
+			<-done
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64("app-id", 0, "ID of the GitHub App")
+	cmd.Flags().String("private-key-file", "", "Path to the private key file of the GitHub App")
+	_ = cmd.MarkFlagRequired("private-key-file")
+	_ = cmd.MarkFlagFilename("private-key-file", "pem")
+	cmd.Flags().String("github-api-url", githubapp.DefaultAPIURL, "GitHub API URL")
+	cmd.Flags().String("addr", ":8080", "Address to listen on for webhooks")
+	cmd.Flags().Int("workers", 2, "Number of pull requests to estimate in parallel")
+	cmd.Flags().Bool("allow-forks", false, "Estimate pull requests from forks, without evaluating their Terragrunt projects")
+	cmd.Flags().String("behavior", "update", `Behavior when posting comments, one of:
+  update (default)  Update latest comment
+  new               Create a new comment
+  hide-and-new      Hide previous matching comments and create a new comment
+  delete-and-new    Delete previous matching comments and create a new comment`)
+	_ = cmd.RegisterFlagCompletionFunc("behavior", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return validCommentGitHubBehaviors, cobra.ShellCompDirectiveDefault
+	})
+
+	return cmd
+}
+
+// githubAppChildEnvSkipped are the environment variables of the server that
+// the infracost commands that it runs for pull requests don't get. The
+// installation token is only passed to the comment command by the runner.
+var githubAppChildEnvSkipped = []string{
+	"INFRACOST_GITHUB_APP_WEBHOOK_SECRET",
+	"INFRACOST_GITHUB_TOKEN",
+}
+
+// githubAppChildEnv returns the environment of the server without the
+// variables in githubAppChildEnvSkipped.
+func githubAppChildEnv() []string {
+	var env []string
+	for _, e := range os.Environ() {
+		name, _, _ := strings.Cut(e, "=")
+		if !contains(githubAppChildEnvSkipped, name) {
+			env = append(env, e)
+		}
+	}
+
+	return env
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+)
+
+const s3HistoryPrefix = "s3://"
+
+// checkCostAnomalies compares the costs of r with the cost history at
+// cfg.HistoryPath to find anomalies, and then adds the costs of r to the
+// history so the next run is compared with them.
+func checkCostAnomalies(r *output.Root, cfg *config.Config) error {
+	h, err := loadCostHistory(cfg.HistoryPath)
+	if err != nil {
+		return err
+	}
+
+	output.DetectCostAnomalies(r, h, output.AnomalyThresholds{
+		StdDevs: cfg.AnomalyStdDevs,
+		Percent: cfg.AnomalyPercent,
+	})
+
+	h.AddRun(output.NewCostHistoryRun(*r), cfg.HistorySize)
+
+	return saveCostHistory(cfg.HistoryPath, h)
+}
+
+// loadCostHistory reads the cost history from a local file or an S3 object
+// URL. The history is empty if it doesn't exist yet.
+func loadCostHistory(historyPath string) (output.CostHistory, error) {
+	var h output.CostHistory
+
+	var b []byte
+	var err error
+	if strings.HasPrefix(historyPath, s3HistoryPrefix) {
+		b, err = readS3History(historyPath)
+	} else {
+		b, err = os.ReadFile(historyPath)
+		if errors.Is(err, os.ErrNotExist) {
+			return h, nil
+		}
+	}
+	if err != nil {
+		return h, fmt.Errorf("Error reading cost history %s: %w", historyPath, err)
+	}
+
+	if b == nil {
+		return h, nil
+	}
+
+	err = json.Unmarshal(b, &h)
+	if err != nil {
+		return h, fmt.Errorf("Error parsing cost history %s: %w", historyPath, err)
+	}
+
+	return h, nil
+}
+
+// saveCostHistory writes the cost history to a local file or an S3 object URL.
+func saveCostHistory(historyPath string, h output.CostHistory) error {
+	b, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasPrefix(historyPath, s3HistoryPrefix) {
+		err = writeS3History(historyPath, b)
+	} else {
+		err = os.WriteFile(historyPath, b, 0600)
+	}
+	if err != nil {
+		return fmt.Errorf("Error saving cost history %s: %w", historyPath, err)
+	}
+
+	return nil
+}
+
+// parseS3HistoryPath returns the bucket and key of an s3://bucket/key URL.
+func parseS3HistoryPath(historyPath string) (string, string, error) {
+	bucket, key, ok := strings.Cut(strings.TrimPrefix(historyPath, s3HistoryPrefix), "/")
+	if !ok || bucket == "" || key == "" {
+		return "", "", fmt.Errorf("Invalid cost history URL %s, use s3://bucket/key", historyPath)
+	}
+
+	return bucket, key, nil
+}
+
+func newS3HistoryClient(ctx context.Context) (*s3.Client, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s3.NewFromConfig(cfg), nil
+}
+
+// readS3History returns the S3 object of the cost history, or nil if it
+// doesn't exist yet.
+func readS3History(historyPath string) ([]byte, error) {
+	bucket, key, err := parseS3HistoryPath(historyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	client, err := newS3HistoryClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NoSuchKey
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer out.Body.Close()
+
+	return io.ReadAll(out.Body)
+}
+
+func writeS3History(historyPath string, b []byte) error {
+	bucket, key, err := parseS3HistoryPath(historyPath)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	client, err := newS3HistoryClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(b),
+		ContentType: aws.String("application/json"),
+	})
+
+	return err
+}
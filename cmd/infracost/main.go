@@ -19,6 +19,7 @@ import (
 	"github.com/infracost/infracost/internal/clierror"
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/tracing"
 	"github.com/infracost/infracost/internal/ui"
 	"github.com/infracost/infracost/internal/update"
 	"github.com/infracost/infracost/internal/version"
@@ -51,10 +52,16 @@ func Run(modifyCtx func(*config.RunContext), args *[]string) {
 		modifyCtx(ctx)
 	}
 
+	tracing.Init(version.Version)
+
 	var appErr error
 	updateMessageChan := make(chan *update.Info)
 
 	defer func() {
+		if err := tracing.Shutdown(context.Background()); err != nil {
+			logging.Logger.WithError(err).Debug("could not export traces")
+		}
+
 		if appErr != nil {
 			if v, ok := appErr.(*clierror.PanicError); ok {
 				handleUnexpectedErr(ctx, v)
@@ -180,9 +187,14 @@ func newRootCmd(ctx *config.RunContext) *cobra.Command {
 	rootCmd.AddCommand(diffCmd(ctx))
 	rootCmd.AddCommand(breakdownCmd(ctx))
 	rootCmd.AddCommand(scanCommand(ctx))
+	rootCmd.AddCommand(orphansCommand(ctx))
 	rootCmd.AddCommand(outputCmd(ctx))
+	rootCmd.AddCommand(showCmd(ctx))
+	rootCmd.AddCommand(snapshotCmd(ctx))
 	rootCmd.AddCommand(uploadCmd(ctx))
+	rootCmd.AddCommand(pricingCmd(ctx))
 	rootCmd.AddCommand(commentCmd(ctx))
+	rootCmd.AddCommand(githubAppCmd(ctx))
 	rootCmd.AddCommand(completionCmd())
 	rootCmd.AddCommand(figAutocompleteCmd())
 	rootCmd.AddCommand(newGenerateCommand())
@@ -274,6 +286,17 @@ func checkAPIKey(apiKey string, apiEndpoint string, defaultEndpoint string) erro
 	return nil
 }
 
+// isOffline returns true if the run looks up prices from the pricing snapshot,
+// which doesn't need an API key.
+func isOffline(cmd *cobra.Command, cfg *config.Config) bool {
+	if cmd.Flags().Changed("offline") {
+		offline, _ := cmd.Flags().GetBool("offline")
+		return offline
+	}
+
+	return cfg.Offline
+}
+
 func handleCLIError(ctx *config.RunContext, cliErr error) {
 	if cliErr.Error() != "" {
 		ui.PrintError(ctx.ErrWriter, cliErr.Error())
@@ -360,11 +383,14 @@ func saveOutFileWithMsg(ctx *config.RunContext, cmd *cobra.Command, outFile, suc
 		return errors.Wrap(err, "Unable to save output")
 	}
 
+	logOutFileSaved(ctx, cmd, successMsg)
+	return nil
+}
+
+func logOutFileSaved(ctx *config.RunContext, cmd *cobra.Command, successMsg string) {
 	if ctx.Config.IsLogging() {
 		logging.Logger.Info(successMsg)
 	} else {
 		cmd.PrintErrf("%s\n", successMsg)
 	}
-
-	return nil
 }
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+var validOrphansFormats = map[string]bool{
+	"table": true,
+	"json":  true,
+}
+
+type orphansCmd struct {
+	TerraformVarFiles  []string
+	TerraformVars      []string
+	TerraformWorkspace string
+
+	Path      string
+	StateFile string
+	UsageFile string
+	Format    string
+
+	cmd *cobra.Command
+}
+
+func (o orphansCmd) loadRunFlags(cfg *config.Config) error {
+	if o.Path == "" {
+		ui.PrintUsage(o.cmd)
+		return errors.New("No path specified\n\nUse the --path flag to specify the path to the Terraform directory.")
+	}
+
+	if o.StateFile == "" {
+		ui.PrintUsage(o.cmd)
+		return errors.New("No Terraform state file specified\n\nUse the --terraform-state-file flag to specify the path to a Terraform state JSON file, this can be generated with `terraform show -json`.")
+	}
+
+	if !validOrphansFormats[strings.ToLower(o.Format)] {
+		ui.PrintUsage(o.cmd)
+		return errors.New("--format only supports table and json")
+	}
+
+	projectCfg := cfg.Projects[0]
+	cfg.RootPath = o.Path
+	projectCfg.Path = o.Path
+	projectCfg.TerraformVarFiles = o.TerraformVarFiles
+	projectCfg.TerraformVars = tfVarsToMap(o.TerraformVars)
+	projectCfg.TerraformWorkspace = o.TerraformWorkspace
+	projectCfg.UsageFile = o.UsageFile
+
+	return nil
+}
+
+func (o orphansCmd) run(runCtx *config.RunContext) error {
+	err := o.loadRunFlags(runCtx.Config)
+	if err != nil {
+		return err
+	}
+
+	usageData := schema.NewUsageMapFromInterface(map[string]interface{}{})
+	if o.UsageFile != "" {
+		usageFile, err := usage.LoadUsageFile(o.UsageFile)
+		if err != nil {
+			return err
+		}
+
+		usageData = usageFile.ToUsageDataMap()
+	}
+
+	spinnerOpts := ui.SpinnerOptions{
+		EnableLogging: runCtx.Config.IsLogging(),
+		NoColor:       runCtx.Config.NoColor,
+		Indent:        "  ",
+	}
+	spinner := ui.NewSpinner("Finding resources in the Terraform state that are not in code", spinnerOpts)
+	defer spinner.Fail()
+
+	projectCfg := runCtx.Config.Projects[0]
+	hclProvider, err := terraform.NewHCLProvider(config.NewProjectContext(runCtx, projectCfg, log.Fields{}), &terraform.HCLProviderConfig{SuppressLogging: true})
+	if err != nil {
+		return err
+	}
+
+	hclProjects, err := hclProvider.LoadResources(usageData)
+	if err != nil {
+		return err
+	}
+
+	for _, project := range hclProjects {
+		if project.Metadata.HasErrors() {
+			return fmt.Errorf("Error loading Terraform directory %s: %s", project.Metadata.Path, project.Metadata.Errors[0].Message)
+		}
+	}
+
+	stateCfg := *projectCfg
+	stateCfg.Path = o.StateFile
+	stateProvider := terraform.NewStateJSONProvider(config.NewProjectContext(runCtx, &stateCfg, log.Fields{}), false)
+
+	stateProjects, err := stateProvider.LoadResources(usageData)
+	if err != nil {
+		return err
+	}
+
+	project := stateProjects[0]
+	project.Name = projectCfg.Path
+	project.PartialResources = terraform.OrphanedResources(hclProjects, project)
+	project.BuildResources(schema.UsageMap{})
+
+	spinner.Success()
+
+	spinner = ui.NewSpinner("Retrieving cloud prices to calculate costs", spinnerOpts)
+	defer spinner.Fail()
+
+	err = prices.PopulatePrices(runCtx, project)
+	if err != nil {
+		return err
+	}
+
+	var exchangeRates prices.ExchangeRateSource
+	if runCtx.Config.ExchangeRatesSource != "" {
+		exchangeRates, err = prices.NewExchangeRateSource(runCtx.Config.ExchangeRatesSource)
+		if err != nil {
+			return err
+		}
+	}
+
+	var priceOverrides *prices.PriceOverrides
+	if runCtx.Config.PriceOverridesFile != "" {
+		priceOverrides, err = prices.LoadPriceOverrides(runCtx.Config.PriceOverridesFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The costs are calculated the same way as the other commands, so the
+	// overrides, free tier and discounts of the run apply to them too.
+	err = prices.CalculateCosts(runCtx, exchangeRates, priceOverrides, project)
+	if err != nil {
+		return err
+	}
+
+	spinner.Success()
+
+	r, err := output.ToOutputFormat([]*schema.Project{project})
+	if err != nil {
+		return err
+	}
+	r.Currency = runCtx.Config.Currency
+	r.Metadata = output.NewMetadata(runCtx)
+
+	b, err := output.FormatOutput(strings.ToLower(o.Format), r, output.Options{
+		ShowSkipped:    true,
+		NoColor:        runCtx.Config.NoColor,
+		CurrencyFormat: runCtx.Config.CurrencyFormat,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = o.cmd.OutOrStdout().Write(b)
+	return err
+}
+
+func orphansCommand(ctx *config.RunContext) *cobra.Command {
+	var orphans orphansCmd
+
+	cmd := &cobra.Command{
+		Use:   "orphans",
+		Short: "Show the cost of resources in Terraform state that are not in code",
+		Long: `Show the cost of resources in Terraform state that are not in code.
+
+These are resources that have been removed from the Terraform code, or were
+never defined in it, but still exist in the state and are costing money until
+they are destroyed.`,
+		Example: `  Generate the state JSON and show its orphaned resources:
+
+      terraform show -json > state.json
+      infracost orphans --path /code --terraform-state-file state.json`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+				return err
+			}
+
+			return orphans.run(ctx)
+		},
+	}
+
+	orphans.cmd = cmd
+	cmd.Flags().StringVarP(&orphans.Path, "path", "p", "", "Path to the Terraform directory")
+	cmd.Flags().StringVar(&orphans.StateFile, "terraform-state-file", "", "Path to the Terraform state JSON file, generated with 'terraform show -json'")
+	cmd.Flags().StringSliceVar(&orphans.TerraformVarFiles, "terraform-var-file", nil, "Load variable files, similar to Terraform's -var-file flag. Provided files must be relative to the --path flag")
+	cmd.Flags().StringSliceVar(&orphans.TerraformVars, "terraform-var", nil, "Set value for an input variable, similar to Terraform's -var flag")
+	cmd.Flags().StringVar(&orphans.TerraformWorkspace, "terraform-workspace", "", "Terraform workspace to use. Applicable when path is a Terraform directory")
+	cmd.Flags().StringVar(&orphans.UsageFile, "usage-file", "", "Path to Infracost usage file that specifies values for usage-based resources")
+	cmd.Flags().StringVar(&orphans.Format, "format", "table", "Output format: json, table")
+
+	_ = cmd.MarkFlagFilename("path")
+	_ = cmd.MarkFlagFilename("terraform-state-file", "json")
+	_ = cmd.MarkFlagFilename("usage-file", "yml")
+
+	return cmd
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
@@ -27,6 +28,14 @@ var (
 		"bitbucket-comment",
 		"bitbucket-comment-summary",
 		"slack-message",
+		"teams-card",
+		"focus",
+		"csv",
+		"xlsx",
+		"junit",
+		"sarif",
+		"prometheus",
+		"template",
 	}
 
 	validCompareToFormats = map[string]bool{
@@ -38,6 +47,10 @@ var (
 		"bitbucket-comment":         true,
 		"bitbucket-comment-summary": true,
 		"slack-message":             true,
+		"teams-card":                true,
+		"junit":                     true,
+		"sarif":                     true,
+		"template":                  true,
 	}
 )
 
@@ -58,6 +71,10 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 
       infracost output --format json --path "out*.json" # glob needs quotes
 
+  Only show the resources with a large cost change, excluding a project:
+
+      infracost output --format diff --path "out*.json" --filter 'monthlyCostChange>100' --exclude-path "dev/*" # glob needs quotes
+
   Create markdown report to post in a GitHub comment:
 
       infracost output --format github-comment --path "out*.json" # glob needs quotes
@@ -72,7 +89,44 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 
   Create markdown report to post in a Bitbucket comment:
 
-      infracost output --format bitbucket-comment --path "out*.json" # glob needs quotes`,
+      infracost output --format bitbucket-comment --path "out*.json" # glob needs quotes
+
+  Post a summary of the cost changes to a Slack or Microsoft Teams channel:
+
+      infracost output --format slack-message --path "out*.json" --webhook-url $SLACK_WEBHOOK_URL # glob needs quotes
+      infracost output --format teams-card --path "out*.json" --top-resources 10 --webhook-url $TEAMS_WEBHOOK_URL # glob needs quotes
+
+  Export the estimate as FinOps FOCUS CSV rows:
+
+      infracost output --format focus --path "out*.json" --out-file focus.csv # glob needs quotes
+
+  Export the cost components as spreadsheet rows:
+
+      infracost output --format xlsx --path "out*.json" --out-file costs.xlsx # glob needs quotes
+
+  Create a JUnit report of cost increases and cost policies for CI test report UIs:
+
+      infracost output --format junit --path "out*.json" --policy-path policy.rego --out-file infracost-junit.xml # glob needs quotes
+
+  Create a SARIF log of cost warnings to upload to GitHub code scanning:
+
+      infracost output --format sarif --path "out*.json" --cost-increase-threshold 100 --out-file infracost.sarif # glob needs quotes
+
+  Push monthly cost gauges to a Prometheus Pushgateway:
+
+      infracost output --format prometheus --path "out*.json" | curl --data-binary @- http://pushgateway:9091/metrics/job/infracost # glob needs quotes
+
+  Show which modules drive the cost of multiple projects:
+
+      infracost output --path "out*.json" --group-by module:1 # glob needs quotes
+
+  Show the cost of each team and fail if more than 10% of the cost isn't tagged with a team:
+
+      infracost output --path "out*.json" --group-by tag:team --max-untagged-cost-percent 10 # glob needs quotes
+
+  Create a custom report from a Go template:
+
+      infracost output --format template --template-path report.tmpl --path "out*.json" # glob needs quotes`,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
@@ -86,6 +140,23 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				return fmt.Errorf("--format only supports %s", strings.Join(validOutputFormats, ", "))
 			}
 
+			templatePath, _ := cmd.Flags().GetString("template-path")
+			if format == "template" && templatePath == "" {
+				ui.PrintUsage(cmd)
+				return errors.New("--template-path is required when using --format template")
+			}
+
+			if err := checkOutFileFormat(cmd, format); err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
+			webhookURL, _ := cmd.Flags().GetString("webhook-url")
+			if webhookURL != "" && format != "slack-message" && format != "teams-card" {
+				ui.PrintUsage(cmd)
+				return errors.New("--webhook-url is only supported with --format slack-message or teams-card")
+			}
+
 			paths, _ := cmd.Flags().GetStringArray("path")
 
 			inputs, err := output.LoadPaths(paths)
@@ -104,6 +175,14 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 			combined.IsCIRun = ctx.IsCIRun()
 			combined.Metadata.InfracostCommand = "output"
 
+			filters, _ := cmd.Flags().GetStringArray("filter")
+			excludePaths, _ := cmd.Flags().GetStringSlice("exclude-path")
+			err = applyFilters(&combined, filters, excludePaths)
+			if err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
 			includeAllFields := "all"
 			validFields := []string{"price", "monthlyQuantity", "unit", "hourlyCost", "monthlyCost"}
 
@@ -132,9 +211,74 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				NoColor:           ctx.Config.NoColor,
 				Fields:            fields,
 				CurrencyFormat:    ctx.Config.CurrencyFormat,
+				TemplatePath:      templatePath,
 			}
 			opts.ShowSkipped, _ = cmd.Flags().GetBool("show-skipped")
 			opts.ShowAllProjects, _ = cmd.Flags().GetBool("show-all-projects")
+			opts.ShowResourceSummary, _ = cmd.Flags().GetBool("show-resource-summary")
+			opts.ShowSharedCosts, _ = cmd.Flags().GetBool("show-shared-costs")
+			opts.GroupByScope, _ = cmd.Flags().GetBool("group-by-scope")
+			opts.GroupBy, _ = cmd.Flags().GetString("group-by")
+			opts.ShowEmissions, _ = cmd.Flags().GetBool("show-emissions")
+			opts.ShowQuickWins, _ = cmd.Flags().GetBool("show-quick-wins")
+			opts.Granularities, _ = cmd.Flags().GetStringSlice("show-granularity")
+			if err := output.ValidateGranularities(opts.Granularities); err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+			if opts.ShowEmissions {
+				output.SetEmissions(&combined)
+			}
+			maxUntaggedCostPercent, _ := cmd.Flags().GetFloat64("max-untagged-cost-percent")
+			if err := checkGroupByFlags(opts.GroupBy, maxUntaggedCostPercent); err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+			tagPolicyPath, _ := cmd.Flags().GetString("tag-policy-path")
+			failOnTagPolicy, _ := cmd.Flags().GetBool("fail-on-tag-policy")
+			if failOnTagPolicy && tagPolicyPath == "" {
+				ui.PrintUsage(cmd)
+				return errors.New("--fail-on-tag-policy requires --tag-policy-path")
+			}
+			expandForeach, _ := cmd.Flags().GetBool("expand-foreach")
+			opts.RollUpInstances = !expandForeach
+			opts.OwnershipTag, _ = cmd.Flags().GetString("ownership-tag")
+			ownershipDriftThreshold, _ := cmd.Flags().GetFloat64("ownership-drift-threshold")
+			opts.OwnershipDriftThreshold = decimal.NewFromFloat(ownershipDriftThreshold)
+			costIncreaseThreshold, _ := cmd.Flags().GetFloat64("cost-increase-threshold")
+			opts.CostIncreaseThreshold = decimal.NewFromFloat(costIncreaseThreshold)
+			opts.TopResources, _ = cmd.Flags().GetInt("top-resources")
+			opts.CommentThresholds, err = commentThresholds(cmd, ctx)
+			if err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
+			var coverageErr error
+			if minCoverage, _ := cmd.Flags().GetFloat64("min-pricing-coverage"); minCoverage > 0 {
+				coverageErr = output.CheckPricingCoverage(&combined, minCoverage)
+			}
+
+			var untaggedErr error
+			if maxUntaggedCostPercent > 0 {
+				untaggedErr = output.CheckUntaggedCost(&combined, opts.GroupBy, maxUntaggedCostPercent)
+			}
+
+			tagPolicyErr := checkTagPolicy(&combined, tagPolicyPath, failOnTagPolicy)
+
+			policyPaths, _ := cmd.Flags().GetStringArray("policy-path")
+			if len(policyPaths) > 0 {
+				ignoresFile, _ := cmd.Flags().GetString("ignores-file")
+				ignores, err := output.LoadIgnores(ignoresFile)
+				if err != nil {
+					return err
+				}
+
+				opts.PolicyChecks, err = queryPolicy(policyPaths, ignores, combined)
+				if err != nil {
+					return err
+				}
+			}
 
 			validFieldsFormats := []string{"table", "html"}
 
@@ -161,29 +305,71 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				log.Errorf("Error reporting event: %s", err)
 			}
 
+			if webhookURL != "" {
+				err = apiclient.PostWebhook(webhookURL, b)
+				if err != nil {
+					return err
+				}
+
+				log.Info("Posted message to webhook")
+			}
+
 			if outFile, _ := cmd.Flags().GetString("out-file"); outFile != "" {
 				err = saveOutFile(ctx, cmd, outFile, b)
 				if err != nil {
 					return err
 				}
-			} else {
+			} else if webhookURL == "" {
 				cmd.Println(string(b))
 			}
 
-			return nil
+			if opts.PolicyChecks.HasFailed() {
+				return opts.PolicyChecks.Failures
+			}
+
+			if coverageErr != nil {
+				return coverageErr
+			}
+
+			if untaggedErr != nil {
+				return untaggedErr
+			}
+
+			return tagPolicyErr
 		},
 	}
 
 	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files, glob patterns need quotes")
 	cmd.Flags().StringP("out-file", "o", "", "Save output to a file, helpful with format flag")
 
-	cmd.Flags().String("format", "table", "Output format: json, diff, table, html, github-comment, gitlab-comment, azure-repos-comment, bitbucket-comment, bitbucket-comment-summary, slack-message")
+	cmd.Flags().String("format", "table", "Output format: json, diff, table, html, github-comment, gitlab-comment, azure-repos-comment, bitbucket-comment, bitbucket-comment-summary, slack-message, teams-card, focus, csv, xlsx, junit, sarif, prometheus, template")
+	cmd.Flags().String("template-path", "", "Path to a Go template file, used with the template and comment formats")
 	cmd.Flags().Bool("show-all-projects", false, "Show all projects in the table of the comment output")
 	cmd.Flags().Bool("show-skipped", false, "List unsupported and free resources")
+	cmd.Flags().Bool("show-resource-summary", false, "Show resource counts by type, provider, coverage and cost. Supported by table and json output formats")
+	cmd.Flags().Bool("show-shared-costs", false, "Split the cost of projects between the projects that consume them, set with consumes_projects in the config file. Supported by table and json output formats")
+	cmd.Flags().Bool("group-by-scope", false, "Subtotal costs by AWS account, Azure resource group or GCP project. Supported by table and json output formats")
+	addGroupByFlag(cmd)
+	addMaxUntaggedCostFlag(cmd)
+	addShowEmissionsFlag(cmd)
+	addShowQuickWinsFlag(cmd)
+	addShowGranularityFlag(cmd)
+	addExpandForeachFlag(cmd)
 	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,price,monthlyQuantity,unit,hourlyCost,monthlyCost.\nSupported by table and html output formats")
+	addOwnershipFlags(cmd)
+	addCostIncreaseThresholdFlag(cmd)
+	addPricingCoverageFlag(cmd)
+	addTagPolicyFlags(cmd)
+	addPolicyFlags(cmd)
+	addFilterFlag(cmd)
+	addExcludeProjectPathFlag(cmd)
+	addCommentThresholdFlags(cmd)
+	cmd.Flags().Int("top-resources", output.DefaultTopResources, "Number of resources with the largest cost changes to list. Supported by slack-message and teams-card output formats")
+	cmd.Flags().String("webhook-url", "", "Post the output to a Slack or Microsoft Teams incoming webhook instead of printing it. Supported by slack-message and teams-card output formats")
 
 	_ = cmd.MarkFlagRequired("path")
 	_ = cmd.MarkFlagFilename("path", "json")
+	_ = cmd.MarkFlagFilename("template-path", "tmpl")
 
 	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return validOutputFormats, cobra.ShellCompDirectiveDefault
@@ -218,3 +404,17 @@ func contains(arr []string, e string) bool {
 	}
 	return false
 }
+
+// checkOutFileFormat returns an error if the format is binary, so it can't be
+// printed, and no out-file is given.
+func checkOutFileFormat(cmd *cobra.Command, format string) error {
+	if format != "xlsx" {
+		return nil
+	}
+
+	if outFile, _ := cmd.Flags().GetString("out-file"); outFile == "" {
+		return fmt.Errorf("--out-file is required when using --format %s", format)
+	}
+
+	return nil
+}
@@ -19,6 +19,22 @@ func TestOutputFormatHTML(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "html", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
 }
 
+func TestOutputFormatCSV(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "csv", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
+}
+
+func TestOutputFormatJUnit(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "junit", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
+}
+
+func TestOutputFormatSARIF(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "sarif", "--path", "./testdata/example_out.json", "--cost-increase-threshold", "400"}, nil)
+}
+
+func TestOutputFormatPrometheus(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "prometheus", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
+}
+
 func TestOutputFormatJSON(t *testing.T) {
 	opts := DefaultOptions()
 	opts.IsJSON = true
@@ -138,6 +154,18 @@ func TestOutputFormatSlackMessageNoChange(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "slack-message", "--path", "./testdata/terraform_v0.14_nochange_breakdown.json"}, nil)
 }
 
+func TestOutputFormatTemplate(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "template", "--template-path", "./testdata/output_format_template/report.tmpl", "--path", "./testdata/example_out.json"}, nil)
+}
+
+func TestOutputFormatGitHubCommentTemplate(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "github-comment", "--template-path", "./testdata/output_format_git_hub_comment_template/comment.tmpl", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
+}
+
+func TestOutputFormatTemplateMissingPath(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "template", "--path", "./testdata/example_out.json"}, nil)
+}
+
 func TestOutputFormatSlackMessageMoreProjects(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "slack-message", "--path", "./testdata/example_out.json", "--path", "./testdata/example_out.json", "--path", "./testdata/example_out.json", "--path", "./testdata/example_out.json", "--path", "./testdata/example_out.json", "--path", "./testdata/example_out.json", "--path", "./testdata/example_out.json"}, nil)
 }
@@ -199,3 +227,95 @@ func TestOutputTerraformOutFileTable(t *testing.T) {
 func TestOutputJSONArrayPath(t *testing.T) {
 	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--path", "[\"./testdata/example_out.json\", \"./testdata/terraform_v0.14*breakdown.json\"]"}, nil)
 }
+
+func TestOutputFormatTeamsCard(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "teams-card", "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json", "--path", "./testdata/terraform_v0.14_nochange_breakdown.json"}, nil)
+}
+
+func TestOutputFormatTeamsCardTopResources(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "teams-card", "--top-resources", "2", "--path", "./testdata/example_out.json"}, nil)
+}
+
+func TestOutputWebhookUnsupportedFormat(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "json", "--webhook-url", "http://localhost/webhook", "--path", "./testdata/example_out.json"}, nil)
+}
+
+func TestOutputGroupByModule(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "table", "--group-by", "module", "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputGroupByInvalid(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--group-by", "module:0", "--path", "./testdata/example_out.json"}, nil)
+}
+
+func TestOutputGroupByTagMaxUntaggedCost(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "table", "--group-by", "tag:Owner", "--max-untagged-cost-percent", "50", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputTagPolicy(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "json", "--tag-policy-path", "./testdata/tag_policy.yml", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputTagPolicyFail(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "table", "--tag-policy-path", "./testdata/tag_policy.yml", "--fail-on-tag-policy", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputFilterMonthlyCost(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "table", "--filter", "monthlyCost>10", "--filter", "resourceType=aws_*", "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputFilterDiffExcludePath(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "diff", "--filter", "monthlyCostChange>=10", "--exclude-path", "cmd/infracost/testdata/terraform_v0.14_plan.json", "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputFilterInvalid(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--filter", "cost>10", "--path", "./testdata/example_out.json"}, nil)
+}
+
+func TestOutputFormatGitHubCommentThresholds(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "github-comment", "--min-cost-change", "5", "--min-percent-change", "20", "--warning-cost-increase", "10", "--critical-cost-increase", "500", "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json", "--path", "./testdata/terraform_v0.14_nochange_breakdown.json"}, nil)
+}
+
+func TestOutputFormatBitbucketCommentThresholds(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "bitbucket-comment", "--min-cost-change", "5", "--warning-cost-increase", "10", "--critical-cost-increase", "500", "--path", "./testdata/example_out.json", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputPolicyPath(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "table", "--policy-path", "./testdata/policy.rego", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputPolicyPathGitHubComment(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "github-comment", "--policy-path", "./testdata/policy.rego", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputCostPolicyPath(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "diff", "--policy-path", "./testdata/cost_policy.yml", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputShowEmissions(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "table", "--show-emissions", "--path", "./testdata/terraform_v0.14_breakdown.json"}, nil)
+}
+
+func TestOutputRecommendations(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "table", "--path", "./testdata/recommendations_out.json"}, nil)
+}
+
+func TestOutputRecommendationsGitHubComment(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "github-comment", "--path", "./testdata/recommendations_out.json"}, nil)
+}
+
+func TestOutputShowQuickWins(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "table", "--show-quick-wins", "--path", "./testdata/recommendations_out.json"}, nil)
+}
+
+func TestOutputShowGranularity(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "table", "--show-granularity", "hourly,daily,monthly,annual", "--path", "./testdata/example_out.json", "--path", "./testdata/azure_firewall_out.json"}, nil)
+}
+
+func TestOutputShowGranularityInvalid(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--show-granularity", "weekly", "--path", "./testdata/example_out.json"}, nil)
+}
+
+func TestOutputShowQuickWinsGitLabComment(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"output", "--format", "gitlab-comment", "--show-quick-wins", "--path", "./testdata/recommendations_out.json"}, nil)
+}
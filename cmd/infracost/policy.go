@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+
+	"github.com/infracost/infracost/internal/output"
+)
+
+// queryPolicy evaluates the policies in policyPaths against the Infracost
+// JSON. Paths to YAML files or remote policy packs are cost policies, see
+// output.CostPolicyFile, and the other paths are Rego policies. Failures that
+// match ignores don't fail the run.
+func queryPolicy(policyPaths []string, ignores []output.Ignore, input output.Root) (output.PolicyCheck, error) {
+	var regoPaths, costPolicyPaths []string
+	for _, p := range policyPaths {
+		if output.IsCostPolicyPath(p) {
+			costPolicyPaths = append(costPolicyPaths, p)
+		} else {
+			regoPaths = append(regoPaths, p)
+		}
+	}
+
+	checks := output.PolicyCheck{
+		Enabled: true,
+	}
+
+	if len(regoPaths) > 0 {
+		var err error
+		checks, err = queryRegoPolicy(regoPaths, input)
+		if err != nil {
+			return checks, err
+		}
+	}
+
+	if len(costPolicyPaths) > 0 {
+		policies, err := output.LoadCostPolicies(costPolicyPaths)
+		if err != nil {
+			return checks, err
+		}
+
+		costChecks, err := output.CheckCostPolicies(input, policies)
+		if err != nil {
+			return checks, err
+		}
+
+		checks.Failures = append(checks.Failures, costChecks.Failures...)
+		checks.Warnings = append(checks.Warnings, costChecks.Warnings...)
+	}
+
+	output.IgnorePolicyFailures(&checks, ignores, time.Now())
+
+	return checks, nil
+}
+
+// queryRegoPolicy evaluates the Rego policies in policyPaths against the
+// Infracost JSON. The policies are in the infracost package and can have deny
+// and warn rules. deny rules fail the run and warn rules are only shown. Each
+// rule returns a message, either as a string or as an object with a msg
+// property. deny rules can also return objects with a failed property so
+// passed checks are listed too, e.g.
+//
+//	package infracost
+//
+//	deny[msg] {
+//		r := input.projects[_].breakdown.resources[_]
+//		to_number(r.monthlyCost) > 500
+//		msg := sprintf("%s costs more than $500/mo", [r.name])
+//	}
+//
+//	warn[{"msg": msg}] {
+//		r := input.projects[_].breakdown.resources[_]
+//		r.resourceType == "aws_ebs_volume"
+//		msg := sprintf("%s should use gp3 volumes", [r.name])
+//	}
+func queryRegoPolicy(policyPaths []string, input output.Root) (output.PolicyCheck, error) {
+	checks := output.PolicyCheck{
+		Enabled: true,
+	}
+
+	inputValue, err := ast.InterfaceToValue(input)
+	if err != nil {
+		return checks, fmt.Errorf("Unable to process Infracost output into Rego input: %s", err.Error())
+	}
+
+	ctx := context.Background()
+	r := rego.New(
+		rego.Query("data.infracost"),
+		rego.ParsedInput(inputValue),
+		rego.Load(policyPaths, func(abspath string, info os.FileInfo, depth int) bool {
+			return false
+		}),
+	)
+	pq, err := r.PrepareForEval(ctx)
+	if err != nil {
+		return checks, fmt.Errorf("Unable to query provided policies: %s", err.Error())
+	}
+
+	res, err := pq.Eval(ctx)
+	if err != nil {
+		return checks, err
+	}
+
+	var rules map[string]interface{}
+	if len(res) > 0 && len(res[0].Expressions) > 0 {
+		rules, _ = res[0].Expressions[0].Value.(map[string]interface{})
+	}
+
+	deny, hasDeny := rules["deny"]
+	warn, hasWarn := rules["warn"]
+	if !hasDeny && !hasWarn {
+		return checks, fmt.Errorf("The provided polices returned no valid data.infracost.deny or data.infracost.warn rules. Please check that the policies are formatted correctly.")
+	}
+
+	for _, v := range policyResults(deny) {
+		readPolicyOut(v, &checks)
+	}
+
+	for _, v := range policyResults(warn) {
+		readPolicyWarning(v, &checks)
+	}
+
+	sort.Strings(checks.Warnings)
+
+	return checks, nil
+}
+
+// policyResults returns the results of a rule, which is a set of results for
+// partial rules, or a single result for complete rules.
+func policyResults(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	}
+
+	return []interface{}{v}
+}
+
+func readPolicyOut(result interface{}, checks *output.PolicyCheck) {
+	// Rules that return messages, like deny[msg], are always failures.
+	if msg, ok := result.(string); ok {
+		checks.Failures = append(checks.Failures, msg)
+		return
+	}
+
+	v, ok := result.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if _, ok := v["msg"]; !ok {
+		checks.Failures = append(checks.Failures, "Policy rule invalid as it did not contain {msg: string} property in output object. Please edit rule output object.")
+		return
+	}
+	msg := fmt.Sprintf("%v", v["msg"])
+
+	if _, ok := v["failed"]; !ok {
+		checks.Failures = append(checks.Failures, fmt.Sprintf("Policy rule: [%s] did not contain {failed: bool} output property. Please edit rule output object.", msg))
+		return
+	}
+
+	failed, _ := v["failed"].(bool)
+
+	if failed {
+		checks.Failures = append(checks.Failures, msg)
+		return
+	}
+
+	checks.Passed = append(checks.Passed, msg)
+}
+
+func readPolicyWarning(result interface{}, checks *output.PolicyCheck) {
+	if msg, ok := result.(string); ok {
+		checks.Warnings = append(checks.Warnings, msg)
+		return
+	}
+
+	v, ok := result.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	msg, ok := v["msg"]
+	if !ok {
+		checks.Warnings = append(checks.Warnings, "Policy rule invalid as it did not contain {msg: string} property in output object. Please edit rule output object.")
+		return
+	}
+
+	checks.Warnings = append(checks.Warnings, fmt.Sprintf("%v", msg))
+}
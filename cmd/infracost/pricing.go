@@ -0,0 +1,111 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+func pricingCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pricing",
+		Short: "Manage the pricing snapshot used by offline runs",
+		Long:  "Manage the pricing snapshot used by offline runs",
+		Example: `  Download the prices of a Terraform directory, then run without the Cloud Pricing API:
+
+      infracost pricing download --path /code
+      infracost breakdown --path /code --offline`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(pricingDownloadCmd(ctx))
+
+	return cmd
+}
+
+func pricingDownloadCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "download",
+		Short: "Download the prices needed by projects to a local snapshot",
+		Long: `Download the prices needed by projects to a local snapshot.
+
+The prices of all the resources in the projects are looked up and saved to a
+snapshot file. Runs with --offline look up prices from the snapshot instead of
+the Cloud Pricing API, so they don't need network access. Download the snapshot
+again when resources are added to the projects.
+
+The snapshot is a bbolt database with one key for each price lookup, so runs
+only read the prices of their own resources from it.`,
+		Example: `  Download the prices of a Terraform directory:
+
+      infracost pricing download --path /code
+
+  Download the prices of the projects in a config file to a custom location:
+
+      infracost pricing download --config-file infracost.yml --out-file prices.db`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+				return err
+			}
+
+			err := loadRunFlags(ctx.Config, cmd)
+			if err != nil {
+				return err
+			}
+
+			err = checkRunConfig(cmd.ErrOrStderr(), ctx.Config)
+			if err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
+			// The prices are always downloaded from the configured price sources.
+			ctx.Config.Offline = false
+
+			return runPricingDownload(cmd, ctx)
+		},
+	}
+
+	addRunFlags(cmd)
+
+	cmd.Flags().String("out-file", "", "Path of the snapshot file. Defaults to the pricing snapshot file used by --offline")
+	_ = cmd.MarkFlagFilename("out-file", "db")
+	_ = cmd.Flags().MarkHidden("offline")
+
+	return cmd
+}
+
+func runPricingDownload(cmd *cobra.Command, ctx *config.RunContext) error {
+	outFile, _ := cmd.Flags().GetString("out-file")
+	if outFile == "" {
+		outFile = ctx.Config.PricingSnapshotFile
+	}
+
+	pr, err := newParallelRunner(cmd, ctx)
+	if err != nil {
+		return err
+	}
+
+	snapshot := apiclient.NewPricingSnapshot(apiclient.PriceCurrency(ctx))
+	pr.priceSource = apiclient.NewRecordingPriceSource(apiclient.NewPriceSource(ctx), snapshot)
+
+	_, err = pr.run(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	err = snapshot.Save(outFile)
+	if err != nil {
+		return err
+	}
+
+	ui.PrintSuccessf(cmd.ErrOrStderr(), "Saved %d prices to %s", snapshot.Len(), outFile)
+
+	return nil
+}
@@ -3,9 +3,13 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"runtime/debug"
 	"sort"
 	"strings"
@@ -14,6 +18,7 @@ import (
 
 	"github.com/Rhymond/go-money"
 	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"golang.org/x/sync/errgroup"
@@ -25,11 +30,14 @@ import (
 	"github.com/infracost/infracost/internal/clierror"
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/plugins"
 	"github.com/infracost/infracost/internal/prices"
 	"github.com/infracost/infracost/internal/providers"
 	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/tracing"
 	"github.com/infracost/infracost/internal/ui"
 	"github.com/infracost/infracost/internal/usage"
+	"github.com/infracost/infracost/internal/version"
 )
 
 type projectJob struct {
@@ -57,21 +65,38 @@ func addRunFlags(cmd *cobra.Command) {
 	cmd.Flags().String("terraform-plan-flags", "", "Flags to pass to 'terraform plan'. Applicable with --terraform-force-cli")
 	cmd.Flags().String("terraform-init-flags", "", "Flags to pass to 'terraform init'. Applicable with --terraform-force-cli")
 	cmd.Flags().String("terraform-workspace", "", "Terraform workspace to use. Applicable when path is a Terraform directory")
+	cmd.Flags().String("terraform-cloud-workspace", "", "Terraform Cloud/Enterprise workspace, as <organization>/<workspace>, whose latest plan is used instead of path")
+	cmd.Flags().String("terraform-cloud-run", "", "ID of the Terraform Cloud/Enterprise run whose plan is used instead of path")
+	cmd.Flags().Bool("terraform-remote-state", false, "Read the outputs of terraform_remote_state data sources from their s3, azurerm or gcs backend. Needs credentials that can read the state")
+	cmd.Flags().StringArray("plugin", nil, "Path to a plugin executable that builds the costs of resources of unsupported Terraform providers (experimental)")
+	cmd.Flags().String("resource-mappings", "", "Directory or git URL of YAML files that map resources of unsupported Terraform providers to cost components (experimental)")
 
 	cmd.Flags().StringSlice("exclude-path", nil, "Paths of directories to exclude, glob patterns need quotes")
 	cmd.Flags().Bool("include-all-paths", false, "Set project auto-detection to use all subdirectories in given path")
+	cmd.Flags().Bool("ansible", false, "Estimate the cloud resources created by the Ansible playbooks in path. Best-effort as the playbooks are read, not run (experimental)")
 	cmd.Flags().String("git-diff-target", "master", "Show only costs that have git changes compared to the provided branch. Use the name of the current branch to fetch changes from the last two commits")
 	_ = cmd.Flags().MarkHidden("git-diff-target")
 
-	cmd.Flags().Bool("no-cache", false, "Don't attempt to cache Terraform plans")
+	cmd.Flags().Bool("no-cache", false, "Don't use cached Terraform plans or prices")
+	cmd.Flags().Int("parallelism", 0, "Number of projects, resources and price lookups that are processed at the same time. Defaults to 4 per CPU, up to 16")
 
 	cmd.Flags().Bool("show-skipped", false, "List unsupported and free resources")
 
+	cmd.Flags().Bool("include-free-tier", false, "Deduct always-free allowances of cloud providers from costs, e.g. the first 1M Lambda requests")
+	cmd.Flags().String("price-overrides-file", "", "Path to a file of fixed unit prices that replace prices from the pricing API, e.g. negotiated rates")
+	cmd.Flags().String("currency", "", "ISO 4217 currency code of the output, e.g. EUR. Defaults to USD")
+	cmd.Flags().String("exchange-rates-source", "", "Source of the rates that convert USD prices to the currency: ecb or the path to an exchange rates file. Defaults to the Cloud Pricing API")
+	cmd.Flags().Bool("offline", false, "Look up prices from the snapshot saved by 'infracost pricing download' instead of the Cloud Pricing API")
+	cmd.Flags().Bool("debug-pricing", false, "Print the product and price filters of each cost component, the products found and why a price was or wasn't used")
+	cmd.Flags().Bool("timing-report", false, "Print how long each phase of the run took and the memory it allocated, in total and for each project")
+
 	cmd.Flags().Bool("sync-usage-file", false, "Sync usage-file with missing resources, needs usage-file too (experimental)")
 
 	_ = cmd.MarkFlagFilename("path", "json", "tf")
 	_ = cmd.MarkFlagFilename("config-file", "yml")
 	_ = cmd.MarkFlagFilename("usage-file", "yml")
+	_ = cmd.MarkFlagFilename("price-overrides-file", "yml")
+	_ = cmd.MarkFlagFilename("exchange-rates-source", "yml")
 
 	_ = cmd.Flags().MarkHidden("terraform-force-cli")
 	// These are deprecated and will show a warning if used without --terraform-force-cli
@@ -79,7 +104,24 @@ func addRunFlags(cmd *cobra.Command) {
 	_ = cmd.Flags().MarkHidden("terraform-init-flags")
 }
 
-func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
+func runMain(cmd *cobra.Command, runCtx *config.RunContext) (err error) {
+	if runCtx.Config.TimingReport {
+		tracing.EnableReport()
+	}
+
+	traceCtx, span := tracing.Start(runCtx.Context(), "run", tracing.String("command", runCtx.CMD))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+
+		if runCtx.Config.TimingReport {
+			cmd.PrintErrln()
+			if reportErr := tracing.WriteReport(cmd.ErrOrStderr()); reportErr != nil {
+				logging.Logger.WithError(reportErr).Debug("could not write the timing report")
+			}
+		}
+	}()
+
 	if runCtx.Config.IsSelfHosted() && runCtx.IsCloudEnabled() {
 		ui.PrintWarning(cmd.ErrOrStderr(), "Infracost Cloud is part of Infracost's hosted services. Contact hello@infracost.io for help.")
 	}
@@ -91,12 +133,22 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 	}
 	runCtx.VCSMetadata = metadata
 
+	pluginsHash, closePlugins, err := plugins.Register(runCtx)
+	if err != nil {
+		return err
+	}
+	defer closePlugins()
+
+	// Work out the cache key before running as the projects can be changed by the run
+	cacheKey := reportCacheKey(runCtx)
+
 	pr, err := newParallelRunner(cmd, runCtx)
 	if err != nil {
 		return err
 	}
+	pr.pluginsHash = pluginsHash
 
-	projectResults, err := pr.run()
+	projectResults, err := pr.run(traceCtx)
 	if err != nil {
 		return err
 	}
@@ -139,21 +191,124 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 		log.Debug("Skipping sending project results since Infracost Cloud upload is not enabled.")
 	}
 
+	if cacheKey != "" && pr.prior == nil && !runCtx.IsCIRun() {
+		err = output.NewReportCache(runCtx.Config.ReportCacheDir).Save(cacheKey, r)
+		if err != nil {
+			logging.Logger.WithError(err).Debug("failed to save report to cache")
+		}
+	}
+
+	ignores, err := output.LoadIgnores(runCtx.Config.IgnoresFile)
+	if err != nil {
+		return err
+	}
+
+	// Budgets are checked before the resources are filtered so they apply to
+	// the full estimate.
+	budgetErr := output.CheckBudgets(&r, runCtx.Config.Budgets)
+	if budgetErr != nil {
+		budgetErr = output.IgnoreBudgetFailures(&r, ignores, time.Now())
+	}
+
+	// Anomalies are also found in the full estimate, so the history has the
+	// costs of all the resources.
+	if runCtx.Config.HistoryPath != "" {
+		err = checkCostAnomalies(&r, runCtx.Config)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = applyFilters(&r, runCtx.Config.Filters, nil)
+	if err != nil {
+		return err
+	}
+
+	if runCtx.Config.ShowEmissions {
+		output.SetEmissions(&r)
+	}
+
+	// The coverage and pricing failures are checked before the output is
+	// formatted so they're included in the JSON, but the run only fails after
+	// the output is shown.
+	var coverageErr error
+	if runCtx.Config.MinPricingCoverage > 0 {
+		coverageErr = output.CheckPricingCoverage(&r, runCtx.Config.MinPricingCoverage)
+	}
+
+	var untaggedErr error
+	if runCtx.Config.MaxUntaggedCostPercent > 0 {
+		untaggedErr = output.CheckUntaggedCost(&r, runCtx.Config.GroupBy, runCtx.Config.MaxUntaggedCostPercent)
+	}
+
+	tagPolicyErr := checkTagPolicy(&r, runCtx.Config.TagPolicyPath, runCtx.Config.FailOnTagPolicy)
+
+	var policyChecks output.PolicyCheck
+	if len(runCtx.Config.PolicyPaths) > 0 {
+		policyChecks, err = queryPolicy(runCtx.Config.PolicyPaths, ignores, r)
+		if err != nil {
+			return err
+		}
+	}
+
+	var pricingErr error
+	if runCtx.Config.StrictPricing {
+		pricingErr = output.CheckPricingFailures(&r, projects)
+	}
+
 	format := strings.ToLower(runCtx.Config.Format)
+
+	// The SARIF output always annotates the resources with missing usage and
+	// the unsupported resources.
+	if runCtx.Config.ShowMissingUsage || format == "sarif" {
+		output.SetMissingUsage(&r, projects)
+	}
+
+	if runCtx.Config.ShowUnknownInputs {
+		output.SetUnknownInputs(&r, projects)
+	}
+
+	if format == "sarif" {
+		output.SetUnsupportedResources(&r, projects)
+	}
+
 	isCompareRun := runCtx.Config.CompareTo != ""
 	if isCompareRun && !validCompareToFormats[format] {
 		return errors.New("The --compare-to option cannot be used with table and html formats as they output breakdowns, specify a different --format.")
 	}
 
-	b, err := output.FormatOutput(format, r, output.Options{
-		DashboardEndpoint: runCtx.Config.DashboardEndpoint,
-		ShowSkipped:       runCtx.Config.ShowSkipped,
-		NoColor:           runCtx.Config.NoColor,
-		Fields:            runCtx.Config.Fields,
-		CurrencyFormat:    runCtx.Config.CurrencyFormat,
-	})
-	if err != nil {
-		return err
+	opts := output.Options{
+		DashboardEndpoint:       runCtx.Config.DashboardEndpoint,
+		ShowSkipped:             runCtx.Config.ShowSkipped,
+		ShowResourceSummary:     runCtx.Config.ShowResourceSummary,
+		ShowSharedCosts:         runCtx.Config.ShowSharedCosts,
+		GroupByScope:            runCtx.Config.GroupByScope,
+		GroupBy:                 runCtx.Config.GroupBy,
+		ShowEmissions:           runCtx.Config.ShowEmissions,
+		ShowQuickWins:           runCtx.Config.ShowQuickWins,
+		Granularities:           runCtx.Config.ShowGranularity,
+		RollUpInstances:         runCtx.Config.RollUpInstances,
+		NoColor:                 runCtx.Config.NoColor,
+		Fields:                  runCtx.Config.Fields,
+		CurrencyFormat:          runCtx.Config.CurrencyFormat,
+		OwnershipTag:            runCtx.Config.OwnershipTag,
+		OwnershipDriftThreshold: decimal.NewFromFloat(runCtx.Config.OwnershipDriftThreshold),
+		CostIncreaseThreshold:   decimal.NewFromFloat(runCtx.Config.CostIncreaseThreshold),
+		PolicyChecks:            policyChecks,
+	}
+
+	// The JSON output is written straight to the out file or stdout once the
+	// run has been reported, one resource at a time, so the encoded JSON isn't
+	// held in memory as well as the output.
+	var b []byte
+	if format != "json" {
+		_, outputSpan := tracing.Start(traceCtx, "output", tracing.String("format", format))
+		b, err = output.FormatOutput(format, r, opts)
+		outputSpan.RecordError(err)
+		outputSpan.End()
+		if err != nil {
+			return err
+		}
 	}
 
 	if format == "diff" || format == "table" {
@@ -169,7 +324,15 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 		log.Errorf("Error reporting event: %s", err)
 	}
 
-	if outFile, _ := cmd.Flags().GetString("out-file"); outFile != "" {
+	if format == "json" {
+		_, outputSpan := tracing.Start(traceCtx, "output", tracing.String("format", format))
+		err = writeJSONOutput(runCtx, cmd, r, opts)
+		outputSpan.RecordError(err)
+		outputSpan.End()
+		if err != nil {
+			return err
+		}
+	} else if outFile, _ := cmd.Flags().GetString("out-file"); outFile != "" {
 		err = saveOutFile(runCtx, cmd, outFile, b)
 		if err != nil {
 			return err
@@ -182,6 +345,72 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 		cmd.Println(string(b))
 	}
 
+	if coverageErr != nil {
+		return coverageErr
+	}
+
+	if untaggedErr != nil {
+		return untaggedErr
+	}
+
+	if tagPolicyErr != nil {
+		return tagPolicyErr
+	}
+
+	if policyChecks.HasFailed() {
+		return policyChecks.Failures
+	}
+
+	if budgetErr != nil {
+		return budgetErr
+	}
+
+	return pricingErr
+}
+
+// writeJSONOutput writes the JSON output of r to the file passed in the
+// `--out-file` flag, or to the output of cmd. The file is written to a
+// temporary file first so it's left as it was if the output fails.
+func writeJSONOutput(runCtx *config.RunContext, cmd *cobra.Command, r output.Root, opts output.Options) error {
+	outFile, _ := cmd.Flags().GetString("out-file")
+	if outFile == "" {
+		// Print a new line to separate the logs from the output
+		if runCtx.Config.IsLogging() {
+			cmd.PrintErrln()
+		}
+
+		err := output.WriteOutput(cmd.OutOrStderr(), "json", r, opts)
+		if err != nil {
+			return err
+		}
+
+		cmd.Println()
+		return nil
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(outFile), filepath.Base(outFile)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "Unable to save output")
+	}
+	defer os.Remove(f.Name())
+
+	err = output.WriteOutput(f, "json", r, opts)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = errors.Wrap(closeErr, "Unable to save output")
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(f.Name(), 0644) // nolint:gosec
+	if err == nil {
+		err = os.Rename(f.Name(), outFile)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Unable to save output")
+	}
+
+	logOutFileSaved(runCtx, cmd, fmt.Sprintf("Output saved to %s", outFile))
 	return nil
 }
 
@@ -195,7 +424,19 @@ type parallelRunner struct {
 	pathMuxs    map[string]*sync.Mutex
 	prior       *output.Root
 	parallelism int
-	numJobs     int
+	// exchangeRates converts USD prices to the currency of the run, it is nil
+	// if the pricing API converts them.
+	exchangeRates prices.ExchangeRateSource
+	// priceSource looks up the prices of the projects instead of the price
+	// source configured for the run if it is set.
+	priceSource apiclient.PriceSource
+	// pluginsHash is the hash of the plugins and resource mappings of the run,
+	// which build the resources of the types that aren't built in.
+	pluginsHash string
+
+	// outputs are the outputs of the projects that have been run, which are
+	// used as the input vars of the projects that depend on them.
+	outputs providers.ProjectOutputs
 }
 
 func newParallelRunner(cmd *cobra.Command, runCtx *config.RunContext) (*parallelRunner, error) {
@@ -227,11 +468,20 @@ func newParallelRunner(cmd *cobra.Command, runCtx *config.RunContext) (*parallel
 
 	numJobs := len(runCtx.Config.Projects)
 
+	var exchangeRates prices.ExchangeRateSource
+	if runCtx.Config.ExchangeRatesSource != "" {
+		exchangeRates, err = prices.NewExchangeRateSource(runCtx.Config.ExchangeRatesSource)
+		if err != nil {
+			return nil, err
+		}
+		runCtx.SetContextValue("exchangeRatesSource", exchangeRatesSourceLabel(runCtx.Config.ExchangeRatesSource))
+	}
+
 	runInParallel := parallelism > 1 && numJobs > 1
 	if (runInParallel || runCtx.IsCIRun()) && !runCtx.Config.IsLogging() {
 		if runInParallel {
 			cmd.PrintErrln("Running multiple projects in parallel, so log-level=info is enabled by default.")
-			cmd.PrintErrln("Run with INFRACOST_PARALLELISM=1 to disable parallelism to help debugging.")
+			cmd.PrintErrln("Run with --parallelism=1 or INFRACOST_PARALLELISM=1 to disable parallelism to help debugging.")
 			cmd.PrintErrln()
 		}
 
@@ -244,19 +494,64 @@ func newParallelRunner(cmd *cobra.Command, runCtx *config.RunContext) (*parallel
 
 	return &parallelRunner{
 		parallelism: parallelism,
-		numJobs:     numJobs,
 		runCtx:      runCtx,
 		cmd:         cmd,
 		pathMuxs:    pathMuxs,
 		prior:       prior,
+
+		exchangeRates: exchangeRates,
 	}, nil
 }
 
-func (r *parallelRunner) run() ([]projectResult, error) {
-	projectResultChan := make(chan projectResult, r.numJobs)
-	jobs := make(chan projectJob, r.numJobs)
+func (r *parallelRunner) populatePrices(project *schema.Project) error {
+	if r.priceSource != nil {
+		return prices.PopulatePricesFromSource(r.runCtx, r.priceSource, project)
+	}
+
+	return prices.PopulatePrices(r.runCtx, project)
+}
+
+// exchangeRatesSourceLabel returns the source for the run event, without the
+// path of an exchange rates file.
+func exchangeRatesSourceLabel(source string) string {
+	if strings.EqualFold(source, prices.ExchangeRatesSourceECB) {
+		return prices.ExchangeRatesSourceECB
+	}
+
+	return "file"
+}
+
+func (r *parallelRunner) run(traceCtx context.Context) ([]projectResult, error) {
+	// Projects are run in levels so that the projects they depend on, and
+	// whose outputs they use, have finished first.
+	levels, err := r.runCtx.Config.ProjectLevels()
+	if err != nil {
+		return nil, err
+	}
+
+	projectResults := make([]projectResult, 0, len(r.runCtx.Config.Projects))
+	for _, level := range levels {
+		results, err := r.runLevel(traceCtx, level)
+		if err != nil {
+			return nil, err
+		}
+
+		projectResults = append(projectResults, results...)
+	}
+
+	sort.Slice(projectResults, func(i, j int) bool {
+		return projectResults[i].index < projectResults[j].index
+	})
+
+	return projectResults, nil
+}
+
+// runLevel runs the projects of the config with the indexes in parallel.
+func (r *parallelRunner) runLevel(traceCtx context.Context, indexes []int) ([]projectResult, error) {
+	projectResultChan := make(chan projectResult, len(indexes))
+	jobs := make(chan projectJob, len(indexes))
 
-	errGroup, _ := errgroup.WithContext(context.Background())
+	errGroup, _ := errgroup.WithContext(traceCtx)
 	for i := 0; i < r.parallelism; i++ {
 		i := i
 		errGroup.Go(func() (err error) {
@@ -275,9 +570,20 @@ func (r *parallelRunner) run() ([]projectResult, error) {
 				ctx := config.NewProjectContext(r.runCtx, job.projectCfg, log.Fields{
 					"routine": i,
 				})
-				configProjects, err := r.runProjectConfig(ctx)
+				projectTraceCtx, span := tracing.Start(traceCtx, "project", tracing.String("path", job.projectCfg.Path))
+
+				outputVars, err := r.outputs.Vars(r.runCtx.Config, job.projectCfg)
+				var configProjects *projectOutput
+				if err == nil {
+					ctx.OutputVars = outputVars
+					configProjects, err = r.runProjectConfig(projectTraceCtx, ctx)
+				}
+				span.RecordError(err)
+				span.End()
 				if err != nil {
 					configProjects = newErroredProject(ctx, err)
+				} else {
+					r.outputs.Set(job.projectCfg, configProjects.projects)
 				}
 
 				projectResultChan <- projectResult{
@@ -291,8 +597,8 @@ func (r *parallelRunner) run() ([]projectResult, error) {
 		})
 	}
 
-	for i, p := range r.runCtx.Config.Projects {
-		jobs <- projectJob{index: i, projectCfg: p}
+	for _, i := range indexes {
+		jobs <- projectJob{index: i, projectCfg: r.runCtx.Config.Projects[i]}
 	}
 	close(jobs)
 
@@ -303,19 +609,15 @@ func (r *parallelRunner) run() ([]projectResult, error) {
 
 	close(projectResultChan)
 
-	projectResults := make([]projectResult, 0, len(r.runCtx.Config.Projects))
+	projectResults := make([]projectResult, 0, len(indexes))
 	for result := range projectResultChan {
 		projectResults = append(projectResults, result)
 	}
 
-	sort.Slice(projectResults, func(i, j int) bool {
-		return projectResults[i].index < projectResults[j].index
-	})
-
 	return projectResults, nil
 }
 
-func (r *parallelRunner) runProjectConfig(ctx *config.ProjectContext) (*projectOutput, error) {
+func (r *parallelRunner) runProjectConfig(traceCtx context.Context, ctx *config.ProjectContext) (*projectOutput, error) {
 	mux := r.pathMuxs[ctx.ProjectConfig.Path]
 	if mux != nil {
 		mux.Lock()
@@ -377,59 +679,31 @@ func (r *parallelRunner) runProjectConfig(ctx *config.ProjectContext) (*projectO
 	}
 
 	// Load usage data
-	var usageFile *usage.UsageFile
+	_, usageSpan := tracing.Start(traceCtx, "usage")
+	defer usageSpan.End()
 
-	if ctx.ProjectConfig.UsageFile != "" {
-		var err error
-		usageFile, err = usage.LoadUsageFile(ctx.ProjectConfig.UsageFile)
-		if err != nil {
-			return nil, err
-		}
-
-		invalidKeys, err := usageFile.InvalidKeys()
-		if err != nil {
-			log.Errorf("Error checking usage file keys: %v", err)
-		} else if len(invalidKeys) > 0 {
-			ui.PrintWarningf(r.cmd.ErrOrStderr(),
-				"The following usage file parameters are invalid and will be ignored: %s\n",
-				strings.Join(invalidKeys, ", "),
-			)
-		}
-
-		ctx.SetContextValue("hasUsageFile", true)
-	} else {
-		usageFile = usage.NewBlankUsageFile()
-	}
-
-	// Merge wildcard usages into individual usage
-	wildCardUsage := make(map[string]*usage.ResourceUsage)
-	for _, us := range usageFile.ResourceUsages {
-		if strings.HasSuffix(us.Name, "[*]") {
-			lastIndexOfOpenBracket := strings.LastIndex(us.Name, "[")
-			prefixName := us.Name[:lastIndexOfOpenBracket]
-			wildCardUsage[prefixName] = us
-		}
+	usageData, pastUsageData, err := providers.LoadProjectUsage(ctx, r.cmd.ErrOrStderr())
+	if err != nil {
+		return nil, err
 	}
+	usageSpan.End()
 
-	for _, us := range usageFile.ResourceUsages {
-		if strings.HasSuffix(us.Name, "[*]") {
-			continue
-		}
+	out := &projectOutput{}
 
-		if !strings.HasSuffix(us.Name, "]") {
-			continue
+	var priceOverrides *prices.PriceOverrides
+	if r.runCtx.Config.PriceOverridesFile != "" {
+		priceOverrides, err = prices.LoadPriceOverrides(r.runCtx.Config.PriceOverridesFile)
+		if err != nil {
+			return nil, err
 		}
-		lastIndexOfOpenBracket := strings.LastIndex(us.Name, "[")
-		prefixName := us.Name[:lastIndexOfOpenBracket]
-
-		us.MergeResourceUsage(wildCardUsage[prefixName])
+		ctx.SetContextValue("hasPriceOverrides", true)
 	}
 
-	usageData := usageFile.ToUsageDataMap()
-	out := &projectOutput{}
-
 	t1 := time.Now()
+	_, parseSpan := tracing.Start(traceCtx, "parse", tracing.String("project_type", provider.Type()))
 	projects, err := provider.LoadResources(usageData)
+	parseSpan.RecordError(err)
+	parseSpan.End()
 	if err != nil {
 		r.cmd.PrintErrln()
 		return nil, err
@@ -437,7 +711,15 @@ func (r *parallelRunner) runProjectConfig(ctx *config.ProjectContext) (*projectO
 
 	_ = r.uploadCloudResourceIDs(projects)
 
+	providers.SetProjectConfig(ctx.ProjectConfig, projects, pastUsageData)
+
+	if r.useResourceCache() {
+		r.loadResourceCaches(ctx, projects)
+	}
+
+	_, buildSpan := tracing.Start(traceCtx, "build_resources")
 	r.buildResources(projects)
+	buildSpan.End()
 
 	spinnerOpts := ui.SpinnerOptions{
 		EnableLogging: r.runCtx.Config.IsLogging(),
@@ -447,8 +729,12 @@ func (r *parallelRunner) runProjectConfig(ctx *config.ProjectContext) (*projectO
 	spinner := ui.NewSpinner("Retrieving cloud prices to calculate costs", spinnerOpts)
 	defer spinner.Fail()
 
+	_, priceSpan := tracing.Start(traceCtx, "price_lookup", tracing.Int("projects", len(projects)))
+	defer priceSpan.End()
+
 	for _, project := range projects {
-		if err := prices.PopulatePrices(r.runCtx, project); err != nil {
+		if err := r.populatePrices(project); err != nil {
+			priceSpan.RecordError(err)
 			spinner.Fail()
 			r.cmd.PrintErrln()
 
@@ -474,11 +760,24 @@ func (r *parallelRunner) runProjectConfig(ctx *config.ProjectContext) (*projectO
 
 			return nil, err
 		}
-		schema.CalculateCosts(project)
 
-		project.CalculateDiff()
+		// The resources are cached before their prices are converted, as
+		// that's done for the cached resources too.
+		if project.ResourceCache != nil {
+			if err := project.ResourceCache.Save(); err != nil {
+				logging.Logger.WithError(err).Debug("failed to save resource cache")
+			}
+		}
+
+		if err := prices.CalculateCosts(r.runCtx, r.exchangeRates, priceOverrides, project); err != nil {
+			spinner.Fail()
+			r.cmd.PrintErrln()
+			return nil, err
+		}
 	}
 
+	priceSpan.End()
+
 	t2 := time.Now()
 	taken := t2.Sub(t1).Milliseconds()
 	ctx.SetContextValue("tfProjectRunTimeMs", taken)
@@ -542,7 +841,38 @@ func (r *parallelRunner) buildResources(projects []*schema.Project) {
 		projectPtrToUsageMap = r.fetchProjectUsage(projects)
 	}
 
-	schema.BuildResources(projects, projectPtrToUsageMap)
+	schema.BuildResources(projects, projectPtrToUsageMap, r.parallelism)
+}
+
+// useResourceCache returns true if diff runs should use the resources of
+// the previous run that haven't changed instead of building and pricing them.
+// The cache isn't used with --debug-pricing so the price of every resource is
+// explained. Cached resources expire with cached prices, so the cache isn't
+// used when prices aren't cached either.
+func (r *parallelRunner) useResourceCache() bool {
+	cfg := r.runCtx.Config
+	return r.cmd.Name() == "diff" && !cfg.NoCache && !cfg.DebugPricing && cfg.ResourceCacheDir != "" && cfg.PriceCacheTTL > 0
+}
+
+// loadResourceCaches sets the resource cache of each of the projects, which
+// is stored in a file for each project of the config.
+func (r *parallelRunner) loadResourceCaches(ctx *config.ProjectContext, projects []*schema.Project) {
+	namespace, _ := json.Marshal(map[string]interface{}{
+		"version":          version.Version,
+		"currency":         apiclient.PriceCurrency(r.runCtx),
+		"endpoint":         r.runCtx.Config.PricingAPIEndpoint,
+		"pricingEndpoints": r.runCtx.Config.PricingEndpoints,
+		"priceSources":     r.runCtx.Config.PriceSources,
+		"offline":          r.runCtx.Config.Offline,
+		"plugins":          r.pluginsHash,
+	})
+
+	for _, project := range projects {
+		h := sha256.Sum256([]byte(strings.Join([]string{ctx.ProjectConfig.Path, project.Name, project.Metadata.WorkspaceLabel()}, "\n")))
+		path := filepath.Join(r.runCtx.Config.ResourceCacheDir, hex.EncodeToString(h[:])+".json")
+
+		project.ResourceCache = schema.LoadResourceCache(path, string(namespace), r.runCtx.Config.PriceCacheTTL)
+	}
 }
 
 func (r *parallelRunner) fetchProjectUsage(projects []*schema.Project) map[*schema.Project]schema.UsageMap {
@@ -701,7 +1031,9 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 
 	cfg.CompareTo, _ = cmd.Flags().GetString("compare-to")
 
-	if cmd.Name() != "infracost" && !hasPathFlag && !hasConfigFile {
+	hasCloudPlanFlags := cmd.Flags().Changed("terraform-cloud-workspace") || cmd.Flags().Changed("terraform-cloud-run")
+
+	if cmd.Name() != "infracost" && !hasPathFlag && !hasConfigFile && !hasCloudPlanFlags {
 		m := fmt.Sprintf("No path specified\n\nUse the %s flag to specify the path to one of the following:\n", ui.PrimaryString("--path"))
 		m += fmt.Sprintf(" - Terraform/Terragrunt directory\n - Terraform plan JSON file, see %s for how to generate this.", ui.SecondaryLinkString("https://infracost.io/troubleshoot"))
 		m += fmt.Sprintf("\n\nAlternatively, use --config-file to process multiple projects, see %s", ui.SecondaryLinkString("https://infracost.io/config-file"))
@@ -712,12 +1044,14 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 
 	hasProjectFlags := (hasPathFlag ||
 		cmd.Flags().Changed("usage-file") ||
+		cmd.Flags().Changed("usage-file-before") ||
 		cmd.Flags().Changed("project-name") ||
 		cmd.Flags().Changed("terraform-plan-flags") ||
 		cmd.Flags().Changed("terraform-var-file") ||
 		cmd.Flags().Changed("terraform-var") ||
 		cmd.Flags().Changed("terraform-init-flags") ||
-		cmd.Flags().Changed("terraform-workspace"))
+		cmd.Flags().Changed("terraform-workspace") ||
+		hasCloudPlanFlags)
 
 	if hasConfigFile && hasProjectFlags {
 		m := "--config-file flag cannot be used with the following flags: "
@@ -737,6 +1071,9 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 		tfVars, _ := cmd.Flags().GetStringSlice("terraform-var")
 		projectCfg.TerraformVars = tfVarsToMap(tfVars)
 		projectCfg.UsageFile, _ = cmd.Flags().GetString("usage-file")
+		if cmd.Flags().Lookup("usage-file-before") != nil {
+			projectCfg.UsageFileBefore, _ = cmd.Flags().GetString("usage-file-before")
+		}
 		projectCfg.Name, _ = cmd.Flags().GetString("project-name")
 		projectCfg.TerraformForceCLI, _ = cmd.Flags().GetBool("terraform-force-cli")
 		projectCfg.TerraformPlanFlags, _ = cmd.Flags().GetString("terraform-plan-flags")
@@ -744,10 +1081,14 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 		projectCfg.TerraformUseState, _ = cmd.Flags().GetBool("terraform-use-state")
 		projectCfg.ExcludePaths, _ = cmd.Flags().GetStringSlice("exclude-path")
 		projectCfg.IncludeAllPaths, _ = cmd.Flags().GetBool("include-all-paths")
+		projectCfg.Ansible, _ = cmd.Flags().GetBool("ansible")
 
 		if cmd.Flags().Changed("terraform-workspace") {
 			projectCfg.TerraformWorkspace, _ = cmd.Flags().GetString("terraform-workspace")
 		}
+
+		projectCfg.TerraformCloudWorkspace, _ = cmd.Flags().GetString("terraform-cloud-workspace")
+		projectCfg.TerraformCloudRun, _ = cmd.Flags().GetString("terraform-cloud-run")
 	}
 
 	if hasConfigFile {
@@ -760,6 +1101,11 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 
 		cfg.ConfigFilePath = cfgFilePath
 
+		err = providers.DetectConfigProjects(cfg)
+		if err != nil {
+			return err
+		}
+
 		if forceCLI, _ := cmd.Flags().GetBool("terraform-force-cli"); forceCLI {
 			for _, p := range cfg.Projects {
 				p.TerraformForceCLI = true
@@ -770,11 +1116,75 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 				p.TerraformUseState = true
 			}
 		}
+		if ansible, _ := cmd.Flags().GetBool("ansible"); ansible {
+			for _, p := range cfg.Projects {
+				p.Ansible = true
+			}
+		}
 	}
 
 	cfg.NoCache, _ = cmd.Flags().GetBool("no-cache")
+	cfg.DebugPricing, _ = cmd.Flags().GetBool("debug-pricing")
+	cfg.TimingReport, _ = cmd.Flags().GetBool("timing-report")
+	cfg.TerraformRemoteState, _ = cmd.Flags().GetBool("terraform-remote-state")
+	if cmd.Flags().Changed("include-free-tier") {
+		cfg.IncludeFreeTier, _ = cmd.Flags().GetBool("include-free-tier")
+	}
+	if cmd.Flags().Changed("plugin") {
+		cfg.Plugins, _ = cmd.Flags().GetStringArray("plugin")
+	}
+	if cmd.Flags().Changed("resource-mappings") {
+		cfg.ResourceMappings, _ = cmd.Flags().GetString("resource-mappings")
+	}
+	if cmd.Flags().Changed("price-overrides-file") {
+		cfg.PriceOverridesFile, _ = cmd.Flags().GetString("price-overrides-file")
+	}
+	if cmd.Flags().Changed("currency") {
+		currency, _ := cmd.Flags().GetString("currency")
+		cfg.Currency = strings.ToUpper(currency)
+	}
+	if cmd.Flags().Changed("exchange-rates-source") {
+		cfg.ExchangeRatesSource, _ = cmd.Flags().GetString("exchange-rates-source")
+	}
+	if cmd.Flags().Changed("parallelism") {
+		parallelism, _ := cmd.Flags().GetInt("parallelism")
+		cfg.Parallelism = &parallelism
+	}
+	if cmd.Flags().Changed("offline") {
+		cfg.Offline, _ = cmd.Flags().GetBool("offline")
+	}
+	if cfg.Offline {
+		cfg.EventsDisabled = true
+	}
 	cfg.Format, _ = cmd.Flags().GetString("format")
 	cfg.ShowSkipped, _ = cmd.Flags().GetBool("show-skipped")
+	cfg.ShowResourceSummary, _ = cmd.Flags().GetBool("show-resource-summary")
+	cfg.ShowSharedCosts, _ = cmd.Flags().GetBool("show-shared-costs")
+	cfg.GroupByScope, _ = cmd.Flags().GetBool("group-by-scope")
+	cfg.GroupBy, _ = cmd.Flags().GetString("group-by")
+	cfg.MaxUntaggedCostPercent, _ = cmd.Flags().GetFloat64("max-untagged-cost-percent")
+	cfg.TagPolicyPath, _ = cmd.Flags().GetString("tag-policy-path")
+	cfg.Filters, _ = cmd.Flags().GetStringArray("filter")
+	cfg.FailOnTagPolicy, _ = cmd.Flags().GetBool("fail-on-tag-policy")
+	cfg.PolicyPaths, _ = cmd.Flags().GetStringArray("policy-path")
+	cfg.IgnoresFile, _ = cmd.Flags().GetString("ignores-file")
+	cfg.HistoryPath, _ = cmd.Flags().GetString("history-path")
+	cfg.HistorySize, _ = cmd.Flags().GetInt("history-size")
+	cfg.AnomalyStdDevs, _ = cmd.Flags().GetFloat64("anomaly-std-devs")
+	cfg.AnomalyPercent, _ = cmd.Flags().GetFloat64("anomaly-percent")
+	cfg.ShowEmissions, _ = cmd.Flags().GetBool("show-emissions")
+	cfg.ShowQuickWins, _ = cmd.Flags().GetBool("show-quick-wins")
+	cfg.ShowGranularity, _ = cmd.Flags().GetStringSlice("show-granularity")
+	cfg.OwnershipTag, _ = cmd.Flags().GetString("ownership-tag")
+	cfg.OwnershipDriftThreshold, _ = cmd.Flags().GetFloat64("ownership-drift-threshold")
+	cfg.MinPricingCoverage, _ = cmd.Flags().GetFloat64("min-pricing-coverage")
+	cfg.StrictPricing, _ = cmd.Flags().GetBool("strict-pricing")
+	cfg.ShowMissingUsage, _ = cmd.Flags().GetBool("show-missing-usage")
+	cfg.ShowUnknownInputs, _ = cmd.Flags().GetBool("show-unknown-inputs")
+	cfg.CostIncreaseThreshold, _ = cmd.Flags().GetFloat64("cost-increase-threshold")
+	if expandForeach, err := cmd.Flags().GetBool("expand-foreach"); err == nil {
+		cfg.RollUpInstances = !expandForeach
+	}
 	cfg.SyncUsageFile, _ = cmd.Flags().GetBool("sync-usage-file")
 
 	includeAllFields := "all"
@@ -844,6 +1254,38 @@ func checkRunConfig(warningWriter io.Writer, cfg *config.Config) error {
 		}
 	}
 
+	if err := checkGroupByFlags(cfg.GroupBy, cfg.MaxUntaggedCostPercent); err != nil {
+		return err
+	}
+
+	if _, err := output.ParseResourceFilters(cfg.Filters); err != nil {
+		return err
+	}
+
+	if err := output.ValidateGranularities(cfg.ShowGranularity); err != nil {
+		return err
+	}
+
+	if cfg.HistoryPath != "" {
+		if strings.HasPrefix(cfg.HistoryPath, s3HistoryPrefix) {
+			if _, _, err := parseS3HistoryPath(cfg.HistoryPath); err != nil {
+				return err
+			}
+		}
+
+		if cfg.AnomalyStdDevs < 0 || cfg.AnomalyPercent < 0 {
+			return errors.New("--anomaly-std-devs and --anomaly-percent must not be negative")
+		}
+	}
+
+	if cfg.TagPolicyPath != "" {
+		if _, err := output.LoadTagPolicy(cfg.TagPolicyPath); err != nil {
+			return err
+		}
+	} else if cfg.FailOnTagPolicy {
+		return errors.New("--fail-on-tag-policy requires --tag-policy-path")
+	}
+
 	if money.GetCurrency(cfg.Currency) == nil {
 		ui.PrintWarning(warningWriter, fmt.Sprintf("Ignoring unknown currency '%s', using USD.\n", cfg.Currency))
 		cfg.Currency = "USD"
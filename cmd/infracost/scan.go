@@ -11,6 +11,7 @@ import (
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/logging"
 	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/providers"
 	"github.com/infracost/infracost/internal/providers/terraform"
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
@@ -72,6 +73,11 @@ func (s scanCmd) loadRunFlags(cfg *config.Config) error {
 		}
 
 		cfg.ConfigFilePath = s.ConfigFile
+
+		err = providers.DetectConfigProjects(cfg)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -0,0 +1,101 @@
+package main_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	main "github.com/infracost/infracost/cmd/infracost"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/pkg/infracost"
+)
+
+// TestSDKMatchesCLI checks that the Go API estimates the projects of a config
+// file with its discounts the same as the CLI.
+func TestSDKMatchesCLI(t *testing.T) {
+	pricingAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var queries []json.RawMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&queries))
+
+		results := make([]interface{}, 0, len(queries))
+		for range queries {
+			results = append(results, map[string]interface{}{
+				"data": map[string]interface{}{
+					"products": []interface{}{
+						map[string]interface{}{"prices": []interface{}{map[string]interface{}{"priceHash": "test", "USD": "0.1"}}},
+					},
+				},
+			})
+		}
+
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	defer pricingAPI.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "prod"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "prod", "main.tf"), []byte(`
+provider "aws" {
+  region = "us-east-1"
+}
+
+resource "aws_instance" "web" {
+  ami           = "ami-674cbc1e"
+  instance_type = "m5.large"
+}
+`), 0600))
+
+	configFile := filepath.Join(dir, "infracost.yml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`version: 0.1
+discounts:
+  aws compute: 10%
+projects:
+  - path: `+filepath.Join(dir, "prod")+`
+    name: prod
+`), 0600))
+
+	outBuf := bytes.NewBuffer([]byte{})
+	main.Run(func(c *config.RunContext) {
+		enableCloud := false
+		c.Config.EnableCloud = &enableCloud
+		c.Config.EventsDisabled = true
+		c.Config.APIKey = "test"
+		c.Config.PricingAPIEndpoint = pricingAPI.URL
+		c.Config.NoColor = true
+		c.ErrWriter = bytes.NewBuffer([]byte{})
+		c.OutWriter = outBuf
+		c.Exit = func(code int) {}
+	}, &[]string{"breakdown", "--config-file", configFile, "--format", "json"})
+
+	var cli struct {
+		TotalMonthlyCost decimal.Decimal `json:"totalMonthlyCost"`
+	}
+	require.NoError(t, json.Unmarshal(outBuf.Bytes(), &cli))
+
+	projects, err := infracost.LoadProjects(configFile)
+	require.NoError(t, err)
+
+	e, err := infracost.Breakdown(context.Background(), projects, infracost.Options{APIKey: "test", PricingAPIEndpoint: pricingAPI.URL})
+	require.NoError(t, err)
+
+	require.NotNil(t, e.TotalMonthlyCost)
+	assert.True(t, cli.TotalMonthlyCost.Equal(*e.TotalMonthlyCost), "CLI total %s, SDK total %s", cli.TotalMonthlyCost, e.TotalMonthlyCost)
+
+	require.Len(t, e.Projects, 1)
+	require.NotEmpty(t, e.Projects[0].Resources)
+
+	var names []string
+	for _, c := range e.Projects[0].Resources[0].CostComponents {
+		names = append(names, c.Name)
+	}
+	assert.Contains(t, names, "Discount (aws compute 10%)")
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/infracost/infracost/internal/vcs"
+)
+
+func showCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show a previous breakdown without re-running it",
+		Long: `Show a previous breakdown without re-running it.
+
+Finished runs of breakdown and diff are saved for the git commit and flags they
+were run with. The show command re-displays them in any output format without
+re-pricing, as long as the same path or config file flags are used.`,
+		Example: `  Show the last breakdown of the current commit as HTML:
+
+      infracost breakdown --path /code
+      infracost show --path /code --format html --out-file report.html
+
+  Show the breakdown of the previous commit:
+
+      infracost show --path /code --commit HEAD~1`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			format = strings.ToLower(format)
+			ctx.SetContextValue("outputFormat", format)
+
+			if !contains(validOutputFormats, format) {
+				ui.PrintUsage(cmd)
+				return fmt.Errorf("--format only supports %s", strings.Join(validOutputFormats, ", "))
+			}
+
+			templatePath, _ := cmd.Flags().GetString("template-path")
+			if format == "template" && templatePath == "" {
+				ui.PrintUsage(cmd)
+				return errors.New("--template-path is required when using --format template")
+			}
+
+			if err := checkOutFileFormat(cmd, format); err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
+			err := loadRunFlags(ctx.Config, cmd)
+			if err != nil {
+				return err
+			}
+
+			if err := checkGroupByFlags(ctx.Config.GroupBy, 0); err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
+			repoPath := ctx.Config.RepoPath()
+			metadata, err := vcs.MetadataFetcher.Get(repoPath, nil)
+			if err != nil {
+				logging.Logger.WithError(err).Debugf("failed to fetch vcs metadata for path %s", repoPath)
+			}
+			ctx.VCSMetadata = metadata
+
+			if revision, _ := cmd.Flags().GetString("commit"); revision != "" {
+				sha, err := vcs.ResolveCommit(repoPath, revision)
+				if err != nil {
+					return err
+				}
+				ctx.VCSMetadata.Commit.SHA = sha
+			}
+
+			if ctx.VCSMetadata.Commit.SHA == "" {
+				return fmt.Errorf("Could not determine the git commit of %s\n\nPrevious runs can only be shown for paths in a git repository.", repoPath)
+			}
+
+			key := reportCacheKey(ctx)
+			if key == "" {
+				return errors.New("Saving runs is disabled as INFRACOST_REPORT_CACHE_DIR is empty")
+			}
+
+			r, err := output.NewReportCache(ctx.Config.ReportCacheDir).Load(key)
+			if errors.Is(err, output.ErrReportNotCached) {
+				return fmt.Errorf("No previous run found for commit %s\n\nRun infracost breakdown or diff with the same path or config file flags first.", ctx.VCSMetadata.Commit.SHA)
+			} else if err != nil {
+				return err
+			}
+			r.IsCIRun = ctx.IsCIRun()
+
+			opts := output.Options{
+				DashboardEndpoint:       ctx.Config.DashboardEndpoint,
+				ShowSkipped:             ctx.Config.ShowSkipped,
+				ShowResourceSummary:     ctx.Config.ShowResourceSummary,
+				ShowSharedCosts:         ctx.Config.ShowSharedCosts,
+				GroupByScope:            ctx.Config.GroupByScope,
+				GroupBy:                 ctx.Config.GroupBy,
+				NoColor:                 ctx.Config.NoColor,
+				Fields:                  ctx.Config.Fields,
+				CurrencyFormat:          ctx.Config.CurrencyFormat,
+				TemplatePath:            templatePath,
+				OwnershipTag:            ctx.Config.OwnershipTag,
+				OwnershipDriftThreshold: decimal.NewFromFloat(ctx.Config.OwnershipDriftThreshold),
+				CostIncreaseThreshold:   decimal.NewFromFloat(ctx.Config.CostIncreaseThreshold),
+				TopResources:            output.DefaultTopResources,
+			}
+
+			b, err := output.FormatOutput(format, r, opts)
+			if err != nil {
+				return err
+			}
+
+			if outFile, _ := cmd.Flags().GetString("out-file"); outFile != "" {
+				return saveOutFile(ctx, cmd, outFile, b)
+			}
+
+			cmd.Println(string(b))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("path", "p", "", "Path to the Terraform directory or JSON/plan file that was run")
+	cmd.Flags().String("config-file", "", "Path to the Infracost config file that was run. Cannot be used with path, terraform* or usage-file flags")
+	cmd.Flags().StringSlice("terraform-var-file", nil, "Variable files that were run, must match the run to find it")
+	cmd.Flags().StringSlice("terraform-var", nil, "Input variables that were run, must match the run to find it")
+	cmd.Flags().String("terraform-workspace", "", "Terraform workspace that was run, must match the run to find it")
+	cmd.Flags().String("usage-file", "", "Usage file that was run, must match the run to find it")
+	cmd.Flags().String("project-name", "", "Project name that was run, must match the run to find it")
+	cmd.Flags().StringSlice("exclude-path", nil, "Paths of directories that were excluded, must match the run to find it")
+	cmd.Flags().Bool("include-all-paths", false, "Show the run that used all subdirectories in the given path")
+	cmd.Flags().Bool("include-free-tier", false, "Show the run that deducted always-free allowances of cloud providers from costs")
+	cmd.Flags().String("price-overrides-file", "", "Price overrides file that was run, must match the run to find it")
+	cmd.Flags().String("currency", "", "Currency that was run, must match the run to find it")
+	cmd.Flags().String("exchange-rates-source", "", "Exchange rates source that was run, must match the run to find it")
+	cmd.Flags().String("commit", "", "Git revision to show the run of, e.g. HEAD~1 or a branch name. Defaults to the current commit")
+
+	cmd.Flags().StringP("out-file", "o", "", "Save output to a file, helpful with format flag")
+	cmd.Flags().String("format", "table", "Output format: json, diff, table, html, github-comment, gitlab-comment, azure-repos-comment, bitbucket-comment, bitbucket-comment-summary, slack-message, teams-card, focus, csv, xlsx, junit, sarif, prometheus, template")
+	cmd.Flags().String("template-path", "", "Path to a Go template file, used with the template and comment formats")
+	cmd.Flags().Bool("show-skipped", false, "List unsupported and free resources")
+	cmd.Flags().Bool("show-resource-summary", false, "Show resource counts by type, provider, coverage and cost. Supported by table and json output formats")
+	cmd.Flags().Bool("show-shared-costs", false, "Split the cost of projects between the projects that consume them, set with consumes_projects in the config file. Supported by table and json output formats")
+	cmd.Flags().Bool("group-by-scope", false, "Subtotal costs by AWS account, Azure resource group or GCP project. Supported by table and json output formats")
+	addGroupByFlag(cmd)
+	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,price,monthlyQuantity,unit,hourlyCost,monthlyCost.\nSupported by table and html output formats")
+	addOwnershipFlags(cmd)
+	addCostIncreaseThresholdFlag(cmd)
+
+	_ = cmd.MarkFlagFilename("path", "json", "tf")
+	_ = cmd.MarkFlagFilename("config-file", "yml")
+	_ = cmd.MarkFlagFilename("usage-file", "yml")
+	_ = cmd.MarkFlagFilename("template-path", "tmpl")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return validOutputFormats, cobra.ShellCompDirectiveDefault
+	})
+
+	return cmd
+}
+
+// reportCacheKey returns the key that the report of the current repo, commit and
+// config is cached under. An empty key is returned if reports can't be cached,
+// e.g. because the path isn't in a git repository.
+func reportCacheKey(ctx *config.RunContext) string {
+	commit := ctx.VCSMetadata.Commit.SHA
+	if commit == "" || ctx.Config.ReportCacheDir == "" {
+		return ""
+	}
+
+	repo := ctx.VCSRepositoryURL()
+	if repo == "" {
+		repo, _ = filepath.Abs(ctx.Config.RepoPath())
+	}
+
+	configHash, err := ctx.Config.ReportCacheHash()
+	if err != nil {
+		logging.Logger.WithError(err).Debug("failed to hash config for report cache")
+		return ""
+	}
+
+	return output.ReportCacheKey(repo, commit, configHash)
+}
@@ -0,0 +1,19 @@
+package main_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/testutil"
+)
+
+func TestShowHelp(t *testing.T) {
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"show", "--help"}, nil)
+}
+
+func TestShowNoPreviousRun(t *testing.T) {
+	dir := t.TempDir()
+	GoldenFileCommandTest(t, testutil.CalcGoldenFileTestdataDirName(), []string{"show", "--path", "./testdata/example_plan.json"}, nil, func(c *config.RunContext) {
+		c.Config.ReportCacheDir = dir
+	})
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+const defaultSnapshotFile = "infracost-snapshot.json"
+
+func snapshotCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save cost snapshots to use as diff baselines",
+		Long: `Save cost snapshots to use as diff baselines.
+
+A snapshot is an Infracost JSON file of the current costs. Any snapshot can be
+passed to 'infracost diff --compare-to' to show how costs changed since it was
+saved, without needing a Terraform plan of the changes.`,
+		Example: `  Report the cost drift of a week:
+
+      infracost snapshot save --path /code --out-file baseline.json
+      # A week later
+      infracost diff --path /code --compare-to baseline.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(snapshotSaveCmd(ctx))
+
+	return cmd
+}
+
+func snapshotSaveCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "save",
+		Short: "Save a snapshot of the current costs",
+		Long: `Save a snapshot of the current costs.
+
+The snapshot is the JSON output of 'infracost breakdown', so it can be used as
+the baseline of 'infracost diff --compare-to' and by the other commands that
+read Infracost JSON files.`,
+		Example: `  Save a snapshot of a Terraform directory:
+
+      infracost snapshot save --path /code --out-file baseline.json
+
+  Save a snapshot of the projects of a config file:
+
+      infracost snapshot save --config-file infracost.yml --out-file baseline.json`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !isOffline(cmd, ctx.Config) {
+				if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+					return err
+				}
+			}
+
+			err := loadRunFlags(ctx.Config, cmd)
+			if err != nil {
+				return err
+			}
+
+			// Snapshots are always JSON so they can be used as --compare-to
+			// baselines.
+			ctx.Config.Format = "json"
+			ctx.SetContextValue("outputFormat", ctx.Config.Format)
+
+			err = checkRunConfig(cmd.ErrOrStderr(), ctx.Config)
+			if err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
+			return runMain(cmd, ctx)
+		},
+	}
+
+	addRunFlags(cmd)
+
+	cmd.Flags().String("out-file", defaultSnapshotFile, "Save the snapshot to a file")
+	_ = cmd.MarkFlagFilename("out-file", "json")
+
+	return cmd
+}
@@ -0,0 +1,27 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_requesting_account_id  = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+module "lb" {
+  source = "./modules/lb"
+}
+
+# The listener sorts before the load balancer in the module, but the load
+# balancer still owns its hourly cost.
+resource "aws_lb_listener" "http" {
+  load_balancer_arn = module.lb.arn
+  port              = 80
+
+  default_action {
+    type = "fixed-response"
+
+    fixed_response {
+      content_type = "text/plain"
+      status_code  = "200"
+    }
+  }
+}
@@ -0,0 +1,7 @@
+resource "aws_lb" "this" {
+  load_balancer_type = "application"
+}
+
+output "arn" {
+  value = aws_lb.this.arn
+}
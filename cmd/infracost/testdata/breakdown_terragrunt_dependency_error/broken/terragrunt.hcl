@@ -0,0 +1,13 @@
+include {
+  path = find_in_parent_folders()
+}
+
+retry_max_attempts = 0
+
+terraform {
+  source = "..//modules/example"
+}
+
+inputs = {
+  vpc_name = "broken"
+}
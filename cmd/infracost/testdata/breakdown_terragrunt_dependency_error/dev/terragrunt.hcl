@@ -0,0 +1,15 @@
+include {
+  path = find_in_parent_folders()
+}
+
+dependency "broken" {
+  config_path = "../broken"
+}
+
+terraform {
+  source = "..//modules/example"
+}
+
+inputs = {
+  vpc_name = dependency.broken.outputs.vpc_name
+}
@@ -0,0 +1,15 @@
+variable "vpc_name" {
+  type = string
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = {
+    Name = var.vpc_name
+  }
+}
+
+output "vpc_name" {
+  value = aws_vpc.main.tags.Name
+}
@@ -0,0 +1,14 @@
+package infracost
+
+deny[msg] {
+	r := input.projects[_].breakdown.resources[_]
+	startswith(r.name, "module.db.")
+	to_number(r.monthlyCost) > 10
+	msg := sprintf("%s costs more than $10/mo", [r.name])
+}
+
+warn[{"msg": msg}] {
+	r := input.projects[_].breakdown.resources[_]
+	contains(r.name, "counted")
+	msg := sprintf("%s uses count, consider for_each", [r.name])
+}
@@ -0,0 +1,298 @@
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+)
+
+var (
+	// The AWS Price List API is only available in a few regions, it returns the
+	// prices of all regions from each of them.
+	awsPriceListRegion   = "us-east-1"
+	awsPriceListEndpoint = "https://api.pricing.us-east-1.amazonaws.com/"
+)
+
+// AWSPriceListClient is a PriceSource that looks up prices directly from the
+// AWS Price List Query API: https://docs.aws.amazon.com/awsaccountbilling/latest/aboutv2/using-price-list-query-api.html
+// Requests are signed with the credentials from the default AWS credential chain.
+type AWSPriceListClient struct {
+	httpClient *http.Client
+	endpoint   string
+	cache      sourceCache
+
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+}
+
+type awsPriceListFilter struct {
+	Type  string `json:"Type"`
+	Field string `json:"Field"`
+	Value string `json:"Value"`
+}
+
+type awsPriceListRequest struct {
+	ServiceCode   string               `json:"ServiceCode"`
+	Filters       []awsPriceListFilter `json:"Filters"`
+	FormatVersion string               `json:"FormatVersion"`
+	NextToken     string               `json:"NextToken,omitempty"`
+}
+
+type awsPriceListResponse struct {
+	PriceList []string `json:"PriceList"`
+	NextToken string   `json:"NextToken"`
+}
+
+type awsPriceListProduct struct {
+	ServiceCode string `json:"serviceCode"`
+	Product     struct {
+		Sku           string            `json:"sku"`
+		ProductFamily string            `json:"productFamily"`
+		Attributes    map[string]string `json:"attributes"`
+	} `json:"product"`
+	Terms map[string]map[string]awsPriceListTerm `json:"terms"`
+}
+
+type awsPriceListTerm struct {
+	PriceDimensions map[string]struct {
+		RateCode     string            `json:"rateCode"`
+		Description  string            `json:"description"`
+		BeginRange   string            `json:"beginRange"`
+		EndRange     string            `json:"endRange"`
+		Unit         string            `json:"unit"`
+		PricePerUnit map[string]string `json:"pricePerUnit"`
+	} `json:"priceDimensions"`
+	TermAttributes map[string]string `json:"termAttributes"`
+}
+
+func NewAWSPriceListClient(ctx *config.RunContext) *AWSPriceListClient {
+	client := retryablehttp.NewClient()
+	client.Logger = &LeveledLogger{Logger: logging.Logger.WithField("library", "retryablehttp")}
+
+	c := &AWSPriceListClient{
+		httpClient: client.StandardClient(),
+		endpoint:   awsPriceListEndpoint,
+		signer:     v4.NewSigner(),
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(awsPriceListRegion))
+	if err != nil {
+		log.Warnf("Error loading AWS config for the AWS Price List API: %s", err)
+	} else {
+		c.credentials = cfg.Credentials
+	}
+
+	return c
+}
+
+func (c *AWSPriceListClient) LookupPrices(keys []PriceQueryKey) ([]PriceQueryResult, error) {
+	results := make([]PriceQueryResult, 0, len(keys))
+
+	for _, k := range keys {
+		req := c.buildRequest(k)
+		if req == nil {
+			results = append(results, PriceQueryResult{
+				PriceQueryKey: k,
+				Result:        buildSourceResult(nil, nil, nil, "USD"),
+			})
+			continue
+		}
+
+		cacheKey, _ := json.Marshal(req)
+		products, err := c.cache.get(string(cacheKey), func() ([]sourceProduct, error) {
+			return c.fetchProducts(*req)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, PriceQueryResult{
+			PriceQueryKey: k,
+			Result:        buildSourceResult(products, k.CostComponent.ProductFilter, k.CostComponent.PriceFilter, "USD"),
+		})
+	}
+
+	return results, nil
+}
+
+// buildRequest returns the GetProducts request for the cost component. Only
+// exact values are included, regex filters are matched after the prices are
+// fetched. Cost components without a service can't be looked up so nil is
+// returned for them.
+func (c *AWSPriceListClient) buildRequest(k PriceQueryKey) *awsPriceListRequest {
+	f := k.CostComponent.ProductFilter
+	if f == nil || f.Service == nil {
+		return nil
+	}
+
+	req := &awsPriceListRequest{
+		ServiceCode:   *f.Service,
+		FormatVersion: "aws_v1",
+		Filters:       make([]awsPriceListFilter, 0),
+	}
+
+	values := filterValues(f)
+	if f.Region != nil {
+		values["regionCode"] = *f.Region
+	}
+	if f.ProductFamily != nil {
+		values["productFamily"] = *f.ProductFamily
+	}
+	if f.Sku != nil {
+		values["sku"] = *f.Sku
+	}
+
+	fields := make([]string, 0, len(values))
+	for k := range values {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		req.Filters = append(req.Filters, awsPriceListFilter{Type: "TERM_MATCH", Field: field, Value: values[field]})
+	}
+
+	return req
+}
+
+func (c *AWSPriceListClient) fetchProducts(req awsPriceListRequest) ([]sourceProduct, error) {
+	log.Debugf("Getting prices from the AWS Price List API for %s", req.ServiceCode)
+
+	products := make([]sourceProduct, 0)
+
+	for {
+		resp, err := c.getProducts(req)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, raw := range resp.PriceList {
+			var p awsPriceListProduct
+			err := json.Unmarshal([]byte(raw), &p)
+			if err != nil {
+				return nil, &APIError{err, "Invalid AWS Price List API response"}
+			}
+
+			products = append(products, p.toSourceProduct())
+		}
+
+		if resp.NextToken == "" {
+			break
+		}
+		req.NextToken = resp.NextToken
+	}
+
+	return products, nil
+}
+
+func (c *AWSPriceListClient) getProducts(r awsPriceListRequest) (*awsPriceListResponse, error) {
+	if c.credentials == nil {
+		return nil, errors.New("No AWS credentials found for the AWS Price List API")
+	}
+
+	body, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error generating request body")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error generating request")
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AWSPriceListService.GetProducts")
+	req.Header.Set("User-Agent", userAgent())
+
+	creds, err := c.credentials.Retrieve(req.Context())
+	if err != nil {
+		return nil, errors.Wrap(err, "Error retrieving AWS credentials")
+	}
+
+	hash := sha256.Sum256(body)
+	err = c.signer.SignHTTP(req.Context(), creds, req, hex.EncodeToString(hash[:]), "pricing", awsPriceListRegion, time.Now())
+	if err != nil {
+		return nil, errors.Wrap(err, "Error signing AWS Price List API request")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error sending AWS Price List API request")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &APIError{err, "Invalid AWS Price List API response"}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{fmt.Errorf("%v %s", resp.Status, respBody), "Received error from AWS Price List API"}
+	}
+
+	var out awsPriceListResponse
+	err = json.Unmarshal(respBody, &out)
+	if err != nil {
+		return nil, &APIError{err, "Invalid AWS Price List API response"}
+	}
+
+	return &out, nil
+}
+
+func (p awsPriceListProduct) toSourceProduct() sourceProduct {
+	prices := make([]sourcePrice, 0)
+
+	purchaseOptions := map[string]string{
+		"OnDemand": "on_demand",
+		"Reserved": "reserved",
+	}
+
+	for termType, terms := range p.Terms {
+		for _, term := range terms {
+			for _, d := range term.PriceDimensions {
+				prices = append(prices, sourcePrice{
+					Hash:               sourcePriceHash(d.RateCode),
+					Price:              d.PricePerUnit["USD"],
+					PurchaseOption:     purchaseOptions[termType],
+					Unit:               d.Unit,
+					Description:        d.Description,
+					StartUsageAmount:   d.BeginRange,
+					EndUsageAmount:     d.EndRange,
+					TermLength:         term.TermAttributes["LeaseContractLength"],
+					TermPurchaseOption: term.TermAttributes["PurchaseOption"],
+					TermOfferingClass:  term.TermAttributes["OfferingClass"],
+				})
+			}
+		}
+	}
+
+	// Terms are maps so the prices are sorted to make the order stable.
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Hash < prices[j].Hash
+	})
+
+	return sourceProduct{
+		Region:        p.Product.Attributes["regionCode"],
+		Service:       p.ServiceCode,
+		ProductFamily: p.Product.ProductFamily,
+		Sku:           p.Product.Sku,
+		Attributes:    p.Product.Attributes,
+		Prices:        prices,
+	}
+}
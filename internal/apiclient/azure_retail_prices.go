@@ -0,0 +1,246 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+)
+
+var (
+	azureRetailPricesEndpoint = "https://prices.azure.com/api/retail/prices"
+
+	// azureRetailPricesFilterFields are the attribute filter keys that can be
+	// used in the $filter parameter of the Azure Retail Prices API.
+	azureRetailPricesFilterFields = map[string]bool{
+		"armSkuName":  true,
+		"meterId":     true,
+		"meterName":   true,
+		"productId":   true,
+		"productName": true,
+		"skuId":       true,
+		"skuName":     true,
+	}
+)
+
+// AzureRetailPricesClient is a PriceSource that looks up prices directly from
+// the Azure Retail Prices API: https://learn.microsoft.com/en-us/rest/api/cost-management/retail-prices/azure-retail-prices
+type AzureRetailPricesClient struct {
+	httpClient *http.Client
+	endpoint   string
+	currency   string
+	cache      sourceCache
+}
+
+type azureRetailPricesResponse struct {
+	Items        []azureRetailPrice `json:"Items"`
+	NextPageLink string             `json:"NextPageLink"`
+}
+
+type azureRetailPrice struct {
+	CurrencyCode     string          `json:"currencyCode"`
+	TierMinimumUnits decimal.Decimal `json:"tierMinimumUnits"`
+	RetailPrice      decimal.Decimal `json:"retailPrice"`
+	ArmRegionName    string          `json:"armRegionName"`
+	Location         string          `json:"location"`
+	MeterID          string          `json:"meterId"`
+	MeterName        string          `json:"meterName"`
+	ProductID        string          `json:"productId"`
+	SkuID            string          `json:"skuId"`
+	ProductName      string          `json:"productName"`
+	SkuName          string          `json:"skuName"`
+	ServiceName      string          `json:"serviceName"`
+	ServiceFamily    string          `json:"serviceFamily"`
+	UnitOfMeasure    string          `json:"unitOfMeasure"`
+	Type             string          `json:"type"`
+	ArmSkuName       string          `json:"armSkuName"`
+	ReservationTerm  string          `json:"reservationTerm"`
+}
+
+func NewAzureRetailPricesClient(ctx *config.RunContext) *AzureRetailPricesClient {
+	client := retryablehttp.NewClient()
+	client.Logger = &LeveledLogger{Logger: logging.Logger.WithField("library", "retryablehttp")}
+
+	return &AzureRetailPricesClient{
+		httpClient: client.StandardClient(),
+		endpoint:   azureRetailPricesEndpoint,
+		currency:   PriceCurrency(ctx),
+	}
+}
+
+func (c *AzureRetailPricesClient) LookupPrices(keys []PriceQueryKey) ([]PriceQueryResult, error) {
+	results := make([]PriceQueryResult, 0, len(keys))
+
+	for _, k := range keys {
+		filter := c.buildFilter(k)
+
+		products, err := c.cache.get(filter, func() ([]sourceProduct, error) {
+			return c.fetchProducts(filter)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, PriceQueryResult{
+			PriceQueryKey: k,
+			Result:        buildSourceResult(products, k.CostComponent.ProductFilter, k.CostComponent.PriceFilter, c.currency),
+		})
+	}
+
+	return results, nil
+}
+
+// buildFilter returns the OData $filter for the cost component. Only exact
+// values are included, regex filters are matched after the prices are fetched.
+func (c *AzureRetailPricesClient) buildFilter(k PriceQueryKey) string {
+	f := k.CostComponent.ProductFilter
+	if f == nil {
+		return ""
+	}
+
+	conditions := make([]string, 0)
+
+	if f.Service != nil {
+		conditions = append(conditions, odataEq("serviceName", *f.Service))
+	}
+
+	if f.ProductFamily != nil {
+		conditions = append(conditions, odataEq("serviceFamily", *f.ProductFamily))
+	}
+
+	if f.Region != nil {
+		conditions = append(conditions, odataEq("armRegionName", *f.Region))
+	}
+
+	values := filterValues(f)
+	fields := make([]string, 0, len(values))
+	for k := range values {
+		if azureRetailPricesFilterFields[k] {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		conditions = append(conditions, odataEq(field, values[field]))
+	}
+
+	return strings.Join(conditions, " and ")
+}
+
+func (c *AzureRetailPricesClient) fetchProducts(filter string) ([]sourceProduct, error) {
+	q := url.Values{}
+	q.Set("currencyCode", fmt.Sprintf("'%s'", c.currency))
+	if filter != "" {
+		q.Set("$filter", filter)
+	}
+
+	log.Debugf("Getting prices from the Azure Retail Prices API for %s", filter)
+
+	// The API returns an item for each price, these are grouped by meter and
+	// SKU so that the tiers and purchase options of a meter are all prices of
+	// the same product.
+	products := make([]sourceProduct, 0)
+	indexes := make(map[string]int)
+	next := c.endpoint + "?" + q.Encode()
+
+	for next != "" {
+		resp, err := c.get(next)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range resp.Items {
+			key := item.MeterID + "/" + item.SkuID
+			if i, ok := indexes[key]; ok {
+				products[i].Prices = append(products[i].Prices, item.toSourcePrice())
+				continue
+			}
+
+			indexes[key] = len(products)
+			products = append(products, item.toSourceProduct())
+		}
+
+		next = resp.NextPageLink
+	}
+
+	return products, nil
+}
+
+func (c *AzureRetailPricesClient) get(u string) (*azureRetailPricesResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error generating request")
+	}
+	req.Header.Set("User-Agent", userAgent())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error sending Azure Retail Prices API request")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &APIError{err, "Invalid Azure Retail Prices API response"}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{fmt.Errorf("%v %s", resp.Status, body), "Received error from Azure Retail Prices API"}
+	}
+
+	var r azureRetailPricesResponse
+	err = json.Unmarshal(body, &r)
+	if err != nil {
+		return nil, &APIError{err, "Invalid Azure Retail Prices API response"}
+	}
+
+	return &r, nil
+}
+
+func (p azureRetailPrice) toSourceProduct() sourceProduct {
+	return sourceProduct{
+		Region:        p.ArmRegionName,
+		Service:       p.ServiceName,
+		ProductFamily: p.ServiceFamily,
+		Sku:           p.SkuID,
+		Attributes: map[string]string{
+			"armRegionName": p.ArmRegionName,
+			"armSkuName":    p.ArmSkuName,
+			"location":      p.Location,
+			"meterId":       p.MeterID,
+			"meterName":     p.MeterName,
+			"productId":     p.ProductID,
+			"productName":   p.ProductName,
+			"skuId":         p.SkuID,
+			"skuName":       p.SkuName,
+		},
+		Prices: []sourcePrice{p.toSourcePrice()},
+	}
+}
+
+func (p azureRetailPrice) toSourcePrice() sourcePrice {
+	return sourcePrice{
+		Hash:             sourcePriceHash(p.MeterID, p.SkuID, p.Type, p.ReservationTerm, p.TierMinimumUnits.String()),
+		Price:            p.RetailPrice.String(),
+		PurchaseOption:   p.Type,
+		Unit:             p.UnitOfMeasure,
+		StartUsageAmount: p.TierMinimumUnits.String(),
+		TermLength:       p.ReservationTerm,
+	}
+}
+
+func odataEq(field, value string) string {
+	return fmt.Sprintf("%s eq '%s'", field, strings.ReplaceAll(value, "'", "''"))
+}
@@ -0,0 +1,121 @@
+package apiclient
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/logging"
+)
+
+var ecbExchangeRatesEndpoint = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// ECBExchangeRatesClient looks up the daily euro foreign exchange reference
+// rates of the European Central Bank: https://www.ecb.europa.eu/stats/policy_and_exchange_rates/euro_reference_exchange_rates/html/index.en.html
+// The rates are only fetched once per run.
+type ECBExchangeRatesClient struct {
+	httpClient *http.Client
+	endpoint   string
+
+	once  sync.Once
+	rates map[string]decimal.Decimal
+	err   error
+}
+
+type ecbExchangeRatesResponse struct {
+	Cube struct {
+		Cube struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+func NewECBExchangeRatesClient() *ECBExchangeRatesClient {
+	client := retryablehttp.NewClient()
+	client.Logger = &LeveledLogger{Logger: logging.Logger.WithField("library", "retryablehttp")}
+
+	return &ECBExchangeRatesClient{
+		httpClient: client.StandardClient(),
+		endpoint:   ecbExchangeRatesEndpoint,
+	}
+}
+
+// Rate returns the amount of currency that one US dollar converts to. The ECB
+// rates are in euros, so they're converted through the USD rate.
+func (c *ECBExchangeRatesClient) Rate(currency string) (decimal.Decimal, error) {
+	c.once.Do(func() {
+		c.rates, c.err = c.fetchRates()
+	})
+	if c.err != nil {
+		return decimal.Zero, c.err
+	}
+
+	usd, ok := c.rates["USD"]
+	if !ok || usd.IsZero() {
+		return decimal.Zero, errors.New("No USD rate in the ECB exchange rates")
+	}
+
+	if currency == "EUR" {
+		return decimal.NewFromInt(1).Div(usd), nil
+	}
+
+	rate, ok := c.rates[currency]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("No ECB exchange rate for %s", currency)
+	}
+
+	return rate.Div(usd), nil
+}
+
+func (c *ECBExchangeRatesClient) fetchRates() (map[string]decimal.Decimal, error) {
+	req, err := http.NewRequest(http.MethodGet, c.endpoint, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error generating request")
+	}
+	req.Header.Set("User-Agent", userAgent())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error sending ECB exchange rates request")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &APIError{err, "Invalid ECB exchange rates response"}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{fmt.Errorf("%v %s", resp.Status, body), "Received error from ECB exchange rates"}
+	}
+
+	var r ecbExchangeRatesResponse
+	err = xml.Unmarshal(body, &r)
+	if err != nil {
+		return nil, &APIError{err, "Invalid ECB exchange rates response"}
+	}
+
+	rates := make(map[string]decimal.Decimal, len(r.Cube.Cube.Rates))
+	for _, rate := range r.Cube.Cube.Rates {
+		d, err := decimal.NewFromString(rate.Rate)
+		if err != nil {
+			return nil, &APIError{err, "Invalid ECB exchange rates response"}
+		}
+		rates[rate.Currency] = d
+	}
+
+	log.Debugf("Using ECB exchange rates from %s", r.Cube.Cube.Time)
+
+	return rates, nil
+}
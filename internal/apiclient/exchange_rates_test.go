@@ -0,0 +1,47 @@
+package apiclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestECBExchangeRatesClientRate(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2024-05-10">
+			<Cube currency="USD" rate="1.25"/>
+			<Cube currency="GBP" rate="0.85"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`)
+	}))
+	defer server.Close()
+
+	c := &ECBExchangeRatesClient{
+		httpClient: server.Client(),
+		endpoint:   server.URL,
+	}
+
+	rate, err := c.Rate("EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "0.8", rate.String())
+
+	rate, err = c.Rate("GBP")
+	require.NoError(t, err)
+	assert.Equal(t, "0.68", rate.String())
+
+	_, err = c.Rate("XYZ")
+	assert.EqualError(t, err, "No ECB exchange rate for XYZ")
+
+	assert.Equal(t, 1, requests, "the rates should only be fetched once")
+}
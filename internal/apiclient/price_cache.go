@@ -0,0 +1,151 @@
+package apiclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+// CachedPriceSource caches the results of another price source on disk, so
+// repeated runs don't look up the same prices again. Results are keyed by the
+// filters of the cost component and expire after the TTL. Failed lookups
+// aren't cached.
+type CachedPriceSource struct {
+	source PriceSource
+	dir    string
+	ttl    time.Duration
+	// namespace separates the results of runs that would get different prices
+	// for the same filters, e.g. in another currency.
+	namespace string
+}
+
+func NewCachedPriceSource(ctx *config.RunContext, source PriceSource) *CachedPriceSource {
+	namespace, _ := json.Marshal(map[string]interface{}{
+		"currency":         PriceCurrency(ctx),
+		"endpoint":         ctx.Config.PricingAPIEndpoint,
+		"pricingEndpoints": ctx.Config.PricingEndpoints,
+		"priceSources":     ctx.Config.PriceSources,
+	})
+
+	return &CachedPriceSource{
+		source:    source,
+		dir:       ctx.Config.PriceCacheDir,
+		ttl:       ctx.Config.PriceCacheTTL,
+		namespace: string(namespace),
+	}
+}
+
+// usePriceCache returns true if the prices of the run should be cached.
+func usePriceCache(ctx *config.RunContext) bool {
+	return !ctx.Config.NoCache && ctx.Config.PriceCacheDir != "" && ctx.Config.PriceCacheTTL > 0
+}
+
+func (c *CachedPriceSource) LookupPrices(keys []PriceQueryKey) ([]PriceQueryResult, error) {
+	results := make([]PriceQueryResult, len(keys))
+
+	var (
+		missing        []PriceQueryKey
+		missingIndexes []int
+	)
+
+	for i, k := range keys {
+		if raw, ok := c.get(c.key(k)); ok {
+			results[i] = PriceQueryResult{PriceQueryKey: k, Result: gjson.ParseBytes(raw)}
+			continue
+		}
+
+		missing = append(missing, k)
+		missingIndexes = append(missingIndexes, i)
+	}
+
+	log.Debugf("Using %d cached prices, looking up %d prices", len(keys)-len(missing), len(missing))
+
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	missingResults, err := c.source.LookupPrices(missing)
+	if err != nil {
+		return nil, err
+	}
+
+	for j, res := range missingResults {
+		results[missingIndexes[j]] = res
+
+		if res.Result.Exists() {
+			c.set(c.key(res.PriceQueryKey), []byte(res.Result.Raw))
+		}
+	}
+
+	return results, nil
+}
+
+// BatchesLookups returns true if the source that the prices are cached from
+// batches lookups.
+func (c *CachedPriceSource) BatchesLookups() bool {
+	return BatchesLookups(c.source)
+}
+
+func (c *CachedPriceSource) key(k PriceQueryKey) string {
+	h := sha256.Sum256([]byte(c.namespace + priceFiltersKey(k.CostComponent.ProductFilter, k.CostComponent.PriceFilter)))
+	return hex.EncodeToString(h[:])
+}
+
+func (c *CachedPriceSource) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+func (c *CachedPriceSource) get(key string) ([]byte, bool) {
+	path := c.path(key)
+
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > c.ttl {
+		return nil, false
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil || !gjson.ValidBytes(b) {
+		return nil, false
+	}
+
+	return b, true
+}
+
+// set writes the result to a temporary file first, so concurrent runs never
+// read a partially written result.
+func (c *CachedPriceSource) set(key string, b []byte) {
+	path := c.path(key)
+
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		log.Debugf("Error creating price cache directory: %s", err)
+		return
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		log.Debugf("Error caching price: %s", err)
+		return
+	}
+
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+
+	if err != nil {
+		log.Debugf("Error caching price: %s", err)
+		_ = os.Remove(f.Name())
+	}
+}
@@ -0,0 +1,89 @@
+package apiclient
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+type countingPriceSource struct {
+	fakePriceSource
+	lookedUp int
+}
+
+func (s *countingPriceSource) LookupPrices(keys []PriceQueryKey) ([]PriceQueryResult, error) {
+	s.lookedUp += len(keys)
+	return s.fakePriceSource.LookupPrices(keys)
+}
+
+func TestCachedPriceSource(t *testing.T) {
+	dir := t.TempDir()
+
+	newCtx := func(currency string) *config.RunContext {
+		ctx := config.EmptyRunContext()
+		ctx.Config.Currency = currency
+		ctx.Config.PriceCacheDir = dir
+		ctx.Config.PriceCacheTTL = time.Hour
+		return ctx
+	}
+
+	r := &schema.Resource{
+		Name: "resource",
+		CostComponents: []*schema.CostComponent{
+			{Name: "a", ProductFilter: &schema.ProductFilter{Sku: strPtr("a")}},
+			{Name: "b", ProductFilter: &schema.ProductFilter{Sku: strPtr("b")}},
+		},
+	}
+
+	source := &countingPriceSource{fakePriceSource: fakePriceSource{name: "pricing_api"}}
+	cached := NewCachedPriceSource(newCtx("USD"), source)
+
+	results, err := cached.LookupPrices(PriceQueryKeys(r))
+	require.NoError(t, err)
+	assert.Equal(t, 2, source.lookedUp)
+
+	results2, err := cached.LookupPrices(PriceQueryKeys(r))
+	require.NoError(t, err)
+	assert.Equal(t, 2, source.lookedUp, "prices should be read from the cache")
+	require.Len(t, results2, 2)
+	for i := range results {
+		assert.Equal(t, results[i].Result.Raw, results2[i].Result.Raw)
+		assert.Equal(t, results[i].CostComponent, results2[i].CostComponent)
+	}
+
+	// Prices in another currency aren't shared.
+	_, err = NewCachedPriceSource(newCtx("EUR"), source).LookupPrices(PriceQueryKeys(r))
+	require.NoError(t, err)
+	assert.Equal(t, 4, source.lookedUp)
+
+	// Expired prices are looked up again.
+	past := time.Now().Add(-2 * time.Hour)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		return os.Chtimes(path, past, past)
+	})
+	require.NoError(t, err)
+
+	_, err = cached.LookupPrices(PriceQueryKeys(r))
+	require.NoError(t, err)
+	assert.Equal(t, 6, source.lookedUp)
+}
+
+func TestNewPriceSourceCache(t *testing.T) {
+	ctx := config.EmptyRunContext()
+	ctx.Config.PriceCacheDir = t.TempDir()
+	ctx.Config.PriceCacheTTL = time.Hour
+	assert.IsType(t, &CachedPriceSource{}, NewPriceSource(ctx))
+
+	ctx.Config.NoCache = true
+	assert.IsType(t, &PricingAPIClient{}, NewPriceSource(ctx))
+}
@@ -0,0 +1,95 @@
+package apiclient
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+// fallbackSource is a price source in a FallbackPriceSource chain.
+type fallbackSource struct {
+	name   string
+	source PriceSource
+}
+
+// FallbackPriceSource looks up prices from an ordered list of sources, e.g. a
+// self-hosted pricing API, the hosted Cloud Pricing API and the pricing
+// snapshot. If a source fails, or one of the batched requests to a pricing API
+// doesn't answer within its timeout, all the lookups are retried with the next
+// source.
+type FallbackPriceSource struct {
+	sources []fallbackSource
+}
+
+// NewFallbackPriceSource returns the fallback chain of the PricingEndpoints in
+// the config.
+func NewFallbackPriceSource(ctx *config.RunContext) *FallbackPriceSource {
+	f := &FallbackPriceSource{}
+
+	for _, e := range ctx.Config.PricingEndpoints {
+		s := fallbackSource{name: e.Endpoint}
+
+		if e.Endpoint == config.PricingEndpointSnapshot {
+			s.name = fmt.Sprintf("pricing snapshot %s", ctx.Config.PricingSnapshotFile)
+			s.source = NewSnapshotPriceSource(ctx)
+		} else {
+			timeout := e.Timeout
+			if timeout <= 0 {
+				timeout = ctx.Config.PricingEndpointTimeout
+			}
+
+			// The timeout is set on the HTTP client so that it applies to each
+			// batch of queries, and the requests that time out are cancelled.
+			c := NewPricingAPIClient(ctx)
+			c.endpoint = e.Endpoint
+			c.apiKey = e.ResolveAPIKey(ctx.Config.APIKey)
+			c.httpClient.Timeout = timeout
+			s.source = c
+		}
+
+		f.sources = append(f.sources, s)
+	}
+
+	return f
+}
+
+// BatchesLookups returns true if all the sources in the chain batch lookups.
+func (f *FallbackPriceSource) BatchesLookups() bool {
+	for _, s := range f.sources {
+		if !BatchesLookups(s.source) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *FallbackPriceSource) LookupPrices(keys []PriceQueryKey) ([]PriceQueryResult, error) {
+	var lastErr error
+
+	for i, s := range f.sources {
+		results, err := s.source.LookupPrices(keys)
+		if err == nil {
+			for _, res := range results {
+				log.Debugf("Price of %s %s looked up from %s", res.Resource.Name, res.CostComponent.Name, s.name)
+			}
+
+			return results, nil
+		}
+
+		lastErr = err
+
+		if i < len(f.sources)-1 {
+			log.Warnf("Error looking up prices from %s, trying %s: %v", s.name, f.sources[i+1].name, err)
+		}
+	}
+
+	if lastErr == nil {
+		return nil, errors.New("No pricing endpoints are configured")
+	}
+
+	return nil, errors.Wrap(lastErr, "Error looking up prices from all pricing endpoints")
+}
@@ -0,0 +1,190 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func pricingAPIServer(t *testing.T, priceHash string, delay time.Duration) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+
+		var queries []GraphQLQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&queries))
+
+		results := make([]interface{}, 0, len(queries))
+		for range queries {
+			results = append(results, map[string]interface{}{
+				"data": map[string]interface{}{
+					"products": []interface{}{
+						map[string]interface{}{"prices": []interface{}{map[string]interface{}{"priceHash": priceHash, "USD": "1"}}},
+					},
+				},
+			})
+		}
+
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestFallbackPriceSourceLookupPrices(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"Bad request"}`))
+	}))
+	defer failing.Close()
+
+	slow := pricingAPIServer(t, "slow", time.Second)
+	hosted := pricingAPIServer(t, "hosted", 0)
+
+	r := &schema.Resource{Name: "resource", CostComponents: []*schema.CostComponent{{Name: "a", ProductFilter: &schema.ProductFilter{Sku: strPtr("a")}}}}
+
+	tests := []struct {
+		name      string
+		endpoints []config.PricingEndpoint
+		expected  string
+	}{
+		{
+			name:      "first endpoint answers",
+			endpoints: []config.PricingEndpoint{{Endpoint: hosted.URL}, {Endpoint: failing.URL}},
+			expected:  "hosted",
+		},
+		{
+			name:      "failing endpoint",
+			endpoints: []config.PricingEndpoint{{Endpoint: failing.URL}, {Endpoint: hosted.URL}},
+			expected:  "hosted",
+		},
+		{
+			name:      "endpoint times out",
+			endpoints: []config.PricingEndpoint{{Endpoint: slow.URL, Timeout: 50 * time.Millisecond}, {Endpoint: hosted.URL}},
+			expected:  "hosted",
+		},
+		{
+			name:      "endpoint within timeout",
+			endpoints: []config.PricingEndpoint{{Endpoint: slow.URL}, {Endpoint: hosted.URL}},
+			expected:  "slow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := config.EmptyRunContext()
+			ctx.Config.PricingEndpoints = tt.endpoints
+			ctx.Config.PricingEndpointTimeout = 5 * time.Second
+
+			s := NewPriceSource(ctx)
+			require.IsType(t, &FallbackPriceSource{}, s)
+			assert.True(t, BatchesLookups(s))
+
+			results, err := s.LookupPrices(PriceQueryKeys(r))
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, tt.expected, results[0].Result.Get("data.products.0.prices.0.priceHash").String())
+		})
+	}
+}
+
+func TestFallbackPriceSourceSnapshot(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"Bad request"}`))
+	}))
+	defer failing.Close()
+
+	r := &schema.Resource{Name: "resource", CostComponents: []*schema.CostComponent{{Name: "a", ProductFilter: &schema.ProductFilter{Sku: strPtr("a")}}}}
+
+	snapshot := NewPricingSnapshot("USD")
+	_, err := NewRecordingPriceSource(&fakePriceSource{name: "snapshot"}, snapshot).LookupPrices(PriceQueryKeys(r))
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "pricing-snapshot.db")
+	require.NoError(t, snapshot.Save(path))
+
+	ctx := config.EmptyRunContext()
+	ctx.Config.PricingSnapshotFile = path
+	ctx.Config.PricingEndpoints = []config.PricingEndpoint{{Endpoint: failing.URL}, {Endpoint: config.PricingEndpointSnapshot}}
+
+	results, err := NewPriceSource(ctx).LookupPrices(PriceQueryKeys(r))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "snapshot", results[0].Result.String())
+
+	ctx.Config.PricingEndpoints = []config.PricingEndpoint{{Endpoint: failing.URL}}
+
+	_, err = NewPriceSource(ctx).LookupPrices(PriceQueryKeys(r))
+	assert.ErrorContains(t, err, "Error looking up prices from all pricing endpoints")
+}
+
+func TestFallbackPriceSourceAPIKeys(t *testing.T) {
+	var selfHostedKey, hostedKey string
+
+	selfHosted := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		selfHostedKey = r.Header.Get("X-Api-Key")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"Bad request"}`))
+	}))
+	defer selfHosted.Close()
+
+	hosted := pricingAPIServer(t, "hosted", 0)
+	hostedWithKey := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hostedKey = r.Header.Get("X-Api-Key")
+		hosted.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer hostedWithKey.Close()
+
+	t.Setenv("SELF_HOSTED_PRICING_API_KEY", "self-hosted-key")
+
+	ctx := config.EmptyRunContext()
+	ctx.Config.APIKey = "hosted-key"
+	ctx.Config.PricingEndpoints = []config.PricingEndpoint{
+		{Endpoint: selfHosted.URL, APIKeyEnv: "SELF_HOSTED_PRICING_API_KEY"},
+		{Endpoint: hostedWithKey.URL},
+	}
+
+	r := &schema.Resource{Name: "resource", CostComponents: []*schema.CostComponent{{Name: "a", ProductFilter: &schema.ProductFilter{Sku: strPtr("a")}}}}
+
+	results, err := NewPriceSource(ctx).LookupPrices(PriceQueryKeys(r))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "self-hosted-key", selfHostedKey)
+	assert.Equal(t, "hosted-key", hostedKey)
+}
+
+func TestFallbackPriceSourceTimeoutPerBatch(t *testing.T) {
+	slow := pricingAPIServer(t, "slow", 150*time.Millisecond)
+	hosted := pricingAPIServer(t, "hosted", 0)
+
+	// Each batch answers within the timeout, but all of them together don't.
+	parallelism := 1
+	ctx := config.EmptyRunContext()
+	ctx.Config.PricingAPIBatchSize = 1
+	ctx.Config.Parallelism = &parallelism
+	ctx.Config.PricingEndpoints = []config.PricingEndpoint{{Endpoint: slow.URL, Timeout: 500 * time.Millisecond}, {Endpoint: hosted.URL}}
+
+	r := &schema.Resource{Name: "resource", CostComponents: []*schema.CostComponent{
+		{Name: "a", ProductFilter: &schema.ProductFilter{Sku: strPtr("a")}},
+		{Name: "b", ProductFilter: &schema.ProductFilter{Sku: strPtr("b")}},
+		{Name: "c", ProductFilter: &schema.ProductFilter{Sku: strPtr("c")}},
+		{Name: "d", ProductFilter: &schema.ProductFilter{Sku: strPtr("d")}},
+	}}
+
+	results, err := NewPriceSource(ctx).LookupPrices(PriceQueryKeys(r))
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	for _, res := range results {
+		assert.Equal(t, "slow", res.Result.Get("data.products.0.prices.0.priceHash").String())
+	}
+}
@@ -0,0 +1,423 @@
+package apiclient
+
+import (
+	"crypto/md5" // nolint:gosec
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// Price source names that can be set per vendor in the price_sources configuration.
+const (
+	PriceSourcePricingAPI        = "pricing_api"
+	PriceSourceAzureRetailPrices = "azure_retail_prices"
+	PriceSourceAWSPriceList      = "aws_price_list"
+)
+
+// PriceSource looks up the prices of cost components. Results must be returned
+// in the same order as the keys and use the response format of the Cloud Pricing
+// API GraphQL queries, so they can be handled the same way regardless of the
+// source that they came from.
+type PriceSource interface {
+	LookupPrices(keys []PriceQueryKey) ([]PriceQueryResult, error)
+}
+
+// BatchPriceSource is a PriceSource that looks up many prices efficiently in
+// one call, so it should be given the lookups of all the resources of a project
+// at once instead of one resource at a time.
+type BatchPriceSource interface {
+	PriceSource
+	BatchesLookups() bool
+}
+
+// BatchesLookups returns true if s should be given the lookups of all the
+// resources of a project at once.
+func BatchesLookups(s PriceSource) bool {
+	b, ok := s.(BatchPriceSource)
+	return ok && b.BatchesLookups()
+}
+
+// NewPriceSource returns the PriceSource configured for the run. Vendors that
+// don't have a price source set in the config use the Cloud Pricing API, or the
+// pricing endpoints fallback chain if it's configured.
+// Offline runs look up all prices from the pricing snapshot file. Other runs
+// cache the prices on disk unless the cache is disabled.
+func NewPriceSource(ctx *config.RunContext) PriceSource {
+	if ctx.Config.Offline {
+		return NewSnapshotPriceSource(ctx)
+	}
+
+	s := newVendorPriceSource(ctx)
+	if usePriceCache(ctx) {
+		return NewCachedPriceSource(ctx, s)
+	}
+
+	return s
+}
+
+func newVendorPriceSource(ctx *config.RunContext) PriceSource {
+	pricingAPI := newPricingAPISource(ctx)
+
+	if len(ctx.Config.PriceSources) == 0 {
+		return pricingAPI
+	}
+
+	v := &vendorPriceSource{
+		defaultSource: pricingAPI,
+		sources:       make(map[string]PriceSource),
+	}
+
+	for vendor, name := range ctx.Config.PriceSources {
+		switch {
+		case name == PriceSourcePricingAPI:
+			v.sources[vendor] = pricingAPI
+		case name == PriceSourceAzureRetailPrices && vendor == "azure":
+			v.sources[vendor] = NewAzureRetailPricesClient(ctx)
+		case name == PriceSourceAWSPriceList && vendor == "aws":
+			if PriceCurrency(ctx) != "USD" {
+				log.Warnf("The AWS Price List API only returns prices in USD, using the Cloud Pricing API for %s", vendor)
+				continue
+			}
+
+			v.sources[vendor] = NewAWSPriceListClient(ctx)
+		default:
+			log.Warnf("Invalid price source %q for %s, using the Cloud Pricing API", name, vendor)
+		}
+	}
+
+	return v
+}
+
+// newPricingAPISource returns the source of Cloud Pricing API prices, which is
+// the fallback chain of the pricing endpoints if they're configured.
+func newPricingAPISource(ctx *config.RunContext) PriceSource {
+	if len(ctx.Config.PricingEndpoints) > 0 {
+		return NewFallbackPriceSource(ctx)
+	}
+
+	return NewPricingAPIClient(ctx)
+}
+
+// PriceCurrency returns the currency that prices are looked up in. Prices are
+// looked up in USD if they're converted with an exchange rates source.
+func PriceCurrency(ctx *config.RunContext) string {
+	if ctx.Config.Currency == "" || ctx.Config.ExchangeRatesSource != "" {
+		return "USD"
+	}
+
+	return ctx.Config.Currency
+}
+
+// PriceQueryKeys returns the keys for all the cost components of the resource
+// and its sub resources.
+func PriceQueryKeys(r *schema.Resource) []PriceQueryKey {
+	keys := make([]PriceQueryKey, 0, len(r.CostComponents))
+
+	for _, component := range r.CostComponents {
+		keys = append(keys, PriceQueryKey{r, component})
+	}
+
+	for _, subresource := range r.FlattenedSubResources() {
+		for _, component := range subresource.CostComponents {
+			keys = append(keys, PriceQueryKey{subresource, component})
+		}
+	}
+
+	for _, alternative := range r.RecommendationAlternatives() {
+		for _, component := range alternative.CostComponents {
+			keys = append(keys, PriceQueryKey{alternative, component})
+		}
+	}
+
+	return keys
+}
+
+// vendorPriceSource sends the lookups for each cost component to the price
+// source configured for the vendor of its product filter.
+type vendorPriceSource struct {
+	defaultSource PriceSource
+	sources       map[string]PriceSource
+}
+
+// BatchesLookups returns true if all the sources of the vendors batch lookups.
+func (v *vendorPriceSource) BatchesLookups() bool {
+	if !BatchesLookups(v.defaultSource) {
+		return false
+	}
+
+	for _, s := range v.sources {
+		if !BatchesLookups(s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (v *vendorPriceSource) LookupPrices(keys []PriceQueryKey) ([]PriceQueryResult, error) {
+	groups := make(map[PriceSource][]int)
+	order := make([]PriceSource, 0, 1)
+
+	for i, k := range keys {
+		source := v.defaultSource
+		if k.CostComponent.ProductFilter != nil && k.CostComponent.ProductFilter.VendorName != nil {
+			if s, ok := v.sources[*k.CostComponent.ProductFilter.VendorName]; ok {
+				source = s
+			}
+		}
+
+		if _, ok := groups[source]; !ok {
+			order = append(order, source)
+		}
+		groups[source] = append(groups[source], i)
+	}
+
+	results := make([]PriceQueryResult, len(keys))
+
+	for _, source := range order {
+		indexes := groups[source]
+
+		sourceKeys := make([]PriceQueryKey, 0, len(indexes))
+		for _, i := range indexes {
+			sourceKeys = append(sourceKeys, keys[i])
+		}
+
+		sourceResults, err := source.LookupPrices(sourceKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, i := range indexes {
+			results[i] = sourceResults[j]
+		}
+	}
+
+	return results, nil
+}
+
+// sourceProduct is a product returned by a vendor price API, normalized so it
+// can be matched against the product and price filters of a cost component.
+type sourceProduct struct {
+	Region        string
+	Service       string
+	ProductFamily string
+	Sku           string
+	Attributes    map[string]string
+	Prices        []sourcePrice
+}
+
+type sourcePrice struct {
+	Hash               string
+	Price              string
+	PurchaseOption     string
+	Unit               string
+	Description        string
+	StartUsageAmount   string
+	EndUsageAmount     string
+	TermLength         string
+	TermPurchaseOption string
+	TermOfferingClass  string
+}
+
+// sourceCache caches the products returned by a vendor price API so cost
+// components that share the same request only fetch the products once.
+type sourceCache struct {
+	mu       sync.Mutex
+	products map[string][]sourceProduct
+}
+
+func (c *sourceCache) get(key string, fetch func() ([]sourceProduct, error)) ([]sourceProduct, error) {
+	c.mu.Lock()
+	products, ok := c.products[key]
+	c.mu.Unlock()
+
+	if ok {
+		return products, nil
+	}
+
+	products, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.products == nil {
+		c.products = make(map[string][]sourceProduct)
+	}
+	c.products[key] = products
+	c.mu.Unlock()
+
+	return products, nil
+}
+
+// buildSourceResult filters the products and their prices using the filters of
+// the cost component and returns them in the Cloud Pricing API response format.
+func buildSourceResult(products []sourceProduct, productFilter *schema.ProductFilter, priceFilter *schema.PriceFilter, currency string) gjson.Result {
+	type price map[string]string
+	type product struct {
+		Prices []price `json:"prices"`
+	}
+
+	matched := make([]product, 0)
+
+	for _, p := range products {
+		if !matchProduct(p, productFilter) {
+			continue
+		}
+
+		prices := make([]price, 0)
+		for _, pr := range p.Prices {
+			if matchPrice(pr, priceFilter) {
+				prices = append(prices, price{"priceHash": pr.Hash, currency: pr.Price})
+			}
+		}
+
+		matched = append(matched, product{Prices: prices})
+	}
+
+	b, _ := json.Marshal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"products": matched,
+		},
+	})
+
+	return gjson.ParseBytes(b)
+}
+
+func matchProduct(p sourceProduct, f *schema.ProductFilter) bool {
+	if f == nil {
+		return true
+	}
+
+	if !matchValue(p.Region, f.Region) ||
+		!matchValue(p.Service, f.Service) ||
+		!matchValue(p.ProductFamily, f.ProductFamily) ||
+		!matchValue(p.Sku, f.Sku) {
+		return false
+	}
+
+	for _, a := range f.AttributeFilters {
+		v, ok := p.Attributes[a.Key]
+		if !ok {
+			return false
+		}
+
+		if a.Value != nil && v != *a.Value {
+			return false
+		}
+
+		if a.ValueRegex != nil && !matchRegex(v, *a.ValueRegex) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchPrice(p sourcePrice, f *schema.PriceFilter) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.DescriptionRegex != nil && !matchRegex(p.Description, *f.DescriptionRegex) {
+		return false
+	}
+
+	// An empty end usage amount is used by the filters to select the last tier
+	// which the vendor APIs return as Inf or leave empty.
+	if f.EndUsageAmount != nil && *f.EndUsageAmount == "" && p.EndUsageAmount != "Inf" && p.EndUsageAmount != "" {
+		return false
+	}
+
+	if f.EndUsageAmount != nil && *f.EndUsageAmount != "" && !matchValue(p.EndUsageAmount, f.EndUsageAmount) {
+		return false
+	}
+
+	return matchValue(p.PurchaseOption, f.PurchaseOption) &&
+		matchValue(p.Unit, f.Unit) &&
+		matchValue(p.Description, f.Description) &&
+		matchValue(p.StartUsageAmount, f.StartUsageAmount) &&
+		matchValue(p.TermLength, f.TermLength) &&
+		matchValue(p.TermPurchaseOption, f.TermPurchaseOption) &&
+		matchValue(p.TermOfferingClass, f.TermOfferingClass)
+}
+
+func matchValue(v string, filter *string) bool {
+	return filter == nil || v == *filter
+}
+
+var (
+	regexCache   = map[string]*regexp.Regexp{}
+	regexCacheMu sync.Mutex
+)
+
+// matchRegex matches v against a filter regex in the /pattern/flags format
+// used by the Cloud Pricing API. Regexes that can't be compiled don't match.
+func matchRegex(v string, filterRegex string) bool {
+	regexCacheMu.Lock()
+	re, ok := regexCache[filterRegex]
+	if !ok {
+		re = compileFilterRegex(filterRegex)
+		regexCache[filterRegex] = re
+	}
+	regexCacheMu.Unlock()
+
+	return re != nil && re.MatchString(v)
+}
+
+func compileFilterRegex(filterRegex string) *regexp.Regexp {
+	pattern := filterRegex
+	flags := ""
+
+	if strings.HasPrefix(pattern, "/") {
+		end := strings.LastIndex(pattern, "/")
+		if end > 0 {
+			flags = pattern[end+1:]
+			pattern = pattern[1:end]
+		}
+	}
+
+	if strings.Contains(flags, "i") {
+		pattern = "(?i)" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		log.Debugf("Error compiling filter regex %s: %s", filterRegex, err)
+		return nil
+	}
+
+	return re
+}
+
+// sourcePriceHash returns a stable hash for a price from the values that
+// identify it in the vendor price API.
+func sourcePriceHash(parts ...string) string {
+	h := md5.Sum([]byte(strings.Join(parts, "-"))) // nolint:gosec
+	return hex.EncodeToString(h[:])
+}
+
+// filterValues returns the attribute filters of the product filter that have
+// exact values, which vendor APIs can use to narrow down their results.
+func filterValues(f *schema.ProductFilter) map[string]string {
+	values := make(map[string]string)
+	if f == nil {
+		return values
+	}
+
+	for _, a := range f.AttributeFilters {
+		if a.Value != nil {
+			values[a.Key] = *a.Value
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,128 @@
+package apiclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+type fakePriceSource struct {
+	name string
+}
+
+func (s *fakePriceSource) LookupPrices(keys []PriceQueryKey) ([]PriceQueryResult, error) {
+	results := make([]PriceQueryResult, 0, len(keys))
+	for _, k := range keys {
+		results = append(results, PriceQueryResult{PriceQueryKey: k, Result: gjson.Parse(fmt.Sprintf(`"%s"`, s.name))})
+	}
+
+	return results, nil
+}
+
+func TestVendorPriceSourceLookupPrices(t *testing.T) {
+	r := &schema.Resource{
+		Name: "resource",
+		CostComponents: []*schema.CostComponent{
+			{Name: "a", ProductFilter: &schema.ProductFilter{VendorName: strPtr("azure")}},
+			{Name: "b", ProductFilter: &schema.ProductFilter{VendorName: strPtr("gcp")}},
+			{Name: "c", ProductFilter: &schema.ProductFilter{VendorName: strPtr("azure")}},
+		},
+	}
+
+	s := &vendorPriceSource{
+		defaultSource: &fakePriceSource{name: "default"},
+		sources: map[string]PriceSource{
+			"azure": &fakePriceSource{name: "azure"},
+		},
+	}
+
+	results, err := s.LookupPrices(PriceQueryKeys(r))
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	for i, expected := range []string{"azure", "default", "azure"} {
+		assert.Equal(t, r.CostComponents[i], results[i].CostComponent)
+		assert.Equal(t, expected, results[i].Result.String())
+	}
+}
+
+func TestAzureRetailPricesClientLookupPrices(t *testing.T) {
+	var filters []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		filters = append(filters, req.URL.Query().Get("$filter"))
+
+		if req.URL.Query().Get("page") == "" {
+			fmt.Fprintf(w, `{"Items": [
+				{"meterId": "m1", "skuId": "s1", "skuName": "D2s v3", "productName": "Virtual Machines Dv3 Series", "serviceName": "Virtual Machines", "armRegionName": "eastus", "type": "Consumption", "unitOfMeasure": "1 Hour", "retailPrice": 0.096, "tierMinimumUnits": 0},
+				{"meterId": "m1", "skuId": "s1", "skuName": "D2s v3", "productName": "Virtual Machines Dv3 Series", "serviceName": "Virtual Machines", "armRegionName": "eastus", "type": "Reservation", "reservationTerm": "1 Year", "unitOfMeasure": "1 Hour", "retailPrice": 500, "tierMinimumUnits": 0}
+			], "NextPageLink": "%s?page=2"}`, "http://"+req.Host)
+			return
+		}
+
+		fmt.Fprint(w, `{"Items": [
+			{"meterId": "m2", "skuId": "s2", "skuName": "D2s v3 Spot", "productName": "Virtual Machines Dv3 Series", "serviceName": "Virtual Machines", "armRegionName": "eastus", "type": "Consumption", "unitOfMeasure": "1 Hour", "retailPrice": 0.02, "tierMinimumUnits": 0}
+		], "NextPageLink": null}`)
+	}))
+	defer server.Close()
+
+	c := &AzureRetailPricesClient{
+		httpClient: server.Client(),
+		endpoint:   server.URL,
+		currency:   "USD",
+	}
+
+	component := &schema.CostComponent{
+		Name: "Instance usage",
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("azure"),
+			Region:     strPtr("eastus"),
+			Service:    strPtr("Virtual Machines"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "productName", Value: strPtr("Virtual Machines Dv3 Series")},
+				{Key: "skuName", ValueRegex: strPtr("/^d2s v3$/i")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("Consumption"),
+		},
+	}
+
+	results, err := c.LookupPrices([]PriceQueryKey{{CostComponent: component}, {CostComponent: component}})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	// Both pages are fetched for the first lookup and the second lookup is cached.
+	assert.Equal(t, []string{
+		"serviceName eq 'Virtual Machines' and armRegionName eq 'eastus' and productName eq 'Virtual Machines Dv3 Series'",
+		"",
+	}, filters)
+
+	products := results[0].Result.Get("data.products").Array()
+	require.Len(t, products, 1)
+
+	prices := products[0].Get("prices").Array()
+	require.Len(t, prices, 1)
+	assert.Equal(t, "0.096", prices[0].Get("USD").String())
+	assert.NotEmpty(t, prices[0].Get("priceHash").String())
+}
+
+func TestMatchPriceEndUsageAmount(t *testing.T) {
+	last := sourcePrice{StartUsageAmount: "10", EndUsageAmount: "Inf"}
+	first := sourcePrice{StartUsageAmount: "0", EndUsageAmount: "10"}
+
+	assert.True(t, matchPrice(last, &schema.PriceFilter{EndUsageAmount: strPtr("")}))
+	assert.False(t, matchPrice(first, &schema.PriceFilter{EndUsageAmount: strPtr("")}))
+	assert.True(t, matchPrice(first, &schema.PriceFilter{EndUsageAmount: strPtr("10")}))
+}
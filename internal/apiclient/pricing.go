@@ -1,11 +1,15 @@
 package apiclient
 
 import (
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
+	"sync"
 
 	"github.com/hashicorp/go-retryablehttp"
 
@@ -23,10 +27,23 @@ var (
 	}
 )
 
+const (
+	// defaultPricingAPIBatchSize is the number of GraphQL queries that are sent
+	// in each request to the pricing API. Self-hosted pricing APIs can set a
+	// different size with INFRACOST_PRICING_API_BATCH_SIZE.
+	defaultPricingAPIBatchSize = 500
+	// pricingAPIBatchConcurrency is the default number of requests that are
+	// sent to the pricing API at the same time.
+	pricingAPIBatchConcurrency = 4
+)
+
 type PricingAPIClient struct {
 	APIClient
 	Currency       string
 	EventsDisabled bool
+	BatchSize      int
+	// Concurrency is the number of batches that are sent at the same time.
+	Concurrency int
 }
 
 type PriceQueryKey struct {
@@ -40,10 +57,7 @@ type PriceQueryResult struct {
 }
 
 func NewPricingAPIClient(ctx *config.RunContext) *PricingAPIClient {
-	currency := ctx.Config.Currency
-	if currency == "" {
-		currency = "USD"
-	}
+	currency := PriceCurrency(ctx)
 
 	tlsConfig := tls.Config{} // nolint: gosec
 
@@ -77,6 +91,16 @@ func NewPricingAPIClient(ctx *config.RunContext) *PricingAPIClient {
 	client.Logger = &LeveledLogger{Logger: logging.Logger.WithField("library", "retryablehttp")}
 	client.HTTPClient.Transport.(*http.Transport).TLSClientConfig = &tlsConfig
 
+	batchSize := ctx.Config.PricingAPIBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultPricingAPIBatchSize
+	}
+
+	concurrency := pricingAPIBatchConcurrency
+	if ctx.Config.Parallelism != nil && *ctx.Config.Parallelism > 0 {
+		concurrency = *ctx.Config.Parallelism
+	}
+
 	return &PricingAPIClient{
 		APIClient: APIClient{
 			httpClient: client.StandardClient(),
@@ -86,6 +110,8 @@ func NewPricingAPIClient(ctx *config.RunContext) *PricingAPIClient {
 		},
 		Currency:       currency,
 		EventsDisabled: ctx.Config.EventsDisabled,
+		BatchSize:      batchSize,
+		Concurrency:    concurrency,
 	}
 }
 
@@ -112,22 +138,120 @@ func (c *PricingAPIClient) AddEvent(name string, env map[string]interface{}) err
 	return err
 }
 
-func (c *PricingAPIClient) RunQueries(r *schema.Resource) ([]PriceQueryResult, error) {
-	keys, queries := c.batchQueries(r)
-
-	if len(queries) == 0 {
-		log.Debugf("Skipping getting pricing details for %s since there are no queries to run", r.Name)
+// LookupPrices looks up the prices of the cost components using the Cloud
+// Pricing API. Cost components with the same filters are only queried once and
+// the queries are sent in batches of GraphQL calls.
+func (c *PricingAPIClient) LookupPrices(keys []PriceQueryKey) ([]PriceQueryResult, error) {
+	if len(keys) == 0 {
+		log.Debug("Skipping getting pricing details since there are no queries to run")
 		return []PriceQueryResult{}, nil
 	}
 
-	log.Debugf("Getting pricing details from %s for %s", c.endpoint, r.Name)
+	queries := make([]GraphQLQuery, 0, len(keys))
+	queryIndexes := make([]int, 0, len(keys))
+	seen := make(map[string]int, len(keys))
+
+	for _, k := range keys {
+		filtersKey := priceFiltersKey(k.CostComponent.ProductFilter, k.CostComponent.PriceFilter)
+
+		i, ok := seen[filtersKey]
+		if !ok {
+			i = len(queries)
+			seen[filtersKey] = i
+			queries = append(queries, c.buildQuery(k.CostComponent.ProductFilter, k.CostComponent.PriceFilter))
+		}
+
+		queryIndexes = append(queryIndexes, i)
+	}
 
-	results, err := c.doQueries(queries)
+	log.Debugf("Getting pricing details from %s for %d cost components with %d queries", c.endpoint, len(keys), len(queries))
+
+	results, err := c.doBatchedQueries(queries)
 	if err != nil {
 		return []PriceQueryResult{}, err
 	}
 
-	return c.zipQueryResults(keys, results), nil
+	return c.zipQueryResults(keys, queryIndexes, results), nil
+}
+
+// BatchesLookups returns true since the lookups of all the resources of a
+// project are sent in a few requests.
+func (c *PricingAPIClient) BatchesLookups() bool {
+	return true
+}
+
+// doBatchedQueries sends the queries in batches of BatchSize, Concurrency
+// batches at a time. The results are returned in the same order as the queries.
+func (c *PricingAPIClient) doBatchedQueries(queries []GraphQLQuery) ([]gjson.Result, error) {
+	batchSize := c.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultPricingAPIBatchSize
+	}
+
+	concurrency := c.Concurrency
+	if concurrency <= 0 {
+		concurrency = pricingAPIBatchConcurrency
+	}
+
+	results := make([]gjson.Result, len(queries))
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for start := 0; start < len(queries); start += batchSize {
+		end := start + batchSize
+		if end > len(queries) {
+			end = len(queries)
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			batch, err := c.doQueries(queries[start:end])
+			if err == nil && len(batch) != end-start {
+				err = &APIError{fmt.Errorf("expected %d results, got %d", end-start, len(batch)), "Invalid pricing API response"}
+			}
+
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+
+			copy(results[start:end], batch)
+		}(start, end)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return results, nil
+}
+
+// priceFiltersKey returns a key for the product and price filters of a cost
+// component, which are the only inputs that change the result of a lookup.
+func priceFiltersKey(productFilter *schema.ProductFilter, priceFilter *schema.PriceFilter) string {
+	b, _ := json.Marshal(map[string]interface{}{
+		"productFilter": productFilter,
+		"priceFilter":   priceFilter,
+	})
+
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
 }
 
 func (c *PricingAPIClient) buildQuery(product *schema.ProductFilter, price *schema.PriceFilter) GraphQLQuery {
@@ -149,34 +273,13 @@ func (c *PricingAPIClient) buildQuery(product *schema.ProductFilter, price *sche
 	return GraphQLQuery{query, v}
 }
 
-// Batch all the queries for this resource so we can use one GraphQL call.
-// Use PriceQueryKeys to keep track of which query maps to which sub-resource and price component.
-func (c *PricingAPIClient) batchQueries(r *schema.Resource) ([]PriceQueryKey, []GraphQLQuery) {
-	keys := make([]PriceQueryKey, 0)
-	queries := make([]GraphQLQuery, 0)
-
-	for _, component := range r.CostComponents {
-		keys = append(keys, PriceQueryKey{r, component})
-		queries = append(queries, c.buildQuery(component.ProductFilter, component.PriceFilter))
-	}
-
-	for _, subresource := range r.FlattenedSubResources() {
-		for _, component := range subresource.CostComponents {
-			keys = append(keys, PriceQueryKey{subresource, component})
-			queries = append(queries, c.buildQuery(component.ProductFilter, component.PriceFilter))
-		}
-	}
-
-	return keys, queries
-}
-
-func (c *PricingAPIClient) zipQueryResults(k []PriceQueryKey, r []gjson.Result) []PriceQueryResult {
+func (c *PricingAPIClient) zipQueryResults(k []PriceQueryKey, queryIndexes []int, r []gjson.Result) []PriceQueryResult {
 	res := make([]PriceQueryResult, 0, len(k))
 
 	for i, k := range k {
 		res = append(res, PriceQueryResult{
 			PriceQueryKey: k,
-			Result:        r[i],
+			Result:        r[queryIndexes[i]],
 		})
 	}
 
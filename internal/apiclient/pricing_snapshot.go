@@ -0,0 +1,294 @@
+package apiclient
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+const pricingSnapshotVersion = "0.1"
+
+var (
+	// pricingSnapshotMetaBucket holds the version, currency and time
+	// generated of a pricing snapshot file.
+	pricingSnapshotMetaBucket = []byte("meta")
+	// pricingSnapshotPricesBucket holds one key per price lookup, so the
+	// prices can be looked up without loading the whole file.
+	pricingSnapshotPricesBucket = []byte("prices")
+)
+
+// emptyPriceResult is returned for the cost components that aren't in a
+// pricing snapshot, so they're shown without a price like they are when the
+// pricing API can't find a product.
+var emptyPriceResult = gjson.Parse(`{"data":{"products":[]}}`)
+
+// PricingSnapshot holds the results of price lookups so runs can resolve the
+// prices of a project without access to the pricing API. The results are keyed
+// by the product and price filters of the lookup and stored in the Cloud
+// Pricing API response format.
+type PricingSnapshot struct {
+	Version       string
+	Currency      string
+	TimeGenerated time.Time
+
+	prices map[string]string
+	mu     sync.Mutex
+}
+
+// NewPricingSnapshot returns an empty snapshot for prices in currency.
+func NewPricingSnapshot(currency string) *PricingSnapshot {
+	return &PricingSnapshot{
+		Version:       pricingSnapshotVersion,
+		Currency:      currency,
+		TimeGenerated: time.Now().UTC(),
+		prices:        make(map[string]string),
+	}
+}
+
+// Save writes the snapshot to a bbolt database at path. The database is
+// written to a temporary file first so an existing snapshot is left as it was
+// if the save fails.
+func (s *PricingSnapshot) Save(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := filepath.Dir(path)
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return errors.Wrap(err, "Error creating pricing snapshot directory")
+	}
+
+	f, err := os.CreateTemp(dir, ".pricing-snapshot-*")
+	if err != nil {
+		return errors.Wrap(err, "Error creating pricing snapshot")
+	}
+	tmpPath := f.Name()
+	f.Close()
+	defer os.Remove(tmpPath)
+
+	db, err := bolt.Open(tmpPath, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return errors.Wrap(err, "Error creating pricing snapshot")
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucket(pricingSnapshotMetaBucket)
+		if err != nil {
+			return err
+		}
+
+		for k, v := range map[string]string{
+			"version":       s.Version,
+			"currency":      s.Currency,
+			"timeGenerated": s.TimeGenerated.Format(time.RFC3339),
+		} {
+			err = meta.Put([]byte(k), []byte(v))
+			if err != nil {
+				return err
+			}
+		}
+
+		prices, err := tx.CreateBucket(pricingSnapshotPricesBucket)
+		if err != nil {
+			return err
+		}
+
+		// bbolt fills its pages best when the keys are put in order.
+		keys := make([]string, 0, len(s.prices))
+		for k := range s.prices {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			err = prices.Put([]byte(k), []byte(s.prices[k]))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return errors.Wrap(err, "Error writing pricing snapshot")
+	}
+
+	err = db.Close()
+	if err != nil {
+		return errors.Wrap(err, "Error writing pricing snapshot")
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// Len returns the number of price lookups in the snapshot.
+func (s *PricingSnapshot) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.prices)
+}
+
+func (s *PricingSnapshot) set(key string, raw string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prices[key] = raw
+}
+
+// pricingSnapshotFile is a pricing snapshot database opened read-only. Prices
+// are read from it one key at a time.
+type pricingSnapshotFile struct {
+	db       *bolt.DB
+	version  string
+	currency string
+}
+
+// openPricingSnapshot opens the snapshot database at path read-only and reads
+// its metadata.
+func openPricingSnapshot(path string) (*pricingSnapshotFile, error) {
+	_, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading pricing snapshot, run `infracost pricing download` to create it")
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{ReadOnly: true, Timeout: time.Second})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading pricing snapshot, run `infracost pricing download` to create it again")
+	}
+
+	f := &pricingSnapshotFile{db: db}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(pricingSnapshotMetaBucket)
+		if meta == nil || tx.Bucket(pricingSnapshotPricesBucket) == nil {
+			return errors.New("missing buckets")
+		}
+
+		f.version = string(meta.Get([]byte("version")))
+		f.currency = string(meta.Get([]byte("currency")))
+
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "Error reading pricing snapshot")
+	}
+
+	if f.version != pricingSnapshotVersion {
+		db.Close()
+		return nil, fmt.Errorf("Invalid pricing snapshot version %q, expected %s", f.version, pricingSnapshotVersion)
+	}
+
+	return f, nil
+}
+
+func (f *pricingSnapshotFile) Close() error {
+	return f.db.Close()
+}
+
+// RecordingPriceSource looks up prices from another source and records the
+// results in a snapshot.
+type RecordingPriceSource struct {
+	source   PriceSource
+	snapshot *PricingSnapshot
+}
+
+func NewRecordingPriceSource(source PriceSource, snapshot *PricingSnapshot) *RecordingPriceSource {
+	return &RecordingPriceSource{source: source, snapshot: snapshot}
+}
+
+func (r *RecordingPriceSource) LookupPrices(keys []PriceQueryKey) ([]PriceQueryResult, error) {
+	results, err := r.source.LookupPrices(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, res := range results {
+		c := res.CostComponent
+		if c == nil || !res.Result.Exists() {
+			continue
+		}
+
+		r.snapshot.set(priceFiltersKey(c.ProductFilter, c.PriceFilter), res.Result.Raw)
+	}
+
+	return results, nil
+}
+
+// BatchesLookups returns true if the source that the prices are recorded from
+// batches lookups.
+func (r *RecordingPriceSource) BatchesLookups() bool {
+	return BatchesLookups(r.source)
+}
+
+// SnapshotPriceSource looks up prices from a pricing snapshot file instead of
+// the pricing API. Cost components that aren't in the snapshot don't have a
+// price. The file is opened for each batch of lookups and only the prices of
+// the lookups are read from it.
+type SnapshotPriceSource struct {
+	path     string
+	currency string
+}
+
+func NewSnapshotPriceSource(ctx *config.RunContext) *SnapshotPriceSource {
+	return &SnapshotPriceSource{
+		path:     ctx.Config.PricingSnapshotFile,
+		currency: PriceCurrency(ctx),
+	}
+}
+
+func (s *SnapshotPriceSource) LookupPrices(keys []PriceQueryKey) ([]PriceQueryResult, error) {
+	snapshot, err := openPricingSnapshot(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer snapshot.Close()
+
+	if snapshot.currency != s.currency {
+		return nil, fmt.Errorf("The pricing snapshot %s has prices in %s, download it again with --currency %s", s.path, snapshot.currency, s.currency)
+	}
+
+	results := make([]PriceQueryResult, 0, len(keys))
+
+	err = snapshot.db.View(func(tx *bolt.Tx) error {
+		prices := tx.Bucket(pricingSnapshotPricesBucket)
+
+		for _, k := range keys {
+			result := emptyPriceResult
+
+			// The value is only valid during the transaction so it's copied.
+			raw := prices.Get([]byte(priceFiltersKey(k.CostComponent.ProductFilter, k.CostComponent.PriceFilter)))
+			if raw != nil {
+				result = gjson.Parse(string(raw))
+			} else {
+				log.Debugf("No price in the pricing snapshot for %s %s", k.Resource.Name, k.CostComponent.Name)
+			}
+
+			results = append(results, PriceQueryResult{PriceQueryKey: k, Result: result})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading pricing snapshot")
+	}
+
+	return results, nil
+}
+
+// BatchesLookups returns true since the lookups don't make any requests.
+func (s *SnapshotPriceSource) BatchesLookups() bool {
+	return true
+}
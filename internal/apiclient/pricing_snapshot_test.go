@@ -0,0 +1,83 @@
+package apiclient
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestPricingSnapshotRoundTrip(t *testing.T) {
+	recorded := &schema.CostComponent{Name: "a", ProductFilter: &schema.ProductFilter{VendorName: strPtr("aws"), Service: strPtr("AmazonEC2")}}
+	missing := &schema.CostComponent{Name: "b", ProductFilter: &schema.ProductFilter{VendorName: strPtr("aws"), Service: strPtr("AmazonS3")}}
+	r := &schema.Resource{Name: "resource", CostComponents: []*schema.CostComponent{recorded}}
+
+	snapshot := NewPricingSnapshot("USD")
+	_, err := NewRecordingPriceSource(&fakePriceSource{name: "pricing_api"}, snapshot).LookupPrices(PriceQueryKeys(r))
+	require.NoError(t, err)
+	assert.Equal(t, 1, snapshot.Len())
+
+	path := filepath.Join(t.TempDir(), "snapshot", "pricing-snapshot.db")
+	require.NoError(t, snapshot.Save(path))
+
+	ctx := &config.RunContext{Config: &config.Config{Offline: true, PricingSnapshotFile: path}}
+	s := NewPriceSource(ctx)
+	require.IsType(t, &SnapshotPriceSource{}, s)
+
+	r.CostComponents = append(r.CostComponents, missing)
+	results, err := s.LookupPrices(PriceQueryKeys(r))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "pricing_api", results[0].Result.String())
+	assert.Equal(t, recorded, results[0].CostComponent)
+	assert.Empty(t, results[1].Result.Get("data.products").Array())
+	assert.Equal(t, missing, results[1].CostComponent)
+}
+
+func TestSnapshotPriceSourceErrors(t *testing.T) {
+	r := &schema.Resource{Name: "resource", CostComponents: []*schema.CostComponent{{Name: "a"}}}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pricing-snapshot.db")
+	require.NoError(t, NewPricingSnapshot("USD").Save(path))
+
+	invalidPath := filepath.Join(dir, "pricing-snapshot.json.gz")
+	require.NoError(t, os.WriteFile(invalidPath, []byte(`{"version":"0.1","prices":{}}`), 0600))
+
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		errorMsg string
+	}{
+		{
+			name:     "missing file",
+			cfg:      &config.Config{PricingSnapshotFile: filepath.Join(dir, "missing.db")},
+			errorMsg: "Error reading pricing snapshot, run `infracost pricing download` to create it",
+		},
+		{
+			name:     "invalid file",
+			cfg:      &config.Config{PricingSnapshotFile: invalidPath},
+			errorMsg: "Error reading pricing snapshot, run `infracost pricing download` to create it again",
+		},
+		{
+			name:     "different currency",
+			cfg:      &config.Config{PricingSnapshotFile: path, Currency: "EUR"},
+			errorMsg: "has prices in USD, download it again with --currency EUR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSnapshotPriceSource(&config.RunContext{Config: tt.cfg})
+			_, err := s.LookupPrices(PriceQueryKeys(r))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
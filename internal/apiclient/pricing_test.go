@@ -0,0 +1,90 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestPricingAPIClientLookupPricesBatches(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		batchSizes []int
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var queries []GraphQLQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&queries))
+
+		mu.Lock()
+		batchSizes = append(batchSizes, len(queries))
+		mu.Unlock()
+
+		results := make([]interface{}, 0, len(queries))
+		for _, q := range queries {
+			sku := q.Variables["productFilter"].(map[string]interface{})["sku"]
+			results = append(results, map[string]interface{}{
+				"data": map[string]interface{}{
+					"products": []interface{}{
+						map[string]interface{}{"prices": []interface{}{map[string]interface{}{"priceHash": sku, "USD": "1"}}},
+					},
+				},
+			})
+		}
+
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	ctx := config.EmptyRunContext()
+	ctx.Config.PricingAPIEndpoint = server.URL
+	ctx.Config.PricingAPIBatchSize = 2
+
+	c := NewPricingAPIClient(ctx)
+	assert.True(t, BatchesLookups(c))
+
+	// Five cost components with three distinct filters are looked up with
+	// three queries, which are sent in two batches.
+	var keys []PriceQueryKey
+	for _, sku := range []string{"a", "b", "a", "c", "b"} {
+		r := &schema.Resource{Name: "resource"}
+		keys = append(keys, PriceQueryKey{
+			Resource:      r,
+			CostComponent: &schema.CostComponent{Name: sku, ProductFilter: &schema.ProductFilter{Sku: strPtr(sku)}},
+		})
+	}
+
+	results, err := c.LookupPrices(keys)
+	require.NoError(t, err)
+	require.Len(t, results, len(keys))
+
+	for i, res := range results {
+		assert.Equal(t, keys[i].CostComponent, res.CostComponent)
+		assert.Equal(t, keys[i].CostComponent.Name, res.Result.Get("data.products.0.prices.0.priceHash").String(), fmt.Sprintf("result %d", i))
+	}
+
+	assert.ElementsMatch(t, []int{2, 1}, batchSizes)
+}
+
+func TestPricingAPIClientLookupPricesInvalidResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	ctx := config.EmptyRunContext()
+	ctx.Config.PricingAPIEndpoint = server.URL
+
+	c := NewPricingAPIClient(ctx)
+	_, err := c.LookupPrices([]PriceQueryKey{{Resource: &schema.Resource{Name: "resource"}, CostComponent: &schema.CostComponent{Name: "a"}}})
+	assert.EqualError(t, err, "Invalid pricing API response: expected 1 results, got 0")
+}
@@ -0,0 +1,39 @@
+package apiclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/infracost/infracost/internal/logging"
+)
+
+// PostWebhook posts a JSON message, such as a Slack message or Microsoft Teams
+// card, to an incoming webhook URL. Failed requests are retried.
+func PostWebhook(url string, body []byte) error {
+	client := retryablehttp.NewClient()
+	client.Logger = &LeveledLogger{Logger: logging.Logger.WithField("library", "retryablehttp")}
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("Error creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Error posting to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Error posting to webhook: %s %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package apiclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostWebhook(t *testing.T) {
+	var body []byte
+	var contentType string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		contentType = r.Header.Get("Content-Type")
+	}))
+	defer ts.Close()
+
+	require.NoError(t, PostWebhook(ts.URL, []byte(`{"text":"hello"}`)))
+	assert.Equal(t, `{"text":"hello"}`, string(body))
+	assert.Equal(t, "application/json", contentType)
+}
+
+func TestPostWebhookError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid_payload"))
+	}))
+	defer ts.Close()
+
+	err := PostWebhook(ts.URL, []byte(`{}`))
+	assert.EqualError(t, err, "Error posting to webhook: 400 Bad Request invalid_payload")
+}
@@ -48,10 +48,10 @@ func (c *azureReposComment) Less(other Comment) bool {
 	return c.id < j.id
 }
 
-// IsHidden always returns false for Azure Repos since Azure Repos doesn't have a
-// feature for hiding comments.
+// IsHidden returns true if the comment has been collapsed. Azure Repos doesn't
+// have a feature for hiding comments so they're collapsed instead.
 func (c *azureReposComment) IsHidden() bool {
-	return false
+	return isCollapsedComment(c.content)
 }
 
 // AzureReposExtra contains any extra inputs that can be passed to the Azure Repos
@@ -340,9 +340,9 @@ func (h *azureReposPRHandler) CallDeleteComment(ctx context.Context, comment Com
 	return err
 }
 
-// CallHideComment calls the Azure Repos API to minimize the pull request comment.
+// CallHideComment calls the Azure Repos API to collapse the pull request comment.
 func (h *azureReposPRHandler) CallHideComment(ctx context.Context, comment Comment) error {
-	return errors.New("Not implemented")
+	return h.CallUpdateComment(ctx, comment, collapseComment(comment.Body()))
 }
 
 // AddMarkdownTag prepends a tag as a markdown comment to the given string.
@@ -1,9 +1,14 @@
 package comment
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_buildAzureAPIURL(t *testing.T) {
@@ -24,3 +29,20 @@ func Test_buildAzureAPIURL(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureReposCallHideComment(t *testing.T) {
+	var method string
+	var reqBody map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		_ = json.NewDecoder(r.Body).Decode(&reqBody)
+	}))
+	defer ts.Close()
+
+	h := &azureReposPRHandler{httpClient: ts.Client()}
+	err := h.CallHideComment(context.Background(), &azureReposComment{content: "Monthly cost will increase by $10", href: ts.URL})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPatch, method)
+	assert.True(t, isCollapsedComment(reqBody["content"].(string)))
+}
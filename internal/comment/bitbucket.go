@@ -53,10 +53,10 @@ func (c *bitbucketComment) Less(other Comment) bool {
 	return c.id < j.id
 }
 
-// IsHidden always returns false for Bitbucket since Bitbucket doesn't have a
-// feature for hiding comments.
+// IsHidden returns true if the comment has been marked as outdated. Bitbucket
+// doesn't have a feature for hiding comments so they're marked instead.
 func (c *bitbucketComment) IsHidden() bool {
-	return false
+	return isOutdatedComment(c.body)
 }
 
 // BitbucketExtra contains any extra inputs that can be passed to the Bitbucket
@@ -324,9 +324,9 @@ func (h *bitbucketPRHandler) CallDeleteComment(ctx context.Context, comment Comm
 	return err
 }
 
-// CallHideComment calls the Bitbucket API to minimize the pull request comment.
+// CallHideComment calls the Bitbucket API to mark the pull request comment as outdated.
 func (h *bitbucketPRHandler) CallHideComment(ctx context.Context, comment Comment) error {
-	return errors.New("Not implemented")
+	return h.CallUpdateComment(ctx, comment, markCommentOutdated(comment.Body()))
 }
 
 // AddMarkdownTag appends a tag to the end of the given string. Bitbucket
@@ -529,9 +529,9 @@ func (h *bitbucketCommitHandler) CallDeleteComment(ctx context.Context, comment
 	return err
 }
 
-// CallHideComment calls the Bitbucket API to minimize the pull request comment.
+// CallHideComment calls the Bitbucket API to mark the commit comment as outdated.
 func (h *bitbucketCommitHandler) CallHideComment(ctx context.Context, comment Comment) error {
-	return errors.New("Not implemented")
+	return h.CallUpdateComment(ctx, comment, markCommentOutdated(comment.Body()))
 }
 
 // AddMarkdownTag appends a tag to the end of the given string. Bitbucket
@@ -751,9 +751,9 @@ func (h *bitbucketServerPRHandler) CallDeleteComment(ctx context.Context, commen
 	return err
 }
 
-// CallHideComment calls the Bitbucket Server API to minimize the pull request comment.
+// CallHideComment calls the Bitbucket Server API to mark the pull request comment as outdated.
 func (h *bitbucketServerPRHandler) CallHideComment(ctx context.Context, comment Comment) error {
-	return errors.New("Not implemented")
+	return h.CallUpdateComment(ctx, comment, markCommentOutdated(comment.Body()))
 }
 
 // AddMarkdownTag appends a tag to the end of the given string. Bitbucket
@@ -96,7 +96,7 @@ func newGitHubAPIClients(ctx context.Context, token string, apiURL string, tlsCo
 	tc := oauth2.NewClient(httpCtx, ts)
 
 	// Handle default GitHub API client
-	if apiURL == "" || apiURL == "https://api.github.com" {
+	if apiURL == "" || strings.TrimSuffix(apiURL, "/") == "https://api.github.com" {
 		return github.NewClient(tc), githubv4.NewClient(tc), nil
 	}
 
@@ -114,6 +114,17 @@ func newGitHubAPIClients(ctx context.Context, token string, apiURL string, tlsCo
 		u.Path += "/"
 	}
 
+	// Remove the API version, e.g. GitHub Actions sets GITHUB_API_URL to
+	// https://github.example.com/api/v3 for GitHub Enterprise Server. Only the
+	// exact api/v3 and api/graphql segments are removed so custom paths like
+	// /foov3/ are kept.
+	for _, suffix := range []string{"api/v3/", "api/graphql/"} {
+		if strings.HasSuffix(u.Path, "/"+suffix) {
+			u.Path = strings.TrimSuffix(u.Path, suffix) + "api/"
+			break
+		}
+	}
+
 	// Add api to path if it doesn't exist
 	if !strings.HasSuffix(u.Path, "/api/") {
 		u.Path += "api/"
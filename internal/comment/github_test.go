@@ -0,0 +1,40 @@
+package comment
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGitHubAPIClients(t *testing.T) {
+	tests := []struct {
+		name      string
+		apiURL    string
+		baseURL   string
+		uploadURL string
+	}{
+		{"github.com default", "", "https://api.github.com/", "https://uploads.github.com/"},
+		{"github.com", "https://api.github.com", "https://api.github.com/", "https://uploads.github.com/"},
+		{"github.com trailing slash", "https://api.github.com/", "https://api.github.com/", "https://uploads.github.com/"},
+		{"GHES host", "https://github.example.com", "https://github.example.com/api/v3/", "https://github.example.com/api/uploads/"},
+		{"GHES api", "https://github.example.com/api", "https://github.example.com/api/v3/", "https://github.example.com/api/uploads/"},
+		{"GHES api v3", "https://github.example.com/api/v3", "https://github.example.com/api/v3/", "https://github.example.com/api/uploads/"},
+		{"GHES api v3 trailing slash", "https://github.example.com/api/v3/", "https://github.example.com/api/v3/", "https://github.example.com/api/uploads/"},
+		{"GHES graphql", "https://github.example.com/api/graphql", "https://github.example.com/api/v3/", "https://github.example.com/api/uploads/"},
+		{"custom path", "https://example.com/github/api/v3", "https://example.com/github/api/v3/", "https://example.com/github/api/uploads/"},
+		{"custom path ending in v3", "https://example.com/foov3", "https://example.com/foov3/api/v3/", "https://example.com/foov3/api/uploads/"},
+		{"custom path ending in graphql", "https://example.com/mygraphql/", "https://example.com/mygraphql/api/v3/", "https://example.com/mygraphql/api/uploads/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v3client, _, err := newGitHubAPIClients(context.Background(), "token", tt.apiURL, &tls.Config{}) // nolint: gosec
+			require.NoError(t, err)
+			assert.Equal(t, tt.baseURL, v3client.BaseURL.String())
+			assert.Equal(t, tt.uploadURL, v3client.UploadURL.String())
+		})
+	}
+}
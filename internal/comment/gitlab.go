@@ -49,10 +49,10 @@ func (c *gitlabComment) Less(other Comment) bool {
 	return c.id < j.id
 }
 
-// IsHidden always returns false for GitLab since GitLab doesn't have a
-// feature for hiding comments.
+// IsHidden returns true if the comment has been collapsed. GitLab doesn't have
+// a feature for hiding comments so they're collapsed instead.
 func (c *gitlabComment) IsHidden() bool {
-	return false
+	return isCollapsedComment(c.body)
 }
 
 // GitLabExtra contains any extra inputs that can be passed to the GitLab comment handlers.
@@ -287,9 +287,9 @@ func (h *gitlabPRHandler) CallDeleteComment(ctx context.Context, comment Comment
 	return h.graphqlClient.Mutate(ctx, &m, variables)
 }
 
-// CallHideComment calls the GitLab API to minimize the merge request comment.
+// CallHideComment calls the GitLab API to collapse the merge request comment.
 func (h *gitlabPRHandler) CallHideComment(ctx context.Context, comment Comment) error {
-	return errors.New("Not implemented")
+	return h.CallUpdateComment(ctx, comment, collapseComment(comment.Body()))
 }
 
 // AddMarkdownTag prepends a tag as a markdown comment to the given string.
@@ -536,9 +536,9 @@ func (h *gitlabCommitHandler) CallDeleteComment(ctx context.Context, comment Com
 	return nil
 }
 
-// CallHideComment calls the GitLab API to minimize the commit comment.
+// CallHideComment calls the GitLab API to collapse the commit comment.
 func (h *gitlabCommitHandler) CallHideComment(ctx context.Context, comment Comment) error {
-	return errors.New("Not implemented")
+	return h.CallUpdateComment(ctx, comment, collapseComment(comment.Body()))
 }
 
 // AddMarkdownTag prepends a tag as a markdown comment to the given string.
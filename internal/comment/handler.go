@@ -6,10 +6,15 @@ import (
 	"github.com/fatih/color"
 	log "github.com/sirupsen/logrus"
 	"sort"
+	"strings"
 )
 
 var defaultTag = "infracost-comment"
 
+// fingerprintTagPrefix prefixes the fingerprint that is embedded in comments
+// as a second tag.
+var fingerprintTagPrefix = "infracost-fingerprint:"
+
 // Comment is an interface that represents a comment on any platform. It wraps
 // the platform specific comment structures and is used to abstract the
 // logic for finding, creating, updating, and deleting the comments.
@@ -60,6 +65,11 @@ type PlatformHandler interface {
 type CommentHandler struct { //nolint
 	PlatformHandler PlatformHandler
 	Tag             string
+	// Fingerprint is a hash of the material content of the comment. It's
+	// embedded in the comment so that it isn't updated or posted again if the
+	// latest matching comment has the same fingerprint, even if the body has
+	// changed in ways that don't matter, e.g. the share URL of the run.
+	Fingerprint string
 }
 
 // NewCommentHandler creates a new CommentHandler.
@@ -135,9 +145,45 @@ func (h *CommentHandler) LatestMatchingComment(ctx context.Context) (Comment, er
 	return matchingComments[len(matchingComments)-1], nil
 }
 
+// addTags adds the tag and the fingerprint, if there is one, to the body.
+func (h *CommentHandler) addTags(body string) string {
+	if h.Fingerprint != "" {
+		body = h.PlatformHandler.AddMarkdownTag(body, fingerprintTagPrefix+h.Fingerprint)
+	}
+
+	return h.PlatformHandler.AddMarkdownTag(body, h.Tag)
+}
+
+// isUnchanged returns true if the comment has the same fingerprint as the
+// comment that would be posted.
+func (h *CommentHandler) isUnchanged(comment Comment) bool {
+	return h.Fingerprint != "" && comment != nil && strings.Contains(comment.Body(), fingerprintTagPrefix+h.Fingerprint)
+}
+
+// latestUnchangedComment returns the latest of the comments if it's visible
+// and has the same fingerprint as the comment that would be posted.
+func (h *CommentHandler) latestUnchangedComment(comments []Comment) Comment {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	sorted := make([]Comment, len(comments))
+	copy(sorted, comments)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Less(sorted[j])
+	})
+
+	latest := sorted[len(sorted)-1]
+	if latest.IsHidden() || !h.isUnchanged(latest) {
+		return nil
+	}
+
+	return latest
+}
+
 // UpdateComment updates the comment with the given body. Returns boolean indicating if the comment was actually posted.
 func (h *CommentHandler) UpdateComment(ctx context.Context, skipNoDiff bool, body string) (bool, error) {
-	bodyWithTag := h.PlatformHandler.AddMarkdownTag(body, h.Tag)
+	bodyWithTag := h.addTags(body)
 
 	latestMatchingComment, err := h.LatestMatchingComment(ctx)
 	if err != nil {
@@ -150,6 +196,11 @@ func (h *CommentHandler) UpdateComment(ctx context.Context, skipNoDiff bool, bod
 			return false, nil
 		}
 
+		if h.isUnchanged(latestMatchingComment) {
+			log.Infof("Not updating comment since the cost estimate hasn't changed since the latest one: %s", color.HiBlueString(latestMatchingComment.Ref()))
+			return false, nil
+		}
+
 		log.Infof("Updating comment %s", color.HiBlueString(latestMatchingComment.Ref()))
 
 		err := h.PlatformHandler.CallUpdateComment(ctx, latestMatchingComment, bodyWithTag)
@@ -177,7 +228,7 @@ func (h *CommentHandler) UpdateComment(ctx context.Context, skipNoDiff bool, bod
 
 // NewComment creates a new comment with the given body.
 func (h *CommentHandler) NewComment(ctx context.Context, body string) error {
-	bodyWithTag := h.PlatformHandler.AddMarkdownTag(body, h.Tag)
+	bodyWithTag := h.addTags(body)
 
 	log.Info("Creating new comment")
 
@@ -204,6 +255,11 @@ func (h *CommentHandler) HideAndNewComment(ctx context.Context, skipNoDiff bool,
 		return false, nil
 	}
 
+	if latest := h.latestUnchangedComment(matchingComments); latest != nil {
+		log.Infof("Not posting a new comment since the cost estimate hasn't changed since the latest one: %s", color.HiBlueString(latest.Ref()))
+		return false, nil
+	}
+
 	err = h.hideComments(ctx, matchingComments)
 	if err != nil {
 		return false, err
@@ -265,6 +321,11 @@ func (h *CommentHandler) DeleteAndNewComment(ctx context.Context, skipNoDiff boo
 		return false, nil
 	}
 
+	if latest := h.latestUnchangedComment(matchingComments); latest != nil {
+		log.Infof("Not posting a new comment since the cost estimate hasn't changed since the latest one: %s", color.HiBlueString(latest.Ref()))
+		return false, nil
+	}
+
 	err = h.deleteComments(ctx, matchingComments)
 	if err != nil {
 		return false, err
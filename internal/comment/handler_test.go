@@ -0,0 +1,89 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeComment struct {
+	id     int
+	body   string
+	hidden bool
+}
+
+func (c *fakeComment) Body() string        { return c.body }
+func (c *fakeComment) Ref() string         { return "comment" }
+func (c *fakeComment) Less(o Comment) bool { return c.id < o.(*fakeComment).id }
+func (c *fakeComment) IsHidden() bool      { return c.hidden }
+
+// fakePlatformHandler keeps the comments in memory.
+type fakePlatformHandler struct {
+	comments []*fakeComment
+}
+
+func (h *fakePlatformHandler) CallFindMatchingComments(ctx context.Context, tag string) ([]Comment, error) {
+	var comments []Comment
+	for _, c := range h.comments {
+		comments = append(comments, c)
+	}
+
+	return comments, nil
+}
+
+func (h *fakePlatformHandler) CallCreateComment(ctx context.Context, body string) (Comment, error) {
+	c := &fakeComment{id: len(h.comments), body: body}
+	h.comments = append(h.comments, c)
+
+	return c, nil
+}
+
+func (h *fakePlatformHandler) CallUpdateComment(ctx context.Context, comment Comment, body string) error {
+	comment.(*fakeComment).body = body
+	return nil
+}
+
+func (h *fakePlatformHandler) CallDeleteComment(ctx context.Context, comment Comment) error {
+	return errors.New("not implemented")
+}
+
+func (h *fakePlatformHandler) CallHideComment(ctx context.Context, comment Comment) error {
+	comment.(*fakeComment).hidden = true
+	return nil
+}
+
+func (h *fakePlatformHandler) AddMarkdownTag(s string, tag string) string {
+	return addMarkdownTag(s, tag)
+}
+
+func TestCommentWithBehaviorFingerprint(t *testing.T) {
+	for _, behavior := range []string{"update", "hide-and-new"} {
+		t.Run(behavior, func(t *testing.T) {
+			ctx := context.Background()
+			platform := &fakePlatformHandler{}
+			h := NewCommentHandler(ctx, platform, "")
+
+			h.Fingerprint = "abc"
+			posted, err := h.CommentWithBehavior(ctx, false, behavior, "Monthly cost: $10, share URL 1")
+			require.NoError(t, err)
+			assert.True(t, posted)
+			require.Len(t, platform.comments, 1)
+			assert.Equal(t, "[//]: <> (infracost-comment)\n[//]: <> (infracost-fingerprint:abc)\nMonthly cost: $10, share URL 1", platform.comments[0].body)
+
+			posted, err = h.CommentWithBehavior(ctx, false, behavior, "Monthly cost: $10, share URL 2")
+			require.NoError(t, err)
+			assert.False(t, posted, "the comment should not be posted again if the fingerprint is the same")
+			assert.Len(t, platform.comments, 1)
+			assert.Contains(t, platform.comments[0].body, "share URL 1")
+
+			h.Fingerprint = "def"
+			posted, err = h.CommentWithBehavior(ctx, false, behavior, "Monthly cost: $20, share URL 3")
+			require.NoError(t, err)
+			assert.True(t, posted)
+			assert.Contains(t, platform.comments[len(platform.comments)-1].body, "$20")
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package comment
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // markdownTag wraps a tag in a markdown comment.
 func markdownTag(s string) string {
@@ -17,3 +20,31 @@ func addMarkdownTag(s string, tag string) string {
 
 	return comment
 }
+
+// outdatedCommentSummary is shown in place of comments that are hidden on
+// platforms that don't have a feature for hiding comments.
+const outdatedCommentSummary = "Outdated Infracost estimate, see the latest comment"
+
+// collapseComment hides a markdown comment by collapsing it into a details
+// block. The tag is kept in the body so the comment can still be matched.
+func collapseComment(s string) string {
+	return fmt.Sprintf("<details>\n<summary>%s</summary>\n\n%s\n</details>", outdatedCommentSummary, s)
+}
+
+// isCollapsedComment returns true if the comment has been hidden with
+// collapseComment.
+func isCollapsedComment(s string) bool {
+	return strings.HasPrefix(s, fmt.Sprintf("<details>\n<summary>%s</summary>", outdatedCommentSummary))
+}
+
+// markCommentOutdated hides a comment on platforms that don't support HTML in
+// markdown, e.g. Bitbucket, by marking it as outdated at the top.
+func markCommentOutdated(s string) string {
+	return fmt.Sprintf("**%s**\n\n%s", outdatedCommentSummary, s)
+}
+
+// isOutdatedComment returns true if the comment has been hidden with
+// markCommentOutdated.
+func isOutdatedComment(s string) bool {
+	return strings.HasPrefix(s, fmt.Sprintf("**%s**", outdatedCommentSummary))
+}
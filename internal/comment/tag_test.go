@@ -0,0 +1,30 @@
+package comment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollapseComment(t *testing.T) {
+	body := addMarkdownTag("Monthly cost will increase by $10", "infracost-comment")
+	collapsed := collapseComment(body)
+
+	assert.Equal(t, "<details>\n<summary>Outdated Infracost estimate, see the latest comment</summary>\n\n[//]: <> (infracost-comment)\nMonthly cost will increase by $10\n</details>", collapsed)
+	assert.Contains(t, collapsed, markdownTag("infracost-comment"))
+	assert.True(t, isCollapsedComment(collapsed))
+	assert.False(t, isCollapsedComment(body))
+	assert.True(t, (&gitlabComment{body: collapsed}).IsHidden())
+	assert.True(t, (&azureReposComment{content: collapsed}).IsHidden())
+}
+
+func TestMarkCommentOutdated(t *testing.T) {
+	body := "Monthly cost will increase by $10" + bitbucketMarkdownTag("infracost-comment")
+	outdated := markCommentOutdated(body)
+
+	assert.True(t, isOutdatedComment(outdated))
+	assert.False(t, isOutdatedComment(body))
+	assert.Contains(t, outdated, bitbucketMarkdownTag("infracost-comment"))
+	assert.True(t, (&bitbucketComment{body: outdated}).IsHidden())
+	assert.False(t, (&bitbucketComment{body: body}).IsHidden())
+}
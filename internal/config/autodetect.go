@@ -0,0 +1,325 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+)
+
+const (
+	// autodetectDirPlaceholder is replaced by the path of the detected project
+	// in the values of autodetect rules.
+	autodetectDirPlaceholder = "{dir}"
+	// autodetectEnvPlaceholder is replaced by the env of the detected project
+	// in the values of autodetect rules.
+	autodetectEnvPlaceholder = "{env}"
+)
+
+// Autodetect configures how the projects of a repo are detected from the
+// config file, so monorepos don't need to list every project. The Terraform
+// root modules under Path are detected, and a project is added for each of
+// them that's included and not excluded.
+type Autodetect struct {
+	// Path is the directory that projects are detected in. Defaults to the
+	// current directory.
+	Path string `yaml:"path,omitempty"`
+	// IncludePaths are glob patterns, relative to Path, of the directories
+	// that are used as projects. Defaults to all the root modules under Path.
+	IncludePaths []string `yaml:"include_paths,omitempty"`
+	// ExcludePaths are glob patterns, relative to Path, of the directories
+	// that aren't used as projects.
+	ExcludePaths []string `yaml:"exclude_paths,omitempty"`
+	// EnvNames are the names of the environments that the projects are run
+	// with. A project with a var file named after envs, e.g. prod.tfvars, is
+	// added once for each of them with that var file.
+	EnvNames []string `yaml:"env_names,omitempty"`
+	// Rules set the options of the detected projects that match them. The
+	// options of all the matching rules are used, in order.
+	Rules []AutodetectRule `yaml:"rules,omitempty"`
+}
+
+// AutodetectRule sets the options of the detected projects whose paths match
+// it. Values can use {dir} for the path of the project and {env} for its env,
+// e.g. usage_file: usage/{env}.yml.
+type AutodetectRule struct {
+	// Match is a glob pattern, relative to the autodetect path, of the
+	// directories the rule applies to.
+	Match string `yaml:"match"`
+	// UsageFile is the usage file of the projects.
+	UsageFile string `yaml:"usage_file,omitempty"`
+	// TerraformVarFiles are used after the var files of the env, relative to
+	// the project.
+	TerraformVarFiles []string `yaml:"terraform_var_files,omitempty"`
+	// TerraformVars are merged with the vars of previous rules.
+	TerraformVars map[string]string `yaml:"terraform_vars,omitempty"`
+	// TerraformWorkspace is the Terraform workspace of the projects.
+	TerraformWorkspace string `yaml:"terraform_workspace,omitempty"`
+	// Env are the environment variables of the projects, merged with the env
+	// of previous rules.
+	Env map[string]string `yaml:"env,omitempty"`
+	// DependencyPaths are paths, relative to the config file, that the projects
+	// depend on.
+	DependencyPaths []string `yaml:"dependency_paths,omitempty"`
+	// ConsumesProjects are the names or paths of other projects whose outputs
+	// the projects consume.
+	ConsumesProjects []string `yaml:"consumes_projects,omitempty"`
+}
+
+// BasePath returns the directory that projects are detected in.
+func (a *Autodetect) BasePath() string {
+	if a.Path == "" {
+		return "."
+	}
+
+	return a.Path
+}
+
+// Projects returns a project for each of the root module dirs that are
+// included by a, with the options of the rules that match them. Dirs that are
+// already the path of one of the existing projects are skipped, so projects
+// in the config file override detected ones.
+func (a *Autodetect) Projects(dirs []string, existing []*Project) []*Project {
+	seen := make(map[string]bool, len(existing))
+	for _, p := range existing {
+		seen[filepath.Clean(p.Path)] = true
+	}
+
+	sorted := append([]string{}, dirs...)
+	sort.Strings(sorted)
+
+	var projects []*Project
+	for _, dir := range sorted {
+		dir = filepath.Clean(dir)
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		rel, err := filepath.Rel(a.BasePath(), dir)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+
+		if !a.included(rel) {
+			continue
+		}
+
+		envs := a.envVarFiles(dir)
+		if len(envs) == 0 {
+			projects = append(projects, a.project(dir, rel, "", ""))
+			continue
+		}
+
+		for _, env := range a.EnvNames {
+			if varFile, ok := envs[env]; ok {
+				projects = append(projects, a.project(dir, rel, env, varFile))
+			}
+		}
+	}
+
+	return projects
+}
+
+func (a *Autodetect) included(rel string) bool {
+	for _, pattern := range a.ExcludePaths {
+		if matchPath(pattern, rel) {
+			return false
+		}
+	}
+
+	if len(a.IncludePaths) == 0 {
+		return true
+	}
+
+	for _, pattern := range a.IncludePaths {
+		if matchPath(pattern, rel) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// envVarFiles returns the var files in dir that are named after the envs.
+func (a *Autodetect) envVarFiles(dir string) map[string]string {
+	files := make(map[string]string)
+
+	for _, env := range a.EnvNames {
+		for _, name := range []string{env + ".tfvars", env + ".tfvars.json"} {
+			if FileExists(filepath.Join(dir, name)) {
+				files[env] = name
+				break
+			}
+		}
+	}
+
+	return files
+}
+
+func (a *Autodetect) project(dir, rel, env, varFile string) *Project {
+	p := &Project{Path: dir}
+	if env != "" {
+		p.Name = dir + "-" + env
+		p.TerraformVarFiles = []string{varFile}
+	}
+
+	// Values that use {env} only apply to the projects that have an env.
+	replace := func(s string) (string, bool) {
+		if env == "" && strings.Contains(s, autodetectEnvPlaceholder) {
+			return "", false
+		}
+
+		s = strings.ReplaceAll(s, autodetectDirPlaceholder, dir)
+		return strings.ReplaceAll(s, autodetectEnvPlaceholder, env), true
+	}
+
+	replaceAll := func(values []string) []string {
+		var out []string
+		for _, v := range values {
+			if r, ok := replace(v); ok {
+				out = append(out, r)
+			}
+		}
+
+		return out
+	}
+
+	mergeMap := func(dst map[string]string, src map[string]string) map[string]string {
+		for k, v := range src {
+			r, ok := replace(v)
+			if !ok {
+				continue
+			}
+
+			if dst == nil {
+				dst = make(map[string]string, len(src))
+			}
+			dst[k] = r
+		}
+
+		return dst
+	}
+
+	for _, rule := range a.Rules {
+		if !matchPath(rule.Match, rel) {
+			continue
+		}
+
+		if v, ok := replace(rule.UsageFile); ok && v != "" {
+			p.UsageFile = v
+		}
+
+		if v, ok := replace(rule.TerraformWorkspace); ok && v != "" {
+			p.TerraformWorkspace = v
+		}
+
+		p.TerraformVarFiles = append(p.TerraformVarFiles, replaceAll(rule.TerraformVarFiles)...)
+		p.TerraformVars = mergeMap(p.TerraformVars, rule.TerraformVars)
+		p.Env = mergeMap(p.Env, rule.Env)
+		p.DependencyPaths = append(p.DependencyPaths, replaceAll(rule.DependencyPaths)...)
+		p.ConsumesProjects = append(p.ConsumesProjects, replaceAll(rule.ConsumesProjects)...)
+	}
+
+	return p
+}
+
+// matchPath returns true if the slash separated path rel matches the glob
+// pattern. A pattern also matches the subdirectories of the paths it
+// matches, so e.g. envs/prod matches envs/prod/app.
+func matchPath(pattern, rel string) bool {
+	pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+
+	for _, p := range []string{pattern, pattern + "/**"} {
+		if ok, _ := doublestar.Match(p, rel); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateAutodetect returns the errors of the autodetect section of the
+// config file, which must only have valid keys and rules that have a valid
+// match pattern.
+func validateAutodetect(autodetect map[interface{}]interface{}) []error {
+	var errs []error
+
+	allowedKeys := yamlKeys(reflect.TypeOf(Autodetect{}))
+	for _, k := range sortedKeys(autodetect) {
+		if !allowedKeys[k] {
+			errs = append(errs, fmt.Errorf("%s is not a valid autodetect option", k))
+		}
+	}
+
+	for _, key := range []string{"include_paths", "exclude_paths"} {
+		patterns, _ := autodetect[key].([]interface{})
+		for _, p := range patterns {
+			if err := validatePattern(p); err != nil {
+				errs = append(errs, fmt.Errorf("%s has an invalid pattern: %w", key, err))
+			}
+		}
+	}
+
+	rules, ok := autodetect["rules"]
+	if !ok {
+		return errs
+	}
+
+	list, ok := rules.([]interface{})
+	if !ok {
+		return append(errs, fmt.Errorf("rules must be a list"))
+	}
+
+	allowedRuleKeys := yamlKeys(reflect.TypeOf(AutodetectRule{}))
+	for i, raw := range list {
+		fields, ok := raw.(map[interface{}]interface{})
+		if !ok {
+			errs = append(errs, fmt.Errorf("rule at index %d must be a map", i))
+			continue
+		}
+
+		for _, k := range sortedKeys(fields) {
+			if !allowedRuleKeys[k] {
+				errs = append(errs, fmt.Errorf("%s is not a valid autodetect rule option", k))
+			}
+		}
+
+		match, ok := fields["match"]
+		if !ok {
+			errs = append(errs, fmt.Errorf("rule at index %d must have a match pattern", i))
+			continue
+		}
+
+		if err := validatePattern(match); err != nil {
+			errs = append(errs, fmt.Errorf("rule at index %d has an invalid match pattern: %w", i, err))
+		}
+	}
+
+	return errs
+}
+
+func validatePattern(pattern interface{}) error {
+	s, ok := pattern.(string)
+	if !ok || s == "" {
+		return fmt.Errorf("pattern must be a string")
+	}
+
+	// Matching the pattern against itself makes doublestar parse all of it,
+	// so syntax errors are returned.
+	_, err := doublestar.Match(s, s)
+	return err
+}
+
+func yamlKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		keys[strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]] = true
+	}
+
+	return keys
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// budgetProviders maps the providers that can be used in budgets to the
+// prefix of their Terraform resource types.
+var budgetProviders = map[string]string{
+	"aws":     "aws",
+	"azure":   "azurerm",
+	"azurerm": "azurerm",
+	"gcp":     "google",
+	"google":  "google",
+}
+
+var budgetKeys = map[string]bool{
+	"project":               true,
+	"provider":              true,
+	"monthly_cost":          true,
+	"monthly_cost_increase": true,
+}
+
+// Budget is a limit on the estimated monthly cost, or on the increase of the
+// monthly cost in a diff, set in the budgets of the config file:
+//
+//	budgets:
+//	  - monthly_cost: 10000
+//	    monthly_cost_increase: 500
+//	  - project: my-org/my-repo/prod*
+//	    monthly_cost: 2000
+//	  - provider: aws
+//	    monthly_cost: 5000
+//
+// A budget applies to the projects whose name or path match the Project glob,
+// to the resources of the Provider, or to the total cost if neither is set.
+type Budget struct {
+	Project  string
+	Provider string
+	// MonthlyCost is the maximum estimated monthly cost, or nil if there's no
+	// limit.
+	MonthlyCost *decimal.Decimal
+	// MonthlyCostIncrease is the maximum increase of the monthly cost in a
+	// diff, or nil if there's no limit.
+	MonthlyCostIncrease *decimal.Decimal
+}
+
+// Label returns a description of what the budget applies to.
+func (b Budget) Label() string {
+	if b.Project != "" {
+		return "project " + b.Project
+	}
+
+	if b.Provider != "" {
+		return "provider " + b.Provider
+	}
+
+	return "total"
+}
+
+// parseBudgets parses the budgets of the config file.
+func parseBudgets(raw []map[string]interface{}) ([]Budget, error) {
+	budgets := make([]Budget, 0, len(raw))
+
+	for i, fields := range raw {
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if !budgetKeys[k] {
+				return nil, fmt.Errorf("budget %d is invalid, %s is not a valid budget option", i+1, k)
+			}
+		}
+
+		var b Budget
+		if v, ok := fields["project"]; ok && v != nil {
+			b.Project = fmt.Sprint(v)
+			if _, err := path.Match(b.Project, ""); err != nil {
+				return nil, fmt.Errorf("budget %d is invalid, project %s is not a valid glob pattern", i+1, b.Project)
+			}
+		}
+
+		if v, ok := fields["provider"]; ok && v != nil {
+			provider, ok := budgetProviders[strings.ToLower(fmt.Sprint(v))]
+			if !ok {
+				return nil, fmt.Errorf("budget %d is invalid, provider %s must be aws, azure or gcp", i+1, v)
+			}
+
+			b.Provider = provider
+		}
+
+		if b.Project != "" && b.Provider != "" {
+			return nil, fmt.Errorf("budget %d is invalid, it can have a project or a provider but not both", i+1)
+		}
+
+		var err error
+		b.MonthlyCost, err = parseBudgetCost(fields, "monthly_cost")
+		if err != nil {
+			return nil, fmt.Errorf("budget %d is invalid, %s", i+1, err)
+		}
+
+		b.MonthlyCostIncrease, err = parseBudgetCost(fields, "monthly_cost_increase")
+		if err != nil {
+			return nil, fmt.Errorf("budget %d is invalid, %s", i+1, err)
+		}
+
+		if b.MonthlyCost == nil && b.MonthlyCostIncrease == nil {
+			return nil, fmt.Errorf("budget %d is invalid, it must have a monthly_cost or monthly_cost_increase", i+1)
+		}
+
+		budgets = append(budgets, b)
+	}
+
+	return budgets, nil
+}
+
+func parseBudgetCost(fields map[string]interface{}, key string) (*decimal.Decimal, error) {
+	v, ok := fields[key]
+	if !ok || v == nil {
+		return nil, nil
+	}
+
+	d, err := decimal.NewFromString(strings.TrimSpace(fmt.Sprint(v)))
+	if err != nil || d.IsNegative() {
+		return nil, fmt.Errorf("%s %v is not a positive number", key, v)
+	}
+
+	return &d, nil
+}
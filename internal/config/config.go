@@ -1,11 +1,15 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
@@ -28,6 +32,16 @@ type Project struct {
 	Path string `yaml:"path,omitempty" ignored:"true"`
 	// ExcludePaths defines a list of directories that the provider should ignore.
 	ExcludePaths []string `yaml:"exclude_paths,omitempty" ignored:"true"`
+	// ConsumesProjects is a list of the names or paths of other projects whose outputs this project
+	// consumes, e.g. through terraform_remote_state data sources. With --show-shared-costs the cost of
+	// those projects is split between the projects that consume them.
+	ConsumesProjects []string `yaml:"consumes_projects,omitempty" ignored:"true"`
+	// DependsOn is a list of the names or paths of other projects that are run before this project.
+	DependsOn []string `yaml:"depends_on,omitempty" ignored:"true"`
+	// TerraformOutputVars sets input vars of the project to the outputs of other projects, given as
+	// <project name or path>.<output name>, e.g. hub_vnet_id: hub.vnet_id. The projects are run before
+	// this project. Only the outputs of Terraform directory projects can be used.
+	TerraformOutputVars map[string]string `yaml:"terraform_output_vars,omitempty" ignored:"true"`
 	// DependencyPaths is a list of any paths that this project depends on. These paths are relative to the
 	// config file and NOT the project.
 	DependencyPaths []string `yaml:"dependency_paths"`
@@ -55,10 +69,35 @@ type Project struct {
 	// TerraformCloudToken sets the Team API Token or User API Token so infracost can use it to access the plan.
 	// Only applicable for terraform cloud/enterprise users.
 	TerraformCloudToken string `yaml:"terraform_cloud_token,omitempty" envconfig:"TERRAFORM_CLOUD_TOKEN"`
+	// TerraformCloudWorkspace is the Terraform Cloud/Enterprise workspace, in the format
+	// <organization>/<workspace>, whose latest planned run is used instead of the path.
+	TerraformCloudWorkspace string `yaml:"terraform_cloud_workspace,omitempty" ignored:"true"`
+	// TerraformCloudRun is the ID of the Terraform Cloud/Enterprise run whose plan is used
+	// instead of the path. It takes precedence over TerraformCloudWorkspace.
+	TerraformCloudRun string `yaml:"terraform_cloud_run,omitempty" ignored:"true"`
 	// TerragruntFlags set additional flags that should be passed to terragrunt.
 	TerragruntFlags string `envconfig:"TERRAGRUNT_FLAGS"`
 	// UsageFile is the full path to usage file that specifies values for usage-based resources
 	UsageFile string `yaml:"usage_file,omitempty" ignored:"true"`
+	// UsageFileBefore is the full path to usage file that specifies values for usage-based resources
+	// in the current state. This is used by diffs so changes to the expected usage are included in the
+	// cost changes, and UsageFile is used for the planned state. When empty, UsageFile is used for
+	// both states. Resources that don't support separate usage for the current state use UsageFile
+	// for both states and are warned about.
+	UsageFileBefore string `yaml:"usage_file_before,omitempty" ignored:"true"`
+	// AzurePurchaseOption is the default purchase_option of the Azure resources that support
+	// reservations, savings plans or spot capacity, e.g. reserved_1_year. Resources can override
+	// it with the purchase_option key in the usage file.
+	AzurePurchaseOption string `yaml:"azure_purchase_option,omitempty" ignored:"true"`
+	// Ansible estimates the cost of the cloud resources created by the Ansible playbooks at Path.
+	// The playbooks are read rather than run, so the estimates are best-effort.
+	Ansible bool `yaml:"ansible,omitempty" ignored:"true"`
+	// Matrix is a list of Terraform workspace and var file combinations that the project is run
+	// with. The project is replaced by a project for each combination when the config file is loaded.
+	Matrix []ProjectVariant `yaml:"matrix,omitempty" ignored:"true"`
+	// Growth is the expected monthly growth of the cost and usage of the project, which is used to
+	// project its cumulative cost over the next 12 and 36 months.
+	Growth *ProjectGrowth `yaml:"growth,omitempty" ignored:"true"`
 	// TerraformUseState sets if the users wants to use the terraform state for infracost ops.
 	TerraformUseState bool              `yaml:"terraform_use_state,omitempty" ignored:"true"`
 	Env               map[string]string `yaml:"env,omitempty" ignored:"true"`
@@ -78,6 +117,7 @@ type Config struct {
 	APIKey                    string `envconfig:"API_KEY"`
 	PricingAPIEndpoint        string `yaml:"pricing_api_endpoint,omitempty" envconfig:"PRICING_API_ENDPOINT"`
 	DefaultPricingAPIEndpoint string `yaml:"default_pricing_api_endpoint,omitempty" envconfig:"DEFAULT_PRICING_API_ENDPOINT"`
+	PricingAPIBatchSize       int    `yaml:"pricing_api_batch_size,omitempty" envconfig:"PRICING_API_BATCH_SIZE"`
 	DashboardAPIEndpoint      string `yaml:"dashboard_api_endpoint,omitempty" envconfig:"DASHBOARD_API_ENDPOINT"`
 	DashboardEndpoint         string `yaml:"dashboard_endpoint,omitempty" envconfig:"DASHBOARD_ENDPOINT"`
 	UsageAPIEndpoint          string `yaml:"usage_api_endpoint,omitempty" envconfig:"USAGE_API_ENDPOINT"`
@@ -87,6 +127,10 @@ type Config struct {
 	EnableCloud               *bool  `yaml:"enable_cloud,omitempty" envconfig:"ENABLE_CLOUD"`
 	EnableCloudUpload         *bool  `yaml:"enable_cloud,omitempty" envconfig:"ENABLE_CLOUD_UPLOAD"`
 	DisableHCLParsing         bool   `yaml:"disable_hcl_parsing,omitempty" envconfig:"DISABLE_HCL_PARSING"`
+	// DisableTerragrunt fails Terragrunt projects instead of evaluating their
+	// config, since Terragrunt functions such as run_cmd run commands. It's set
+	// when estimating code that isn't trusted, e.g. pull requests from forks.
+	DisableTerragrunt bool `envconfig:"DISABLE_TERRAGRUNT"`
 
 	TLSInsecureSkipVerify *bool  `envconfig:"TLS_INSECURE_SKIP_VERIFY"`
 	TLSCACertFile         string `envconfig:"TLS_CA_CERT_FILE"`
@@ -94,6 +138,55 @@ type Config struct {
 	Currency       string `envconfig:"CURRENCY"`
 	CurrencyFormat string `envconfig:"CURRENCY_FORMAT"`
 
+	// IncludeFreeTier deducts the always-free monthly allowances of the cloud
+	// providers, e.g. the first 1M Lambda requests, from the costs.
+	IncludeFreeTier bool `yaml:"include_free_tier,omitempty" envconfig:"INCLUDE_FREE_TIER"`
+
+	// Discounts are the enterprise discounts on the list prices of cloud
+	// providers, set in the config file.
+	Discounts []Discount `yaml:"-" ignored:"true"`
+
+	// Budgets are the limits on the estimated cost and cost increase that fail
+	// runs when they're exceeded, set in the config file.
+	Budgets []Budget `yaml:"-" ignored:"true"`
+
+	// PriceOverridesFile is the path to a file of fixed unit prices that replace
+	// the prices from the pricing API, e.g. negotiated rates.
+	PriceOverridesFile string `yaml:"price_overrides_file,omitempty" envconfig:"PRICE_OVERRIDES_FILE"`
+
+	// ExchangeRatesSource is where the rates that convert USD prices to the
+	// currency come from, either ecb or the path to an exchange rates file. If
+	// it's empty the pricing API converts the prices.
+	ExchangeRatesSource string `yaml:"exchange_rates_source,omitempty" envconfig:"EXCHANGE_RATES_SOURCE"`
+
+	// Offline resolves all prices from the pricing snapshot file instead of the
+	// pricing API, so runs don't need network access.
+	Offline bool `envconfig:"OFFLINE"`
+	// PricingSnapshotFile is the file that `infracost pricing download` saves
+	// prices to and that offline runs look up prices from.
+	PricingSnapshotFile string `envconfig:"PRICING_SNAPSHOT_FILE"`
+
+	// PricingEndpoints is an ordered list of endpoints that prices are looked up
+	// from. If an endpoint fails or times out the lookups fail over to the next
+	// one. If it's empty prices are looked up from PricingAPIEndpoint.
+	PricingEndpoints []PricingEndpoint `envconfig:"PRICING_ENDPOINTS"`
+	// PricingEndpointTimeout is the timeout of each request to the
+	// PricingEndpoints that don't set their own.
+	PricingEndpointTimeout time.Duration `envconfig:"PRICING_ENDPOINT_TIMEOUT"`
+
+	// PriceSources maps vendor names (aws, azure, gcp) to the source that their prices
+	// are looked up from. Vendors that aren't set use the Cloud Pricing API.
+	PriceSources map[string]string `envconfig:"PRICE_SOURCES"`
+
+	// Plugins are the paths to the plugin executables that build the costs of
+	// resources of Terraform providers that aren't supported, see the plugins
+	// package.
+	Plugins []string `yaml:"plugins,omitempty" envconfig:"PLUGINS"`
+	// ResourceMappings is the directory or git URL of the YAML files that map
+	// resource types of unsupported Terraform providers to cost components,
+	// see plugins.MappingFile.
+	ResourceMappings string `yaml:"resource_mappings,omitempty" envconfig:"RESOURCE_MAPPINGS"`
+
 	AWSOverrideRegion    string `envconfig:"AWS_OVERRIDE_REGION"`
 	AzureOverrideRegion  string `envconfig:"AZURE_OVERRIDE_REGION"`
 	GoogleOverrideRegion string `envconfig:"GOOGLE_OVERRIDE_REGION"`
@@ -101,14 +194,87 @@ type Config struct {
 	// Org settings
 	EnableCloudForOrganization bool
 
-	Projects        []*Project `yaml:"projects" ignored:"true"`
-	Format          string     `yaml:"format,omitempty" ignored:"true"`
-	ShowAllProjects bool       `yaml:"show_all_projects,omitempty" ignored:"true"`
-	ShowSkipped     bool       `yaml:"show_skipped,omitempty" ignored:"true"`
-	SyncUsageFile   bool       `yaml:"sync_usage_file,omitempty" ignored:"true"`
-	Fields          []string   `yaml:"fields,omitempty" ignored:"true"`
-	CompareTo       string
-	GitDiffTarget   *string
+	Projects []*Project `yaml:"projects" ignored:"true"`
+	// Autodetect is set if the config file detects projects. The detected
+	// projects are added to Projects when the config file is loaded by a
+	// command.
+	Autodetect          *Autodetect `yaml:"autodetect,omitempty" ignored:"true"`
+	Format              string      `yaml:"format,omitempty" ignored:"true"`
+	ShowAllProjects     bool        `yaml:"show_all_projects,omitempty" ignored:"true"`
+	ShowSkipped         bool        `yaml:"show_skipped,omitempty" ignored:"true"`
+	ShowResourceSummary bool        `yaml:"show_resource_summary,omitempty" ignored:"true"`
+	ShowSharedCosts     bool        `yaml:"show_shared_costs,omitempty" ignored:"true"`
+	GroupByScope        bool        `yaml:"group_by_scope,omitempty" ignored:"true"`
+	GroupBy             string      `yaml:"group_by,omitempty" ignored:"true"`
+	// OwnershipTag is the tag that diffs group resources by to report cost that
+	// moves from one owner to another, e.g. team.
+	OwnershipTag            string   `yaml:"ownership_tag,omitempty" ignored:"true"`
+	OwnershipDriftThreshold float64  `yaml:"ownership_drift_threshold,omitempty" ignored:"true"`
+	SyncUsageFile           bool     `yaml:"sync_usage_file,omitempty" ignored:"true"`
+	Fields                  []string `yaml:"fields,omitempty" ignored:"true"`
+	CompareTo               string
+	GitDiffTarget           *string
+
+	// MinPricingCoverage fails runs where less than this percentage of the
+	// resources or cost components are priced.
+	MinPricingCoverage float64 `yaml:"min_pricing_coverage,omitempty" ignored:"true"`
+	// MaxUntaggedCostPercent fails runs where more than this percentage of the
+	// monthly cost isn't tagged with the tag that GroupBy groups by.
+	MaxUntaggedCostPercent float64 `yaml:"max_untagged_cost_percent,omitempty" ignored:"true"`
+	// TagPolicyPath is the path to a tag policy file that the tags of the
+	// resources are checked against.
+	TagPolicyPath string `yaml:"tag_policy_path,omitempty" ignored:"true"`
+	// FailOnTagPolicy fails runs where any resource doesn't follow the tag
+	// policy.
+	FailOnTagPolicy bool `yaml:"fail_on_tag_policy,omitempty" ignored:"true"`
+	// HistoryPath is the local path or S3 URL of the cost history that the
+	// costs of breakdown runs are compared with to find anomalies.
+	HistoryPath string `yaml:"history_path,omitempty" ignored:"true"`
+	// HistorySize is the number of runs that are kept in the cost history.
+	HistorySize int `yaml:"history_size,omitempty" ignored:"true"`
+	// AnomalyStdDevs is the number of standard deviations from the mean of
+	// the cost history that a resource cost must change to be an anomaly.
+	AnomalyStdDevs float64 `yaml:"anomaly_std_devs,omitempty" ignored:"true"`
+	// AnomalyPercent is the percentage that a resource cost must change since
+	// the previous run to be an anomaly.
+	AnomalyPercent float64 `yaml:"anomaly_percent,omitempty" ignored:"true"`
+	// ShowEmissions estimates the carbon footprint of the compute and storage
+	// resources.
+	ShowEmissions bool `yaml:"show_emissions,omitempty" ignored:"true"`
+	// ShowQuickWins lists the quick wins, e.g. gp3 instead of gp2 volumes, in
+	// the table output.
+	ShowQuickWins bool `yaml:"show_quick_wins,omitempty" ignored:"true"`
+	// ShowGranularity are the periods of time, e.g. hourly or annual, that
+	// the table output shows costs for.
+	ShowGranularity []string `yaml:"show_granularity,omitempty" ignored:"true"`
+	// PolicyPaths are the paths to Rego policies that the Infracost JSON of the
+	// run is evaluated against.
+	PolicyPaths []string `yaml:"policy_paths,omitempty" ignored:"true"`
+	// IgnoresFile is the path to the file of policy and budget failures that
+	// don't fail the run, see output.IgnoresFile.
+	IgnoresFile string `yaml:"ignores_file,omitempty" ignored:"true"`
+	// Filters are the --filter expressions that resources must match to be
+	// included in the output.
+	Filters []string `yaml:"filters,omitempty" ignored:"true"`
+	// StrictPricing fails runs where the price of any cost component couldn't
+	// be resolved to a single price.
+	StrictPricing bool `yaml:"strict_pricing,omitempty" ignored:"true"`
+	// ShowMissingUsage lists the resources with costs that depend on usage
+	// that isn't set, along with the usage keys to set.
+	ShowMissingUsage bool `yaml:"show_missing_usage,omitempty" ignored:"true"`
+	// ShowUnknownInputs lists the resources with costs that depend on values
+	// that are unknown until apply, along with the attributes.
+	ShowUnknownInputs bool `yaml:"show_unknown_inputs,omitempty" ignored:"true"`
+	// CostIncreaseThreshold is the monthly cost increase that resources must
+	// exceed to be reported as warnings in the SARIF output format.
+	CostIncreaseThreshold float64 `yaml:"cost_increase_threshold,omitempty" ignored:"true"`
+	// TerraformRemoteState evaluates the outputs of terraform_remote_state data
+	// sources by reading the state from their s3, azurerm or gcs backend with
+	// the credentials of the environment.
+	TerraformRemoteState bool `yaml:"terraform_remote_state,omitempty" ignored:"true"`
+	// RollUpInstances combines the instances of resources that use count or
+	// for_each into one resource in the output, set with --expand-foreach=false.
+	RollUpInstances bool `yaml:"roll_up_instances,omitempty" ignored:"true"`
 
 	// Base configuration settings
 	// RootPath defines the raw value of the `--path` flag provided by the user
@@ -117,6 +283,42 @@ type Config struct {
 	ConfigFilePath string
 
 	NoCache bool `yaml:"fields,omitempty" ignored:"true"`
+	// DebugPricing prints how the price of each cost component was resolved:
+	// the filters that were looked up, the products that were found and why a
+	// price was used or not.
+	DebugPricing bool `yaml:"debug_pricing,omitempty" ignored:"true"`
+	// TimingReport prints how long each phase of the run took and how much
+	// memory it allocated, in total and for each project.
+	TimingReport bool `yaml:"timing_report,omitempty" ignored:"true"`
+
+	// ReportCacheDir is the directory that finished reports are saved in so that
+	// they can be re-displayed with `infracost show`. Reports aren't saved when empty.
+	ReportCacheDir string `envconfig:"REPORT_CACHE_DIR"`
+
+	// PriceCacheDir is the directory that prices are cached in so repeated runs
+	// don't look up the same prices again. Prices aren't cached when empty or
+	// when NoCache is set. Cached prices expire after PriceCacheTTL.
+	PriceCacheDir string        `envconfig:"PRICE_CACHE_DIR"`
+	PriceCacheTTL time.Duration `envconfig:"PRICE_CACHE_TTL"`
+
+	// ResourceCacheDir is the directory that the built and priced resources of
+	// each project are cached in, so diff runs only build and price the
+	// resources that changed since the previous run. Resources aren't cached
+	// when empty, when NoCache is set or when prices aren't cached, and they
+	// expire after PriceCacheTTL like the prices they were built with.
+	ResourceCacheDir string `envconfig:"RESOURCE_CACHE_DIR"`
+
+	// HCLCacheDir is the directory that the Terraform projects evaluated from
+	// HCL are cached in, keyed by the contents of their modules, so projects
+	// that haven't changed aren't parsed again. Projects aren't cached when
+	// empty or when NoCache is set.
+	HCLCacheDir string `envconfig:"HCL_CACHE_DIR"`
+
+	// ModuleCacheDir is the directory that registry modules are downloaded to so
+	// they are shared by all the projects and runs that use the same version of
+	// a module. Modules are only downloaded to the project when empty or when
+	// NoCache is set.
+	ModuleCacheDir string `envconfig:"MODULE_CACHE_DIR"`
 
 	SkipErrLine bool
 
@@ -150,6 +352,17 @@ func DefaultConfig() *Config {
 		Format: "table",
 		Fields: []string{"monthlyQuantity", "unit", "monthlyCost"},
 
+		ReportCacheDir:   defaultReportCacheDir(),
+		PriceCacheDir:    defaultPriceCacheDir(),
+		PriceCacheTTL:    defaultPriceCacheTTL,
+		ModuleCacheDir:   defaultModuleCacheDir(),
+		ResourceCacheDir: defaultResourceCacheDir(),
+		HCLCacheDir:      defaultHCLCacheDir(),
+
+		PricingEndpointTimeout: defaultPricingEndpointTimeout,
+
+		PricingSnapshotFile: defaultPricingSnapshotFile(),
+
 		EventsDisabled: IsTest(),
 	}
 }
@@ -163,13 +376,50 @@ func (c *Config) RepoPath() string {
 	return c.RootPath
 }
 
+// ReportCacheHash returns a hash of the config that the estimate depends on.
+// Together with the repo and commit this is used as the key of cached reports.
+func (c *Config) ReportCacheHash() (string, error) {
+	// Hash the contents of the price overrides file so that changed prices
+	// aren't shown from the cache.
+	var priceOverrides string
+	if c.PriceOverridesFile != "" {
+		contents, err := os.ReadFile(c.PriceOverridesFile)
+		if err != nil {
+			return "", err
+		}
+
+		sum := sha256.Sum256(contents)
+		priceOverrides = hex.EncodeToString(sum[:])
+	}
+
+	b, err := json.Marshal(struct {
+		Projects        []*Project `json:"projects"`
+		ConfigFilePath  string     `json:"configFilePath"`
+		Currency        string     `json:"currency"`
+		IncludeFreeTier bool       `json:"includeFreeTier"`
+		PriceOverrides  string     `json:"priceOverrides,omitempty"`
+		Discounts       []Discount `json:"discounts,omitempty"`
+		ExchangeRates   string     `json:"exchangeRates,omitempty"`
+		Offline         bool       `json:"offline,omitempty"`
+	}{c.Projects, c.ConfigFilePath, c.Currency, c.IncludeFreeTier, priceOverrides, c.Discounts, c.ExchangeRatesSource, c.Offline})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 func (c *Config) LoadFromConfigFile(path string) error {
 	cfgFile, err := loadConfigFile(path)
 	if err != nil {
 		return err
 	}
 
-	c.Projects = cfgFile.Projects
+	c.Projects = expandMatrix(cfgFile.Projects)
+	c.Autodetect = cfgFile.Autodetect
+	c.Discounts = cfgFile.parsedDiscounts
+	c.Budgets = cfgFile.parsedBudgets
 
 	// Reload the environment to overwrite any of the config file configs
 	err = c.LoadFromEnv()
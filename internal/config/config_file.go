@@ -14,7 +14,11 @@ import (
 
 const (
 	minConfigFileVersion = "0.1"
-	maxConfigFileVersion = "0.1"
+	maxConfigFileVersion = "0.2"
+
+	// autodetectConfigFileVersion is the first version of the config file
+	// that supports autodetect.
+	autodetectConfigFileVersion = "0.2"
 )
 
 var ErrorInvalidConfigFile = errors.New("parsing config file failed check file syntax")
@@ -106,8 +110,14 @@ func (y *YamlError) Error() string {
 }
 
 type fileSpec struct {
-	Version  string     `yaml:"version"`
-	Projects []*Project `yaml:"projects" ignored:"true"`
+	Version    string                   `yaml:"version"`
+	Projects   []*Project               `yaml:"projects" ignored:"true"`
+	Autodetect *Autodetect              `yaml:"autodetect,omitempty" ignored:"true"`
+	Discounts  map[string]string        `yaml:"discounts,omitempty"`
+	Budgets    []map[string]interface{} `yaml:"budgets,omitempty"`
+
+	parsedDiscounts []Discount
+	parsedBudgets   []Budget
 }
 
 // UnmarshalYAML implements the yaml.v2.Unmarshaller interface. Marshalls the
@@ -116,8 +126,11 @@ type fileSpec struct {
 // type so that we don't run into error collisions with the base yaml.v2 errors.
 func (f *fileSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	type roughFile struct {
-		Version  string                   `yaml:"version"`
-		Projects []map[string]interface{} `yaml:"projects"`
+		Version    string                      `yaml:"version"`
+		Projects   []map[string]interface{}    `yaml:"projects"`
+		Autodetect map[interface{}]interface{} `yaml:"autodetect"`
+		Discounts  map[string]string           `yaml:"discounts"`
+		Budgets    []map[string]interface{}    `yaml:"budgets"`
 	}
 
 	var r roughFile
@@ -168,11 +181,44 @@ func (f *fileSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			projectError.add(fmt.Errorf("%s is not a valid project configuration option", k))
 		}
 
+		if matrix, ok := fields["matrix"]; ok {
+			for _, err := range validateMatrix(matrix) {
+				projectError.add(err)
+			}
+		}
+
+		if growth, ok := fields["growth"]; ok {
+			for _, err := range validateGrowth(growth) {
+				projectError.add(err)
+			}
+		}
+
 		if projectError.isValid() {
 			validationError.add(projectError)
 		}
 	}
 
+	if r.Autodetect != nil {
+		autodetectError := &YamlError{base: "autodetect config is invalid"}
+		for _, err := range validateAutodetect(r.Autodetect) {
+			autodetectError.add(err)
+		}
+
+		if autodetectError.isValid() {
+			validationError.add(autodetectError)
+		}
+	}
+
+	discounts, err := parseDiscounts(r.Discounts)
+	if err != nil {
+		validationError.add(err)
+	}
+
+	budgets, err := parseBudgets(r.Budgets)
+	if err != nil {
+		validationError.add(err)
+	}
+
 	if validationError.isValid() {
 		return validationError
 	}
@@ -187,6 +233,15 @@ func (f *fileSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
+	if r.Autodetect != nil && !versionAtLeast(r.Version, autodetectConfigFileVersion) {
+		return &YamlError{
+			base: "config file is invalid, see https://infracost.io/config-file for file specification",
+			errors: []error{
+				fmt.Errorf("autodetect requires version %s or later, version is '%s'", autodetectConfigFileVersion, r.Version),
+			},
+		}
+	}
+
 	type fileSpecClone fileSpec
 	var c fileSpecClone
 	err = unmarshal(&c)
@@ -196,6 +251,11 @@ func (f *fileSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 	f.Version = c.Version
 	f.Projects = c.Projects
+	f.Autodetect = c.Autodetect
+	f.Discounts = c.Discounts
+	f.Budgets = c.Budgets
+	f.parsedDiscounts = discounts
+	f.parsedBudgets = budgets
 	return nil
 }
 
@@ -232,8 +292,18 @@ func loadConfigFile(path string) (fileSpec, error) {
 }
 
 func checkVersion(v string) bool {
+	return versionAtLeast(v, minConfigFileVersion) && semver.Compare(semverVersion(v), semverVersion(maxConfigFileVersion)) <= 0
+}
+
+// versionAtLeast returns true if the config file version v is min or later.
+func versionAtLeast(v, min string) bool {
+	return semver.Compare(semverVersion(v), semverVersion(min)) >= 0
+}
+
+func semverVersion(v string) string {
 	if !strings.HasPrefix(v, "v") {
 		v = "v" + v
 	}
-	return semver.Compare(v, "v"+minConfigFileVersion) >= 0 && semver.Compare(v, "v"+maxConfigFileVersion) <= 0
+
+	return v
 }
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 )
 
@@ -86,6 +87,77 @@ projects:
 				},
 			},
 		},
+		{
+			name: "should expand project matrix",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+    terraform_var_files: [common.tfvars]
+    terraform_vars:
+      region: us-east-1
+    matrix:
+      - terraform_workspace: staging
+        terraform_var_files: [staging.tfvars]
+      - name: production
+        terraform_workspace: prod
+        terraform_var_files: [prod.tfvars]
+        terraform_vars:
+          region: eu-west-1
+  - path: path/to/my_terraform_two
+    name: app
+    matrix:
+      - terraform_var_files: [envs/dev.tfvars]
+`),
+			expected: []*Project{
+				{
+					Path:               "path/to/my_terraform",
+					Name:               "path/to/my_terraform-staging",
+					TerraformWorkspace: "staging",
+					TerraformVarFiles:  []string{"common.tfvars", "staging.tfvars"},
+					TerraformVars:      map[string]string{"region": "us-east-1"},
+				},
+				{
+					Path:               "path/to/my_terraform",
+					Name:               "path/to/my_terraform-production",
+					TerraformWorkspace: "prod",
+					TerraformVarFiles:  []string{"common.tfvars", "prod.tfvars"},
+					TerraformVars:      map[string]string{"region": "eu-west-1"},
+				},
+				{
+					Path:              "path/to/my_terraform_two",
+					Name:              "app-dev",
+					TerraformVarFiles: []string{"envs/dev.tfvars"},
+				},
+			},
+		},
+		{
+			name: "should error invalid matrix given",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+    matrix:
+      - terraform_workspace: staging
+        usage_file: usage.yml
+      - name: staging
+      - terraform_vars:
+          region: eu-west-1
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for valid options",
+				errors: []error{
+					&YamlError{
+						base: "project config defined for path: [path/to/my_terraform] is invalid",
+						errors: []error{
+							errors.New("usage_file is not a valid matrix option"),
+							errors.New("matrix entry at index 1 has the same name as another entry: staging"),
+							errors.New("matrix entry at index 2 must have a name, terraform_workspace or terraform_var_files"),
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "should error invalid version given",
 			contents: []byte(`version: 81923.1
@@ -96,7 +168,7 @@ projects:
 			error: &YamlError{
 				base: "config file is invalid, see https://infracost.io/config-file for file specification",
 				errors: []error{
-					errors.New("version '81923.1' is not supported, valid versions are 0.1 ≤ x ≤ 0.1"),
+					errors.New("version '81923.1' is not supported, valid versions are 0.1 ≤ x ≤ 0.2"),
 				},
 			},
 		},
@@ -129,3 +201,506 @@ projects:
 		})
 	}
 }
+
+func TestConfigLoadFromConfigFileDiscounts(t *testing.T) {
+	tmp := t.TempDir()
+	tests := []struct {
+		name     string
+		contents []byte
+		expected []Discount
+		error    error
+	}{
+		{
+			name: "should parse vendor and service discounts",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+
+discounts:
+  aws: 12%
+  google: 7.5
+  azure: 5%
+  azure compute: 20%
+`),
+			expected: []Discount{
+				{Vendor: "aws", Percent: decimal.NewFromInt(12)},
+				{Vendor: "azure", Service: "compute", Percent: decimal.NewFromInt(20)},
+				{Vendor: "azure", Percent: decimal.NewFromInt(5)},
+				{Vendor: "gcp", Percent: decimal.RequireFromString("7.5")},
+			},
+		},
+		{
+			name: "should error invalid vendor given",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+
+discounts:
+  oracle: 12%
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for valid options",
+				errors: []error{
+					errors.New("discount oracle is invalid, it must start with aws, azure or gcp"),
+				},
+			},
+		},
+		{
+			name: "should error invalid percentage given",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+
+discounts:
+  aws: 120%
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for valid options",
+				errors: []error{
+					errors.New("discount aws is invalid, 120% is not a percentage between 0% and 100%"),
+				},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{}
+			path := filepath.Join(tmp, fmt.Sprintf("conf-%d.yaml", i))
+			err := os.WriteFile(path, tt.contents, os.ModePerm)
+			require.NoError(t, err)
+
+			err = c.LoadFromConfigFile(path)
+
+			require.Equal(t, tt.error, err)
+			require.Len(t, c.Discounts, len(tt.expected))
+			for j, d := range tt.expected {
+				require.Equal(t, d.Vendor, c.Discounts[j].Vendor)
+				require.Equal(t, d.Service, c.Discounts[j].Service)
+				require.True(t, d.Percent.Equal(c.Discounts[j].Percent), "expected %s, got %s", d.Percent, c.Discounts[j].Percent)
+			}
+		})
+	}
+}
+
+func TestConfigLoadFromConfigFileBudgets(t *testing.T) {
+	tmp := t.TempDir()
+	decimalPtr := func(d decimal.Decimal) *decimal.Decimal { return &d }
+
+	tests := []struct {
+		name     string
+		contents []byte
+		expected []Budget
+		error    error
+	}{
+		{
+			name: "should parse total, project and provider budgets",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+
+budgets:
+  - monthly_cost: 10000
+    monthly_cost_increase: 500.50
+  - project: my-org/my-repo/prod*
+    monthly_cost: 2000
+  - provider: gcp
+    monthly_cost_increase: 100
+`),
+			expected: []Budget{
+				{MonthlyCost: decimalPtr(decimal.NewFromInt(10000)), MonthlyCostIncrease: decimalPtr(decimal.RequireFromString("500.5"))},
+				{Project: "my-org/my-repo/prod*", MonthlyCost: decimalPtr(decimal.NewFromInt(2000))},
+				{Provider: "google", MonthlyCostIncrease: decimalPtr(decimal.NewFromInt(100))},
+			},
+		},
+		{
+			name: "should error unknown budget option",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+
+budgets:
+  - monthly_cost: 100
+    limit: 5
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for valid options",
+				errors: []error{
+					errors.New("budget 1 is invalid, limit is not a valid budget option"),
+				},
+			},
+		},
+		{
+			name: "should error budget without a limit",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+
+budgets:
+  - provider: aws
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for valid options",
+				errors: []error{
+					errors.New("budget 1 is invalid, it must have a monthly_cost or monthly_cost_increase"),
+				},
+			},
+		},
+		{
+			name: "should error invalid cost",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+
+budgets:
+  - monthly_cost: 100 USD
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for valid options",
+				errors: []error{
+					errors.New("budget 1 is invalid, monthly_cost 100 USD is not a positive number"),
+				},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{}
+			path := filepath.Join(tmp, fmt.Sprintf("conf-%d.yaml", i))
+			err := os.WriteFile(path, tt.contents, os.ModePerm)
+			require.NoError(t, err)
+
+			err = c.LoadFromConfigFile(path)
+
+			require.Equal(t, tt.error, err)
+			require.Len(t, c.Budgets, len(tt.expected))
+			for j, b := range tt.expected {
+				require.Equal(t, b.Project, c.Budgets[j].Project)
+				require.Equal(t, b.Provider, c.Budgets[j].Provider)
+				require.Equal(t, b.MonthlyCost == nil, c.Budgets[j].MonthlyCost == nil)
+				if b.MonthlyCost != nil {
+					require.True(t, b.MonthlyCost.Equal(*c.Budgets[j].MonthlyCost), "expected %s, got %s", b.MonthlyCost, c.Budgets[j].MonthlyCost)
+				}
+				require.Equal(t, b.MonthlyCostIncrease == nil, c.Budgets[j].MonthlyCostIncrease == nil)
+				if b.MonthlyCostIncrease != nil {
+					require.True(t, b.MonthlyCostIncrease.Equal(*c.Budgets[j].MonthlyCostIncrease), "expected %s, got %s", b.MonthlyCostIncrease, c.Budgets[j].MonthlyCostIncrease)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigLoadFromConfigFileGrowth(t *testing.T) {
+	tmp := t.TempDir()
+
+	tests := []struct {
+		name     string
+		contents []byte
+		expected *ProjectGrowth
+		error    error
+	}{
+		{
+			name: "should parse project and usage growth rates",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+    growth:
+      monthly_rate: 2
+      usage_monthly_rates:
+        monthly_requests: 10.5
+`),
+			expected: &ProjectGrowth{MonthlyRate: 2, UsageMonthlyRates: map[string]float64{"monthly_requests": 10.5}},
+		},
+		{
+			name: "should error invalid growth",
+			contents: []byte(`version: 0.1
+
+projects:
+  - path: path/to/my_terraform
+    growth:
+      monthly_rate: -100
+      rate: 5
+      usage_monthly_rates:
+        monthly_requests: lots
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for valid options",
+				errors: []error{
+					&YamlError{
+						base: "project config defined for path: [path/to/my_terraform] is invalid",
+						errors: []error{
+							errors.New("rate is not a valid growth option"),
+							errors.New("growth monthly_rate -100 must be greater than -100"),
+							errors.New("growth rate of usage key monthly_requests lots is not a number"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{}
+			path := filepath.Join(tmp, fmt.Sprintf("conf-%d.yaml", i))
+			err := os.WriteFile(path, tt.contents, os.ModePerm)
+			require.NoError(t, err)
+
+			err = c.LoadFromConfigFile(path)
+
+			require.Equal(t, tt.error, err)
+			if tt.expected != nil {
+				require.Len(t, c.Projects, 1)
+				require.Equal(t, tt.expected, c.Projects[0].Growth)
+			}
+		})
+	}
+}
+
+func TestConfigLoadFromConfigFileAutodetect(t *testing.T) {
+	tmp := t.TempDir()
+
+	tests := []struct {
+		name     string
+		contents []byte
+		expected *Autodetect
+		error    error
+	}{
+		{
+			name: "should parse autodetect",
+			contents: []byte(`version: 0.2
+
+autodetect:
+  path: infra
+  include_paths:
+    - envs/**
+  exclude_paths:
+    - "**/examples"
+  env_names: [dev, prod]
+  rules:
+    - match: envs/*/app
+      usage_file: usage/{env}.yml
+      terraform_var_files: [common.tfvars]
+      dependency_paths: [modules/app]
+`),
+			expected: &Autodetect{
+				Path:         "infra",
+				IncludePaths: []string{"envs/**"},
+				ExcludePaths: []string{"**/examples"},
+				EnvNames:     []string{"dev", "prod"},
+				Rules: []AutodetectRule{
+					{
+						Match:             "envs/*/app",
+						UsageFile:         "usage/{env}.yml",
+						TerraformVarFiles: []string{"common.tfvars"},
+						DependencyPaths:   []string{"modules/app"},
+					},
+				},
+			},
+		},
+		{
+			name: "should error invalid autodetect",
+			contents: []byte(`version: 0.2
+
+autodetect:
+  include: [envs]
+  exclude_paths: ["envs/[prod"]
+  rules:
+    - usage_file: usage.yml
+    - match: envs
+      name: app
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for valid options",
+				errors: []error{
+					&YamlError{
+						base: "autodetect config is invalid",
+						errors: []error{
+							errors.New("include is not a valid autodetect option"),
+							fmt.Errorf("exclude_paths has an invalid pattern: %w", errors.New("syntax error in pattern")),
+							errors.New("rule at index 0 must have a match pattern"),
+							errors.New("name is not a valid autodetect rule option"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "should error autodetect with version 0.1",
+			contents: []byte(`version: 0.1
+
+autodetect:
+  path: infra
+`),
+			error: &YamlError{
+				base: "config file is invalid, see https://infracost.io/config-file for file specification",
+				errors: []error{
+					errors.New("autodetect requires version 0.2 or later, version is '0.1'"),
+				},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{}
+			path := filepath.Join(tmp, fmt.Sprintf("conf-%d.yaml", i))
+			err := os.WriteFile(path, tt.contents, os.ModePerm)
+			require.NoError(t, err)
+
+			err = c.LoadFromConfigFile(path)
+
+			require.Equal(t, tt.error, err)
+			require.Equal(t, tt.expected, c.Autodetect)
+		})
+	}
+}
+
+func TestAutodetectProjects(t *testing.T) {
+	base := t.TempDir()
+	dir := func(rel string) string {
+		return filepath.Join(base, rel)
+	}
+
+	for _, f := range []string{"envs/app/dev.tfvars", "envs/app/prod.tfvars.json", "envs/db/main.tf", "examples/app/main.tf"} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(dir(f)), 0700))
+		require.NoError(t, os.WriteFile(dir(f), nil, 0600))
+	}
+
+	a := &Autodetect{
+		Path:         base,
+		ExcludePaths: []string{"examples"},
+		EnvNames:     []string{"dev", "staging", "prod"},
+		Rules: []AutodetectRule{
+			{
+				Match:         "envs/**",
+				UsageFile:     "usage/{env}.yml",
+				TerraformVars: map[string]string{"env": "{env}", "region": "us-east-1"},
+			},
+			{
+				Match:             "envs/app",
+				TerraformVarFiles: []string{"common.tfvars"},
+				DependencyPaths:   []string{"{dir}/../../modules/app"},
+				ConsumesProjects:  []string{dir("envs/db")},
+			},
+		},
+	}
+
+	existing := []*Project{{Path: dir("envs/legacy")}}
+	projects := a.Projects([]string{dir("examples/app"), dir("envs/db"), dir("envs/app"), dir("envs/legacy")}, existing)
+
+	require.Equal(t, []*Project{
+		{
+			Path:              dir("envs/app"),
+			Name:              dir("envs/app") + "-dev",
+			UsageFile:         "usage/dev.yml",
+			TerraformVarFiles: []string{"dev.tfvars", "common.tfvars"},
+			TerraformVars:     map[string]string{"env": "dev", "region": "us-east-1"},
+			DependencyPaths:   []string{dir("envs/app") + "/../../modules/app"},
+			ConsumesProjects:  []string{dir("envs/db")},
+		},
+		{
+			Path:              dir("envs/app"),
+			Name:              dir("envs/app") + "-prod",
+			UsageFile:         "usage/prod.yml",
+			TerraformVarFiles: []string{"prod.tfvars.json", "common.tfvars"},
+			TerraformVars:     map[string]string{"env": "prod", "region": "us-east-1"},
+			DependencyPaths:   []string{dir("envs/app") + "/../../modules/app"},
+			ConsumesProjects:  []string{dir("envs/db")},
+		},
+		{
+			// Values that use {env} don't apply to projects without an env.
+			Path:          dir("envs/db"),
+			TerraformVars: map[string]string{"region": "us-east-1"},
+		},
+	}, projects)
+
+	a.IncludePaths = []string{"envs/d*"}
+	projects = a.Projects([]string{dir("envs/db"), dir("envs/app")}, nil)
+	require.Len(t, projects, 1)
+	require.Equal(t, dir("envs/db"), projects[0].Path)
+}
+
+func TestConfigProjectLevels(t *testing.T) {
+	hub := &Project{Path: "infra/hub", Name: "hub"}
+	dns := &Project{Path: "infra/dns"}
+	spoke := &Project{
+		Path:                "infra/spoke",
+		TerraformOutputVars: map[string]string{"hub_vnet_id": "hub.vnet_id"},
+	}
+	app := &Project{Path: "apps/web", DependsOn: []string{"infra/spoke", "./infra/dns"}}
+
+	c := Config{Projects: []*Project{app, spoke, hub, dns}}
+
+	levels, err := c.ProjectLevels()
+	require.NoError(t, err)
+	require.Equal(t, [][]int{{2, 3}, {1}, {0}}, levels)
+
+	deps, err := c.ProjectDependencies(app)
+	require.NoError(t, err)
+	require.Equal(t, []*Project{spoke, dns}, deps)
+
+	ref, err := ParseOutputRef("../shared.v2.vnet_id")
+	require.NoError(t, err)
+	require.Equal(t, OutputRef{Project: "../shared.v2", Output: "vnet_id"}, ref)
+}
+
+func TestConfigProjectLevelsInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		projects []*Project
+		error    string
+	}{
+		{
+			name: "should error cycle",
+			projects: []*Project{
+				{Path: "a", DependsOn: []string{"b"}},
+				{Path: "b", DependsOn: []string{"a"}},
+				{Path: "c"},
+			},
+			error: "the dependencies of projects a, b have a cycle",
+		},
+		{
+			name:     "should error missing project",
+			projects: []*Project{{Path: "a", DependsOn: []string{"b"}}},
+			error:    "dependency of project a is invalid: project b does not exist",
+		},
+		{
+			name: "should error ambiguous path",
+			projects: []*Project{
+				{Path: "a", Name: "a-dev"},
+				{Path: "a", Name: "a-prod"},
+				{Path: "b", DependsOn: []string{"a"}},
+			},
+			error: "dependency of project b is invalid: project a is ambiguous as more than one project has that path, use the name of the project instead",
+		},
+		{
+			name:     "should error invalid output",
+			projects: []*Project{{Path: "a", TerraformOutputVars: map[string]string{"id": "hub"}}},
+			error:    `terraform_output_vars of project a is invalid: output "hub" must be given as <project name or path>.<output name>`,
+		},
+		{
+			name:     "should error self dependency",
+			projects: []*Project{{Path: "a", Name: "app", DependsOn: []string{"app"}}},
+			error:    "project app can't depend on itself",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{Projects: tt.projects}
+
+			_, err := c.ProjectLevels()
+			require.EqualError(t, err, tt.error)
+		})
+	}
+}
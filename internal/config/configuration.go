@@ -21,6 +21,17 @@ type Configuration struct {
 	TLSCACertFile         string `yaml:"tls_ca_cert_file,omitempty"`
 	EnableCloud           *bool  `yaml:"enable_cloud"`
 	EnableCloudUpload     *bool  `yaml:"enable_cloud_upload"`
+	IncludeFreeTier       *bool  `yaml:"include_free_tier,omitempty"`
+
+	// The comment thresholds hide small cost changes and highlight large cost
+	// increases in comments, unless they're overridden by flags.
+	CommentMinCostChange        *float64 `yaml:"comment_min_cost_change,omitempty"`
+	CommentMinPercentChange     *float64 `yaml:"comment_min_percent_change,omitempty"`
+	CommentWarningCostIncrease  *float64 `yaml:"comment_warning_cost_increase,omitempty"`
+	CommentCriticalCostIncrease *float64 `yaml:"comment_critical_cost_increase,omitempty"`
+
+	PriceSources     map[string]string `yaml:"price_sources,omitempty"`
+	PricingEndpoints []PricingEndpoint `yaml:"pricing_endpoints,omitempty"`
 }
 
 func loadConfiguration(cfg *Config) error {
@@ -68,6 +79,18 @@ func loadConfiguration(cfg *Config) error {
 		cfg.TLSCACertFile = cfg.Configuration.TLSCACertFile
 	}
 
+	if cfg.Configuration.IncludeFreeTier != nil && !IsEnvPresent("INFRACOST_INCLUDE_FREE_TIER") {
+		cfg.IncludeFreeTier = *cfg.Configuration.IncludeFreeTier
+	}
+
+	if len(cfg.PriceSources) == 0 {
+		cfg.PriceSources = cfg.Configuration.PriceSources
+	}
+
+	if len(cfg.PricingEndpoints) == 0 {
+		cfg.PricingEndpoints = cfg.Configuration.PricingEndpoints
+	}
+
 	return nil
 }
 
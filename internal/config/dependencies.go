@@ -0,0 +1,174 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// OutputRef is a reference to an output of a project, which is used to set
+// an input var of a project that depends on it.
+type OutputRef struct {
+	// Project is the name or path of the project.
+	Project string
+	// Output is the name of the output.
+	Output string
+}
+
+// ParseOutputRef parses an output reference in the format
+// <project name or path>.<output name>. Output names can't have dots, so the
+// project is everything before the last dot.
+func ParseOutputRef(s string) (OutputRef, error) {
+	i := strings.LastIndex(s, ".")
+	if i <= 0 || i == len(s)-1 {
+		return OutputRef{}, fmt.Errorf("output %q must be given as <project name or path>.<output name>", s)
+	}
+
+	return OutputRef{Project: s[:i], Output: s[i+1:]}, nil
+}
+
+// FindProject returns the project whose name is ref, or else whose path is
+// ref. It returns an error if no project matches, or if ref is the path of
+// more than one project, e.g. of a matrix, which must be referenced by name.
+func (c *Config) FindProject(ref string) (*Project, error) {
+	for _, p := range c.Projects {
+		if p.Name != "" && p.Name == ref {
+			return p, nil
+		}
+	}
+
+	var found *Project
+	for _, p := range c.Projects {
+		if filepath.Clean(p.Path) != filepath.Clean(ref) {
+			continue
+		}
+
+		if found != nil {
+			return nil, fmt.Errorf("project %s is ambiguous as more than one project has that path, use the name of the project instead", ref)
+		}
+		found = p
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("project %s does not exist", ref)
+	}
+
+	return found, nil
+}
+
+// ProjectDependencies returns the projects that p depends on, either with
+// depends_on or because it uses their outputs.
+func (c *Config) ProjectDependencies(p *Project) ([]*Project, error) {
+	refs := append([]string{}, p.DependsOn...)
+	for _, name := range sortedStringKeys(p.TerraformOutputVars) {
+		ref, err := ParseOutputRef(p.TerraformOutputVars[name])
+		if err != nil {
+			return nil, fmt.Errorf("terraform_output_vars of project %s is invalid: %w", p.displayName(), err)
+		}
+
+		refs = append(refs, ref.Project)
+	}
+
+	var deps []*Project
+	seen := make(map[*Project]bool)
+	for _, ref := range refs {
+		dep, err := c.FindProject(ref)
+		if err != nil {
+			return nil, fmt.Errorf("dependency of project %s is invalid: %w", p.displayName(), err)
+		}
+
+		if dep == p {
+			return nil, fmt.Errorf("project %s can't depend on itself", p.displayName())
+		}
+
+		if !seen[dep] {
+			seen[dep] = true
+			deps = append(deps, dep)
+		}
+	}
+
+	return deps, nil
+}
+
+// ProjectLevels returns the indexes of the projects grouped into levels that
+// are run one after the other, so every project runs after the projects it
+// depends on. The projects of a level can run in parallel. It returns an
+// error if the dependencies are invalid or have a cycle.
+func (c *Config) ProjectLevels() ([][]int, error) {
+	index := make(map[*Project]int, len(c.Projects))
+	for i, p := range c.Projects {
+		index[p] = i
+	}
+
+	pending := make(map[int][]int, len(c.Projects))
+	for i, p := range c.Projects {
+		deps, err := c.ProjectDependencies(p)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dep := range deps {
+			pending[i] = append(pending[i], index[dep])
+		}
+	}
+
+	done := make(map[int]bool, len(c.Projects))
+	var levels [][]int
+	for len(done) < len(c.Projects) {
+		var level []int
+		for i := range c.Projects {
+			if done[i] {
+				continue
+			}
+
+			ready := true
+			for _, dep := range pending[i] {
+				if !done[dep] {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				level = append(level, i)
+			}
+		}
+
+		if len(level) == 0 {
+			var names []string
+			for i, p := range c.Projects {
+				if !done[i] {
+					names = append(names, p.displayName())
+				}
+			}
+
+			return nil, fmt.Errorf("the dependencies of projects %s have a cycle", strings.Join(names, ", "))
+		}
+
+		for _, i := range level {
+			done[i] = true
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+func (p *Project) displayName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+
+	return p.Path
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
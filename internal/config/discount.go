@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// discountVendors maps the vendors that can be used in discount keys to the
+// vendor names of the pricing API.
+var discountVendors = map[string]string{
+	"aws":    "aws",
+	"azure":  "azure",
+	"gcp":    "gcp",
+	"google": "gcp",
+}
+
+// Discount is an enterprise discount that a cloud provider gives on its list
+// prices, e.g. an AWS EDP or an Azure MACC, set in the discounts of the config
+// file:
+//
+//	discounts:
+//	  aws: 12%
+//	  azure compute: 20%
+//	  gcp Cloud Storage: 5%
+//
+// A discount applies to all the services of a vendor, or to one service or
+// group of services if the key has a service after the vendor.
+type Discount struct {
+	Vendor string
+	// Service is either the name of a service in the pricing API, e.g.
+	// AmazonEC2, or a group of services, e.g. compute. It is empty if the
+	// discount applies to all the services of the vendor.
+	Service string
+	// Percent is the discount percentage, e.g. 12 for 12%.
+	Percent decimal.Decimal
+}
+
+// Label returns the key of the discount in the config file.
+func (d Discount) Label() string {
+	if d.Service == "" {
+		return d.Vendor
+	}
+
+	return d.Vendor + " " + d.Service
+}
+
+// parseDiscounts parses the discounts of the config file. The discounts are
+// sorted so that discounts for a service come before the discount for all the
+// services of the vendor.
+func parseDiscounts(raw map[string]string) ([]Discount, error) {
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	discounts := make([]Discount, 0, len(raw))
+
+	for _, key := range keys {
+		value := raw[key]
+		vendorKey, service, _ := strings.Cut(strings.TrimSpace(key), " ")
+
+		vendor, ok := discountVendors[strings.ToLower(vendorKey)]
+		if !ok {
+			return nil, fmt.Errorf("discount %s is invalid, it must start with aws, azure or gcp", key)
+		}
+
+		percent, err := decimal.NewFromString(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "%")))
+		if err != nil || percent.IsNegative() || percent.GreaterThan(decimal.NewFromInt(100)) {
+			return nil, fmt.Errorf("discount %s is invalid, %s is not a percentage between 0%% and 100%%", key, value)
+		}
+
+		discounts = append(discounts, Discount{
+			Vendor:  vendor,
+			Service: strings.TrimSpace(service),
+			Percent: percent,
+		})
+	}
+
+	sort.Slice(discounts, func(i, j int) bool {
+		if discounts[i].Vendor != discounts[j].Vendor {
+			return discounts[i].Vendor < discounts[j].Vendor
+		}
+
+		if (discounts[i].Service == "") != (discounts[j].Service == "") {
+			return discounts[i].Service != ""
+		}
+
+		return discounts[i].Service < discounts[j].Service
+	})
+
+	return discounts, nil
+}
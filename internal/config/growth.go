@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ProjectGrowth is the expected growth of a project, set in the growth of the
+// project in the config file:
+//
+//	growth:
+//	  monthly_rate: 2
+//	  usage_monthly_rates:
+//	    monthly_requests: 10
+//
+// The rates are the percentages that the cost of the project, or the values
+// of the usage keys, grow by each month. They're used to project the
+// cumulative cost of the project over the next 12 and 36 months.
+type ProjectGrowth struct {
+	// MonthlyRate is the percentage the cost of the project grows by each
+	// month, on top of the growth of its usage.
+	MonthlyRate float64 `yaml:"monthly_rate,omitempty"`
+	// UsageMonthlyRates are the percentages that the values of usage keys
+	// grow by each month, e.g. monthly_requests. They apply to the usage keys
+	// of all the resources of the project.
+	UsageMonthlyRates map[string]float64 `yaml:"usage_monthly_rates,omitempty"`
+}
+
+// validateGrowth returns the errors of the growth of a project in the config
+// file, which must only have valid keys and rates that are numbers greater
+// than -100.
+func validateGrowth(growth interface{}) []error {
+	fields, ok := growth.(map[interface{}]interface{})
+	if !ok {
+		return []error{fmt.Errorf("growth must be a map of monthly_rate and usage_monthly_rates")}
+	}
+
+	allowedKeys := make(map[string]bool)
+	t := reflect.TypeOf(ProjectGrowth{})
+	for i := 0; i < t.NumField(); i++ {
+		allowedKeys[strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]] = true
+	}
+
+	keys := sortedKeys(fields)
+
+	var errs []error
+	for _, k := range keys {
+		if !allowedKeys[k] {
+			errs = append(errs, fmt.Errorf("%s is not a valid growth option", k))
+		}
+	}
+
+	if rate, ok := fields["monthly_rate"]; ok {
+		if err := validateGrowthRate("growth monthly_rate", rate); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if raw, ok := fields["usage_monthly_rates"]; ok {
+		rates, ok := raw.(map[interface{}]interface{})
+		if !ok {
+			return append(errs, fmt.Errorf("growth usage_monthly_rates must be a map of usage keys to rates"))
+		}
+
+		for _, k := range sortedKeys(rates) {
+			if err := validateGrowthRate("growth rate of usage key "+k, rates[k]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateGrowthRate returns an error if the rate isn't a number greater than
+// -100, as the cost can't shrink by more than all of it.
+func validateGrowthRate(name string, rate interface{}) error {
+	var f float64
+	switch v := rate.(type) {
+	case int:
+		f = float64(v)
+	case float64:
+		f = v
+	default:
+		return fmt.Errorf("%s %v is not a number", name, rate)
+	}
+
+	if f <= -100 {
+		return fmt.Errorf("%s %v must be greater than -100", name, rate)
+	}
+
+	return nil
+}
+
+// sortedKeys returns the keys of the raw YAML map as sorted strings.
+func sortedKeys(m map[interface{}]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, fmt.Sprintf("%v", k))
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ProjectVariant is a combination of Terraform workspace and var files that a
+// project is run with. A project with variants is expanded into one project for
+// each of them, so e.g. the staging and prod sizing of the same Terraform
+// directory show in the same report.
+type ProjectVariant struct {
+	// Name is added to the name of the project to name the project of the
+	// variant. Defaults to the workspace, or the names of the var files.
+	Name string `yaml:"name,omitempty"`
+	// TerraformWorkspace overrides the workspace of the project.
+	TerraformWorkspace string `yaml:"terraform_workspace,omitempty"`
+	// TerraformVarFiles are used after the var files of the project.
+	TerraformVarFiles []string `yaml:"terraform_var_files,omitempty"`
+	// TerraformVars are merged with the vars of the project, overriding any
+	// vars with the same name.
+	TerraformVars map[string]string `yaml:"terraform_vars,omitempty"`
+}
+
+// name returns the name of the variant in the names of its project.
+func (v ProjectVariant) name() string {
+	if v.Name != "" {
+		return v.Name
+	}
+
+	if v.TerraformWorkspace != "" {
+		return v.TerraformWorkspace
+	}
+
+	names := make([]string, 0, len(v.TerraformVarFiles))
+	for _, f := range v.TerraformVarFiles {
+		base := filepath.Base(f)
+		names = append(names, strings.TrimSuffix(base, filepath.Ext(base)))
+	}
+
+	return strings.Join(names, "-")
+}
+
+// expandMatrix returns the projects with each project that has a matrix
+// replaced by a project for each of its variants.
+func expandMatrix(projects []*Project) []*Project {
+	var expanded []*Project
+
+	for _, p := range projects {
+		if len(p.Matrix) == 0 {
+			expanded = append(expanded, p)
+			continue
+		}
+
+		baseName := p.Name
+		if baseName == "" {
+			baseName = p.Path
+		}
+
+		for _, v := range p.Matrix {
+			variant := *p
+			variant.Matrix = nil
+			variant.Name = baseName + "-" + v.name()
+
+			if v.TerraformWorkspace != "" {
+				variant.TerraformWorkspace = v.TerraformWorkspace
+			}
+
+			variant.TerraformVarFiles = append(append([]string{}, p.TerraformVarFiles...), v.TerraformVarFiles...)
+
+			if len(v.TerraformVars) > 0 {
+				vars := make(map[string]string, len(p.TerraformVars)+len(v.TerraformVars))
+				for k, val := range p.TerraformVars {
+					vars[k] = val
+				}
+				for k, val := range v.TerraformVars {
+					vars[k] = val
+				}
+				variant.TerraformVars = vars
+			}
+
+			expanded = append(expanded, &variant)
+		}
+	}
+
+	return expanded
+}
+
+// validateMatrix returns the errors of the matrix of a project in the config
+// file, which must be a list of variants that only have valid keys and have
+// unique names.
+func validateMatrix(matrix interface{}) []error {
+	variants, ok := matrix.([]interface{})
+	if !ok {
+		return []error{fmt.Errorf("matrix must be a list of workspace and var file combinations")}
+	}
+
+	allowedKeys := make(map[string]bool)
+	t := reflect.TypeOf(ProjectVariant{})
+	for i := 0; i < t.NumField(); i++ {
+		allowedKeys[strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]] = true
+	}
+
+	var errs []error
+	names := make(map[string]bool)
+
+	for i, raw := range variants {
+		fields, ok := raw.(map[interface{}]interface{})
+		if !ok {
+			errs = append(errs, fmt.Errorf("matrix entry at index %d must be a map", i))
+			continue
+		}
+
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, fmt.Sprintf("%v", k))
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if !allowedKeys[k] {
+				errs = append(errs, fmt.Errorf("%s is not a valid matrix option", k))
+			}
+		}
+
+		name := variantName(fields)
+		if name == "" {
+			errs = append(errs, fmt.Errorf("matrix entry at index %d must have a name, terraform_workspace or terraform_var_files", i))
+			continue
+		}
+
+		if names[name] {
+			errs = append(errs, fmt.Errorf("matrix entry at index %d has the same name as another entry: %s", i, name))
+		}
+		names[name] = true
+	}
+
+	return errs
+}
+
+// variantName returns the name of the variant of the raw matrix entry.
+func variantName(fields map[interface{}]interface{}) string {
+	v := ProjectVariant{}
+	v.Name, _ = fields["name"].(string)
+	v.TerraformWorkspace, _ = fields["terraform_workspace"].(string)
+
+	files, _ := fields["terraform_var_files"].([]interface{})
+	for _, f := range files {
+		if s, ok := f.(string); ok {
+			v.TerraformVarFiles = append(v.TerraformVarFiles, s)
+		}
+	}
+
+	return v.name()
+}
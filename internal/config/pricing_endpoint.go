@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// PricingEndpointSnapshot is the PricingEndpoint that looks up prices from the
+// pricing snapshot file instead of a pricing API.
+const PricingEndpointSnapshot = "snapshot"
+
+// PricingEndpoint is one of the endpoints in the pricing lookup fallback
+// chain. Endpoint is the URL of a self-hosted or the hosted Cloud Pricing API,
+// or PricingEndpointSnapshot. Each request to the endpoint that takes longer
+// than Timeout fails over to the next endpoint, if it's zero
+// PricingEndpointTimeout is used.
+//
+// Endpoints are sent APIKey, or the value of the APIKeyEnv environment
+// variable, so a self-hosted pricing API and the hosted Cloud Pricing API can
+// be in the same chain. If neither is set the API key of the run is sent.
+type PricingEndpoint struct {
+	Endpoint  string        `yaml:"endpoint"`
+	Timeout   time.Duration `yaml:"timeout,omitempty"`
+	APIKey    string        `yaml:"api_key,omitempty"`
+	APIKeyEnv string        `yaml:"api_key_env,omitempty"`
+}
+
+// Decode sets the endpoint from an entry of the comma separated
+// INFRACOST_PRICING_ENDPOINTS environment variable.
+func (p *PricingEndpoint) Decode(value string) error {
+	p.Endpoint = strings.TrimSpace(value)
+	return nil
+}
+
+// ResolveAPIKey returns the API key that is sent to the endpoint, which is
+// defaultKey if the endpoint doesn't set its own.
+func (p PricingEndpoint) ResolveAPIKey(defaultKey string) string {
+	if p.APIKey != "" {
+		return p.APIKey
+	}
+
+	if p.APIKeyEnv != "" {
+		return os.Getenv(p.APIKeyEnv)
+	}
+
+	return defaultKey
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -26,6 +27,10 @@ type ProjectContext struct {
 
 	UsingCache bool
 	CacheErr   string
+
+	// OutputVars are the input vars of the project that are set to the
+	// outputs of the projects it depends on, as JSON values.
+	OutputVars map[string]json.RawMessage
 }
 
 func NewProjectContext(runCtx *RunContext, projectCfg *Project, fields logrus.Fields) *ProjectContext {
@@ -17,6 +17,7 @@ import (
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
 	"github.com/infracost/infracost/internal/version"
 )
@@ -35,6 +36,11 @@ type RunContext struct {
 
 	isCommentCmd bool
 
+	// ResourceRegistry has the registry items of the resource types that are
+	// built in the run, when resource types are registered for the run, e.g.
+	// by plugins. It's nil when only the built-in resource types are used.
+	ResourceRegistry map[string]*schema.RegistryItem
+
 	OutWriter io.Writer
 	ErrWriter io.Writer
 	Exit      func(code int)
@@ -114,7 +120,7 @@ func (r *RunContext) GetParallelism() (int, error) {
 
 	parallelism = *r.Config.Parallelism
 
-	if parallelism < 0 {
+	if parallelism < 1 {
 		return parallelism, fmt.Errorf("parallelism must be a positive number")
 	}
 
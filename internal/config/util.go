@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/mitchellh/go-homedir"
 )
@@ -23,6 +24,90 @@ func userConfigDir() string {
 	return dir
 }
 
+// defaultReportCacheDir returns the directory that finished reports are saved
+// in. Reports aren't saved when running tests.
+func defaultReportCacheDir() string {
+	if IsTest() {
+		return ""
+	}
+
+	return filepath.Join(userConfigDir(), "reports")
+}
+
+// defaultPriceCacheTTL is how long cached prices are used for. Prices rarely
+// change, but new prices are added to the pricing API regularly.
+const defaultPriceCacheTTL = 24 * time.Hour
+
+// defaultPricingEndpointTimeout is how long lookups from an endpoint of the
+// pricing endpoints fallback chain can take before failing over to the next.
+const defaultPricingEndpointTimeout = 30 * time.Second
+
+// defaultPriceCacheDir returns the directory that prices are cached in. Prices
+// aren't cached when running tests.
+func defaultPriceCacheDir() string {
+	if IsTest() {
+		return ""
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "infracost", "prices")
+}
+
+// defaultModuleCacheDir returns the directory that registry modules are shared
+// in. Modules aren't shared when running tests.
+func defaultModuleCacheDir() string {
+	if IsTest() {
+		return ""
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "infracost", "terraform_modules")
+}
+
+// defaultResourceCacheDir returns the directory that built and priced
+// resources are cached in. Resources aren't cached when running tests.
+func defaultResourceCacheDir() string {
+	if IsTest() {
+		return ""
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "infracost", "resources")
+}
+
+// defaultHCLCacheDir returns the directory that evaluated Terraform projects
+// are cached in. Projects aren't cached when running tests.
+func defaultHCLCacheDir() string {
+	if IsTest() {
+		return ""
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "infracost", "hcl")
+}
+
+// defaultPricingSnapshotFile returns the file that prices are downloaded to
+// for offline runs.
+func defaultPricingSnapshotFile() string {
+	return filepath.Join(userConfigDir(), "pricing-snapshot.db")
+}
+
 func FileExists(path string) bool {
 	info, err := os.Stat(path)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclparse"
@@ -19,8 +20,17 @@ var (
 	ErrInvalidCloudToken = errors.New("invalid Terraform Cloud Token")
 )
 
+// tokenEnvPrefix is the prefix of the environment variables that Terraform
+// reads host credentials from, e.g. TF_TOKEN_app_terraform_io.
+const tokenEnvPrefix = "TF_TOKEN_"
+
 // FindTerraformCloudToken returns a TFC Bearer token for the given host.
 func FindTerraformCloudToken(host string) string {
+	if token := credFromEnv(host); token != "" {
+		log.Debugf("Using Terraform Cloud credentials for %s from the environment", host)
+		return token
+	}
+
 	if os.Getenv("TF_CLI_CONFIG_FILE") != "" {
 		log.Debugf("TF_CLI_CONFIG_FILE is set, checking %s for Terraform Cloud credentials", os.Getenv("TF_CLI_CONFIG_FILE"))
 		token, err := credFromHCL(os.Getenv("TF_CLI_CONFIG_FILE"), host)
@@ -59,6 +69,29 @@ func FindTerraformCloudToken(host string) string {
 	return ""
 }
 
+// credFromEnv returns the token for the host from a TF_TOKEN_ environment
+// variable. The periods of the host are encoded as underscores in the name of
+// the variable and hyphens as double underscores, as Terraform does, e.g.
+// TF_TOKEN_tfe_my__company_com for tfe.my-company.com.
+func credFromEnv(host string) string {
+	for _, env := range os.Environ() {
+		k, v, ok := strings.Cut(env, "=")
+		if !ok || !strings.HasPrefix(k, tokenEnvPrefix) {
+			continue
+		}
+
+		name := strings.TrimPrefix(k, tokenEnvPrefix)
+		name = strings.ReplaceAll(name, "__", "-")
+		name = strings.ReplaceAll(name, "_", ".")
+
+		if strings.EqualFold(name, host) {
+			return v
+		}
+	}
+
+	return ""
+}
+
 func credFromHCL(filename string, host string) (string, error) {
 	parser := hclparse.NewParser()
 	f, parseDiags := parser.ParseHCLFile(filename)
@@ -116,6 +149,12 @@ func CheckCloudConfigSet() bool {
 		return true
 	}
 
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, tokenEnvPrefix) {
+			return true
+		}
+	}
+
 	if _, err := os.Stat(defaultConfFile()); err == nil {
 		return true
 	}
@@ -0,0 +1,131 @@
+package githubapp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+	"golang.org/x/oauth2"
+)
+
+// DefaultAPIURL is the API URL of github.com.
+const DefaultAPIURL = "https://api.github.com"
+
+// App authenticates as a GitHub App. The App signs JWTs with its private key
+// to get access tokens for the installations of the App.
+type App struct {
+	ID         int64
+	PrivateKey *rsa.PrivateKey
+	// APIURL is the URL of the GitHub API, it defaults to DefaultAPIURL. GitHub
+	// Enterprise API URLs are of the form https://github.example.com/api/v3/.
+	APIURL string
+}
+
+// ParsePrivateKey parses the PEM encoded private key that GitHub generates for
+// an App.
+func ParsePrivateKey(b []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse private key: %w", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+
+	return rsaKey, nil
+}
+
+// JWT returns a JWT that authenticates as the App for 10 minutes from now.
+// GitHub only accepts JWTs that expire within 10 minutes, the issue time is
+// set a minute in the past to allow for clock drift.
+func (a *App) JWT(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(a.ID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, a.PrivateKey, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+// InstallationToken returns an access token for the installation of the App
+// with installationID. The token expires after an hour.
+func (a *App) InstallationToken(ctx context.Context, installationID int64) (string, error) {
+	jwt, err := a.JWT(time.Now())
+	if err != nil {
+		return "", fmt.Errorf("could not sign GitHub App JWT: %w", err)
+	}
+
+	client, err := a.client(ctx, jwt)
+	if err != nil {
+		return "", err
+	}
+
+	token, _, err := client.Apps.CreateInstallationToken(ctx, installationID, nil)
+	if err != nil {
+		return "", fmt.Errorf("could not create access token for installation %d: %w", installationID, err)
+	}
+
+	return token.GetToken(), nil
+}
+
+// client returns a GitHub API client that is authenticated with token.
+func (a *App) client(ctx context.Context, token string) (*github.Client, error) {
+	tc := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+
+	if a.APIURL == "" || a.APIURL == DefaultAPIURL {
+		return github.NewClient(tc), nil
+	}
+
+	u, err := url.Parse(a.APIURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub API URL: %w", err)
+	}
+
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+
+	return github.NewEnterpriseClient(u.String(), u.String(), tc)
+}
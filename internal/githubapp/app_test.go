@@ -0,0 +1,92 @@
+package githubapp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	parsed, err := ParsePrivateKey(pkcs1)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	b, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: b})
+	parsed, err = ParsePrivateKey(pkcs8)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	_, err = ParsePrivateKey([]byte("not a key"))
+	assert.EqualError(t, err, "private key is not PEM encoded")
+}
+
+func TestAppJWT(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	app := &App{ID: 12345, PrivateKey: key}
+	now := time.Unix(1700000000, 0)
+
+	jwt, err := app.JWT(now)
+	require.NoError(t, err)
+
+	parts := strings.Split(jwt, ".")
+	require.Len(t, parts, 3)
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	sum := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, sum[:], sig))
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+
+	var claims map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &claims))
+	assert.Equal(t, "12345", claims["iss"])
+	assert.Equal(t, float64(now.Unix()-60), claims["iat"])
+	assert.Equal(t, float64(now.Unix()+540), claims["exp"])
+}
+
+func TestAppInstallationToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var auth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v3/app/installations/42/access_tokens", r.URL.Path)
+		auth = r.Header.Get("Authorization")
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"token": "ghs_installation"}`))
+	}))
+	defer ts.Close()
+
+	app := &App{ID: 1, PrivateKey: key, APIURL: ts.URL + "/api/v3"}
+
+	token, err := app.InstallationToken(context.Background(), 42)
+	require.NoError(t, err)
+	assert.Equal(t, "ghs_installation", token)
+	assert.True(t, strings.HasPrefix(auth, "Bearer "))
+}
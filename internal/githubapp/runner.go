@@ -0,0 +1,355 @@
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/google/go-github/v41/github"
+	"gopkg.in/yaml.v2"
+
+	"github.com/infracost/infracost/internal/logging"
+)
+
+// projectFileExts are the extensions of the files that change the cost of the
+// directory they are in.
+var projectFileExts = []string{".tf", ".tf.json", ".tfvars", ".tfvars.json", ".hcl"}
+
+const configFileName = "infracost-github-app.yml"
+
+// Runner estimates the cost of the changed directories of pull requests and
+// posts the estimates as pull request comments.
+type Runner struct {
+	App *App
+	// Infracost runs the infracost CLI with args in dir, with env added to its
+	// environment.
+	Infracost func(ctx context.Context, dir string, env []string, args ...string) error
+	// Behavior is the behavior of the comment, see `infracost comment github`.
+	Behavior string
+	// WorkDir is the directory that pull requests are checked out in, it
+	// defaults to the temporary directory.
+	WorkDir string
+	// AllowForks estimates pull requests from forks. Their code isn't trusted,
+	// so Terragrunt projects, whose config can run commands, aren't evaluated.
+	AllowForks bool
+}
+
+// Run estimates the cost of the pull request of job and comments it. The
+// merge-base and head of the pull request are checked out and the cost of each
+// directory with changed Terraform files is compared between them. Pull
+// requests from forks are skipped unless AllowForks is set.
+func (r *Runner) Run(ctx context.Context, job Job) error {
+	if job.fromFork() && !r.AllowForks {
+		logging.Logger.Infof("Skipping %s#%d as it's from a fork", job.Repo, job.PullRequest)
+		return nil
+	}
+
+	token, err := r.App.InstallationToken(ctx, job.InstallationID)
+	if err != nil {
+		return err
+	}
+
+	client, err := r.App.client(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	files, err := pullRequestFiles(ctx, client, job)
+	if err != nil {
+		return err
+	}
+
+	dirs := ChangedProjectDirs(files)
+	if len(dirs) == 0 {
+		logging.Logger.Infof("Skipping %s#%d as no Terraform files changed", job.Repo, job.PullRequest)
+		return nil
+	}
+
+	tmp, err := os.MkdirTemp(r.WorkDir, "infracost-github-app-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	// The base is checked out at the merge-base rather than the tip of the
+	// base branch, so the changes of the base branch since the pull request
+	// was opened aren't included in its cost.
+	mergeBase, err := mergeBaseSHA(ctx, client, job)
+	if err != nil {
+		return err
+	}
+
+	baseDir := filepath.Join(tmp, "base")
+	err = checkoutRef(ctx, baseDir, job.CloneURL, token, plumbing.NewBranchReferenceName(job.BaseRef), plumbing.NewHash(mergeBase))
+	if err != nil {
+		return fmt.Errorf("could not check out %s of %s: %w", mergeBase, job.Repo, err)
+	}
+
+	headDir := filepath.Join(tmp, "head")
+	err = checkoutRef(ctx, headDir, job.CloneURL, token, plumbing.ReferenceName(fmt.Sprintf("refs/pull/%d/head", job.PullRequest)), plumbing.NewHash(job.HeadSHA))
+	if err != nil {
+		return fmt.Errorf("could not check out %s of pull request %s#%d: %w", job.HeadSHA, job.Repo, job.PullRequest, err)
+	}
+
+	headDirs := existingDirs(headDir, dirs)
+	if len(headDirs) == 0 {
+		logging.Logger.Infof("Skipping %s#%d as all the changed directories were deleted", job.Repo, job.PullRequest)
+		return nil
+	}
+
+	var headEnv []string
+	if job.fromFork() {
+		headEnv = []string{"INFRACOST_DISABLE_TERRAGRUNT=true"}
+	}
+
+	headPath := filepath.Join(tmp, "infracost.json")
+
+	baseDirs := existingDirs(baseDir, dirs)
+	if len(baseDirs) == 0 {
+		err = r.run(ctx, headDir, headDirs, headEnv, "breakdown", "--out-file", headPath)
+		if err != nil {
+			return err
+		}
+	} else {
+		basePath := filepath.Join(tmp, "infracost-base.json")
+
+		err = r.run(ctx, baseDir, baseDirs, nil, "breakdown", "--out-file", basePath)
+		if err != nil {
+			return err
+		}
+
+		err = r.run(ctx, headDir, headDirs, headEnv, "diff", "--compare-to", basePath, "--out-file", headPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	apiURL := r.App.APIURL
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+
+	// The token is passed in the environment rather than the arguments so it
+	// isn't visible to other processes.
+	return r.Infracost(ctx, tmp, []string{"INFRACOST_GITHUB_TOKEN=" + token},
+		"comment", "github",
+		"--path", headPath,
+		"--repo", job.Repo,
+		"--pull-request", strconv.Itoa(job.PullRequest),
+		"--github-api-url", apiURL,
+		"--behavior", r.Behavior,
+	)
+}
+
+// run runs the infracost command for the projects in dirs of the checkout in
+// dir, with env added to its environment. The projects are listed in a config
+// file so they are all estimated in one run. The command is run in the parent
+// of dir so that files of the checkout, e.g. a .env file, can't configure it.
+func (r *Runner) run(ctx context.Context, dir string, dirs []string, env []string, command string, args ...string) error {
+	configFile := dir + "-" + configFileName
+
+	err := writeConfigFile(configFile, dir, dirs)
+	if err != nil {
+		return err
+	}
+
+	args = append([]string{command, "--config-file", configFile, "--format", "json"}, args...)
+	return r.Infracost(ctx, filepath.Dir(dir), env, args...)
+}
+
+// ChangedProjectDirs returns the sorted directories of the changed files that
+// can change the cost of a Terraform project.
+func ChangedProjectDirs(files []string) []string {
+	seen := map[string]bool{}
+	var dirs []string
+
+	for _, f := range files {
+		if !isProjectFile(f) {
+			continue
+		}
+
+		dir := path.Dir(f)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	sort.Strings(dirs)
+	return dirs
+}
+
+func isProjectFile(f string) bool {
+	for _, ext := range projectFileExts {
+		if strings.HasSuffix(f, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// pullRequestFiles returns the paths of the files that the pull request of job
+// changes. The previous paths of renamed files are included since their
+// directory changes too.
+func pullRequestFiles(ctx context.Context, client *github.Client, job Job) ([]string, error) {
+	owner, repo, ok := strings.Cut(job.Repo, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid GitHub repository name: %s", job.Repo)
+	}
+
+	var files []string
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		page, resp, err := client.PullRequests.ListFiles(ctx, owner, repo, job.PullRequest, opts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list files of %s#%d: %w", job.Repo, job.PullRequest, err)
+		}
+
+		for _, f := range page {
+			files = append(files, f.GetFilename())
+			if f.GetPreviousFilename() != "" {
+				files = append(files, f.GetPreviousFilename())
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return files, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// mergeBaseSHA returns the commit of the base branch of the pull request of
+// job that its head branched off from.
+func mergeBaseSHA(ctx context.Context, client *github.Client, job Job) (string, error) {
+	owner, repo, ok := strings.Cut(job.Repo, "/")
+	if !ok {
+		return "", fmt.Errorf("invalid GitHub repository name: %s", job.Repo)
+	}
+
+	comparison, _, err := client.Repositories.CompareCommits(ctx, owner, repo, job.BaseRef, job.HeadSHA, &github.ListOptions{PerPage: 1})
+	if err != nil {
+		return "", fmt.Errorf("could not find the merge-base of %s#%d: %w", job.Repo, job.PullRequest, err)
+	}
+
+	sha := comparison.GetMergeBaseCommit().GetSHA()
+	if sha == "" {
+		return "", fmt.Errorf("could not find the merge-base of %s#%d", job.Repo, job.PullRequest)
+	}
+
+	return sha, nil
+}
+
+// checkoutRef checks out commit want of ref of the repository at cloneURL into
+// dir, or the latest commit of ref if want is the zero hash. Only the latest
+// commit is fetched, unless ref has moved on from want, e.g. when a pull
+// request is pushed to again before its job runs or when want is the
+// merge-base of a pull request whose base branch has moved on.
+func checkoutRef(ctx context.Context, dir, cloneURL, token string, ref plumbing.ReferenceName, want plumbing.Hash) error {
+	repo, fetched, err := fetchRef(ctx, dir, cloneURL, token, ref, 1)
+	if err != nil {
+		return err
+	}
+
+	if want.IsZero() {
+		want = fetched
+	} else if want != fetched {
+		err = os.RemoveAll(dir)
+		if err != nil {
+			return err
+		}
+
+		repo, _, err = fetchRef(ctx, dir, cloneURL, token, ref, 0)
+		if err != nil {
+			return err
+		}
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	return wt.Checkout(&git.CheckoutOptions{Hash: want})
+}
+
+// fetchRef fetches depth commits of ref of the repository at cloneURL into a
+// new repository in dir, or all of its commits if depth is 0. It returns the
+// repository and the latest commit of ref.
+func fetchRef(ctx context.Context, dir, cloneURL, token string, ref plumbing.ReferenceName, depth int) (*git.Repository, plumbing.Hash, error) {
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+
+	remote, err := repo.CreateRemote(&gitconfig.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{cloneURL}})
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+
+	err = remote.FetchContext(ctx, &git.FetchOptions{
+		RefSpecs: []gitconfig.RefSpec{gitconfig.RefSpec(fmt.Sprintf("+%s:%s", ref, ref))},
+		Depth:    depth,
+		Auth:     &githttp.BasicAuth{Username: "x-access-token", Password: token},
+	})
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+
+	fetched, err := repo.Reference(ref, true)
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+
+	return repo, fetched.Hash(), nil
+}
+
+// existingDirs returns the dirs that exist in the checkout in root.
+func existingDirs(root string, dirs []string) []string {
+	var existing []string
+
+	for _, d := range dirs {
+		info, err := os.Stat(filepath.Join(root, filepath.FromSlash(d)))
+		if err == nil && info.IsDir() {
+			existing = append(existing, d)
+		}
+	}
+
+	return existing
+}
+
+// writeConfigFile writes an Infracost config file with a project for each of
+// dirs of the checkout in root. Projects are named by their directory so that
+// the projects of the base and the head of a pull request are matched.
+func writeConfigFile(path, root string, dirs []string) error {
+	type project struct {
+		Path string `yaml:"path"`
+		Name string `yaml:"name"`
+	}
+
+	cfg := struct {
+		Version  string    `yaml:"version"`
+		Projects []project `yaml:"projects"`
+	}{Version: "0.1"}
+
+	for _, d := range dirs {
+		cfg.Projects = append(cfg.Projects, project{Path: filepath.Join(root, filepath.FromSlash(d)), Name: d})
+	}
+
+	b, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0600)
+}
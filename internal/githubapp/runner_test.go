@@ -0,0 +1,61 @@
+package githubapp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedProjectDirs(t *testing.T) {
+	files := []string{
+		"README.md",
+		"prod/main.tf",
+		"prod/variables.tf",
+		"dev/terraform.tfvars",
+		"modules/vpc/main.tf.json",
+		"live/terragrunt.hcl",
+		"main.tf",
+		".github/workflows/infracost.yml",
+	}
+
+	assert.Equal(t, []string{".", "dev", "live", "modules/vpc", "prod"}, ChangedProjectDirs(files))
+	assert.Empty(t, ChangedProjectDirs([]string{"README.md"}))
+}
+
+func TestExistingDirs(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "prod"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.tf"), nil, 0600))
+
+	assert.Equal(t, []string{".", "prod"}, existingDirs(root, []string{".", "dev", "prod", "main.tf"}))
+}
+
+func TestWriteConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), configFileName)
+	require.NoError(t, writeConfigFile(path, "/tmp/head", []string{"dev", "envs/prod"}))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `version: "0.1"
+projects:
+- path: /tmp/head/dev
+  name: dev
+- path: /tmp/head/envs/prod
+  name: envs/prod
+`, string(b))
+}
+
+func TestRunnerRunSkipsForks(t *testing.T) {
+	// The runner has no App, so the test fails if the pull request isn't
+	// skipped before it's checked out.
+	r := &Runner{}
+
+	for _, headRepo := range []string{"someone/my-repo", ""} {
+		err := r.Run(context.Background(), Job{Repo: "my-org/my-repo", PullRequest: 7, HeadRepo: headRepo})
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,229 @@
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/google/go-github/v41/github"
+
+	"github.com/infracost/infracost/internal/logging"
+)
+
+// pullRequestActions are the actions of pull_request events that change the
+// code of a pull request, so its cost estimate needs to be updated.
+var pullRequestActions = []string{"opened", "reopened", "synchronize"}
+
+// Job is a pull request that needs a cost estimate.
+type Job struct {
+	InstallationID int64
+	// Repo is the full name of the repository, i.e. owner/repo.
+	Repo        string
+	CloneURL    string
+	PullRequest int
+	BaseRef     string
+	HeadSHA     string
+	// HeadRepo is the full name of the repository that the head of the pull
+	// request is in. It differs from Repo for pull requests from forks, and
+	// it's empty if the fork has been deleted.
+	HeadRepo string
+}
+
+// fromFork returns true if the head of the pull request isn't in the
+// repository of the App installation, so its code isn't trusted.
+func (j Job) fromFork() bool {
+	return j.HeadRepo != j.Repo
+}
+
+// key returns the key of the pull request of the job. Jobs with the same key
+// are run one at a time.
+func (j Job) key() string {
+	return fmt.Sprintf("%s#%d", j.Repo, j.PullRequest)
+}
+
+// Server receives the webhooks of a GitHub App and queues a Job for each pull
+// request that is opened or updated. The jobs are run in the background by Run
+// so that webhooks are acknowledged before GitHub's 10 second timeout.
+//
+// The queue is keyed by pull request, so the jobs of a pull request are run
+// one at a time and their comments are posted in order. A job replaces the
+// job of the same pull request that's still queued, as only the estimate of
+// the latest commit is needed.
+type Server struct {
+	webhookSecret []byte
+	// keys are the keys of the pull requests with a pending job that isn't
+	// running.
+	keys chan string
+
+	mu sync.Mutex
+	// pending are the latest jobs of the pull requests that haven't started.
+	pending map[string]Job
+	// running are the keys of the pull requests with a running job. Their
+	// pending jobs are queued once the running job finishes.
+	running map[string]bool
+}
+
+// NewServer returns a Server that validates webhooks with webhookSecret and
+// queues the jobs of up to queueSize pull requests.
+func NewServer(webhookSecret []byte, queueSize int) *Server {
+	return &Server{
+		webhookSecret: webhookSecret,
+		keys:          make(chan string, queueSize),
+		pending:       make(map[string]Job),
+		running:       make(map[string]bool),
+	}
+}
+
+// queue adds the job to the queue, it returns false if the queue is full.
+func (s *Server) queue(job Job) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := job.key()
+	if _, ok := s.pending[key]; ok || s.running[key] {
+		s.pending[key] = job
+		return true
+	}
+
+	select {
+	case s.keys <- key:
+		s.pending[key] = job
+		return true
+	default:
+		return false
+	}
+}
+
+// start returns the pending job of the pull request with key and marks the
+// pull request as running.
+func (s *Server) start(key string) Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job := s.pending[key]
+	delete(s.pending, key)
+	s.running[key] = true
+
+	return job
+}
+
+// finish marks the pull request with key as not running and queues its
+// pending job if it got one while it was running.
+func (s *Server) finish(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.running, key)
+
+	job, ok := s.pending[key]
+	if !ok {
+		return
+	}
+
+	select {
+	case s.keys <- key:
+	default:
+		delete(s.pending, key)
+		logging.Logger.Warnf("Dropped cost estimate for %s#%d as the queue is full", job.Repo, job.PullRequest)
+	}
+}
+
+// ServeHTTP handles a webhook delivery.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := github.ValidatePayload(r, s.webhookSecret)
+	if err != nil {
+		logging.Logger.WithError(err).Debug("invalid GitHub webhook")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := github.ParseWebHook(github.WebHookType(r), payload)
+	if err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	job, ok := jobFromEvent(event)
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if !s.queue(job) {
+		logging.Logger.Warnf("Dropped cost estimate for %s#%d as the queue is full", job.Repo, job.PullRequest)
+		http.Error(w, "queue is full", http.StatusServiceUnavailable)
+		return
+	}
+
+	logging.Logger.Infof("Queued cost estimate for %s#%d", job.Repo, job.PullRequest)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Run runs the queued jobs with run until ctx is done. Jobs are run by workers
+// goroutines in parallel, but only one job of each pull request runs at a
+// time.
+func (s *Server) Run(ctx context.Context, workers int, run func(context.Context, Job) error) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case key := <-s.keys:
+					job := s.start(key)
+					err := run(ctx, job)
+					if err != nil {
+						logging.Logger.WithError(err).Errorf("Failed to estimate cost of %s#%d", job.Repo, job.PullRequest)
+					}
+					s.finish(key)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// jobFromEvent returns the Job for a webhook event, it returns false if the
+// event doesn't need a cost estimate.
+func jobFromEvent(event interface{}) (Job, bool) {
+	e, ok := event.(*github.PullRequestEvent)
+	if !ok || !contains(pullRequestActions, e.GetAction()) {
+		return Job{}, false
+	}
+
+	pr := e.GetPullRequest()
+	if pr.GetState() != "open" {
+		return Job{}, false
+	}
+
+	return Job{
+		InstallationID: e.GetInstallation().GetID(),
+		Repo:           e.GetRepo().GetFullName(),
+		CloneURL:       e.GetRepo().GetCloneURL(),
+		PullRequest:    e.GetNumber(),
+		BaseRef:        pr.GetBase().GetRef(),
+		HeadSHA:        pr.GetHead().GetSHA(),
+		HeadRepo:       pr.GetHead().GetRepo().GetFullName(),
+	}, true
+}
+
+func contains(arr []string, e string) bool {
+	for _, a := range arr {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
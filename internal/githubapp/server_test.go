@@ -0,0 +1,189 @@
+package githubapp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPullRequestEvent = `{
+  "action": "%s",
+  "number": 7,
+  "pull_request": {
+    "state": "open",
+    "base": {"ref": "main"},
+    "head": {"sha": "abc123", "repo": {"full_name": "my-org/my-repo"}}
+  },
+  "repository": {
+    "full_name": "my-org/my-repo",
+    "clone_url": "https://github.com/my-org/my-repo.git"
+  },
+  "installation": {"id": 42}
+}`
+
+func newWebhookRequest(t *testing.T, secret, event, payload string) *http.Request {
+	t.Helper()
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(payload))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	return req
+}
+
+func TestServerServeHTTP(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		event   string
+		payload string
+		status  int
+		queued  bool
+	}{
+		{
+			name:    "opened pull request",
+			secret:  "secret",
+			event:   "pull_request",
+			payload: strings.Replace(testPullRequestEvent, "%s", "opened", 1),
+			status:  http.StatusAccepted,
+			queued:  true,
+		},
+		{
+			name:    "synchronized pull request",
+			secret:  "secret",
+			event:   "pull_request",
+			payload: strings.Replace(testPullRequestEvent, "%s", "synchronize", 1),
+			status:  http.StatusAccepted,
+			queued:  true,
+		},
+		{
+			name:    "closed pull request",
+			secret:  "secret",
+			event:   "pull_request",
+			payload: strings.Replace(testPullRequestEvent, "%s", "closed", 1),
+			status:  http.StatusNoContent,
+		},
+		{
+			name:    "other event",
+			secret:  "secret",
+			event:   "ping",
+			payload: `{"zen": "Keep it logically awesome."}`,
+			status:  http.StatusNoContent,
+		},
+		{
+			name:    "invalid signature",
+			secret:  "wrong",
+			event:   "pull_request",
+			payload: strings.Replace(testPullRequestEvent, "%s", "opened", 1),
+			status:  http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer([]byte("secret"), 1)
+
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, newWebhookRequest(t, tt.secret, tt.event, tt.payload))
+			assert.Equal(t, tt.status, w.Code)
+
+			select {
+			case key := <-s.keys:
+				require.True(t, tt.queued, "unexpected job queued")
+				job := s.start(key)
+				assert.Equal(t, Job{
+					InstallationID: 42,
+					Repo:           "my-org/my-repo",
+					CloneURL:       "https://github.com/my-org/my-repo.git",
+					PullRequest:    7,
+					BaseRef:        "main",
+					HeadSHA:        "abc123",
+					HeadRepo:       "my-org/my-repo",
+				}, job)
+			default:
+				assert.False(t, tt.queued, "expected a job to be queued")
+			}
+		})
+	}
+}
+
+func TestServerQueueFull(t *testing.T) {
+	s := NewServer([]byte("secret"), 1)
+	payload := strings.Replace(testPullRequestEvent, "%s", "opened", 1)
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, newWebhookRequest(t, "secret", "pull_request", payload))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	// Another job of the same pull request replaces the queued job.
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, newWebhookRequest(t, "secret", "pull_request", strings.Replace(payload, "abc123", "def456", 1)))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, newWebhookRequest(t, "secret", "pull_request", strings.Replace(payload, `"number": 7`, `"number": 8`, 1)))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	assert.Equal(t, "def456", s.start(<-s.keys).HeadSHA)
+}
+
+func TestServerQueuePerPullRequest(t *testing.T) {
+	s := NewServer([]byte("secret"), 10)
+
+	require.True(t, s.queue(Job{Repo: "my-org/my-repo", PullRequest: 7, HeadSHA: "a"}))
+	job := s.start(<-s.keys)
+	assert.Equal(t, "a", job.HeadSHA)
+
+	// Jobs of a pull request with a running job wait until it finishes, and
+	// only the latest of them is run.
+	require.True(t, s.queue(Job{Repo: "my-org/my-repo", PullRequest: 7, HeadSHA: "b"}))
+	require.True(t, s.queue(Job{Repo: "my-org/my-repo", PullRequest: 7, HeadSHA: "c"}))
+	require.True(t, s.queue(Job{Repo: "my-org/other-repo", PullRequest: 7, HeadSHA: "d"}))
+	assert.Len(t, s.keys, 1)
+	assert.Equal(t, "d", s.start(<-s.keys).HeadSHA)
+
+	s.finish(job.key())
+	assert.Len(t, s.keys, 1)
+	assert.Equal(t, "c", s.start(<-s.keys).HeadSHA)
+	assert.Empty(t, s.keys)
+}
+
+func TestServerRun(t *testing.T) {
+	s := NewServer([]byte("secret"), 1)
+	require.True(t, s.queue(Job{Repo: "my-org/my-repo", PullRequest: 7}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ran := make(chan Job, 1)
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx, 1, func(ctx context.Context, job Job) error {
+			ran <- job
+			return nil
+		})
+		close(done)
+	}()
+
+	select {
+	case job := <-ran:
+		assert.Equal(t, 7, job.PullRequest)
+	case <-time.After(5 * time.Second):
+		t.Fatal("job was not run")
+	}
+
+	cancel()
+	<-done
+}
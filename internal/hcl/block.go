@@ -997,6 +997,11 @@ func (b *Block) FullName() string {
 	return b.LocalName()
 }
 
+// StartLine returns the line number in Filename that the Block definition starts on.
+func (b *Block) StartLine() int {
+	return b.hclBlock.DefRange.Start.Line
+}
+
 func (b *Block) Type() string {
 	return b.hclBlock.Type
 }
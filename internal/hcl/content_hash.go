@@ -0,0 +1,208 @@
+package hcl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyJson "github.com/zclconf/go-cty/cty/json"
+)
+
+// DirHashes hashes the contents of module directories, caching the hash of
+// each directory so modules that are called by many projects are only read
+// once.
+type DirHashes struct {
+	mu     sync.Mutex
+	hashes map[string]string
+}
+
+// NewDirHashes returns an empty DirHashes.
+func NewDirHashes() *DirHashes {
+	return &DirHashes{hashes: make(map[string]string)}
+}
+
+// Hash returns the hash of the files of the module in dir. The files of its
+// subdirectories are included, e.g. templates that are read with the file
+// function, except for hidden directories and directories that are modules of
+// their own, as those are hashed separately when they're called.
+func (h *DirHashes) Hash(dir string) (string, error) {
+	dir = filepath.Clean(dir)
+
+	h.mu.Lock()
+	hash, ok := h.hashes[dir]
+	h.mu.Unlock()
+	if ok {
+		return hash, nil
+	}
+
+	sum := sha256.New()
+	err := hashDir(sum, dir, "")
+	if err != nil {
+		return "", err
+	}
+
+	hash = hex.EncodeToString(sum.Sum(nil))
+
+	h.mu.Lock()
+	h.hashes[dir] = hash
+	h.mu.Unlock()
+
+	return hash, nil
+}
+
+func hashDir(w io.Writer, dir string, rel string) error {
+	entries, err := os.ReadDir(filepath.Join(dir, rel))
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := filepath.Join(rel, entry.Name())
+		if entry.IsDir() {
+			if strings.HasPrefix(entry.Name(), ".") || isModuleDir(filepath.Join(dir, name)) {
+				continue
+			}
+
+			err = hashDir(w, dir, name)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "%s\x00%d\x00", filepath.ToSlash(name), len(b))
+		_, _ = w.Write(b)
+	}
+
+	return nil
+}
+
+// isModuleDir returns true if dir has Terraform files.
+func isModuleDir(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && (strings.HasSuffix(entry.Name(), ".tf") || strings.HasSuffix(entry.Name(), ".tf.json")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ContentHash returns a hash of everything that the root module of the parser
+// is evaluated from: the contents of its directory and of the directories of
+// all the modules that it calls, its var files, input vars and workspace. It
+// returns an error if the hash can't be worked out, e.g. if the root module
+// loads variables from Terraform Cloud or reads remote state.
+func (p *Parser) ContentHash(dirHashes *DirHashes) (string, error) {
+	if p.remoteVariablesLoader != nil {
+		return "", fmt.Errorf("remote variables can't be hashed")
+	}
+
+	if p.remoteStateLoader != nil {
+		return "", fmt.Errorf("remote state can't be hashed")
+	}
+
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.New()
+	fmt.Fprintf(sum, "path\x00%s\x00wd\x00%s\x00workspace\x00%s\x00", p.initialPath, workingDir, p.workspaceName)
+
+	rootHash, err := dirHashes.Hash(p.initialPath)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(sum, "module\x00\x00%s\x00", rootHash)
+
+	manifest, err := p.moduleLoader.Load(p.initialPath)
+	if err != nil {
+		return "", err
+	}
+
+	moduleKeys := make([]string, 0, len(manifest.Modules))
+	for _, m := range manifest.Modules {
+		moduleKeys = append(moduleKeys, m.Key)
+	}
+	sort.Strings(moduleKeys)
+
+	for _, key := range moduleKeys {
+		moduleHash, err := dirHashes.Hash(manifest.FindModulePath(key))
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(sum, "module\x00%s\x00%s\x00", key, moduleHash)
+	}
+
+	for _, filename := range append(append([]string{}, p.defaultVarFiles...), p.tfvarsPaths...) {
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(sum, "varfile\x00%s\x00%d\x00", filename, len(b))
+		_, _ = sum.Write(b)
+	}
+
+	err = hashVars(sum, "envvar", p.tfEnvVars)
+	if err != nil {
+		return "", err
+	}
+
+	err = hashVars(sum, "inputvar", p.inputVars)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
+
+func hashVars(w io.Writer, kind string, vars map[string]cty.Value) error {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v := vars[name]
+		b, err := ctyJson.Marshal(v, v.Type())
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "%s\x00%s\x00%s\x00", kind, name, b)
+	}
+
+	return nil
+}
+
+// HasChanges returns true if the root module of the parser has changes in
+// the VCS diff that the run is for.
+func (p *Parser) HasChanges() bool {
+	return p.hasChanges
+}
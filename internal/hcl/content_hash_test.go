@@ -0,0 +1,68 @@
+package hcl
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/hcl/modules"
+	"github.com/infracost/infracost/internal/sync"
+)
+
+func TestParserContentHash(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, contents string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	}
+
+	write("infra/main.tf", `
+module "app" {
+  source = "../modules/app"
+}
+`)
+	write("modules/app/main.tf", `
+resource "aws_instance" "app" {
+  instance_type = "t3.micro"
+  user_data     = file("${path.module}/templates/user_data.sh")
+}
+`)
+	write("modules/app/templates/user_data.sh", "echo hello")
+	write("other/main.tf", `resource "aws_instance" "other" {}`)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	entry := logrus.NewEntry(logger)
+
+	hash := func(options ...Option) string {
+		root := filepath.Join(dir, "infra")
+		parser := newParser(RootPath{Path: root}, modules.NewModuleLoader(root, nil, entry, &sync.KeyMutex{}), entry, options...)
+
+		h, err := parser.ContentHash(NewDirHashes())
+		require.NoError(t, err)
+
+		return h
+	}
+
+	original := hash()
+	assert.Equal(t, original, hash(), "the hash should be stable")
+
+	write("other/main.tf", `resource "aws_instance" "other" { instance_type = "m5.large" }`)
+	assert.Equal(t, original, hash(), "modules that aren't called shouldn't change the hash")
+
+	assert.NotEqual(t, original, hash(OptionWithInputVars(map[string]string{"env": "prod"})))
+	assert.NotEqual(t, original, hash(OptionWithTerraformWorkspace("prod")))
+
+	write("modules/app/templates/user_data.sh", "echo goodbye")
+	templateChanged := hash()
+	assert.NotEqual(t, original, templateChanged, "files that modules read should change the hash")
+
+	write("modules/app/main.tf", `resource "aws_instance" "app" { instance_type = "t3.large" }`)
+	assert.NotEqual(t, templateChanged, hash())
+}
@@ -91,8 +91,11 @@ type Evaluator struct {
 	workspace string
 	// blockBuilder handles generating blocks in the evaluation step.
 	blockBuilder BlockBuilder
-	newSpinner   ui.SpinnerFunc
-	logger       *logrus.Entry
+	// remoteStateLoader reads the outputs of terraform_remote_state data sources. Their outputs
+	// aren't evaluated if it's nil.
+	remoteStateLoader *RemoteStateLoader
+	newSpinner        ui.SpinnerFunc
+	logger            *logrus.Entry
 }
 
 // NewEvaluator returns an Evaluator with Context initialised with top level variables.
@@ -106,6 +109,7 @@ func NewEvaluator(
 	visitedModules map[string]map[string]cty.Value,
 	workspace string,
 	blockBuilder BlockBuilder,
+	remoteStateLoader *RemoteStateLoader,
 	spinFunc ui.SpinnerFunc,
 	logger *logrus.Entry,
 ) *Evaluator {
@@ -149,17 +153,18 @@ func NewEvaluator(
 	})
 
 	return &Evaluator{
-		module:         module,
-		ctx:            ctx,
-		inputVars:      inputVars,
-		moduleMetadata: moduleMetadata,
-		moduleCalls:    map[string]*ModuleCall{},
-		visitedModules: visitedModules,
-		workspace:      workspace,
-		workingDir:     workingDir,
-		blockBuilder:   blockBuilder,
-		newSpinner:     spinFunc,
-		logger:         l,
+		module:            module,
+		ctx:               ctx,
+		inputVars:         inputVars,
+		moduleMetadata:    moduleMetadata,
+		moduleCalls:       map[string]*ModuleCall{},
+		visitedModules:    visitedModules,
+		workspace:         workspace,
+		workingDir:        workingDir,
+		blockBuilder:      blockBuilder,
+		remoteStateLoader: remoteStateLoader,
+		newSpinner:        spinFunc,
+		logger:            l,
 	}
 }
 
@@ -326,6 +331,7 @@ func (e *Evaluator) evaluateModules() {
 			map[string]map[string]cty.Value{},
 			e.workspace,
 			e.blockBuilder,
+			e.remoteStateLoader,
 			nil,
 			e.logger,
 		)
@@ -683,23 +689,48 @@ func (e *Evaluator) evaluateResource(b *Block, values map[string]cty.Value) cty.
 		valueMap = make(map[string]cty.Value)
 	}
 
+	blockValues := e.blockValues(b)
+
 	if k := b.Key(); k != nil {
 		e.logger.Debugf("expanding block %s to be available for for_each key %s", b.FullName(), *k)
-		valueMap[stripCount(labels[1])] = e.expandedEachBlockToValue(b, valueMap)
+		valueMap[stripCount(labels[1])] = e.expandedEachBlockToValue(b, blockValues, valueMap)
 		return cty.ObjectVal(valueMap)
 	}
 
 	if k := b.Index(); k != nil {
 		e.logger.Debugf("expanding block %s to be available for index key %d", b.FullName(), *k)
-		valueMap[stripCount(labels[1])] = expandCountBlockToValue(b, valueMap)
+		valueMap[stripCount(labels[1])] = expandCountBlockToValue(b, blockValues, valueMap)
 		return cty.ObjectVal(valueMap)
 	}
 
-	valueMap[b.Labels()[1]] = b.Values()
+	valueMap[b.Labels()[1]] = blockValues
+	return cty.ObjectVal(valueMap)
+}
+
+// blockValues returns the values of the resource or data Block b. The values of
+// terraform_remote_state data sources also have the outputs of the remote state,
+// if they can be read.
+func (e *Evaluator) blockValues(b *Block) cty.Value {
+	values := b.Values()
+	if e.remoteStateLoader == nil || b.Type() != "data" || b.TypeLabel() != remoteStateDataSource {
+		return values
+	}
+
+	outputs, ok := e.remoteStateLoader.Load(b, e.module.RootPath)
+	if !ok {
+		return values
+	}
+
+	valueMap := values.AsValueMap()
+	if valueMap == nil {
+		valueMap = make(map[string]cty.Value)
+	}
+
+	valueMap["outputs"] = outputs
 	return cty.ObjectVal(valueMap)
 }
 
-func expandCountBlockToValue(b *Block, existingValues map[string]cty.Value) cty.Value {
+func expandCountBlockToValue(b *Block, blockValues cty.Value, existingValues map[string]cty.Value) cty.Value {
 	k := b.Index()
 	if k == nil {
 		return cty.DynamicVal
@@ -718,11 +749,11 @@ func expandCountBlockToValue(b *Block, existingValues map[string]cty.Value) cty.
 		}
 	}
 
-	elements = append(elements, b.Values())
+	elements = append(elements, blockValues)
 	return cty.TupleVal(elements)
 }
 
-func (e *Evaluator) expandedEachBlockToValue(b *Block, existingValues map[string]cty.Value) cty.Value {
+func (e *Evaluator) expandedEachBlockToValue(b *Block, blockValues cty.Value, existingValues map[string]cty.Value) cty.Value {
 	k := b.Key()
 	if k == nil {
 		return cty.DynamicVal
@@ -738,7 +769,7 @@ func (e *Evaluator) expandedEachBlockToValue(b *Block, existingValues map[string
 				"block": b.Label(),
 			}).Debugf("skipping unexpected cty value type '%s' for existing for_each context value", eachMap.GoString())
 
-			ob[*k] = b.Values()
+			ob[*k] = blockValues
 			return cty.ObjectVal(ob)
 		}
 
@@ -747,7 +778,7 @@ func (e *Evaluator) expandedEachBlockToValue(b *Block, existingValues map[string
 		}
 	}
 
-	ob[*k] = b.Values()
+	ob[*k] = blockValues
 	return cty.ObjectVal(ob)
 }
 
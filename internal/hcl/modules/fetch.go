@@ -34,27 +34,7 @@ func (r *PackageFetcher) fetch(moduleAddr string, dest string) error {
 
 		r.logger.Debugf("module %s already downloaded, copying from '%s' to '%s'", moduleAddr, prevDest, dest)
 
-		err := os.Mkdir(dest, os.ModePerm)
-		if err != nil {
-			return fmt.Errorf("failed to create directory '%s': %w", dest, err)
-		}
-
-		// Skip dotfiles and create new symlinks to be consistent with what Terraform init does
-		opt := copy.Options{
-			Skip: func(src string) (bool, error) {
-				return strings.HasPrefix(filepath.Base(src), "."), nil
-			},
-			OnSymlink: func(src string) copy.SymlinkAction {
-				return copy.Shallow
-			},
-		}
-
-		err = copy.Copy(prevDest, dest, opt)
-		if err != nil {
-			return fmt.Errorf("failed to copy module from '%s' to '%s': %w", prevDest, dest, err)
-		}
-
-		return nil
+		return copyModule(prevDest, dest)
 	}
 	var cached []string
 	r.cache.Range(func(k, value any) bool {
@@ -95,3 +75,28 @@ func (r *PackageFetcher) fetch(moduleAddr string, dest string) error {
 
 	return nil
 }
+
+// copyModule copies a downloaded module from src to the dest directory.
+func copyModule(src string, dest string) error {
+	err := os.MkdirAll(dest, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("failed to create directory '%s': %w", dest, err)
+	}
+
+	// Skip dotfiles and create new symlinks to be consistent with what Terraform init does
+	opt := copy.Options{
+		Skip: func(src string) (bool, error) {
+			return strings.HasPrefix(filepath.Base(src), "."), nil
+		},
+		OnSymlink: func(src string) copy.SymlinkAction {
+			return copy.Shallow
+		},
+	}
+
+	err = copy.Copy(src, dest, opt)
+	if err != nil {
+		return fmt.Errorf("failed to copy module from '%s' to '%s': %w", src, dest, err)
+	}
+
+	return nil
+}
@@ -40,6 +40,12 @@ var (
 type ModuleLoader struct {
 	NewSpinner ui.SpinnerFunc
 
+	// SharedCacheDir is the directory that registry modules are downloaded to once
+	// and copied from into the cachePath of each project, so projects and runs that
+	// use the same module version don't download it again. Modules are downloaded
+	// straight into the cachePath when empty.
+	SharedCacheDir string
+
 	// cachePath is the path to the directory that Infracost will download modules to.
 	// This is normally the top level directory of a multi-project environment, where the
 	// Infracost config file resides or project auto-detection starts from.
@@ -297,7 +303,7 @@ func (m *ModuleLoader) loadModule(moduleCall *tfconfig.ModuleCall, parentPath st
 			return manifestModule, nil
 		}
 
-		err = m.registryLoader.downloadModule(lookupResult, dest)
+		err = m.downloadRegistryModule(lookupResult, dest)
 		if err != nil {
 			return nil, fmt.Errorf("failed to download registry module %s: %w", key, err)
 		}
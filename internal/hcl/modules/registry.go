@@ -301,18 +301,21 @@ func findLatestMatchingVersion(versions []string, constraints string) (string, e
 	// Loop through all the versions since they aren't necessarily sorted
 	// Skip any versions that are less than the current matching version
 	for _, rawVersion := range versions {
+		// Private registries can return versions that aren't valid semver, which Terraform
+		// ignores, so skip these rather than failing to resolve the module
 		version, err := goversion.NewVersion(rawVersion)
 		if err != nil {
-			return "", err
+			continue
 		}
 
 		if matchingVersion != nil && version.LessThan(matchingVersion) {
 			continue
 		}
 
-		// If there's no constraints then we want the latest version
+		// If there's no constraints then we want the latest version that isn't a pre-release,
+		// as Terraform only selects pre-releases when they are requested by an exact constraint.
 		// Otherwise we need to check if the version matches the constraints
-		if c.String() == "" || c.Check(version) {
+		if (c.String() == "" && version.Prerelease() == "") || (c.String() != "" && c.Check(version)) {
 			matchingVersion = version
 		}
 	}
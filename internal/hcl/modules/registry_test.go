@@ -30,6 +30,11 @@ func TestFindLatestMatchingVersion(t *testing.T) {
 		{versions, "~> 1.0.0, != 1.0.2", "1.0.1", false},
 		{versions, "> 1.1.2", "", true},
 		{[]string{}, "1.0.2", "", true},
+		{append([]string{"1.2.0-beta1"}, versions...), "", "1.1.2", false},
+		{append([]string{"1.2.0-beta1"}, versions...), ">= 1.1.0", "1.1.2", false},
+		{append([]string{"1.2.0-beta1"}, versions...), "1.2.0-beta1", "1.2.0-beta1", false},
+		{append([]string{"latest", "v2"}, versions...), "", "2.0.0", false},
+		{[]string{"latest"}, "", "", true},
 	}
 
 	for _, test := range tests {
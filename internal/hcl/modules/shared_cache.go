@@ -0,0 +1,64 @@
+package modules
+
+import (
+	"crypto/md5" //nolint
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// downloadRegistryModule downloads the registry module to dest. If the loader has
+// a SharedCacheDir, the module version is copied from it instead, and is
+// downloaded to it first if it isn't there yet.
+func (m *ModuleLoader) downloadRegistryModule(lookupResult *RegistryLookupResult, dest string) error {
+	if m.SharedCacheDir == "" {
+		return m.registryLoader.downloadModule(lookupResult, dest)
+	}
+
+	key := lookupResult.ModuleURL.RawSource + "@" + lookupResult.Version
+	return m.fetchShared(key, dest, func(dir string) error {
+		return m.registryLoader.downloadModule(lookupResult, dir)
+	})
+}
+
+// fetchShared copies the module with the given key from the shared cache to
+// dest, calling download to add it to the shared cache first if needed.
+// Modules are downloaded to a temporary directory and then renamed, so
+// concurrent runs never copy an incomplete download.
+func (m *ModuleLoader) fetchShared(key string, dest string, download func(dir string) error) error {
+	shared := filepath.Join(m.SharedCacheDir, fmt.Sprintf("%x", md5.Sum([]byte(key)))) //nolint
+
+	if _, err := os.Stat(shared); err == nil {
+		m.logger.Debugf("module %s found in shared cache '%s'", key, shared)
+		return copyModule(shared, dest)
+	}
+
+	err := os.MkdirAll(m.SharedCacheDir, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("failed to create shared module cache directory '%s': %w", m.SharedCacheDir, err)
+	}
+
+	tmp, err := os.MkdirTemp(m.SharedCacheDir, ".download-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory in '%s': %w", m.SharedCacheDir, err)
+	}
+	defer os.RemoveAll(tmp)
+
+	// go-getter expects to create the destination directory itself
+	dir := filepath.Join(tmp, "module")
+	err = download(dir)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(dir, shared)
+	if err != nil {
+		// Another run may have added the module to the shared cache in the meantime,
+		// in which case we can use its copy.
+		if _, statErr := os.Stat(shared); statErr != nil {
+			return fmt.Errorf("failed to move module to shared cache '%s': %w", shared, err)
+		}
+	}
+
+	return copyModule(shared, dest)
+}
@@ -0,0 +1,48 @@
+package modules
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/credentials"
+	sync2 "github.com/infracost/infracost/internal/sync"
+)
+
+func TestFetchShared(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	loader := NewModuleLoader(t.TempDir(), &CredentialsSource{FetchToken: credentials.FindTerraformCloudToken}, logrus.NewEntry(logger), &sync2.KeyMutex{})
+	loader.SharedCacheDir = t.TempDir()
+
+	downloads := 0
+	download := func(dir string) error {
+		downloads++
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`variable "name" {}`), 0600)
+	}
+
+	for _, project := range []string{"project1", "project2"} {
+		dest := filepath.Join(t.TempDir(), project)
+		err := loader.fetchShared("app.terraform.io/org/vpc/aws@1.2.0", dest, download)
+		require.NoError(t, err)
+
+		b, err := os.ReadFile(filepath.Join(dest, "main.tf"))
+		require.NoError(t, err)
+		assert.Equal(t, `variable "name" {}`, string(b))
+	}
+
+	assert.Equal(t, 1, downloads)
+
+	entries, err := os.ReadDir(loader.SharedCacheDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary download directories should be removed")
+}
@@ -150,6 +150,15 @@ func OptionWithRemoteVarLoader(host, token, localWorkspace string) Option {
 	}
 }
 
+// OptionWithRemoteStateLoader sets a RemoteStateLoader onto the Parser, which
+// evaluates the outputs of terraform_remote_state data sources from the state
+// of their backend. The outputs aren't evaluated without it.
+func OptionWithRemoteStateLoader(loader *RemoteStateLoader) Option {
+	return func(p *Parser) {
+		p.remoteStateLoader = loader
+	}
+}
+
 func OptionWithBlockBuilder(blockBuilder BlockBuilder) Option {
 	return func(p *Parser) {
 		p.blockBuilder = blockBuilder
@@ -199,6 +208,7 @@ type Parser struct {
 	blockBuilder          BlockBuilder
 	newSpinner            ui.SpinnerFunc
 	remoteVariablesLoader *RemoteVariablesLoader
+	remoteStateLoader     *RemoteStateLoader
 	logger                *logrus.Entry
 	hasChanges            bool
 }
@@ -342,6 +352,7 @@ func (p *Parser) ParseDirectory() (m *Module, err error) {
 		nil,
 		p.workspaceName,
 		p.blockBuilder,
+		p.remoteStateLoader,
 		p.newSpinner,
 		p.logger,
 	)
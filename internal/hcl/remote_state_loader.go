@@ -0,0 +1,434 @@
+package hcl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+	azstorage "github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+	ctyJson "github.com/zclconf/go-cty/cty/json"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+)
+
+const (
+	remoteStateDataSource = "terraform_remote_state"
+	remoteStateTimeout    = 30 * time.Second
+)
+
+// errRemoteStateConfigUnknown is returned when the config of a remote state
+// data source references values that aren't evaluated yet.
+var errRemoteStateConfigUnknown = errors.New("config of the remote state isn't known")
+
+// remoteStateFetcher returns the state file that a backend stores for workspace.
+type remoteStateFetcher func(ctx context.Context, r *RemoteStateLoader, config map[string]string, workspace string) ([]byte, error)
+
+// remoteStateFetchers are the backends that the state of terraform_remote_state
+// data sources can be read from.
+var remoteStateFetchers = map[string]remoteStateFetcher{
+	"azurerm": fetchAzureRMState,
+	"gcs":     fetchGCSState,
+	"local":   fetchLocalState,
+	"s3":      fetchS3State,
+}
+
+// RemoteStateLoader reads the outputs of terraform_remote_state data sources
+// from the backends that store the state, so that values derived from them,
+// e.g. instance counts, are evaluated instead of mocked. States are read with
+// the credentials of the environment and are cached, so each one is only read
+// once.
+type RemoteStateLoader struct {
+	env      map[string]string
+	fetchers map[string]remoteStateFetcher
+	logger   *logrus.Entry
+
+	mu    sync.Mutex
+	cache map[string]remoteStateResult
+}
+
+type remoteStateResult struct {
+	outputs cty.Value
+	err     error
+}
+
+type remoteStateFile struct {
+	Outputs map[string]struct {
+		Value json.RawMessage `json:"value"`
+		Type  json.RawMessage `json:"type"`
+	} `json:"outputs"`
+}
+
+// NewRemoteStateLoader returns a RemoteStateLoader that reads credentials from
+// env before the OS environment.
+func NewRemoteStateLoader(env map[string]string, logger *logrus.Entry) *RemoteStateLoader {
+	return &RemoteStateLoader{
+		env:      env,
+		fetchers: remoteStateFetchers,
+		logger:   logger,
+		cache:    make(map[string]remoteStateResult),
+	}
+}
+
+// Load returns the outputs of the terraform_remote_state data source b, merged
+// over its defaults. rootPath is the path of the root module, which the paths
+// of the local backend are relative to. It returns false if the outputs can't
+// be read, e.g. because there are no credentials for the backend, in which
+// case a warning is logged the first time.
+func (r *RemoteStateLoader) Load(b *Block, rootPath string) (cty.Value, bool) {
+	backend, conf, workspace, err := remoteStateConfig(b)
+	if err != nil {
+		if !errors.Is(err, errRemoteStateConfigUnknown) {
+			r.logger.WithError(err).Debugf("skipping remote state %s", b.FullName())
+		}
+
+		return cty.NilVal, false
+	}
+
+	if backend == "local" {
+		for _, k := range []string{"path", "workspace_dir"} {
+			if v, ok := conf[k]; ok && !filepath.IsAbs(v) {
+				conf[k] = filepath.Join(rootPath, v)
+			}
+		}
+	}
+
+	key := remoteStateCacheKey(backend, conf, workspace)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res, ok := r.cache[key]
+	if !ok {
+		res.outputs, res.err = r.load(backend, conf, workspace)
+		r.cache[key] = res
+
+		if res.err != nil {
+			r.logger.Warnf("Could not read the %s remote state of %s, its outputs will not be evaluated: %s", backend, b.FullName(), res.err)
+		}
+	}
+
+	if res.err != nil {
+		return cty.NilVal, false
+	}
+
+	return mergeRemoteStateDefaults(b, res.outputs), true
+}
+
+func (r *RemoteStateLoader) load(backend string, conf map[string]string, workspace string) (cty.Value, error) {
+	fetch, ok := r.fetchers[backend]
+	if !ok {
+		return cty.NilVal, fmt.Errorf("backend %s is not supported", backend)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteStateTimeout)
+	defer cancel()
+
+	r.logger.Debugf("reading %s remote state for workspace %s", backend, workspace)
+	b, err := fetch(ctx, r, conf, workspace)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return parseRemoteStateOutputs(b)
+}
+
+// getenv returns the value of the env var k from the env of the loader or
+// the OS environment.
+func (r *RemoteStateLoader) getenv(k string) string {
+	if v, ok := r.env[k]; ok {
+		return v
+	}
+
+	return os.Getenv(k)
+}
+
+// remoteStateConfig returns the backend, backend config and workspace of the
+// terraform_remote_state data source b.
+func remoteStateConfig(b *Block) (string, map[string]string, string, error) {
+	backendAttr := b.GetAttribute("backend")
+	if backendAttr == nil {
+		return "", nil, "", errors.New("backend is not set")
+	}
+
+	backendVal := backendAttr.Value()
+	if !backendVal.IsKnown() || backendVal.IsNull() || backendVal.Type() != cty.String {
+		return "", nil, "", errRemoteStateConfigUnknown
+	}
+
+	workspace := defaultTerraformWorkspaceName
+	if attr := b.GetAttribute("workspace"); attr != nil {
+		v := attr.Value()
+		if !v.IsKnown() || v.IsNull() || v.Type() != cty.String {
+			return "", nil, "", errRemoteStateConfigUnknown
+		}
+
+		workspace = v.AsString()
+	}
+
+	conf := make(map[string]string)
+	if attr := b.GetAttribute("config"); attr != nil {
+		v := attr.Value()
+		if !v.IsWhollyKnown() {
+			return "", nil, "", errRemoteStateConfigUnknown
+		}
+
+		if v.CanIterateElements() {
+			for k, el := range v.AsValueMap() {
+				if el.IsNull() || !el.Type().IsPrimitiveType() {
+					continue
+				}
+
+				s, err := convertToString(el)
+				if err != nil {
+					return "", nil, "", fmt.Errorf("config %s is invalid: %w", k, err)
+				}
+				conf[k] = s
+			}
+		}
+	}
+
+	return backendVal.AsString(), conf, workspace, nil
+}
+
+func convertToString(v cty.Value) (string, error) {
+	switch v.Type() {
+	case cty.String:
+		return v.AsString(), nil
+	case cty.Bool:
+		return fmt.Sprintf("%t", v.True()), nil
+	case cty.Number:
+		return v.AsBigFloat().Text('f', -1), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %s", v.Type().FriendlyName())
+}
+
+func remoteStateCacheKey(backend string, conf map[string]string, workspace string) string {
+	keys := make([]string, 0, len(conf))
+	for k := range conf {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\x00%s", backend, workspace)
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "\x00%s=%s", k, conf[k])
+	}
+
+	return sb.String()
+}
+
+// parseRemoteStateOutputs returns the outputs of the state file b as an object.
+func parseRemoteStateOutputs(b []byte) (cty.Value, error) {
+	var state remoteStateFile
+	if err := json.Unmarshal(b, &state); err != nil {
+		return cty.NilVal, fmt.Errorf("invalid state file: %w", err)
+	}
+
+	outputs := make(map[string]cty.Value, len(state.Outputs))
+	for name, output := range state.Outputs {
+		ty, err := ctyJson.UnmarshalType(output.Type)
+		if len(output.Type) == 0 || err != nil {
+			ty, err = ctyJson.ImpliedType(output.Value)
+			if err != nil {
+				return cty.NilVal, fmt.Errorf("invalid value of output %s: %w", name, err)
+			}
+		}
+
+		v, err := ctyJson.Unmarshal(output.Value, ty)
+		if err != nil {
+			return cty.NilVal, fmt.Errorf("invalid value of output %s: %w", name, err)
+		}
+
+		outputs[name] = v
+	}
+
+	return cty.ObjectVal(outputs), nil
+}
+
+// mergeRemoteStateDefaults returns the outputs merged over the defaults
+// attribute of b, which Terraform uses for outputs that aren't in the state.
+func mergeRemoteStateDefaults(b *Block, outputs cty.Value) cty.Value {
+	attr := b.GetAttribute("defaults")
+	if attr == nil {
+		return outputs
+	}
+
+	defaults := attr.Value()
+	if !defaults.IsWhollyKnown() || defaults.IsNull() || !defaults.CanIterateElements() {
+		return outputs
+	}
+
+	merged := defaults.AsValueMap()
+	if merged == nil {
+		return outputs
+	}
+
+	for k, v := range outputs.AsValueMap() {
+		merged[k] = v
+	}
+
+	return cty.ObjectVal(merged)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func fetchLocalState(_ context.Context, _ *RemoteStateLoader, conf map[string]string, workspace string) ([]byte, error) {
+	p := firstNonEmpty(conf["path"], "terraform.tfstate")
+	if workspace != defaultTerraformWorkspaceName {
+		p = filepath.Join(firstNonEmpty(conf["workspace_dir"], "terraform.tfstate.d"), workspace, "terraform.tfstate")
+	}
+
+	return os.ReadFile(p)
+}
+
+func fetchS3State(ctx context.Context, r *RemoteStateLoader, conf map[string]string, workspace string) ([]byte, error) {
+	bucket, key := conf["bucket"], conf["key"]
+	if bucket == "" || key == "" {
+		return nil, errors.New("bucket and key must be set")
+	}
+
+	if workspace != defaultTerraformWorkspaceName {
+		prefix, ok := conf["workspace_key_prefix"]
+		if !ok {
+			prefix = "env:"
+		}
+
+		key = path.Join(prefix, workspace, key)
+	}
+
+	region := firstNonEmpty(conf["region"], r.getenv("AWS_REGION"), r.getenv("AWS_DEFAULT_REGION"), "us-east-1")
+	opts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(region)}
+
+	if profile := firstNonEmpty(conf["profile"], r.getenv("AWS_PROFILE")); profile != "" {
+		opts = append(opts, awsconfig.WithSharedConfigProfile(profile))
+	}
+
+	accessKey := firstNonEmpty(conf["access_key"], r.getenv("AWS_ACCESS_KEY_ID"))
+	secretKey := firstNonEmpty(conf["secret_key"], r.getenv("AWS_SECRET_ACCESS_KEY"))
+	if accessKey != "" && secretKey != "" {
+		token := firstNonEmpty(conf["token"], r.getenv("AWS_SESSION_TOKEN"))
+		opts = append(opts, awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secretKey, token)))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if roleARN := conf["role_arn"]; roleARN != "" {
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), roleARN))
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.UsePathStyle = conf["force_path_style"] == "true"
+	})
+
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+
+	return io.ReadAll(out.Body)
+}
+
+func fetchGCSState(ctx context.Context, r *RemoteStateLoader, conf map[string]string, workspace string) ([]byte, error) {
+	bucket := conf["bucket"]
+	if bucket == "" {
+		return nil, errors.New("bucket must be set")
+	}
+
+	opts := []option.ClientOption{option.WithScopes(storage.ScopeReadOnly)}
+
+	if token := firstNonEmpty(conf["access_token"], r.getenv("GOOGLE_OAUTH_ACCESS_TOKEN")); token != "" {
+		opts = append(opts, option.WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})))
+	} else if creds := firstNonEmpty(conf["credentials"], r.getenv("GOOGLE_BACKEND_CREDENTIALS"), r.getenv("GOOGLE_CREDENTIALS")); creds != "" {
+		// Like Terraform, credentials are either the path of a key file or its
+		// contents.
+		if _, err := os.Stat(creds); err == nil {
+			opts = append(opts, option.WithCredentialsFile(creds))
+		} else {
+			opts = append(opts, option.WithCredentialsJSON([]byte(creds)))
+		}
+	}
+
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	rd, err := client.Bucket(bucket).Object(path.Join(conf["prefix"], workspace+".tfstate")).NewReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+
+	return io.ReadAll(rd)
+}
+
+func fetchAzureRMState(_ context.Context, r *RemoteStateLoader, conf map[string]string, workspace string) ([]byte, error) {
+	account, container, key := conf["storage_account_name"], conf["container_name"], conf["key"]
+	if account == "" || container == "" || key == "" {
+		return nil, errors.New("storage_account_name, container_name and key must be set")
+	}
+
+	if workspace != defaultTerraformWorkspaceName {
+		key += "env:" + workspace
+	}
+
+	var client azstorage.Client
+	var err error
+
+	if accessKey := firstNonEmpty(conf["access_key"], r.getenv("ARM_ACCESS_KEY")); accessKey != "" {
+		client, err = azstorage.NewBasicClient(account, accessKey)
+	} else if sasToken := firstNonEmpty(conf["sas_token"], r.getenv("ARM_SAS_TOKEN")); sasToken != "" {
+		endpoint := fmt.Sprintf("https://%s.blob.%s", account, azstorage.DefaultBaseURL)
+		client, err = azstorage.NewAccountSASClientFromEndpointToken(endpoint, strings.TrimPrefix(sasToken, "?"))
+	} else {
+		return nil, errors.New("an access_key or sas_token is needed to read azurerm state, set them in the config or with ARM_ACCESS_KEY or ARM_SAS_TOKEN")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	blobClient := client.GetBlobService()
+	rd, err := blobClient.GetContainerReference(container).GetBlobReference(key).Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+
+	return io.ReadAll(rd)
+}
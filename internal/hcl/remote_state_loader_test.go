@@ -0,0 +1,88 @@
+package hcl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/hcl/modules"
+	"github.com/infracost/infracost/internal/sync"
+)
+
+func TestRemoteStateLoader(t *testing.T) {
+	path := createTestFile("test.tf", `
+data "terraform_remote_state" "network" {
+  backend = "local"
+
+  config = {
+    path = "network.tfstate"
+  }
+
+  defaults = {
+    instance_count = 1
+    instance_type  = "t3.micro"
+  }
+}
+
+data "terraform_remote_state" "other" {
+  backend = "s3"
+
+  config = {
+    bucket = "state"
+    key    = "other.tfstate"
+  }
+}
+
+resource "aws_instance" "web" {
+  count         = data.terraform_remote_state.network.outputs.instance_count
+  instance_type = data.terraform_remote_state.network.outputs.instance_type
+  subnet_id     = data.terraform_remote_state.network.outputs.subnet_ids[0]
+  ami           = data.terraform_remote_state.other.outputs.ami
+}
+`)
+
+	err := os.WriteFile(filepath.Join(filepath.Dir(path), "network.tfstate"), []byte(`{
+  "version": 4,
+  "outputs": {
+    "instance_count": {"value": 3, "type": "number"},
+    "subnet_ids": {"value": ["subnet-a", "subnet-b"], "type": ["list", "string"]}
+  }
+}`), os.ModePerm)
+	require.NoError(t, err)
+
+	logger := newDiscardLogger()
+	loader := NewRemoteStateLoader(nil, logger)
+	loader.fetchers = map[string]remoteStateFetcher{
+		"local": fetchLocalState,
+		"s3": func(ctx context.Context, r *RemoteStateLoader, config map[string]string, workspace string) ([]byte, error) {
+			return nil, errors.New("no credentials")
+		},
+	}
+
+	parser := newParser(
+		RootPath{Path: filepath.Dir(path)},
+		modules.NewModuleLoader(filepath.Dir(path), nil, logger, &sync.KeyMutex{}),
+		logger,
+		OptionWithRemoteStateLoader(loader),
+	)
+	module, err := parser.ParseDirectory()
+	require.NoError(t, err)
+
+	var instances []*Block
+	for _, b := range module.Blocks.OfType("resource") {
+		if b.TypeLabel() == "aws_instance" {
+			instances = append(instances, b)
+		}
+	}
+	require.Len(t, instances, 3)
+
+	web := instances[0]
+	assert.Equal(t, "t3.micro", web.GetAttribute("instance_type").Value().AsString())
+	assert.Equal(t, "subnet-a", web.GetAttribute("subnet_id").Value().AsString())
+	assert.Equal(t, "ami-mock", web.GetAttribute("ami").Value().AsString())
+}
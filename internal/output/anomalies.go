@@ -0,0 +1,215 @@
+package output
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// costHistoryVersion is the version of the cost history file format.
+const costHistoryVersion = "0.1"
+
+// minAnomalyStdDevRuns is the number of previous runs that a resource needs
+// before its cost is compared with the standard deviation of its history.
+const minAnomalyStdDevRuns = 3
+
+// CostHistory is the monthly costs of the resources in previous runs, used to
+// find resources whose cost changes unexpectedly between runs, e.g. because
+// of price or usage changes, rather than because of a planned change.
+type CostHistory struct {
+	Version string           `json:"version"`
+	Runs    []CostHistoryRun `json:"runs"`
+}
+
+// CostHistoryRun is the monthly costs of the resources in a run, by project
+// name and resource name.
+type CostHistoryRun struct {
+	TimeGenerated time.Time                             `json:"timeGenerated"`
+	Projects      map[string]map[string]decimal.Decimal `json:"projects"`
+}
+
+// NewCostHistoryRun returns the monthly costs of the resources of r.
+func NewCostHistoryRun(r Root) CostHistoryRun {
+	run := CostHistoryRun{
+		TimeGenerated: r.TimeGenerated,
+		Projects:      make(map[string]map[string]decimal.Decimal, len(r.Projects)),
+	}
+
+	for _, p := range r.Projects {
+		costs := make(map[string]decimal.Decimal)
+		for _, res := range breakdownResources(p.Breakdown) {
+			if res.MonthlyCost != nil {
+				costs[res.Name] = *res.MonthlyCost
+			}
+		}
+
+		run.Projects[p.Name] = costs
+	}
+
+	return run
+}
+
+// AddRun adds the run to the history, keeping at most maxRuns of the latest
+// runs if maxRuns is positive.
+func (h *CostHistory) AddRun(run CostHistoryRun, maxRuns int) {
+	h.Version = costHistoryVersion
+	h.Runs = append(h.Runs, run)
+
+	if maxRuns > 0 && len(h.Runs) > maxRuns {
+		h.Runs = h.Runs[len(h.Runs)-maxRuns:]
+	}
+}
+
+// AnomalyThresholds set which cost changes between runs are anomalies. A
+// resource is an anomaly if its cost is more than StdDevs standard deviations
+// from the mean of its history, or if it changed more than Percent since the
+// previous run. Zero values disable the threshold.
+type AnomalyThresholds struct {
+	StdDevs float64
+	Percent float64
+}
+
+// CostAnomaly is a resource whose monthly cost changed more than the anomaly
+// thresholds compared with the cost history.
+type CostAnomaly struct {
+	ProjectName         string           `json:"projectName"`
+	ResourceName        string           `json:"resourceName"`
+	MonthlyCost         *decimal.Decimal `json:"monthlyCost"`
+	PreviousMonthlyCost *decimal.Decimal `json:"previousMonthlyCost"`
+	MeanMonthlyCost     *decimal.Decimal `json:"meanMonthlyCost"`
+	// PercentChange is the change since the previous run, or nil if the
+	// previous cost was zero.
+	PercentChange *float64 `json:"percentChange"`
+	// StdDevs is how many standard deviations the cost is from the mean of the
+	// history, or nil if there isn't enough history.
+	StdDevs *float64 `json:"stdDevs"`
+}
+
+// DetectCostAnomalies compares the monthly costs of the resources of r with
+// the history and adds the resources whose costs changed more than the
+// thresholds to r. Resources that aren't in the history aren't anomalies as
+// they're new.
+func DetectCostAnomalies(r *Root, h CostHistory, t AnomalyThresholds) {
+	var anomalies []CostAnomaly
+
+	for _, p := range r.Projects {
+		for _, res := range breakdownResources(p.Breakdown) {
+			if res.MonthlyCost == nil {
+				continue
+			}
+
+			var history []decimal.Decimal
+			for _, run := range h.Runs {
+				if c, ok := run.Projects[p.Name][res.Name]; ok {
+					history = append(history, c)
+				}
+			}
+
+			if len(history) == 0 {
+				continue
+			}
+
+			if a, ok := costAnomaly(*res.MonthlyCost, history, t); ok {
+				a.ProjectName = p.Name
+				a.ResourceName = res.Name
+				anomalies = append(anomalies, a)
+			}
+		}
+	}
+
+	r.CostAnomalies = anomalies
+}
+
+// costAnomaly returns the anomaly if cost changed more than the thresholds
+// compared with the history of the resource.
+func costAnomaly(cost decimal.Decimal, history []decimal.Decimal, t AnomalyThresholds) (CostAnomaly, bool) {
+	current := cost.InexactFloat64()
+	previous := history[len(history)-1].InexactFloat64()
+
+	values := make([]float64, len(history))
+	var sum float64
+	for i, c := range history {
+		values[i] = c.InexactFloat64()
+		sum += values[i]
+	}
+	mean := sum / float64(len(values))
+
+	a := CostAnomaly{
+		MonthlyCost:         decimalPtr(cost),
+		PreviousMonthlyCost: decimalPtr(history[len(history)-1]),
+		MeanMonthlyCost:     decimalPtr(decimal.NewFromFloat(mean).Round(2)),
+	}
+
+	isAnomaly := false
+
+	if previous != 0 {
+		percent := (current - previous) / math.Abs(previous) * 100
+		a.PercentChange = &percent
+
+		if t.Percent > 0 && math.Abs(percent) > t.Percent {
+			isAnomaly = true
+		}
+	} else if current != 0 && t.Percent > 0 {
+		isAnomaly = true
+	}
+
+	if len(history) >= minAnomalyStdDevRuns {
+		var sq float64
+		for _, v := range values {
+			sq += (v - mean) * (v - mean)
+		}
+		stdDev := math.Sqrt(sq / float64(len(values)))
+
+		// Costs that never changed have no deviation, so any change to them is
+		// only compared with the percent threshold.
+		if stdDev > 0 {
+			stdDevs := math.Abs(current-mean) / stdDev
+			a.StdDevs = &stdDevs
+
+			if t.StdDevs > 0 && stdDevs > t.StdDevs {
+				isAnomaly = true
+			}
+		}
+	}
+
+	return a, isAnomaly
+}
+
+// costAnomaliesMessage returns the resources whose costs changed unexpectedly
+// compared with the cost history.
+func (r *Root) costAnomaliesMessage() string {
+	if len(r.CostAnomalies) == 0 {
+		return ""
+	}
+
+	noun := "resources have"
+	if len(r.CostAnomalies) == 1 {
+		noun = "resource has"
+	}
+
+	msg := fmt.Sprintf("%d %s unusual cost changes compared with previous runs:", len(r.CostAnomalies), noun)
+	for _, a := range r.CostAnomalies {
+		msg += fmt.Sprintf("\n∙ %s: %s, was %s",
+			a.ResourceName,
+			FormatCost2DP(r.Currency, a.MonthlyCost),
+			FormatCost2DP(r.Currency, a.PreviousMonthlyCost),
+		)
+
+		var details []string
+		if a.PercentChange != nil {
+			details = append(details, fmt.Sprintf("%+.0f%%", *a.PercentChange))
+		}
+		if a.StdDevs != nil {
+			details = append(details, fmt.Sprintf("%.1f standard deviations from the mean of %s", *a.StdDevs, FormatCost2DP(r.Currency, a.MeanMonthlyCost)))
+		}
+
+		if len(details) > 0 {
+			msg += " (" + strings.Join(details, ", ") + ")"
+		}
+	}
+
+	return msg
+}
@@ -0,0 +1,73 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func historyRun(costs map[string]int64) CostHistoryRun {
+	resources := make(map[string]decimal.Decimal, len(costs))
+	for name, c := range costs {
+		resources[name] = decimal.NewFromInt(c)
+	}
+
+	return CostHistoryRun{Projects: map[string]map[string]decimal.Decimal{"infra": resources}}
+}
+
+func TestCostHistoryAddRun(t *testing.T) {
+	var h CostHistory
+	for i := int64(1); i <= 4; i++ {
+		h.AddRun(historyRun(map[string]int64{"aws_instance.web": i}), 3)
+	}
+
+	assert.Equal(t, "0.1", h.Version)
+	require.Len(t, h.Runs, 3)
+	assert.Equal(t, "2", h.Runs[0].Projects["infra"]["aws_instance.web"].String())
+	assert.Equal(t, "4", h.Runs[2].Projects["infra"]["aws_instance.web"].String())
+}
+
+func TestDetectCostAnomalies(t *testing.T) {
+	h := CostHistory{Runs: []CostHistoryRun{
+		historyRun(map[string]int64{"aws_instance.web": 100, "aws_instance.db": 100, "aws_s3_bucket.logs": 10}),
+		historyRun(map[string]int64{"aws_instance.web": 102, "aws_instance.db": 100, "aws_s3_bucket.logs": 30}),
+		historyRun(map[string]int64{"aws_instance.web": 98, "aws_instance.db": 100, "aws_s3_bucket.logs": 11}),
+	}}
+
+	root := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name: "infra",
+				Breakdown: &Breakdown{Resources: []Resource{
+					// 6.1 standard deviations from the mean but only 12% more.
+					{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(110))},
+					// The cost never changed so there's no deviation.
+					{Name: "aws_instance.db", MonthlyCost: decimalPtr(decimal.NewFromInt(120))},
+					// 0.3 standard deviations from the mean but 82% more.
+					{Name: "aws_s3_bucket.logs", MonthlyCost: decimalPtr(decimal.NewFromInt(20))},
+					// New resources aren't anomalies.
+					{Name: "aws_instance.new", MonthlyCost: decimalPtr(decimal.NewFromInt(500))},
+				}},
+			},
+		},
+	}
+
+	DetectCostAnomalies(&root, h, AnomalyThresholds{StdDevs: 3, Percent: 50})
+
+	require.Len(t, root.CostAnomalies, 2)
+	assert.Equal(t, "aws_instance.web", root.CostAnomalies[0].ResourceName)
+	assert.Equal(t, "aws_s3_bucket.logs", root.CostAnomalies[1].ResourceName)
+	assert.Equal(t, "infra", root.CostAnomalies[1].ProjectName)
+	assert.Equal(t, "11", root.CostAnomalies[1].PreviousMonthlyCost.String())
+
+	assert.Equal(t, `2 resources have unusual cost changes compared with previous runs:
+∙ aws_instance.web: $110.00, was $98.00 (+12%, 6.1 standard deviations from the mean of $100.00)
+∙ aws_s3_bucket.logs: $20.00, was $11.00 (+82%, 0.3 standard deviations from the mean of $17.00)`, root.costAnomaliesMessage())
+
+	DetectCostAnomalies(&root, h, AnomalyThresholds{Percent: 10})
+	require.Len(t, root.CostAnomalies, 3)
+	assert.Equal(t, "aws_instance.db", root.CostAnomalies[1].ResourceName)
+}
@@ -0,0 +1,152 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+// BudgetFailure is a budget from the config file that the estimate exceeds.
+type BudgetFailure struct {
+	Budget  string `json:"budget"`
+	Message string `json:"message"`
+}
+
+// CheckBudgets returns an error if the estimated monthly cost or the monthly
+// cost increase exceeds any of the budgets, so CI runs can fail on costs
+// without parsing the JSON output. The failures are added to r so they're
+// included in the JSON, table and diff output formats.
+func CheckBudgets(r *Root, budgets []config.Budget) error {
+	var failures []BudgetFailure
+
+	for _, b := range budgets {
+		cost, pastCost, ok := budgetCosts(r, b)
+		if !ok {
+			continue
+		}
+
+		if b.MonthlyCost != nil && cost.GreaterThan(*b.MonthlyCost) {
+			failures = append(failures, BudgetFailure{
+				Budget: b.Label(),
+				Message: fmt.Sprintf("monthly cost of %s is over the budget of %s",
+					FormatCost2DP(r.Currency, &cost),
+					FormatCost2DP(r.Currency, b.MonthlyCost),
+				),
+			})
+		}
+
+		if b.MonthlyCostIncrease != nil && pastCost != nil {
+			increase := cost.Sub(*pastCost)
+			if increase.GreaterThan(*b.MonthlyCostIncrease) {
+				failures = append(failures, BudgetFailure{
+					Budget: b.Label(),
+					Message: fmt.Sprintf("monthly cost increase of %s is over the limit of %s",
+						FormatCost2DP(r.Currency, &increase),
+						FormatCost2DP(r.Currency, b.MonthlyCostIncrease),
+					),
+				})
+			}
+		}
+	}
+
+	r.BudgetFailures = failures
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return errors.New(r.budgetMessage())
+}
+
+// budgetCosts returns the monthly cost that the budget applies to, and the
+// past monthly cost if the estimate is a diff. It returns false if the budget
+// doesn't apply to any of the projects.
+func budgetCosts(r *Root, b config.Budget) (decimal.Decimal, *decimal.Decimal, bool) {
+	if b.Project == "" && b.Provider == "" {
+		var pastCost *decimal.Decimal
+		if r.PastTotalMonthlyCost != nil {
+			pastCost = decimalPtr(*r.PastTotalMonthlyCost)
+		}
+
+		return decimalValue(r.TotalMonthlyCost), pastCost, true
+	}
+
+	cost := decimal.Zero
+	var pastCost *decimal.Decimal
+	matched := false
+
+	for _, p := range r.Projects {
+		if b.Project != "" && !budgetMatchesProject(b.Project, p) {
+			continue
+		}
+		matched = true
+
+		if p.Breakdown != nil {
+			cost = cost.Add(budgetBreakdownCost(b, p.Breakdown))
+		}
+
+		if p.PastBreakdown != nil {
+			pastCost = decimalPtr(decimalValue(pastCost).Add(budgetBreakdownCost(b, p.PastBreakdown)))
+		}
+	}
+
+	return cost, pastCost, matched
+}
+
+// budgetBreakdownCost returns the monthly cost of the breakdown that the budget
+// applies to.
+func budgetBreakdownCost(b config.Budget, breakdown *Breakdown) decimal.Decimal {
+	if b.Provider == "" {
+		return decimalValue(breakdown.TotalMonthlyCost)
+	}
+
+	cost := decimal.Zero
+	for _, res := range breakdown.Resources {
+		if resourceProvider(res.ResourceType()) == b.Provider {
+			cost = cost.Add(decimalValue(res.MonthlyCost))
+		}
+	}
+
+	return cost
+}
+
+// budgetMatchesProject returns true if the glob pattern matches the name or the
+// path of the project.
+func budgetMatchesProject(pattern string, p Project) bool {
+	if ok, _ := path.Match(pattern, p.Name); ok {
+		return true
+	}
+
+	if p.Metadata != nil && p.Metadata.Path != "" {
+		projectPath := path.Clean(strings.TrimPrefix(p.Metadata.Path, "./"))
+		if ok, _ := path.Match(path.Clean(strings.TrimPrefix(pattern, "./")), projectPath); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// budgetMessage returns the budgets that the estimate exceeds.
+func (r *Root) budgetMessage() string {
+	if len(r.BudgetFailures) == 0 {
+		return ""
+	}
+
+	noun := "budgets were"
+	if len(r.BudgetFailures) == 1 {
+		noun = "budget was"
+	}
+
+	msg := fmt.Sprintf("%d %s exceeded:", len(r.BudgetFailures), noun)
+	for _, f := range r.BudgetFailures {
+		msg += fmt.Sprintf("\n∙ %s: %s", f.Budget, f.Message)
+	}
+
+	return msg
+}
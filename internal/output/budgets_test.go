@@ -0,0 +1,69 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestCheckBudgets(t *testing.T) {
+	cost := func(i int64) *decimal.Decimal {
+		return decimalPtr(decimal.NewFromInt(i))
+	}
+
+	root := Root{
+		Currency:             "USD",
+		TotalMonthlyCost:     cost(1500),
+		PastTotalMonthlyCost: cost(900),
+		Projects: []Project{
+			{
+				Name:          "my-org/my-repo/prod",
+				Metadata:      &schema.ProjectMetadata{Path: "./envs/prod"},
+				PastBreakdown: &Breakdown{TotalMonthlyCost: cost(800), Resources: []Resource{{Name: "aws_instance.web", MonthlyCost: cost(800)}}},
+				Breakdown: &Breakdown{TotalMonthlyCost: cost(1300), Resources: []Resource{
+					{Name: "aws_instance.web", MonthlyCost: cost(1000)},
+					{Name: "google_compute_instance.worker", MonthlyCost: cost(300)},
+				}},
+			},
+			{
+				Name:          "my-org/my-repo/dev",
+				Metadata:      &schema.ProjectMetadata{Path: "./envs/dev"},
+				PastBreakdown: &Breakdown{TotalMonthlyCost: cost(100)},
+				Breakdown:     &Breakdown{TotalMonthlyCost: cost(200)},
+			},
+		},
+	}
+
+	err := CheckBudgets(&root, []config.Budget{
+		{MonthlyCost: cost(2000), MonthlyCostIncrease: cost(500)},
+		{Project: "envs/prod", MonthlyCost: cost(1000)},
+		{Project: "my-org/my-repo/dev", MonthlyCost: cost(1000), MonthlyCostIncrease: cost(200)},
+		{Project: "other/*", MonthlyCost: cost(1)},
+		{Provider: "google", MonthlyCostIncrease: cost(250)},
+	})
+	require.Error(t, err)
+
+	assert.Equal(t, []BudgetFailure{
+		{Budget: "total", Message: "monthly cost increase of $600.00 is over the limit of $500.00"},
+		{Budget: "project envs/prod", Message: "monthly cost of $1,300.00 is over the budget of $1,000.00"},
+		{Budget: "provider google", Message: "monthly cost increase of $300.00 is over the limit of $250.00"},
+	}, root.BudgetFailures)
+
+	assert.Equal(t, `3 budgets were exceeded:
+∙ total: monthly cost increase of $600.00 is over the limit of $500.00
+∙ project envs/prod: monthly cost of $1,300.00 is over the budget of $1,000.00
+∙ provider google: monthly cost increase of $300.00 is over the limit of $250.00`, err.Error())
+
+	root.PastTotalMonthlyCost = nil
+	for i := range root.Projects {
+		root.Projects[i].PastBreakdown = nil
+	}
+
+	assert.NoError(t, CheckBudgets(&root, []config.Budget{{MonthlyCost: cost(2000), MonthlyCostIncrease: cost(1)}}))
+	assert.Empty(t, root.BudgetFailures)
+}
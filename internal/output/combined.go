@@ -3,6 +3,7 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -199,6 +200,15 @@ func Combine(inputs []ReportInput) (Root, error) {
 
 	projects := make([]Project, 0)
 	summaries := make([]*Summary, 0, len(inputs))
+	resourceSummaries := make([]*Summary, 0, len(inputs))
+	var missingUsage []MissingUsage
+	var unknownInputs []UnknownInput
+	var unsupportedResources []UnsupportedResource
+	var tagPolicyViolations []TagPolicyViolation
+	var budgetFailures []BudgetFailure
+	var ignoredBudgetFailures []IgnoredFailure
+	var costAnomalies []CostAnomaly
+	var emissions *Emissions
 	currency := ""
 
 	var metadata Metadata
@@ -215,6 +225,26 @@ func Combine(inputs []ReportInput) (Root, error) {
 
 		summaries = append(summaries, input.Root.Summary)
 
+		if input.Root.ResourceSummary != nil {
+			resourceSummaries = append(resourceSummaries, input.Root.ResourceSummary)
+		}
+
+		missingUsage = append(missingUsage, input.Root.MissingUsage...)
+		unknownInputs = append(unknownInputs, input.Root.UnknownInputs...)
+		unsupportedResources = append(unsupportedResources, input.Root.UnsupportedResources...)
+		tagPolicyViolations = append(tagPolicyViolations, input.Root.TagPolicyViolations...)
+		budgetFailures = append(budgetFailures, input.Root.BudgetFailures...)
+		ignoredBudgetFailures = append(ignoredBudgetFailures, input.Root.IgnoredBudgetFailures...)
+		costAnomalies = append(costAnomalies, input.Root.CostAnomalies...)
+
+		if input.Root.Emissions != nil {
+			if emissions == nil {
+				emissions = &Emissions{TotalMonthlyKgCO2e: decimalPtr(decimal.Zero)}
+			}
+			emissions.TotalMonthlyKgCO2e = decimalPtr(emissions.TotalMonthlyKgCO2e.Add(decimalValue(input.Root.Emissions.TotalMonthlyKgCO2e)))
+			emissions.Projects = append(emissions.Projects, input.Root.Emissions.Projects...)
+		}
+
 		if input.Root.TotalHourlyCost != nil {
 			if totalHourlyCost == nil {
 				totalHourlyCost = decimalPtr(decimal.Zero)
@@ -271,6 +301,8 @@ func Combine(inputs []ReportInput) (Root, error) {
 	combined.Currency = currency
 	combined.Projects = projects
 	combined.TotalHourlyCost = totalHourlyCost
+	combined.TotalLowMonthlyCost, combined.TotalHighMonthlyCost = projectsCostRange(projects)
+	combined.Projections = projectsProjections(projects)
 	combined.TotalMonthlyCost = totalMonthlyCost
 	combined.PastTotalHourlyCost = pastTotalHourlyCost
 	combined.PastTotalMonthlyCost = pastTotalMonthlyCost
@@ -278,6 +310,17 @@ func Combine(inputs []ReportInput) (Root, error) {
 	combined.DiffTotalMonthlyCost = diffTotalMonthlyCost
 	combined.TimeGenerated = time.Now().UTC()
 	combined.Summary = MergeSummaries(summaries)
+	if len(resourceSummaries) > 0 {
+		combined.ResourceSummary = MergeSummaries(resourceSummaries)
+	}
+	combined.MissingUsage = missingUsage
+	combined.UnknownInputs = unknownInputs
+	combined.UnsupportedResources = unsupportedResources
+	combined.TagPolicyViolations = tagPolicyViolations
+	combined.BudgetFailures = budgetFailures
+	combined.IgnoredBudgetFailures = ignoredBudgetFailures
+	combined.CostAnomalies = costAnomalies
+	combined.Emissions = emissions
 	combined.Metadata = metadata
 
 	if invalidMetadata {
@@ -315,14 +358,48 @@ func checkOutputVersion(v string) bool {
 	return semver.Compare(v, "v"+minOutputVersion) >= 0 && semver.Compare(v, "v"+maxOutputVersion) <= 0
 }
 
+// WriteOutput writes Root r to w as the format specified. The JSON format is
+// written to w one resource at a time, the other formats are generated with
+// FormatOutput first.
+func WriteOutput(w io.Writer, format string, r Root, opts Options) error {
+	if format != "json" {
+		b, err := FormatOutput(format, r, opts)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(b)
+		return err
+	}
+
+	r = prepareOutput(r, opts)
+
+	err := WriteJSON(w, r, opts)
+	if err != nil {
+		return fmt.Errorf("error generating %s output %w", format, err)
+	}
+
+	return nil
+}
+
+func prepareOutput(r Root, opts Options) Root {
+	if opts.CurrencyFormat != "" {
+		addCurrencyFormat(opts.CurrencyFormat)
+	}
+
+	if opts.RollUpInstances {
+		r = RollUpInstances(r)
+	}
+
+	return r
+}
+
 // FormatOutput returns Root r as the format specified. The default format is a table output.
 func FormatOutput(format string, r Root, opts Options) ([]byte, error) {
 	var b []byte
 	var err error
 
-	if opts.CurrencyFormat != "" {
-		addCurrencyFormat(opts.CurrencyFormat)
-	}
+	r = prepareOutput(r, opts)
 
 	switch format {
 	case "json":
@@ -341,6 +418,22 @@ func FormatOutput(format string, r Root, opts Options) ([]byte, error) {
 		b, err = ToMarkdown(r, opts, MarkdownOptions{BasicSyntax: true, OmitDetails: true})
 	case "slack-message":
 		b, err = ToSlackMessage(r, opts)
+	case "teams-card":
+		b, err = ToTeamsCard(r, opts)
+	case "focus":
+		b, err = ToFOCUS(r, opts)
+	case "csv":
+		b, err = ToCSV(r, opts)
+	case "xlsx":
+		b, err = ToXLSX(r, opts)
+	case "junit":
+		b, err = ToJUnit(r, opts)
+	case "sarif":
+		b, err = ToSARIF(r, opts)
+	case "prometheus":
+		b, err = ToPrometheus(r, opts)
+	case "template":
+		b, err = ToTemplate(r, opts)
 	default:
 		b, err = ToTable(r, opts)
 	}
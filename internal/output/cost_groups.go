@@ -0,0 +1,231 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	groupByModule = "module"
+	groupByTag    = "tag"
+
+	// rootModuleCostGroup is the group of resources that aren't in a module.
+	rootModuleCostGroup = "Root module"
+	// untaggedCostGroup is the group of resources that don't have the tag.
+	untaggedCostGroup = "Untagged"
+)
+
+// CostGroup is the subtotal of the resources across all projects that are in
+// the same group, e.g. the same Terraform module or with the same tag value.
+type CostGroup struct {
+	Name          string           `json:"name"`
+	ResourceCount int              `json:"resourceCount"`
+	MonthlyCost   *decimal.Decimal `json:"monthlyCost"`
+}
+
+// groupBy is a parsed --group-by value.
+type groupBy struct {
+	// tagKey is the tag or label that resources are grouped by the value of.
+	// Resources are grouped by module if it's empty.
+	tagKey string
+	// moduleDepth is the number of nested modules that resources are grouped
+	// by, or 0 for the full module path.
+	moduleDepth int
+}
+
+// ValidateGroupBy returns an error if s isn't a valid --group-by value. The
+// valid values are module, or module:<depth> to only group by the first depth
+// nested modules, and tag:<key> to group by the value of a tag or label.
+func ValidateGroupBy(s string) error {
+	_, err := parseGroupBy(s)
+	return err
+}
+
+func parseGroupBy(s string) (groupBy, error) {
+	kind, arg, hasArg := strings.Cut(s, ":")
+
+	switch kind {
+	case groupByModule:
+		if !hasArg {
+			return groupBy{}, nil
+		}
+
+		depth, err := strconv.Atoi(arg)
+		if err != nil || depth < 1 {
+			return groupBy{}, fmt.Errorf("Invalid module depth '%s' in --group-by, the depth must be a number greater than 0", arg)
+		}
+
+		return groupBy{moduleDepth: depth}, nil
+	case groupByTag:
+		if strings.TrimSpace(arg) == "" {
+			return groupBy{}, errors.New("Missing tag key in --group-by, use tag:<key>, e.g. tag:team")
+		}
+
+		return groupBy{tagKey: arg}, nil
+	}
+
+	return groupBy{}, fmt.Errorf("Invalid --group-by value '%s', supported values are module, module:<depth> and tag:<key>", s)
+}
+
+// name returns the name of the group that the resource is in.
+func (g groupBy) name(r Resource) string {
+	if g.tagKey != "" {
+		if v := r.Tags[g.tagKey]; v != "" {
+			return v
+		}
+
+		return untaggedCostGroup
+	}
+
+	segments := moduleSegments(r.Name)
+	if len(segments) == 0 {
+		return rootModuleCostGroup
+	}
+
+	if g.moduleDepth > 0 && len(segments) > g.moduleDepth {
+		segments = segments[:g.moduleDepth]
+	}
+
+	return strings.Join(segments, ".")
+}
+
+// ungrouped returns the name of the group of resources that don't have a
+// value to group by.
+func (g groupBy) ungrouped() string {
+	if g.tagKey != "" {
+		return untaggedCostGroup
+	}
+
+	return rootModuleCostGroup
+}
+
+// title returns the title of the cost groups in the table output format.
+func (g groupBy) title() string {
+	if g.tagKey != "" {
+		return fmt.Sprintf("Monthly cost by %s tag:", g.tagKey)
+	}
+
+	if g.moduleDepth > 0 {
+		return fmt.Sprintf("Monthly cost by module (depth %d):", g.moduleDepth)
+	}
+
+	return "Monthly cost by module:"
+}
+
+// costGroups returns the subtotals of the resources of all the projects by the
+// group of each resource, sorted by cost with the ungrouped resources last.
+func costGroups(projects Projects, g groupBy) []CostGroup {
+	groups := make(map[string]*CostGroup)
+
+	for _, p := range projects {
+		if p.Breakdown == nil {
+			continue
+		}
+
+		for _, r := range p.Breakdown.Resources {
+			name := g.name(r)
+
+			c, ok := groups[name]
+			if !ok {
+				c = &CostGroup{Name: name}
+				groups[name] = c
+			}
+
+			c.ResourceCount++
+			if r.MonthlyCost != nil {
+				c.MonthlyCost = decimalPtr(decimalValue(c.MonthlyCost).Add(*r.MonthlyCost))
+			}
+		}
+	}
+
+	arr := make([]CostGroup, 0, len(groups))
+	for _, c := range groups {
+		arr = append(arr, *c)
+	}
+
+	ungrouped := g.ungrouped()
+	sort.Slice(arr, func(i, j int) bool {
+		if (arr[i].Name == ungrouped) != (arr[j].Name == ungrouped) {
+			return arr[j].Name == ungrouped
+		}
+
+		ci, cj := decimalValue(arr[i].MonthlyCost), decimalValue(arr[j].MonthlyCost)
+		if !ci.Equal(cj) {
+			return ci.GreaterThan(cj)
+		}
+
+		return arr[i].Name < arr[j].Name
+	})
+
+	return arr
+}
+
+// costGroupsMessage returns the monthly cost of each group across all the
+// projects.
+func (r *Root) costGroupsMessage(s string) string {
+	g, err := parseGroupBy(s)
+	if err != nil {
+		return ""
+	}
+
+	costs := costGroups(r.Projects, g)
+	if len(costs) == 0 {
+		return ""
+	}
+
+	msg := g.title()
+	for _, c := range costs {
+		noun := "resources"
+		if c.ResourceCount == 1 {
+			noun = "resource"
+		}
+
+		msg += fmt.Sprintf("\n∙ %s: %s (%d %s)", c.Name, FormatCost2DP(r.Currency, decimalPtr(decimalValue(c.MonthlyCost))), c.ResourceCount, noun)
+	}
+
+	return msg
+}
+
+// CheckUntaggedCost returns an error if the resources that don't have the tag
+// that s groups by cost more than maxPercent of the total monthly cost of all
+// the projects, so CI runs can fail when tagging policies aren't followed.
+func CheckUntaggedCost(r *Root, s string, maxPercent float64) error {
+	g, err := parseGroupBy(s)
+	if err != nil {
+		return err
+	}
+
+	if g.tagKey == "" {
+		return errors.New("--max-untagged-cost-percent can only be used with --group-by tag:<key>")
+	}
+
+	total, untagged := decimal.Zero, decimal.Zero
+	for _, c := range costGroups(r.Projects, g) {
+		total = total.Add(decimalValue(c.MonthlyCost))
+		if c.Name == untaggedCostGroup {
+			untagged = decimalValue(c.MonthlyCost)
+		}
+	}
+
+	if total.IsZero() {
+		return nil
+	}
+
+	p, _ := untagged.Div(total).Mul(decimal.NewFromInt(100)).Float64()
+	if p > maxPercent {
+		return fmt.Errorf("Untagged cost is above the maximum of %s: %s of the %s monthly cost (%s) isn't tagged with %s",
+			formatPercent(maxPercent),
+			FormatCost2DP(r.Currency, &untagged),
+			FormatCost2DP(r.Currency, &total),
+			formatPercent(p),
+			g.tagKey,
+		)
+	}
+
+	return nil
+}
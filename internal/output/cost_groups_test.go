@@ -0,0 +1,106 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateGroupBy(t *testing.T) {
+	assert.NoError(t, ValidateGroupBy("module"))
+	assert.NoError(t, ValidateGroupBy("module:2"))
+	assert.EqualError(t, ValidateGroupBy("module:0"), "Invalid module depth '0' in --group-by, the depth must be a number greater than 0")
+	assert.EqualError(t, ValidateGroupBy("module:x"), "Invalid module depth 'x' in --group-by, the depth must be a number greater than 0")
+	assert.NoError(t, ValidateGroupBy("tag:team"))
+	assert.EqualError(t, ValidateGroupBy("tag:"), "Missing tag key in --group-by, use tag:<key>, e.g. tag:team")
+	assert.EqualError(t, ValidateGroupBy("service"), "Invalid --group-by value 'service', supported values are module, module:<depth> and tag:<key>")
+}
+
+func TestCostGroupsByModule(t *testing.T) {
+	resource := func(name string, cost int64) Resource {
+		return Resource{Name: name, MonthlyCost: decimalPtr(decimal.NewFromInt(cost))}
+	}
+
+	root := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name: "dev",
+				Breakdown: &Breakdown{Resources: []Resource{
+					resource("aws_instance.bastion", 10),
+					resource("module.networking.aws_nat_gateway.this[0]", 40),
+					resource(`module.app["api"].module.db.aws_db_instance.this`, 100),
+					resource(`module.app["api"].aws_instance.web`, 30),
+					{Name: "module.networking.aws_vpc.this"},
+				}},
+			},
+			{
+				Name: "prod",
+				Breakdown: &Breakdown{Resources: []Resource{
+					resource("module.networking.aws_nat_gateway.this[0]", 80),
+				}},
+			},
+		},
+	}
+
+	g, err := parseGroupBy("module")
+	require.NoError(t, err)
+
+	assert.Equal(t, []CostGroup{
+		{Name: "module.networking", ResourceCount: 3, MonthlyCost: decimalPtr(decimal.NewFromInt(120))},
+		{Name: `module.app["api"].module.db`, ResourceCount: 1, MonthlyCost: decimalPtr(decimal.NewFromInt(100))},
+		{Name: `module.app["api"]`, ResourceCount: 1, MonthlyCost: decimalPtr(decimal.NewFromInt(30))},
+		{Name: rootModuleCostGroup, ResourceCount: 1, MonthlyCost: decimalPtr(decimal.NewFromInt(10))},
+	}, costGroups(root.Projects, g))
+
+	expected := `Monthly cost by module (depth 1):
+∙ module.app["api"]: $130.00 (2 resources)
+∙ module.networking: $120.00 (3 resources)
+∙ Root module: $10.00 (1 resource)`
+	assert.Equal(t, expected, root.costGroupsMessage("module:1"))
+
+	b, err := ToJSON(root, Options{GroupBy: "module:1"})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"costGroups":[{"name":"module.app[\"api\"]","resourceCount":2,"monthlyCost":"130"},`)
+
+	b, err = ToJSON(root, Options{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), `"costGroups"`)
+}
+
+func TestCostGroupsByTag(t *testing.T) {
+	resource := func(name string, cost int64, team string) Resource {
+		r := Resource{Name: name, MonthlyCost: decimalPtr(decimal.NewFromInt(cost))}
+		if team != "" {
+			r.Tags = map[string]string{"team": team}
+		}
+
+		return r
+	}
+
+	root := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{Name: "dev", Breakdown: &Breakdown{Resources: []Resource{
+				resource("aws_instance.web", 60, "web"),
+				resource("aws_instance.batch", 20, ""),
+			}}},
+			{Name: "prod", Breakdown: &Breakdown{Resources: []Resource{
+				resource("aws_instance.web", 100, "web"),
+				resource("aws_db_instance.db", 120, "data"),
+			}}},
+		},
+	}
+
+	expected := `Monthly cost by team tag:
+∙ web: $160.00 (2 resources)
+∙ data: $120.00 (1 resource)
+∙ Untagged: $20.00 (1 resource)`
+	assert.Equal(t, expected, root.costGroupsMessage("tag:team"))
+
+	assert.NoError(t, CheckUntaggedCost(&root, "tag:team", 10))
+	assert.EqualError(t, CheckUntaggedCost(&root, "tag:team", 5), "Untagged cost is above the maximum of 5.0%: $20.00 of the $300.00 monthly cost (6.6%) isn't tagged with team")
+	assert.EqualError(t, CheckUntaggedCost(&root, "module", 5), "--max-untagged-cost-percent can only be used with --group-by tag:<key>")
+}
@@ -0,0 +1,633 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	getter "github.com/hashicorp/go-getter"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const (
+	costPolicyActionDeny = "deny"
+	costPolicyActionWarn = "warn"
+
+	costPolicyScopeResource = "resource"
+	costPolicyScopeProject  = "project"
+)
+
+// maxCostPolicyIncludeDepth limits how deep policy files can include other
+// policy files, so include cycles don't loop forever.
+const maxCostPolicyIncludeDepth = 5
+
+// CostPolicyFile is a file of cost policies, for teams that want to write
+// policies without Rego. Conditions are CEL expressions, and messages are
+// text with ${...} CEL expressions that are replaced by their values, e.g.
+//
+//	version: 0.1
+//	include:
+//	  - git::https://github.com/my-org/infracost-policies.git//aws?ref=v1.0.0
+//	policies:
+//	  - name: large-instances
+//	    resource_types: [aws_instance]
+//	    condition: resource.monthly_cost > 500 && resource.region != "us-east-1"
+//	    message: "${resource.name} costs ${resource.monthly_cost} per month"
+//	  - name: gp2-volumes
+//	    action: warn
+//	    condition: 'resource.cost_components.exists(c, c.name.startsWith("Storage (gp2"))'
+//	  - name: project-increase
+//	    scope: project
+//	    condition: project.monthly_cost_change > 1000
+//
+// Include loads the policies of other files, or of all the files in a
+// directory. They can be local paths relative to the file or go-getter URLs
+// of shared policy packs, like git repos.
+type CostPolicyFile struct {
+	Version  string       `yaml:"version"`
+	Include  []string     `yaml:"include,omitempty"`
+	Policies []CostPolicy `yaml:"policies"`
+}
+
+// CostPolicy is a policy whose condition is evaluated for each resource, or
+// for each project if Scope is project. Resource policies can use the
+// resource and project variables, project policies only the project variable.
+// The run fails if the condition of a deny policy is true, warn policies are
+// only shown.
+type CostPolicy struct {
+	Name          string   `yaml:"name"`
+	Description   string   `yaml:"description,omitempty"`
+	Action        string   `yaml:"action,omitempty"`
+	Scope         string   `yaml:"scope,omitempty"`
+	ResourceTypes []string `yaml:"resource_types,omitempty"`
+	Condition     string   `yaml:"condition"`
+	Message       string   `yaml:"message,omitempty"`
+
+	condition cel.Program
+	message   []costPolicyMessagePart
+}
+
+// costPolicyMessagePart is either text or the CEL expression of a ${...} in
+// the message of a policy.
+type costPolicyMessagePart struct {
+	text string
+	expr cel.Program
+}
+
+// IsCostPolicyPath returns true if the --policy-path is a cost policy file or
+// a remote policy pack rather than a Rego policy.
+func IsCostPolicyPath(p string) bool {
+	if isRemotePolicyPath(p) {
+		return true
+	}
+
+	ext := strings.ToLower(filepath.Ext(p))
+	return ext == ".yml" || ext == ".yaml"
+}
+
+// isRemotePolicyPath returns true if p is a go-getter URL, e.g.
+// git::https://github.com/my-org/policies.git.
+func isRemotePolicyPath(p string) bool {
+	return strings.Contains(p, "::")
+}
+
+// LoadCostPolicies reads and validates the cost policy files at paths and
+// the files that they include.
+func LoadCostPolicies(paths []string) ([]CostPolicy, error) {
+	env, err := costPolicyEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	l := costPolicyLoader{env: env, seen: make(map[string]bool)}
+
+	for _, p := range paths {
+		if err := l.load(p, "", 0); err != nil {
+			return nil, err
+		}
+	}
+
+	return l.policies, nil
+}
+
+type costPolicyLoader struct {
+	env      *cel.Env
+	seen     map[string]bool
+	policies []CostPolicy
+}
+
+// load loads the policies at p, which is relative to baseDir if it's a local
+// path. Remote policy packs are downloaded to a temporary directory.
+func (l *costPolicyLoader) load(p string, baseDir string, depth int) error {
+	if depth > maxCostPolicyIncludeDepth {
+		return fmt.Errorf("Cost policy %s is included more than %d levels deep", p, maxCostPolicyIncludeDepth)
+	}
+
+	if isRemotePolicyPath(p) {
+		if l.seen[p] {
+			return nil
+		}
+		l.seen[p] = true
+
+		dir, err := os.MkdirTemp("", "infracost-policies")
+		if err != nil {
+			return fmt.Errorf("Error creating directory for policy pack %s: %w", p, err)
+		}
+		defer os.RemoveAll(dir)
+
+		dest := filepath.Join(dir, "pack")
+		if err := fetchPolicyPack(p, dest); err != nil {
+			return err
+		}
+
+		return l.load(dest, "", depth)
+	}
+
+	if baseDir != "" && !filepath.IsAbs(p) {
+		p = filepath.Join(baseDir, p)
+	}
+
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		abs = p
+	}
+	if l.seen[abs] {
+		return nil
+	}
+	l.seen[abs] = true
+
+	info, err := os.Stat(p)
+	if err != nil {
+		return fmt.Errorf("Error reading cost policy %s: %w", p, err)
+	}
+
+	if !info.IsDir() {
+		return l.loadFile(p, depth)
+	}
+
+	entries, err := os.ReadDir(p)
+	if err != nil {
+		return fmt.Errorf("Error reading cost policy directory %s: %w", p, err)
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !IsCostPolicyPath(e.Name()) {
+			continue
+		}
+
+		if err := l.load(filepath.Join(p, e.Name()), "", depth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *costPolicyLoader) loadFile(filePath string, depth int) error {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("Error reading cost policy file: %w", err)
+	}
+
+	var f CostPolicyFile
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return fmt.Errorf("Error parsing cost policy file %s: %w", filePath, err)
+	}
+
+	if len(f.Policies) == 0 && len(f.Include) == 0 {
+		return fmt.Errorf("Cost policy file %s has no policies", filePath)
+	}
+
+	for _, inc := range f.Include {
+		if err := l.load(inc, filepath.Dir(filePath), depth+1); err != nil {
+			return err
+		}
+	}
+
+	for i := range f.Policies {
+		policy := f.Policies[i]
+		if err := policy.parse(l.env, filePath); err != nil {
+			return err
+		}
+
+		l.policies = append(l.policies, policy)
+	}
+
+	return nil
+}
+
+// parse validates the policy and parses its condition and message.
+func (p *CostPolicy) parse(env *cel.Env, filePath string) error {
+	if p.Name == "" {
+		return fmt.Errorf("A policy in cost policy file %s has no name", filePath)
+	}
+
+	switch p.Action {
+	case "":
+		p.Action = costPolicyActionDeny
+	case costPolicyActionDeny, costPolicyActionWarn:
+	default:
+		return fmt.Errorf("Invalid action %s for policy %s in cost policy file %s, must be deny or warn", p.Action, p.Name, filePath)
+	}
+
+	switch p.Scope {
+	case "":
+		p.Scope = costPolicyScopeResource
+	case costPolicyScopeResource, costPolicyScopeProject:
+	default:
+		return fmt.Errorf("Invalid scope %s for policy %s in cost policy file %s, must be resource or project", p.Scope, p.Name, filePath)
+	}
+
+	for _, t := range p.ResourceTypes {
+		if _, err := path.Match(t, ""); err != nil {
+			return fmt.Errorf("Invalid resource type %s for policy %s in cost policy file %s: %w", t, p.Name, filePath, err)
+		}
+	}
+
+	if strings.TrimSpace(p.Condition) == "" {
+		return fmt.Errorf("Policy %s in cost policy file %s has no condition", p.Name, filePath)
+	}
+
+	var err error
+	p.condition, err = compileCostPolicyExpr(env, p.Condition)
+	if err != nil {
+		return fmt.Errorf("Invalid condition for policy %s in cost policy file %s: %w", p.Name, filePath, err)
+	}
+
+	if p.Message != "" {
+		p.message, err = parseCostPolicyMessage(env, p.Message)
+		if err != nil {
+			return fmt.Errorf("Invalid message for policy %s in cost policy file %s: %w", p.Name, filePath, err)
+		}
+	}
+
+	return nil
+}
+
+// costPolicyEnv returns the CEL environment that policy conditions and
+// messages are compiled in. Costs are doubles that can be compared with ints,
+// e.g. resource.monthly_cost > 500.
+func costPolicyEnv() (*cel.Env, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("resource", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("project", cel.MapType(cel.StringType, cel.DynType)),
+		cel.CrossTypeNumericComparisons(true),
+		ext.Strings(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating cost policy environment: %w", err)
+	}
+
+	return env, nil
+}
+
+func compileCostPolicyExpr(env *cel.Env, expr string) (cel.Program, error) {
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+
+	return env.Program(ast)
+}
+
+// parseCostPolicyMessage splits the message into its text and the CEL
+// expressions of its ${...}. Braces in the expressions, e.g. of map literals,
+// must be balanced.
+func parseCostPolicyMessage(env *cel.Env, msg string) ([]costPolicyMessagePart, error) {
+	var parts []costPolicyMessagePart
+
+	for {
+		start := strings.Index(msg, "${")
+		if start == -1 {
+			break
+		}
+
+		end := -1
+		depth := 0
+		for i := start + 2; i < len(msg) && end == -1; i++ {
+			switch msg[i] {
+			case '{':
+				depth++
+			case '}':
+				if depth == 0 {
+					end = i
+				}
+				depth--
+			}
+		}
+		if end == -1 {
+			return nil, fmt.Errorf("unclosed ${ in %q", msg[start:])
+		}
+
+		expr, err := compileCostPolicyExpr(env, msg[start+2:end])
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, costPolicyMessagePart{text: msg[:start]}, costPolicyMessagePart{expr: expr})
+		msg = msg[end+1:]
+	}
+
+	return append(parts, costPolicyMessagePart{text: msg}), nil
+}
+
+// appliesTo returns true if the policy applies to resources of resourceType.
+func (p CostPolicy) appliesTo(resourceType string) bool {
+	if len(p.ResourceTypes) == 0 {
+		return true
+	}
+
+	for _, pattern := range p.ResourceTypes {
+		if ok, _ := path.Match(pattern, resourceType); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fetchPolicyPack downloads the policy pack at src to the dest directory.
+func fetchPolicyPack(src string, dest string) error {
+	pwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	client := getter.Client{
+		Src:  src,
+		Dst:  dest,
+		Pwd:  pwd,
+		Mode: getter.ClientModeDir,
+	}
+
+	if err := client.Get(); err != nil {
+		return fmt.Errorf("Error downloading policy pack %s: %w", src, err)
+	}
+
+	return nil
+}
+
+// CheckCostPolicies evaluates the policies against the projects and resources
+// of r. The messages of deny policies whose condition is true are returned as
+// failures, and the messages of warn policies as warnings.
+func CheckCostPolicies(r Root, policies []CostPolicy) (PolicyCheck, error) {
+	checks := PolicyCheck{
+		Enabled: true,
+	}
+
+	for _, policy := range policies {
+		for _, p := range r.Projects {
+			projectVal := costPolicyProjectVal(p)
+
+			if policy.Scope == costPolicyScopeProject {
+				vars := map[string]interface{}{"project": projectVal}
+				if err := policy.evaluate(vars, p.Label(), &checks); err != nil {
+					return checks, err
+				}
+
+				continue
+			}
+
+			for _, res := range costPolicyResources(p) {
+				if !policy.appliesTo(res.resourceType) {
+					continue
+				}
+
+				vars := map[string]interface{}{
+					"project":  projectVal,
+					"resource": res.val,
+				}
+				if err := policy.evaluate(vars, res.name, &checks); err != nil {
+					return checks, err
+				}
+			}
+		}
+	}
+
+	return checks, nil
+}
+
+// evaluate adds the message of the policy to checks if its condition is true
+// for the resource or project called subject.
+func (p CostPolicy) evaluate(vars map[string]interface{}, subject string, checks *PolicyCheck) error {
+	v, _, err := p.condition.Eval(vars)
+	if err != nil {
+		return fmt.Errorf("Error evaluating the condition of policy %s for %s: %w", p.Name, subject, err)
+	}
+
+	if v == types.NullValue {
+		return nil
+	}
+
+	b, ok := v.(types.Bool)
+	if !ok {
+		return fmt.Errorf("The condition of policy %s must be true or false, got %s", p.Name, v.Type().TypeName())
+	}
+
+	if !b {
+		return nil
+	}
+
+	msg, err := p.formatMessage(vars, subject)
+	if err != nil {
+		return err
+	}
+
+	if p.Action == costPolicyActionWarn {
+		checks.Warnings = append(checks.Warnings, msg)
+	} else {
+		checks.Failures = append(checks.Failures, msg)
+	}
+
+	return nil
+}
+
+func (p CostPolicy) formatMessage(vars map[string]interface{}, subject string) (string, error) {
+	if p.message == nil {
+		if p.Description != "" {
+			return fmt.Sprintf("%s: %s", subject, p.Description), nil
+		}
+
+		return fmt.Sprintf("%s failed policy %s", subject, p.Name), nil
+	}
+
+	var b strings.Builder
+	for _, part := range p.message {
+		if part.expr == nil {
+			b.WriteString(part.text)
+			continue
+		}
+
+		v, _, err := part.expr.Eval(vars)
+		if err != nil {
+			return "", fmt.Errorf("Error evaluating the message of policy %s for %s: %w", p.Name, subject, err)
+		}
+
+		b.WriteString(costPolicyValString(v))
+	}
+
+	return b.String(), nil
+}
+
+// costPolicyValString returns the value of a ${...} in a message as text, so
+// costs are written like 200 or 12.5 rather than in scientific notation.
+func costPolicyValString(v ref.Val) string {
+	switch v := v.(type) {
+	case types.String:
+		return string(v)
+	case types.Double:
+		return strconv.FormatFloat(float64(v), 'f', -1, 64)
+	}
+
+	s, err := v.ConvertToType(types.StringType).ConvertToNative(reflect.TypeOf(""))
+	if err != nil {
+		return fmt.Sprint(v.Value())
+	}
+
+	return s.(string)
+}
+
+// costPolicyResource is a resource and the value of the resource variable
+// that policies are evaluated with.
+type costPolicyResource struct {
+	name         string
+	resourceType string
+	val          map[string]interface{}
+}
+
+// costPolicyResources returns the resources of the project, including the
+// resources that are removed in a diff.
+func costPolicyResources(p Project) []costPolicyResource {
+	current := breakdownResources(p.Breakdown)
+	past := breakdownResources(p.PastBreakdown)
+
+	var resources []costPolicyResource
+	add := func(res Resource, cost, pastCost *decimal.Decimal) {
+		change := ""
+		if p.PastBreakdown != nil {
+			change = "unchanged"
+			if d := findResourceByName(breakdownResources(p.Diff), res.Name); d != nil {
+				switch {
+				case pastCost == nil:
+					change = "added"
+				case cost == nil:
+					change = "removed"
+				default:
+					change = "updated"
+				}
+			}
+		}
+
+		region, _ := res.Metadata[schema.RegionMetadataKey].(string)
+
+		resources = append(resources, costPolicyResource{
+			name:         res.Name,
+			resourceType: res.ResourceType(),
+			val: map[string]interface{}{
+				"name":                res.Name,
+				"type":                res.ResourceType(),
+				"region":              region,
+				"tags":                costPolicyTagsVal(res.Tags),
+				"monthly_cost":        costPolicyNumberVal(cost),
+				"past_monthly_cost":   costPolicyNumberVal(pastCost),
+				"monthly_cost_change": costPolicyNumberVal(decimalPtr(decimalValue(cost).Sub(decimalValue(pastCost)))),
+				"change":              change,
+				"cost_components":     costPolicyComponentsVal(res),
+			},
+		})
+	}
+
+	for _, res := range current {
+		var pastCost *decimal.Decimal
+		if pastRes := findResourceByName(past, res.Name); pastRes != nil {
+			pastCost = decimalPtr(decimalValue(pastRes.MonthlyCost))
+		}
+
+		add(res, decimalPtr(decimalValue(res.MonthlyCost)), pastCost)
+	}
+
+	for _, res := range past {
+		if findResourceByName(current, res.Name) == nil {
+			add(res, nil, decimalPtr(decimalValue(res.MonthlyCost)))
+		}
+	}
+
+	return resources
+}
+
+func costPolicyProjectVal(p Project) map[string]interface{} {
+	projectPath := ""
+	if p.Metadata != nil {
+		projectPath = p.Metadata.Path
+	}
+
+	var cost, pastCost *decimal.Decimal
+	if p.Breakdown != nil {
+		cost = decimalPtr(decimalValue(p.Breakdown.TotalMonthlyCost))
+	}
+	if p.PastBreakdown != nil {
+		pastCost = decimalPtr(decimalValue(p.PastBreakdown.TotalMonthlyCost))
+	}
+
+	return map[string]interface{}{
+		"name":                p.Name,
+		"path":                projectPath,
+		"monthly_cost":        costPolicyNumberVal(cost),
+		"past_monthly_cost":   costPolicyNumberVal(pastCost),
+		"monthly_cost_change": costPolicyNumberVal(decimalPtr(decimalValue(cost).Sub(decimalValue(pastCost)))),
+	}
+}
+
+// costPolicyComponentsVal returns the cost components of the resource and its
+// sub resources.
+func costPolicyComponentsVal(res Resource) []interface{} {
+	vals := []interface{}{}
+
+	var add func(res Resource)
+	add = func(res Resource) {
+		for _, c := range res.CostComponents {
+			vals = append(vals, map[string]interface{}{
+				"name":             c.Name,
+				"unit":             c.Unit,
+				"price":            costPolicyNumberVal(&c.Price),
+				"monthly_quantity": costPolicyNumberVal(c.MonthlyQuantity),
+				"monthly_cost":     costPolicyNumberVal(c.MonthlyCost),
+			})
+		}
+
+		for _, s := range res.SubResources {
+			add(s)
+		}
+	}
+	add(res)
+
+	return vals
+}
+
+func costPolicyTagsVal(tags map[string]string) map[string]string {
+	if tags == nil {
+		return map[string]string{}
+	}
+
+	return tags
+}
+
+// costPolicyNumberVal returns the cost as a double, or zero if it isn't set so
+// conditions can compare costs without checking for nulls.
+func costPolicyNumberVal(d *decimal.Decimal) float64 {
+	return decimalValue(d).InexactFloat64()
+}
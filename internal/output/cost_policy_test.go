@@ -0,0 +1,164 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func writeCostPolicy(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+
+	p := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(p, []byte(content), 0600))
+
+	return p
+}
+
+func TestIsCostPolicyPath(t *testing.T) {
+	assert.True(t, IsCostPolicyPath("policies.yml"))
+	assert.True(t, IsCostPolicyPath("policies/aws.YAML"))
+	assert.True(t, IsCostPolicyPath("git::https://github.com/my-org/policies.git//aws?ref=v1"))
+	assert.False(t, IsCostPolicyPath("policy.rego"))
+	assert.False(t, IsCostPolicyPath("policies"))
+}
+
+func TestLoadCostPoliciesInvalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadCostPolicies([]string{writeCostPolicy(t, dir, "empty.yml", "version: 0.1\npolicies: []\n")})
+	assert.ErrorContains(t, err, "has no policies")
+
+	_, err = LoadCostPolicies([]string{writeCostPolicy(t, dir, "name.yml", "version: 0.1\npolicies:\n  - condition: true\n")})
+	assert.ErrorContains(t, err, "has no name")
+
+	_, err = LoadCostPolicies([]string{writeCostPolicy(t, dir, "action.yml", "version: 0.1\npolicies:\n  - name: a\n    action: block\n    condition: true\n")})
+	assert.ErrorContains(t, err, "Invalid action block for policy a")
+
+	_, err = LoadCostPolicies([]string{writeCostPolicy(t, dir, "condition.yml", "version: 0.1\npolicies:\n  - name: a\n    condition: resource.monthly_cost >\n")})
+	assert.ErrorContains(t, err, "Invalid condition for policy a")
+
+	_, err = LoadCostPolicies([]string{writeCostPolicy(t, dir, "message.yml", "version: 0.1\npolicies:\n  - name: a\n    condition: true\n    message: ${resource.name\n")})
+	assert.ErrorContains(t, err, "Invalid message for policy a")
+
+	_, err = LoadCostPolicies([]string{writeCostPolicy(t, dir, "unknown.yml", "version: 0.1\npolicies:\n  - name: a\n    when: true\n")})
+	assert.ErrorContains(t, err, "Error parsing cost policy file")
+}
+
+func TestLoadCostPoliciesInclude(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "pack"), 0700))
+
+	writeCostPolicy(t, filepath.Join(dir, "pack"), "aws.yml", "version: 0.1\npolicies:\n  - name: aws\n    condition: true\n")
+	writeCostPolicy(t, filepath.Join(dir, "pack"), "README.md", "not a policy")
+	p := writeCostPolicy(t, dir, "policies.yml", `version: 0.1
+include:
+  - pack
+  - policies.yml
+policies:
+  - name: local
+    condition: true
+`)
+
+	policies, err := LoadCostPolicies([]string{p})
+	require.NoError(t, err)
+
+	require.Len(t, policies, 2)
+	assert.Equal(t, "aws", policies[0].Name)
+	assert.Equal(t, "local", policies[1].Name)
+}
+
+func TestCheckCostPolicies(t *testing.T) {
+	p := writeCostPolicy(t, t.TempDir(), "policies.yml", `version: 0.1
+policies:
+  - name: large-instances
+    resource_types: [aws_instance]
+    condition: resource.monthly_cost > 100 && resource.region != "us-east-1"
+    message: "${resource.name} costs ${resource.monthly_cost} in ${resource.region}"
+  - name: gp2-volumes
+    action: warn
+    description: use gp3 volumes
+    condition: 'resource.cost_components.exists(c, c.name.startsWith("Storage (gp2"))'
+  - name: removed
+    action: warn
+    condition: resource.change == "removed"
+  - name: project-increase
+    scope: project
+    condition: project.monthly_cost_change > 50
+  - name: tags
+    action: warn
+    condition: '"team" in resource.tags && resource.tags.team == "data"'
+    message: '${resource.name} is tagged ${ {"team": resource.tags.team}.team } (${resource.monthly_cost})'
+`)
+	policies, err := LoadCostPolicies([]string{p})
+	require.NoError(t, err)
+
+	root := Root{
+		Projects: []Project{
+			{
+				Name: "infra",
+				Metadata: &schema.ProjectMetadata{
+					Path: "infra",
+				},
+				PastBreakdown: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(60)),
+					Resources: []Resource{
+						{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(50))},
+						{Name: "aws_instance.old", MonthlyCost: decimalPtr(decimal.NewFromInt(10))},
+					},
+				},
+				Breakdown: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(320)),
+					Resources: []Resource{
+						{
+							Name:        "aws_instance.web",
+							Metadata:    map[string]interface{}{"region": "eu-west-1"},
+							MonthlyCost: decimalPtr(decimal.NewFromInt(200)),
+							SubResources: []Resource{
+								{Name: "root_block_device", CostComponents: []CostComponent{{Name: "Storage (gp2)"}}},
+							},
+						},
+						{Name: "aws_instance.east", Metadata: map[string]interface{}{"region": "us-east-1"}, Tags: map[string]string{"team": "data"}, MonthlyCost: decimalPtr(decimal.NewFromInt(120))},
+					},
+				},
+				Diff: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(150))},
+						{Name: "aws_instance.east", MonthlyCost: decimalPtr(decimal.NewFromInt(120))},
+						{Name: "aws_instance.old", MonthlyCost: decimalPtr(decimal.NewFromInt(-10))},
+					},
+				},
+			},
+		},
+	}
+
+	checks, err := CheckCostPolicies(root, policies)
+	require.NoError(t, err)
+
+	assert.Equal(t, PolicyCheckFailures{
+		"aws_instance.web costs 200 in eu-west-1",
+		"infra failed policy project-increase",
+	}, checks.Failures)
+	assert.Equal(t, []string{
+		"aws_instance.web: use gp3 volumes",
+		"aws_instance.old failed policy removed",
+		"aws_instance.east is tagged data (120)",
+	}, checks.Warnings)
+}
+
+func TestCheckCostPoliciesInvalidCondition(t *testing.T) {
+	p := writeCostPolicy(t, t.TempDir(), "policies.yml", "version: 0.1\npolicies:\n  - name: a\n    condition: resource.monthly_cost\n")
+	policies, err := LoadCostPolicies([]string{p})
+	require.NoError(t, err)
+
+	root := Root{Projects: []Project{{Name: "infra", Breakdown: &Breakdown{Resources: []Resource{{Name: "aws_instance.web"}}}}}}
+
+	_, err = CheckCostPolicies(root, policies)
+	assert.ErrorContains(t, err, "The condition of policy a must be true or false")
+}
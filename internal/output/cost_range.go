@@ -0,0 +1,91 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// formatCostRange formats the monthly cost followed by its range if it has
+// one, e.g. "$30.00 ($10.00 - $50.00)".
+func formatCostRange(currency string, cost *decimal.Decimal, low *decimal.Decimal, high *decimal.Decimal) string {
+	if low == nil || high == nil {
+		return FormatCost2DP(currency, cost)
+	}
+
+	return fmt.Sprintf("%s (%s - %s)", FormatCost2DP(currency, cost), FormatCost2DP(currency, low), FormatCost2DP(currency, high))
+}
+
+// rangeBound returns the bound of a cost range, or the cost itself if it has
+// no range.
+func rangeBound(bound *decimal.Decimal, cost *decimal.Decimal) decimal.Decimal {
+	if bound != nil {
+		return *bound
+	}
+
+	return decimalValue(cost)
+}
+
+// resourcesCostRange returns the bounds of the total monthly cost of the
+// resources, or nil if none of them has a cost range.
+func resourcesCostRange(resources []Resource) (*decimal.Decimal, *decimal.Decimal) {
+	hasRange := false
+	low := decimal.Zero
+	high := decimal.Zero
+
+	for _, r := range resources {
+		if r.LowMonthlyCost != nil && r.HighMonthlyCost != nil {
+			hasRange = true
+		}
+
+		low = low.Add(rangeBound(r.LowMonthlyCost, r.MonthlyCost))
+		high = high.Add(rangeBound(r.HighMonthlyCost, r.MonthlyCost))
+	}
+
+	if !hasRange {
+		return nil, nil
+	}
+
+	return &low, &high
+}
+
+// projectsCostRange returns the bounds of the total monthly cost of the
+// projects, or nil if none of them has a cost range.
+func projectsCostRange(projects []Project) (*decimal.Decimal, *decimal.Decimal) {
+	hasRange := false
+	low := decimal.Zero
+	high := decimal.Zero
+
+	for _, p := range projects {
+		if p.Breakdown == nil {
+			continue
+		}
+
+		if p.Breakdown.TotalLowMonthlyCost != nil && p.Breakdown.TotalHighMonthlyCost != nil {
+			hasRange = true
+		}
+
+		low = low.Add(rangeBound(p.Breakdown.TotalLowMonthlyCost, p.Breakdown.TotalMonthlyCost))
+		high = high.Add(rangeBound(p.Breakdown.TotalHighMonthlyCost, p.Breakdown.TotalMonthlyCost))
+	}
+
+	if !hasRange {
+		return nil, nil
+	}
+
+	return &low, &high
+}
+
+// CostRangeMessage returns the range of the total monthly cost if some of the
+// usage is given as a range, e.g. for comment templates.
+func (r Root) CostRangeMessage() string {
+	if r.TotalLowMonthlyCost == nil || r.TotalHighMonthlyCost == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("The monthly cost could range from %s to %s, with %s expected, as some usage is estimated as a range",
+		FormatCost2DP(r.Currency, r.TotalLowMonthlyCost),
+		FormatCost2DP(r.Currency, r.TotalHighMonthlyCost),
+		FormatCost2DP(r.Currency, r.TotalMonthlyCost),
+	)
+}
@@ -0,0 +1,64 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestOutputCostRanges(t *testing.T) {
+	requests := &schema.CostComponent{
+		Name:                "Requests",
+		Unit:                "1M requests",
+		UnitMultiplier:      decimal.NewFromInt(1000000),
+		MonthlyQuantity:     decimalPtr(decimal.NewFromInt(3000000)),
+		LowMonthlyQuantity:  decimalPtr(decimal.NewFromInt(1000000)),
+		HighMonthlyQuantity: decimalPtr(decimal.NewFromInt(5000000)),
+	}
+	requests.SetPrice(decimal.NewFromFloat(0.0000002))
+
+	storage := &schema.CostComponent{
+		Name:            "Storage",
+		Unit:            "GB",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: decimalPtr(decimal.NewFromInt(10)),
+	}
+	storage.SetPrice(decimal.NewFromFloat(0.1))
+
+	withRange := &schema.Resource{Name: "aws_lambda_function.api", CostComponents: []*schema.CostComponent{requests}}
+	withoutRange := &schema.Resource{Name: "aws_s3_bucket.assets", CostComponents: []*schema.CostComponent{storage}}
+	withRange.CalculateCosts()
+	withoutRange.CalculateCosts()
+
+	root, err := ToOutputFormat([]*schema.Project{{Name: "infra", Resources: []*schema.Resource{withRange, withoutRange}}})
+	require.NoError(t, err)
+	root.Currency = "USD"
+
+	b := root.Projects[0].Breakdown
+	c := b.Resources[0].CostComponents[0]
+	assert.Equal(t, "1", c.LowMonthlyQuantity.String())
+	assert.Equal(t, "5", c.HighMonthlyQuantity.String())
+	assert.Equal(t, "0.2", b.Resources[0].LowMonthlyCost.String())
+	assert.Equal(t, "1", b.Resources[0].HighMonthlyCost.String())
+	assert.Nil(t, b.Resources[1].LowMonthlyCost)
+	assert.Nil(t, b.Resources[1].CostComponents[0].LowMonthlyCost)
+
+	// The resources without a range add their cost to both bounds.
+	assert.Equal(t, "1.2", b.TotalLowMonthlyCost.String())
+	assert.Equal(t, "2", b.TotalHighMonthlyCost.String())
+	assert.Equal(t, "1.2", root.TotalLowMonthlyCost.String())
+	assert.Equal(t, "2", root.TotalHighMonthlyCost.String())
+
+	assert.Equal(t, "$0.60 ($0.20 - $1.00)", formatCostRange(root.Currency, c.MonthlyCost, c.LowMonthlyCost, c.HighMonthlyCost))
+	assert.Equal(t, "$1.00", formatCostRange(root.Currency, b.Resources[1].MonthlyCost, nil, nil))
+	assert.Equal(t, "The monthly cost could range from $1.20 to $2.00, with $1.60 expected, as some usage is estimated as a range", root.CostRangeMessage())
+
+	withoutRanges, err := ToOutputFormat([]*schema.Project{{Name: "infra", Resources: []*schema.Resource{withoutRange}}})
+	require.NoError(t, err)
+	assert.Nil(t, withoutRanges.TotalLowMonthlyCost)
+	assert.Equal(t, "", withoutRanges.CostRangeMessage())
+}
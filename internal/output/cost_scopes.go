@@ -0,0 +1,122 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// unscopedCostScope is the scope of resources that the AWS account, Azure
+// resource group or GCP project couldn't be found for.
+const unscopedCostScope = "Unscoped"
+
+// ScopeCost is the subtotal of the resources of a project that the cloud
+// reports in the same scope: an AWS account, Azure resource group or GCP
+// project.
+type ScopeCost struct {
+	Scope         string           `json:"scope"`
+	ResourceCount int              `json:"resourceCount"`
+	MonthlyCost   *decimal.Decimal `json:"monthlyCost"`
+}
+
+// withScopeCosts returns a copy of projects with the ScopeCosts of each project
+// set from the cost scope in the metadata of its resources.
+func withScopeCosts(projects Projects) Projects {
+	out := make(Projects, len(projects))
+	copy(out, projects)
+
+	for i, p := range out {
+		if p.Breakdown == nil {
+			continue
+		}
+
+		out[i].ScopeCosts = scopeCosts(p.Breakdown.Resources)
+	}
+
+	return out
+}
+
+// scopeCosts returns the subtotals of the resources by their cost scope, sorted
+// by scope with the unscoped resources last.
+func scopeCosts(resources []Resource) []ScopeCost {
+	costs := make(map[string]*ScopeCost)
+
+	for _, r := range resources {
+		scope := resourceCostScope(r)
+
+		c, ok := costs[scope]
+		if !ok {
+			c = &ScopeCost{Scope: scope}
+			costs[scope] = c
+		}
+
+		c.ResourceCount++
+		if r.MonthlyCost != nil {
+			c.MonthlyCost = decimalPtr(decimalValue(c.MonthlyCost).Add(*r.MonthlyCost))
+		}
+	}
+
+	arr := make([]ScopeCost, 0, len(costs))
+	for _, c := range costs {
+		arr = append(arr, *c)
+	}
+
+	sort.Slice(arr, func(i, j int) bool {
+		if (arr[i].Scope == unscopedCostScope) != (arr[j].Scope == unscopedCostScope) {
+			return arr[j].Scope == unscopedCostScope
+		}
+
+		return arr[i].Scope < arr[j].Scope
+	})
+
+	return arr
+}
+
+func resourceCostScope(r Resource) string {
+	if scope, ok := r.Metadata[schema.CostScopeMetadataKey].(string); ok && scope != "" {
+		return scope
+	}
+
+	return unscopedCostScope
+}
+
+// scopeCostsMessage returns the monthly cost of each cost scope across all the
+// projects.
+func (r *Root) scopeCostsMessage() string {
+	var resources []Resource
+	for _, p := range r.Projects {
+		if p.Breakdown != nil {
+			resources = append(resources, p.Breakdown.Resources...)
+		}
+	}
+
+	costs := scopeCosts(resources)
+
+	// There's nothing to group if no scopes were found.
+	if len(costs) == 0 || (len(costs) == 1 && costs[0].Scope == unscopedCostScope) {
+		return ""
+	}
+
+	msg := "Monthly cost by AWS account, Azure resource group and GCP project:"
+	for _, c := range costs {
+		noun := "resources"
+		if c.ResourceCount == 1 {
+			noun = "resource"
+		}
+
+		msg += fmt.Sprintf("\n∙ %s: %s (%d %s)", c.Scope, FormatCost2DP(r.Currency, decimalPtr(decimalValue(c.MonthlyCost))), c.ResourceCount, noun)
+	}
+
+	return msg
+}
+
+func decimalValue(d *decimal.Decimal) decimal.Decimal {
+	if d == nil {
+		return decimal.Zero
+	}
+
+	return *d
+}
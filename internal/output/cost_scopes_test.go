@@ -0,0 +1,68 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestScopeCosts(t *testing.T) {
+	resource := func(name string, cost int64, scope string) *schema.Resource {
+		r := &schema.Resource{Name: name, ResourceType: "aws_instance", MonthlyCost: decimalPtr(decimal.NewFromInt(cost))}
+		if scope != "" {
+			r.Metadata = map[string]gjson.Result{schema.CostScopeMetadataKey: gjson.Parse(`"` + scope + `"`)}
+		}
+
+		return r
+	}
+
+	root, err := ToOutputFormat([]*schema.Project{
+		{
+			Name:     "app",
+			Metadata: &schema.ProjectMetadata{Path: "infra/app"},
+			Resources: []*schema.Resource{
+				resource("aws_instance.a", 100, "AWS account 222222222222"),
+				resource("aws_instance.b", 50, "AWS account 111111111111"),
+				resource("aws_instance.c", 10, ""),
+			},
+		},
+		{
+			Name:     "web",
+			Metadata: &schema.ProjectMetadata{Path: "infra/web"},
+			Resources: []*schema.Resource{
+				resource("aws_instance.d", 20, "AWS account 111111111111"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	root.Currency = "USD"
+
+	projects := withScopeCosts(root.Projects)
+	assert.Empty(t, root.Projects[0].ScopeCosts, "the input projects should not be changed")
+
+	require.Len(t, projects[0].ScopeCosts, 3)
+	assert.Equal(t, "AWS account 111111111111", projects[0].ScopeCosts[0].Scope)
+	assert.Equal(t, "AWS account 222222222222", projects[0].ScopeCosts[1].Scope)
+	assert.Equal(t, unscopedCostScope, projects[0].ScopeCosts[2].Scope)
+	assert.Equal(t, 1, projects[0].ScopeCosts[2].ResourceCount)
+	assert.True(t, decimal.NewFromInt(10).Equal(*projects[0].ScopeCosts[2].MonthlyCost))
+
+	expected := `Monthly cost by AWS account, Azure resource group and GCP project:
+∙ AWS account 111111111111: $70.00 (2 resources)
+∙ AWS account 222222222222: $100.00 (1 resource)
+∙ Unscoped: $10.00 (1 resource)`
+	assert.Equal(t, expected, root.scopeCostsMessage())
+
+	b, err := ToJSON(root, Options{GroupByScope: true})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"scopeCosts":[{"scope":"AWS account 111111111111","resourceCount":1,"monthlyCost":"20"}]`)
+
+	b, err = ToJSON(root, Options{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), `"scopeCosts"`)
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// PricingCoverage is how much of the projects Infracost could price. Resources
+// are priced if they're free or have a price for all of their cost components,
+// resources that aren't supported aren't priced.
+type PricingCoverage struct {
+	ResourcesPercent      float64 `json:"resourcesPercent"`
+	CostComponentsPercent float64 `json:"costComponentsPercent"`
+}
+
+// pricingCoverage returns the pricing coverage from the resource summary, or
+// nil if the summary doesn't have the counts, e.g. if it was loaded from a JSON
+// file generated by an older version.
+func (r *Root) pricingCoverage() *PricingCoverage {
+	s := r.resourceSummary()
+	if s == nil || s.TotalDetectedResources == nil || s.TotalPricedResources == nil || s.TotalCostComponents == nil {
+		return nil
+	}
+
+	return &PricingCoverage{
+		ResourcesPercent:      percent(intPtrValue(s.TotalPricedResources)+intPtrValue(s.TotalNoPriceResources), intPtrValue(s.TotalDetectedResources)),
+		CostComponentsPercent: percent(intPtrValue(s.TotalPricedCostComponents), intPtrValue(s.TotalCostComponents)),
+	}
+}
+
+// CheckPricingCoverage returns an error if less than min percent of the
+// resources or of the cost components of r were priced, so CI runs can fail
+// when the coverage drops. The resource summary and pricing coverage are added
+// to r so they're included in the JSON output.
+func CheckPricingCoverage(r *Root, min float64) error {
+	c := r.pricingCoverage()
+	if c == nil {
+		return errors.New("Pricing coverage is not available in the Infracost JSON, generate it with --min-pricing-coverage or --show-resource-summary")
+	}
+
+	r.ResourceSummary = r.resourceSummary()
+	r.PricingCoverage = c
+
+	if c.ResourcesPercent < min || c.CostComponentsPercent < min {
+		return fmt.Errorf("Pricing coverage is below the minimum of %s: %s of resources and %s of cost components were priced",
+			formatPercent(min),
+			formatPercent(c.ResourcesPercent),
+			formatPercent(c.CostComponentsPercent),
+		)
+	}
+
+	return nil
+}
+
+// countPricedCostComponents returns the number of cost components of r and its
+// sub resources, and how many of them have a price from the price source or a
+// custom price.
+func countPricedCostComponents(r *schema.Resource) (int, int) {
+	total, priced := 0, 0
+
+	resources := append([]*schema.Resource{r}, r.FlattenedSubResources()...)
+	for _, res := range resources {
+		for _, c := range res.CostComponents {
+			total++
+			if c.PriceHash() != "" || c.CustomPrice() != nil {
+				priced++
+			}
+		}
+	}
+
+	return total, priced
+}
+
+// percent returns count as a percentage of total. Nothing to price is full
+// coverage.
+func percent(count, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+
+	return float64(count) / float64(total) * 100
+}
+
+// formatPercent formats a coverage percentage with one decimal place. It's
+// rounded down so coverage that's just below a minimum isn't shown as equal.
+func formatPercent(p float64) string {
+	return fmt.Sprintf("%.1f%%", math.Floor(p*10)/10)
+}
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func pricedCostComponent(name string) *schema.CostComponent {
+	c := &schema.CostComponent{Name: name, MonthlyQuantity: decimalPtr(decimal.NewFromInt(1))}
+	c.SetPrice(decimal.NewFromInt(10))
+	c.SetPriceHash(name + "-hash")
+	return c
+}
+
+func TestCheckPricingCoverage(t *testing.T) {
+	unpriced := &schema.CostComponent{Name: "unpriced", MonthlyQuantity: decimalPtr(decimal.NewFromInt(1))}
+
+	resources := []*schema.Resource{
+		{Name: "aws_instance.web", ResourceType: "aws_instance", CostComponents: []*schema.CostComponent{pricedCostComponent("a"), pricedCostComponent("b")}},
+		{
+			Name:           "aws_instance.app",
+			ResourceType:   "aws_instance",
+			CostComponents: []*schema.CostComponent{pricedCostComponent("c")},
+			SubResources: []*schema.Resource{
+				{Name: "root_block_device", CostComponents: []*schema.CostComponent{unpriced}},
+			},
+		},
+		{Name: "aws_iam_role.role", ResourceType: "aws_iam_role", NoPrice: true, IsSkipped: true},
+		{Name: "aws_foo.bar", ResourceType: "aws_foo", IsSkipped: true},
+	}
+
+	for _, r := range resources {
+		r.CalculateCosts()
+	}
+
+	root, err := ToOutputFormat([]*schema.Project{{Name: "test", Metadata: &schema.ProjectMetadata{}, Resources: resources}})
+	require.NoError(t, err)
+
+	err = CheckPricingCoverage(&root, 50)
+	require.NoError(t, err)
+	require.NotNil(t, root.PricingCoverage)
+	assert.Equal(t, 50.0, root.PricingCoverage.ResourcesPercent)
+	assert.Equal(t, 75.0, root.PricingCoverage.CostComponentsPercent)
+	assert.Equal(t, 1, *root.ResourceSummary.TotalPricedResources)
+	assert.Equal(t, 4, *root.ResourceSummary.TotalCostComponents)
+	assert.Equal(t, 3, *root.ResourceSummary.TotalPricedCostComponents)
+
+	err = CheckPricingCoverage(&root, 60)
+	assert.EqualError(t, err, "Pricing coverage is below the minimum of 60.0%: 50.0% of resources and 75.0% of cost components were priced")
+
+	b, err := ToJSON(root, Options{})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"pricingCoverage":{"resourcesPercent":50,"costComponentsPercent":75}`)
+}
+
+func TestCheckPricingCoverageFromJSON(t *testing.T) {
+	root, err := ToOutputFormat([]*schema.Project{{Name: "test", Metadata: &schema.ProjectMetadata{}, Resources: []*schema.Resource{
+		{Name: "aws_instance.web", ResourceType: "aws_instance", CostComponents: []*schema.CostComponent{pricedCostComponent("a")}},
+	}}})
+	require.NoError(t, err)
+
+	// The full summary isn't included in the JSON so the coverage is only
+	// available from the resource summary.
+	b, err := ToJSON(root, Options{ShowResourceSummary: true})
+	require.NoError(t, err)
+
+	var loaded Root
+	require.NoError(t, json.Unmarshal(b, &loaded))
+	require.NoError(t, CheckPricingCoverage(&loaded, 100))
+
+	b, err = ToJSON(root, Options{})
+	require.NoError(t, err)
+
+	loaded = Root{}
+	require.NoError(t, json.Unmarshal(b, &loaded))
+	assert.EqualError(t, CheckPricingCoverage(&loaded, 100), "Pricing coverage is not available in the Infracost JSON, generate it with --min-pricing-coverage or --show-resource-summary")
+}
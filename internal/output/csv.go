@@ -0,0 +1,111 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+
+	"github.com/shopspring/decimal"
+)
+
+// costComponentRow is a cost component of the planned state flattened into a
+// row of the CSV and XLSX output formats.
+type costComponentRow struct {
+	Project   string
+	Resource  string
+	Component string
+	Unit      string
+	// MonthlyQuantity and MonthlyCost are nil if the cost depends on usage
+	// that isn't set.
+	MonthlyQuantity *decimal.Decimal
+	Price           decimal.Decimal
+	MonthlyCost     *decimal.Decimal
+}
+
+func costComponentHeaders(currency string) []string {
+	return []string{
+		"Project",
+		"Resource",
+		"Component",
+		"Unit",
+		"Monthly quantity",
+		formatTitleWithCurrency("Price", currency),
+		formatTitleWithCurrency("Monthly cost", currency),
+	}
+}
+
+// costComponentRows returns a row for each cost component of the planned
+// state. The components of sub resources are in the rows of their top level
+// resource, prefixed with the name of the sub resource.
+func costComponentRows(out Root) []costComponentRow {
+	var rows []costComponentRow
+
+	for _, project := range out.Projects {
+		if project.Breakdown == nil {
+			continue
+		}
+
+		for _, r := range project.Breakdown.Resources {
+			rows = appendCostComponentRows(rows, project.Name, r.Name, "", r)
+		}
+	}
+
+	return rows
+}
+
+func appendCostComponentRows(rows []costComponentRow, projectName, resourceName, prefix string, r Resource) []costComponentRow {
+	for _, c := range r.CostComponents {
+		rows = append(rows, costComponentRow{
+			Project:         projectName,
+			Resource:        resourceName,
+			Component:       prefix + c.Name,
+			Unit:            c.Unit,
+			MonthlyQuantity: c.MonthlyQuantity,
+			Price:           c.Price,
+			MonthlyCost:     c.MonthlyCost,
+		})
+	}
+
+	for _, s := range r.SubResources {
+		rows = appendCostComponentRows(rows, projectName, resourceName, prefix+s.Name+": ", s)
+	}
+
+	return rows
+}
+
+// ToCSV returns the cost components of the planned state as CSV rows, so they
+// can be imported into spreadsheets and BI tools.
+func ToCSV(out Root, opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	err := w.Write(costComponentHeaders(out.Currency))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range costComponentRows(out) {
+		err = w.Write([]string{
+			row.Project,
+			row.Resource,
+			row.Component,
+			row.Unit,
+			decimalString(row.MonthlyQuantity),
+			row.Price.String(),
+			decimalString(row.MonthlyCost),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func decimalString(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+
+	return d.String()
+}
@@ -0,0 +1,67 @@
+package output
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func spreadsheetTestRoot() Root {
+	return Root{
+		Currency: "EUR",
+		Projects: []Project{
+			{
+				Name: "infracost/example",
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name: "aws_instance.web",
+							CostComponents: []CostComponent{
+								{
+									Name:            "Instance usage (Linux/UNIX, on-demand, t3.medium)",
+									Unit:            "hours",
+									MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)),
+									Price:           decimal.RequireFromString("0.0416"),
+									MonthlyCost:     decimalPtr(decimal.RequireFromString("30.368")),
+								},
+							},
+							SubResources: []Resource{
+								{
+									Name: "root_block_device",
+									CostComponents: []CostComponent{
+										{Name: "Storage (general purpose SSD, gp2)", Unit: "GB", MonthlyQuantity: decimalPtr(decimal.NewFromInt(8)), Price: decimal.RequireFromString("0.1"), MonthlyCost: decimalPtr(decimal.RequireFromString("0.8"))},
+									},
+								},
+							},
+						},
+						{
+							Name: "aws_lambda_function.hello",
+							CostComponents: []CostComponent{
+								{Name: "Requests", Unit: "1M requests", Price: decimal.RequireFromString("0.2")},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestToCSV(t *testing.T) {
+	b, err := ToCSV(spreadsheetTestRoot(), Options{})
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(string(b))).ReadAll()
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		{"Project", "Resource", "Component", "Unit", "Monthly quantity", "Price (EUR)", "Monthly cost (EUR)"},
+		{"infracost/example", "aws_instance.web", "Instance usage (Linux/UNIX, on-demand, t3.medium)", "hours", "730", "0.0416", "30.368"},
+		{"infracost/example", "aws_instance.web", "root_block_device: Storage (general purpose SSD, gp2)", "GB", "8", "0.1", "0.8"},
+		{"infracost/example", "aws_lambda_function.hello", "Requests", "1M requests", "", "0.2", ""},
+	}, rows)
+}
@@ -23,6 +23,10 @@ func ToDiff(out Root, opts Options) ([]byte, error) {
 	noDiffProjects := make([]string, 0)
 	erroredProjects := make([]string, 0)
 
+	if opts.OwnershipTag != "" {
+		out.Projects = withOwnershipChanges(out.Projects, opts.OwnershipTag, opts.OwnershipDriftThreshold)
+	}
+
 	for i, project := range out.Projects {
 		if project.Metadata.HasErrors() {
 			erroredProjects = append(erroredProjects, project.LabelWithMetadata())
@@ -33,8 +37,9 @@ func ToDiff(out Root, opts Options) ([]byte, error) {
 			continue
 		}
 
-		// Check whether there is any diff or not
-		if len(project.Diff.Resources) == 0 {
+		// Check whether there is any diff or not. Cost that moves between owners
+		// is a change even if the resources' costs are the same.
+		if len(project.Diff.Resources) == 0 && len(project.OwnershipChanges) == 0 {
 			noDiffProjects = append(noDiffProjects, project.LabelWithMetadata())
 			continue
 		}
@@ -68,7 +73,7 @@ func ToDiff(out Root, opts Options) ([]byte, error) {
 			oldResource := findResourceByName(project.PastBreakdown.Resources, diffResource.Name)
 			newResource := findResourceByName(project.Breakdown.Resources, diffResource.Name)
 
-			s += resourceToDiff(out.Currency, diffResource, oldResource, newResource, true)
+			s += resourceToDiff(out.Currency, diffResource, oldResource, newResource, "")
 			s += "\n"
 		}
 
@@ -96,6 +101,10 @@ func ToDiff(out Root, opts Options) ([]byte, error) {
 			)
 		}
 
+		if ownershipMsg := ownershipChangesMessage(out.Currency, project); ownershipMsg != "" {
+			s += "\n\n" + ownershipMsg
+		}
+
 		s += "\n\n"
 	}
 
@@ -133,11 +142,31 @@ func ToDiff(out Root, opts Options) ([]byte, error) {
 		s += unsupportedMsg
 	}
 
+	if budgetMsg := out.budgetMessage(); budgetMsg != "" {
+		s += "\n──────────────────────────────────\n" + budgetMsg
+	}
+
+	if policyMsg := opts.PolicyChecks.message(); policyMsg != "" {
+		s += "\n──────────────────────────────────\n" + policyMsg
+	}
+
+	if missingUsageMsg := out.missingUsageMessage(); missingUsageMsg != "" {
+		s += "\n──────────────────────────────────\n" + missingUsageMsg
+	}
+
+	if unknownInputsMsg := out.unknownInputsMessage(); unknownInputsMsg != "" {
+		s += "\n──────────────────────────────────\n" + unknownInputsMsg
+	}
+
 	return []byte(s), nil
 }
 
-func resourceToDiff(currency string, diffResource Resource, oldResource *Resource, newResource *Resource, isTopLevel bool) string {
+// resourceToDiff returns the diff of the resource, parentType is the type of
+// the resource that it's a sub resource of, or empty if it's a top level
+// resource.
+func resourceToDiff(currency string, diffResource Resource, oldResource *Resource, newResource *Resource, parentType string) string {
 	s := ""
+	isTopLevel := parentType == ""
 
 	op := UPDATED
 	if oldResource == nil {
@@ -156,9 +185,10 @@ func resourceToDiff(currency string, diffResource Resource, oldResource *Resourc
 		newCost = newResource.MonthlyCost
 	}
 
-	nameLabel := diffResource.Name
-	if isTopLevel {
-		nameLabel = ui.BoldString(nameLabel)
+	nameLabel := ui.BoldString(diffResource.Name)
+	resourceType := diffResource.ResourceType()
+	if !isTopLevel {
+		nameLabel, resourceType = subResourceLabel(diffResource, parentType)
 	}
 
 	s += fmt.Sprintf("%s %s\n", opChar(op), nameLabel)
@@ -201,7 +231,7 @@ func resourceToDiff(currency string, diffResource Resource, oldResource *Resourc
 		}
 
 		s += "\n"
-		s += ui.Indent(resourceToDiff(currency, diffSubResource, oldSubResource, newSubResource, false), "    ")
+		s += ui.Indent(resourceToDiff(currency, diffSubResource, oldSubResource, newSubResource, resourceType), "    ")
 	}
 
 	return s
@@ -0,0 +1,395 @@
+package output
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// The emissions coefficients follow the Cloud Carbon Footprint methodology,
+// see https://www.cloudcarbonfootprint.org/docs/methodology. Only the
+// operational emissions of compute and storage are estimated, the embodied
+// emissions of the hardware and the emissions of networking aren't included.
+
+// emissionsField is the table output field of the estimated emissions, added
+// to the fields if emissions are shown.
+const emissionsField = "monthlyKgCO2e"
+
+// emissionsProvider is the power and power usage effectiveness coefficients
+// of a cloud provider.
+type emissionsProvider struct {
+	// minWattsPerVCPU and maxWattsPerVCPU are the power of a vCPU when it's
+	// idle and fully used.
+	minWattsPerVCPU float64
+	maxWattsPerVCPU float64
+	// pue is the power usage effectiveness of the data centers.
+	pue float64
+	// defaultKgCO2ePerKWh is the emissions factor of regions that aren't in
+	// regionKgCO2ePerKWh.
+	defaultKgCO2ePerKWh float64
+}
+
+var emissionsProviders = map[string]emissionsProvider{
+	"aws":     {minWattsPerVCPU: 0.74, maxWattsPerVCPU: 3.5, pue: 1.135, defaultKgCO2ePerKWh: 0.379069},
+	"azurerm": {minWattsPerVCPU: 0.78, maxWattsPerVCPU: 3.76, pue: 1.185, defaultKgCO2ePerKWh: 0.379069},
+	"google":  {minWattsPerVCPU: 0.71, maxWattsPerVCPU: 4.26, pue: 1.1, defaultKgCO2ePerKWh: 0.454},
+}
+
+// Graviton instances use less power than the x86 instances.
+const (
+	gravitonMinWattsPerVCPU = 0.47
+	gravitonMaxWattsPerVCPU = 1.69
+)
+
+// averageCPUUtilization is the CPU utilization that compute emissions are
+// estimated with.
+const averageCPUUtilization = 0.5
+
+// Storage power in watt hours per terabyte hour, and the number of copies
+// that block storage keeps of the data.
+const (
+	ssdWattHoursPerTBHour = 1.2
+	hddWattHoursPerTBHour = 0.65
+	storageReplication    = 2
+)
+
+// regionKgCO2ePerKWh is the emissions factor of the electricity grid of the
+// cloud regions.
+var regionKgCO2ePerKWh = map[string]float64{
+	// AWS
+	"us-east-1":      0.379069,
+	"us-east-2":      0.410608,
+	"us-west-1":      0.322167,
+	"us-west-2":      0.322167,
+	"ca-central-1":   0.00013,
+	"sa-east-1":      0.0617,
+	"eu-west-1":      0.2786,
+	"eu-west-2":      0.225,
+	"eu-west-3":      0.0511,
+	"eu-central-1":   0.338,
+	"eu-north-1":     0.0088,
+	"eu-south-1":     0.233,
+	"ap-south-1":     0.708,
+	"ap-east-1":      0.71,
+	"ap-northeast-1": 0.4658,
+	"ap-northeast-2": 0.4156,
+	"ap-northeast-3": 0.4658,
+	"ap-southeast-1": 0.4085,
+	"ap-southeast-2": 0.79,
+	"me-south-1":     0.732,
+	"af-south-1":     0.928,
+	// Azure
+	"eastus":             0.379069,
+	"eastus2":            0.379069,
+	"centralus":          0.426254,
+	"northcentralus":     0.426254,
+	"southcentralus":     0.373231,
+	"westcentralus":      0.322167,
+	"westus":             0.322167,
+	"westus2":            0.322167,
+	"westus3":            0.322167,
+	"canadacentral":      0.00013,
+	"canadaeast":         0.00013,
+	"brazilsouth":        0.0617,
+	"northeurope":        0.2786,
+	"westeurope":         0.3284,
+	"uksouth":            0.225,
+	"ukwest":             0.225,
+	"francecentral":      0.0511,
+	"germanywestcentral": 0.338,
+	"swedencentral":      0.0088,
+	"norwayeast":         0.00762,
+	"switzerlandnorth":   0.0117,
+	"centralindia":       0.708,
+	"southindia":         0.708,
+	"japaneast":          0.4658,
+	"koreacentral":       0.4156,
+	"southeastasia":      0.4085,
+	"eastasia":           0.71,
+	"australiaeast":      0.79,
+	"southafricanorth":   0.928,
+	// GCP
+	"us-central1":             0.454,
+	"us-east1":                0.48,
+	"us-east4":                0.361,
+	"us-west1":                0.078,
+	"us-west2":                0.253,
+	"us-west3":                0.533,
+	"us-west4":                0.455,
+	"northamerica-northeast1": 0.0,
+	"southamerica-east1":      0.103,
+	"europe-west1":            0.123,
+	"europe-west2":            0.231,
+	"europe-west3":            0.293,
+	"europe-west4":            0.41,
+	"europe-west6":            0.0087,
+	"europe-north1":           0.127,
+	"asia-east1":              0.541,
+	"asia-east2":              0.626,
+	"asia-northeast1":         0.506,
+	"asia-northeast3":         0.5,
+	"asia-south1":             0.723,
+	"asia-southeast1":         0.493,
+	"australia-southeast1":    0.725,
+}
+
+var (
+	awsGravitonFamilyRegex = regexp.MustCompile(`^[a-z]+\d+[a-z]*g[a-z]*$`)
+	azureVMSizeRegex       = regexp.MustCompile(`(?i)^(?:standard|basic)_[a-z]+(\d+)`)
+	googleMachineTypeRegex = regexp.MustCompile(`-(\d+)$`)
+	awsXLargeRegex         = regexp.MustCompile(`^(\d*)xlarge$`)
+)
+
+// googleSharedCoreVCPUs is the fraction of a vCPU of the GCP shared-core
+// machine types.
+var googleSharedCoreVCPUs = map[string]float64{
+	"f1-micro":  0.2,
+	"g1-small":  0.5,
+	"e2-micro":  0.25,
+	"e2-small":  0.5,
+	"e2-medium": 1,
+}
+
+// Emissions is the estimated carbon footprint of the compute and storage
+// resources, included in the JSON output format if emissions are estimated.
+type Emissions struct {
+	TotalMonthlyKgCO2e *decimal.Decimal   `json:"totalMonthlyKgCo2e"`
+	Projects           []ProjectEmissions `json:"projects"`
+}
+
+// ProjectEmissions is the estimated carbon footprint of a project.
+type ProjectEmissions struct {
+	Name          string           `json:"name"`
+	MonthlyKgCO2e *decimal.Decimal `json:"monthlyKgCo2e"`
+	// EstimatedResourceCount is the number of resources whose emissions
+	// could be estimated.
+	EstimatedResourceCount int `json:"estimatedResourceCount"`
+}
+
+// SetEmissions estimates the monthly carbon footprint of the compute and
+// storage cost components of the resources of r, from their instance types,
+// storage types and regions. The estimates are added to the cost components
+// and resources, and the totals to the emissions of r.
+func SetEmissions(r *Root) {
+	total := decimal.Zero
+	projects := make([]ProjectEmissions, 0, len(r.Projects))
+
+	for _, p := range r.Projects {
+		pe := ProjectEmissions{Name: p.Name, MonthlyKgCO2e: decimalPtr(decimal.Zero)}
+
+		if p.PastBreakdown != nil {
+			setBreakdownEmissions(p.PastBreakdown)
+		}
+
+		if p.Breakdown != nil {
+			for _, res := range setBreakdownEmissions(p.Breakdown) {
+				if res.MonthlyKgCO2e != nil {
+					pe.MonthlyKgCO2e = decimalPtr(pe.MonthlyKgCO2e.Add(*res.MonthlyKgCO2e))
+					pe.EstimatedResourceCount++
+				}
+			}
+		}
+
+		total = total.Add(*pe.MonthlyKgCO2e)
+		projects = append(projects, pe)
+	}
+
+	r.Emissions = &Emissions{
+		TotalMonthlyKgCO2e: decimalPtr(total),
+		Projects:           projects,
+	}
+}
+
+func setBreakdownEmissions(b *Breakdown) []Resource {
+	for i := range b.Resources {
+		res := &b.Resources[i]
+		provider := resourceProvider(res.ResourceType())
+		region, _ := res.Metadata[schema.RegionMetadataKey].(string)
+
+		setResourceEmissions(res, provider, region)
+	}
+
+	return b.Resources
+}
+
+// setResourceEmissions sets the emissions of the cost components and sub
+// resources of res, and returns the total.
+func setResourceEmissions(res *Resource, provider string, region string) *decimal.Decimal {
+	var total *decimal.Decimal
+	add := func(d *decimal.Decimal) {
+		if d != nil {
+			total = decimalPtr(decimalValue(total).Add(*d))
+		}
+	}
+
+	for i := range res.CostComponents {
+		c := &res.CostComponents[i]
+		c.MonthlyKgCO2e = costComponentEmissions(*c, provider, region)
+		add(c.MonthlyKgCO2e)
+	}
+
+	for i := range res.SubResources {
+		add(setResourceEmissions(&res.SubResources[i], provider, region))
+	}
+
+	res.MonthlyKgCO2e = total
+
+	return total
+}
+
+// costComponentEmissions returns the estimated monthly kgCO2e of the cost
+// component, or nil if it's not a compute or storage cost component.
+func costComponentEmissions(c CostComponent, provider string, region string) *decimal.Decimal {
+	p, ok := emissionsProviders[provider]
+	if !ok || c.MonthlyQuantity == nil {
+		return nil
+	}
+
+	var kWh float64
+	quantity := c.MonthlyQuantity.InexactFloat64()
+
+	switch {
+	case isComputeCostComponent(c):
+		instanceType := costComponentInstanceType(c.Name)
+		vCPUs := instanceVCPUs(provider, instanceType)
+		if vCPUs == 0 {
+			return nil
+		}
+
+		minWatts, maxWatts := p.minWattsPerVCPU, p.maxWattsPerVCPU
+		if provider == "aws" && isGravitonInstance(instanceType) {
+			minWatts, maxWatts = gravitonMinWattsPerVCPU, gravitonMaxWattsPerVCPU
+		}
+
+		watts := minWatts + averageCPUUtilization*(maxWatts-minWatts)
+		kWh = vCPUs * quantity * watts / 1000
+	case isStorageCostComponent(c):
+		wattHours := ssdWattHoursPerTBHour
+		if isHDDStorage(c.Name) {
+			wattHours = hddWattHoursPerTBHour
+		}
+
+		kWh = quantity / 1000 * schema.HourToMonthUnitMultiplier.InexactFloat64() * wattHours * storageReplication / 1000
+	default:
+		return nil
+	}
+
+	factor, ok := regionKgCO2ePerKWh[strings.ReplaceAll(strings.ToLower(region), " ", "")]
+	if !ok {
+		factor = p.defaultKgCO2ePerKWh
+	}
+
+	return decimalPtr(decimal.NewFromFloat(kWh * p.pue * factor).Round(4))
+}
+
+func isComputeCostComponent(c CostComponent) bool {
+	return strings.HasSuffix(c.Unit, "hours") &&
+		(strings.HasPrefix(c.Name, "Instance usage") || strings.HasPrefix(c.Name, "Database instance"))
+}
+
+func isStorageCostComponent(c CostComponent) bool {
+	return c.Unit == "GB" && strings.Contains(strings.ToLower(c.Name), "storage")
+}
+
+func isHDDStorage(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range []string{"magnetic", "hdd", "st1", "sc1", "standard)"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// costComponentInstanceType returns the instance type of compute cost
+// components, which is the last value in the parentheses of their names,
+// e.g. t3.medium for Instance usage (Linux/UNIX, on-demand, t3.medium).
+func costComponentInstanceType(name string) string {
+	start := strings.LastIndex(name, "(")
+	end := strings.LastIndex(name, ")")
+	if start == -1 || end < start {
+		return ""
+	}
+
+	parts := strings.Split(name[start+1:end], ",")
+	return strings.TrimSpace(parts[len(parts)-1])
+}
+
+// instanceVCPUs returns the number of vCPUs of the instance type, or 0 if
+// it's not known.
+func instanceVCPUs(provider string, instanceType string) float64 {
+	switch provider {
+	case "aws":
+		parts := strings.Split(strings.TrimPrefix(strings.TrimPrefix(instanceType, "db."), "cache."), ".")
+		if len(parts) != 2 {
+			return 0
+		}
+
+		family, size := parts[0], parts[1]
+		switch size {
+		case "nano", "micro", "small", "medium":
+			// Burstable instances have 2 vCPUs in all the small sizes.
+			if strings.HasPrefix(family, "t") {
+				return 2
+			}
+			return 1
+		case "large":
+			return 2
+		case "metal":
+			return 96
+		}
+
+		if m := awsXLargeRegex.FindStringSubmatch(size); m != nil {
+			if m[1] == "" {
+				return 4
+			}
+
+			n, _ := strconv.ParseFloat(m[1], 64)
+			return 4 * n
+		}
+	case "azurerm":
+		if m := azureVMSizeRegex.FindStringSubmatch(instanceType); m != nil {
+			n, _ := strconv.ParseFloat(m[1], 64)
+			return n
+		}
+	case "google":
+		if v, ok := googleSharedCoreVCPUs[instanceType]; ok {
+			return v
+		}
+
+		if m := googleMachineTypeRegex.FindStringSubmatch(instanceType); m != nil {
+			n, _ := strconv.ParseFloat(m[1], 64)
+			return n
+		}
+	}
+
+	return 0
+}
+
+func isGravitonInstance(instanceType string) bool {
+	family := strings.Split(strings.TrimPrefix(instanceType, "db."), ".")[0]
+	return awsGravitonFamilyRegex.MatchString(family)
+}
+
+// emissionsMessage returns the estimated carbon footprint of the run.
+func (r *Root) emissionsMessage() string {
+	if r.Emissions == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("Estimated emissions of compute and storage: %s kgCO2e per month", formatKgCO2e(r.Emissions.TotalMonthlyKgCO2e))
+}
+
+func formatKgCO2e(d *decimal.Decimal) string {
+	if d == nil {
+		return "-"
+	}
+
+	return d.StringFixed(2)
+}
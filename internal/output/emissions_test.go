@@ -0,0 +1,94 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceVCPUs(t *testing.T) {
+	assert.Equal(t, 2.0, instanceVCPUs("aws", "t3.medium"))
+	assert.Equal(t, 1.0, instanceVCPUs("aws", "m1.small"))
+	assert.Equal(t, 4.0, instanceVCPUs("aws", "m5.xlarge"))
+	assert.Equal(t, 32.0, instanceVCPUs("aws", "db.r5.8xlarge"))
+	assert.Equal(t, 4.0, instanceVCPUs("azurerm", "Standard_D4s_v3"))
+	assert.Equal(t, 8.0, instanceVCPUs("google", "n1-standard-8"))
+	assert.Equal(t, 0.25, instanceVCPUs("google", "e2-micro"))
+	assert.Equal(t, 0.0, instanceVCPUs("aws", "unknown"))
+}
+
+func TestCostComponentEmissions(t *testing.T) {
+	instance := CostComponent{
+		Name:            "Instance usage (Linux/UNIX, on-demand, t3.medium)",
+		Unit:            "hours",
+		MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)),
+	}
+	// 2 vCPUs * 730 hours * 2.12 W * 1.135 PUE * 0.379069 kgCO2e/kWh
+	assert.Equal(t, "1.3317", costComponentEmissions(instance, "aws", "us-east-1").String())
+	// Graviton instances use less power.
+	instance.Name = "Instance usage (Linux/UNIX, on-demand, t4g.medium)"
+	assert.Equal(t, "0.6784", costComponentEmissions(instance, "aws", "us-east-1").String())
+
+	storage := CostComponent{
+		Name:            "Storage (general purpose SSD, gp2)",
+		Unit:            "GB",
+		MonthlyQuantity: decimalPtr(decimal.NewFromInt(100)),
+	}
+	assert.Equal(t, "0.0754", costComponentEmissions(storage, "aws", "us-east-1").String())
+	// Regions with cleaner electricity have lower emissions.
+	assert.Equal(t, "0.0017", costComponentEmissions(storage, "aws", "eu-north-1").String())
+
+	requests := CostComponent{Name: "Requests", Unit: "1M requests", MonthlyQuantity: decimalPtr(decimal.NewFromInt(10))}
+	assert.Nil(t, costComponentEmissions(requests, "aws", "us-east-1"))
+	assert.Nil(t, costComponentEmissions(storage, "kubernetes", "us-east-1"))
+}
+
+func TestSetEmissions(t *testing.T) {
+	root := Root{
+		Projects: []Project{
+			{
+				Name: "infra",
+				Breakdown: &Breakdown{Resources: []Resource{
+					{
+						Name:     "aws_instance.web",
+						Metadata: map[string]interface{}{"region": "us-east-1"},
+						CostComponents: []CostComponent{
+							{Name: "Instance usage (Linux/UNIX, on-demand, t3.medium)", Unit: "hours", MonthlyQuantity: decimalPtr(decimal.NewFromInt(730))},
+						},
+						SubResources: []Resource{
+							{
+								Name: "root_block_device",
+								CostComponents: []CostComponent{
+									{Name: "Storage (general purpose SSD, gp2)", Unit: "GB", MonthlyQuantity: decimalPtr(decimal.NewFromInt(100))},
+								},
+							},
+						},
+					},
+					{
+						Name:     "aws_lambda_function.hello",
+						Metadata: map[string]interface{}{"region": "us-east-1"},
+						CostComponents: []CostComponent{
+							{Name: "Requests", Unit: "1M requests", MonthlyQuantity: decimalPtr(decimal.NewFromInt(10))},
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	SetEmissions(&root)
+
+	require.NotNil(t, root.Emissions)
+	assert.Equal(t, "1.4071", root.Emissions.TotalMonthlyKgCO2e.String())
+	require.Len(t, root.Emissions.Projects, 1)
+	assert.Equal(t, 1, root.Emissions.Projects[0].EstimatedResourceCount)
+
+	resources := root.Projects[0].Breakdown.Resources
+	assert.Equal(t, "1.4071", resources[0].MonthlyKgCO2e.String())
+	assert.Equal(t, "0.0754", resources[0].SubResources[0].MonthlyKgCO2e.String())
+	assert.Nil(t, resources[1].MonthlyKgCO2e)
+
+	assert.Equal(t, "Estimated emissions of compute and storage: 1.41 kgCO2e per month", root.emissionsMessage())
+}
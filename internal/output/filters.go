@@ -0,0 +1,302 @@
+package output
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	filterFieldName              = "name"
+	filterFieldResourceType      = "resourceType"
+	filterFieldMonthlyCost       = "monthlyCost"
+	filterFieldMonthlyCostChange = "monthlyCostChange"
+)
+
+// filterOperators are the supported operators, longest first so >= is matched
+// before >.
+var filterOperators = []string{">=", "<=", "!=", ">", "<", "="}
+
+// ResourceFilter is a parsed --filter expression, e.g. monthlyCost>100 or
+// resourceType=azurerm_*. The name and resourceType fields are compared with
+// glob patterns using = and !=. The monthlyCost and monthlyCostChange fields
+// are compared as numbers, monthlyCostChange is the absolute change in the
+// monthly cost of the resource so it matches increases and decreases.
+type ResourceFilter struct {
+	field string
+	op    string
+	value string
+	cost  decimal.Decimal
+}
+
+// ParseResourceFilters parses the --filter expressions. Resources must match
+// all the filters to be included in the output.
+func ParseResourceFilters(exprs []string) ([]ResourceFilter, error) {
+	filters := make([]ResourceFilter, 0, len(exprs))
+
+	for _, expr := range exprs {
+		f, err := parseResourceFilter(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, f)
+	}
+
+	return filters, nil
+}
+
+func parseResourceFilter(expr string) (ResourceFilter, error) {
+	for _, op := range filterOperators {
+		field, value, ok := strings.Cut(expr, op)
+		if !ok {
+			continue
+		}
+
+		f := ResourceFilter{field: strings.TrimSpace(field), op: op, value: strings.TrimSpace(value)}
+
+		switch f.field {
+		case filterFieldName, filterFieldResourceType:
+			if op != "=" && op != "!=" {
+				return f, fmt.Errorf("Invalid filter '%s', %s can only be compared with = or !=", expr, f.field)
+			}
+
+			if _, err := path.Match(f.value, ""); err != nil {
+				return f, fmt.Errorf("Invalid pattern in filter '%s': %w", expr, err)
+			}
+		case filterFieldMonthlyCost, filterFieldMonthlyCostChange:
+			cost, err := decimal.NewFromString(f.value)
+			if err != nil {
+				return f, fmt.Errorf("Invalid filter '%s', %s must be compared with a number", expr, f.field)
+			}
+
+			f.cost = cost
+		default:
+			return f, fmt.Errorf("Invalid filter field '%s', supported fields are name, resourceType, monthlyCost and monthlyCostChange", f.field)
+		}
+
+		return f, nil
+	}
+
+	return ResourceFilter{}, fmt.Errorf("Invalid filter '%s', use <field><operator><value>, e.g. monthlyCost>100", expr)
+}
+
+// filterResource is the values of a resource that filters are matched against.
+type filterResource struct {
+	name              string
+	resourceType      string
+	monthlyCost       decimal.Decimal
+	monthlyCostChange decimal.Decimal
+}
+
+func (f ResourceFilter) matches(r filterResource) bool {
+	switch f.field {
+	case filterFieldName, filterFieldResourceType:
+		s := r.name
+		if f.field == filterFieldResourceType {
+			s = r.resourceType
+		}
+
+		ok, _ := path.Match(f.value, s)
+		if f.op == "!=" {
+			return !ok
+		}
+
+		return ok
+	}
+
+	v := r.monthlyCost
+	if f.field == filterFieldMonthlyCostChange {
+		v = r.monthlyCostChange.Abs()
+	}
+
+	switch f.op {
+	case ">":
+		return v.GreaterThan(f.cost)
+	case ">=":
+		return v.GreaterThanOrEqual(f.cost)
+	case "<":
+		return v.LessThan(f.cost)
+	case "<=":
+		return v.LessThanOrEqual(f.cost)
+	case "!=":
+		return !v.Equal(f.cost)
+	}
+
+	return v.Equal(f.cost)
+}
+
+// FilterResources removes the resources that don't match all the filters from
+// the breakdowns and diffs of the projects and recalculates the totals, so the
+// table, JSON, diff and comment output formats only show the matching
+// resources. The monthly cost of a resource is its cost after the change, or
+// before it if the resource is removed.
+func FilterResources(r *Root, filters []ResourceFilter) {
+	if len(filters) == 0 {
+		return
+	}
+
+	for i := range r.Projects {
+		p := &r.Projects[i]
+
+		resources := make(map[string]*filterResource)
+		add := func(b *Breakdown, setCost bool) {
+			if b == nil {
+				return
+			}
+
+			for _, res := range b.Resources {
+				fr, ok := resources[res.Name]
+				if !ok {
+					fr = &filterResource{name: res.Name, resourceType: res.ResourceType()}
+					resources[res.Name] = fr
+				}
+
+				if res.MonthlyCost == nil {
+					continue
+				}
+
+				if setCost {
+					fr.monthlyCost = *res.MonthlyCost
+				} else {
+					fr.monthlyCostChange = *res.MonthlyCost
+				}
+			}
+		}
+
+		// The past costs are added first so they're replaced by the current
+		// costs of resources that still exist.
+		add(p.PastBreakdown, true)
+		add(p.Breakdown, true)
+		add(p.Diff, false)
+
+		keep := make(map[string]bool, len(resources))
+		for name, fr := range resources {
+			keep[name] = true
+			for _, f := range filters {
+				if !f.matches(*fr) {
+					keep[name] = false
+					break
+				}
+			}
+		}
+
+		filterBreakdown(p.PastBreakdown, keep)
+		filterBreakdown(p.Breakdown, keep)
+		filterBreakdown(p.Diff, keep)
+	}
+
+	r.recalculateTotals()
+}
+
+func filterBreakdown(b *Breakdown, keep map[string]bool) {
+	if b == nil {
+		return
+	}
+
+	resources := make([]Resource, 0, len(b.Resources))
+	for _, res := range b.Resources {
+		if keep[res.Name] {
+			resources = append(resources, res)
+		}
+	}
+
+	b.Resources = resources
+	b.TotalHourlyCost, b.TotalMonthlyCost = calculateTotalCosts(resources)
+	b.TotalLowMonthlyCost, b.TotalHighMonthlyCost = resourcesCostRange(resources)
+	b.Projections = resourcesProjections(resources)
+}
+
+// ExcludeProjects removes the projects whose path matches any of the glob
+// patterns, or is in a directory that matches them, and recalculates the
+// totals and summary.
+func ExcludeProjects(r *Root, patterns []string) {
+	if len(patterns) == 0 {
+		return
+	}
+
+	projects := make([]Project, 0, len(r.Projects))
+	summaries := make([]*Summary, 0, len(r.Projects))
+	for _, p := range r.Projects {
+		if p.Metadata != nil && projectPathExcluded(p.Metadata.Path, patterns) {
+			continue
+		}
+
+		projects = append(projects, p)
+		summaries = append(summaries, p.Summary)
+	}
+
+	if len(projects) == len(r.Projects) {
+		return
+	}
+
+	r.Projects = projects
+	r.Summary = MergeSummaries(summaries)
+	r.recalculateTotals()
+}
+
+func projectPathExcluded(projectPath string, patterns []string) bool {
+	projectPath = path.Clean(strings.TrimPrefix(projectPath, "./"))
+
+	for _, pattern := range patterns {
+		pattern = path.Clean(strings.TrimPrefix(pattern, "./"))
+
+		// Match the directories that the project is in as well as the project
+		// path so a pattern can exclude all the projects in a directory.
+		for p := projectPath; p != "." && p != "/"; p = path.Dir(p) {
+			if ok, _ := path.Match(pattern, p); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// recalculateTotals sets the totals of r from the totals of the breakdowns and
+// diffs of its projects. Totals that weren't set are left unset.
+func (r *Root) recalculateTotals() {
+	var total, past, diff [2]*decimal.Decimal
+
+	addTotals := func(totals *[2]*decimal.Decimal, b *Breakdown) {
+		if b == nil {
+			return
+		}
+
+		for i, c := range []*decimal.Decimal{b.TotalHourlyCost, b.TotalMonthlyCost} {
+			if c != nil {
+				totals[i] = decimalPtr(decimalValue(totals[i]).Add(*c))
+			}
+		}
+	}
+
+	for _, p := range r.Projects {
+		addTotals(&total, p.Breakdown)
+		addTotals(&past, p.PastBreakdown)
+		addTotals(&diff, p.Diff)
+	}
+
+	if r.TotalHourlyCost != nil {
+		r.TotalHourlyCost = decimalPtr(decimalValue(total[0]))
+	}
+	if r.TotalMonthlyCost != nil {
+		r.TotalMonthlyCost = decimalPtr(decimalValue(total[1]))
+	}
+	if r.PastTotalHourlyCost != nil {
+		r.PastTotalHourlyCost = decimalPtr(decimalValue(past[0]))
+	}
+	if r.PastTotalMonthlyCost != nil {
+		r.PastTotalMonthlyCost = decimalPtr(decimalValue(past[1]))
+	}
+	if r.DiffTotalHourlyCost != nil {
+		r.DiffTotalHourlyCost = decimalPtr(decimalValue(diff[0]))
+	}
+	if r.DiffTotalMonthlyCost != nil {
+		r.DiffTotalMonthlyCost = decimalPtr(decimalValue(diff[1]))
+	}
+
+	r.TotalLowMonthlyCost, r.TotalHighMonthlyCost = projectsCostRange(r.Projects)
+	r.Projections = projectsProjections(r.Projects)
+}
@@ -0,0 +1,117 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestParseResourceFiltersInvalid(t *testing.T) {
+	_, err := ParseResourceFilters([]string{"monthlyCost"})
+	assert.EqualError(t, err, "Invalid filter 'monthlyCost', use <field><operator><value>, e.g. monthlyCost>100")
+
+	_, err = ParseResourceFilters([]string{"cost>10"})
+	assert.EqualError(t, err, "Invalid filter field 'cost', supported fields are name, resourceType, monthlyCost and monthlyCostChange")
+
+	_, err = ParseResourceFilters([]string{"monthlyCost>abc"})
+	assert.EqualError(t, err, "Invalid filter 'monthlyCost>abc', monthlyCost must be compared with a number")
+
+	_, err = ParseResourceFilters([]string{"resourceType>aws_*"})
+	assert.EqualError(t, err, "Invalid filter 'resourceType>aws_*', resourceType can only be compared with = or !=")
+}
+
+func TestFilterResources(t *testing.T) {
+	resource := func(name string, cost int64) Resource {
+		return Resource{Name: name, MonthlyCost: decimalPtr(decimal.NewFromInt(cost))}
+	}
+
+	root := Root{
+		TotalMonthlyCost:     decimalPtr(decimal.NewFromInt(265)),
+		PastTotalMonthlyCost: decimalPtr(decimal.NewFromInt(200)),
+		DiffTotalMonthlyCost: decimalPtr(decimal.NewFromInt(65)),
+		Projects: []Project{
+			{
+				Name: "app",
+				PastBreakdown: &Breakdown{Resources: []Resource{
+					resource("aws_instance.web", 100),
+					resource("aws_db_instance.db", 90),
+					resource("aws_instance.old", 10),
+				}},
+				Breakdown: &Breakdown{Resources: []Resource{
+					resource("aws_instance.web", 200),
+					resource("aws_db_instance.db", 60),
+					resource("azurerm_linux_virtual_machine.vm", 5),
+				}},
+				Diff: &Breakdown{Resources: []Resource{
+					resource("aws_instance.web", 100),
+					resource("aws_db_instance.db", -30),
+					resource("aws_instance.old", -10),
+					resource("azurerm_linux_virtual_machine.vm", 5),
+				}},
+			},
+		},
+	}
+
+	filters, err := ParseResourceFilters([]string{"monthlyCostChange>=10", "resourceType!=aws_db_*"})
+	require.NoError(t, err)
+
+	FilterResources(&root, filters)
+
+	names := func(b *Breakdown) []string {
+		var arr []string
+		for _, r := range b.Resources {
+			arr = append(arr, r.Name)
+		}
+		return arr
+	}
+
+	p := root.Projects[0]
+	assert.Equal(t, []string{"aws_instance.web", "aws_instance.old"}, names(p.PastBreakdown))
+	assert.Equal(t, []string{"aws_instance.web"}, names(p.Breakdown))
+	assert.Equal(t, []string{"aws_instance.web", "aws_instance.old"}, names(p.Diff))
+	assert.Equal(t, "200", root.TotalMonthlyCost.String())
+	assert.Equal(t, "110", root.PastTotalMonthlyCost.String())
+	assert.Equal(t, "90", root.DiffTotalMonthlyCost.String())
+	assert.Nil(t, root.TotalHourlyCost)
+
+	filters, err = ParseResourceFilters([]string{"monthlyCost<50"})
+	require.NoError(t, err)
+
+	FilterResources(&root, filters)
+	assert.Equal(t, []string{"aws_instance.old"}, names(root.Projects[0].PastBreakdown))
+	assert.Empty(t, root.Projects[0].Breakdown.Resources)
+	assert.Equal(t, "0", root.TotalMonthlyCost.String())
+}
+
+func TestExcludeProjects(t *testing.T) {
+	devCount, prodCount := 1, 2
+
+	root := Root{
+		TotalMonthlyCost: decimalPtr(decimal.NewFromInt(30)),
+		Projects: []Project{
+			{
+				Name:      "dev",
+				Metadata:  &schema.ProjectMetadata{Path: "./envs/dev/app"},
+				Breakdown: &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(10))},
+				Summary:   &Summary{TotalDetectedResources: &devCount},
+			},
+			{
+				Name:      "prod",
+				Metadata:  &schema.ProjectMetadata{Path: "envs/prod/app"},
+				Breakdown: &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(20))},
+				Summary:   &Summary{TotalDetectedResources: &prodCount},
+			},
+		},
+	}
+
+	ExcludeProjects(&root, []string{"envs/d*"})
+
+	require.Len(t, root.Projects, 1)
+	assert.Equal(t, "prod", root.Projects[0].Name)
+	assert.Equal(t, "20", root.TotalMonthlyCost.String())
+	assert.Equal(t, 2, *root.Summary.TotalDetectedResources)
+}
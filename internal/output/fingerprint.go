@@ -0,0 +1,115 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// fingerprintCost returns the cost rounded to the cents shown in the output, so
+// changes that can't be seen don't change the fingerprint.
+func fingerprintCost(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+
+	return d.Round(2).String()
+}
+
+type fingerprintResource struct {
+	Name           string                `json:"name"`
+	MonthlyCost    string                `json:"monthlyCost"`
+	CostComponents map[string]string     `json:"costComponents,omitempty"`
+	SubResources   []fingerprintResource `json:"subresources,omitempty"`
+}
+
+type fingerprintProject struct {
+	Name             string                `json:"name"`
+	PastMonthlyCost  string                `json:"pastMonthlyCost"`
+	MonthlyCost      string                `json:"monthlyCost"`
+	DiffResources    []fingerprintResource `json:"diffResources"`
+	UnsupportedCount *int                  `json:"unsupportedCount,omitempty"`
+}
+
+func newFingerprintResource(r Resource) fingerprintResource {
+	f := fingerprintResource{
+		Name:        r.Name,
+		MonthlyCost: fingerprintCost(r.MonthlyCost),
+	}
+
+	if len(r.CostComponents) > 0 {
+		f.CostComponents = make(map[string]string, len(r.CostComponents))
+		for _, c := range r.CostComponents {
+			f.CostComponents[c.Name] = fingerprintCost(c.MonthlyCost)
+		}
+	}
+
+	for _, s := range r.SubResources {
+		f.SubResources = append(f.SubResources, newFingerprintResource(s))
+	}
+
+	return f
+}
+
+// Fingerprint returns a hash of the parts of out that are material to a
+// reviewer: the cost of each project and the resources and costs that changed,
+// along with any policy or guardrail failures in opts. Unlike a hash of the
+// formatted output it doesn't change when the run ID, share URL, time the
+// output was generated or the order of the projects change, so it can be used
+// to tell if a posted comment is still up to date.
+func Fingerprint(out Root, opts Options) string {
+	projects := make([]fingerprintProject, 0, len(out.Projects))
+
+	for _, p := range out.Projects {
+		f := fingerprintProject{
+			Name:          p.Name,
+			DiffResources: []fingerprintResource{},
+		}
+
+		if p.PastBreakdown != nil {
+			f.PastMonthlyCost = fingerprintCost(p.PastBreakdown.TotalMonthlyCost)
+		}
+
+		if p.Breakdown != nil {
+			f.MonthlyCost = fingerprintCost(p.Breakdown.TotalMonthlyCost)
+		}
+
+		if p.Diff != nil {
+			for _, r := range p.Diff.Resources {
+				f.DiffResources = append(f.DiffResources, newFingerprintResource(r))
+			}
+		}
+
+		if p.Summary != nil {
+			f.UnsupportedCount = p.Summary.TotalUnsupportedResources
+		}
+
+		projects = append(projects, f)
+	}
+
+	sort.SliceStable(projects, func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	})
+
+	b, _ := json.Marshal(struct {
+		Currency          string               `json:"currency"`
+		Projects          []fingerprintProject `json:"projects"`
+		PolicyFailures    PolicyCheckFailures  `json:"policyFailures,omitempty"`
+		GuardrailFailures GuardrailFailures    `json:"guardrailFailures,omitempty"`
+		ShowAllProjects   bool                 `json:"showAllProjects,omitempty"`
+		ShowOnlyChanges   bool                 `json:"showOnlyChanges,omitempty"`
+	}{
+		Currency:          out.Currency,
+		Projects:          projects,
+		PolicyFailures:    opts.PolicyChecks.Failures,
+		GuardrailFailures: opts.GuardrailCheck.CommentableFailures(),
+		ShowAllProjects:   opts.ShowAllProjects,
+		ShowOnlyChanges:   opts.ShowOnlyChanges,
+	})
+
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:8])
+}
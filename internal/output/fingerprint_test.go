@@ -0,0 +1,48 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	root := func(cost string) Root {
+		d := decimal.RequireFromString(cost)
+
+		return Root{
+			Currency:      "USD",
+			RunID:         "run",
+			TimeGenerated: time.Now(),
+			Projects: Projects{
+				{
+					Name:      "app",
+					Breakdown: &Breakdown{TotalMonthlyCost: &d},
+					Diff: &Breakdown{
+						Resources: []Resource{{Name: "aws_instance.web", MonthlyCost: &d}},
+					},
+				},
+				{
+					Name:      "network",
+					Breakdown: &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(10))},
+					Diff:      &Breakdown{},
+				},
+			},
+		}
+	}
+
+	fingerprint := Fingerprint(root("100.001"), Options{})
+	assert.Len(t, fingerprint, 16)
+
+	r := root("100.004")
+	r.RunID = "another run"
+	r.ShareURL = "https://dashboard.infracost.io/share/abc"
+	r.TimeGenerated = time.Now().Add(time.Hour)
+	r.Projects[0], r.Projects[1] = r.Projects[1], r.Projects[0]
+	assert.Equal(t, fingerprint, Fingerprint(r, Options{}), "changes that aren't shown should not change the fingerprint")
+
+	assert.NotEqual(t, fingerprint, Fingerprint(root("101"), Options{}))
+	assert.NotEqual(t, fingerprint, Fingerprint(root("100.001"), Options{PolicyChecks: PolicyCheck{Failures: PolicyCheckFailures{"too expensive"}}}))
+}
@@ -0,0 +1,159 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// focusColumns are the columns of the FinOps Open Cost and Usage Specification (FOCUS)
+// that can be populated from an estimate. Columns prefixed with x_ are custom columns
+// holding Infracost specific information.
+var focusColumns = []string{
+	"BilledCost",
+	"BillingCurrency",
+	"BillingPeriodEnd",
+	"BillingPeriodStart",
+	"ChargeCategory",
+	"ChargeDescription",
+	"ChargePeriodEnd",
+	"ChargePeriodStart",
+	"EffectiveCost",
+	"ListCost",
+	"ListUnitPrice",
+	"PricingQuantity",
+	"PricingUnit",
+	"ProviderName",
+	"PublisherName",
+	"InvoiceIssuerName",
+	"ResourceId",
+	"ResourceName",
+	"ResourceType",
+	"SubAccountName",
+	"Tags",
+	"x_CostComponent",
+	"x_UsageBased",
+}
+
+// focusProviders maps the resource type prefix to the name of the provider.
+var focusProviders = map[string]string{
+	"aws":     "AWS",
+	"azurerm": "Microsoft",
+	"google":  "Google Cloud",
+}
+
+// ToFOCUS returns the estimate as CSV rows shaped like the FinOps Open Cost and Usage
+// Specification so that it can be loaded into FinOps tooling alongside actual costs.
+// Each cost component of the planned state is a row, priced for the calendar month
+// that the output was generated in.
+func ToFOCUS(out Root, opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	err := w.Write(focusColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	generated := out.TimeGenerated.UTC()
+	periodStart := time.Date(generated.Year(), generated.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+
+	for _, project := range out.Projects {
+		if project.Breakdown == nil {
+			continue
+		}
+
+		for _, r := range project.Breakdown.Resources {
+			rows, err := focusResourceRows(out.Currency, project.Name, r, r, periodStart, periodEnd)
+			if err != nil {
+				return nil, err
+			}
+
+			err = w.WriteAll(rows)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func focusResourceRows(currency, projectName string, root, r Resource, periodStart, periodEnd time.Time) ([][]string, error) {
+	tags := "{}"
+	if len(root.Tags) > 0 {
+		b, err := json.Marshal(root.Tags)
+		if err != nil {
+			return nil, err
+		}
+		tags = string(b)
+	}
+
+	resourceType := root.ResourceType()
+	provider := focusProviders[strings.Split(resourceType, "_")[0]]
+
+	var rows [][]string
+	for _, c := range r.CostComponents {
+		description := c.Name
+		if r.Name != root.Name {
+			description = r.Name + ": " + c.Name
+		}
+
+		// The costs and quantity are left empty if they depend on usage that
+		// isn't set, so they aren't mistaken for free usage.
+		cost := ""
+		if c.MonthlyCost != nil {
+			cost = c.MonthlyCost.String()
+		}
+
+		quantity := ""
+		if c.MonthlyQuantity != nil {
+			quantity = c.MonthlyQuantity.String()
+		}
+
+		rows = append(rows, []string{
+			cost,
+			currency,
+			formatFOCUSTime(periodEnd),
+			formatFOCUSTime(periodStart),
+			"Usage",
+			description,
+			formatFOCUSTime(periodEnd),
+			formatFOCUSTime(periodStart),
+			cost,
+			cost,
+			c.Price.String(),
+			quantity,
+			c.Unit,
+			provider,
+			provider,
+			provider,
+			root.Name,
+			root.Name,
+			resourceType,
+			projectName,
+			tags,
+			c.Name,
+			strconv.FormatBool(c.UsageBased),
+		})
+	}
+
+	for _, s := range r.SubResources {
+		subRows, err := focusResourceRows(currency, projectName, root, s, periodStart, periodEnd)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, subRows...)
+	}
+
+	return rows, nil
+}
+
+func formatFOCUSTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
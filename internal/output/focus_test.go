@@ -0,0 +1,99 @@
+package output
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToFOCUS(t *testing.T) {
+	out := Root{
+		Currency:      "USD",
+		TimeGenerated: time.Date(2023, 5, 17, 10, 0, 0, 0, time.UTC),
+		Projects: []Project{
+			{
+				Name: "infracost/example",
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							Name: "aws_instance.web",
+							Tags: map[string]string{"team": "web"},
+							CostComponents: []CostComponent{
+								{
+									Name:            "Instance usage",
+									Unit:            "hours",
+									MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)),
+									Price:           decimal.RequireFromString("0.1"),
+									MonthlyCost:     decimalPtr(decimal.NewFromInt(73)),
+								},
+							},
+							SubResources: []Resource{
+								{
+									Name: "root_block_device",
+									CostComponents: []CostComponent{
+										{Name: "Storage", Unit: "GB", Price: decimal.RequireFromString("0.08"), UsageBased: true},
+										{
+											Name:            "Provisioned IOPS",
+											Unit:            "IOPS",
+											MonthlyQuantity: decimalPtr(decimal.Zero),
+											Price:           decimal.RequireFromString("0.065"),
+											MonthlyCost:     decimalPtr(decimal.Zero),
+											UsageBased:      true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := ToFOCUS(out, Options{})
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(string(b))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.Equal(t, focusColumns, rows[0])
+
+	row := map[string]string{}
+	for i, col := range rows[0] {
+		row[col] = rows[1][i]
+	}
+
+	assert.Equal(t, "73", row["BilledCost"])
+	assert.Equal(t, "2023-05-01T00:00:00Z", row["BillingPeriodStart"])
+	assert.Equal(t, "2023-06-01T00:00:00Z", row["BillingPeriodEnd"])
+	assert.Equal(t, "730", row["PricingQuantity"])
+	assert.Equal(t, "AWS", row["ProviderName"])
+	assert.Equal(t, "aws_instance", row["ResourceType"])
+	assert.Equal(t, "infracost/example", row["SubAccountName"])
+	assert.Equal(t, `{"team":"web"}`, row["Tags"])
+	assert.Equal(t, "false", row["x_UsageBased"])
+
+	for i, col := range rows[0] {
+		row[col] = rows[2][i]
+	}
+
+	assert.Equal(t, "", row["BilledCost"])
+	assert.Equal(t, "", row["PricingQuantity"])
+	assert.Equal(t, "root_block_device: Storage", row["ChargeDescription"])
+	assert.Equal(t, "aws_instance.web", row["ResourceId"])
+	assert.Equal(t, "true", row["x_UsageBased"])
+
+	for i, col := range rows[0] {
+		row[col] = rows[3][i]
+	}
+
+	assert.Equal(t, "0", row["BilledCost"])
+	assert.Equal(t, "0", row["PricingQuantity"])
+	assert.Equal(t, "root_block_device: Provisioned IOPS", row["ChargeDescription"])
+	assert.Equal(t, "true", row["x_UsageBased"])
+}
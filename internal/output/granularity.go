@@ -0,0 +1,124 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// costGranularity is a period of time that the table output format can show
+// costs for with --show-granularity.
+type costGranularity struct {
+	name string
+	// field is the table field of the cost column of the granularity.
+	field  string
+	title  string
+	suffix string
+	hours  int64
+}
+
+// hoursInMonth is the number of hours that monthly costs are calculated for.
+const hoursInMonth = 730
+
+// costGranularities are the granularities in the order of their table
+// columns. The costs of the granularities shorter than a month are calculated
+// from the hourly costs and the others from the monthly costs, so that they
+// add up to the monthly costs in the other output formats.
+var costGranularities = []costGranularity{
+	{name: "hourly", field: "hourlyCost", title: "Hourly Cost", suffix: "/hr", hours: 1},
+	{name: "daily", field: "dailyCost", title: "Daily Cost", suffix: "/day", hours: 24},
+	{name: "monthly", field: "monthlyCost", title: "Monthly Cost", suffix: "/mo", hours: hoursInMonth},
+	{name: "annual", field: "annualCost", title: "Annual Cost", suffix: "/yr", hours: 8760},
+}
+
+// ValidateGranularities returns an error if any of the granularities set with
+// --show-granularity isn't valid.
+func ValidateGranularities(granularities []string) error {
+	names := make([]string, 0, len(costGranularities))
+	for _, g := range costGranularities {
+		names = append(names, g.name)
+	}
+
+	for _, s := range granularities {
+		if !contains(names, s) {
+			return fmt.Errorf("Invalid granularity '%s' in --show-granularity, valid granularities are: %s", s, strings.Join(names, ", "))
+		}
+	}
+
+	return nil
+}
+
+// granularityFields returns the table fields with the cost fields replaced by
+// the cost fields of the granularities.
+func granularityFields(fields []string, granularities []string) []string {
+	costFields := make(map[string]bool, len(costGranularities))
+	for _, g := range costGranularities {
+		costFields[g.field] = true
+	}
+
+	result := make([]string, 0, len(fields)+len(granularities))
+	for _, f := range fields {
+		if !costFields[f] {
+			result = append(result, f)
+		}
+	}
+
+	for _, g := range costGranularities {
+		if contains(granularities, g.name) {
+			result = append(result, g.field)
+		}
+	}
+
+	return result
+}
+
+// granularityForField returns the granularity of the cost field, if it's
+// one.
+func granularityForField(field string) (costGranularity, bool) {
+	for _, g := range costGranularities {
+		if g.field == field {
+			return g, true
+		}
+	}
+
+	return costGranularity{}, false
+}
+
+// cost returns the cost for the granularity from the hourly or monthly cost.
+func (g costGranularity) cost(hourlyCost *decimal.Decimal, monthlyCost *decimal.Decimal) *decimal.Decimal {
+	if g.hours >= hoursInMonth {
+		if monthlyCost == nil {
+			return nil
+		}
+
+		return decimalPtr(monthlyCost.Mul(decimal.NewFromInt(g.hours / hoursInMonth)))
+	}
+
+	if hourlyCost == nil {
+		return nil
+	}
+
+	return decimalPtr(hourlyCost.Mul(decimal.NewFromInt(g.hours)))
+}
+
+// granularityTotals returns the total cost for each of the granularities,
+// e.g. "$1.37/hr  $32.88/day", for the overall total of the table output.
+func granularityTotals(out Root, granularities []string) string {
+	totals := make([]string, 0, len(granularities))
+
+	for _, g := range costGranularities {
+		if !contains(granularities, g.name) {
+			continue
+		}
+
+		total := FormatCost2DP(out.Currency, g.cost(out.TotalHourlyCost, out.TotalMonthlyCost))
+		if g.name == "monthly" {
+			total = formatCostRange(out.Currency, out.TotalMonthlyCost, out.TotalLowMonthlyCost, out.TotalHighMonthlyCost)
+		}
+
+		totals = append(totals, total+g.suffix)
+	}
+
+	return strings.Join(totals, "  ")
+}
@@ -0,0 +1,32 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGranularityFields(t *testing.T) {
+	assert.Equal(t,
+		[]string{"price", "unit", "hourlyCost", "dailyCost", "annualCost"},
+		granularityFields([]string{"price", "monthlyCost", "unit", "hourlyCost"}, []string{"annual", "daily", "hourly"}),
+	)
+
+	assert.NoError(t, ValidateGranularities([]string{"hourly", "daily", "monthly", "annual"}))
+	assert.EqualError(t, ValidateGranularities([]string{"hourly", "weekly"}), "Invalid granularity 'weekly' in --show-granularity, valid granularities are: hourly, daily, monthly, annual")
+}
+
+func TestGranularityCost(t *testing.T) {
+	hourly := decimalPtr(decimal.NewFromFloat(0.5))
+	monthly := decimalPtr(decimal.NewFromInt(365))
+
+	costs := make([]string, 0, len(costGranularities))
+	for _, g := range costGranularities {
+		costs = append(costs, g.cost(hourly, monthly).String())
+	}
+
+	// Annual costs are 12 times the monthly costs so they add up to them.
+	assert.Equal(t, []string{"0.5", "12", "365", "4380"}, costs)
+	assert.Nil(t, costGranularities[1].cost(nil, nil))
+}
@@ -44,6 +44,9 @@ func ToHTML(out Root, opts Options) ([]byte, error) {
 		"filterZeroValComponents": filterZeroValComponents,
 		"filterZeroValResources":  filterZeroValResources,
 		"formatCost2DP":           func(d *decimal.Decimal) string { return FormatCost2DP(out.Currency, d) },
+		"formatCostRange": func(d, low, high *decimal.Decimal) string {
+			return formatCostRange(out.Currency, d, low, high)
+		},
 		"formatPrice":             func(d decimal.Decimal) string { return formatPrice(out.Currency, d) },
 		"formatTitleWithCurrency": func(title string) string { return formatTitleWithCurrency(title, out.Currency) },
 		"formatQuantity":          formatQuantity,
@@ -56,6 +59,14 @@ func ToHTML(out Root, opts Options) ([]byte, error) {
 		"projectWorkspace": func(p Project) string {
 			return p.Metadata.WorkspaceLabel()
 		},
+		"rootHasDiff":      out.HasDiff,
+		"projectHasDiff":   projectHasDiff,
+		"resourceDiffs":    resourceDiffs,
+		"removedResources": removedResources,
+		"formatCostChange": func(d *decimal.Decimal) string { return formatCostChange2DP(out.Currency, d) },
+		"costChangeClass":  costChangeClass,
+		"sortValue":        sortValue,
+		"costSunburst":     costSunburst,
 	})
 	tmpl, err := tmpl.Parse(HTMLTemplate)
 	if err != nil {
@@ -76,3 +87,96 @@ func ToHTML(out Root, opts Options) ([]byte, error) {
 	bufw.Flush()
 	return buf.Bytes(), nil
 }
+
+// htmlDiff is the change of a top level resource in a project that has a
+// diff. Status is added, removed or changed.
+type htmlDiff struct {
+	Status      string
+	MonthlyCost *decimal.Decimal
+}
+
+func projectHasDiff(p Project) bool {
+	return p.Diff != nil && len(p.Diff.Resources) > 0
+}
+
+// resourceDiffs returns the changes of the top level resources of the
+// project, keyed by their name.
+func resourceDiffs(p Project) map[string]htmlDiff {
+	diffs := make(map[string]htmlDiff)
+	if !projectHasDiff(p) {
+		return diffs
+	}
+
+	var past, current []Resource
+	if p.PastBreakdown != nil {
+		past = p.PastBreakdown.Resources
+	}
+	if p.Breakdown != nil {
+		current = p.Breakdown.Resources
+	}
+
+	for _, r := range p.Diff.Resources {
+		status := "changed"
+		if findResourceByName(past, r.Name) == nil {
+			status = "added"
+		} else if findResourceByName(current, r.Name) == nil {
+			status = "removed"
+		}
+
+		diffs[r.Name] = htmlDiff{Status: status, MonthlyCost: r.MonthlyCost}
+	}
+
+	return diffs
+}
+
+// removedResources returns the resources of the past breakdown of the
+// project that aren't in its breakdown, so they can be shown as removed.
+func removedResources(p Project) []Resource {
+	if !projectHasDiff(p) || p.PastBreakdown == nil {
+		return nil
+	}
+
+	var current []Resource
+	if p.Breakdown != nil {
+		current = p.Breakdown.Resources
+	}
+
+	var removed []Resource
+	for _, r := range p.PastBreakdown.Resources {
+		if findResourceByName(current, r.Name) == nil {
+			removed = append(removed, r)
+		}
+	}
+
+	return removed
+}
+
+func formatCostChange2DP(currency string, d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+
+	abs := d.Abs()
+	return getSym(*d) + FormatCost2DP(currency, &abs)
+}
+
+func costChangeClass(d *decimal.Decimal) string {
+	if d == nil || d.IsZero() {
+		return ""
+	}
+
+	if d.IsPositive() {
+		return "cost-increase"
+	}
+
+	return "cost-decrease"
+}
+
+// sortValue returns the value that rows are sorted by in the HTML report.
+func sortValue(d *decimal.Decimal) string {
+	if d == nil {
+		return "0"
+	}
+
+	return d.String()
+}
@@ -0,0 +1,90 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestToHTMLDiff(t *testing.T) {
+	d := func(i int64) *decimal.Decimal { return decimalPtr(decimal.NewFromInt(i)) }
+
+	resource := func(name string, cost int64) Resource {
+		return Resource{
+			Name:        name,
+			MonthlyCost: d(cost),
+			CostComponents: []CostComponent{
+				{Name: "Instance usage", Unit: "hours", MonthlyQuantity: d(730), Price: decimal.NewFromInt(cost).Div(decimal.NewFromInt(730)), MonthlyCost: d(cost)},
+			},
+		}
+	}
+
+	project := Project{
+		Name:     "app",
+		Metadata: &schema.ProjectMetadata{},
+		PastBreakdown: &Breakdown{
+			Resources:        []Resource{resource("aws_instance.web", 100), resource("aws_instance.old", 50)},
+			TotalMonthlyCost: d(150),
+		},
+		Breakdown: &Breakdown{
+			Resources:        []Resource{resource("aws_instance.web", 200), resource("module.db.aws_db_instance.main", 300)},
+			TotalMonthlyCost: d(500),
+		},
+		Diff: &Breakdown{
+			Resources:        []Resource{resource("aws_instance.web", 100), resource("aws_instance.old", -50), resource("module.db.aws_db_instance.main", 300)},
+			TotalMonthlyCost: d(350),
+		},
+	}
+
+	assert.Equal(t, map[string]htmlDiff{
+		"aws_instance.web":               {Status: "changed", MonthlyCost: d(100)},
+		"aws_instance.old":               {Status: "removed", MonthlyCost: d(-50)},
+		"module.db.aws_db_instance.main": {Status: "added", MonthlyCost: d(300)},
+	}, resourceDiffs(project))
+
+	removed := removedResources(project)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "aws_instance.old", removed[0].Name)
+
+	b, err := ToHTML(Root{
+		Currency:             "USD",
+		Projects:             []Project{project},
+		TotalMonthlyCost:     d(500),
+		PastTotalMonthlyCost: d(150),
+		DiffTotalMonthlyCost: d(350),
+	}, Options{Fields: []string{"monthlyQuantity", "unit", "monthlyCost"}})
+	require.NoError(t, err)
+
+	html := string(b)
+	assert.Contains(t, html, `<tbody class="resource-group changed" data-name="aws_instance.web" data-monthly-cost="200" data-diff="100">`)
+	assert.Contains(t, html, `<tbody class="resource-group added" data-name="module.db.aws_db_instance.main" data-monthly-cost="300" data-diff="300">`)
+	assert.Contains(t, html, `<tbody class="resource-group removed" data-name="aws_instance.old" data-monthly-cost="50" data-diff="-50">`)
+	assert.Contains(t, html, `<span class="cost-decrease">-$50.00</span>`)
+	assert.Contains(t, html, `<th class="diff" data-sort="diff">Monthly Diff</th>`)
+	assert.Contains(t, html, `<title>app › module.db: $300.00</title>`)
+}
+
+func TestToHTMLNoDiff(t *testing.T) {
+	d := func(i int64) *decimal.Decimal { return decimalPtr(decimal.NewFromInt(i)) }
+
+	b, err := ToHTML(Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name:      "app",
+				Metadata:  &schema.ProjectMetadata{},
+				Breakdown: &Breakdown{Resources: []Resource{{Name: "aws_instance.web", MonthlyCost: d(100)}}, TotalMonthlyCost: d(100)},
+			},
+		},
+		TotalMonthlyCost: d(100),
+	}, Options{Fields: []string{"monthlyQuantity", "unit", "monthlyCost"}})
+	require.NoError(t, err)
+
+	html := string(b)
+	assert.NotContains(t, html, `class="diff"`)
+	assert.Contains(t, html, `<tbody class="resource-group" data-name="aws_instance.web" data-monthly-cost="100" data-diff="0">`)
+}
@@ -0,0 +1,203 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ignoresFileVersion = "0.1"
+	ignoreDateLayout   = "2006-01-02"
+)
+
+// IgnoresFile is a file of policy and budget failures that have been reviewed
+// and are accepted, so they don't fail runs, e.g.
+//
+//	version: 0.1
+//	ignores:
+//	  - policy: "aws_instance.batch* costs more than $500/mo"
+//	    reason: Approved for the Q3 data migration
+//	    expires: 2024-09-30
+//	  - budget: project my-org/my-repo/prod*
+//	    reason: Temporary capacity for the launch
+//
+// Policy and budget are glob patterns, where * matches any text, of the
+// message of the policy failure or the budget the failure is for. An ignore
+// stops working after its expiry date, so the failure fails runs again.
+type IgnoresFile struct {
+	Version string   `yaml:"version"`
+	Ignores []Ignore `yaml:"ignores"`
+}
+
+// Ignore accepts the policy or budget failures that match it until it
+// expires.
+type Ignore struct {
+	Policy  string `yaml:"policy,omitempty"`
+	Budget  string `yaml:"budget,omitempty"`
+	Reason  string `yaml:"reason"`
+	Expires string `yaml:"expires,omitempty"`
+
+	pattern *regexp.Regexp
+	expires *time.Time
+}
+
+// IgnoredFailure is a policy or budget failure that doesn't fail the run as
+// it matches an ignore.
+type IgnoredFailure struct {
+	Failure string `json:"failure"`
+	Reason  string `json:"reason"`
+	Expires string `json:"expires,omitempty"`
+}
+
+// LoadIgnores reads and validates the ignores file at path. It returns no
+// ignores if path is empty.
+func LoadIgnores(path string) ([]Ignore, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading ignores file: %w", err)
+	}
+
+	var f IgnoresFile
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("Error parsing ignores file %s: %w", path, err)
+	}
+
+	if f.Version != ignoresFileVersion {
+		return nil, fmt.Errorf("Ignores file %s has an invalid version %q, the version must be %s", path, f.Version, ignoresFileVersion)
+	}
+
+	for i := range f.Ignores {
+		if err := f.Ignores[i].parse(); err != nil {
+			return nil, fmt.Errorf("Ignore %d in %s is invalid: %w", i+1, path, err)
+		}
+	}
+
+	return f.Ignores, nil
+}
+
+// parse validates the ignore and parses its pattern and expiry date.
+func (i *Ignore) parse() error {
+	if (i.Policy == "") == (i.Budget == "") {
+		return errors.New("it must have a policy or a budget")
+	}
+
+	if strings.TrimSpace(i.Reason) == "" {
+		return errors.New("it must have a reason")
+	}
+
+	if i.Expires != "" {
+		t, err := time.Parse(ignoreDateLayout, i.Expires)
+		if err != nil {
+			return fmt.Errorf("expires %s is not a date in the format YYYY-MM-DD", i.Expires)
+		}
+
+		i.expires = &t
+	}
+
+	glob := i.Policy
+	if glob == "" {
+		glob = i.Budget
+	}
+
+	parts := strings.Split(glob, "*")
+	for j, p := range parts {
+		parts[j] = regexp.QuoteMeta(p)
+	}
+
+	i.pattern = regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+
+	return nil
+}
+
+// expired returns true if now is after the expiry date of the ignore. The
+// ignore still applies on its expiry date.
+func (i Ignore) expired(now time.Time) bool {
+	if i.expires == nil {
+		return false
+	}
+
+	return !now.Before(i.expires.AddDate(0, 0, 1))
+}
+
+// IgnorePolicyFailures moves the failures of checks that match a policy
+// ignore to the ignored failures of checks. Expired ignores don't apply and
+// are logged as warnings.
+func IgnorePolicyFailures(checks *PolicyCheck, ignores []Ignore, now time.Time) {
+	if len(ignores) == 0 {
+		return
+	}
+
+	var failures PolicyCheckFailures
+	for _, f := range checks.Failures {
+		ignored, ok := findIgnore(ignores, f, false, now)
+		if !ok {
+			failures = append(failures, f)
+			continue
+		}
+
+		checks.Ignored = append(checks.Ignored, ignored)
+	}
+
+	checks.Failures = failures
+}
+
+// IgnoreBudgetFailures moves the budget failures of r that match a budget
+// ignore to its ignored budget failures. It returns an error if any failures
+// remain, like CheckBudgets. Expired ignores don't apply and are logged as
+// warnings.
+func IgnoreBudgetFailures(r *Root, ignores []Ignore, now time.Time) error {
+	if len(ignores) > 0 {
+		var failures []BudgetFailure
+		for _, f := range r.BudgetFailures {
+			ignored, ok := findIgnore(ignores, f.Budget, true, now)
+			if !ok {
+				failures = append(failures, f)
+				continue
+			}
+
+			ignored.Failure = fmt.Sprintf("%s: %s", f.Budget, f.Message)
+			r.IgnoredBudgetFailures = append(r.IgnoredBudgetFailures, ignored)
+		}
+
+		r.BudgetFailures = failures
+	}
+
+	if len(r.BudgetFailures) == 0 {
+		return nil
+	}
+
+	return errors.New(r.budgetMessage())
+}
+
+// findIgnore returns the first ignore that matches the policy failure or the
+// budget s and hasn't expired.
+func findIgnore(ignores []Ignore, s string, budget bool, now time.Time) (IgnoredFailure, bool) {
+	for _, i := range ignores {
+		if (i.Budget != "") != budget || !i.pattern.MatchString(s) {
+			continue
+		}
+
+		if i.expired(now) {
+			log.Warnf("The ignore of %s expired on %s and no longer applies: %s", s, i.Expires, i.Reason)
+			continue
+		}
+
+		return IgnoredFailure{Failure: s, Reason: i.Reason, Expires: i.Expires}, true
+	}
+
+	return IgnoredFailure{}, false
+}
@@ -0,0 +1,138 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadIgnores(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		error    string
+	}{
+		{
+			name: "valid",
+			contents: `version: 0.1
+ignores:
+  - policy: "aws_instance.web*"
+    reason: Approved by the platform team
+    expires: 2024-06-30
+  - budget: total
+    reason: Launch capacity
+`,
+		},
+		{
+			name:     "invalid version",
+			contents: "version: 0.2\nignores: []\n",
+			error:    `has an invalid version "0.2", the version must be 0.1`,
+		},
+		{
+			name:     "unknown key",
+			contents: "version: 0.1\nignores:\n  - policy: x\n    reason: y\n    until: 2024-01-01\n",
+			error:    "field until not found",
+		},
+		{
+			name:     "missing reason",
+			contents: "version: 0.1\nignores:\n  - policy: x\n",
+			error:    "Ignore 1 in",
+		},
+		{
+			name:     "policy and budget",
+			contents: "version: 0.1\nignores:\n  - policy: x\n    budget: total\n    reason: y\n",
+			error:    "it must have a policy or a budget",
+		},
+		{
+			name:     "invalid expires",
+			contents: "version: 0.1\nignores:\n  - policy: x\n    reason: y\n    expires: 30/06/2024\n",
+			error:    "expires 30/06/2024 is not a date in the format YYYY-MM-DD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "infracost-ignores.yml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.contents), 0600))
+
+			ignores, err := LoadIgnores(path)
+			if tt.error != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.error)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, ignores, 2)
+		})
+	}
+
+	ignores, err := LoadIgnores("")
+	require.NoError(t, err)
+	assert.Nil(t, ignores)
+}
+
+func TestIgnorePolicyFailures(t *testing.T) {
+	ignores := []Ignore{
+		{Policy: "aws_instance.web* costs more than $500/mo", Reason: "Approved", Expires: "2024-06-30"},
+		{Policy: "aws_instance.db*", Reason: "Expired", Expires: "2024-01-31"},
+		{Budget: "*", Reason: "Budgets only"},
+	}
+	for i := range ignores {
+		require.NoError(t, ignores[i].parse())
+	}
+
+	checks := PolicyCheck{
+		Enabled: true,
+		Failures: PolicyCheckFailures{
+			"aws_instance.web[0] costs more than $500/mo",
+			"aws_instance.db costs more than $500/mo",
+			"aws_instance.batch costs more than $500/mo",
+		},
+	}
+
+	IgnorePolicyFailures(&checks, ignores, time.Date(2024, 6, 30, 23, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, PolicyCheckFailures{
+		"aws_instance.db costs more than $500/mo",
+		"aws_instance.batch costs more than $500/mo",
+	}, checks.Failures)
+	assert.Equal(t, []IgnoredFailure{
+		{Failure: "aws_instance.web[0] costs more than $500/mo", Reason: "Approved", Expires: "2024-06-30"},
+	}, checks.Ignored)
+
+	IgnorePolicyFailures(&checks, ignores, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC))
+	assert.Len(t, checks.Failures, 2)
+}
+
+func TestIgnoreBudgetFailures(t *testing.T) {
+	ignores := []Ignore{
+		{Budget: "project envs/*", Reason: "Launch capacity"},
+		{Policy: "total", Reason: "Policies only"},
+	}
+	for i := range ignores {
+		require.NoError(t, ignores[i].parse())
+	}
+
+	root := Root{
+		BudgetFailures: []BudgetFailure{
+			{Budget: "total", Message: "monthly cost of $1,500.00 is over the budget of $1,000.00"},
+			{Budget: "project envs/prod", Message: "monthly cost of $1,300.00 is over the budget of $1,000.00"},
+		},
+	}
+
+	err := IgnoreBudgetFailures(&root, ignores, time.Now())
+	require.EqualError(t, err, `1 budget was exceeded:
+∙ total: monthly cost of $1,500.00 is over the budget of $1,000.00`)
+
+	assert.Equal(t, []IgnoredFailure{
+		{Failure: "project envs/prod: monthly cost of $1,300.00 is over the budget of $1,000.00", Reason: "Launch capacity"},
+	}, root.IgnoredBudgetFailures)
+
+	root.BudgetFailures = root.BudgetFailures[:0]
+	require.NoError(t, IgnoreBudgetFailures(&root, ignores, time.Now()))
+}
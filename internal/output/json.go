@@ -1,9 +1,283 @@
 package output
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"io"
 )
 
 func ToJSON(out Root, opts Options) ([]byte, error) {
-	return json.Marshal(out)
+	var buf bytes.Buffer
+	err := WriteJSON(&buf, out, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// WriteJSON writes the JSON output of out to w. The output is the same as
+// json.Marshal(out), but the projects and resources are encoded one at a time
+// so the encoded JSON isn't held in memory as well as out.
+func WriteJSON(w io.Writer, out Root, opts Options) error {
+	if opts.GroupBy != "" {
+		g, err := parseGroupBy(opts.GroupBy)
+		if err != nil {
+			return err
+		}
+
+		out.CostGroups = costGroups(out.Projects, g)
+	}
+
+	if opts.ShowResourceSummary {
+		out.ResourceSummary = out.resourceSummary()
+	}
+
+	if opts.ShowSharedCosts {
+		out.Projects = withSharedCosts(out.Projects)
+	}
+
+	if opts.GroupByScope {
+		out.Projects = withScopeCosts(out.Projects)
+	}
+
+	if opts.OwnershipTag != "" {
+		out.Projects = withOwnershipChanges(out.Projects, opts.OwnershipTag, opts.OwnershipDriftThreshold)
+	}
+
+	jw := newJSONWriter(w)
+	jw.writeRoot(out)
+
+	return jw.flush()
+}
+
+// jsonWriter writes Root to a buffered writer one field at a time. The values
+// of the fields are encoded with a json.Encoder, apart from the projects and
+// resources arrays which are written element by element. The fields must be
+// written in the same order, and with the same omitempty checks, as their
+// struct tags so the output matches json.Marshal.
+type jsonWriter struct {
+	w     *bufio.Writer
+	enc   *json.Encoder
+	err   error
+	comma bool
+}
+
+func newJSONWriter(w io.Writer) *jsonWriter {
+	bw := bufio.NewWriter(w)
+
+	return &jsonWriter{
+		w:   bw,
+		enc: json.NewEncoder(trimNewlineWriter{bw}),
+	}
+}
+
+func (jw *jsonWriter) writeRoot(out Root) {
+	jw.beginObject()
+	jw.field("version", out.Version)
+	jw.field("metadata", out.Metadata)
+	if out.RunID != "" {
+		jw.field("runId", out.RunID)
+	}
+	if out.ShareURL != "" {
+		jw.field("shareUrl", out.ShareURL)
+	}
+	jw.field("currency", out.Currency)
+
+	jw.key("projects")
+	if out.Projects == nil {
+		jw.value(nil)
+	} else {
+		jw.beginArray()
+		for _, p := range out.Projects {
+			jw.element()
+			jw.writeProject(p)
+		}
+		jw.endArray()
+	}
+
+	jw.field("totalHourlyCost", out.TotalHourlyCost)
+	jw.field("totalMonthlyCost", out.TotalMonthlyCost)
+	jw.field("pastTotalHourlyCost", out.PastTotalHourlyCost)
+	jw.field("pastTotalMonthlyCost", out.PastTotalMonthlyCost)
+	jw.field("diffTotalHourlyCost", out.DiffTotalHourlyCost)
+	jw.field("diffTotalMonthlyCost", out.DiffTotalMonthlyCost)
+	jw.field("timeGenerated", out.TimeGenerated)
+	jw.field("summary", out.Summary)
+	if out.ResourceSummary != nil {
+		jw.field("resourceSummary", out.ResourceSummary)
+	}
+	if out.PricingCoverage != nil {
+		jw.field("pricingCoverage", out.PricingCoverage)
+	}
+	if len(out.PricingFailures) > 0 {
+		jw.field("pricingFailures", out.PricingFailures)
+	}
+	if len(out.MissingUsage) > 0 {
+		jw.field("missingUsage", out.MissingUsage)
+	}
+	if len(out.UnknownInputs) > 0 {
+		jw.field("unknownInputs", out.UnknownInputs)
+	}
+	if len(out.UnsupportedResources) > 0 {
+		jw.field("unsupportedResources", out.UnsupportedResources)
+	}
+	if len(out.TagPolicyViolations) > 0 {
+		jw.field("tagPolicyViolations", out.TagPolicyViolations)
+	}
+	if len(out.BudgetFailures) > 0 {
+		jw.field("budgetFailures", out.BudgetFailures)
+	}
+	if len(out.CostAnomalies) > 0 {
+		jw.field("costAnomalies", out.CostAnomalies)
+	}
+	if out.Emissions != nil {
+		jw.field("emissions", out.Emissions)
+	}
+	if out.TotalLowMonthlyCost != nil {
+		jw.field("totalLowMonthlyCost", out.TotalLowMonthlyCost)
+	}
+	if out.TotalHighMonthlyCost != nil {
+		jw.field("totalHighMonthlyCost", out.TotalHighMonthlyCost)
+	}
+	if len(out.Projections) > 0 {
+		jw.field("projections", out.Projections)
+	}
+	if len(out.IgnoredBudgetFailures) > 0 {
+		jw.field("ignoredBudgetFailures", out.IgnoredBudgetFailures)
+	}
+	if len(out.CostGroups) > 0 {
+		jw.field("costGroups", out.CostGroups)
+	}
+	jw.endObject()
+}
+
+func (jw *jsonWriter) writeProject(p Project) {
+	jw.beginObject()
+	jw.field("name", p.Name)
+	jw.field("metadata", p.Metadata)
+	jw.key("pastBreakdown")
+	jw.writeBreakdown(p.PastBreakdown)
+	jw.key("breakdown")
+	jw.writeBreakdown(p.Breakdown)
+	jw.key("diff")
+	jw.writeBreakdown(p.Diff)
+	jw.field("summary", p.Summary)
+	if len(p.SharedCosts) > 0 {
+		jw.field("sharedCosts", p.SharedCosts)
+	}
+	if len(p.ScopeCosts) > 0 {
+		jw.field("scopeCosts", p.ScopeCosts)
+	}
+	if len(p.OwnershipChanges) > 0 {
+		jw.field("ownershipChanges", p.OwnershipChanges)
+	}
+	jw.endObject()
+}
+
+func (jw *jsonWriter) writeBreakdown(b *Breakdown) {
+	if b == nil {
+		jw.value(nil)
+		return
+	}
+
+	jw.beginObject()
+	jw.key("resources")
+	if b.Resources == nil {
+		jw.value(nil)
+	} else {
+		jw.beginArray()
+		for _, r := range b.Resources {
+			jw.element()
+			jw.value(r)
+		}
+		jw.endArray()
+	}
+	jw.field("totalHourlyCost", b.TotalHourlyCost)
+	jw.field("totalMonthlyCost", b.TotalMonthlyCost)
+	if b.TotalLowMonthlyCost != nil {
+		jw.field("totalLowMonthlyCost", b.TotalLowMonthlyCost)
+	}
+	if b.TotalHighMonthlyCost != nil {
+		jw.field("totalHighMonthlyCost", b.TotalHighMonthlyCost)
+	}
+	if len(b.Projections) > 0 {
+		jw.field("projections", b.Projections)
+	}
+	jw.endObject()
+}
+
+func (jw *jsonWriter) beginObject() {
+	jw.writeByte('{')
+	jw.comma = false
+}
+
+func (jw *jsonWriter) endObject() {
+	jw.writeByte('}')
+	jw.comma = true
+}
+
+func (jw *jsonWriter) beginArray() {
+	jw.writeByte('[')
+	jw.comma = false
+}
+
+func (jw *jsonWriter) endArray() {
+	jw.writeByte(']')
+	jw.comma = true
+}
+
+// element writes the separator before an array element.
+func (jw *jsonWriter) element() {
+	if jw.comma {
+		jw.writeByte(',')
+	}
+}
+
+func (jw *jsonWriter) key(name string) {
+	jw.element()
+	jw.value(name)
+	jw.writeByte(':')
+}
+
+func (jw *jsonWriter) field(name string, v interface{}) {
+	jw.key(name)
+	jw.value(v)
+}
+
+func (jw *jsonWriter) value(v interface{}) {
+	if jw.err == nil {
+		jw.err = jw.enc.Encode(v)
+	}
+	jw.comma = true
+}
+
+func (jw *jsonWriter) writeByte(c byte) {
+	if jw.err == nil {
+		jw.err = jw.w.WriteByte(c)
+	}
+}
+
+func (jw *jsonWriter) flush() error {
+	if jw.err != nil {
+		return jw.err
+	}
+
+	return jw.w.Flush()
+}
+
+// trimNewlineWriter drops the newline that json.Encoder writes after each
+// value, so the values can be written inside objects and arrays without it.
+type trimNewlineWriter struct {
+	w io.Writer
+}
+
+func (t trimNewlineWriter) Write(p []byte) (int, error) {
+	_, err := t.w.Write(bytes.TrimSuffix(p, []byte("\n")))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
 }
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestWriteJSON(t *testing.T) {
+	root := spreadsheetTestRoot()
+	root.Version = "0.2"
+	root.TimeGenerated = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	root.TotalMonthlyCost = decimalPtr(decimal.RequireFromString("31.168"))
+	root.Summary = &Summary{}
+	root.Projects[0].Metadata = &schema.ProjectMetadata{Path: "examples/<web> & api"}
+	root.Projects[0].Diff = &Breakdown{Resources: []Resource{}}
+	root.Projects = append(root.Projects, Project{Name: "empty"})
+	root.IsCIRun = true
+
+	expected, err := json.Marshal(root)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, root, Options{}))
+	assert.Equal(t, string(expected), buf.String())
+
+	b, err := ToJSON(root, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(b))
+}
+
+func TestWriteJSONWithOptions(t *testing.T) {
+	project := func(name string, consumes []string, resources ...*schema.Resource) *schema.Project {
+		return &schema.Project{
+			Name:          name,
+			Metadata:      &schema.ProjectMetadata{Path: "infra/" + name, ConsumesProjects: consumes},
+			PastResources: []*schema.Resource{{Name: "aws_instance." + name, ResourceType: "aws_instance", Tags: map[string]string{"team": "platform"}, MonthlyCost: decimalPtr(decimal.NewFromInt(40))}},
+			Resources:     resources,
+			HasDiff:       true,
+		}
+	}
+	resource := func(name string, cost int64, team string) *schema.Resource {
+		return &schema.Resource{
+			Name:         name,
+			ResourceType: "aws_instance",
+			Tags:         map[string]string{"team": team},
+			MonthlyCost:  decimalPtr(decimal.NewFromInt(cost)),
+			CostComponents: []*schema.CostComponent{
+				{Name: "Instance usage <t3.medium> & more", Unit: "hours", MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)), MonthlyCost: decimalPtr(decimal.NewFromInt(cost))},
+			},
+		}
+	}
+
+	root, err := ToOutputFormat([]*schema.Project{
+		project("network", nil, resource("aws_instance.network", 100, "platform")),
+		project("app", []string{"network"}, resource("aws_instance.app", 50, "payments"), resource("aws_instance.worker", 20, "payments")),
+		project("empty", nil),
+	})
+	require.NoError(t, err)
+	root.Currency = "USD"
+	root.TimeGenerated = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	root.Projects = append(root.Projects, Project{Name: "nil breakdowns"})
+
+	opts := Options{
+		GroupBy:                 "tag:team",
+		ShowSharedCosts:         true,
+		OwnershipTag:            "team",
+		OwnershipDriftThreshold: decimal.NewFromInt(10),
+	}
+
+	g, err := parseGroupBy(opts.GroupBy)
+	require.NoError(t, err)
+
+	want := root
+	want.CostGroups = costGroups(want.Projects, g)
+	want.Projects = withSharedCosts(want.Projects)
+	want.Projects = withOwnershipChanges(want.Projects, opts.OwnershipTag, opts.OwnershipDriftThreshold)
+	require.NotEmpty(t, want.CostGroups)
+	require.NotEmpty(t, want.Projects[1].SharedCosts)
+	require.NotEmpty(t, want.Projects[1].OwnershipChanges)
+
+	expected, err := json.Marshal(want)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, root, opts))
+	assert.Equal(t, string(expected), buf.String())
+
+	expected, err = json.Marshal(Root{})
+	require.NoError(t, err)
+
+	buf.Reset()
+	require.NoError(t, WriteJSON(&buf, Root{}, Options{}))
+	assert.Equal(t, string(expected), buf.String())
+}
@@ -0,0 +1,164 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (s *junitTestSuite) add(tc junitTestCase) {
+	s.TestCases = append(s.TestCases, tc)
+	s.Tests++
+	if tc.Failure != nil {
+		s.Failures++
+	}
+}
+
+func (s *junitTestSuites) add(suite junitTestSuite) {
+	s.Suites = append(s.Suites, suite)
+	s.Tests += suite.Tests
+	s.Failures += suite.Failures
+}
+
+// ToJUnit returns a JUnit XML report so CI systems can show cost regressions
+// in their test report UI. Each project is a test suite with a test case for
+// each resource, which fails if its monthly cost increased or its price
+// couldn't be resolved. Cost policies and guardrails are added as suites of
+// their own if they were checked.
+func ToJUnit(out Root, opts Options) ([]byte, error) {
+	report := junitTestSuites{Name: "Infracost"}
+
+	pricingFailures := make(map[string][]string)
+	for _, f := range out.PricingFailures {
+		key := f.ProjectName + "\x00" + f.ResourceName
+		pricingFailures[key] = append(pricingFailures[key], fmt.Sprintf("%s: %s", f.CostComponent, f.Reason))
+	}
+
+	for _, p := range out.Projects {
+		suite := junitTestSuite{Name: p.Label()}
+
+		for _, r := range junitResources(p) {
+			tc := junitTestCase{
+				Name:      r.Name,
+				ClassName: p.Label(),
+				SystemOut: fmt.Sprintf("Monthly cost: %s", FormatCost2DP(out.Currency, r.MonthlyCost)),
+			}
+
+			var reasons []string
+			if d := junitResourceDiff(p, r.Name); d != nil && d.MonthlyCost != nil && d.MonthlyCost.IsPositive() {
+				reasons = append(reasons, fmt.Sprintf("Monthly cost increased by %s", formatCostChange2DP(out.Currency, d.MonthlyCost)))
+			}
+
+			if fs, ok := pricingFailures[p.Name+"\x00"+r.Name]; ok {
+				reasons = append(reasons, fs...)
+			}
+
+			if len(reasons) > 0 {
+				tc.Failure = &junitFailure{
+					Message: reasons[0],
+					Type:    "cost",
+					Text:    strings.Join(reasons, "\n"),
+				}
+			}
+
+			suite.add(tc)
+		}
+
+		report.add(suite)
+	}
+
+	if opts.PolicyChecks.Enabled {
+		suite := junitTestSuite{Name: "Cost policies"}
+		for _, msg := range opts.PolicyChecks.Passed {
+			suite.add(junitTestCase{Name: msg, ClassName: "policy"})
+		}
+		for _, msg := range opts.PolicyChecks.Failures {
+			suite.add(junitTestCase{Name: msg, ClassName: "policy", Failure: &junitFailure{Message: msg, Type: "policy"}})
+		}
+
+		report.add(suite)
+	}
+
+	if opts.GuardrailCheck.TotalChecked > 0 {
+		suite := junitTestSuite{Name: "Guardrails"}
+		for _, e := range opts.GuardrailCheck.GuardrailEvents {
+			suite.add(junitTestCase{Name: e.TriggerReason, ClassName: "guardrail", Failure: &junitFailure{Message: e.TriggerReason, Type: "guardrail"}})
+		}
+
+		report.add(suite)
+	}
+
+	b, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
+
+// junitResources returns the resources of the planned state of the project,
+// plus the resources that are removed by the diff so their decrease in cost is
+// still reported.
+func junitResources(p Project) []Resource {
+	var resources []Resource
+	seen := make(map[string]bool)
+
+	if p.Breakdown != nil {
+		for _, r := range p.Breakdown.Resources {
+			resources = append(resources, r)
+			seen[r.Name] = true
+		}
+	}
+
+	if p.PastBreakdown != nil {
+		for _, r := range p.PastBreakdown.Resources {
+			if !seen[r.Name] {
+				resources = append(resources, Resource{Name: r.Name})
+			}
+		}
+	}
+
+	return resources
+}
+
+func junitResourceDiff(p Project, name string) *Resource {
+	if p.Diff == nil {
+		return nil
+	}
+
+	for i := range p.Diff.Resources {
+		if p.Diff.Resources[i].Name == name {
+			return &p.Diff.Resources[i]
+		}
+	}
+
+	return nil
+}
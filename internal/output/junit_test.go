@@ -0,0 +1,81 @@
+package output
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToJUnit(t *testing.T) {
+	r := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name: "infracost/example",
+				PastBreakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(30))},
+						{Name: "aws_instance.old", MonthlyCost: decimalPtr(decimal.NewFromInt(10))},
+					},
+				},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(60))},
+						{Name: "aws_lambda_function.hello", MonthlyCost: decimalPtr(decimal.Zero)},
+					},
+				},
+				Diff: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(30))},
+						{Name: "aws_instance.old", MonthlyCost: decimalPtr(decimal.NewFromInt(-10))},
+					},
+				},
+			},
+		},
+		PricingFailures: []PricingFailure{
+			{ProjectName: "infracost/example", ResourceName: "aws_lambda_function.hello", CostComponent: "Requests", Reason: "no price found"},
+		},
+	}
+
+	opts := Options{
+		PolicyChecks: PolicyCheck{
+			Enabled:  true,
+			Passed:   []string{"Instances must be t3"},
+			Failures: PolicyCheckFailures{"Total monthly cost must be less than $50"},
+		},
+	}
+
+	b, err := ToJUnit(r, opts)
+	require.NoError(t, err)
+
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal(b, &report))
+
+	assert.Equal(t, 5, report.Tests)
+	assert.Equal(t, 3, report.Failures)
+	require.Len(t, report.Suites, 2)
+
+	project := report.Suites[0]
+	assert.Equal(t, "infracost/example", project.Name)
+	require.Len(t, project.TestCases, 3)
+
+	assert.Equal(t, "aws_instance.web", project.TestCases[0].Name)
+	require.NotNil(t, project.TestCases[0].Failure)
+	assert.Equal(t, "Monthly cost increased by +$30.00", project.TestCases[0].Failure.Message)
+	assert.Equal(t, "Monthly cost: $60.00", project.TestCases[0].SystemOut)
+
+	assert.Equal(t, "aws_lambda_function.hello", project.TestCases[1].Name)
+	require.NotNil(t, project.TestCases[1].Failure)
+	assert.Equal(t, "Requests: no price found", project.TestCases[1].Failure.Message)
+
+	assert.Equal(t, "aws_instance.old", project.TestCases[2].Name)
+	assert.Nil(t, project.TestCases[2].Failure)
+
+	policies := report.Suites[1]
+	assert.Equal(t, "Cost policies", policies.Name)
+	assert.Equal(t, 2, policies.Tests)
+	assert.Equal(t, 1, policies.Failures)
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 	"text/template"
@@ -105,6 +106,7 @@ type MarkdownCtx struct {
 	SkippedProjectCount          int
 	ErroredProjectCount          int
 	SkippedUnchangedProjectCount int
+	SuppressedProjectCount       int
 	DiffOutput                   string
 	Options                      Options
 	MarkdownOptions              MarkdownOptions
@@ -123,6 +125,12 @@ func (m MarkdownCtx) ProjectCounts() string {
 		out += fmt.Sprintf("%d projects have no cost estimate changes, ", m.SkippedProjectCount)
 	}
 
+	if m.SuppressedProjectCount == 1 {
+		out += "1 project has cost changes below the minimum, "
+	} else if m.SuppressedProjectCount > 0 {
+		out += fmt.Sprintf("%d projects have cost changes below the minimum, ", m.SuppressedProjectCount)
+	}
+
 	if m.ErroredProjectCount == 1 {
 		out += "1 project could not be evaluated"
 	} else if m.ErroredProjectCount > 0 {
@@ -142,12 +150,23 @@ func ToMarkdown(out Root, opts Options, markdownOpts MarkdownOptions) ([]byte, e
 	if opts.diffMsg != "" {
 		diffMsg = opts.diffMsg
 	} else {
-		diff, err := ToDiff(out, opts)
+		diffOut, suppressedCount := suppressSmallChanges(out, opts.CommentThresholds)
+
+		// The policy checks have their own sections in the comment templates.
+		diffOpts := opts
+		diffOpts.PolicyChecks = PolicyCheck{}
+		diff, err := ToDiff(diffOut, diffOpts)
 		if err != nil {
 			return nil, errors.Wrap(err, "Failed to generate diff")
 		}
 
 		diffMsg = ui.StripColor(string(diff))
+
+		if suppressedCount == 1 {
+			diffMsg += "\n──────────────────────────────────\n1 resource has a cost change below the minimum and isn't shown"
+		} else if suppressedCount > 0 {
+			diffMsg += fmt.Sprintf("\n──────────────────────────────────\n%d resources have cost changes below the minimum and aren't shown", suppressedCount)
+		}
 	}
 
 	hasModulePath, hasWorkspace := calculateMetadataToDisplay(out.Projects)
@@ -157,6 +176,7 @@ func ToMarkdown(out Root, opts Options, markdownOpts MarkdownOptions) ([]byte, e
 
 	tmpl := template.New("base")
 	tmpl.Funcs(sprig.TxtFuncMap())
+	tmpl.Funcs(templateFuncs(out.Currency))
 	tmpl.Funcs(template.FuncMap{
 		"formatCost": func(d *decimal.Decimal) string {
 			if d == nil || d.IsZero() {
@@ -189,7 +209,12 @@ func ToMarkdown(out Root, opts Options, markdownOpts MarkdownOptions) ([]byte, e
 				return false
 			}
 
-			return true // has diff
+			return !isSuppressedProject(opts, p) // has diff
+		},
+		"costChangeSeverity": opts.CommentThresholds.severity,
+		"severityIcon":       severityIcon,
+		"largeCostIncreases": func() []LargeCostIncrease {
+			return largeCostIncreases(out, opts.CommentThresholds)
 		},
 		"validProjects": func() Projects {
 			var valid Projects
@@ -238,8 +263,22 @@ func ToMarkdown(out Root, opts Options, markdownOpts MarkdownOptions) ([]byte, e
 	if markdownOpts.BasicSyntax {
 		t = CommentMarkdownTemplate
 	}
+
+	// Teams can replace the comment with their own template, which has the
+	// same data and functions as the default one.
+	if opts.TemplatePath != "" {
+		content, err := os.ReadFile(opts.TemplatePath)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to read template file")
+		}
+		t = string(content)
+	}
+
 	tmpl, err := tmpl.Parse(t)
 	if err != nil {
+		if opts.TemplatePath != "" {
+			return nil, fmt.Errorf("Failed to parse template %s: %w", opts.TemplatePath, err)
+		}
 		return []byte{}, err
 	}
 
@@ -254,6 +293,15 @@ func ToMarkdown(out Root, opts Options, markdownOpts MarkdownOptions) ([]byte, e
 		}
 	}
 
+	suppressedProjectCount := 0
+	if !opts.ShowAllProjects {
+		for _, p := range out.Projects {
+			if !p.Metadata.HasErrors() && p.Diff != nil && len(p.Diff.Resources) > 0 && !hasCodeChanges(opts, p) && isSuppressedProject(opts, p) {
+				suppressedProjectCount++
+			}
+		}
+	}
+
 	erroredProjectCount := 0
 	for _, p := range out.Projects {
 		if p.Metadata.HasErrors() {
@@ -275,6 +323,7 @@ func ToMarkdown(out Root, opts Options, markdownOpts MarkdownOptions) ([]byte, e
 		SkippedProjectCount:          skippedProjectCount,
 		ErroredProjectCount:          erroredProjectCount,
 		SkippedUnchangedProjectCount: skippedUnchangedProjectCount,
+		SuppressedProjectCount:       suppressedProjectCount,
 		DiffOutput:                   diffMsg,
 		Options:                      opts,
 		MarkdownOptions:              markdownOpts})
@@ -300,6 +349,20 @@ func ToMarkdown(out Root, opts Options, markdownOpts MarkdownOptions) ([]byte, e
 	return msg, nil
 }
 
+// isSuppressedProject returns true if the cost change of the project is below
+// the comment thresholds.
+func isSuppressedProject(options Options, project Project) bool {
+	var pastCost, cost *decimal.Decimal
+	if project.PastBreakdown != nil {
+		pastCost = project.PastBreakdown.TotalMonthlyCost
+	}
+	if project.Breakdown != nil {
+		cost = project.Breakdown.TotalMonthlyCost
+	}
+
+	return options.CommentThresholds.isBelowMinimum(pastCost, cost)
+}
+
 func hasCodeChanges(options Options, project Project) bool {
 	return options.ShowOnlyChanges && project.Metadata.VCSCodeChanged != nil && *project.Metadata.VCSCodeChanged
 }
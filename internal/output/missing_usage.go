@@ -0,0 +1,99 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// MissingUsage is a resource with cost components that depend on usage that
+// wasn't set, so their cost is not included in the totals.
+type MissingUsage struct {
+	ProjectName    string   `json:"projectName"`
+	ResourceName   string   `json:"resourceName"`
+	CostComponents []string `json:"costComponents"`
+	// UsageKeys are the keys of the usage file that the resource has no value
+	// for.
+	UsageKeys []string `json:"usageKeys"`
+}
+
+// SetMissingUsage adds the resources of projects that have cost components
+// without a quantity to r, along with the usage keys to set to estimate them.
+// Without this the totals of these resources show as 0.00, which is easily
+// misread as the resources being free.
+func SetMissingUsage(r *Root, projects []*schema.Project) {
+	var missing []MissingUsage
+
+	for _, p := range projects {
+		for _, res := range p.Resources {
+			if res.IsSkipped {
+				continue
+			}
+
+			components := unestimatedCostComponents("", res)
+			if len(components) == 0 {
+				continue
+			}
+
+			keys := append([]string{}, res.UnsetUsageKeys...)
+			for _, sub := range res.SubResources {
+				keys = append(keys, sub.UnsetUsageKeys...)
+			}
+
+			missing = append(missing, MissingUsage{
+				ProjectName:    p.Name,
+				ResourceName:   res.Name,
+				CostComponents: components,
+				UsageKeys:      keys,
+			})
+		}
+	}
+
+	r.MissingUsage = missing
+}
+
+// unestimatedCostComponents returns the names of the cost components of res and
+// its sub resources that have neither an hourly nor a monthly quantity. Cost
+// components of sub resources are prefixed by the path of the sub resource.
+func unestimatedCostComponents(prefix string, res *schema.Resource) []string {
+	var names []string
+
+	for _, c := range res.CostComponents {
+		if c.HourlyQuantity == nil && c.MonthlyQuantity == nil {
+			names = append(names, prefix+c.Name)
+		}
+	}
+
+	for _, sub := range res.SubResources {
+		names = append(names, unestimatedCostComponents(prefix+sub.Name+": ", sub)...)
+	}
+
+	return names
+}
+
+// missingUsageMessage returns the resources with costs that couldn't be
+// estimated and the usage keys to set for each of them.
+func (r *Root) missingUsageMessage() string {
+	if len(r.MissingUsage) == 0 {
+		return ""
+	}
+
+	msg := fmt.Sprintf("Usage costs not estimated, set these keys in the usage file to include them, see %s:", ui.SecondaryLinkString("https://infracost.io/usage-file"))
+	for _, m := range r.MissingUsage {
+		noun := "cost components"
+		if len(m.CostComponents) == 1 {
+			noun = "cost component"
+		}
+
+		keys := "no usage keys are unset"
+		if len(m.UsageKeys) > 0 {
+			keys = strings.Join(m.UsageKeys, ", ")
+		}
+
+		msg += fmt.Sprintf("\n∙ %s (%d %s): %s", m.ResourceName, len(m.CostComponents), noun, keys)
+	}
+
+	return msg
+}
@@ -0,0 +1,51 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestSetMissingUsage(t *testing.T) {
+	resources := []*schema.Resource{
+		{Name: "aws_instance.web", CostComponents: []*schema.CostComponent{pricedCostComponent("a")}},
+		{
+			Name:           "aws_lambda_function.hello",
+			CostComponents: []*schema.CostComponent{{Name: "Requests"}, {Name: "Duration"}},
+			UnsetUsageKeys: []string{"monthly_requests", "request_duration_ms"},
+		},
+		{
+			Name:           "aws_instance.app",
+			CostComponents: []*schema.CostComponent{pricedCostComponent("b")},
+			SubResources: []*schema.Resource{
+				{Name: "root_block_device", CostComponents: []*schema.CostComponent{{Name: "Provisioned IOPS"}}},
+			},
+		},
+		{Name: "aws_sqs_queue.skipped", IsSkipped: true, CostComponents: []*schema.CostComponent{{Name: "Requests"}}},
+	}
+
+	projects := []*schema.Project{{Name: "test", Metadata: &schema.ProjectMetadata{}, Resources: resources}}
+
+	root, err := ToOutputFormat(projects)
+	require.NoError(t, err)
+	root.Currency = "USD"
+
+	SetMissingUsage(&root, projects)
+	assert.Equal(t, []MissingUsage{
+		{ProjectName: "test", ResourceName: "aws_lambda_function.hello", CostComponents: []string{"Requests", "Duration"}, UsageKeys: []string{"monthly_requests", "request_duration_ms"}},
+		{ProjectName: "test", ResourceName: "aws_instance.app", CostComponents: []string{"root_block_device: Provisioned IOPS"}, UsageKeys: []string{}},
+	}, root.MissingUsage)
+
+	b, err := ToTable(root, Options{NoColor: true, Fields: []string{"monthlyQuantity", "unit", "monthlyCost"}})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "Usage costs not estimated, set these keys in the usage file to include them, see https://infracost.io/usage-file:\n"+
+		"∙ aws_lambda_function.hello (2 cost components): monthly_requests, request_duration_ms\n"+
+		"∙ aws_instance.app (1 cost component): no usage keys are unset")
+
+	b, err = ToJSON(root, Options{})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"missingUsage":[{"projectName":"test","resourceName":"aws_lambda_function.hello","costComponents":["Requests","Duration"],"usageKeys":["monthly_requests","request_duration_ms"]}`)
+}
@@ -21,22 +21,45 @@ import (
 var outputVersion = "0.2"
 
 type Root struct {
-	Version              string           `json:"version"`
-	Metadata             Metadata         `json:"metadata"`
-	RunID                string           `json:"runId,omitempty"`
-	ShareURL             string           `json:"shareUrl,omitempty"`
-	Currency             string           `json:"currency"`
-	Projects             Projects         `json:"projects"`
-	TotalHourlyCost      *decimal.Decimal `json:"totalHourlyCost"`
-	TotalMonthlyCost     *decimal.Decimal `json:"totalMonthlyCost"`
-	PastTotalHourlyCost  *decimal.Decimal `json:"pastTotalHourlyCost"`
-	PastTotalMonthlyCost *decimal.Decimal `json:"pastTotalMonthlyCost"`
-	DiffTotalHourlyCost  *decimal.Decimal `json:"diffTotalHourlyCost"`
-	DiffTotalMonthlyCost *decimal.Decimal `json:"diffTotalMonthlyCost"`
-	TimeGenerated        time.Time        `json:"timeGenerated"`
-	Summary              *Summary         `json:"summary"`
-	FullSummary          *Summary         `json:"-"`
-	IsCIRun              bool             `json:"-"`
+	Version              string                `json:"version"`
+	Metadata             Metadata              `json:"metadata"`
+	RunID                string                `json:"runId,omitempty"`
+	ShareURL             string                `json:"shareUrl,omitempty"`
+	Currency             string                `json:"currency"`
+	Projects             Projects              `json:"projects"`
+	TotalHourlyCost      *decimal.Decimal      `json:"totalHourlyCost"`
+	TotalMonthlyCost     *decimal.Decimal      `json:"totalMonthlyCost"`
+	PastTotalHourlyCost  *decimal.Decimal      `json:"pastTotalHourlyCost"`
+	PastTotalMonthlyCost *decimal.Decimal      `json:"pastTotalMonthlyCost"`
+	DiffTotalHourlyCost  *decimal.Decimal      `json:"diffTotalHourlyCost"`
+	DiffTotalMonthlyCost *decimal.Decimal      `json:"diffTotalMonthlyCost"`
+	TimeGenerated        time.Time             `json:"timeGenerated"`
+	Summary              *Summary              `json:"summary"`
+	ResourceSummary      *Summary              `json:"resourceSummary,omitempty"`
+	PricingCoverage      *PricingCoverage      `json:"pricingCoverage,omitempty"`
+	PricingFailures      []PricingFailure      `json:"pricingFailures,omitempty"`
+	MissingUsage         []MissingUsage        `json:"missingUsage,omitempty"`
+	UnknownInputs        []UnknownInput        `json:"unknownInputs,omitempty"`
+	UnsupportedResources []UnsupportedResource `json:"unsupportedResources,omitempty"`
+	TagPolicyViolations  []TagPolicyViolation  `json:"tagPolicyViolations,omitempty"`
+	BudgetFailures       []BudgetFailure       `json:"budgetFailures,omitempty"`
+	CostAnomalies        []CostAnomaly         `json:"costAnomalies,omitempty"`
+	Emissions            *Emissions            `json:"emissions,omitempty"`
+	// TotalLowMonthlyCost and TotalHighMonthlyCost are the bounds of the
+	// total monthly cost if some of the usage is given as a range.
+	TotalLowMonthlyCost  *decimal.Decimal `json:"totalLowMonthlyCost,omitempty"`
+	TotalHighMonthlyCost *decimal.Decimal `json:"totalHighMonthlyCost,omitempty"`
+	// Projections are the cumulative costs over the next months if some of
+	// the projects have a growth set in the config file.
+	Projections []Projection `json:"projections,omitempty"`
+	// IgnoredBudgetFailures are the budget failures that are accepted in the
+	// ignores file, so they don't fail the run.
+	IgnoredBudgetFailures []IgnoredFailure `json:"ignoredBudgetFailures,omitempty"`
+	// CostGroups is set in the JSON output format if resources are grouped
+	// with --group-by.
+	CostGroups  []CostGroup `json:"costGroups,omitempty"`
+	FullSummary *Summary    `json:"-"`
+	IsCIRun     bool        `json:"-"`
 }
 
 type Project struct {
@@ -46,7 +69,14 @@ type Project struct {
 	Breakdown     *Breakdown              `json:"breakdown"`
 	Diff          *Breakdown              `json:"diff"`
 	Summary       *Summary                `json:"summary"`
-	fullSummary   *Summary
+	SharedCosts   []SharedCost            `json:"sharedCosts,omitempty"`
+	// ScopeCosts is set in the JSON output format if resources are grouped by
+	// their cost scope.
+	ScopeCosts []ScopeCost `json:"scopeCosts,omitempty"`
+	// OwnershipChanges is set in the diff and JSON output formats if an
+	// ownership tag is given.
+	OwnershipChanges []OwnershipChange `json:"ownershipChanges,omitempty"`
+	fullSummary      *Summary
 }
 
 // ToSchemaProject generates a schema.Project from a Project. The created schema.Project is not suitable to be
@@ -75,20 +105,30 @@ func convertOutputResources(outResources []Resource) []*schema.Resource {
 	resources := make([]*schema.Resource, len(outResources))
 
 	for i, resource := range outResources {
-		resources[i] = &schema.Resource{
-			Name:           resource.Name,
-			CostComponents: convertCostComponents(resource.CostComponents),
-			ActualCosts:    convertActualCosts(resource.ActualCosts),
-			SubResources:   convertOutputResources(resource.SubResources),
-			HourlyCost:     resource.HourlyCost,
-			MonthlyCost:    resource.MonthlyCost,
-			ResourceType:   resource.ResourceType(),
-		}
+		resources[i] = convertOutputResource(resource)
+		resources[i].ResourceType = resource.ResourceType()
 	}
 
 	return resources
 }
 
+func convertOutputResource(resource Resource) *schema.Resource {
+	subResources := make([]*schema.Resource, len(resource.SubResources))
+	for i, s := range resource.SubResources {
+		subResources[i] = convertOutputResource(s)
+	}
+
+	return &schema.Resource{
+		Name:           resource.Name,
+		CostComponents: convertCostComponents(resource.CostComponents),
+		ActualCosts:    convertActualCosts(resource.ActualCosts),
+		SubResources:   subResources,
+		HourlyCost:     resource.HourlyCost,
+		MonthlyCost:    resource.MonthlyCost,
+		ResourceType:   resource.Type,
+	}
+}
+
 func convertCostComponents(outComponents []CostComponent) []*schema.CostComponent {
 	components := make([]*schema.CostComponent, len(outComponents))
 
@@ -101,8 +141,10 @@ func convertCostComponents(outComponents []CostComponent) []*schema.CostComponen
 			MonthlyCost:     c.MonthlyCost,
 			HourlyQuantity:  c.HourlyQuantity,
 			MonthlyQuantity: c.MonthlyQuantity,
+			UsageBased:      c.UsageBased,
 		}
 		sc.SetPrice(c.Price)
+		sc.SetSharedWith(c.SharedWith)
 
 		components[i] = sc
 	}
@@ -183,6 +225,13 @@ type Breakdown struct {
 	Resources        []Resource       `json:"resources"`
 	TotalHourlyCost  *decimal.Decimal `json:"totalHourlyCost"`
 	TotalMonthlyCost *decimal.Decimal `json:"totalMonthlyCost"`
+	// TotalLowMonthlyCost and TotalHighMonthlyCost are the bounds of the
+	// total monthly cost if some of the usage is given as a range.
+	TotalLowMonthlyCost  *decimal.Decimal `json:"totalLowMonthlyCost,omitempty"`
+	TotalHighMonthlyCost *decimal.Decimal `json:"totalHighMonthlyCost,omitempty"`
+	// Projections are the cumulative costs over the next months if the
+	// project has a growth set in the config file.
+	Projections []Projection `json:"projections,omitempty"`
 }
 
 type CostComponent struct {
@@ -193,6 +242,20 @@ type CostComponent struct {
 	Price           decimal.Decimal  `json:"price"`
 	HourlyCost      *decimal.Decimal `json:"hourlyCost"`
 	MonthlyCost     *decimal.Decimal `json:"monthlyCost"`
+	SharedWith      string           `json:"sharedWith,omitempty"`
+	// UsageBased is true if the quantity of the cost component depends on
+	// usage, whether or not the usage is set.
+	UsageBased bool `json:"usageBased,omitempty"`
+	// MonthlyKgCO2e is the estimated carbon footprint, set if emissions are
+	// estimated and the cost component is compute or storage.
+	MonthlyKgCO2e *decimal.Decimal `json:"monthlyKgCo2e,omitempty"`
+	// LowMonthlyQuantity and HighMonthlyQuantity are the bounds of the monthly
+	// quantity, and LowMonthlyCost and HighMonthlyCost of the monthly cost, if
+	// the usage they're based on is given as a range.
+	LowMonthlyQuantity  *decimal.Decimal `json:"lowMonthlyQuantity,omitempty"`
+	HighMonthlyQuantity *decimal.Decimal `json:"highMonthlyQuantity,omitempty"`
+	LowMonthlyCost      *decimal.Decimal `json:"lowMonthlyCost,omitempty"`
+	HighMonthlyCost     *decimal.Decimal `json:"highMonthlyCost,omitempty"`
 }
 
 type ActualCosts struct {
@@ -203,7 +266,11 @@ type ActualCosts struct {
 }
 
 type Resource struct {
-	Name           string                 `json:"name"`
+	Name string `json:"name"`
+	// Type is the resource type of a sub resource, e.g. aws_ebs_volume for an EC2
+	// block device. It is not set for top level resources as their type is part of
+	// the name.
+	Type           string                 `json:"resourceType,omitempty"`
 	Tags           map[string]string      `json:"tags,omitempty"`
 	Metadata       map[string]interface{} `json:"metadata"`
 	HourlyCost     *decimal.Decimal       `json:"hourlyCost"`
@@ -211,10 +278,34 @@ type Resource struct {
 	CostComponents []CostComponent        `json:"costComponents,omitempty"`
 	ActualCosts    []ActualCosts          `json:"actualCosts,omitempty"`
 	SubResources   []Resource             `json:"subresources,omitempty"`
+	// MonthlyKgCO2e is the estimated carbon footprint of the compute and
+	// storage cost components, set if emissions are estimated.
+	MonthlyKgCO2e *decimal.Decimal `json:"monthlyKgCo2e,omitempty"`
+	// Recommendations are cheaper alternatives to the resource, e.g. smaller
+	// instance types, based on its usage.
+	Recommendations []Recommendation `json:"recommendations,omitempty"`
+	// LowMonthlyCost and HighMonthlyCost are the bounds of the monthly cost if
+	// some of its usage is given as a range.
+	LowMonthlyCost  *decimal.Decimal `json:"lowMonthlyCost,omitempty"`
+	HighMonthlyCost *decimal.Decimal `json:"highMonthlyCost,omitempty"`
+	// Projections are the cumulative costs over the next months if the
+	// project of the resource has a growth set in the config file.
+	Projections []Projection `json:"projections,omitempty"`
 }
 
 func (r Resource) ResourceType() string {
-	pieces := strings.Split(r.Name, ".")
+	if r.Type != "" {
+		return r.Type
+	}
+
+	// The index of resources with count or for_each can contain dots, e.g.
+	// aws_instance.web["a.b"], so it's removed before the name is split.
+	name := r.Name
+	if i := strings.Index(name, "["); i != -1 && strings.HasSuffix(name, "]") {
+		name = name[:i]
+	}
+
+	pieces := strings.Split(name, ".")
 
 	if len(pieces) >= 2 {
 		return pieces[len(pieces)-2]
@@ -223,6 +314,23 @@ func (r Resource) ResourceType() string {
 	return r.Name
 }
 
+// subResourceLabel returns the label of the sub resource r of a resource of
+// parentType, which has the type of r if it's a different type that isn't
+// already part of its name, e.g. root_block_device (aws_ebs_volume), and the
+// type of r that its own sub resources are labelled with.
+func subResourceLabel(r Resource, parentType string) (string, string) {
+	resourceType := r.Type
+	if resourceType == "" {
+		resourceType = parentType
+	}
+
+	if resourceType == parentType || (Resource{Name: r.Name}).ResourceType() == resourceType {
+		return r.Name, resourceType
+	}
+
+	return r.Name + ui.FaintString(fmt.Sprintf(" (%s)", resourceType)), resourceType
+}
+
 type Summary struct {
 	TotalResources            *int `json:"totalResources,omitempty"`
 	TotalDetectedResources    *int `json:"totalDetectedResources,omitempty"`
@@ -230,10 +338,17 @@ type Summary struct {
 	TotalUnsupportedResources *int `json:"totalUnsupportedResources,omitempty"`
 	TotalUsageBasedResources  *int `json:"totalUsageBasedResources,omitempty"`
 	TotalNoPriceResources     *int `json:"totalNoPriceResources,omitempty"`
+	TotalPaidResources        *int `json:"totalPaidResources,omitempty"`
+	// TotalPricedResources are the supported resources that have a price for
+	// all of their cost components.
+	TotalPricedResources      *int `json:"totalPricedResources,omitempty"`
+	TotalCostComponents       *int `json:"totalCostComponents,omitempty"`
+	TotalPricedCostComponents *int `json:"totalPricedCostComponents,omitempty"`
 
 	SupportedResourceCounts   *map[string]int `json:"supportedResourceCounts,omitempty"`
 	UnsupportedResourceCounts *map[string]int `json:"unsupportedResourceCounts,omitempty"`
 	NoPriceResourceCounts     *map[string]int `json:"noPriceResourceCounts,omitempty"`
+	ProviderResourceCounts    *map[string]int `json:"providerResourceCounts,omitempty"`
 
 	EstimatedUsageCounts   *map[string]int `json:"-"`
 	UnestimatedUsageCounts *map[string]int `json:"-"`
@@ -247,17 +362,51 @@ type SummaryOptions struct {
 }
 
 type Options struct {
-	DashboardEndpoint string
-	NoColor           bool
-	ShowSkipped       bool
-	ShowAllProjects   bool
-	ShowOnlyChanges   bool
+	DashboardEndpoint   string
+	NoColor             bool
+	ShowSkipped         bool
+	ShowAllProjects     bool
+	ShowResourceSummary bool
+	ShowSharedCosts     bool
+	GroupByScope        bool
+	// GroupBy subtotals the costs of the resources across all projects, see
+	// ValidateGroupBy for the supported values.
+	GroupBy         string
+	ShowOnlyChanges bool
+	// RollUpInstances combines the instances of resources that use count or
+	// for_each into one resource, see RollUpInstances.
+	RollUpInstances bool
+	// OwnershipTag is the tag that resources are grouped by to report cost that
+	// moves between owners, e.g. team. Only moves of at least
+	// OwnershipDriftThreshold are reported.
+	OwnershipTag            string
+	OwnershipDriftThreshold decimal.Decimal
+	// CostIncreaseThreshold is the monthly cost increase that resources must
+	// exceed to be reported as warnings in the SARIF output format.
+	CostIncreaseThreshold decimal.Decimal
+	// TopResources is the number of resources with the largest cost changes
+	// that are listed in the slack-message and teams-card output formats.
+	TopResources int
+	// ShowEmissions adds the estimated carbon footprint to the table output
+	// format.
+	ShowEmissions bool
+	// ShowQuickWins lists the quick wins, e.g. gp3 instead of gp2 volumes, in
+	// the table and comment output formats.
+	ShowQuickWins bool
+	// Granularities are the periods of time, e.g. hourly or annual, that the
+	// table output format shows costs for instead of the fields' hourly and
+	// monthly costs.
+	Granularities []string
+	// CommentThresholds hide small cost changes and highlight large cost
+	// increases in the comment output formats.
+	CommentThresholds CostChangeThresholds
 	Fields            []string
 	IncludeHTML       bool
 	PolicyChecks      PolicyCheck
 	GuardrailCheck    GuardrailCheck
 	diffMsg           string
 	CurrencyFormat    string
+	TemplatePath      string
 }
 
 // PolicyCheck holds information if a given run has any policy checks enabled.
@@ -266,6 +415,10 @@ type PolicyCheck struct {
 	Enabled  bool
 	Failures PolicyCheckFailures
 	Passed   []string
+	// Warnings are the messages of policy rules that don't fail the run.
+	Warnings []string
+	// Ignored are the failures that are accepted in the ignores file.
+	Ignored []IgnoredFailure
 }
 
 // HasFailed returns if the PolicyCheck has any cost policy failures
@@ -273,6 +426,40 @@ func (p PolicyCheck) HasFailed() bool {
 	return len(p.Failures) > 0
 }
 
+// message returns the failures and warnings of the policy checks.
+func (p PolicyCheck) message() string {
+	if !p.Enabled {
+		return ""
+	}
+
+	var msgs []string
+	if len(p.Failures) > 0 {
+		msg := "Policy checks failed:"
+		for _, f := range p.Failures {
+			msg += "\n∙ " + f
+		}
+		msgs = append(msgs, msg)
+	}
+
+	if len(p.Warnings) > 0 {
+		msg := "Policy warnings:"
+		for _, w := range p.Warnings {
+			msg += "\n∙ " + w
+		}
+		msgs = append(msgs, msg)
+	}
+
+	if len(p.Ignored) > 0 {
+		msg := "Ignored policy failures:"
+		for _, i := range p.Ignored {
+			msg += fmt.Sprintf("\n∙ %s (%s)", i.Failure, i.Reason)
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return strings.Join(msgs, "\n\n")
+}
+
 // PolicyCheckFailures defines a list of policy check failures that can be collected from a policy evaluation.
 type PolicyCheckFailures []string
 
@@ -408,11 +595,15 @@ func outputBreakdown(resources []*schema.Resource) *Breakdown {
 	sortResources(arr, "")
 
 	totalMonthlyCost, totalHourlyCost := calculateTotalCosts(arr)
+	totalLowMonthlyCost, totalHighMonthlyCost := resourcesCostRange(arr)
 
 	return &Breakdown{
-		Resources:        arr,
-		TotalHourlyCost:  totalMonthlyCost,
-		TotalMonthlyCost: totalHourlyCost,
+		Resources:            arr,
+		TotalHourlyCost:      totalMonthlyCost,
+		TotalMonthlyCost:     totalHourlyCost,
+		TotalLowMonthlyCost:  totalLowMonthlyCost,
+		TotalHighMonthlyCost: totalHighMonthlyCost,
+		Projections:          resourcesProjections(arr),
 	}
 }
 
@@ -423,7 +614,9 @@ func outputResource(r *schema.Resource) Resource {
 
 	subresources := make([]Resource, 0, len(r.SubResources))
 	for _, s := range r.SubResources {
-		subresources = append(subresources, outputResource(s))
+		sub := outputResource(s)
+		sub.Type = s.ResourceType
+		subresources = append(subresources, sub)
 	}
 
 	metadata := make(map[string]interface{})
@@ -434,21 +627,25 @@ func outputResource(r *schema.Resource) Resource {
 	}
 
 	return Resource{
-		Name:           r.Name,
-		Metadata:       metadata,
-		Tags:           r.Tags,
-		HourlyCost:     r.HourlyCost,
-		MonthlyCost:    r.MonthlyCost,
-		CostComponents: comps,
-		ActualCosts:    actualCosts,
-		SubResources:   subresources,
+		Name:            r.Name,
+		Metadata:        metadata,
+		Tags:            r.Tags,
+		HourlyCost:      r.HourlyCost,
+		MonthlyCost:     r.MonthlyCost,
+		CostComponents:  comps,
+		ActualCosts:     actualCosts,
+		SubResources:    subresources,
+		Recommendations: outputRecommendations(r.Recommendations),
+		LowMonthlyCost:  r.LowMonthlyCost,
+		HighMonthlyCost: r.HighMonthlyCost,
+		Projections:     outputProjections(r.ProjectedCosts),
 	}
 }
 
 func outputCostComponents(costComponents []*schema.CostComponent) []CostComponent {
 	comps := make([]CostComponent, 0, len(costComponents))
 	for _, c := range costComponents {
-		comps = append(comps, CostComponent{
+		comp := CostComponent{
 			Name:            c.Name,
 			Unit:            c.Unit,
 			HourlyQuantity:  c.UnitMultiplierHourlyQuantity(),
@@ -456,7 +653,18 @@ func outputCostComponents(costComponents []*schema.CostComponent) []CostComponen
 			Price:           c.UnitMultiplierPrice(),
 			HourlyCost:      c.HourlyCost,
 			MonthlyCost:     c.MonthlyCost,
-		})
+			SharedWith:      c.SharedWith(),
+			UsageBased:      c.UsageBased,
+		}
+
+		if c.HasCostRange() {
+			comp.LowMonthlyQuantity = c.UnitMultiplierQuantity(c.LowMonthlyQuantity)
+			comp.HighMonthlyQuantity = c.UnitMultiplierQuantity(c.HighMonthlyQuantity)
+			comp.LowMonthlyCost = c.LowMonthlyCost
+			comp.HighMonthlyCost = c.HighMonthlyCost
+		}
+
+		comps = append(comps, comp)
 	}
 	return comps
 }
@@ -574,6 +782,8 @@ func ToOutputFormat(projects []*schema.Project) (Root, error) {
 		})
 	}
 
+	totalLowMonthlyCost, totalHighMonthlyCost := projectsCostRange(outProjects)
+
 	out := Root{
 		Version:              outputVersion,
 		Projects:             outProjects,
@@ -583,6 +793,9 @@ func ToOutputFormat(projects []*schema.Project) (Root, error) {
 		PastTotalMonthlyCost: pastTotalMonthlyCost,
 		DiffTotalHourlyCost:  diffTotalHourlyCost,
 		DiffTotalMonthlyCost: diffTotalMonthlyCost,
+		TotalLowMonthlyCost:  totalLowMonthlyCost,
+		TotalHighMonthlyCost: totalHighMonthlyCost,
+		Projections:          projectsProjections(outProjects),
 		TimeGenerated:        time.Now().UTC(),
 		Summary:              MergeSummaries(summaries),
 		FullSummary:          MergeSummaries(fullSummaries),
@@ -675,6 +888,70 @@ func (r *Root) summaryMessage(showSkipped bool) string {
 	return msg
 }
 
+// resourceSummary returns the resource summary of all the detected resources,
+// including those from providers that Infracost doesn't support. The summary
+// from the input JSON is used when the output is generated from a JSON file
+// since the resources aren't available then.
+func (r *Root) resourceSummary() *Summary {
+	if r.ResourceSummary != nil {
+		return r.ResourceSummary
+	}
+
+	if r.FullSummary == nil {
+		return nil
+	}
+
+	return &Summary{
+		TotalDetectedResources:    r.FullSummary.TotalDetectedResources,
+		TotalSupportedResources:   r.FullSummary.TotalSupportedResources,
+		TotalUnsupportedResources: r.FullSummary.TotalUnsupportedResources,
+		TotalNoPriceResources:     r.FullSummary.TotalNoPriceResources,
+		TotalPaidResources:        r.FullSummary.TotalPaidResources,
+		TotalPricedResources:      r.FullSummary.TotalPricedResources,
+		TotalCostComponents:       r.FullSummary.TotalCostComponents,
+		TotalPricedCostComponents: r.FullSummary.TotalPricedCostComponents,
+		SupportedResourceCounts:   r.FullSummary.SupportedResourceCounts,
+		UnsupportedResourceCounts: r.FullSummary.UnsupportedResourceCounts,
+		NoPriceResourceCounts:     r.FullSummary.NoPriceResourceCounts,
+		ProviderResourceCounts:    r.FullSummary.ProviderResourceCounts,
+	}
+}
+
+// resourceSummaryMessage returns the resource summary section of the table
+// output, which gives reviewers an idea of the size of the projects and how
+// much of them the estimate covers.
+func (r *Root) resourceSummaryMessage() string {
+	s := r.resourceSummary()
+	if s == nil || s.TotalDetectedResources == nil || *s.TotalDetectedResources == 0 {
+		return ""
+	}
+
+	supported := intPtrValue(s.TotalSupportedResources) + intPtrValue(s.TotalNoPriceResources)
+	paid := intPtrValue(s.TotalPaidResources)
+	free := intPtrValue(s.TotalNoPriceResources)
+
+	msg := "Resource summary:"
+	msg += fmt.Sprintf("\n∙ %d supported, %d not supported", supported, intPtrValue(s.TotalUnsupportedResources))
+	msg += fmt.Sprintf("\n∙ %d paid, %d free", paid, free)
+
+	if unpriced := intPtrValue(s.TotalSupportedResources) - paid; unpriced > 0 {
+		msg += fmt.Sprintf(", %d with no cost until usage is specified", unpriced)
+	}
+
+	if c := r.pricingCoverage(); c != nil {
+		msg += fmt.Sprintf("\n∙ %s of resources and %s of cost components priced", formatPercent(c.ResourcesPercent), formatPercent(c.CostComponentsPercent))
+	}
+
+	msg += "\n∙ By provider:"
+	msg += formatCounts(s.ProviderResourceCounts)
+
+	typeCounts := mergeCounts(mergeCounts(s.SupportedResourceCounts, s.NoPriceResourceCounts), s.UnsupportedResourceCounts)
+	msg += "\n∙ By resource type:"
+	msg += formatCounts(typeCounts)
+
+	return msg
+}
+
 func formatCounts(countMap *map[string]int) string {
 	msg := ""
 
@@ -710,17 +987,28 @@ func hasSupportedTerraformProvider(rType string) bool {
 	return strings.HasPrefix(rType, "aws_") || strings.HasPrefix(rType, "google_") || strings.HasPrefix(rType, "azurerm_")
 }
 
+// resourceProvider returns the provider of a resource type, which is the
+// prefix before the first underscore, e.g. aws for aws_instance.
+func resourceProvider(rType string) string {
+	return strings.SplitN(rType, "_", 2)[0]
+}
+
 func BuildSummary(resources []*schema.Resource, opts SummaryOptions) (*Summary, error) {
 	s := &Summary{}
 
 	supportedResourceCounts := make(map[string]int)
 	unsupportedResourceCounts := make(map[string]int)
 	noPriceResourceCounts := make(map[string]int)
+	providerResourceCounts := make(map[string]int)
 	totalDetectedResources := 0
 	totalSupportedResources := 0
 	totalUnsupportedResources := 0
 	totalUsageBasedResources := 0
 	totalNoPriceResources := 0
+	totalPaidResources := 0
+	totalPricedResources := 0
+	totalCostComponents := 0
+	totalPricedCostComponents := 0
 
 	estimatedUsageCounts := make(map[string]int)
 	unestimatedUsageCounts := make(map[string]int)
@@ -738,6 +1026,7 @@ func BuildSummary(resources []*schema.Resource, opts SummaryOptions) (*Summary,
 		}
 
 		totalDetectedResources++
+		providerResourceCounts[resourceProvider(r.ResourceType)]++
 
 		if r.NoPrice {
 			totalNoPriceResources++
@@ -761,6 +1050,17 @@ func BuildSummary(resources []*schema.Resource, opts SummaryOptions) (*Summary,
 			if refFile.FindMatchingResourceUsage(r.Name) != nil {
 				totalUsageBasedResources++
 			}
+
+			if r.MonthlyCost != nil && r.MonthlyCost.IsPositive() {
+				totalPaidResources++
+			}
+
+			total, priced := countPricedCostComponents(r)
+			totalCostComponents += total
+			totalPricedCostComponents += priced
+			if priced == total {
+				totalPricedResources++
+			}
 		}
 
 		for usage, isEstimated := range r.EstimationSummary {
@@ -801,6 +1101,18 @@ func BuildSummary(resources []*schema.Resource, opts SummaryOptions) (*Summary,
 	if len(opts.OnlyFields) == 0 || contains(opts.OnlyFields, "TotalNoPriceResources") {
 		s.TotalNoPriceResources = &totalNoPriceResources
 	}
+	if len(opts.OnlyFields) == 0 || contains(opts.OnlyFields, "TotalPaidResources") {
+		s.TotalPaidResources = &totalPaidResources
+	}
+	if len(opts.OnlyFields) == 0 || contains(opts.OnlyFields, "TotalPricedResources") {
+		s.TotalPricedResources = &totalPricedResources
+	}
+	if len(opts.OnlyFields) == 0 || contains(opts.OnlyFields, "TotalCostComponents") {
+		s.TotalCostComponents = &totalCostComponents
+	}
+	if len(opts.OnlyFields) == 0 || contains(opts.OnlyFields, "TotalPricedCostComponents") {
+		s.TotalPricedCostComponents = &totalPricedCostComponents
+	}
 	if len(opts.OnlyFields) == 0 || contains(opts.OnlyFields, "SupportedResourceCounts") {
 		s.SupportedResourceCounts = &supportedResourceCounts
 	}
@@ -810,6 +1122,9 @@ func BuildSummary(resources []*schema.Resource, opts SummaryOptions) (*Summary,
 	if len(opts.OnlyFields) == 0 || contains(opts.OnlyFields, "NoPriceResourceCounts") {
 		s.NoPriceResourceCounts = &noPriceResourceCounts
 	}
+	if len(opts.OnlyFields) == 0 || contains(opts.OnlyFields, "ProviderResourceCounts") {
+		s.ProviderResourceCounts = &providerResourceCounts
+	}
 
 	if len(opts.OnlyFields) == 0 || contains(opts.OnlyFields, "EstimatedUsageCounts") {
 		s.EstimatedUsageCounts = &estimatedUsageCounts
@@ -841,9 +1156,14 @@ func MergeSummaries(summaries []*Summary) *Summary {
 		merged.TotalUnsupportedResources = addIntPtrs(merged.TotalUnsupportedResources, s.TotalUnsupportedResources)
 		merged.TotalUsageBasedResources = addIntPtrs(merged.TotalUsageBasedResources, s.TotalUsageBasedResources)
 		merged.TotalNoPriceResources = addIntPtrs(merged.TotalNoPriceResources, s.TotalNoPriceResources)
+		merged.TotalPaidResources = addIntPtrs(merged.TotalPaidResources, s.TotalPaidResources)
+		merged.TotalPricedResources = addIntPtrs(merged.TotalPricedResources, s.TotalPricedResources)
+		merged.TotalCostComponents = addIntPtrs(merged.TotalCostComponents, s.TotalCostComponents)
+		merged.TotalPricedCostComponents = addIntPtrs(merged.TotalPricedCostComponents, s.TotalPricedCostComponents)
 		merged.SupportedResourceCounts = mergeCounts(merged.SupportedResourceCounts, s.SupportedResourceCounts)
 		merged.UnsupportedResourceCounts = mergeCounts(merged.UnsupportedResourceCounts, s.UnsupportedResourceCounts)
 		merged.NoPriceResourceCounts = mergeCounts(merged.NoPriceResourceCounts, s.NoPriceResourceCounts)
+		merged.ProviderResourceCounts = mergeCounts(merged.ProviderResourceCounts, s.ProviderResourceCounts)
 
 		merged.EstimatedUsageCounts = mergeCounts(merged.EstimatedUsageCounts, s.EstimatedUsageCounts)
 		merged.UnestimatedUsageCounts = mergeCounts(merged.UnestimatedUsageCounts, s.UnestimatedUsageCounts)
@@ -931,6 +1251,14 @@ func mergeCounts(c1 *map[string]int, c2 *map[string]int) *map[string]int {
 	return &res
 }
 
+func intPtrValue(i *int) int {
+	if i == nil {
+		return 0
+	}
+
+	return *i
+}
+
 func addIntPtrs(i1 *int, i2 *int) *int {
 	if i1 == nil && i2 == nil {
 		return nil
@@ -5,6 +5,8 @@ import (
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/infracost/infracost/internal/schema"
 )
 
 func TestCalculateTotalCosts(t *testing.T) {
@@ -32,3 +34,87 @@ func TestCalculateTotalCosts(t *testing.T) {
 	actual, _ = totalMonthlyCost.Float64()
 	assert.Equal(t, expected, actual)
 }
+
+func TestOutputResourceSubResourceTypes(t *testing.T) {
+	r := &schema.Resource{
+		Name:         "aws_instance.web",
+		ResourceType: "aws_instance",
+		SubResources: []*schema.Resource{
+			{Name: "root_block_device", ResourceType: "aws_ebs_volume"},
+			{Name: "untyped"},
+		},
+	}
+
+	out := outputResource(r)
+	assert.Equal(t, "", out.Type)
+	assert.Equal(t, "aws_instance", out.ResourceType())
+	assert.Equal(t, "aws_ebs_volume", out.SubResources[0].ResourceType())
+	assert.Equal(t, "", out.SubResources[1].Type)
+
+	converted := convertOutputResources([]Resource{out})
+	assert.Equal(t, "aws_instance", converted[0].ResourceType)
+	assert.Equal(t, "aws_ebs_volume", converted[0].SubResources[0].ResourceType)
+	assert.Equal(t, "", converted[0].SubResources[1].ResourceType)
+}
+
+func TestResourceSummaryMessage(t *testing.T) {
+	resources := []*schema.Resource{
+		{Name: "aws_instance.web", ResourceType: "aws_instance", MonthlyCost: decimalPtr(decimal.NewFromInt(100))},
+		{Name: "aws_instance.app", ResourceType: "aws_instance", MonthlyCost: decimalPtr(decimal.NewFromInt(50))},
+		{Name: "aws_lambda_function.fn", ResourceType: "aws_lambda_function", MonthlyCost: decimalPtr(decimal.Zero)},
+		{Name: "aws_iam_role.role", ResourceType: "aws_iam_role", NoPrice: true, IsSkipped: true},
+		{Name: "google_foo.bar", ResourceType: "google_foo", IsSkipped: true},
+		{Name: "kubernetes_namespace.ns", ResourceType: "kubernetes_namespace", IsSkipped: true},
+	}
+
+	root, err := ToOutputFormat([]*schema.Project{{Name: "test", Metadata: &schema.ProjectMetadata{}, Resources: resources}})
+	assert.NoError(t, err)
+
+	expected := `Resource summary:
+∙ 4 supported, 2 not supported
+∙ 2 paid, 1 free, 1 with no cost until usage is specified
+∙ 66.6% of resources and 100.0% of cost components priced
+∙ By provider:
+  ∙ 4 x aws
+  ∙ 1 x google
+  ∙ 1 x kubernetes
+∙ By resource type:
+  ∙ 2 x aws_instance
+  ∙ 1 x aws_iam_role
+  ∙ 1 x aws_lambda_function
+  ∙ 1 x google_foo
+  ∙ 1 x kubernetes_namespace`
+	assert.Equal(t, expected, root.resourceSummaryMessage())
+
+	b, err := ToJSON(root, Options{ShowResourceSummary: true})
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"resourceSummary":{"totalDetectedResources":6,"totalSupportedResources":3,"totalUnsupportedResources":2,"totalNoPriceResources":1,"totalPaidResources":2`)
+
+	b, err = ToJSON(root, Options{})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), `"resourceSummary"`)
+}
+
+func TestSubResourceLabel(t *testing.T) {
+	tests := []struct {
+		name         string
+		resource     Resource
+		parentType   string
+		expected     string
+		expectedType string
+	}{
+		{"different type", Resource{Name: "root_block_device", Type: "aws_ebs_volume"}, "aws_instance", "root_block_device (aws_ebs_volume)", "aws_ebs_volume"},
+		{"same type", Resource{Name: "Standard", Type: "aws_s3_bucket"}, "aws_s3_bucket", "Standard", "aws_s3_bucket"},
+		{"untyped", Resource{Name: "Standard"}, "aws_s3_bucket", "Standard", "aws_s3_bucket"},
+		{"type in name", Resource{Name: "aws_launch_template.lt", Type: "aws_launch_template"}, "aws_autoscaling_group", "aws_launch_template.lt", "aws_launch_template"},
+		{"name is type", Resource{Name: "aws_db_instance", Type: "aws_db_instance"}, "aws_elastic_beanstalk_environment", "aws_db_instance", "aws_db_instance"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label, resourceType := subResourceLabel(tt.resource, tt.parentType)
+			assert.Equal(t, tt.expected, label)
+			assert.Equal(t, tt.expectedType, resourceType)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// maxOwnershipChangeResources is the number of resources that are listed for
+// each ownership change in the diff output.
+const maxOwnershipChangeResources = 3
+
+// OwnershipChange is monthly cost that moves from one owner to another between
+// the past and current breakdown of a project, because the owner tag of its
+// resources changed. From and To are the values of the owner tag, they are
+// empty if the resources aren't tagged.
+type OwnershipChange struct {
+	Tag         string           `json:"tag"`
+	From        string           `json:"from"`
+	To          string           `json:"to"`
+	MonthlyCost *decimal.Decimal `json:"monthlyCost"`
+	Resources   []string         `json:"resources"`
+}
+
+// withOwnershipChanges returns a copy of projects with the OwnershipChanges of
+// each project set. Changes below threshold aren't included.
+func withOwnershipChanges(projects Projects, tag string, threshold decimal.Decimal) Projects {
+	out := make(Projects, len(projects))
+	copy(out, projects)
+
+	for i := range out {
+		out[i].OwnershipChanges = ownershipChanges(out[i], tag, threshold)
+	}
+
+	return out
+}
+
+// ownershipChanges compares the tag of the resources that are in both the past
+// and current breakdown of p. The current monthly cost of a resource moves to
+// its new owner. Resources that are added or removed change the cost of their
+// owner but don't move any cost, so they're not included.
+func ownershipChanges(p Project, tag string, threshold decimal.Decimal) []OwnershipChange {
+	if p.PastBreakdown == nil || p.Breakdown == nil {
+		return nil
+	}
+
+	pastOwners := make(map[string]string, len(p.PastBreakdown.Resources))
+	for _, r := range p.PastBreakdown.Resources {
+		pastOwners[r.Name] = r.Tags[tag]
+	}
+
+	var changes []OwnershipChange
+	index := make(map[[2]string]int)
+
+	for _, r := range p.Breakdown.Resources {
+		from, ok := pastOwners[r.Name]
+		to := r.Tags[tag]
+		if !ok || from == to || r.MonthlyCost == nil || r.MonthlyCost.IsZero() {
+			continue
+		}
+
+		key := [2]string{from, to}
+		i, ok := index[key]
+		if !ok {
+			i = len(changes)
+			index[key] = i
+			changes = append(changes, OwnershipChange{Tag: tag, From: from, To: to, MonthlyCost: decimalPtr(decimal.Zero)})
+		}
+
+		changes[i].MonthlyCost = decimalPtr(changes[i].MonthlyCost.Add(*r.MonthlyCost))
+		changes[i].Resources = append(changes[i].Resources, r.Name)
+	}
+
+	significant := make([]OwnershipChange, 0, len(changes))
+	for _, c := range changes {
+		if c.MonthlyCost.Abs().LessThan(threshold) {
+			continue
+		}
+
+		sort.Strings(c.Resources)
+		significant = append(significant, c)
+	}
+
+	sort.SliceStable(significant, func(i, j int) bool {
+		return significant[i].MonthlyCost.Abs().GreaterThan(significant[j].MonthlyCost.Abs())
+	})
+
+	if len(significant) == 0 {
+		return nil
+	}
+
+	return significant
+}
+
+// ownershipChangesMessage returns the ownership changes of p for the diff
+// output.
+func ownershipChangesMessage(currency string, p Project) string {
+	if len(p.OwnershipChanges) == 0 {
+		return ""
+	}
+
+	s := ui.BoldString(fmt.Sprintf("Cost ownership changes (%s tag)", p.OwnershipChanges[0].Tag))
+
+	for _, c := range p.OwnershipChanges {
+		resources := c.Resources
+		more := ""
+		if len(resources) > maxOwnershipChangeResources {
+			more = fmt.Sprintf(" and %d more", len(resources)-maxOwnershipChangeResources)
+			resources = resources[:maxOwnershipChangeResources]
+		}
+
+		s += fmt.Sprintf("\n∙ %s moved from %s to %s %s",
+			FormatCost2DP(currency, c.MonthlyCost),
+			ownerLabel(c.From),
+			ownerLabel(c.To),
+			ui.FaintStringf("(%s%s)", strings.Join(resources, ", "), more),
+		)
+	}
+
+	return s
+}
+
+func ownerLabel(owner string) string {
+	if owner == "" {
+		return "untagged"
+	}
+
+	return owner
+}
@@ -0,0 +1,77 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestOwnershipChanges(t *testing.T) {
+	resource := func(name string, cost int64, team string) Resource {
+		r := Resource{Name: name, MonthlyCost: decimalPtr(decimal.NewFromInt(cost))}
+		if team != "" {
+			r.Tags = map[string]string{"team": team}
+		}
+		return r
+	}
+
+	past := &Breakdown{Resources: []Resource{
+		resource("aws_instance.api", 100, "platform"),
+		resource("aws_instance.worker", 50, "platform"),
+		resource("aws_s3_bucket.logs", 5, "platform"),
+		resource("aws_instance.batch", 20, ""),
+		resource("aws_instance.old", 30, "payments"),
+	}}
+	current := &Breakdown{Resources: []Resource{
+		resource("aws_instance.api", 100, "payments"),
+		resource("aws_instance.worker", 60, "payments"),
+		resource("aws_s3_bucket.logs", 5, "security"),
+		resource("aws_instance.batch", 20, "data"),
+		resource("aws_instance.new", 40, "data"),
+	}}
+
+	root := Root{
+		Currency: "USD",
+		Projects: Projects{{
+			Name:          "infra",
+			Metadata:      &schema.ProjectMetadata{},
+			PastBreakdown: past,
+			Breakdown:     current,
+			Diff:          &Breakdown{Resources: []Resource{}, TotalMonthlyCost: decimalPtr(decimal.Zero)},
+		}},
+	}
+
+	projects := withOwnershipChanges(root.Projects, "team", decimal.NewFromInt(10))
+	assert.Empty(t, root.Projects[0].OwnershipChanges, "the input projects should not be changed")
+
+	changes := projects[0].OwnershipChanges
+	require.Len(t, changes, 2)
+	assert.Equal(t, "platform", changes[0].From)
+	assert.Equal(t, "payments", changes[0].To)
+	assert.True(t, decimal.NewFromInt(160).Equal(*changes[0].MonthlyCost))
+	assert.Equal(t, []string{"aws_instance.api", "aws_instance.worker"}, changes[0].Resources)
+	assert.Equal(t, "", changes[1].From)
+	assert.Equal(t, "data", changes[1].To)
+	assert.True(t, decimal.NewFromInt(20).Equal(*changes[1].MonthlyCost))
+
+	expected := `Cost ownership changes (team tag)
+∙ $160.00 moved from platform to payments (aws_instance.api, aws_instance.worker)
+∙ $20.00 moved from untagged to data (aws_instance.batch)`
+	assert.Equal(t, expected, ownershipChangesMessage(root.Currency, projects[0]))
+
+	b, err := ToDiff(root, Options{NoColor: true, OwnershipTag: "team", OwnershipDriftThreshold: decimal.NewFromInt(10)})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), expected, "projects with only ownership changes should be shown")
+
+	b, err = ToJSON(root, Options{OwnershipTag: "team"})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"ownershipChanges":[{"tag":"team","from":"platform","to":"payments","monthlyCost":"160","resources":["aws_instance.api","aws_instance.worker"]}`)
+
+	b, err = ToJSON(root, Options{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), `"ownershipChanges"`)
+}
@@ -0,0 +1,75 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// PricingFailure is a cost component that has no price or that matched several
+// prices, so its cost may be wrong.
+type PricingFailure struct {
+	ProjectName   string `json:"projectName"`
+	ResourceName  string `json:"resourceName"`
+	CostComponent string `json:"costComponent"`
+	Reason        string `json:"reason"`
+}
+
+// CheckPricingFailures returns an error if the price of any cost component of
+// projects couldn't be resolved to a single price, so runs can fail instead of
+// silently using 0.00 or the first of several prices. The failures are added
+// to r so they're included in the JSON output.
+func CheckPricingFailures(r *Root, projects []*schema.Project) error {
+	var failures []PricingFailure
+
+	for _, p := range projects {
+		for _, res := range p.Resources {
+			failures = append(failures, resourcePricingFailures(p.Name, res.Name, res)...)
+		}
+	}
+
+	r.PricingFailures = failures
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	noun := "cost components don't"
+	if len(failures) == 1 {
+		noun = "cost component doesn't"
+	}
+
+	lines := make([]string, 0, len(failures))
+	for _, f := range failures {
+		lines = append(lines, fmt.Sprintf("  ∙ %s %s: %s", f.ResourceName, f.CostComponent, f.Reason))
+	}
+
+	return fmt.Errorf("%d %s have a single price:\n%s", len(failures), noun, strings.Join(lines, "\n"))
+}
+
+// resourcePricingFailures returns the pricing failures of res and its sub
+// resources. Sub resources are named by their path from the top level
+// resource, e.g. aws_instance.web.root_block_device.
+func resourcePricingFailures(projectName string, name string, res *schema.Resource) []PricingFailure {
+	var failures []PricingFailure
+
+	for _, c := range res.CostComponents {
+		if c.PriceWarning() == "" {
+			continue
+		}
+
+		failures = append(failures, PricingFailure{
+			ProjectName:   projectName,
+			ResourceName:  name,
+			CostComponent: c.Name,
+			Reason:        c.PriceWarning(),
+		})
+	}
+
+	for _, sub := range res.SubResources {
+		failures = append(failures, resourcePricingFailures(projectName, name+"."+sub.Name, sub)...)
+	}
+
+	return failures
+}
@@ -0,0 +1,45 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestCheckPricingFailures(t *testing.T) {
+	noPrice := &schema.CostComponent{Name: "Storage"}
+	noPrice.SetPriceWarning("No prices found")
+
+	resources := []*schema.Resource{
+		{Name: "aws_instance.web", ResourceType: "aws_instance", CostComponents: []*schema.CostComponent{pricedCostComponent("a")}},
+		{
+			Name:           "aws_instance.app",
+			ResourceType:   "aws_instance",
+			CostComponents: []*schema.CostComponent{pricedCostComponent("b")},
+			SubResources: []*schema.Resource{
+				{Name: "root_block_device", CostComponents: []*schema.CostComponent{noPrice}},
+			},
+		},
+	}
+
+	projects := []*schema.Project{{Name: "test", Metadata: &schema.ProjectMetadata{}, Resources: resources}}
+
+	root, err := ToOutputFormat(projects)
+	require.NoError(t, err)
+
+	err = CheckPricingFailures(&root, projects)
+	assert.EqualError(t, err, "1 cost component doesn't have a single price:\n  ∙ aws_instance.app.root_block_device Storage: No prices found")
+	assert.Equal(t, []PricingFailure{{ProjectName: "test", ResourceName: "aws_instance.app.root_block_device", CostComponent: "Storage", Reason: "No prices found"}}, root.PricingFailures)
+
+	b, err := ToJSON(root, Options{})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"pricingFailures":[{"projectName":"test","resourceName":"aws_instance.app.root_block_device","costComponent":"Storage","reason":"No prices found"}]`)
+
+	noPrice.SetPriceWarning("")
+	err = CheckPricingFailures(&root, projects)
+	assert.NoError(t, err)
+	assert.Empty(t, root.PricingFailures)
+}
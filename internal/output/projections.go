@@ -0,0 +1,112 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// Projection is the cumulative cost over a number of months given the growth
+// of the projects set in the config file.
+type Projection struct {
+	Months    int              `json:"months"`
+	TotalCost *decimal.Decimal `json:"totalCost"`
+}
+
+func outputProjections(costs map[int]*decimal.Decimal) []Projection {
+	if len(costs) == 0 {
+		return nil
+	}
+
+	projections := make([]Projection, 0, len(schema.ProjectionMonths))
+	for _, months := range schema.ProjectionMonths {
+		if c, ok := costs[months]; ok {
+			projections = append(projections, Projection{Months: months, TotalCost: c})
+		}
+	}
+
+	return projections
+}
+
+// projectionCost returns the projected cost over the months, or the monthly
+// cost over the months if there's no projection for them.
+func projectionCost(projections []Projection, months int, monthlyCost *decimal.Decimal) decimal.Decimal {
+	for _, p := range projections {
+		if p.Months == months {
+			return decimalValue(p.TotalCost)
+		}
+	}
+
+	return decimalValue(monthlyCost).Mul(decimal.NewFromInt(int64(months)))
+}
+
+// sumProjections returns the projections of the sum of n costs, or nil if
+// none of them has projections.
+func sumProjections(n int, projections func(i int) []Projection, monthlyCost func(i int) *decimal.Decimal) []Projection {
+	hasProjections := false
+	for i := 0; i < n; i++ {
+		if len(projections(i)) > 0 {
+			hasProjections = true
+			break
+		}
+	}
+
+	if !hasProjections {
+		return nil
+	}
+
+	summed := make([]Projection, 0, len(schema.ProjectionMonths))
+	for _, months := range schema.ProjectionMonths {
+		total := decimal.Zero
+		for i := 0; i < n; i++ {
+			total = total.Add(projectionCost(projections(i), months, monthlyCost(i)))
+		}
+		summed = append(summed, Projection{Months: months, TotalCost: decimalPtr(total)})
+	}
+
+	return summed
+}
+
+// resourcesProjections returns the projections of the total cost of the
+// resources, or nil if none of them has projections.
+func resourcesProjections(resources []Resource) []Projection {
+	return sumProjections(len(resources),
+		func(i int) []Projection { return resources[i].Projections },
+		func(i int) *decimal.Decimal { return resources[i].MonthlyCost },
+	)
+}
+
+// projectsProjections returns the projections of the total cost of the
+// projects, or nil if none of them has projections. Projects without a growth
+// add their monthly cost for each of the months.
+func projectsProjections(projects []Project) []Projection {
+	breakdowns := make([]*Breakdown, 0, len(projects))
+	for _, p := range projects {
+		if p.Breakdown != nil {
+			breakdowns = append(breakdowns, p.Breakdown)
+		}
+	}
+
+	return sumProjections(len(breakdowns),
+		func(i int) []Projection { return breakdowns[i].Projections },
+		func(i int) *decimal.Decimal { return breakdowns[i].TotalMonthlyCost },
+	)
+}
+
+// ProjectionsMessage returns the projected cumulative costs if some of the
+// projects have a growth, e.g. for comment templates.
+func (r Root) ProjectionsMessage() string {
+	if len(r.Projections) == 0 {
+		return ""
+	}
+
+	costs := make([]string, 0, len(r.Projections))
+	for _, p := range r.Projections {
+		costs = append(costs, fmt.Sprintf("%s over %d months", FormatCost2DP(r.Currency, p.TotalCost), p.Months))
+	}
+
+	return "Projected cumulative cost with the expected growth: " + strings.Join(costs, ", ")
+}
@@ -0,0 +1,65 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestOutputProjections(t *testing.T) {
+	newResource := func(name string, monthlyCost int64) *schema.Resource {
+		c := &schema.CostComponent{
+			Name:            "Usage",
+			Unit:            "hours",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: decimalPtr(decimal.NewFromInt(monthlyCost)),
+		}
+		c.SetPrice(decimal.NewFromInt(1))
+
+		r := &schema.Resource{Name: name, CostComponents: []*schema.CostComponent{c}}
+		r.CalculateCosts()
+		return r
+	}
+
+	growing := &schema.Project{
+		Name:      "growing",
+		Resources: []*schema.Resource{newResource("aws_instance.web", 10), newResource("aws_instance.api", 20)},
+		Growth:    &schema.Growth{MonthlyRate: 100},
+	}
+	growing.CalculateProjections()
+
+	flat := &schema.Project{
+		Name:      "flat",
+		Resources: []*schema.Resource{newResource("aws_instance.db", 5)},
+	}
+
+	root, err := ToOutputFormat([]*schema.Project{growing, flat})
+	require.NoError(t, err)
+	root.Currency = "USD"
+
+	projections := root.Projects[0].Breakdown.Projections
+	require.Len(t, projections, 2)
+	assert.Equal(t, 12, projections[0].Months)
+	assert.Equal(t, "122850", projections[0].TotalCost.String())
+	assert.Equal(t, 36, projections[1].Months)
+	assert.Equal(t, "2061584302050", projections[1].TotalCost.String())
+	assert.Nil(t, root.Projects[1].Breakdown.Projections)
+
+	// Projects without a growth add their monthly cost for each month.
+	assert.Equal(t, "122910", root.Projections[0].TotalCost.String())
+	assert.Equal(t, "Projected cumulative cost with the expected growth: $122,910.00 over 12 months, $2,061,584,302,230.00 over 36 months", root.ProjectionsMessage())
+
+	filters, err := ParseResourceFilters([]string{"name=aws_instance.web"})
+	require.NoError(t, err)
+	FilterResources(&root, filters)
+	assert.Equal(t, "40950", root.Projects[0].Breakdown.Projections[0].TotalCost.String())
+
+	withoutGrowth, err := ToOutputFormat([]*schema.Project{flat})
+	require.NoError(t, err)
+	assert.Nil(t, withoutGrowth.Projections)
+	assert.Equal(t, "", withoutGrowth.ProjectionsMessage())
+}
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// ToPrometheus returns the monthly costs of the projects and their resources
+// as gauges in the Prometheus text exposition format, so they can be pushed to
+// a Pushgateway or scraped from a file and graphed over time. Resource gauges
+// are labelled with the provider, the service, which is the resource type,
+// and the module the resource is in. Resources with costs that depend on
+// usage that isn't set have no gauge.
+func ToPrometheus(out Root, opts Options) ([]byte, error) {
+	var b strings.Builder
+
+	b.WriteString("# HELP infracost_project_monthly_cost Estimated monthly cost of the project.\n")
+	b.WriteString("# TYPE infracost_project_monthly_cost gauge\n")
+	for _, p := range out.Projects {
+		if p.Breakdown == nil {
+			continue
+		}
+
+		writePrometheusSample(&b, "infracost_project_monthly_cost", [][2]string{
+			{"project", p.Label()},
+			{"currency", out.Currency},
+		}, p.Breakdown.TotalMonthlyCost)
+	}
+
+	b.WriteString("# HELP infracost_resource_monthly_cost Estimated monthly cost of the resource.\n")
+	b.WriteString("# TYPE infracost_resource_monthly_cost gauge\n")
+	for _, p := range out.Projects {
+		if p.Breakdown == nil {
+			continue
+		}
+
+		for _, r := range p.Breakdown.Resources {
+			if r.MonthlyCost == nil {
+				continue
+			}
+
+			resourceType := r.ResourceType()
+			writePrometheusSample(&b, "infracost_resource_monthly_cost", [][2]string{
+				{"project", p.Label()},
+				{"resource", r.Name},
+				{"provider", resourceProvider(resourceType)},
+				{"service", resourceType},
+				{"module", strings.Join(moduleSegments(r.Name), ".")},
+				{"currency", out.Currency},
+			}, r.MonthlyCost)
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+func writePrometheusSample(b *strings.Builder, name string, labels [][2]string, value *decimal.Decimal) {
+	if value == nil {
+		return
+	}
+
+	pairs := make([]string, 0, len(labels))
+	for _, l := range labels {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, l[0], prometheusLabelEscaper.Replace(l[1])))
+	}
+
+	fmt.Fprintf(b, "%s{%s} %s\n", name, strings.Join(pairs, ","), value.String())
+}
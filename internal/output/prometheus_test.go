@@ -0,0 +1,40 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToPrometheus(t *testing.T) {
+	r := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name: `infracost/"example"`,
+				Breakdown: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.RequireFromString("42.5")),
+					Resources: []Resource{
+						{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.RequireFromString("40"))},
+						{Name: `module.app["a"].aws_ebs_volume.data`, MonthlyCost: decimalPtr(decimal.RequireFromString("2.5"))},
+						{Name: "aws_lambda_function.hello"},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := ToPrometheus(r, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, `# HELP infracost_project_monthly_cost Estimated monthly cost of the project.
+# TYPE infracost_project_monthly_cost gauge
+infracost_project_monthly_cost{project="infracost/\"example\"",currency="USD"} 42.5
+# HELP infracost_resource_monthly_cost Estimated monthly cost of the resource.
+# TYPE infracost_resource_monthly_cost gauge
+infracost_resource_monthly_cost{project="infracost/\"example\"",resource="aws_instance.web",provider="aws",service="aws_instance",module="",currency="USD"} 40
+infracost_resource_monthly_cost{project="infracost/\"example\"",resource="module.app[\"a\"].aws_ebs_volume.data",provider="aws",service="aws_ebs_volume",module="module.app[\"a\"]",currency="USD"} 2.5
+`, string(b))
+}
@@ -0,0 +1,140 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// Recommendation is a cheaper alternative to a resource, e.g. a smaller
+// instance type for an instance that uses little of its CPU and memory.
+type Recommendation struct {
+	// Type is the kind of recommendation, either rightsizing or quick_win.
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	// MonthlyCost is the monthly cost of the alternative to the cost
+	// components that the recommendation replaces. It's nil if the
+	// recommendation removes them.
+	MonthlyCost    *decimal.Decimal `json:"monthlyCost"`
+	MonthlySavings *decimal.Decimal `json:"monthlySavings"`
+}
+
+// ResourceRecommendation is a recommendation for a resource of a project.
+type ResourceRecommendation struct {
+	ProjectName  string
+	ResourceName string
+	Recommendation
+}
+
+// outputRecommendations returns the recommendations that save money. The
+// recommendations whose alternatives couldn't be priced, or don't cost less,
+// aren't included.
+func outputRecommendations(recommendations []*schema.Recommendation) []Recommendation {
+	var recs []Recommendation
+	for _, r := range recommendations {
+		savings := r.MonthlySavings()
+		if savings == nil || !savings.IsPositive() {
+			continue
+		}
+
+		var monthlyCost *decimal.Decimal
+		if r.Alternative != nil {
+			monthlyCost = r.Alternative.MonthlyCost
+		}
+
+		recs = append(recs, Recommendation{
+			Type:           r.Type,
+			Description:    r.Description,
+			MonthlyCost:    monthlyCost,
+			MonthlySavings: savings,
+		})
+	}
+
+	return recs
+}
+
+// Recommendations returns the recommendations of the given type for the
+// resources of all the projects, with the largest savings first. The
+// recommendations for sub resources are named after their parent resource,
+// e.g. aws_instance.web.root_block_device.
+func (r Root) Recommendations(recType string) []ResourceRecommendation {
+	var recs []ResourceRecommendation
+
+	var add func(projectName string, name string, res Resource)
+	add = func(projectName string, name string, res Resource) {
+		for _, rec := range res.Recommendations {
+			if rec.Type != recType {
+				continue
+			}
+
+			recs = append(recs, ResourceRecommendation{
+				ProjectName:    projectName,
+				ResourceName:   name,
+				Recommendation: rec,
+			})
+		}
+
+		for _, sub := range res.SubResources {
+			add(projectName, name+"."+sub.Name, sub)
+		}
+	}
+
+	for _, p := range r.Projects {
+		for _, res := range breakdownResources(p.Breakdown) {
+			add(p.Name, res.Name, res)
+		}
+	}
+
+	sort.SliceStable(recs, func(i, j int) bool {
+		return decimalValue(recs[i].MonthlySavings).GreaterThan(decimalValue(recs[j].MonthlySavings))
+	})
+
+	return recs
+}
+
+// RecommendationMessages returns the recommendations of the given type for
+// the resources of all the projects with their savings, e.g. for comment
+// templates.
+func (r Root) RecommendationMessages(recType string) []string {
+	recs := r.Recommendations(recType)
+
+	msgs := make([]string, 0, len(recs))
+	for _, rec := range recs {
+		msgs = append(msgs, fmt.Sprintf("%s: %s, saving %s per month", rec.ResourceName, rec.Description, FormatCost2DP(r.Currency, rec.MonthlySavings)))
+	}
+
+	return msgs
+}
+
+// recommendationsMessage returns the recommendations of the given type for
+// the resources and the total monthly savings if they're all followed.
+func (r *Root) recommendationsMessage(recType string) string {
+	recs := r.Recommendations(recType)
+	if len(recs) == 0 {
+		return ""
+	}
+
+	total := decimal.Zero
+	for _, rec := range recs {
+		total = total.Add(decimalValue(rec.MonthlySavings))
+	}
+
+	noun := "rightsizing recommendations"
+	if recType == schema.RecommendationTypeQuickWin {
+		noun = "quick wins"
+	}
+	if len(recs) == 1 {
+		noun = strings.TrimSuffix(noun, "s")
+	}
+
+	msg := fmt.Sprintf("%d %s could save %s per month:", len(recs), noun, FormatCost2DP(r.Currency, &total))
+	for _, m := range r.RecommendationMessages(recType) {
+		msg += "\n∙ " + m
+	}
+
+	return msg
+}
@@ -0,0 +1,126 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func pricedInstanceCostComponent(instanceType string, price float64) *schema.CostComponent {
+	c := &schema.CostComponent{
+		Name:            "Instance usage (Linux/UNIX, on-demand, " + instanceType + ")",
+		Unit:            "hours",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)),
+	}
+	c.SetPrice(decimal.NewFromFloat(price))
+
+	return c
+}
+
+func TestOutputRecommendations(t *testing.T) {
+	current := pricedInstanceCostComponent("m5.xlarge", 0.192)
+	r := &schema.Resource{
+		Name:           "aws_instance.web",
+		CostComponents: []*schema.CostComponent{current},
+		Recommendations: []*schema.Recommendation{
+			{
+				Type:           schema.RecommendationTypeRightsizing,
+				Description:    "Downsize from m5.xlarge to m5.large",
+				CostComponents: []*schema.CostComponent{current},
+				Alternative:    &schema.Resource{CostComponents: []*schema.CostComponent{pricedInstanceCostComponent("m5.large", 0.096)}},
+			},
+			{
+				// The alternative had no price so its cost component was removed.
+				Description:    "Downsize from m5.xlarge to m5.medium",
+				CostComponents: []*schema.CostComponent{current},
+				Alternative:    &schema.Resource{},
+			},
+			{
+				Description:    "Use a more expensive instance",
+				CostComponents: []*schema.CostComponent{current},
+				Alternative:    &schema.Resource{CostComponents: []*schema.CostComponent{pricedInstanceCostComponent("m5.2xlarge", 0.384)}},
+			},
+		},
+	}
+	r.CalculateCosts()
+
+	res := outputResource(r)
+	require.Len(t, res.Recommendations, 1)
+	assert.Equal(t, "70.08", res.Recommendations[0].MonthlyCost.String())
+	assert.Equal(t, "70.08", res.Recommendations[0].MonthlySavings.String())
+
+	root := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name: "infra",
+				Breakdown: &Breakdown{Resources: []Resource{
+					{Name: "aws_db_instance.db", Recommendations: []Recommendation{{Type: schema.RecommendationTypeRightsizing, Description: "Downsize from db.r5.xlarge to db.r5.large", MonthlySavings: decimalPtr(decimal.NewFromInt(120))}}},
+					res,
+				}},
+			},
+		},
+	}
+
+	assert.Equal(t, `2 rightsizing recommendations could save $190.08 per month:
+∙ aws_db_instance.db: Downsize from db.r5.xlarge to db.r5.large, saving $120.00 per month
+∙ aws_instance.web: Downsize from m5.xlarge to m5.large, saving $70.08 per month`, root.recommendationsMessage(schema.RecommendationTypeRightsizing))
+	assert.Equal(t, "", root.recommendationsMessage(schema.RecommendationTypeQuickWin))
+}
+
+func TestOutputQuickWins(t *testing.T) {
+	eip := &schema.CostComponent{
+		Name:            "IP address (if unused)",
+		Unit:            "hours",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)),
+	}
+	eip.SetPrice(decimal.NewFromFloat(0.005))
+
+	r := &schema.Resource{
+		Name:           "aws_eip.unused",
+		CostComponents: []*schema.CostComponent{eip},
+		Recommendations: []*schema.Recommendation{
+			{
+				// The recommendation has no alternative so it saves the whole cost.
+				Type:           schema.RecommendationTypeQuickWin,
+				Description:    "Release the Elastic IP as it isn't attached to an instance or network interface",
+				CostComponents: []*schema.CostComponent{eip},
+			},
+		},
+	}
+	r.CalculateCosts()
+
+	res := outputResource(r)
+	require.Len(t, res.Recommendations, 1)
+	assert.Nil(t, res.Recommendations[0].MonthlyCost)
+	assert.Equal(t, "3.65", res.Recommendations[0].MonthlySavings.String())
+
+	root := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name: "infra",
+				Breakdown: &Breakdown{Resources: []Resource{
+					res,
+					{
+						Name: "aws_instance.web",
+						SubResources: []Resource{
+							{Name: "root_block_device", Recommendations: []Recommendation{{Type: schema.RecommendationTypeQuickWin, Description: "Use gp3 instead of gp2", MonthlySavings: decimalPtr(decimal.NewFromInt(2))}}},
+						},
+					},
+				}},
+			},
+		},
+	}
+
+	assert.Equal(t, `2 quick wins could save $5.65 per month:
+∙ aws_eip.unused: Release the Elastic IP as it isn't attached to an instance or network interface, saving $3.65 per month
+∙ aws_instance.web.root_block_device: Use gp3 instead of gp2, saving $2.00 per month`, root.recommendationsMessage(schema.RecommendationTypeQuickWin))
+	assert.Equal(t, "", root.recommendationsMessage(schema.RecommendationTypeRightsizing))
+}
@@ -0,0 +1,78 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrReportNotCached is returned by ReportCache.Load when no report is stored
+// under the given key.
+var ErrReportNotCached = errors.New("report not cached")
+
+// ReportCache stores finished reports on disk so that they can be re-rendered
+// in any format without re-running the estimate. Reports are keyed by the repo,
+// commit and config they were estimated from, see ReportCacheKey.
+type ReportCache struct {
+	dir string
+}
+
+// NewReportCache returns a ReportCache that stores reports in dir.
+func NewReportCache(dir string) *ReportCache {
+	return &ReportCache{dir: dir}
+}
+
+// ReportCacheKey returns the key that the report for a commit of repo, estimated
+// with the config that hashes to configHash, is stored under.
+func ReportCacheKey(repo, commit, configHash string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{repo, commit, configHash}, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Save stores r under key, replacing any report that is already stored there.
+func (c *ReportCache) Save(key string, r Root) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(c.dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a concurrent Load never sees a partial report.
+	f, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), c.path(key))
+}
+
+// Load returns the report stored under key.
+func (c *ReportCache) Load(key string) (Root, error) {
+	p := c.path(key)
+	if _, err := os.Stat(p); errors.Is(err, os.ErrNotExist) {
+		return Root{}, ErrReportNotCached
+	}
+
+	return Load(p)
+}
+
+func (c *ReportCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportCache(t *testing.T) {
+	c := NewReportCache(t.TempDir())
+	key := ReportCacheKey("https://github.com/infracost/infracost", "abc123", "hash")
+
+	_, err := c.Load(key)
+	assert.ErrorIs(t, err, ErrReportNotCached)
+
+	r := Root{
+		Version:          outputVersion,
+		Currency:         "USD",
+		TotalMonthlyCost: decimalPtr(decimal.NewFromInt(100)),
+	}
+	require.NoError(t, c.Save(key, r))
+
+	loaded, err := c.Load(key)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", loaded.Currency)
+	assert.True(t, decimal.NewFromInt(100).Equal(*loaded.TotalMonthlyCost))
+
+	// Reports of other commits and config are stored separately
+	assert.NotEqual(t, key, ReportCacheKey("https://github.com/infracost/infracost", "def456", "hash"))
+	assert.NotEqual(t, key, ReportCacheKey("https://github.com/infracost/infracost", "abc123", "other"))
+}
@@ -0,0 +1,190 @@
+package output
+
+import (
+	"regexp"
+
+	"github.com/shopspring/decimal"
+)
+
+// instanceIndexRegex matches the count index or for_each key at the end of a
+// resource address, e.g. [0] or ["prod"].
+var instanceIndexRegex = regexp.MustCompile(`\[[^\[\]]+\]$`)
+
+// RollUpInstances returns r with the instances of each resource that uses count
+// or for_each combined into one resource, named with a [*] index like the
+// wildcard keys of the usage file. The quantities and costs of cost components
+// with the same name, unit and price are summed, so instances with different
+// prices, e.g. different instance types, still show separately.
+func RollUpInstances(r Root) Root {
+	projects := make([]Project, len(r.Projects))
+
+	for i, p := range r.Projects {
+		p.PastBreakdown = rollUpBreakdown(p.PastBreakdown)
+		p.Breakdown = rollUpBreakdown(p.Breakdown)
+		p.Diff = rollUpBreakdown(p.Diff)
+		projects[i] = p
+	}
+
+	r.Projects = projects
+
+	return r
+}
+
+func rollUpBreakdown(b *Breakdown) *Breakdown {
+	if b == nil {
+		return nil
+	}
+
+	rolled := *b
+	rolled.Resources = rollUpResources(b.Resources)
+
+	return &rolled
+}
+
+// rollUpResources combines the resources with the same address apart from their
+// instance index. Resources without an index or with only one instance are left
+// as they are.
+func rollUpResources(resources []Resource) []Resource {
+	var keys []string
+	groups := make(map[string][]Resource)
+
+	for _, r := range resources {
+		key := r.Name
+		if loc := instanceIndexRegex.FindStringIndex(r.Name); loc != nil {
+			key = r.Name[:loc[0]] + "[*]"
+		}
+
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], r)
+	}
+
+	rolled := make([]Resource, 0, len(keys))
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) == 1 {
+			rolled = append(rolled, group[0])
+			continue
+		}
+
+		r := mergeResources(key, group)
+		r.Metadata = make(map[string]interface{}, len(group[0].Metadata)+1)
+		for k, v := range group[0].Metadata {
+			r.Metadata[k] = v
+		}
+		r.Metadata["instanceCount"] = len(group)
+
+		rolled = append(rolled, r)
+	}
+
+	return rolled
+}
+
+// mergeResources returns a resource with the summed costs of the resources, and
+// their cost components and sub resources merged by name. Only the tags that all
+// the resources have are kept.
+func mergeResources(name string, resources []Resource) Resource {
+	merged := Resource{
+		Name: name,
+		Type: resources[0].Type,
+		Tags: commonTags(resources),
+	}
+
+	var subNames []string
+	subResources := make(map[string][]Resource)
+
+	for _, r := range resources {
+		merged.HourlyCost = addDecimalPtrs(merged.HourlyCost, r.HourlyCost)
+		merged.MonthlyCost = addDecimalPtrs(merged.MonthlyCost, r.MonthlyCost)
+		merged.CostComponents = mergeCostComponents(merged.CostComponents, r.CostComponents)
+		merged.ActualCosts = append(merged.ActualCosts, r.ActualCosts...)
+
+		for _, sub := range r.SubResources {
+			if _, ok := subResources[sub.Name]; !ok {
+				subNames = append(subNames, sub.Name)
+			}
+			subResources[sub.Name] = append(subResources[sub.Name], sub)
+		}
+	}
+
+	for _, subName := range subNames {
+		merged.SubResources = append(merged.SubResources, mergeResources(subName, subResources[subName]))
+	}
+
+	merged.LowMonthlyCost, merged.HighMonthlyCost = resourcesCostRange(resources)
+	merged.Projections = resourcesProjections(resources)
+
+	return merged
+}
+
+// mergeCostComponents adds the quantities and costs of the components to the
+// merged components with the same name, unit and price, or appends them if
+// there is none.
+func mergeCostComponents(merged []CostComponent, components []CostComponent) []CostComponent {
+	for _, c := range components {
+		found := false
+
+		for i, m := range merged {
+			if m.Name != c.Name || m.Unit != c.Unit || !m.Price.Equal(c.Price) {
+				continue
+			}
+
+			if m.LowMonthlyCost != nil || c.LowMonthlyCost != nil {
+				merged[i].LowMonthlyQuantity = decimalPtr(rangeBound(m.LowMonthlyQuantity, m.MonthlyQuantity).Add(rangeBound(c.LowMonthlyQuantity, c.MonthlyQuantity)))
+				merged[i].HighMonthlyQuantity = decimalPtr(rangeBound(m.HighMonthlyQuantity, m.MonthlyQuantity).Add(rangeBound(c.HighMonthlyQuantity, c.MonthlyQuantity)))
+				merged[i].LowMonthlyCost = decimalPtr(rangeBound(m.LowMonthlyCost, m.MonthlyCost).Add(rangeBound(c.LowMonthlyCost, c.MonthlyCost)))
+				merged[i].HighMonthlyCost = decimalPtr(rangeBound(m.HighMonthlyCost, m.MonthlyCost).Add(rangeBound(c.HighMonthlyCost, c.MonthlyCost)))
+			}
+
+			merged[i].HourlyQuantity = addDecimalPtrs(m.HourlyQuantity, c.HourlyQuantity)
+			merged[i].MonthlyQuantity = addDecimalPtrs(m.MonthlyQuantity, c.MonthlyQuantity)
+			merged[i].HourlyCost = addDecimalPtrs(m.HourlyCost, c.HourlyCost)
+			merged[i].MonthlyCost = addDecimalPtrs(m.MonthlyCost, c.MonthlyCost)
+			found = true
+			break
+		}
+
+		if !found {
+			merged = append(merged, c)
+		}
+	}
+
+	return merged
+}
+
+// addDecimalPtrs returns the sum of a and b, which is nil if both are nil.
+func addDecimalPtrs(a, b *decimal.Decimal) *decimal.Decimal {
+	if a == nil {
+		return b
+	}
+
+	if b == nil {
+		return a
+	}
+
+	return decimalPtr(a.Add(*b))
+}
+
+func commonTags(resources []Resource) map[string]string {
+	if resources[0].Tags == nil {
+		return nil
+	}
+
+	tags := make(map[string]string)
+	for k, v := range resources[0].Tags {
+		common := true
+		for _, r := range resources[1:] {
+			if r.Tags[k] != v {
+				common = false
+				break
+			}
+		}
+
+		if common {
+			tags[k] = v
+		}
+	}
+
+	return tags
+}
@@ -0,0 +1,81 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollUpInstances(t *testing.T) {
+	d := func(i int64) *decimal.Decimal { return decimalPtr(decimal.NewFromInt(i)) }
+
+	instance := func(name string, instanceType string, price int64, env string) Resource {
+		return Resource{
+			Name:        name,
+			Tags:        map[string]string{"team": "web", "env": env},
+			Metadata:    map[string]interface{}{"filename": "main.tf"},
+			MonthlyCost: d(price * 730),
+			CostComponents: []CostComponent{
+				{Name: "Instance usage (" + instanceType + ")", Unit: "hours", MonthlyQuantity: d(730), Price: decimal.NewFromInt(price), MonthlyCost: d(price * 730)},
+			},
+			SubResources: []Resource{
+				{
+					Name:        "root_block_device",
+					MonthlyCost: d(8),
+					CostComponents: []CostComponent{
+						{Name: "Storage (general purpose SSD, gp3)", Unit: "GB", MonthlyQuantity: d(100), Price: decimal.RequireFromString("0.08"), MonthlyCost: d(8)},
+					},
+				},
+			},
+		}
+	}
+
+	root := Root{
+		Projects: []Project{
+			{
+				Name: "test",
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						instance("aws_instance.web[0]", "t3.medium", 1, "prod"),
+						instance("aws_instance.web[1]", "t3.medium", 1, "prod"),
+						instance("aws_instance.web[2]", "t3.large", 2, "staging"),
+						instance(`module.app["prod"].aws_instance.api["a"]`, "t3.medium", 1, "prod"),
+						instance("aws_instance.single", "t3.medium", 1, "prod"),
+					},
+				},
+			},
+		},
+	}
+
+	rolled := RollUpInstances(root)
+
+	require.Len(t, root.Projects[0].Breakdown.Resources, 5, "the original breakdown should not be changed")
+
+	resources := rolled.Projects[0].Breakdown.Resources
+	require.Len(t, resources, 3)
+
+	web := resources[0]
+	assert.Equal(t, "aws_instance.web[*]", web.Name)
+	assert.Equal(t, map[string]string{"team": "web"}, web.Tags)
+	assert.Equal(t, 3, web.Metadata["instanceCount"])
+	assert.Equal(t, "main.tf", web.Metadata["filename"])
+	assert.Equal(t, "2920", web.MonthlyCost.String())
+
+	require.Len(t, web.CostComponents, 2)
+	assert.Equal(t, "Instance usage (t3.medium)", web.CostComponents[0].Name)
+	assert.Equal(t, "1460", web.CostComponents[0].MonthlyQuantity.String())
+	assert.Equal(t, "1460", web.CostComponents[0].MonthlyCost.String())
+	assert.Equal(t, "Instance usage (t3.large)", web.CostComponents[1].Name)
+	assert.Equal(t, "730", web.CostComponents[1].MonthlyQuantity.String())
+
+	require.Len(t, web.SubResources, 1)
+	assert.Equal(t, "root_block_device", web.SubResources[0].Name)
+	assert.Equal(t, "300", web.SubResources[0].CostComponents[0].MonthlyQuantity.String())
+	assert.Equal(t, "24", web.SubResources[0].MonthlyCost.String())
+
+	assert.Equal(t, `module.app["prod"].aws_instance.api["a"]`, resources[1].Name)
+	assert.Equal(t, "aws_instance.single", resources[2].Name)
+	assert.Nil(t, resources[2].Metadata["instanceCount"])
+}
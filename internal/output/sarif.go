@@ -0,0 +1,246 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	sarifRuleCostIncrease        = "cost-increase"
+	sarifRuleUnsupportedResource = "unsupported-resource"
+	sarifRuleMissingUsage        = "missing-usage"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+var sarifRules = []sarifRule{
+	{
+		ID:                   sarifRuleCostIncrease,
+		ShortDescription:     sarifMessage{Text: "Resource monthly cost increases by more than the threshold"},
+		DefaultConfiguration: sarifConfiguration{Level: "warning"},
+	},
+	{
+		ID:                   sarifRuleUnsupportedResource,
+		ShortDescription:     sarifMessage{Text: "Resource is not supported by Infracost so its cost isn't estimated"},
+		DefaultConfiguration: sarifConfiguration{Level: "note"},
+	},
+	{
+		ID:                   sarifRuleMissingUsage,
+		ShortDescription:     sarifMessage{Text: "Resource costs depend on usage that isn't set in the usage file"},
+		DefaultConfiguration: sarifConfiguration{Level: "note"},
+	},
+}
+
+// ToSARIF returns the cost warnings of the projects as a SARIF log, so code
+// scanning tools can annotate the Terraform resources that cause them. The
+// warnings are resources with a monthly cost increase over
+// opts.CostIncreaseThreshold, unsupported resources and resources with missing
+// usage. Resources are located by the filename and line in their metadata,
+// which is only set for resources parsed from HCL.
+func ToSARIF(out Root, opts Options) ([]byte, error) {
+	results := make([]sarifResult, 0)
+
+	projects := make(map[string]Project, len(out.Projects))
+	for _, p := range out.Projects {
+		projects[p.Name] = p
+
+		if p.Diff == nil {
+			continue
+		}
+
+		for _, d := range p.Diff.Resources {
+			if d.MonthlyCost == nil || !d.MonthlyCost.IsPositive() || d.MonthlyCost.LessThanOrEqual(opts.CostIncreaseThreshold) {
+				continue
+			}
+
+			if p.Breakdown == nil {
+				continue
+			}
+
+			r := findResourceByName(p.Breakdown.Resources, d.Name)
+			if r == nil {
+				continue
+			}
+
+			filename, line := resourceLocation(r.Metadata)
+			results = append(results, sarifResult{
+				RuleID:    sarifRuleCostIncrease,
+				Level:     "warning",
+				Message:   sarifMessage{Text: fmt.Sprintf("%s monthly cost increases by %s to %s", d.Name, formatCostChange2DP(out.Currency, d.MonthlyCost), FormatCost2DP(out.Currency, r.MonthlyCost))},
+				Locations: sarifLocations(p.Metadata, d.Name, filename, line),
+			})
+		}
+	}
+
+	for _, u := range out.UnsupportedResources {
+		results = append(results, sarifResult{
+			RuleID:    sarifRuleUnsupportedResource,
+			Level:     "note",
+			Message:   sarifMessage{Text: fmt.Sprintf("%s is not supported by Infracost, %s resources are not included in the estimate", u.ResourceName, u.ResourceType)},
+			Locations: sarifLocations(projects[u.ProjectName].Metadata, u.ResourceName, u.Filename, u.StartLine),
+		})
+	}
+
+	for _, m := range out.MissingUsage {
+		p := projects[m.ProjectName]
+
+		var filename string
+		var line int
+		if p.Breakdown != nil {
+			if r := findResourceByName(p.Breakdown.Resources, m.ResourceName); r != nil {
+				filename, line = resourceLocation(r.Metadata)
+			}
+		}
+
+		msg := fmt.Sprintf("%s costs depend on usage that isn't set: %s", m.ResourceName, strings.Join(m.CostComponents, ", "))
+		if len(m.UsageKeys) > 0 {
+			msg += fmt.Sprintf(". Set %s in the usage file", strings.Join(m.UsageKeys, ", "))
+		}
+
+		results = append(results, sarifResult{
+			RuleID:    sarifRuleMissingUsage,
+			Level:     "note",
+			Message:   sarifMessage{Text: msg},
+			Locations: sarifLocations(p.Metadata, m.ResourceName, filename, line),
+		})
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "Infracost",
+						InformationURI: "https://www.infracost.io",
+						Rules:          sarifRules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// resourceLocation returns the filename and line of the resource block from
+// its metadata. The line is a float64 if the metadata was loaded from JSON.
+func resourceLocation(metadata map[string]interface{}) (string, int) {
+	filename, _ := metadata["filename"].(string)
+
+	var line int
+	switch v := metadata["startLine"].(type) {
+	case int64:
+		line = int(v)
+	case int:
+		line = v
+	case float64:
+		line = int(v)
+	}
+
+	return filename, line
+}
+
+func sarifLocations(metadata *schema.ProjectMetadata, resourceName, filename string, line int) []sarifLocation {
+	loc := sarifLocation{
+		LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: resourceName, Kind: "resource"}},
+	}
+
+	if filename != "" {
+		loc.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: sarifURI(metadata, filename)},
+		}
+
+		if line > 0 {
+			loc.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+		}
+	}
+
+	return []sarifLocation{loc}
+}
+
+// sarifURI returns the path of filename relative to the root of the
+// repository, so code scanning can match it to the files of the commit. The
+// filename is relative to the directory that Infracost was run in, which is
+// the project path without its VCS sub path.
+func sarifURI(metadata *schema.ProjectMetadata, filename string) string {
+	if metadata != nil && metadata.Path != "" {
+		if rel, err := filepath.Rel(metadata.Path, filename); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(filepath.Join(metadata.VCSSubPath, rel))
+		}
+	}
+
+	return filepath.ToSlash(filename)
+}
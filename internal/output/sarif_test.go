@@ -0,0 +1,120 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestSetUnsupportedResources(t *testing.T) {
+	projects := []*schema.Project{
+		{
+			Name: "test",
+			Resources: []*schema.Resource{
+				{Name: "aws_instance.web", ResourceType: "aws_instance"},
+				{Name: "aws_foo.bar", ResourceType: "aws_foo", IsSkipped: true, Metadata: map[string]gjson.Result{
+					"filename":  gjson.Parse(`"main.tf"`),
+					"startLine": gjson.Parse(`12`),
+				}},
+				{Name: "aws_iam_role.free", ResourceType: "aws_iam_role", IsSkipped: true, NoPrice: true},
+				{Name: "random_id.skipped", ResourceType: "random_id", IsSkipped: true},
+			},
+		},
+	}
+
+	var r Root
+	SetUnsupportedResources(&r, projects)
+
+	assert.Equal(t, []UnsupportedResource{
+		{ProjectName: "test", ResourceName: "aws_foo.bar", ResourceType: "aws_foo", Filename: "main.tf", StartLine: 12},
+	}, r.UnsupportedResources)
+}
+
+func TestToSARIF(t *testing.T) {
+	r := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name:     "infracost/example/dev",
+				Metadata: &schema.ProjectMetadata{Path: "dev", VCSSubPath: "infra/dev"},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(150)), Metadata: map[string]interface{}{"filename": "dev/main.tf", "startLine": float64(8)}},
+						{Name: "aws_instance.app", MonthlyCost: decimalPtr(decimal.NewFromInt(20)), Metadata: map[string]interface{}{"filename": "dev/main.tf", "startLine": float64(20)}},
+						{Name: "aws_lambda_function.hello", Metadata: map[string]interface{}{"filename": "dev/lambda.tf", "startLine": float64(1)}},
+					},
+				},
+				Diff: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(100))},
+						{Name: "aws_instance.app", MonthlyCost: decimalPtr(decimal.NewFromInt(20))},
+					},
+				},
+			},
+		},
+		UnsupportedResources: []UnsupportedResource{
+			{ProjectName: "infracost/example/dev", ResourceName: "aws_foo.bar", ResourceType: "aws_foo", Filename: "dev/foo.tf", StartLine: 3},
+		},
+		MissingUsage: []MissingUsage{
+			{ProjectName: "infracost/example/dev", ResourceName: "aws_lambda_function.hello", CostComponents: []string{"Requests"}, UsageKeys: []string{"monthly_requests"}},
+		},
+	}
+
+	b, err := ToSARIF(r, Options{CostIncreaseThreshold: decimal.NewFromInt(50)})
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(b, &log))
+
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	assert.Len(t, log.Runs[0].Tool.Driver.Rules, 3)
+
+	results := log.Runs[0].Results
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "cost-increase", results[0].RuleID)
+	assert.Equal(t, "warning", results[0].Level)
+	assert.Equal(t, "aws_instance.web monthly cost increases by +$100.00 to $150.00", results[0].Message.Text)
+	assert.Equal(t, "infra/dev/main.tf", results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 8, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+
+	assert.Equal(t, "unsupported-resource", results[1].RuleID)
+	assert.Equal(t, "infra/dev/foo.tf", results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 3, results[1].Locations[0].PhysicalLocation.Region.StartLine)
+
+	assert.Equal(t, "missing-usage", results[2].RuleID)
+	assert.Equal(t, "aws_lambda_function.hello costs depend on usage that isn't set: Requests. Set monthly_requests in the usage file", results[2].Message.Text)
+	assert.Equal(t, "infra/dev/lambda.tf", results[2].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
+
+func TestToSARIFWithoutLocation(t *testing.T) {
+	r := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name:      "plan",
+				Metadata:  &schema.ProjectMetadata{Path: "plan.json"},
+				Breakdown: &Breakdown{Resources: []Resource{{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(10)), Metadata: map[string]interface{}{}}}},
+				Diff:      &Breakdown{Resources: []Resource{{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(10))}}},
+			},
+		},
+	}
+
+	b, err := ToSARIF(r, Options{})
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(b, &log))
+
+	results := log.Runs[0].Results
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].Locations[0].PhysicalLocation)
+	assert.Equal(t, "aws_instance.web", results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+}
@@ -0,0 +1,122 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// SharedCost is the part of another project's cost that is attributed to a
+// project because it consumes the other project's outputs, e.g. through a
+// terraform_remote_state data source.
+type SharedCost struct {
+	ProjectName string           `json:"projectName"`
+	Share       decimal.Decimal  `json:"share"`
+	MonthlyCost *decimal.Decimal `json:"monthlyCost"`
+}
+
+// withSharedCosts returns a copy of projects with the SharedCosts of each
+// project set. The cost of a project that is listed in the ConsumesProjects
+// metadata of other projects is split evenly between those projects. Projects
+// are referenced by their name or path.
+func withSharedCosts(projects Projects) Projects {
+	out := make(Projects, len(projects))
+	copy(out, projects)
+
+	// consumers maps the index of each consumed project to the indexes of the
+	// projects that consume it.
+	consumers := make(map[int][]int)
+	var consumed []int
+
+	for i, p := range out {
+		if p.Metadata == nil {
+			continue
+		}
+
+		for _, ref := range p.Metadata.ConsumesProjects {
+			found := false
+
+			for j, other := range out {
+				if i == j || (other.Name != ref && (other.Metadata == nil || other.Metadata.Path != ref)) {
+					continue
+				}
+
+				found = true
+				if containsInt(consumers[j], i) {
+					continue
+				}
+
+				if _, ok := consumers[j]; !ok {
+					consumed = append(consumed, j)
+				}
+				consumers[j] = append(consumers[j], i)
+			}
+
+			if !found {
+				log.Warnf("Project %s consumes project %s which is not in the output, its cost can't be shared", p.Name, ref)
+			}
+		}
+	}
+
+	for _, j := range consumed {
+		producer := out[j]
+		if producer.Breakdown == nil || producer.Breakdown.TotalMonthlyCost == nil {
+			continue
+		}
+
+		share := decimal.NewFromInt(1).Div(decimal.NewFromInt(int64(len(consumers[j]))))
+		cost := producer.Breakdown.TotalMonthlyCost.Mul(share)
+
+		for _, i := range consumers[j] {
+			out[i].SharedCosts = append(out[i].SharedCosts, SharedCost{
+				ProjectName: producer.Name,
+				Share:       share,
+				MonthlyCost: decimalPtr(cost),
+			})
+		}
+	}
+
+	return out
+}
+
+// sharedCostsMessage returns the monthly cost of each project that has shared
+// costs, including those shared costs.
+func (r *Root) sharedCostsMessage() string {
+	msg := ""
+
+	for _, p := range withSharedCosts(r.Projects) {
+		if len(p.SharedCosts) == 0 {
+			continue
+		}
+
+		total := decimal.Zero
+		if p.Breakdown != nil && p.Breakdown.TotalMonthlyCost != nil {
+			total = *p.Breakdown.TotalMonthlyCost
+		}
+
+		parts := []string{FormatCost2DP(r.Currency, decimalPtr(total))}
+		for _, shared := range p.SharedCosts {
+			total = total.Add(*shared.MonthlyCost)
+			parts = append(parts, fmt.Sprintf("%s shared from %s", FormatCost2DP(r.Currency, shared.MonthlyCost), shared.ProjectName))
+		}
+
+		msg += fmt.Sprintf("\n∙ %s: %s (%s)", p.Label(), FormatCost2DP(r.Currency, decimalPtr(total)), strings.Join(parts, " + "))
+	}
+
+	if msg == "" {
+		return ""
+	}
+
+	return "Monthly cost including shared costs:" + msg
+}
+
+func containsInt(arr []int, e int) bool {
+	for _, a := range arr {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestSharedCosts(t *testing.T) {
+	project := func(name string, cost int64, consumes ...string) *schema.Project {
+		return &schema.Project{
+			Name:     name,
+			Metadata: &schema.ProjectMetadata{Path: "infra/" + name, ConsumesProjects: consumes},
+			Resources: []*schema.Resource{
+				{Name: "aws_instance." + name, ResourceType: "aws_instance", MonthlyCost: decimalPtr(decimal.NewFromInt(cost))},
+			},
+		}
+	}
+
+	root, err := ToOutputFormat([]*schema.Project{
+		project("network", 100),
+		project("app", 50, "network", "infra/dns"),
+		project("web", 20, "network"),
+		project("dns", 10),
+	})
+	require.NoError(t, err)
+	root.Currency = "USD"
+
+	projects := withSharedCosts(root.Projects)
+	assert.Empty(t, projects[0].SharedCosts)
+	assert.Empty(t, root.Projects[1].SharedCosts, "the input projects should not be changed")
+
+	require.Len(t, projects[1].SharedCosts, 2)
+	assert.Equal(t, "network", projects[1].SharedCosts[0].ProjectName)
+	assert.True(t, decimal.NewFromFloat(0.5).Equal(projects[1].SharedCosts[0].Share))
+	assert.True(t, decimal.NewFromInt(50).Equal(*projects[1].SharedCosts[0].MonthlyCost))
+	assert.Equal(t, "dns", projects[1].SharedCosts[1].ProjectName)
+	assert.True(t, decimal.NewFromInt(10).Equal(*projects[1].SharedCosts[1].MonthlyCost))
+
+	expected := `Monthly cost including shared costs:
+∙ app: $110.00 ($50.00 + $50.00 shared from network + $10.00 shared from dns)
+∙ web: $70.00 ($20.00 + $50.00 shared from network)`
+	assert.Equal(t, expected, root.sharedCostsMessage())
+
+	b, err := ToJSON(root, Options{ShowSharedCosts: true})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"sharedCosts":[{"projectName":"network","share":"0.5","monthlyCost":"50"}`)
+
+	b, err = ToJSON(root, Options{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), `"sharedCosts"`)
+}
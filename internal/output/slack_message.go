@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
@@ -142,6 +143,21 @@ func ToSlackMessage(out Root, opts Options) ([]byte, error) {
 		))
 	}
 
+	if changed := topChangedResources(out, opts.TopResources); len(changed) > 0 {
+		lines := make([]string, 0, len(changed))
+		for _, c := range changed {
+			lines = append(lines, fmt.Sprintf("• %s %s", changedResourceLabel(out, c, "`%s`"), changedResourceCost(out.Currency, c)))
+		}
+
+		blocks = append(blocks, slack.NewSectionBlock(
+			&slack.TextBlockObject{
+				Type: slack.MarkdownType,
+				Text: fmt.Sprintf("*Top changed resources*\n%s", strings.Join(lines, "\n")),
+			},
+			[]*slack.TextBlockObject{}, nil,
+		))
+	}
+
 	diffMsg := fmt.Sprintf("*Infracost output*\n```%s```", ui.StripColor(string(diff)))
 	diffMsg = truncateMiddle(diffMsg, 3000, "\n\n...(truncated due to Slack message length)...\n\n")
 
@@ -0,0 +1,182 @@
+package output
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	sunburstRadius      = 190.0
+	sunburstInnerRadius = 40.0
+	// sunburstMaxAngle is just under a full circle, as an SVG arc that starts
+	// and ends at the same point isn't drawn.
+	sunburstMaxAngle = 2*math.Pi - 0.0001
+)
+
+// SunburstArc is a segment of the sunburst of monthly cost by module in the
+// HTML report. The first ring has the projects and the outer rings have the
+// modules, nested as they are in the Terraform addresses.
+type SunburstArc struct {
+	Label string
+	Cost  string
+	// D is the SVG path of the segment.
+	D    string
+	Fill string
+}
+
+type sunburstNode struct {
+	name     string
+	cost     decimal.Decimal
+	children []*sunburstNode
+	index    map[string]*sunburstNode
+}
+
+func newSunburstNode(name string) *sunburstNode {
+	return &sunburstNode{name: name, index: make(map[string]*sunburstNode)}
+}
+
+func (n *sunburstNode) child(name string) *sunburstNode {
+	if c, ok := n.index[name]; ok {
+		return c
+	}
+
+	c := newSunburstNode(name)
+	n.children = append(n.children, c)
+	n.index[name] = c
+
+	return c
+}
+
+func (n *sunburstNode) depth() int {
+	d := 0
+	for _, c := range n.children {
+		if cd := c.depth() + 1; cd > d {
+			d = cd
+		}
+	}
+
+	return d
+}
+
+// costSunburst returns the segments of the sunburst of the monthly cost of
+// the projects by module. Nothing is returned if there is no cost to show.
+func costSunburst(out Root) []SunburstArc {
+	root := newSunburstNode("")
+
+	for _, p := range out.Projects {
+		if p.Breakdown == nil {
+			continue
+		}
+
+		project := root.child(p.Label())
+		for _, r := range p.Breakdown.Resources {
+			if r.MonthlyCost == nil || !r.MonthlyCost.IsPositive() {
+				continue
+			}
+
+			root.cost = root.cost.Add(*r.MonthlyCost)
+			project.cost = project.cost.Add(*r.MonthlyCost)
+
+			n := project
+			for _, m := range moduleSegments(r.Name) {
+				n = n.child(m)
+				n.cost = n.cost.Add(*r.MonthlyCost)
+			}
+		}
+	}
+
+	if !root.cost.IsPositive() {
+		return nil
+	}
+
+	ringWidth := (sunburstRadius - sunburstInnerRadius) / float64(root.depth())
+	total, _ := root.cost.Float64()
+
+	var arcs []SunburstArc
+	var add func(n *sunburstNode, labels []string, depth int, start float64, hue int)
+	add = func(n *sunburstNode, labels []string, depth int, start float64, hue int) {
+		for i, c := range n.children {
+			if !c.cost.IsPositive() {
+				continue
+			}
+
+			cost, _ := c.cost.Float64()
+			span := cost / total * 2 * math.Pi
+
+			h := hue
+			if depth == 0 {
+				h = (i * 47) % 360
+			}
+
+			inner := sunburstInnerRadius + float64(depth)*ringWidth
+			path := append(append([]string{}, labels...), c.name)
+			d := c.cost
+
+			arcs = append(arcs, SunburstArc{
+				Label: strings.Join(path, " › "),
+				Cost:  FormatCost2DP(out.Currency, &d),
+				D:     arcPath(inner, inner+ringWidth, start, start+math.Min(span, sunburstMaxAngle)),
+				Fill:  fmt.Sprintf("hsl(%d, 60%%, %d%%)", h, 40+10*depth),
+			})
+
+			add(c, path, depth+1, start, h)
+			start += span
+		}
+	}
+	add(root, nil, 0, -math.Pi/2, 0)
+
+	return arcs
+}
+
+// arcPath returns the SVG path of the ring segment between radii r0 and r1
+// from angle a0 to a1, in radians clockwise from the x axis.
+func arcPath(r0, r1, a0, a1 float64) string {
+	largeArc := 0
+	if a1-a0 > math.Pi {
+		largeArc = 1
+	}
+
+	return fmt.Sprintf("M%s %s L%s %s A%s %s 0 %d 1 %s %s L%s %s A%s %s 0 %d 0 %s %s Z",
+		svgNum(r0*math.Cos(a0)), svgNum(r0*math.Sin(a0)),
+		svgNum(r1*math.Cos(a0)), svgNum(r1*math.Sin(a0)),
+		svgNum(r1), svgNum(r1), largeArc, svgNum(r1*math.Cos(a1)), svgNum(r1*math.Sin(a1)),
+		svgNum(r0*math.Cos(a1)), svgNum(r0*math.Sin(a1)),
+		svgNum(r0), svgNum(r0), largeArc, svgNum(r0*math.Cos(a0)), svgNum(r0*math.Sin(a0)),
+	)
+}
+
+func svgNum(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
+
+// moduleSegments returns the modules of a resource address, e.g.
+// module.a["x"].module.b.aws_instance.web returns module.a["x"] and module.b.
+func moduleSegments(address string) []string {
+	var parts []string
+	start, brackets := 0, 0
+	for i, ch := range address {
+		switch ch {
+		case '[':
+			brackets++
+		case ']':
+			brackets--
+		case '.':
+			if brackets == 0 {
+				parts = append(parts, address[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, address[start:])
+
+	var segments []string
+	for i := 0; i+2 < len(parts) && parts[i] == "module"; i += 2 {
+		segments = append(segments, "module."+parts[i+1])
+	}
+
+	return segments
+}
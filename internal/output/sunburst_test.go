@@ -0,0 +1,96 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestModuleSegments(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected []string
+	}{
+		{"aws_instance.web", nil},
+		{"aws_instance.web[0]", nil},
+		{"module.app.aws_instance.web", []string{"module.app"}},
+		{`module.app["eu.west"].module.db[1].aws_db_instance.main`, []string{`module.app["eu.west"]`, "module.db[1]"}},
+		{"module.app", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			assert.Equal(t, tt.expected, moduleSegments(tt.address))
+		})
+	}
+}
+
+func TestCostSunburst(t *testing.T) {
+	d := func(i int64) *decimal.Decimal { return decimalPtr(decimal.NewFromInt(i)) }
+
+	root := Root{
+		Currency: "USD",
+		Projects: []Project{
+			{
+				Name:     "app",
+				Metadata: &schema.ProjectMetadata{},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_instance.web", MonthlyCost: d(100)},
+						{Name: "module.db.aws_db_instance.main", MonthlyCost: d(200)},
+						{Name: "module.db.module.replica.aws_db_instance.main", MonthlyCost: d(100)},
+						{Name: "module.free.aws_s3_bucket.logs", MonthlyCost: d(0)},
+					},
+				},
+			},
+			{
+				Name:     "network",
+				Metadata: &schema.ProjectMetadata{},
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{Name: "aws_nat_gateway.main", MonthlyCost: d(400)},
+						{Name: "aws_eip.main"},
+					},
+				},
+			},
+		},
+	}
+
+	arcs := costSunburst(root)
+	require.Len(t, arcs, 4)
+
+	labels := make([]string, 0, len(arcs))
+	for _, a := range arcs {
+		labels = append(labels, a.Label+": "+a.Cost)
+	}
+
+	assert.Equal(t, []string{
+		"app: $400.00",
+		"app › module.db: $300.00",
+		"app › module.db › module.replica: $100.00",
+		"network: $400.00",
+	}, labels)
+
+	// Each project is half of the cost, so the projects are half rings that
+	// start at the top, in the first of the three rings.
+	assert.Equal(t, "M0.00 -40.00 L0.00 -90.00 A90.00 90.00 0 0 1 0.00 90.00 L0.00 40.00 A40.00 40.00 0 0 0 0.00 -40.00 Z", arcs[0].D)
+	assert.NotEqual(t, arcs[0].Fill, arcs[3].Fill)
+}
+
+func TestCostSunburstNoCost(t *testing.T) {
+	root := Root{
+		Projects: []Project{
+			{
+				Name:      "app",
+				Metadata:  &schema.ProjectMetadata{},
+				Breakdown: &Breakdown{Resources: []Resource{{Name: "aws_s3_bucket.logs"}}},
+			},
+		},
+	}
+
+	assert.Nil(t, costSunburst(root))
+}
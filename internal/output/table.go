@@ -7,6 +7,7 @@ import (
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
 
 	log "github.com/sirupsen/logrus"
@@ -71,7 +72,15 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 				s += "\n"
 			}
 		} else {
-			tableOut := tableForBreakdown(out.Currency, *project.Breakdown, opts.Fields, includeProjectTotals)
+			fields := opts.Fields
+			if len(opts.Granularities) > 0 {
+				fields = granularityFields(fields, opts.Granularities)
+			}
+			if opts.ShowEmissions {
+				fields = append(append([]string{}, fields...), emissionsField)
+			}
+
+			tableOut := tableForBreakdown(out.Currency, *project.Breakdown, fields, includeProjectTotals)
 
 			// Get the last table length so we can align the overall total with it
 			if i == len(out.Projects)-1 {
@@ -92,7 +101,10 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 		s += "\n"
 	}
 
-	totalOut := FormatCost2DP(out.Currency, out.TotalMonthlyCost)
+	totalOut := formatCostRange(out.Currency, out.TotalMonthlyCost, out.TotalLowMonthlyCost, out.TotalHighMonthlyCost)
+	if len(opts.Granularities) > 0 {
+		totalOut = granularityTotals(out, opts.Granularities)
+	}
 
 	overallTitle := formatTitleWithCurrency(" OVERALL TOTAL", out.Currency)
 	padding := 12
@@ -111,6 +123,74 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 		s += "\n──────────────────────────────────\n" + summaryMsg
 	}
 
+	if opts.ShowResourceSummary {
+		if resourceSummaryMsg := out.resourceSummaryMessage(); resourceSummaryMsg != "" {
+			s += "\n──────────────────────────────────\n" + resourceSummaryMsg
+		}
+	}
+
+	if opts.ShowSharedCosts {
+		if sharedCostsMsg := out.sharedCostsMessage(); sharedCostsMsg != "" {
+			s += "\n──────────────────────────────────\n" + sharedCostsMsg
+		}
+	}
+
+	if opts.GroupByScope {
+		if scopeCostsMsg := out.scopeCostsMessage(); scopeCostsMsg != "" {
+			s += "\n──────────────────────────────────\n" + scopeCostsMsg
+		}
+	}
+
+	if opts.GroupBy != "" {
+		if costGroupsMsg := out.costGroupsMessage(opts.GroupBy); costGroupsMsg != "" {
+			s += "\n──────────────────────────────────\n" + costGroupsMsg
+		}
+	}
+
+	if opts.ShowEmissions {
+		if emissionsMsg := out.emissionsMessage(); emissionsMsg != "" {
+			s += "\n──────────────────────────────────\n" + emissionsMsg
+		}
+	}
+
+	if recommendationsMsg := out.recommendationsMessage(schema.RecommendationTypeRightsizing); recommendationsMsg != "" {
+		s += "\n──────────────────────────────────\n" + recommendationsMsg
+	}
+
+	if opts.ShowQuickWins {
+		if quickWinsMsg := out.recommendationsMessage(schema.RecommendationTypeQuickWin); quickWinsMsg != "" {
+			s += "\n──────────────────────────────────\n" + quickWinsMsg
+		}
+	}
+
+	if projectionsMsg := out.ProjectionsMessage(); projectionsMsg != "" {
+		s += "\n──────────────────────────────────\n" + projectionsMsg
+	}
+
+	if budgetMsg := out.budgetMessage(); budgetMsg != "" {
+		s += "\n──────────────────────────────────\n" + budgetMsg
+	}
+
+	if anomaliesMsg := out.costAnomaliesMessage(); anomaliesMsg != "" {
+		s += "\n──────────────────────────────────\n" + anomaliesMsg
+	}
+
+	if policyMsg := opts.PolicyChecks.message(); policyMsg != "" {
+		s += "\n──────────────────────────────────\n" + policyMsg
+	}
+
+	if tagPolicyMsg := out.tagPolicyMessage(); tagPolicyMsg != "" {
+		s += "\n──────────────────────────────────\n" + tagPolicyMsg
+	}
+
+	if missingUsageMsg := out.missingUsageMessage(); missingUsageMsg != "" {
+		s += "\n──────────────────────────────────\n" + missingUsageMsg
+	}
+
+	if unknownInputsMsg := out.unknownInputsMessage(); unknownInputsMsg != "" {
+		s += "\n──────────────────────────────────\n" + unknownInputsMsg
+	}
+
 	return []byte(s), nil
 }
 
@@ -127,6 +207,9 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 	headers = append(headers,
 		ui.UnderlineString("Name"),
 	)
+	// columnFields are the fields of the columns after the name, so the
+	// total row can add the totals to the cost columns.
+	var columnFields []string
 
 	i := 1
 
@@ -139,6 +222,7 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 
 	if contains(fields, "price") {
 		headers = append(headers, ui.UnderlineString(formatTitleWithCurrency("Price", currency)))
+		columnFields = append(columnFields, "price")
 		columns = append(columns, table.ColumnConfig{
 			Number:      i,
 			Align:       text.AlignRight,
@@ -148,6 +232,7 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 	}
 	if contains(fields, "monthlyQuantity") {
 		headers = append(headers, ui.UnderlineString("Monthly Qty"))
+		columnFields = append(columnFields, "monthlyQuantity")
 		columns = append(columns, table.ColumnConfig{
 			Number:      i,
 			Align:       text.AlignRight,
@@ -157,6 +242,7 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 	}
 	if contains(fields, "unit") {
 		headers = append(headers, ui.UnderlineString("Unit"))
+		columnFields = append(columnFields, "unit")
 		columns = append(columns, table.ColumnConfig{
 			Number:      i,
 			Align:       text.AlignLeft,
@@ -164,8 +250,9 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 		})
 		i++
 	}
-	if contains(fields, "hourlyCost") {
-		headers = append(headers, ui.UnderlineString(formatTitleWithCurrency("Hourly Cost", currency)))
+	if contains(fields, emissionsField) {
+		headers = append(headers, ui.UnderlineString("Monthly kgCO2e"))
+		columnFields = append(columnFields, emissionsField)
 		columns = append(columns, table.ColumnConfig{
 			Number:      i,
 			Align:       text.AlignRight,
@@ -173,14 +260,17 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 		})
 		i++
 	}
-	if contains(fields, "monthlyCost") {
-		headers = append(headers, ui.UnderlineString(formatTitleWithCurrency("Monthly Cost", currency)))
-		columns = append(columns, table.ColumnConfig{
-			Number:      i,
-			Align:       text.AlignRight,
-			AlignHeader: text.AlignRight,
-		})
-		i++
+	for _, g := range costGranularities {
+		if contains(fields, g.field) {
+			headers = append(headers, ui.UnderlineString(formatTitleWithCurrency(g.title, currency)))
+			columnFields = append(columnFields, g.field)
+			columns = append(columns, table.ColumnConfig{
+				Number:      i,
+				Align:       text.AlignRight,
+				AlignHeader: text.AlignRight,
+			})
+			i++
+		}
 	}
 
 	t.AppendRow(table.Row{""})
@@ -199,7 +289,7 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 		t.AppendRow(table.Row{ui.BoldString(r.Name)})
 
 		buildCostComponentRows(t, currency, filteredComponents, "", len(r.SubResources) > 0, fields)
-		buildSubResourceRows(t, currency, filteredSubResources, "", fields)
+		buildSubResourceRows(t, currency, filteredSubResources, "", r.ResourceType(), fields)
 		buildActualCostRows(t, currency, r.ActualCosts, "", fields)
 
 		t.AppendRow(table.Row{""})
@@ -208,18 +298,32 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 	if includeTotal {
 		var totalCostRow table.Row
 		totalCostRow = append(totalCostRow, ui.BoldString(formatTitleWithCurrency("Project total", currency)))
-		numOfFields := i - 3
-		for q := 0; q < numOfFields; q++ {
-			totalCostRow = append(totalCostRow, "")
+		for _, f := range columnFields {
+			totalCostRow = append(totalCostRow, totalCostCell(currency, breakdown, f))
 		}
-		totalCostRow = append(totalCostRow, FormatCost2DP(currency, breakdown.TotalMonthlyCost))
 		t.AppendRow(totalCostRow)
 	}
 
 	return t.Render()
 }
 
-func buildSubResourceRows(t table.Writer, currency string, subresources []Resource, prefix string, fields []string) {
+// totalCostCell returns the cell of the project total row for the column of
+// the field, which is the total cost for the cost columns and empty for the
+// others.
+func totalCostCell(currency string, breakdown Breakdown, field string) string {
+	g, ok := granularityForField(field)
+	if !ok {
+		return ""
+	}
+
+	if g.name == "monthly" {
+		return formatCostRange(currency, breakdown.TotalMonthlyCost, breakdown.TotalLowMonthlyCost, breakdown.TotalHighMonthlyCost)
+	}
+
+	return FormatCost2DP(currency, g.cost(breakdown.TotalHourlyCost, breakdown.TotalMonthlyCost))
+}
+
+func buildSubResourceRows(t table.Writer, currency string, subresources []Resource, prefix string, parentType string, fields []string) {
 	for i, r := range subresources {
 		filteredComponents := filterZeroValComponents(r.CostComponents, r.Name)
 		filteredSubResources := filterZeroValResources(r.SubResources, r.Name)
@@ -234,10 +338,11 @@ func buildSubResourceRows(t table.Writer, currency string, subresources []Resour
 			nextPrefix = prefix + "   "
 		}
 
-		t.AppendRow(table.Row{fmt.Sprintf("%s %s", ui.FaintString(labelPrefix), r.Name)})
+		label, resourceType := subResourceLabel(r, parentType)
+		t.AppendRow(table.Row{fmt.Sprintf("%s %s", ui.FaintString(labelPrefix), label)})
 
 		buildCostComponentRows(t, currency, filteredComponents, nextPrefix, len(r.SubResources) > 0, fields)
-		buildSubResourceRows(t, currency, filteredSubResources, nextPrefix, fields)
+		buildSubResourceRows(t, currency, filteredSubResources, nextPrefix, resourceType, fields)
 		buildActualCostRows(t, currency, r.ActualCosts, nextPrefix, fields)
 	}
 }
@@ -250,6 +355,9 @@ func buildCostComponentRows(t table.Writer, currency string, costComponents []Co
 		}
 
 		label := fmt.Sprintf("%s %s", ui.FaintString(labelPrefix), c.Name)
+		if c.SharedWith != "" {
+			label += ui.FaintString(fmt.Sprintf(" (shared, cost included in %s)", c.SharedWith))
+		}
 
 		if c.MonthlyCost == nil {
 			price := fmt.Sprintf("Monthly cost depends on usage: %s per %s",
@@ -277,11 +385,19 @@ func buildCostComponentRows(t table.Writer, currency string, costComponents []Co
 			if contains(fields, "unit") {
 				tableRow = append(tableRow, c.Unit)
 			}
-			if contains(fields, "hourlyCost") {
-				tableRow = append(tableRow, FormatCost2DP(currency, c.HourlyCost))
+			if contains(fields, emissionsField) {
+				tableRow = append(tableRow, formatKgCO2e(c.MonthlyKgCO2e))
 			}
-			if contains(fields, "monthlyCost") {
-				tableRow = append(tableRow, FormatCost2DP(currency, c.MonthlyCost))
+			for _, g := range costGranularities {
+				if !contains(fields, g.field) {
+					continue
+				}
+
+				if g.name == "monthly" {
+					tableRow = append(tableRow, formatCostRange(currency, c.MonthlyCost, c.LowMonthlyCost, c.HighMonthlyCost))
+				} else {
+					tableRow = append(tableRow, FormatCost2DP(currency, g.cost(c.HourlyCost, c.MonthlyCost)))
+				}
 			}
 
 			t.AppendRow(tableRow)
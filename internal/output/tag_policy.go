@@ -0,0 +1,217 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxTagPolicyViolationsInTable is the number of violations listed in the
+// table output format, the rest are only in the JSON output.
+const maxTagPolicyViolationsInTable = 10
+
+// TagPolicy is the tags that resources must have so their costs can be
+// allocated, loaded from a tag policy file, e.g.
+//
+//	version: 0.1
+//	tags:
+//	  - key: team
+//	    allowed_values: [platform, data, web]
+//	  - key: cost-center
+//	    pattern: ^CC-[0-9]{4}$
+//	  - key: environment
+//	    required: false
+//	    allowed_values: [dev, staging, prod]
+//	    resource_types: [aws_*]
+type TagPolicy struct {
+	Version string    `yaml:"version"`
+	Tags    []TagRule `yaml:"tags"`
+}
+
+// TagRule is a tag that resources must have. Required defaults to true, if it's
+// false the tag is only checked if it's set. The value must be one of
+// AllowedValues and match Pattern if they're set. The rule applies to the
+// resources that match any of the ResourceTypes globs, or to all resources if
+// there are none.
+type TagRule struct {
+	Key           string   `yaml:"key"`
+	Required      *bool    `yaml:"required,omitempty"`
+	AllowedValues []string `yaml:"allowed_values,omitempty"`
+	Pattern       string   `yaml:"pattern,omitempty"`
+	ResourceTypes []string `yaml:"resource_types,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// TagPolicyViolation is a resource whose tags don't follow a rule of the tag
+// policy.
+type TagPolicyViolation struct {
+	ProjectName  string `json:"projectName"`
+	ResourceName string `json:"resourceName"`
+	Key          string `json:"key"`
+	Message      string `json:"message"`
+}
+
+// LoadTagPolicy reads and validates the tag policy file at path.
+func LoadTagPolicy(filePath string) (*TagPolicy, error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading tag policy file: %w", err)
+	}
+
+	var p TagPolicy
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("Error parsing tag policy file %s: %w", filePath, err)
+	}
+
+	if len(p.Tags) == 0 {
+		return nil, fmt.Errorf("Tag policy file %s has no tags", filePath)
+	}
+
+	for i := range p.Tags {
+		rule := &p.Tags[i]
+
+		if rule.Key == "" {
+			return nil, fmt.Errorf("Tag %d in tag policy file %s has no key", i+1, filePath)
+		}
+
+		if rule.Pattern != "" {
+			rule.pattern, err = regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid pattern for tag %s in tag policy file %s: %w", rule.Key, filePath, err)
+			}
+		}
+
+		for _, t := range rule.ResourceTypes {
+			if _, err := path.Match(t, ""); err != nil {
+				return nil, fmt.Errorf("Invalid resource type %s for tag %s in tag policy file %s: %w", t, rule.Key, filePath, err)
+			}
+		}
+	}
+
+	return &p, nil
+}
+
+// appliesTo returns true if the rule applies to resources of resourceType.
+func (t TagRule) appliesTo(resourceType string) bool {
+	if len(t.ResourceTypes) == 0 {
+		return true
+	}
+
+	for _, pattern := range t.ResourceTypes {
+		if ok, _ := path.Match(pattern, resourceType); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// violation returns why the tags don't follow the rule, or an empty string if
+// they do.
+func (t TagRule) violation(tags map[string]string) string {
+	v, ok := tags[t.Key]
+	if !ok {
+		if t.Required == nil || *t.Required {
+			return fmt.Sprintf("missing required tag %s", t.Key)
+		}
+
+		return ""
+	}
+
+	if len(t.AllowedValues) > 0 && !contains(t.AllowedValues, v) {
+		return fmt.Sprintf("tag %s has value %q, allowed values are %s", t.Key, v, strings.Join(t.AllowedValues, ", "))
+	}
+
+	if t.pattern != nil && !t.pattern.MatchString(v) {
+		return fmt.Sprintf("tag %s has value %q, which doesn't match %s", t.Key, v, t.Pattern)
+	}
+
+	return ""
+}
+
+// CheckTagPolicy returns an error if any resource of r doesn't follow the tag
+// policy, so runs can fail when resources can't be allocated to an owner. Only
+// the resources that Infracost estimates are checked, as tags aren't parsed
+// for free and unsupported resources. The violations are added to r so they're
+// included in the JSON, table and comment output formats.
+func CheckTagPolicy(r *Root, p *TagPolicy) error {
+	var violations []TagPolicyViolation
+
+	for _, proj := range r.Projects {
+		if proj.Breakdown == nil {
+			continue
+		}
+
+		for _, res := range proj.Breakdown.Resources {
+			for _, rule := range p.Tags {
+				if !rule.appliesTo(res.ResourceType()) {
+					continue
+				}
+
+				if msg := rule.violation(res.Tags); msg != "" {
+					violations = append(violations, TagPolicyViolation{
+						ProjectName:  proj.Name,
+						ResourceName: res.Name,
+						Key:          rule.Key,
+						Message:      msg,
+					})
+				}
+			}
+		}
+	}
+
+	r.TagPolicyViolations = violations
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return errors.New(r.tagPolicyMessage())
+}
+
+// tagPolicyMessage returns the number of resources that don't follow the tag
+// policy and the first of the violations.
+func (r *Root) tagPolicyMessage() string {
+	if len(r.TagPolicyViolations) == 0 {
+		return ""
+	}
+
+	resources := make(map[string]bool)
+	for _, v := range r.TagPolicyViolations {
+		resources[v.ProjectName+"\x00"+v.ResourceName] = true
+	}
+
+	noun := "resources don't"
+	if len(resources) == 1 {
+		noun = "resource doesn't"
+	}
+
+	msg := fmt.Sprintf("%d %s follow the tag policy:", len(resources), noun)
+
+	violations := make([]TagPolicyViolation, len(r.TagPolicyViolations))
+	copy(violations, r.TagPolicyViolations)
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].ResourceName < violations[j].ResourceName
+	})
+
+	for i, v := range violations {
+		if i == maxTagPolicyViolationsInTable {
+			msg += fmt.Sprintf("\n∙ and %d more, see the JSON output for all violations", len(violations)-i)
+			break
+		}
+
+		msg += fmt.Sprintf("\n∙ %s: %s", v.ResourceName, v.Message)
+	}
+
+	return msg
+}
@@ -0,0 +1,77 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTagPolicy(t *testing.T, content string) string {
+	t.Helper()
+
+	p := filepath.Join(t.TempDir(), "tag_policy.yml")
+	require.NoError(t, os.WriteFile(p, []byte(content), 0600))
+
+	return p
+}
+
+func TestLoadTagPolicyInvalid(t *testing.T) {
+	_, err := LoadTagPolicy(writeTagPolicy(t, "version: 0.1\ntags: []\n"))
+	assert.ErrorContains(t, err, "has no tags")
+
+	_, err = LoadTagPolicy(writeTagPolicy(t, "version: 0.1\ntags:\n  - allowed_values: [a]\n"))
+	assert.ErrorContains(t, err, "Tag 1 in tag policy file")
+
+	_, err = LoadTagPolicy(writeTagPolicy(t, "version: 0.1\ntags:\n  - key: team\n    pattern: '['\n"))
+	assert.ErrorContains(t, err, "Invalid pattern for tag team")
+
+	_, err = LoadTagPolicy(writeTagPolicy(t, "version: 0.1\ntags:\n  - key: team\n    values: [a]\n"))
+	assert.ErrorContains(t, err, "Error parsing tag policy file")
+}
+
+func TestCheckTagPolicy(t *testing.T) {
+	p, err := LoadTagPolicy(writeTagPolicy(t, `version: 0.1
+tags:
+  - key: team
+    allowed_values: [platform, data]
+  - key: cost-center
+    required: false
+    pattern: ^CC-[0-9]{4}$
+    resource_types: [aws_*]
+`))
+	require.NoError(t, err)
+
+	root := Root{
+		Projects: []Project{
+			{
+				Name: "infra",
+				Breakdown: &Breakdown{Resources: []Resource{
+					{Name: "aws_instance.web", Tags: map[string]string{"team": "platform", "cost-center": "CC-1234"}},
+					{Name: "aws_instance.db", Tags: map[string]string{"team": "web", "cost-center": "1234"}},
+					{Name: "google_compute_instance.worker", Tags: map[string]string{"cost-center": "1234"}},
+				}},
+			},
+		},
+	}
+
+	err = CheckTagPolicy(&root, p)
+	require.Error(t, err)
+
+	assert.Equal(t, []TagPolicyViolation{
+		{ProjectName: "infra", ResourceName: "aws_instance.db", Key: "team", Message: `tag team has value "web", allowed values are platform, data`},
+		{ProjectName: "infra", ResourceName: "aws_instance.db", Key: "cost-center", Message: `tag cost-center has value "1234", which doesn't match ^CC-[0-9]{4}$`},
+		{ProjectName: "infra", ResourceName: "google_compute_instance.worker", Key: "team", Message: "missing required tag team"},
+	}, root.TagPolicyViolations)
+
+	assert.Equal(t, `2 resources don't follow the tag policy:
+∙ aws_instance.db: tag team has value "web", allowed values are platform, data
+∙ aws_instance.db: tag cost-center has value "1234", which doesn't match ^CC-[0-9]{4}$
+∙ google_compute_instance.worker: missing required tag team`, err.Error())
+
+	root.Projects[0].Breakdown.Resources = root.Projects[0].Breakdown.Resources[:1]
+	assert.NoError(t, CheckTagPolicy(&root, p))
+	assert.Empty(t, root.TagPolicyViolations)
+}
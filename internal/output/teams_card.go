@@ -0,0 +1,229 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	teamsCardContentType = "application/vnd.microsoft.card.adaptive"
+	teamsCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	teamsCardVersion     = "1.4"
+
+	// DefaultTopResources is the number of resources with the largest cost
+	// changes that are listed in the slack-message and teams-card formats.
+	DefaultTopResources = 5
+)
+
+// changedResource is a resource whose monthly cost changes in the diff.
+type changedResource struct {
+	ProjectName string
+	Name        string
+	PastCost    *decimal.Decimal
+	Cost        *decimal.Decimal
+	DiffCost    *decimal.Decimal
+}
+
+// topChangedResources returns the n resources with the largest monthly cost
+// change, increases or decreases, across all the projects.
+func topChangedResources(out Root, n int) []changedResource {
+	if n <= 0 {
+		return nil
+	}
+
+	changed := changedResources(out)
+	if len(changed) > n {
+		changed = changed[:n]
+	}
+
+	return changed
+}
+
+// changedResources returns the resources whose monthly cost changes across all
+// the projects, sorted by the size of the change.
+func changedResources(out Root) []changedResource {
+	var changed []changedResource
+	for _, p := range out.Projects {
+		if p.Diff == nil {
+			continue
+		}
+
+		for _, d := range p.Diff.Resources {
+			if d.MonthlyCost == nil || d.MonthlyCost.IsZero() {
+				continue
+			}
+
+			c := changedResource{
+				ProjectName: p.Label(),
+				Name:        d.Name,
+				DiffCost:    d.MonthlyCost,
+			}
+
+			if p.PastBreakdown != nil {
+				if r := findResourceByName(p.PastBreakdown.Resources, d.Name); r != nil {
+					c.PastCost = r.MonthlyCost
+				}
+			}
+
+			if p.Breakdown != nil {
+				if r := findResourceByName(p.Breakdown.Resources, d.Name); r != nil {
+					c.Cost = r.MonthlyCost
+				}
+			}
+
+			changed = append(changed, c)
+		}
+	}
+
+	sort.SliceStable(changed, func(i, j int) bool {
+		return changed[i].DiffCost.Abs().GreaterThan(changed[j].DiffCost.Abs())
+	})
+
+	return changed
+}
+
+// changedResourceLabel returns the name of the resource, with its project if
+// there's more than one project. nameFormat is used to format the name, e.g.
+// as code.
+func changedResourceLabel(out Root, c changedResource, nameFormat string) string {
+	name := fmt.Sprintf(nameFormat, c.Name)
+	if len(out.Projects) > 1 {
+		return fmt.Sprintf("%s (%s)", name, truncateMiddle(c.ProjectName, 42, "..."))
+	}
+
+	return name
+}
+
+// changedResourceCost returns the cost change of the resource, with the past
+// and new costs when the resource exists before and after the change.
+func changedResourceCost(currency string, c changedResource) string {
+	pastCost, cost := c.PastCost, c.Cost
+	if pastCost == nil {
+		pastCost = decimalPtr(decimal.Zero)
+	}
+	if cost == nil {
+		cost = decimalPtr(decimal.Zero)
+	}
+
+	return fmt.Sprintf("%s%s", formatCostChange(currency, c.DiffCost), formatCostChangeDetails(currency, pastCost, cost))
+}
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsCard struct {
+	Schema  string             `json:"$schema"`
+	Type    string             `json:"type"`
+	Version string             `json:"version"`
+	Body    []teamsCardElement `json:"body"`
+	Actions []teamsCardAction  `json:"actions,omitempty"`
+	MSTeams map[string]string  `json:"msteams,omitempty"`
+}
+
+type teamsCardElement struct {
+	Type    string          `json:"type"`
+	Text    string          `json:"text,omitempty"`
+	Size    string          `json:"size,omitempty"`
+	Weight  string          `json:"weight,omitempty"`
+	Wrap    bool            `json:"wrap,omitempty"`
+	Spacing string          `json:"spacing,omitempty"`
+	Facts   []teamsCardFact `json:"facts,omitempty"`
+}
+
+type teamsCardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type teamsCardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// ToTeamsCard returns a compact summary of the cost changes as a Microsoft
+// Teams message with an Adaptive Card, which can be posted to a Teams incoming
+// webhook or workflow. The card lists the cost change of each project and the
+// opts.TopResources resources with the largest cost changes.
+func ToTeamsCard(out Root, opts Options) ([]byte, error) {
+	body := []teamsCardElement{
+		{
+			Type:   "TextBlock",
+			Text:   fmt.Sprintf("💰 Infracost estimate: **%s**", formatCostChangeSentence(out.Currency, out.PastTotalMonthlyCost, out.TotalMonthlyCost, true)),
+			Size:   "Medium",
+			Weight: "Bolder",
+			Wrap:   true,
+		},
+	}
+
+	var projectFacts []teamsCardFact
+	skippedProjectCount := 0
+	for _, project := range out.Projects {
+		if project.Diff == nil || len(project.Diff.Resources) == 0 {
+			skippedProjectCount++
+
+			if len(out.Projects) != 1 {
+				continue
+			}
+		}
+
+		summary := slackProjectSummaryBlock(project, out.Currency)
+		projectFacts = append(projectFacts, teamsCardFact{Title: summary[0].Text, Value: summary[1].Text})
+	}
+
+	if len(out.Projects) > 1 {
+		summary := slackAllProjectsSummaryBlock(out, out.Currency)
+		projectFacts = append(projectFacts, teamsCardFact{Title: summary[0].Text, Value: summary[1].Text})
+	}
+
+	body = append(body, teamsCardElement{Type: "FactSet", Facts: projectFacts, Spacing: "Medium"})
+
+	if len(out.Projects) > 1 {
+		if skippedProjectCount == 1 {
+			body = append(body, teamsCardElement{Type: "TextBlock", Text: "1 project has no cost estimate changes.", Wrap: true})
+		} else if skippedProjectCount > 0 {
+			body = append(body, teamsCardElement{Type: "TextBlock", Text: fmt.Sprintf("%d projects have no cost estimate changes.", skippedProjectCount), Wrap: true})
+		}
+	}
+
+	if changed := topChangedResources(out, opts.TopResources); len(changed) > 0 {
+		resourceFacts := make([]teamsCardFact, 0, len(changed))
+		for _, c := range changed {
+			resourceFacts = append(resourceFacts, teamsCardFact{Title: changedResourceLabel(out, c, "%s"), Value: changedResourceCost(out.Currency, c)})
+		}
+
+		body = append(body,
+			teamsCardElement{Type: "TextBlock", Text: "Top changed resources", Weight: "Bolder", Spacing: "Medium"},
+			teamsCardElement{Type: "FactSet", Facts: resourceFacts},
+		)
+	}
+
+	card := teamsCard{
+		Schema:  teamsCardSchema,
+		Type:    "AdaptiveCard",
+		Version: teamsCardVersion,
+		Body:    body,
+		MSTeams: map[string]string{"width": "Full"},
+	}
+
+	if out.ShareURL != "" {
+		card.Actions = []teamsCardAction{{Type: "Action.OpenUrl", Title: "View in Infracost Cloud", URL: out.ShareURL}}
+	}
+
+	return json.Marshal(teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{
+			{ContentType: teamsCardContentType, Content: card},
+		},
+	})
+}
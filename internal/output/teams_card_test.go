@@ -0,0 +1,78 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestTopChangedResources(t *testing.T) {
+	r := Root{
+		Projects: []Project{
+			{
+				Name:          "dev",
+				PastBreakdown: &Breakdown{Resources: []Resource{{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(10))}, {Name: "aws_db_instance.db", MonthlyCost: decimalPtr(decimal.NewFromInt(200))}}},
+				Breakdown:     &Breakdown{Resources: []Resource{{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(40))}, {Name: "aws_lambda_function.hello", MonthlyCost: decimalPtr(decimal.NewFromInt(5))}}},
+				Diff: &Breakdown{Resources: []Resource{
+					{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(30))},
+					{Name: "aws_db_instance.db", MonthlyCost: decimalPtr(decimal.NewFromInt(-200))},
+					{Name: "aws_lambda_function.hello", MonthlyCost: decimalPtr(decimal.NewFromInt(5))},
+					{Name: "aws_s3_bucket.logs", MonthlyCost: decimalPtr(decimal.Zero)},
+				}},
+			},
+		},
+	}
+
+	changed := topChangedResources(r, 2)
+	require.Len(t, changed, 2)
+	assert.Equal(t, "aws_db_instance.db", changed[0].Name)
+	assert.Equal(t, "-$200 ($200 → $0.00)", changedResourceCost("USD", changed[0]))
+	assert.Equal(t, "aws_instance.web", changed[1].Name)
+	assert.Equal(t, "+$30 ($10 → $40)", changedResourceCost("USD", changed[1]))
+
+	assert.Len(t, topChangedResources(r, 10), 3)
+	assert.Empty(t, topChangedResources(r, 0))
+}
+
+func TestToTeamsCard(t *testing.T) {
+	r := Root{
+		Currency:             "USD",
+		TotalMonthlyCost:     decimalPtr(decimal.NewFromInt(40)),
+		PastTotalMonthlyCost: decimalPtr(decimal.NewFromInt(10)),
+		DiffTotalMonthlyCost: decimalPtr(decimal.NewFromInt(30)),
+		ShareURL:             "https://dashboard.infracost.io/share/abc",
+		Projects: []Project{
+			{
+				Name:          "infracost/example/dev",
+				PastBreakdown: &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(10)), Resources: []Resource{{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(10))}}},
+				Breakdown:     &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(40)), Resources: []Resource{{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(40))}}},
+				Diff:          &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(30)), Resources: []Resource{{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(30))}}},
+			},
+			{
+				Name:          "infracost/example/prod",
+				PastBreakdown: &Breakdown{TotalMonthlyCost: decimalPtr(decimal.Zero)},
+				Breakdown:     &Breakdown{TotalMonthlyCost: decimalPtr(decimal.Zero)},
+				Diff:          &Breakdown{TotalMonthlyCost: decimalPtr(decimal.Zero)},
+			},
+		},
+	}
+
+	b, err := ToTeamsCard(r, Options{TopResources: DefaultTopResources})
+	require.NoError(t, err)
+
+	msg := gjson.ParseBytes(b)
+	assert.Equal(t, "message", msg.Get("type").String())
+	assert.Equal(t, teamsCardContentType, msg.Get("attachments.0.contentType").String())
+
+	card := msg.Get("attachments.0.content")
+	assert.Equal(t, "AdaptiveCard", card.Get("type").String())
+	assert.Equal(t, "💰 Infracost estimate: **monthly cost will increase by $30 (+300%) 📈**", card.Get("body.0.text").String())
+	assert.Equal(t, `[{"title":"infracost/example/dev","value":"+$30 ($10 → $40)"},{"title":"All projects","value":"+$30 ($10 → $40)"}]`, card.Get("body.1.facts").Raw)
+	assert.Equal(t, "1 project has no cost estimate changes.", card.Get("body.2.text").String())
+	assert.Equal(t, "Top changed resources", card.Get("body.3.text").String())
+	assert.Equal(t, `[{"title":"aws_instance.web (infracost/example/dev)","value":"+$30 ($10 → $40)"}]`, card.Get("body.4.facts").Raw)
+	assert.Equal(t, r.ShareURL, card.Get("actions.0.url").String())
+}
@@ -0,0 +1,156 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// ToTemplate renders Root out using the user provided Go template at
+// opts.TemplatePath. The template is executed with the same data model as the
+// JSON output so users can build their own report layouts.
+func ToTemplate(out Root, opts Options) ([]byte, error) {
+	if opts.TemplatePath == "" {
+		return nil, errors.New("template path is required for the template format")
+	}
+
+	content, err := os.ReadFile(opts.TemplatePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read template file")
+	}
+
+	tmpl := template.New(filepath.Base(opts.TemplatePath))
+	tmpl.Funcs(sprig.TxtFuncMap())
+	tmpl.Funcs(templateFuncs(out.Currency))
+
+	tmpl, err = tmpl.Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse template %s: %w", opts.TemplatePath, err)
+	}
+
+	var buf bytes.Buffer
+	bufw := bufio.NewWriter(&buf)
+
+	err = tmpl.Execute(bufw, out)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to execute template %s: %w", opts.TemplatePath, err)
+	}
+
+	bufw.Flush()
+
+	return buf.Bytes(), nil
+}
+
+// templateFuncs returns the helper functions of the templates that users
+// provide with --template-path, in addition to the sprig functions:
+//
+//   - formatCost, formatCost2DP and formatPrice format costs and prices in the
+//     currency of the output, e.g. {{ formatCost .TotalMonthlyCost }}.
+//   - formatCostChange formats the change between two costs, e.g.
+//     {{ formatCostChange .PastTotalMonthlyCost .TotalMonthlyCost }}.
+//   - formatQuantity formats a quantity and stripColor removes colors.
+//   - diffArrow returns ↑ for cost increases and ↓ for decreases, e.g.
+//     {{ diffArrow .DiffTotalMonthlyCost }}.
+//   - projectsAbove and resourcesAbove filter the projects or resources to
+//     those whose monthly cost changes by at least the threshold, up or down,
+//     e.g. {{ range resourcesAbove 100 .Diff.Resources }}.
+func templateFuncs(currency string) template.FuncMap {
+	return template.FuncMap{
+		"formatCost": func(d *decimal.Decimal) string {
+			if d == nil || d.IsZero() {
+				return formatWholeDecimalCurrency(currency, decimal.Zero)
+			}
+			return formatCost(currency, d)
+		},
+		"formatCost2DP": func(d *decimal.Decimal) string {
+			return FormatCost2DP(currency, d)
+		},
+		"formatCostChange": func(pastCost, cost *decimal.Decimal) string {
+			return formatMarkdownCostChange(currency, pastCost, cost, false)
+		},
+		"formatPrice": func(d decimal.Decimal) string {
+			return formatPrice(currency, d)
+		},
+		"formatQuantity": formatQuantity,
+		"stripColor":     ui.StripColor,
+		"diffArrow":      diffArrow,
+		"projectsAbove": func(threshold interface{}, projects Projects) (Projects, error) {
+			t, err := templateDecimal(threshold)
+			if err != nil {
+				return nil, err
+			}
+
+			var filtered Projects
+			for _, p := range projects {
+				if p.Diff != nil && p.Diff.TotalMonthlyCost != nil && p.Diff.TotalMonthlyCost.Abs().GreaterThanOrEqual(t) {
+					filtered = append(filtered, p)
+				}
+			}
+
+			return filtered, nil
+		},
+		"resourcesAbove": func(threshold interface{}, resources []Resource) ([]Resource, error) {
+			t, err := templateDecimal(threshold)
+			if err != nil {
+				return nil, err
+			}
+
+			var filtered []Resource
+			for _, r := range resources {
+				if r.MonthlyCost != nil && r.MonthlyCost.Abs().GreaterThanOrEqual(t) {
+					filtered = append(filtered, r)
+				}
+			}
+
+			return filtered, nil
+		},
+	}
+}
+
+// diffArrow returns an arrow for the direction of a cost change, or an empty
+// string if the cost didn't change.
+func diffArrow(d *decimal.Decimal) string {
+	if d == nil || d.IsZero() {
+		return ""
+	}
+
+	if d.IsPositive() {
+		return "↑"
+	}
+
+	return "↓"
+}
+
+// templateDecimal converts a number passed to a template function to a
+// decimal. Templates pass numbers as ints or floats depending on how they're
+// written.
+func templateDecimal(v interface{}) (decimal.Decimal, error) {
+	switch t := v.(type) {
+	case int:
+		return decimal.NewFromInt(int64(t)), nil
+	case int64:
+		return decimal.NewFromInt(t), nil
+	case float64:
+		return decimal.NewFromFloat(t), nil
+	case string:
+		return decimal.NewFromString(t)
+	case decimal.Decimal:
+		return t, nil
+	case *decimal.Decimal:
+		if t == nil {
+			return decimal.Zero, nil
+		}
+		return *t, nil
+	}
+
+	return decimal.Zero, fmt.Errorf("invalid threshold %v, it must be a number", v)
+}
@@ -0,0 +1,66 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestDiffArrow(t *testing.T) {
+	assert.Equal(t, "", diffArrow(nil))
+	assert.Equal(t, "", diffArrow(decimalPtr(decimal.Zero)))
+	assert.Equal(t, "↑", diffArrow(decimalPtr(decimal.NewFromInt(10))))
+	assert.Equal(t, "↓", diffArrow(decimalPtr(decimal.NewFromInt(-10))))
+}
+
+func TestToMarkdownWithTemplatePath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "comment.tmpl")
+	err := os.WriteFile(path, []byte(`{{- range projectsAbove 50 .Root.Projects }}{{ .Name }} {{ diffArrow .Diff.TotalMonthlyCost }}
+{{- range resourcesAbove "20.5" .Diff.Resources }}
+- {{ .Name }} {{ formatCost2DP .MonthlyCost }}
+{{- end }}
+{{ end -}}`), 0600)
+	require.NoError(t, err)
+
+	r := Root{
+		Currency: "EUR",
+		Projects: []Project{
+			{
+				Name:          "increase",
+				Metadata:      &schema.ProjectMetadata{},
+				PastBreakdown: &Breakdown{},
+				Breakdown:     &Breakdown{},
+				Diff: &Breakdown{
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(80)),
+					Resources: []Resource{
+						{Name: "aws_instance.web", MonthlyCost: decimalPtr(decimal.NewFromInt(100))},
+						{Name: "aws_instance.old", MonthlyCost: decimalPtr(decimal.NewFromInt(-20))},
+					},
+				},
+			},
+			{
+				Name:          "small",
+				Metadata:      &schema.ProjectMetadata{},
+				PastBreakdown: &Breakdown{},
+				Breakdown:     &Breakdown{},
+				Diff:          &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(-10))},
+			},
+		},
+	}
+
+	b, err := ToMarkdown(r, Options{TemplatePath: path}, MarkdownOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "increase ↑\n- aws_instance.web €100.00\n", string(b))
+
+	err = os.WriteFile(path, []byte(`{{ range projectsAbove "lots" .Root.Projects }}{{ end }}`), 0600)
+	require.NoError(t, err)
+
+	_, err = ToMarkdown(r, Options{TemplatePath: path}, MarkdownOptions{})
+	assert.Error(t, err)
+}
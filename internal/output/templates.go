@@ -114,6 +114,131 @@ table.overall-total {
   margin-top: 1rem;
 }
 
+details.project {
+  margin-bottom: 1.5rem;
+}
+
+details.project > summary, details.sunburst > summary {
+  cursor: pointer;
+  font-weight: bold;
+  margin-bottom: 0.5rem;
+}
+
+details.project > summary .project-total {
+  color: #6b7280;
+  margin-left: 0.5rem;
+}
+
+th[data-sort] {
+  cursor: pointer;
+  user-select: none;
+}
+
+th[data-order="asc"]::after {
+  content: " \25B2";
+}
+
+th[data-order="desc"]::after {
+  content: " \25BC";
+}
+
+tr.resource.top-level {
+  cursor: pointer;
+}
+
+tr.resource.top-level td.name::before {
+  content: "\25BE ";
+}
+
+tbody.collapsed tr.resource.top-level td.name::before {
+  content: "\25B8 ";
+}
+
+tbody.collapsed tr:not(.top-level) {
+  display: none;
+}
+
+tbody.added tr.resource.top-level {
+  background-color: #15803d;
+}
+
+tbody.removed tr.resource.top-level {
+  background-color: #b91c1c;
+}
+
+tbody.removed tr.resource.top-level td.name {
+  text-decoration: line-through;
+}
+
+tbody.changed tr.resource.top-level {
+  background-color: #b45309;
+}
+
+td.diff {
+  text-align: right;
+  white-space: nowrap;
+}
+
+.cost-increase {
+  color: #b91c1c;
+}
+
+.cost-decrease {
+  color: #15803d;
+}
+
+tr.top-level .cost-increase, tr.top-level .cost-decrease {
+  color: #ffffff;
+  font-weight: bold;
+}
+
+details.sunburst {
+  margin-bottom: 1.5rem;
+}
+
+details.sunburst path {
+  stroke: #ffffff;
+  stroke-width: 1;
+}
+
+details.sunburst path:hover {
+  opacity: 0.8;
+}
+
+{{end}}
+
+{{define "script"}}
+document.querySelectorAll("tr.resource.top-level").forEach(function (row) {
+  row.addEventListener("click", function () {
+    row.parentElement.classList.toggle("collapsed");
+  });
+});
+
+document.querySelectorAll("table.breakdown th[data-sort]").forEach(function (th) {
+  th.addEventListener("click", function () {
+    var table = th.closest("table");
+    var key = th.dataset.sort;
+    var asc = th.dataset.order !== "asc";
+
+    table.querySelectorAll("th[data-sort]").forEach(function (h) {
+      delete h.dataset.order;
+    });
+    th.dataset.order = asc ? "asc" : "desc";
+
+    var groups = Array.prototype.slice.call(table.querySelectorAll("tbody.resource-group"));
+    groups.sort(function (a, b) {
+      var x = a.dataset[key];
+      var y = b.dataset[key];
+      var c = key === "name" ? x.localeCompare(y) : parseFloat(x) - parseFloat(y);
+      return asc ? c : -c;
+    });
+
+    var foot = table.querySelector("tfoot");
+    groups.forEach(function (g) {
+      table.insertBefore(g, foot);
+    });
+  });
+});
 {{end}}
 
 {{define "faviconBase64"}}
@@ -138,8 +263,15 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
   {{end}}
 {{end}}
 
+{{define "diffCell"}}
+  {{if .HasDiff}}
+    <td class="diff">{{if .Diff}}<span class="{{.Diff | costChangeClass}}">{{.Diff | formatCostChange}}</span>{{end}}</td>
+  {{end}}
+{{end}}
+
 {{define "resourceRows"}}
   {{$fields := .Fields}}
+  {{$hasDiff := .HasDiff}}
   {{- $filteredCostComponents := filterZeroValComponents .Resource.CostComponents .Resource.Name}}
   {{- $filteredSubResources := filterZeroValResources .Resource.SubResources .Resource.Name}}
   {{- if hasCost $filteredCostComponents $filteredSubResources .Resource.Name }}
@@ -150,6 +282,7 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
       {{.Resource.Name}}
     </td>
     {{template "emptyTableRows" dict "Fields" $fields}}
+    {{template "diffCell" dict "HasDiff" $hasDiff "Diff" .Diff.MonthlyCost}}
   </tr>
   {{ if .Resource.Tags}}
     <tr class="tags">
@@ -163,14 +296,15 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
         <span>{{$tags | join ", "}}</span>
       </td>
       {{template "emptyTableRows" dict "Fields" $fields}}
+      {{template "diffCell" dict "HasDiff" $hasDiff}}
     </tr>
   {{end}}
   {{$ident := add .Indent 1}}
   {{range $filteredCostComponents}}
-    {{template "costComponentRow" dict "CostComponent" . "Fields" $fields "Indent" $ident}}
+    {{template "costComponentRow" dict "CostComponent" . "Fields" $fields "Indent" $ident "HasDiff" $hasDiff}}
   {{end}}
   {{range $filteredSubResources}}
-    {{template "resourceRows" dict "Resource" . "Fields" $fields "Indent" $ident}}
+    {{template "resourceRows" dict "Resource" . "Fields" $fields "Indent" $ident "HasDiff" $hasDiff "Diff" (dict)}}
   {{end}}
   {{- end}}
 {{end}}
@@ -196,16 +330,17 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
         <td class="hourly-cost">{{.CostComponent.HourlyCost | formatCost2DP}}</td>
       {{end}}
       {{if contains .Fields "monthlyCost"}}
-        <td class="monthly-cost">{{.CostComponent.MonthlyCost | formatCost2DP}}</td>
+        <td class="monthly-cost">{{formatCostRange .CostComponent.MonthlyCost .CostComponent.LowMonthlyCost .CostComponent.HighMonthlyCost}}</td>
       {{end}}
     {{else}}
       <td colspan="{{len .Fields}}" class="usage-cost">Cost depends on usage: {{.CostComponent.Price | formatPrice}} per {{.CostComponent.Unit}}</td>
     {{end}}
+    {{template "diffCell" dict "HasDiff" .HasDiff}}
   </tr>
 {{end}}
 
 {{define "tableHeaders"}}
-  <th class="name">Name</th>
+  <th class="name" data-sort="name">Name</th>
   {{if contains .Fields "monthlyQuantity"}}
     <td class="monthly-quantity">Monthly Qty</td>
   {{end}}
@@ -219,33 +354,57 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
     <td class="hourly-cost">{{ "Hourly Cost" | formatTitleWithCurrency }}</td>
   {{end}}
   {{if contains .Fields "monthlyCost"}}
-    <td class="monthly-cost">{{ "Monthly Cost" | formatTitleWithCurrency }}</td>
+    <th class="monthly-cost" data-sort="monthlyCost">{{ "Monthly Cost" | formatTitleWithCurrency }}</th>
   {{end}}
+  {{if .HasDiff}}
+    <th class="diff" data-sort="diff">{{ "Monthly Diff" | formatTitleWithCurrency }}</th>
+  {{end}}
+{{end}}
+
+{{define "resourceGroup"}}
+  <tbody class="resource-group{{if .Diff.Status}} {{.Diff.Status}}{{end}}" data-name="{{.Resource.Name}}" data-monthly-cost="{{.Resource.MonthlyCost | sortValue}}" data-diff="{{.Diff.MonthlyCost | sortValue}}">
+    {{template "resourceRows" dict "Resource" .Resource "Fields" .Fields "Indent" 0 "HasDiff" .HasDiff "Diff" .Diff}}
+  </tbody>
 {{end}}
 
 {{define "projectBlock"}}
   {{$fields := .Options.Fields}}
-  <p class="project-name">Project: {{.Project | projectLabel}}</p>
-  {{- if .Project | projectModulePath }}
-  <p class="project-name">Module path: {{.Project | projectModulePath}}</p>
-  {{- end }}
-  {{- if .Project | projectWorkspace }}
-  <p class="project-name">Workspace: {{.Project | projectWorkspace}}</p>
-  {{- end }}
-  <table class="breakdown">
-    <thead>
-      {{template "tableHeaders" dict "Fields" $fields}}
-    </thead>
-    <tbody>
+  {{$hasDiff := projectHasDiff .Project}}
+  {{$diffs := resourceDiffs .Project}}
+  <details class="project" open>
+    <summary>
+      <span class="project-name">Project: {{.Project | projectLabel}}</span>
+      <span class="project-total">{{.Project.Breakdown.TotalMonthlyCost | formatCost2DP}}</span>
+    </summary>
+    {{- if .Project | projectModulePath }}
+    <p class="project-name">Module path: {{.Project | projectModulePath}}</p>
+    {{- end }}
+    {{- if .Project | projectWorkspace }}
+    <p class="project-name">Workspace: {{.Project | projectWorkspace}}</p>
+    {{- end }}
+    <table class="breakdown">
+      <thead>
+        <tr>
+          {{template "tableHeaders" dict "Fields" $fields "HasDiff" $hasDiff}}
+        </tr>
+      </thead>
       {{range .Resources}}
-        {{template "resourceRows" dict "Resource" . "Fields" $fields "Indent" 0}}
+        {{template "resourceGroup" dict "Resource" . "Fields" $fields "HasDiff" $hasDiff "Diff" (index $diffs .Name)}}
+      {{end}}
+      {{range removedResources .Project}}
+        {{template "resourceGroup" dict "Resource" . "Fields" $fields "HasDiff" $hasDiff "Diff" (index $diffs .Name)}}
       {{end}}
-      <tr class="total">
-        <td class="name" colspan="{{len .Options.Fields}}">Project total</td>
-        <td class="monthly-cost">{{.Project.Breakdown.TotalMonthlyCost | formatCost2DP}}</td>
-      </tr>
-    </tbody>
-  </table>
+      <tfoot>
+        <tr class="total">
+          <td class="name" colspan="{{len .Options.Fields}}">Project total</td>
+          <td class="monthly-cost">{{formatCostRange .Project.Breakdown.TotalMonthlyCost .Project.Breakdown.TotalLowMonthlyCost .Project.Breakdown.TotalHighMonthlyCost}}</td>
+          {{if $hasDiff}}
+            <td class="diff"><span class="{{.Project.Diff.TotalMonthlyCost | costChangeClass}}">{{.Project.Diff.TotalMonthlyCost | formatCostChange}}</span></td>
+          {{end}}
+        </tr>
+      </tfoot>
+    </table>
+  </details>
 {{end}}
 
 <!doctype html>
@@ -274,6 +433,18 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
 
     {{$options := .Options}}
 
+    {{- $arcs := costSunburst .Root}}
+    {{- if $arcs}}
+    <details class="sunburst" open>
+      <summary>{{ "Monthly cost by module" | formatTitleWithCurrency }}</summary>
+      <svg viewBox="-200 -200 400 400" width="400" height="400" role="img" aria-label="Monthly cost by module">
+        {{- range $arcs}}
+        <path d="{{.D}}" fill="{{.Fill}}"><title>{{.Label}}: {{.Cost}}</title></path>
+        {{- end}}
+      </svg>
+    </details>
+    {{- end}}
+
     {{range .Root.Projects}}
       {{$resources := .Breakdown.Resources}}
       {{template "projectBlock" dict "Project" . "Options" $options "Resources" $resources "Indent" 0}}
@@ -283,14 +454,24 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
       <tbody>
         <tr class="total">
           <td class="name" colspan="{{len .Options.Fields}}">{{ "Overall total" | formatTitleWithCurrency }}</td>
-          <td class="monthly-cost">{{.Root.TotalMonthlyCost | formatCost2DP}}</td>
+          <td class="monthly-cost">{{formatCostRange .Root.TotalMonthlyCost .Root.TotalLowMonthlyCost .Root.TotalHighMonthlyCost}}</td>
         </tr>
+        {{- if and rootHasDiff .Root.DiffTotalMonthlyCost}}
+        <tr class="total">
+          <td class="name" colspan="{{len .Options.Fields}}">{{ "Overall diff" | formatTitleWithCurrency }}</td>
+          <td class="monthly-cost"><span class="{{.Root.DiffTotalMonthlyCost | costChangeClass}}">{{.Root.DiffTotalMonthlyCost | formatCostChange}}</span></td>
+        </tr>
+        {{- end}}
       </tbody>
     </table>
 
     <div class="warnings">
       <p>{{.SummaryMessage | stripColor | replaceNewLines}}</p>
     </div>
+
+    <script>
+      {{template "script"}}
+    </script>
   </body>
 </html>`
 
@@ -303,7 +484,7 @@ var CommentMarkdownWithHTMLTemplate = `
   {{- end }}
       <td align="right">{{ formatCost .PastCost }}</td>
       <td align="right">{{ formatCost .Cost }}</td>
-      <td>{{ formatCostChange .PastCost .Cost }}</td>
+      <td>{{ severityIcon (costChangeSeverity .PastCost .Cost) }}{{ formatCostChange .PastCost .Cost }}</td>
     </tr>
 {{- end}}
 💰 Infracost estimate: **{{ formatCostChangeSentence .Root.Currency .Root.PastTotalMonthlyCost .Root.TotalMonthlyCost true }}**
@@ -341,6 +522,17 @@ var CommentMarkdownWithHTMLTemplate = `
 {{- end }}
 {{- end }}
 
+{{- $increases := largeCostIncreases }}
+{{- if gt (len $increases) 0 }}
+
+<details>
+<summary><strong>Large cost increases</strong></summary>
+{{ range $increases }}
+> - {{ severityIcon .Severity }}<code>{{ .Name }}</code> {{ .CostChange }}
+{{- end }}
+</details>
+{{- end }}
+
 {{- if not .MarkdownOptions.OmitDetails }}
 
 <details>
@@ -368,6 +560,52 @@ var CommentMarkdownWithHTMLTemplate = `
 		</details>
 	{{- end }}
 {{- end }}
+{{- if gt (len .Options.PolicyChecks.Warnings) 0 }}
+		<details>
+			<summary><strong>⚠️ Policy warnings</strong></summary>
+				{{ range $v, $f := .Options.PolicyChecks.Warnings}}
+> - {{ $f }}
+				{{- end}}
+		</details>
+{{- end }}
+{{- if gt (len .Root.TagPolicyViolations) 0 }}
+		<details>
+			<summary><strong>❌ Tag policy failed</strong></summary>
+				{{ range $v, $f := .Root.TagPolicyViolations}}
+> - {{ $f.ResourceName }}: {{ $f.Message }}
+				{{- end}}
+		</details>
+{{- end }}
+{{- if gt (len (.Root.RecommendationMessages "rightsizing")) 0 }}
+		<details>
+			<summary><strong>💡 Rightsizing recommendations</strong></summary>
+				{{ range $v, $f := .Root.RecommendationMessages "rightsizing"}}
+> - {{ $f }}
+				{{- end}}
+		</details>
+{{- end }}
+{{- if .Root.CostRangeMessage }}
+		<details>
+			<summary><strong>📏 Cost range</strong></summary>
+
+> {{ .Root.CostRangeMessage }}
+		</details>
+{{- end }}
+{{- if .Root.ProjectionsMessage }}
+		<details>
+			<summary><strong>📈 Cost projections</strong></summary>
+
+> {{ .Root.ProjectionsMessage }}
+		</details>
+{{- end }}
+{{- if and .Options.ShowQuickWins (gt (len (.Root.RecommendationMessages "quick_win")) 0) }}
+		<details>
+			<summary><strong>💡 Quick wins</strong></summary>
+				{{ range $v, $f := .Root.RecommendationMessages "quick_win"}}
+> - {{ $f }}
+				{{- end}}
+		</details>
+{{- end }}
 {{- if .Options.GuardrailCheck.Comment }}
 	{{- if gt (len .Options.GuardrailCheck.CommentableFailures) 0 }}
 		<details>
@@ -399,10 +637,10 @@ This comment will be replaced when the cost estimate changes
 
 var CommentMarkdownTemplate = `
 {{- define "summaryRow"}}
-| {{ truncateMiddle .Name 64 "..." }}{{- range .MetadataFields }} | {{ . }} {{- end }} | {{ formatCost .PastCost }} | {{ formatCost .Cost }} | {{ formatCostChange .PastCost .Cost }} |
+| {{ truncateMiddle .Name 64 "..." }}{{- range .MetadataFields }} | {{ . }} {{- end }} | {{ formatCost .PastCost }} | {{ formatCost .Cost }} | {{ severityIcon (costChangeSeverity .PastCost .Cost) }}{{ formatCostChange .PastCost .Cost }} |
 {{- end }}
 {{- define "totalRow"}}
-| **{{ truncateMiddle .Name 64 "..." }}**{{- range metadataHeaders }} | {{- end }} | **{{ formatCost .PastCost }}** | **{{ formatCost .Cost }}** | **{{ formatCostChange .PastCost .Cost }}** |
+| **{{ truncateMiddle .Name 64 "..." }}**{{- range metadataHeaders }} | {{- end }} | **{{ formatCost .PastCost }}** | **{{ formatCost .Cost }}** | {{ severityIcon (costChangeSeverity .PastCost .Cost) }}**{{ formatCostChange .PastCost .Cost }}** |
 {{- end }}
 ## Infracost estimate: **{{ formatCostChangeSentence .Root.Currency .Root.PastTotalMonthlyCost .Root.TotalMonthlyCost false }}**
 {{- if gt (len validProjects) 0  }}
@@ -427,6 +665,15 @@ var CommentMarkdownTemplate = `
 {{- end }}
 {{- end }}
 
+{{- $increases := largeCostIncreases }}
+{{- if gt (len $increases) 0 }}
+
+**Large cost increases:**
+{{ range $increases }}
+- {{ severityIcon .Severity }}` + "`" + `{{ .Name }}` + "`" + ` {{ .CostChange }}
+{{- end }}
+{{- end }}
+
 {{- if not .MarkdownOptions.OmitDetails }}
 
 **Infracost output:**
@@ -453,6 +700,50 @@ var CommentMarkdownTemplate = `
 ` + "```" /* can't escape backticks */ + `
 	{{- end }}
 {{- end }}
+{{- if gt (len .Options.PolicyChecks.Warnings) 0 }}
+**Policy warnings:**
+` + "```" /* can't escape backticks */ + `
+				{{ range $v, $f := .Options.PolicyChecks.Warnings}}
+> {{ $f }}
+				{{- end}}
+` + "```" /* can't escape backticks */ + `
+{{- end }}
+{{- if gt (len .Root.TagPolicyViolations) 0 }}
+**Tag policy failed:**
+` + "```" /* can't escape backticks */ + `
+				{{ range $v, $f := .Root.TagPolicyViolations}}
+> {{ $f.ResourceName }}: {{ $f.Message }}
+				{{- end}}
+` + "```" /* can't escape backticks */ + `
+{{- end }}
+{{- if gt (len (.Root.RecommendationMessages "rightsizing")) 0 }}
+**Rightsizing recommendations:**
+` + "```" /* can't escape backticks */ + `
+				{{ range $v, $f := .Root.RecommendationMessages "rightsizing"}}
+> {{ $f }}
+				{{- end}}
+` + "```" /* can't escape backticks */ + `
+{{- end }}
+{{- if .Root.CostRangeMessage }}
+**Cost range:**
+` + "```" /* can't escape backticks */ + `
+> {{ .Root.CostRangeMessage }}
+` + "```" /* can't escape backticks */ + `
+{{- end }}
+{{- if .Root.ProjectionsMessage }}
+**Cost projections:**
+` + "```" /* can't escape backticks */ + `
+> {{ .Root.ProjectionsMessage }}
+` + "```" /* can't escape backticks */ + `
+{{- end }}
+{{- if and .Options.ShowQuickWins (gt (len (.Root.RecommendationMessages "quick_win")) 0) }}
+**Quick wins:**
+` + "```" /* can't escape backticks */ + `
+				{{ range $v, $f := .Root.RecommendationMessages "quick_win"}}
+> {{ $f }}
+				{{- end}}
+` + "```" /* can't escape backticks */ + `
+{{- end }}
 {{- if .MarkdownOptions.WillUpdate }}
 
 This comment will be updated when the cost estimate changes.
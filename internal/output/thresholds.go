@@ -0,0 +1,155 @@
+package output
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+const (
+	severityWarning  = "warning"
+	severityCritical = "critical"
+)
+
+// CostChangeThresholds set which cost changes are shown in comments. Changes
+// smaller than MinCostChange or MinPercentChange are hidden as noise, and
+// increases of at least WarningCostIncrease or CriticalCostIncrease are
+// highlighted. Zero values disable the threshold.
+type CostChangeThresholds struct {
+	MinCostChange        decimal.Decimal
+	MinPercentChange     float64
+	WarningCostIncrease  decimal.Decimal
+	CriticalCostIncrease decimal.Decimal
+}
+
+// isBelowMinimum returns true if the change from pastCost to cost is smaller
+// than the minimum cost or percent change. Costs that change from zero are
+// only compared with the minimum cost change.
+func (t CostChangeThresholds) isBelowMinimum(pastCost, cost *decimal.Decimal) bool {
+	past, current := decimalValue(pastCost), decimalValue(cost)
+	diff := current.Sub(past).Abs()
+
+	if t.MinCostChange.IsPositive() && diff.LessThan(t.MinCostChange) {
+		return true
+	}
+
+	if t.MinPercentChange > 0 && !past.IsZero() {
+		p, _ := diff.Div(past.Abs()).Mul(decimal.NewFromInt(100)).Float64()
+		if p < t.MinPercentChange {
+			return true
+		}
+	}
+
+	return false
+}
+
+// severity returns the severity of the cost increase from pastCost to cost, or
+// an empty string if it doesn't cross any of the increase thresholds.
+func (t CostChangeThresholds) severity(pastCost, cost *decimal.Decimal) string {
+	diff := decimalValue(cost).Sub(decimalValue(pastCost))
+
+	if t.CriticalCostIncrease.IsPositive() && diff.GreaterThanOrEqual(t.CriticalCostIncrease) {
+		return severityCritical
+	}
+
+	if t.WarningCostIncrease.IsPositive() && diff.GreaterThanOrEqual(t.WarningCostIncrease) {
+		return severityWarning
+	}
+
+	return ""
+}
+
+// severityIcon returns the icon that highlights cost changes of the severity.
+func severityIcon(severity string) string {
+	switch severity {
+	case severityCritical:
+		return "🚨 "
+	case severityWarning:
+		return "⚠️ "
+	}
+
+	return ""
+}
+
+// LargeCostIncrease is a resource whose cost increase crosses one of the
+// increase thresholds, listed in comments so reviewers can find the changes
+// that matter.
+type LargeCostIncrease struct {
+	Severity   string
+	Name       string
+	CostChange string
+}
+
+// largeCostIncreases returns the resources whose cost increases cross the
+// thresholds, the largest increases first.
+func largeCostIncreases(out Root, t CostChangeThresholds) []LargeCostIncrease {
+	if !t.WarningCostIncrease.IsPositive() && !t.CriticalCostIncrease.IsPositive() {
+		return nil
+	}
+
+	var increases []LargeCostIncrease
+	for _, c := range changedResources(out) {
+		severity := t.severity(decimalPtr(decimal.Zero), c.DiffCost)
+		if severity == "" {
+			continue
+		}
+
+		increases = append(increases, LargeCostIncrease{
+			Severity:   severity,
+			Name:       changedResourceLabel(out, c, "%s"),
+			CostChange: changedResourceCost(out.Currency, c),
+		})
+	}
+
+	return increases
+}
+
+// suppressSmallChanges returns a copy of out without the diffs of resources
+// whose cost changes are below the minimums, and the number of resources
+// that were removed. The totals aren't changed so they still include the
+// small changes.
+func suppressSmallChanges(out Root, t CostChangeThresholds) (Root, int) {
+	if !t.MinCostChange.IsPositive() && t.MinPercentChange <= 0 {
+		return out, 0
+	}
+
+	suppressed := 0
+	projects := make([]Project, len(out.Projects))
+	for i, p := range out.Projects {
+		projects[i] = p
+		if p.Diff == nil {
+			continue
+		}
+
+		diff := *p.Diff
+		diff.Resources = make([]Resource, 0, len(p.Diff.Resources))
+		for _, d := range p.Diff.Resources {
+			var pastCost, cost *decimal.Decimal
+			if r := findResourceByName(breakdownResources(p.PastBreakdown), d.Name); r != nil {
+				pastCost = r.MonthlyCost
+			}
+			if r := findResourceByName(breakdownResources(p.Breakdown), d.Name); r != nil {
+				cost = r.MonthlyCost
+			}
+
+			if t.isBelowMinimum(pastCost, cost) {
+				suppressed++
+				continue
+			}
+
+			diff.Resources = append(diff.Resources, d)
+		}
+
+		projects[i].Diff = &diff
+	}
+
+	out.Projects = projects
+
+	return out, suppressed
+}
+
+func breakdownResources(b *Breakdown) []Resource {
+	if b == nil {
+		return nil
+	}
+
+	return b.Resources
+}
@@ -0,0 +1,88 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestCostChangeThresholds(t *testing.T) {
+	cost := func(i int64) *decimal.Decimal {
+		return decimalPtr(decimal.NewFromInt(i))
+	}
+
+	th := CostChangeThresholds{
+		MinCostChange:        decimal.NewFromInt(25),
+		MinPercentChange:     5,
+		WarningCostIncrease:  decimal.NewFromInt(100),
+		CriticalCostIncrease: decimal.NewFromInt(1000),
+	}
+
+	assert.True(t, th.isBelowMinimum(cost(100), cost(110)))
+	assert.True(t, th.isBelowMinimum(cost(1000), cost(1030)))
+	assert.False(t, th.isBelowMinimum(cost(100), cost(130)))
+	assert.False(t, th.isBelowMinimum(cost(130), cost(100)))
+	assert.False(t, th.isBelowMinimum(nil, cost(30)))
+	assert.False(t, CostChangeThresholds{}.isBelowMinimum(cost(100), cost(101)))
+
+	assert.Equal(t, "", th.severity(cost(100), cost(199)))
+	assert.Equal(t, severityWarning, th.severity(cost(100), cost(200)))
+	assert.Equal(t, severityCritical, th.severity(nil, cost(1000)))
+	assert.Equal(t, "", th.severity(cost(2000), cost(100)))
+}
+
+func TestToMarkdownCommentThresholds(t *testing.T) {
+	resource := func(name string, cost int64) Resource {
+		return Resource{Name: name, MonthlyCost: decimalPtr(decimal.NewFromInt(cost))}
+	}
+
+	project := func(name string, past, current, diff []Resource) Project {
+		pastBreakdown := &Breakdown{Resources: past}
+		pastBreakdown.TotalHourlyCost, pastBreakdown.TotalMonthlyCost = calculateTotalCosts(past)
+		breakdown := &Breakdown{Resources: current}
+		breakdown.TotalHourlyCost, breakdown.TotalMonthlyCost = calculateTotalCosts(current)
+		diffBreakdown := &Breakdown{Resources: diff}
+		diffBreakdown.TotalHourlyCost, diffBreakdown.TotalMonthlyCost = calculateTotalCosts(diff)
+
+		return Project{Name: name, Metadata: &schema.ProjectMetadata{}, PastBreakdown: pastBreakdown, Breakdown: breakdown, Diff: diffBreakdown}
+	}
+
+	root := Root{
+		Currency:             "USD",
+		PastTotalMonthlyCost: decimalPtr(decimal.NewFromInt(1100)),
+		TotalMonthlyCost:     decimalPtr(decimal.NewFromInt(1610)),
+		Projects: []Project{
+			project("app",
+				[]Resource{resource("aws_instance.web", 100)},
+				[]Resource{resource("aws_instance.web", 600), resource("aws_s3_bucket.logs", 1)},
+				[]Resource{resource("aws_instance.web", 500), resource("aws_s3_bucket.logs", 1)},
+			),
+			project("db",
+				[]Resource{resource("aws_db_instance.db", 1000)},
+				[]Resource{resource("aws_db_instance.db", 1009)},
+				[]Resource{resource("aws_db_instance.db", 9)},
+			),
+		},
+	}
+
+	b, err := ToMarkdown(root, Options{
+		CommentThresholds: CostChangeThresholds{
+			MinCostChange:        decimal.NewFromInt(10),
+			WarningCostIncrease:  decimal.NewFromInt(100),
+			CriticalCostIncrease: decimal.NewFromInt(1000),
+		},
+	}, MarkdownOptions{BasicSyntax: true})
+	require.NoError(t, err)
+
+	md := string(b)
+	assert.Contains(t, md, "| app | $100 | $601 | ⚠️ +$501 (+501%) |")
+	assert.NotContains(t, md, "| db |")
+	assert.Contains(t, md, "1 project has cost changes below the minimum.")
+	assert.Contains(t, md, "- ⚠️ `aws_instance.web (app)` +$500 ($100 → $600)")
+	assert.NotContains(t, md, "+ aws_s3_bucket.logs")
+	assert.Contains(t, md, "2 resources have cost changes below the minimum and aren't shown")
+}
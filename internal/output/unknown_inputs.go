@@ -0,0 +1,54 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// UnknownInput is a resource with a cost that depends on attributes that
+// weren't known when it was parsed, e.g. attributes that depend on data
+// sources or are computed by the provider, so their defaults were used.
+type UnknownInput struct {
+	ProjectName  string   `json:"projectName"`
+	ResourceName string   `json:"resourceName"`
+	Attributes   []string `json:"attributes"`
+}
+
+// SetUnknownInputs adds the resources of projects with costs that depend on
+// unknown attributes to r. Without this their costs look as certain as the
+// costs of the resources that are fully known.
+func SetUnknownInputs(r *Root, projects []*schema.Project) {
+	var unknown []UnknownInput
+
+	for _, p := range projects {
+		for _, res := range p.Resources {
+			if res.IsSkipped || len(res.UnknownInputs) == 0 {
+				continue
+			}
+
+			unknown = append(unknown, UnknownInput{
+				ProjectName:  p.Name,
+				ResourceName: res.Name,
+				Attributes:   res.UnknownInputs,
+			})
+		}
+	}
+
+	r.UnknownInputs = unknown
+}
+
+// unknownInputsMessage returns the resources with costs that depend on unknown
+// attributes and the attributes for each of them.
+func (r *Root) unknownInputsMessage() string {
+	if len(r.UnknownInputs) == 0 {
+		return ""
+	}
+
+	msg := "Costs depend on values that are only known after apply, defaults were used for these attributes:"
+	for _, u := range r.UnknownInputs {
+		msg += "\n∙ " + u.ResourceName + ": " + strings.Join(u.Attributes, ", ")
+	}
+
+	return msg
+}
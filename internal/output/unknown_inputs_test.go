@@ -0,0 +1,38 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestSetUnknownInputs(t *testing.T) {
+	resources := []*schema.Resource{
+		{Name: "aws_instance.web", CostComponents: []*schema.CostComponent{pricedCostComponent("a")}, UnknownInputs: []string{"instance_type", "root_block_device.0.volume_size"}},
+		{Name: "aws_instance.app", CostComponents: []*schema.CostComponent{pricedCostComponent("b")}},
+		{Name: "aws_sqs_queue.skipped", IsSkipped: true, UnknownInputs: []string{"fifo_queue"}},
+	}
+
+	projects := []*schema.Project{{Name: "test", Metadata: &schema.ProjectMetadata{}, Resources: resources}}
+
+	root, err := ToOutputFormat(projects)
+	require.NoError(t, err)
+	root.Currency = "USD"
+
+	SetUnknownInputs(&root, projects)
+	assert.Equal(t, []UnknownInput{
+		{ProjectName: "test", ResourceName: "aws_instance.web", Attributes: []string{"instance_type", "root_block_device.0.volume_size"}},
+	}, root.UnknownInputs)
+
+	b, err := ToTable(root, Options{NoColor: true, Fields: []string{"monthlyQuantity", "unit", "monthlyCost"}})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "Costs depend on values that are only known after apply, defaults were used for these attributes:\n"+
+		"∙ aws_instance.web: instance_type, root_block_device.0.volume_size")
+
+	b, err = ToJSON(root, Options{})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"unknownInputs":[{"projectName":"test","resourceName":"aws_instance.web","attributes":["instance_type","root_block_device.0.volume_size"]}]`)
+}
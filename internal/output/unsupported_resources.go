@@ -0,0 +1,41 @@
+package output
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// UnsupportedResource is a resource that Infracost doesn't support, so it isn't
+// included in the estimate. The filename and line of the resource are set if
+// the resource was parsed from HCL.
+type UnsupportedResource struct {
+	ProjectName  string `json:"projectName"`
+	ResourceName string `json:"resourceName"`
+	ResourceType string `json:"resourceType"`
+	Filename     string `json:"filename,omitempty"`
+	StartLine    int    `json:"startLine,omitempty"`
+}
+
+// SetUnsupportedResources adds the unsupported resources of projects to r. Only
+// their counts are in the summary, so without this the outputs that annotate
+// the resources can't locate them.
+func SetUnsupportedResources(r *Root, projects []*schema.Project) {
+	var unsupported []UnsupportedResource
+
+	for _, p := range projects {
+		for _, res := range p.Resources {
+			if !res.IsSkipped || res.NoPrice || !hasSupportedTerraformProvider(res.ResourceType) {
+				continue
+			}
+
+			unsupported = append(unsupported, UnsupportedResource{
+				ProjectName:  p.Name,
+				ResourceName: res.Name,
+				ResourceType: res.ResourceType,
+				Filename:     res.Metadata["filename"].String(),
+				StartLine:    int(res.Metadata["startLine"].Int()),
+			})
+		}
+	}
+
+	r.UnsupportedResources = unsupported
+}
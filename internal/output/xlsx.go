@@ -0,0 +1,161 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// xlsxParts are the parts of the XLSX package other than the worksheet, which
+// has the rows of the cost components.
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{
+		name: "[Content_Types].xml",
+		content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`,
+	},
+	{
+		name: "xl/workbook.xml",
+		content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Costs" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`,
+	},
+	{
+		name: "xl/_rels/workbook.xml.rels",
+		content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`,
+	},
+	{
+		// The second cell format is bold, for the header row.
+		name: "xl/styles.xml",
+		content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>
+</styleSheet>`,
+	},
+}
+
+// ToXLSX returns the cost components of the planned state as an Excel
+// workbook with the same rows as the CSV output format. Quantities, prices
+// and costs are numeric cells so they can be summed and charted.
+func ToXLSX(out Root, opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+
+	for _, p := range xlsxParts {
+		w, err := z.Create(p.name)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = w.Write([]byte(p.content))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	w, err := z.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = w.Write(xlsxWorksheet(out))
+	if err != nil {
+		return nil, err
+	}
+
+	err = z.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// xlsxWorksheet returns the worksheet XML of the cost component rows, with a
+// frozen header row.
+func xlsxWorksheet(out Root) []byte {
+	var b strings.Builder
+
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>
+<sheetData>`)
+
+	b.WriteString(`<row r="1">`)
+	for i, h := range costComponentHeaders(out.Currency) {
+		b.WriteString(xlsxStringCell(i, 1, h, 1))
+	}
+	b.WriteString(`</row>`)
+
+	for i, row := range costComponentRows(out) {
+		r := i + 2
+		fmt.Fprintf(&b, `<row r="%d">`, r)
+		b.WriteString(xlsxStringCell(0, r, row.Project, 0))
+		b.WriteString(xlsxStringCell(1, r, row.Resource, 0))
+		b.WriteString(xlsxStringCell(2, r, row.Component, 0))
+		b.WriteString(xlsxStringCell(3, r, row.Unit, 0))
+		if row.MonthlyQuantity != nil {
+			b.WriteString(xlsxNumberCell(4, r, row.MonthlyQuantity.String()))
+		}
+		b.WriteString(xlsxNumberCell(5, r, row.Price.String()))
+		if row.MonthlyCost != nil {
+			b.WriteString(xlsxNumberCell(6, r, row.MonthlyCost.String()))
+		}
+		b.WriteString(`</row>`)
+	}
+
+	b.WriteString(`</sheetData>
+</worksheet>`)
+
+	return []byte(b.String())
+}
+
+func xlsxStringCell(col, row int, value string, style int) string {
+	var escaped bytes.Buffer
+	_ = xml.EscapeText(&escaped, []byte(value))
+
+	return fmt.Sprintf(`<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, xlsxCellRef(col, row), style, escaped.String())
+}
+
+func xlsxNumberCell(col, row int, value string) string {
+	return fmt.Sprintf(`<c r="%s"><v>%s</v></c>`, xlsxCellRef(col, row), value)
+}
+
+// xlsxCellRef returns the A1 style reference of the cell, where col is zero
+// based.
+func xlsxCellRef(col, row int) string {
+	name := ""
+	for col >= 0 {
+		name = string(rune('A'+col%26)) + name
+		col = col/26 - 1
+	}
+
+	return fmt.Sprintf("%s%d", name, row)
+}
@@ -0,0 +1,76 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToXLSX(t *testing.T) {
+	b, err := ToXLSX(spreadsheetTestRoot(), Options{})
+	require.NoError(t, err)
+
+	z, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+
+	files := make(map[string][]byte)
+	for _, f := range z.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+
+		files[f.Name] = content
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		require.Contains(t, files, name)
+		require.NoError(t, xml.Unmarshal(files[name], new(interface{})), name)
+	}
+
+	var sheet struct {
+		Rows []struct {
+			R     string `xml:"r,attr"`
+			Cells []struct {
+				R      string `xml:"r,attr"`
+				T      string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				String string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	require.NoError(t, xml.Unmarshal(files["xl/worksheets/sheet1.xml"], &sheet))
+	require.Len(t, sheet.Rows, 4)
+
+	header := sheet.Rows[0].Cells
+	require.Len(t, header, 7)
+	assert.Equal(t, "Project", header[0].String)
+	assert.Equal(t, "Monthly cost (EUR)", header[6].String)
+
+	first := sheet.Rows[1].Cells
+	assert.Equal(t, "A2", first[0].R)
+	assert.Equal(t, "aws_instance.web", first[1].String)
+	assert.Equal(t, "E2", first[4].R)
+	assert.Equal(t, "", first[4].T)
+	assert.Equal(t, "730", first[4].Value)
+	assert.Equal(t, "30.368", first[6].Value)
+
+	// The quantity and cost of usage based components are left empty.
+	usage := sheet.Rows[3].Cells
+	require.Len(t, usage, 5)
+	assert.Equal(t, "F4", usage[4].R)
+	assert.Equal(t, "0.2", usage[4].Value)
+}
+
+func TestXLSXCellRef(t *testing.T) {
+	assert.Equal(t, "A1", xlsxCellRef(0, 1))
+	assert.Equal(t, "G10", xlsxCellRef(6, 10))
+	assert.Equal(t, "Z2", xlsxCellRef(25, 2))
+	assert.Equal(t, "AA3", xlsxCellRef(26, 3))
+}
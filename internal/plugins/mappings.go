@@ -0,0 +1,626 @@
+package plugins
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	getter "github.com/hashicorp/go-getter"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/shopspring/decimal"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"gopkg.in/yaml.v3"
+
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const mappingFileVersion = "0.1"
+
+// mappingFunctions are the functions that the expressions of mapping files
+// can call.
+var mappingFunctions = map[string]function.Function{
+	"ceil":     stdlib.CeilFunc,
+	"coalesce": stdlib.CoalesceFunc,
+	"contains": stdlib.ContainsFunc,
+	"floor":    stdlib.FloorFunc,
+	"lookup":   stdlib.LookupFunc,
+	"lower":    stdlib.LowerFunc,
+	"max":      stdlib.MaxFunc,
+	"min":      stdlib.MinFunc,
+	"upper":    stdlib.UpperFunc,
+}
+
+// MappingFile declares the cost components of resource types that aren't
+// supported, for resources that are simple enough to not need a plugin
+// executable, e.g.
+//
+//	version: 0.1
+//	resources:
+//	  - type: acme_database
+//	    usage:
+//	      - key: monthly_backup_gb
+//	        default: 0
+//	    cost_components:
+//	      - name: Instance (${values.size})
+//	        unit: hours
+//	        hourly_quantity: values.node_count
+//	        price: lookup({small = 0.1, large = 0.4}, values.size, 0.1)
+//	      - name: Backups
+//	        unit: GB
+//	        monthly_quantity: usage.monthly_backup_gb * 1.1
+//	        product_filter:
+//	          vendor_name: aws
+//	          service: AmazonS3
+//	          product_family: Storage
+//	          region: ${values.region}
+//	          attribute_filters:
+//	            - key: volumeType
+//	              value: Standard
+//	        price_filter:
+//	          purchase_option: on_demand
+//
+// Quantities, unit multipliers, prices and conditions are HCL expressions,
+// and names, units and filter values are HCL templates. They can reference
+// the attributes of the resource as values and its usage keys as usage, and
+// optional attributes with lookup, e.g. lookup(values, "zone", ""). A
+// quantity that can't be evaluated, e.g. as its usage isn't set, is left
+// unset.
+type MappingFile struct {
+	Version   string            `yaml:"version"`
+	Resources []ResourceMapping `yaml:"resources"`
+}
+
+// ResourceMapping declares the usage and cost components of a resource type.
+type ResourceMapping struct {
+	Type           string                 `yaml:"type"`
+	Notes          []string               `yaml:"notes,omitempty"`
+	Usage          []UsageMapping         `yaml:"usage,omitempty"`
+	CostComponents []CostComponentMapping `yaml:"cost_components"`
+}
+
+// UsageMapping declares a usage key of a resource type, which can be set in
+// the usage file.
+type UsageMapping struct {
+	Key         string      `yaml:"key"`
+	Default     interface{} `yaml:"default,omitempty"`
+	Description string      `yaml:"description,omitempty"`
+}
+
+// CostComponentMapping declares a cost component of a resource type. It must
+// have either a price or a product filter to look up its price with.
+type CostComponentMapping struct {
+	Name            string                `yaml:"name"`
+	Unit            string                `yaml:"unit"`
+	UnitMultiplier  string                `yaml:"unit_multiplier,omitempty"`
+	Condition       string                `yaml:"condition,omitempty"`
+	HourlyQuantity  string                `yaml:"hourly_quantity,omitempty"`
+	MonthlyQuantity string                `yaml:"monthly_quantity,omitempty"`
+	Price           string                `yaml:"price,omitempty"`
+	ProductFilter   *ProductFilterMapping `yaml:"product_filter,omitempty"`
+	PriceFilter     *PriceFilterMapping   `yaml:"price_filter,omitempty"`
+}
+
+// ProductFilterMapping declares the product filter of a cost component.
+type ProductFilterMapping struct {
+	VendorName       string                   `yaml:"vendor_name"`
+	Service          string                   `yaml:"service,omitempty"`
+	ProductFamily    string                   `yaml:"product_family,omitempty"`
+	Region           string                   `yaml:"region,omitempty"`
+	Sku              string                   `yaml:"sku,omitempty"`
+	AttributeFilters []AttributeFilterMapping `yaml:"attribute_filters,omitempty"`
+}
+
+// AttributeFilterMapping declares an attribute filter of a product filter.
+type AttributeFilterMapping struct {
+	Key        string `yaml:"key"`
+	Value      string `yaml:"value,omitempty"`
+	ValueRegex string `yaml:"value_regex,omitempty"`
+}
+
+// PriceFilterMapping declares the price filter of a cost component.
+type PriceFilterMapping struct {
+	PurchaseOption     string `yaml:"purchase_option,omitempty"`
+	Unit               string `yaml:"unit,omitempty"`
+	Description        string `yaml:"description,omitempty"`
+	DescriptionRegex   string `yaml:"description_regex,omitempty"`
+	StartUsageAmount   string `yaml:"start_usage_amount,omitempty"`
+	EndUsageAmount     string `yaml:"end_usage_amount,omitempty"`
+	TermLength         string `yaml:"term_length,omitempty"`
+	TermPurchaseOption string `yaml:"term_purchase_option,omitempty"`
+	TermOfferingClass  string `yaml:"term_offering_class,omitempty"`
+}
+
+// LoadMappings reads the mapping files, with a .yml or .yaml extension, in
+// the directory or git URL src. Git URLs use the same format as the sources
+// of Terraform modules, e.g. git::https://example.com/mappings.git?ref=v1.
+func LoadMappings(src string) ([]ResourceMapping, error) {
+	dir := src
+	if _, err := os.Stat(src); err != nil {
+		dir, err = os.MkdirTemp("", "infracost-mappings")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		client := getter.Client{
+			Src:  src,
+			Dst:  dir,
+			Pwd:  dir,
+			Mode: getter.ClientModeDir,
+		}
+		if err := client.Get(); err != nil {
+			return nil, fmt.Errorf("Error downloading mapping files from %s: %w", src, err)
+		}
+	}
+
+	var paths []string
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("No mapping files found in %s", src)
+	}
+
+	var mappings []ResourceMapping
+	seen := map[string]string{}
+	for _, path := range paths {
+		f, err := loadMappingFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range f.Resources {
+			if prev, ok := seen[m.Type]; ok {
+				return nil, fmt.Errorf("Resource type %s is mapped in both %s and %s", m.Type, filepath.Base(prev), filepath.Base(path))
+			}
+
+			seen[m.Type] = path
+			mappings = append(mappings, m)
+		}
+	}
+
+	return mappings, nil
+}
+
+func loadMappingFile(path string) (*MappingFile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading mapping file: %w", err)
+	}
+
+	var f MappingFile
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("Error parsing mapping file %s: %w", path, err)
+	}
+
+	if f.Version != mappingFileVersion {
+		return nil, fmt.Errorf("Mapping file %s has an invalid version %q, the version must be %s", path, f.Version, mappingFileVersion)
+	}
+
+	for _, m := range f.Resources {
+		if err := m.validate(); err != nil {
+			return nil, fmt.Errorf("Mapping of %s in %s is invalid: %w", m.Type, path, err)
+		}
+	}
+
+	return &f, nil
+}
+
+// validate checks the mapping has the required fields and that its
+// expressions and templates parse.
+func (m ResourceMapping) validate() error {
+	if m.Type == "" {
+		return errors.New("it must have a type")
+	}
+
+	if len(m.CostComponents) == 0 {
+		return errors.New("it must have cost components")
+	}
+
+	for _, u := range m.Usage {
+		if u.Key == "" {
+			return errors.New("usage must have a key")
+		}
+	}
+
+	for _, c := range m.CostComponents {
+		if c.Name == "" {
+			return errors.New("cost components must have a name")
+		}
+
+		if (c.Price == "") == (c.ProductFilter == nil) {
+			return fmt.Errorf("cost component %q must have a price or a product filter", c.Name)
+		}
+
+		if c.HourlyQuantity != "" && c.MonthlyQuantity != "" {
+			return fmt.Errorf("cost component %q can't have both an hourly and a monthly quantity", c.Name)
+		}
+
+		for _, expr := range []string{c.UnitMultiplier, c.Condition, c.HourlyQuantity, c.MonthlyQuantity, c.Price} {
+			if _, err := parseExpression(expr); err != nil {
+				return fmt.Errorf("cost component %q: %w", c.Name, err)
+			}
+		}
+
+		for _, tmpl := range c.templates() {
+			if _, err := parseTemplate(tmpl); err != nil {
+				return fmt.Errorf("cost component %q: %w", c.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// templates returns the templates of the cost component.
+func (c CostComponentMapping) templates() []string {
+	tmpls := []string{c.Name, c.Unit}
+
+	if f := c.ProductFilter; f != nil {
+		tmpls = append(tmpls, f.VendorName, f.Service, f.ProductFamily, f.Region, f.Sku)
+		for _, a := range f.AttributeFilters {
+			tmpls = append(tmpls, a.Value, a.ValueRegex)
+		}
+	}
+
+	if f := c.PriceFilter; f != nil {
+		tmpls = append(tmpls, f.PurchaseOption, f.Unit, f.Description, f.DescriptionRegex, f.StartUsageAmount, f.EndUsageAmount, f.TermLength, f.TermPurchaseOption, f.TermOfferingClass)
+	}
+
+	return tmpls
+}
+
+// RegistryItem returns the registry item of the resource type of the
+// mapping, which builds the resources from the mapping.
+func (m ResourceMapping) RegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  m.Type,
+		Notes: m.Notes,
+		RFunc: m.newResource,
+	}
+}
+
+func (m ResourceMapping) newResource(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	ctx := m.evalContext(d, u)
+
+	r := &schema.Resource{
+		Name:        d.Address,
+		UsageSchema: m.usageSchema(),
+	}
+
+	for _, c := range m.CostComponents {
+		cc, err := c.build(ctx)
+		if err != nil {
+			logging.Logger.WithError(err).Warnf("could not build cost component %q of %s from its mapping", c.Name, d.Address)
+			continue
+		}
+
+		if cc != nil {
+			r.CostComponents = append(r.CostComponents, cc)
+		}
+	}
+
+	return r
+}
+
+func (m ResourceMapping) usageSchema() []*schema.UsageItem {
+	items := make([]*schema.UsageItem, 0, len(m.Usage))
+	for _, u := range m.Usage {
+		valueType := schema.Float64
+		if _, ok := u.Default.(string); ok {
+			valueType = schema.String
+		}
+
+		items = append(items, &schema.UsageItem{
+			Key:          u.Key,
+			DefaultValue: u.Default,
+			ValueType:    valueType,
+			Description:  u.Description,
+		})
+	}
+
+	return items
+}
+
+// evalContext returns the context that the expressions of the mapping are
+// evaluated in, with the attributes of d as values and the usage keys of the
+// mapping as usage. Usage keys that aren't set in u have their default, or
+// null if they have no default.
+func (m ResourceMapping) evalContext(d *schema.ResourceData, u *schema.UsageData) *hcl.EvalContext {
+	values := cty.EmptyObjectVal
+	if d.RawValues.IsObject() {
+		if v, err := jsonToCty([]byte(d.RawValues.Raw)); err == nil {
+			values = v
+		}
+	}
+
+	usage := map[string]cty.Value{}
+	for _, k := range m.Usage {
+		usage[k.Key] = cty.NullVal(cty.DynamicPseudoType)
+
+		raw := json.RawMessage(nil)
+		if u != nil {
+			if v, ok := u.Attributes[k.Key]; ok {
+				raw = rawJSON(v)
+			}
+		}
+
+		if raw == nil && k.Default != nil {
+			raw, _ = json.Marshal(k.Default)
+		}
+
+		if raw != nil {
+			if v, err := jsonToCty(raw); err == nil {
+				usage[k.Key] = v
+			}
+		}
+	}
+
+	return &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"values": values,
+			"usage":  cty.ObjectVal(usage),
+		},
+		Functions: mappingFunctions,
+	}
+}
+
+// build evaluates the cost component mapping. It returns nil if the
+// condition of the cost component is false.
+func (c CostComponentMapping) build(ctx *hcl.EvalContext) (*schema.CostComponent, error) {
+	if c.Condition != "" {
+		v, err := evalExpression(c.Condition, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if v.IsNull() || !v.IsKnown() {
+			return nil, nil
+		}
+
+		b, err := convert.Convert(v, cty.Bool)
+		if err != nil {
+			return nil, fmt.Errorf("condition is not a bool: %w", err)
+		}
+
+		if b.False() {
+			return nil, nil
+		}
+	}
+
+	name, err := evalTemplate(c.Name, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unit, err := evalTemplate(c.Unit, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cc := &schema.CostComponent{
+		Name:            name,
+		Unit:            unit,
+		UnitMultiplier:  decimal.NewFromInt(1),
+		HourlyQuantity:  evalQuantity(c.HourlyQuantity, ctx),
+		MonthlyQuantity: evalQuantity(c.MonthlyQuantity, ctx),
+	}
+
+	if c.UnitMultiplier != "" {
+		m, err := evalDecimal(c.UnitMultiplier, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unit multiplier: %w", err)
+		}
+
+		cc.UnitMultiplier = m
+	}
+
+	if c.Price != "" {
+		price, err := evalDecimal(c.Price, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("price: %w", err)
+		}
+
+		cc.SetCustomPrice(&price)
+	}
+
+	if c.ProductFilter != nil {
+		cc.ProductFilter, err = c.ProductFilter.build(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.PriceFilter != nil {
+		cc.PriceFilter, err = c.PriceFilter.build(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cc, nil
+}
+
+func (f ProductFilterMapping) build(ctx *hcl.EvalContext) (*schema.ProductFilter, error) {
+	t := &templateEvaluator{ctx: ctx}
+
+	filter := &schema.ProductFilter{
+		VendorName:    t.eval(f.VendorName),
+		Service:       t.eval(f.Service),
+		ProductFamily: t.eval(f.ProductFamily),
+		Region:        t.eval(f.Region),
+		Sku:           t.eval(f.Sku),
+	}
+
+	for _, a := range f.AttributeFilters {
+		filter.AttributeFilters = append(filter.AttributeFilters, &schema.AttributeFilter{
+			Key:        a.Key,
+			Value:      t.eval(a.Value),
+			ValueRegex: t.eval(a.ValueRegex),
+		})
+	}
+
+	return filter, t.err
+}
+
+func (f PriceFilterMapping) build(ctx *hcl.EvalContext) (*schema.PriceFilter, error) {
+	t := &templateEvaluator{ctx: ctx}
+
+	filter := &schema.PriceFilter{
+		PurchaseOption:     t.eval(f.PurchaseOption),
+		Unit:               t.eval(f.Unit),
+		Description:        t.eval(f.Description),
+		DescriptionRegex:   t.eval(f.DescriptionRegex),
+		StartUsageAmount:   t.eval(f.StartUsageAmount),
+		EndUsageAmount:     t.eval(f.EndUsageAmount),
+		TermLength:         t.eval(f.TermLength),
+		TermPurchaseOption: t.eval(f.TermPurchaseOption),
+		TermOfferingClass:  t.eval(f.TermOfferingClass),
+	}
+
+	return filter, t.err
+}
+
+// templateEvaluator evaluates the optional templates of filters, keeping the
+// first error so the filter can be built in one go.
+type templateEvaluator struct {
+	ctx *hcl.EvalContext
+	err error
+}
+
+// eval returns the evaluated template, or nil if the template is empty.
+func (t *templateEvaluator) eval(tmpl string) *string {
+	if tmpl == "" || t.err != nil {
+		return nil
+	}
+
+	s, err := evalTemplate(tmpl, t.ctx)
+	if err != nil {
+		t.err = err
+		return nil
+	}
+
+	return &s
+}
+
+func parseExpression(expr string) (hcl.Expression, error) {
+	if expr == "" {
+		return nil, nil
+	}
+
+	e, diags := hclsyntax.ParseExpression([]byte(expr), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("invalid expression %q: %s", expr, diags.Error())
+	}
+
+	return e, nil
+}
+
+func parseTemplate(tmpl string) (hcl.Expression, error) {
+	e, diags := hclsyntax.ParseTemplate([]byte(tmpl), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("invalid template %q: %s", tmpl, diags.Error())
+	}
+
+	return e, nil
+}
+
+func evalExpression(expr string, ctx *hcl.EvalContext) (cty.Value, error) {
+	e, err := parseExpression(expr)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	v, diags := e.Value(ctx)
+	if diags.HasErrors() {
+		return cty.NilVal, fmt.Errorf("could not evaluate %q: %s", expr, diags.Error())
+	}
+
+	return v, nil
+}
+
+func evalTemplate(tmpl string, ctx *hcl.EvalContext) (string, error) {
+	e, err := parseTemplate(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	v, diags := e.Value(ctx)
+	if diags.HasErrors() {
+		return "", fmt.Errorf("could not evaluate %q: %s", tmpl, diags.Error())
+	}
+
+	if v.IsNull() || !v.IsKnown() {
+		return "", nil
+	}
+
+	s, err := convert.Convert(v, cty.String)
+	if err != nil {
+		return "", fmt.Errorf("%q is not a string: %w", tmpl, err)
+	}
+
+	return s.AsString(), nil
+}
+
+func evalDecimal(expr string, ctx *hcl.EvalContext) (decimal.Decimal, error) {
+	v, err := evalExpression(expr, ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	if v.IsNull() || !v.IsKnown() {
+		return decimal.Zero, fmt.Errorf("%q has no value", expr)
+	}
+
+	n, err := convert.Convert(v, cty.Number)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%q is not a number: %w", expr, err)
+	}
+
+	return decimal.NewFromString(n.AsBigFloat().Text('f', -1))
+}
+
+// evalQuantity returns the quantity of the expression, or nil if the
+// expression is empty or can't be evaluated, e.g. as it uses usage that
+// isn't set.
+func evalQuantity(expr string, ctx *hcl.EvalContext) *decimal.Decimal {
+	if expr == "" {
+		return nil
+	}
+
+	d, err := evalDecimal(expr, ctx)
+	if err != nil {
+		logging.Logger.WithError(err).Debug("could not evaluate quantity")
+		return nil
+	}
+
+	return &d
+}
+
+func jsonToCty(b []byte) (cty.Value, error) {
+	t, err := ctyjson.ImpliedType(b)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return ctyjson.Unmarshal(b, t)
+}
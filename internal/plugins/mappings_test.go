@@ -0,0 +1,198 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const acmeMappings = `version: 0.1
+resources:
+  - type: acme_database
+    notes: [Storage is billed by the GB.]
+    usage:
+      - key: monthly_backup_gb
+        default: 10
+      - key: monthly_queries
+    cost_components:
+      - name: Instance (${values.size})
+        unit: hours
+        hourly_quantity: values.node_count
+        price: lookup({small = 0.1, large = 0.4}, values.size, 0.1)
+      - name: Standby instance
+        unit: hours
+        condition: values.multi_az
+        hourly_quantity: 1
+        price: 0.4
+      - name: Queries
+        unit: 1M queries
+        unit_multiplier: 1000000
+        monthly_quantity: usage.monthly_queries
+        price: 2
+      - name: Backups
+        unit: GB
+        monthly_quantity: usage.monthly_backup_gb * 1.5
+        product_filter:
+          vendor_name: aws
+          service: AmazonS3
+          region: ${lookup(values, "region", "")}
+          attribute_filters:
+            - key: storageClass
+              value: ${upper(values.backup_class)}
+        price_filter:
+          purchase_option: on_demand
+`
+
+func writeMappings(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, contents := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0600))
+	}
+
+	return dir
+}
+
+func TestLoadMappings(t *testing.T) {
+	mappings, err := LoadMappings(writeMappings(t, map[string]string{
+		"acme.yml":   acmeMappings,
+		"other.yaml": "version: 0.1\nresources:\n  - type: acme_queue\n    cost_components:\n      - name: Queue\n        unit: months\n        monthly_quantity: 1\n        price: 5\n",
+		"README.md":  "Not a mapping file",
+	}))
+	require.NoError(t, err)
+
+	require.Len(t, mappings, 2)
+	assert.Equal(t, "acme_database", mappings[0].Type)
+	assert.Equal(t, "acme_queue", mappings[1].Type)
+
+	item := mappings[0].RegistryItem()
+	assert.Equal(t, "acme_database", item.Name)
+	assert.Equal(t, []string{"Storage is billed by the GB."}, item.Notes)
+}
+
+func TestLoadMappingsInvalid(t *testing.T) {
+	component := "    cost_components:\n      - name: Instance\n        unit: hours\n"
+
+	tests := []struct {
+		name     string
+		contents string
+		error    string
+	}{
+		{
+			name:     "invalid version",
+			contents: "version: 0.2\nresources: []\n",
+			error:    `has an invalid version "0.2", the version must be 0.1`,
+		},
+		{
+			name:     "unknown key",
+			contents: "version: 0.1\nresources:\n  - type: acme_database\n    cost: 1\n",
+			error:    "field cost not found",
+		},
+		{
+			name:     "no cost components",
+			contents: "version: 0.1\nresources:\n  - type: acme_database\n",
+			error:    "it must have cost components",
+		},
+		{
+			name:     "no price",
+			contents: "version: 0.1\nresources:\n  - type: acme_database\n" + component + "        hourly_quantity: 1\n",
+			error:    `cost component "Instance" must have a price or a product filter`,
+		},
+		{
+			name:     "both quantities",
+			contents: "version: 0.1\nresources:\n  - type: acme_database\n" + component + "        hourly_quantity: 1\n        monthly_quantity: 730\n        price: 1\n",
+			error:    `cost component "Instance" can't have both an hourly and a monthly quantity`,
+		},
+		{
+			name:     "invalid expression",
+			contents: "version: 0.1\nresources:\n  - type: acme_database\n" + component + "        hourly_quantity: values.node_count *\n        price: 1\n",
+			error:    `invalid expression "values.node_count *"`,
+		},
+		{
+			name:     "invalid template",
+			contents: "version: 0.1\nresources:\n  - type: acme_database\n    cost_components:\n      - name: Instance (${values.size)\n        unit: hours\n        price: 1\n",
+			error:    `invalid template "Instance (${values.size)"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadMappings(writeMappings(t, map[string]string{"acme.yml": tt.contents}))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.error)
+		})
+	}
+
+	_, err := LoadMappings(writeMappings(t, map[string]string{"a.yml": acmeMappings, "b.yml": acmeMappings}))
+	assert.EqualError(t, err, "Resource type acme_database is mapped in both a.yml and b.yml")
+
+	dir := writeMappings(t, nil)
+	_, err = LoadMappings(dir)
+	assert.EqualError(t, err, "No mapping files found in "+dir)
+}
+
+func TestResourceMappingBuild(t *testing.T) {
+	mappings, err := LoadMappings(writeMappings(t, map[string]string{"acme.yml": acmeMappings}))
+	require.NoError(t, err)
+
+	rFunc := mappings[0].RegistryItem().RFunc
+
+	d := schema.NewResourceData("acme_database", "registry.terraform.io/acme/acme", "acme_database.main", nil, gjson.Parse(`{
+		"size": "large",
+		"node_count": 3,
+		"multi_az": false,
+		"region": "us-east-1",
+		"backup_class": "standard"
+	}`))
+	u := schema.NewUsageData("acme_database.main", map[string]gjson.Result{
+		"monthly_queries": gjson.Parse("2500000"),
+	})
+
+	r := rFunc(d, u)
+	assert.Equal(t, "acme_database.main", r.Name)
+	require.Len(t, r.CostComponents, 3)
+
+	instance := r.CostComponents[0]
+	assert.Equal(t, "Instance (large)", instance.Name)
+	assert.Equal(t, "3", instance.HourlyQuantity.String())
+	assert.Equal(t, "0.4", instance.CustomPrice().String())
+
+	queries := r.CostComponents[1]
+	assert.Equal(t, "Queries", queries.Name)
+	assert.Equal(t, "1000000", queries.UnitMultiplier.String())
+	assert.Equal(t, "2500000", queries.MonthlyQuantity.String())
+
+	backups := r.CostComponents[2]
+	assert.Equal(t, "15", backups.MonthlyQuantity.String())
+	assert.Nil(t, backups.CustomPrice())
+	assert.Equal(t, "us-east-1", *backups.ProductFilter.Region)
+	assert.Equal(t, "STANDARD", *backups.ProductFilter.AttributeFilters[0].Value)
+	assert.Equal(t, "on_demand", *backups.PriceFilter.PurchaseOption)
+	assert.Nil(t, backups.PriceFilter.Unit)
+
+	require.Len(t, r.UsageSchema, 2)
+	assert.Equal(t, "monthly_backup_gb", r.UsageSchema[0].Key)
+	assert.Equal(t, 10, r.UsageSchema[0].DefaultValue)
+
+	d = schema.NewResourceData("acme_database", "registry.terraform.io/acme/acme", "acme_database.standby", nil, gjson.Parse(`{
+		"size": "medium",
+		"node_count": 1,
+		"multi_az": true,
+		"backup_class": "standard"
+	}`))
+
+	r = rFunc(d, nil)
+	require.Len(t, r.CostComponents, 4)
+	assert.Equal(t, "Instance (medium)", r.CostComponents[0].Name)
+	assert.Equal(t, "0.1", r.CostComponents[0].CustomPrice().String())
+	assert.Equal(t, "Standby instance", r.CostComponents[1].Name)
+	assert.Nil(t, r.CostComponents[2].MonthlyQuantity, "quantity of usage that isn't set")
+	assert.Equal(t, "", *r.CostComponents[3].ProductFilter.Region)
+}
@@ -0,0 +1,356 @@
+// Package plugins builds the costs of resources of Terraform providers that
+// Infracost doesn't support, e.g. the proprietary providers of an
+// organization, with plugin executables or, for simpler resources, YAML
+// mapping files, see MappingFile.
+//
+// Plugins are executables that serve a plugin.ResourceBuilder of the
+// pkg/plugin package, which runs them with hashicorp/go-plugin. A plugin is
+// started once when it's loaded and runs until the run is finished. Plugins
+// can't build the costs of resource types that Infracost supports, and the
+// fields of schema.Resource that a plugin can't set are listed on
+// plugin.Resource.
+package plugins
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/pkg/plugin"
+)
+
+// Timeout is how long a plugin can take to start or to respond to a call. A
+// plugin that doesn't respond in time is stopped.
+var Timeout = 30 * time.Second
+
+// Plugin is a loaded plugin executable, whose process is running until it's
+// closed.
+type Plugin struct {
+	Path          string
+	Name          string
+	ResourceTypes []string
+
+	client  *goplugin.Client
+	builder plugin.ResourceBuilder
+	stderr  bytes.Buffer
+
+	// mu makes sure that only one call is made to the plugin at a time, so
+	// plugins don't have to build resources concurrently.
+	mu sync.Mutex
+	// err is set once the process has exited, or has been stopped, and is
+	// returned for any more calls.
+	err error
+}
+
+// Load starts the plugin executable at path and returns the plugin with the
+// resource types that it builds the costs of. The plugin must be closed once
+// the resources have been built.
+func Load(path string) (*Plugin, error) {
+	p := &Plugin{Path: path}
+
+	p.client = goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig: plugin.Handshake,
+		Plugins:         plugin.ClientPlugins(),
+		Cmd:             exec.Command(path),
+		StartTimeout:    Timeout,
+		Stderr:          &p.stderr,
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Name:   filepath.Base(path),
+			Level:  hclog.Debug,
+			Output: logWriter{},
+		}),
+	})
+
+	rpcClient, err := p.client.Client()
+	if err != nil {
+		return nil, fmt.Errorf("Error loading plugin %s: %w", path, p.exitError(err))
+	}
+
+	raw, err := rpcClient.Dispense(plugin.Name)
+	if err != nil {
+		return nil, fmt.Errorf("Error loading plugin %s: %w", path, p.exitError(err))
+	}
+	p.builder = raw.(plugin.ResourceBuilder)
+
+	if err := p.describe(); err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// describe calls Describe on the plugin and sets its name and resource types.
+func (p *Plugin) describe() error {
+	var d *plugin.Description
+	err := p.call(func() (err error) {
+		d, err = p.builder.Describe()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Error loading plugin %s: %w", p.Path, err)
+	}
+
+	if len(d.ResourceTypes) == 0 {
+		return fmt.Errorf("Plugin %s doesn't build the costs of any resource types", p.Path)
+	}
+
+	p.Name = d.Name
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(filepath.Base(p.Path), filepath.Ext(p.Path))
+	}
+	p.ResourceTypes = d.ResourceTypes
+
+	return nil
+}
+
+// RegistryItems returns the registry items of the resource types of the
+// plugin, which build the resources with the plugin.
+func (p *Plugin) RegistryItems() []*schema.RegistryItem {
+	items := make([]*schema.RegistryItem, 0, len(p.ResourceTypes))
+	for _, t := range p.ResourceTypes {
+		items = append(items, &schema.RegistryItem{
+			Name:  t,
+			Notes: []string{fmt.Sprintf("Costs are built by the %s plugin.", p.Name)},
+			RFunc: p.newResource,
+		})
+	}
+
+	return items
+}
+
+func (p *Plugin) newResource(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r, err := p.Build(d, u)
+	if err != nil {
+		logging.Logger.WithError(err).Warnf("plugin %s could not build %s", p.Name, d.Address)
+
+		return &schema.Resource{
+			Name:         d.Address,
+			ResourceType: d.Type,
+			IsSkipped:    true,
+			SkipMessage:  fmt.Sprintf("The %s plugin could not build this resource", p.Name),
+		}
+	}
+
+	return r
+}
+
+// Build calls Build on the plugin with the resource data and usage, and
+// converts the resource that it returns.
+func (p *Plugin) Build(d *schema.ResourceData, u *schema.UsageData) (*schema.Resource, error) {
+	var r *plugin.Resource
+	err := p.call(func() (err error) {
+		r, err = p.builder.Build(newResourceData(d, u))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Name == "" {
+		r.Name = d.Address
+	}
+	if r.ResourceType == "" {
+		r.ResourceType = d.Type
+	}
+
+	return toSchemaResource(r)
+}
+
+func newResourceData(d *schema.ResourceData, u *schema.UsageData) *plugin.ResourceData {
+	values := json.RawMessage(d.RawValues.Raw)
+	if !d.RawValues.Exists() {
+		values = json.RawMessage("{}")
+	}
+
+	r := &plugin.ResourceData{
+		Address:      d.Address,
+		Type:         d.Type,
+		ProviderName: d.ProviderName,
+		Values:       values,
+		Tags:         d.Tags,
+	}
+
+	if u != nil && len(u.Attributes) > 0 {
+		r.Usage = make(map[string]json.RawMessage, len(u.Attributes))
+		for k, v := range u.Attributes {
+			r.Usage[k] = rawJSON(v)
+		}
+	}
+
+	return r
+}
+
+// rawJSON returns the JSON of v. Usage values that are parsed from YAML don't
+// always have their raw JSON, so their values are marshalled instead.
+func rawJSON(v gjson.Result) json.RawMessage {
+	if v.Raw != "" && json.Valid([]byte(v.Raw)) {
+		return json.RawMessage(v.Raw)
+	}
+
+	b, err := json.Marshal(v.Value())
+	if err != nil {
+		return json.RawMessage("null")
+	}
+
+	return b
+}
+
+func toSchemaResource(r *plugin.Resource) (*schema.Resource, error) {
+	res := &schema.Resource{
+		Name:         r.Name,
+		ResourceType: r.ResourceType,
+		Tags:         r.Tags,
+		NoPrice:      r.NoPrice,
+		IsSkipped:    r.IsSkipped || r.SkipMessage != "",
+		SkipMessage:  r.SkipMessage,
+	}
+
+	for _, c := range r.CostComponents {
+		cc, err := toSchemaCostComponent(c)
+		if err != nil {
+			return nil, fmt.Errorf("cost component %q of %s: %w", c.Name, r.Name, err)
+		}
+
+		res.CostComponents = append(res.CostComponents, cc)
+	}
+
+	for _, s := range r.SubResources {
+		sub, err := toSchemaResource(s)
+		if err != nil {
+			return nil, err
+		}
+
+		res.SubResources = append(res.SubResources, sub)
+	}
+
+	for _, item := range r.UsageSchema {
+		res.UsageSchema = append(res.UsageSchema, &schema.UsageItem{
+			Key:          item.Key,
+			ValueType:    item.ValueType,
+			DefaultValue: item.DefaultValue,
+			Description:  item.Description,
+		})
+	}
+
+	return res, nil
+}
+
+func toSchemaCostComponent(c *plugin.CostComponent) (*schema.CostComponent, error) {
+	if c.Name == "" {
+		return nil, errors.New("it must have a name")
+	}
+
+	if (c.Price == nil) == (c.ProductFilter == nil) {
+		return nil, errors.New("it must have a price or a product filter")
+	}
+
+	if c.HourlyQuantity != nil && c.MonthlyQuantity != nil {
+		return nil, errors.New("it can't have both an hourly and a monthly quantity")
+	}
+
+	unitMultiplier := decimal.NewFromInt(1)
+	if c.UnitMultiplier != nil {
+		unitMultiplier = *c.UnitMultiplier
+	}
+
+	cc := &schema.CostComponent{
+		Name:                 c.Name,
+		Unit:                 c.Unit,
+		UnitMultiplier:       unitMultiplier,
+		HourlyQuantity:       c.HourlyQuantity,
+		MonthlyQuantity:      c.MonthlyQuantity,
+		ProductFilter:        c.ProductFilter,
+		PriceFilter:          c.PriceFilter,
+		IgnoreIfMissingPrice: c.IgnoreIfMissingPrice,
+		MonthlyDiscountPerc:  c.MonthlyDiscountPerc,
+	}
+
+	if c.Price != nil {
+		cc.SetCustomPrice(c.Price)
+	}
+
+	return cc, nil
+}
+
+// call runs f, which calls the plugin, and stops the plugin if it doesn't
+// return within the Timeout.
+func (p *Plugin) call(f func() error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- f() }()
+
+	select {
+	case err := <-done:
+		if p.client.Exited() {
+			p.err = p.exitError(err)
+			return p.err
+		}
+
+		return err
+	case <-time.After(Timeout):
+		p.client.Kill()
+		p.err = fmt.Errorf("timed out after %s", Timeout)
+
+		return p.err
+	}
+}
+
+// exitError stops the plugin, which has failed with err, and returns err with
+// what the plugin wrote to stderr.
+func (p *Plugin) exitError(err error) error {
+	// Kill waits for the stderr of the process to be read.
+	p.client.Kill()
+
+	if err == nil {
+		err = errors.New("exited")
+	}
+
+	if msg := strings.TrimSpace(p.stderr.String()); msg != "" {
+		return fmt.Errorf("%w: %s", err, msg)
+	}
+
+	return err
+}
+
+// Close stops the process of the plugin. Resources of its types can't be
+// built once it's closed.
+func (p *Plugin) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return
+	}
+
+	p.client.Kill()
+	p.err = errors.New("plugin is closed")
+}
+
+// logWriter writes the logs of go-plugin, which include the stderr of the
+// plugins, to the debug log.
+type logWriter struct{}
+
+func (logWriter) Write(b []byte) (int, error) {
+	logging.Logger.Debug(strings.TrimSpace(string(b)))
+	return len(b), nil
+}
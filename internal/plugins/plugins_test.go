@@ -0,0 +1,303 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/pkg/plugin"
+)
+
+// testPluginEnv is set to the name of one of the testBuilders when the test
+// binary is run as a plugin, see writePlugin.
+const testPluginEnv = "INFRACOST_TEST_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if name := os.Getenv(testPluginEnv); name != "" {
+		if starts := os.Getenv("INFRACOST_TEST_PLUGIN_STARTS"); starts != "" {
+			f, err := os.OpenFile(starts, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+			if err == nil {
+				fmt.Fprintln(f, "started")
+				f.Close()
+			}
+		}
+
+		plugin.Serve(testBuilders[name])
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+type testBuilder struct {
+	description *plugin.Description
+	build       func(d *plugin.ResourceData) (*plugin.Resource, error)
+}
+
+func (b *testBuilder) Describe() (*plugin.Description, error) {
+	if b.description == nil {
+		return nil, errors.New("missing config")
+	}
+
+	return b.description, nil
+}
+
+func (b *testBuilder) Build(d *plugin.ResourceData) (*plugin.Resource, error) {
+	return b.build(d)
+}
+
+func decimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+var testBuilders = map[string]*testBuilder{
+	"acme": {
+		description: &plugin.Description{Name: "acme", ResourceTypes: []string{"acme_database"}},
+		build: func(d *plugin.ResourceData) (*plugin.Resource, error) {
+			if gjson.GetBytes(d.Values, "size").String() != "large" {
+				return nil, errors.New("unknown size")
+			}
+
+			return &plugin.Resource{
+				Tags: map[string]string{"team": d.Tags["team"]},
+				CostComponents: []*plugin.CostComponent{
+					{Name: "Instance (large)", Unit: "hours", HourlyQuantity: decimalPtr(decimal.NewFromInt(1)), Price: decimalPtr(decimal.RequireFromString("0.5"))},
+					{
+						Name:            "Transfer",
+						Unit:            "GB",
+						MonthlyQuantity: decimalPtr(decimal.NewFromInt(gjson.GetBytes(d.Usage["monthly_transfer_gb"], "@this").Int())),
+						ProductFilter:   &plugin.ProductFilter{VendorName: strPtr("aws"), Service: strPtr("AWSDataTransfer")},
+						PriceFilter:     &plugin.PriceFilter{PurchaseOption: strPtr("on_demand")},
+					},
+				},
+				SubResources: []*plugin.Resource{
+					{
+						Name:         "Backups",
+						ResourceType: "acme_database_backup",
+						CostComponents: []*plugin.CostComponent{
+							{Name: "Storage", Unit: "GB", MonthlyQuantity: decimalPtr(decimal.NewFromInt(100)), Price: decimalPtr(decimal.RequireFromString("0.02"))},
+						},
+					},
+				},
+				UsageSchema: []*plugin.UsageItem{
+					{Key: "monthly_transfer_gb", ValueType: plugin.Int64, DefaultValue: int64(0), Description: "Monthly data transfer in GB"},
+				},
+			}, nil
+		},
+	},
+	"no resource types": {
+		description: &plugin.Description{},
+	},
+	"describe error": {},
+	"invalid cost component": {
+		description: &plugin.Description{ResourceTypes: []string{"acme_database"}},
+		build: func(d *plugin.ResourceData) (*plugin.Resource, error) {
+			return &plugin.Resource{CostComponents: []*plugin.CostComponent{
+				{Name: "Instance", Unit: "hours", HourlyQuantity: decimalPtr(decimal.NewFromInt(1))},
+			}}, nil
+		},
+	},
+	"slow": {
+		description: &plugin.Description{ResourceTypes: []string{"acme_database"}},
+		build: func(d *plugin.ResourceData) (*plugin.Resource, error) {
+			time.Sleep(5 * time.Second)
+			return &plugin.Resource{}, nil
+		},
+	},
+	"built-in": {
+		description: &plugin.Description{ResourceTypes: []string{"acme_queue", "aws_instance"}},
+	},
+}
+
+// writePlugin returns the path of an acme-plugin executable that serves the
+// test builder with name. The executable is a link to the test binary, which
+// is run as the plugin since the test env is inherited by the plugin process.
+func writePlugin(t *testing.T, name string) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin links need a POSIX file system")
+	}
+
+	t.Setenv(testPluginEnv, name)
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "acme-plugin")
+	require.NoError(t, os.Symlink(exe, path))
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	p, err := Load(writePlugin(t, "acme"))
+	require.NoError(t, err)
+	defer p.Close()
+
+	assert.Equal(t, "acme", p.Name)
+	assert.Equal(t, []string{"acme_database"}, p.ResourceTypes)
+
+	items := p.RegistryItems()
+	require.Len(t, items, 1)
+	assert.Equal(t, "acme_database", items[0].Name)
+	assert.NotNil(t, items[0].RFunc)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  func(t *testing.T) string
+		error string
+	}{
+		{
+			name:  "no resource types",
+			path:  func(t *testing.T) string { return writePlugin(t, "no resource types") },
+			error: "doesn't build the costs of any resource types",
+		},
+		{
+			name:  "describe error",
+			path:  func(t *testing.T) string { return writePlugin(t, "describe error") },
+			error: "missing config",
+		},
+		{
+			name: "not a plugin",
+			path: func(t *testing.T) string {
+				if runtime.GOOS == "windows" {
+					t.Skip("plugin scripts need a POSIX shell")
+				}
+
+				path := filepath.Join(t.TempDir(), "acme-plugin")
+				require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\necho 'missing license' >&2\nexit 1\n"), 0700))
+				return path
+			},
+			error: "missing license",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.path(t))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.error)
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	p, err := Load(writePlugin(t, "acme"))
+	require.NoError(t, err)
+	defer p.Close()
+
+	d := schema.NewResourceData("acme_database", "registry.terraform.io/acme/acme", "acme_database.main", map[string]string{"team": "data"}, gjson.Parse(`{"size": "large"}`))
+	u := schema.NewUsageData("acme_database.main", map[string]gjson.Result{"monthly_transfer_gb": gjson.Parse("20")})
+
+	r, err := p.Build(d, u)
+	require.NoError(t, err)
+
+	assert.Equal(t, "acme_database.main", r.Name)
+	assert.Equal(t, "acme_database", r.ResourceType)
+	assert.Equal(t, map[string]string{"team": "data"}, r.Tags)
+	require.Len(t, r.CostComponents, 2)
+
+	instance := r.CostComponents[0]
+	assert.Equal(t, "Instance (large)", instance.Name)
+	assert.True(t, decimal.NewFromInt(1).Equal(instance.UnitMultiplier))
+	require.NotNil(t, instance.CustomPrice())
+	assert.Equal(t, "0.5", instance.CustomPrice().String())
+
+	transfer := r.CostComponents[1]
+	assert.Nil(t, transfer.CustomPrice())
+	assert.Equal(t, "20", transfer.MonthlyQuantity.String())
+	require.NotNil(t, transfer.ProductFilter)
+	assert.Equal(t, "AWSDataTransfer", *transfer.ProductFilter.Service)
+	require.NotNil(t, transfer.PriceFilter)
+	assert.Equal(t, "on_demand", *transfer.PriceFilter.PurchaseOption)
+
+	require.Len(t, r.SubResources, 1)
+	assert.Equal(t, "Backups", r.SubResources[0].Name)
+	assert.Equal(t, "acme_database_backup", r.SubResources[0].ResourceType)
+	assert.Equal(t, "0.02", r.SubResources[0].CostComponents[0].CustomPrice().String())
+
+	require.Len(t, r.UsageSchema, 1)
+	assert.Equal(t, &schema.UsageItem{Key: "monthly_transfer_gb", ValueType: schema.Int64, DefaultValue: int64(0), Description: "Monthly data transfer in GB"}, r.UsageSchema[0])
+
+	d = schema.NewResourceData("acme_database", "registry.terraform.io/acme/acme", "acme_database.other", nil, gjson.Parse(`{"size": "tiny"}`))
+
+	_, err = p.Build(d, nil)
+	assert.EqualError(t, err, "unknown size")
+
+	r = p.RegistryItems()[0].RFunc(d, nil)
+	assert.True(t, r.IsSkipped)
+	assert.Equal(t, "The acme plugin could not build this resource", r.SkipMessage)
+}
+
+func TestBuildInvalidCostComponent(t *testing.T) {
+	p, err := Load(writePlugin(t, "invalid cost component"))
+	require.NoError(t, err)
+	defer p.Close()
+	assert.Equal(t, "acme-plugin", p.Name)
+
+	d := schema.NewResourceData("acme_database", "registry.terraform.io/acme/acme", "acme_database.main", nil, gjson.Parse(`{}`))
+
+	_, err = p.Build(d, nil)
+	assert.EqualError(t, err, `cost component "Instance" of acme_database.main: it must have a price or a product filter`)
+}
+
+func TestBuildOneProcess(t *testing.T) {
+	starts := filepath.Join(t.TempDir(), "starts")
+	t.Setenv("INFRACOST_TEST_PLUGIN_STARTS", starts)
+
+	p, err := Load(writePlugin(t, "acme"))
+	require.NoError(t, err)
+
+	for _, address := range []string{"acme_database.a", "acme_database.b", "acme_database.c"} {
+		d := schema.NewResourceData("acme_database", "registry.terraform.io/acme/acme", address, nil, gjson.Parse(`{"size": "large"}`))
+
+		r, err := p.Build(d, nil)
+		require.NoError(t, err)
+		assert.Equal(t, address, r.Name)
+	}
+
+	p.Close()
+
+	b, err := os.ReadFile(starts)
+	require.NoError(t, err)
+	assert.Equal(t, "started\n", string(b))
+
+	d := schema.NewResourceData("acme_database", "registry.terraform.io/acme/acme", "acme_database.d", nil, gjson.Parse(`{"size": "large"}`))
+	_, err = p.Build(d, nil)
+	assert.EqualError(t, err, "plugin is closed")
+}
+
+func TestBuildTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { Timeout = timeout }(Timeout)
+
+	p, err := Load(writePlugin(t, "slow"))
+	require.NoError(t, err)
+	defer p.Close()
+
+	Timeout = 200 * time.Millisecond
+
+	d := schema.NewResourceData("acme_database", "registry.terraform.io/acme/acme", "acme_database.main", nil, gjson.Parse(`{}`))
+
+	_, err = p.Build(d, nil)
+	assert.EqualError(t, err, "timed out after 200ms")
+
+	_, err = p.Build(d, nil)
+	assert.EqualError(t, err, "timed out after 200ms")
+}
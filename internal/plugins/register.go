@@ -0,0 +1,109 @@
+package plugins
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// Register loads the resource mappings and plugin executables of the config of
+// ctx and registers the resource types that they build the costs of for the
+// run, see terraform.GetRunResourceRegistryMap. Plugins are registered last so
+// they replace mappings of the same resource types. Mappings and plugins can't
+// replace the resource types that Infracost supports.
+//
+// It returns a hash of the mappings and of the paths and contents of the
+// plugins, so resources cached in previous runs aren't used once they'd be
+// built differently, and a func that closes the plugins, which must be
+// called once the resources of the run have been built.
+func Register(ctx *config.RunContext) (string, func(), error) {
+	cfg := ctx.Config
+	h := sha256.New()
+	registryMap := *terraform.GetResourceRegistryMap()
+
+	if cfg.ResourceMappings != "" {
+		mappings, err := LoadMappings(cfg.ResourceMappings)
+		if err != nil {
+			return "", nil, err
+		}
+
+		b, err := json.Marshal(mappings)
+		if err != nil {
+			return "", nil, err
+		}
+		h.Write(b)
+
+		items := make([]*schema.RegistryItem, 0, len(mappings))
+		for _, m := range mappings {
+			items = append(items, m.RegistryItem())
+		}
+		registryMap, err = registryMap.WithResources(items)
+		if err != nil {
+			return "", nil, fmt.Errorf("Error registering resource mappings %s: %w", cfg.ResourceMappings, err)
+		}
+
+		logging.Logger.Debugf("Loaded resource mappings for %d resource types", len(mappings))
+	}
+
+	var loaded []*Plugin
+	closePlugins := func() {
+		for _, p := range loaded {
+			p.Close()
+		}
+	}
+
+	for _, path := range cfg.Plugins {
+		p, err := Load(path)
+		if err != nil {
+			closePlugins()
+			return "", nil, err
+		}
+		loaded = append(loaded, p)
+
+		contentHash, err := fileHash(path)
+		if err != nil {
+			closePlugins()
+			return "", nil, fmt.Errorf("Error reading plugin %s: %w", path, err)
+		}
+		fmt.Fprintf(h, "\n%s %s", path, contentHash)
+
+		registryMap, err = registryMap.WithResources(p.RegistryItems())
+		if err != nil {
+			closePlugins()
+			return "", nil, fmt.Errorf("Error registering plugin %s: %w", path, err)
+		}
+
+		logging.Logger.Debugf("Loaded plugin %s for %d resource types", p.Name, len(p.ResourceTypes))
+	}
+
+	if cfg.ResourceMappings != "" || len(cfg.Plugins) > 0 {
+		ctx.ResourceRegistry = registryMap
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), closePlugins, nil
+}
+
+// fileHash returns the hash of the contents of the executable at path, which
+// is looked up in the PATH if it's only a name.
+func fileHash(path string) (string, error) {
+	path, err := exec.LookPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
@@ -0,0 +1,55 @@
+package plugins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/terraform"
+)
+
+func runContext(cfg *config.Config) *config.RunContext {
+	ctx := config.EmptyRunContext()
+	ctx.Config = cfg
+	return ctx
+}
+
+func TestRegister(t *testing.T) {
+	ctx := runContext(&config.Config{
+		ResourceMappings: writeMappings(t, map[string]string{"acme.yml": acmeMappings}),
+		Plugins:          []string{writePlugin(t, "acme")},
+	})
+
+	hash, closePlugins, err := Register(ctx)
+	require.NoError(t, err)
+	defer closePlugins()
+
+	assert.NotEmpty(t, hash)
+
+	item := (*terraform.GetRunResourceRegistryMap(ctx))["acme_database"]
+	require.NotNil(t, item)
+	assert.Equal(t, []string{"Costs are built by the acme plugin."}, item.Notes)
+	assert.NotNil(t, (*terraform.GetRunResourceRegistryMap(ctx))["aws_instance"])
+
+	// The resource types are only registered for the run
+	assert.Nil(t, (*terraform.GetResourceRegistryMap())["acme_database"])
+	assert.Nil(t, (*terraform.GetRunResourceRegistryMap(config.EmptyRunContext()))["acme_database"])
+}
+
+func TestRegisterBuiltInResourceType(t *testing.T) {
+	path := writePlugin(t, "built-in")
+
+	ctx := runContext(&config.Config{Plugins: []string{path}})
+	_, _, err := Register(ctx)
+	assert.EqualError(t, err, "Error registering plugin "+path+": built-in resource types can't be replaced: aws_instance")
+	assert.Nil(t, (*terraform.GetRunResourceRegistryMap(ctx))["acme_queue"])
+
+	dir := writeMappings(t, map[string]string{
+		"aws.yml": "version: 0.1\nresources:\n  - type: aws_instance\n    cost_components:\n      - name: Instance\n        unit: hours\n        hourly_quantity: 1\n        price: 1\n",
+	})
+
+	_, _, err = Register(runContext(&config.Config{ResourceMappings: dir}))
+	assert.EqualError(t, err, "Error registering resource mappings "+dir+": built-in resource types can't be replaced: aws_instance")
+}
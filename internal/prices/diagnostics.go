@@ -0,0 +1,87 @@
+package prices
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// diagnosticsMu stops the diagnostics of cost components that are priced
+// concurrently from being interleaved.
+var diagnosticsMu sync.Mutex
+
+// writePriceDiagnostic writes how the price of c was resolved from the lookup
+// result res to the error writer of the run, if pricing is being debugged. The
+// outcome explains which price was used or why none was.
+func writePriceDiagnostic(ctx *config.RunContext, currency string, r *schema.Resource, c *schema.CostComponent, res gjson.Result, outcome string) {
+	if !ctx.Config.DebugPricing || ctx.ErrWriter == nil {
+		return
+	}
+
+	s := formatPriceDiagnostic(currency, r, c, res, outcome)
+
+	diagnosticsMu.Lock()
+	defer diagnosticsMu.Unlock()
+
+	fmt.Fprint(ctx.ErrWriter, s)
+}
+
+func formatPriceDiagnostic(currency string, r *schema.Resource, c *schema.CostComponent, res gjson.Result, outcome string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Price lookup for %s %s\n", r.Name, c.Name)
+	fmt.Fprintf(&b, "  Product filter: %s\n", filterJSON(c.ProductFilter))
+	fmt.Fprintf(&b, "  Price filter:   %s\n", filterJSON(c.PriceFilter))
+
+	products := res.Get("data.products").Array()
+	fmt.Fprintf(&b, "  Products:       %d found, %d with prices\n", len(products), len(productsWithPrices(res)))
+
+	for i, product := range products {
+		prices := product.Get("prices").Array()
+		if len(prices) == 0 {
+			fmt.Fprintf(&b, "    %d. no prices match the price filter\n", i+1)
+			continue
+		}
+
+		for _, p := range prices {
+			fmt.Fprintf(&b, "    %d. %s %s, price hash %s\n", i+1, p.Get(currency).String(), currency, p.Get("priceHash").String())
+		}
+	}
+
+	fmt.Fprintf(&b, "  Result:         %s\n", outcome)
+
+	return b.String()
+}
+
+// chosenPriceReason explains why the price p was used when there are
+// productCount products with prices and the first one has priceCount prices.
+func chosenPriceReason(p decimal.Decimal, priceHash string, productCount int, priceCount int) string {
+	reason := fmt.Sprintf("using price %s with price hash %s", p, priceHash)
+
+	switch {
+	case productCount > 1:
+		reason += fmt.Sprintf(", the first of %d products with prices as the filters match more than one", productCount)
+	case priceCount > 1:
+		reason += fmt.Sprintf(", the first of %d prices as the price filter matches more than one", priceCount)
+	default:
+		reason += ", the only price that matches the filters"
+	}
+
+	return reason
+}
+
+func filterJSON(filter interface{}) string {
+	b, err := json.Marshal(filter)
+	if err != nil {
+		return fmt.Sprintf("%v", filter)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,54 @@
+package prices
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestPriceDiagnostics(t *testing.T) {
+	component := func(purchaseOption string) *schema.CostComponent {
+		return &schema.CostComponent{
+			Name:          "Instance usage (" + purchaseOption + ")",
+			ProductFilter: &schema.ProductFilter{VendorName: strPtr("aws"), Service: strPtr("AmazonEC2")},
+			PriceFilter:   &schema.PriceFilter{PurchaseOption: strPtr(purchaseOption)},
+		}
+	}
+
+	var out bytes.Buffer
+	ctx := config.EmptyRunContext()
+	ctx.ErrWriter = &out
+	ctx.Config.DebugPricing = true
+
+	s := &fakePriceSource{prices: map[string]string{"on_demand": "0.1"}}
+	r := &schema.Resource{Name: "aws_instance.web", CostComponents: []*schema.CostComponent{component("on_demand"), component("reserved")}}
+
+	err := GetPrices(ctx, s, r)
+	require.NoError(t, err)
+
+	expected := `Price lookup for aws_instance.web Instance usage (on_demand)
+  Product filter: {"vendorName":"aws","service":"AmazonEC2"}
+  Price filter:   {"purchaseOption":"on_demand"}
+  Products:       1 found, 1 with prices
+    1. 0.1 USD, price hash hash
+  Result:         using price 0.1 with price hash hash, the only price that matches the filters
+Price lookup for aws_instance.web Instance usage (reserved)
+  Product filter: {"vendorName":"aws","service":"AmazonEC2"}
+  Price filter:   {"purchaseOption":"reserved"}
+  Products:       0 found, 0 with prices
+  Result:         no products match the product filter, using 0.00
+`
+	assert.Equal(t, expected, out.String())
+
+	out.Reset()
+	ctx.Config.DebugPricing = false
+
+	err = GetPrices(ctx, s, r)
+	require.NoError(t, err)
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,138 @@
+package prices
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// discountServiceGroups are the groups of services that a discount can be set
+// for, by vendor. Services that aren't in a group can still be discounted with
+// their name in the pricing API.
+var discountServiceGroups = map[string]map[string][]string{
+	"compute": {
+		"aws":   {"AmazonEC2", "AWSLambda", "AmazonECS", "AmazonEKS"},
+		"azure": {"Virtual Machines", "Azure App Service", "Functions", "Container Instances"},
+		"gcp":   {"Compute Engine", "Cloud Run", "Cloud Functions", "Kubernetes Engine"},
+	},
+	"storage": {
+		"aws":   {"AmazonS3", "AmazonEFS", "AmazonS3GlacierDeepArchive"},
+		"azure": {"Storage"},
+		"gcp":   {"Cloud Storage"},
+	},
+	"database": {
+		"aws":   {"AmazonRDS", "AmazonDynamoDB", "AmazonElastiCache", "AmazonDocDB", "AmazonNeptune", "AmazonRedshift"},
+		"azure": {"SQL Database", "SQL Managed Instance", "Azure Cosmos DB", "Azure Database for MySQL", "Azure Database for PostgreSQL", "Redis Cache"},
+		"gcp":   {"Cloud SQL", "Cloud Spanner", "Cloud Bigtable", "Cloud Memorystore for Redis"},
+	},
+}
+
+// ApplyDiscounts adds a cost component to each resource of project for each
+// discount that applies to its cost components. The discount cost component
+// has the negative discounted amount as its price for one month, so it is
+// reflected in the totals of the resource and project. A cost component is
+// only discounted by the first discount that matches it, and cost components
+// whose cost is shared with another resource aren't discounted, since their
+// cost is only counted once. It must be called after the prices have been
+// populated and before the costs are calculated.
+func ApplyDiscounts(project *schema.Project, discounts []config.Discount) {
+	if len(discounts) == 0 {
+		return
+	}
+
+	// The shared costs are deduplicated first so that only the resource that's
+	// attributed a shared cost gets its discount.
+	schema.DeduplicateSharedCosts(project)
+
+	// The past and current resources of a project can share the same resources
+	discounted := make(map[*schema.Resource]bool)
+
+	for _, r := range project.AllResources() {
+		if r.IsSkipped || r.NoPrice || discounted[r] {
+			continue
+		}
+		discounted[r] = true
+
+		applyResourceDiscounts(r, discounts)
+	}
+}
+
+func applyResourceDiscounts(r *schema.Resource, discounts []config.Discount) {
+	amounts := make([]decimal.Decimal, len(discounts))
+
+	costComponents := append([]*schema.CostComponent{}, r.CostComponents...)
+	for _, s := range r.FlattenedSubResources() {
+		costComponents = append(costComponents, s.CostComponents...)
+	}
+
+	for _, c := range costComponents {
+		i := matchingDiscount(c, discounts)
+		if i == -1 {
+			continue
+		}
+
+		c.CalculateCosts()
+		if c.MonthlyCost == nil {
+			continue
+		}
+
+		amounts[i] = amounts[i].Add(c.MonthlyCost.Mul(discounts[i].Percent).Div(decimal.NewFromInt(100)))
+	}
+
+	for i, d := range discounts {
+		if amounts[i].IsZero() {
+			continue
+		}
+
+		q := decimal.NewFromInt(1)
+
+		c := &schema.CostComponent{
+			Name:            fmt.Sprintf("Discount (%s %s%%)", d.Label(), d.Percent),
+			Unit:            "months",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: &q,
+		}
+		c.SetPrice(amounts[i].Neg())
+
+		r.CostComponents = append(r.CostComponents, c)
+	}
+}
+
+// matchingDiscount returns the index of the first discount that applies to c,
+// or -1 if none do.
+func matchingDiscount(c *schema.CostComponent, discounts []config.Discount) int {
+	if c.ProductFilter == nil || c.ProductFilter.VendorName == nil {
+		return -1
+	}
+
+	vendor := *c.ProductFilter.VendorName
+	service := ""
+	if c.ProductFilter.Service != nil {
+		service = *c.ProductFilter.Service
+	}
+
+	for i, d := range discounts {
+		if d.Vendor != vendor {
+			continue
+		}
+
+		if d.Service == "" || strings.EqualFold(d.Service, service) || containsFold(discountServiceGroups[strings.ToLower(d.Service)][vendor], service) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func containsFold(arr []string, e string) bool {
+	for _, a := range arr {
+		if strings.EqualFold(a, e) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package prices
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestApplyDiscounts(t *testing.T) {
+	component := func(vendor, service string, monthlyQuantity int64, price string) *schema.CostComponent {
+		q := decimal.NewFromInt(monthlyQuantity)
+		c := &schema.CostComponent{
+			Name:            service,
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: &q,
+			ProductFilter:   &schema.ProductFilter{VendorName: strPtr(vendor), Service: strPtr(service)},
+		}
+		c.SetPrice(decimal.RequireFromString(price))
+		return c
+	}
+
+	vm := &schema.Resource{
+		Name: "azurerm_linux_virtual_machine.vm",
+		CostComponents: []*schema.CostComponent{
+			component("azure", "Virtual Machines", 730, "0.1"),
+		},
+		SubResources: []*schema.Resource{
+			{
+				Name:           "os_disk",
+				CostComponents: []*schema.CostComponent{component("azure", "Storage", 1, "10")},
+			},
+		},
+	}
+	instance := &schema.Resource{
+		Name: "aws_instance.web",
+		CostComponents: []*schema.CostComponent{
+			component("aws", "AmazonEC2", 730, "0.2"),
+			// Usage-based cost components without usage aren't discounted
+			{Name: "Usage", ProductFilter: &schema.ProductFilter{VendorName: strPtr("aws"), Service: strPtr("AmazonEC2")}},
+		},
+	}
+	google := &schema.Resource{
+		Name:           "google_storage_bucket.bucket",
+		CostComponents: []*schema.CostComponent{component("gcp", "Cloud Storage", 100, "0.02")},
+	}
+
+	project := &schema.Project{
+		Resources:     []*schema.Resource{vm, instance, google},
+		PastResources: []*schema.Resource{vm},
+	}
+
+	ApplyDiscounts(project, []config.Discount{
+		{Vendor: "aws", Percent: decimal.NewFromInt(12)},
+		{Vendor: "azure", Service: "compute", Percent: decimal.NewFromInt(20)},
+		{Vendor: "azure", Percent: decimal.NewFromInt(5)},
+	})
+	schema.CalculateCosts(project)
+
+	require.Len(t, vm.CostComponents, 3)
+	assert.Equal(t, "Discount (azure compute 20%)", vm.CostComponents[1].Name)
+	assert.Equal(t, "-14.6", vm.CostComponents[1].MonthlyCost.String())
+	assert.Equal(t, "Discount (azure 5%)", vm.CostComponents[2].Name)
+	assert.Equal(t, "-0.5", vm.CostComponents[2].MonthlyCost.String())
+	assert.Equal(t, "67.9", vm.MonthlyCost.String())
+
+	require.Len(t, instance.CostComponents, 3)
+	assert.Equal(t, "Discount (aws 12%)", instance.CostComponents[2].Name)
+	assert.Equal(t, "128.48", instance.MonthlyCost.String())
+
+	assert.Len(t, google.CostComponents, 1)
+}
+
+func TestApplyDiscountsSharedCosts(t *testing.T) {
+	hours := func() *schema.CostComponent {
+		q := decimal.NewFromInt(1)
+		c := &schema.CostComponent{
+			Name:           "Application load balancer",
+			UnitMultiplier: decimal.NewFromInt(1),
+			HourlyQuantity: &q,
+			ProductFilter:  &schema.ProductFilter{VendorName: strPtr("aws"), Service: strPtr("AWSELB")},
+			SharedCostKey:  "aws_lb.lb",
+		}
+		c.SetPrice(decimal.RequireFromString("0.1"))
+		return c
+	}
+
+	lb := &schema.Resource{Name: "aws_lb.lb", CostComponents: []*schema.CostComponent{hours()}}
+	http := &schema.Resource{Name: "aws_lb_listener.http", CostComponents: []*schema.CostComponent{hours()}}
+	https := &schema.Resource{Name: "aws_lb_listener.https", CostComponents: []*schema.CostComponent{hours()}}
+
+	project := &schema.Project{Resources: []*schema.Resource{http, https, lb}}
+
+	ApplyDiscounts(project, []config.Discount{{Vendor: "aws", Percent: decimal.NewFromInt(10)}})
+	schema.CalculateCosts(project)
+
+	require.Len(t, lb.CostComponents, 2)
+	assert.Equal(t, "-7.3", lb.CostComponents[1].MonthlyCost.String())
+	assert.Len(t, http.CostComponents, 1)
+	assert.Len(t, https.CostComponents, 1)
+	assert.Equal(t, "65.7", lb.MonthlyCost.Add(*http.MonthlyCost).Add(*https.MonthlyCost).String())
+}
+
+func TestApplyDiscountsWithFreeTier(t *testing.T) {
+	q := decimal.NewFromInt(3000000)
+	requests := &schema.CostComponent{
+		Name:            "Requests",
+		UnitMultiplier:  decimal.NewFromInt(1000000),
+		MonthlyQuantity: &q,
+		ProductFilter:   &schema.ProductFilter{VendorName: strPtr("aws"), Service: strPtr("AWSLambda")},
+	}
+	requests.SetPrice(decimal.RequireFromString("0.0000002"))
+
+	lambda := &schema.Resource{
+		Name:           "aws_lambda_function.a",
+		ResourceType:   "aws_lambda_function",
+		CostComponents: []*schema.CostComponent{requests},
+	}
+	project := &schema.Project{Resources: []*schema.Resource{lambda}}
+
+	ApplyFreeTier(project)
+	ApplyDiscounts(project, []config.Discount{{Vendor: "aws", Service: "compute", Percent: decimal.NewFromInt(10)}})
+	schema.CalculateCosts(project)
+
+	// The discount applies to the cost net of the free tier
+	require.Len(t, lambda.CostComponents, 3)
+	assert.Equal(t, "-0.04", lambda.CostComponents[2].MonthlyCost.String())
+	assert.Equal(t, "0.36", lambda.MonthlyCost.String())
+}
@@ -0,0 +1,156 @@
+package prices
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const (
+	// ExchangeRatesSourceECB uses the daily reference rates of the European
+	// Central Bank.
+	ExchangeRatesSourceECB = "ecb"
+
+	exchangeRatesFileVersion = "0.1"
+)
+
+// ExchangeRateSource converts the USD prices of the pricing API to the currency
+// of the output.
+type ExchangeRateSource interface {
+	// Rate returns the amount of currency that one US dollar converts to.
+	Rate(currency string) (decimal.Decimal, error)
+}
+
+// NewExchangeRateSource returns the ExchangeRateSource for source, which is
+// either ecb or the path to an exchange rates file.
+func NewExchangeRateSource(source string) (ExchangeRateSource, error) {
+	if strings.EqualFold(source, ExchangeRatesSourceECB) {
+		return apiclient.NewECBExchangeRatesClient(), nil
+	}
+
+	return LoadExchangeRates(source)
+}
+
+// ExchangeRates are fixed exchange rates loaded from an exchange rates file.
+// Each rate is the amount of the currency that one unit of the base currency
+// converts to. The base currency defaults to USD:
+//
+//	version: 0.1
+//	base: USD
+//	rates:
+//	  EUR: 0.92
+//	  GBP: 0.79
+type ExchangeRates struct {
+	Version string                     `yaml:"version"`
+	Base    string                     `yaml:"base"`
+	Rates   map[string]decimal.Decimal `yaml:"rates"`
+}
+
+// LoadExchangeRates loads the exchange rates file at path.
+func LoadExchangeRates(path string) (*ExchangeRates, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading exchange rates file")
+	}
+
+	var r ExchangeRates
+	err = yamlv3.Unmarshal(contents, &r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing exchange rates file")
+	}
+
+	err = r.validate()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error loading exchange rates file")
+	}
+
+	return &r, nil
+}
+
+func (r *ExchangeRates) validate() error {
+	if r.Version != exchangeRatesFileVersion {
+		return fmt.Errorf("invalid version %q, expected %s", r.Version, exchangeRatesFileVersion)
+	}
+
+	if r.Base == "" {
+		r.Base = "USD"
+	}
+
+	for currency, rate := range r.Rates {
+		if !rate.IsPositive() {
+			return fmt.Errorf("rate for %s must be positive", currency)
+		}
+	}
+
+	return nil
+}
+
+// Rate returns the amount of currency that one US dollar converts to.
+func (r *ExchangeRates) Rate(currency string) (decimal.Decimal, error) {
+	usd, err := r.baseRate("USD")
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	rate, err := r.baseRate(currency)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return rate.Div(usd), nil
+}
+
+func (r *ExchangeRates) baseRate(currency string) (decimal.Decimal, error) {
+	if currency == r.Base {
+		return decimal.NewFromInt(1), nil
+	}
+
+	rate, ok := r.Rates[currency]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("No exchange rate for %s in the exchange rates file", currency)
+	}
+
+	return rate, nil
+}
+
+// ConvertPrices converts the prices of the cost components of project from USD
+// with rate. It must be called after the prices have been populated and before
+// they are overridden, since price overrides are in the currency of the output.
+func ConvertPrices(project *schema.Project, rate decimal.Decimal) {
+	for _, r := range project.AllResources() {
+		resources := append([]*schema.Resource{r}, r.FlattenedSubResources()...)
+		resources = append(resources, r.RecommendationAlternatives()...)
+
+		for _, res := range resources {
+			for _, c := range res.CostComponents {
+				c.SetPrice(c.Price().Mul(rate))
+			}
+		}
+	}
+}
+
+// ConvertCurrency converts the USD prices of project to the currency of the run
+// with the exchange rates of s. Prices aren't converted if s is nil, in which
+// case the pricing API returns prices in the currency of the run.
+func ConvertCurrency(ctx *config.RunContext, s ExchangeRateSource, project *schema.Project) error {
+	currency := ctx.Config.Currency
+	if s == nil || currency == "" || currency == "USD" {
+		return nil
+	}
+
+	rate, err := s.Rate(currency)
+	if err != nil {
+		return errors.Wrapf(err, "Error converting prices to %s", currency)
+	}
+
+	ConvertPrices(project, rate)
+	return nil
+}
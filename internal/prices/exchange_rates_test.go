@@ -0,0 +1,123 @@
+package prices
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestLoadExchangeRates(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		currency string
+		rate     string
+		err      string
+	}{
+		{
+			name: "USD base",
+			contents: `version: 0.1
+rates:
+  EUR: 0.92
+`,
+			currency: "EUR",
+			rate:     "0.92",
+		},
+		{
+			name: "EUR base",
+			contents: `version: 0.1
+base: EUR
+rates:
+  USD: 1.25
+  GBP: 0.85
+`,
+			currency: "GBP",
+			rate:     "0.68",
+		},
+		{
+			name: "missing currency",
+			contents: `version: 0.1
+rates:
+  EUR: 0.92
+`,
+			currency: "GBP",
+			err:      "No exchange rate for GBP in the exchange rates file",
+		},
+		{
+			name:     "invalid version",
+			contents: "version: 0.2\n",
+			err:      `invalid version "0.2"`,
+		},
+		{
+			name: "negative rate",
+			contents: `version: 0.1
+rates:
+  EUR: -1
+`,
+			err: "rate for EUR must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "exchange-rates.yml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.contents), 0600))
+
+			r, err := LoadExchangeRates(path)
+			if err == nil {
+				var rate decimal.Decimal
+				rate, err = r.Rate(tt.currency)
+				if err == nil {
+					assert.Equal(t, tt.rate, rate.String())
+				}
+			}
+
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestConvertCurrency(t *testing.T) {
+	component := func(price string) *schema.CostComponent {
+		c := &schema.CostComponent{Name: "Instance usage"}
+		c.SetPrice(decimal.RequireFromString(price))
+		return c
+	}
+
+	instance := &schema.Resource{
+		Name:           "aws_instance.web",
+		CostComponents: []*schema.CostComponent{component("0.1")},
+		SubResources: []*schema.Resource{
+			{Name: "root_block_device", CostComponents: []*schema.CostComponent{component("0.08")}},
+		},
+	}
+	project := &schema.Project{
+		Resources:     []*schema.Resource{instance},
+		PastResources: []*schema.Resource{instance},
+	}
+
+	rates := &ExchangeRates{Version: "0.1", Base: "USD", Rates: map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.9")}}
+
+	ctx := &config.RunContext{Config: &config.Config{Currency: "USD"}}
+	require.NoError(t, ConvertCurrency(ctx, rates, project))
+	assert.Equal(t, "0.1", instance.CostComponents[0].Price().String())
+
+	ctx.Config.Currency = "EUR"
+	require.NoError(t, ConvertCurrency(ctx, rates, project))
+	assert.Equal(t, "0.09", instance.CostComponents[0].Price().String(), "resources in the past and current resources should only be converted once")
+	assert.Equal(t, "0.072", instance.SubResources[0].CostComponents[0].Price().String())
+
+	ctx.Config.Currency = "GBP"
+	assert.EqualError(t, ConvertCurrency(ctx, rates, project), "Error converting prices to GBP: No exchange rate for GBP in the exchange rates file")
+}
@@ -0,0 +1,186 @@
+package prices
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// freeTierAllowance is an always-free monthly allowance of a cloud provider.
+// The allowance is shared by all the matching cost components of a project,
+// e.g. the first 1M Lambda requests are free across all functions, not per
+// function.
+type freeTierAllowance struct {
+	resourceTypes []string
+	// subResource is the name of the sub resource that the cost components are
+	// part of, it is empty if they're part of the resource itself.
+	subResource    string
+	costComponents []string
+	// quantity is in the units of the cost components' MonthlyQuantity.
+	quantity decimal.Decimal
+}
+
+var freeTierAllowances = []freeTierAllowance{
+	{
+		resourceTypes:  []string{"aws_lambda_function"},
+		costComponents: []string{"Requests"},
+		quantity:       decimal.NewFromInt(1000000),
+	},
+	{
+		// The x86 and Arm architectures share the same allowance
+		resourceTypes:  []string{"aws_lambda_function"},
+		costComponents: []string{"Duration (first 6B)", "Duration (first 7.5B)"},
+		quantity:       decimal.NewFromInt(400000),
+	},
+	{
+		resourceTypes:  []string{"aws_sqs_queue"},
+		costComponents: []string{"Requests"},
+		quantity:       decimal.NewFromInt(1000000),
+	},
+	{
+		resourceTypes:  []string{"aws_s3_bucket"},
+		subResource:    "Standard",
+		costComponents: []string{"Storage"},
+		quantity:       decimal.NewFromInt(5),
+	},
+	{
+		resourceTypes:  []string{"aws_dynamodb_table"},
+		costComponents: []string{"Data storage"},
+		quantity:       decimal.NewFromInt(25),
+	},
+	{
+		// Executions are priced per 10 executions, so this is 1M executions
+		resourceTypes:  []string{"azurerm_function_app", "azurerm_linux_function_app", "azurerm_windows_function_app"},
+		costComponents: []string{"Executions"},
+		quantity:       decimal.NewFromInt(100000),
+	},
+	{
+		resourceTypes:  []string{"azurerm_function_app", "azurerm_linux_function_app", "azurerm_windows_function_app"},
+		costComponents: []string{"Execution time"},
+		quantity:       decimal.NewFromInt(400000),
+	},
+	{
+		resourceTypes:  []string{"google_cloudfunctions_function"},
+		costComponents: []string{"Invocations"},
+		quantity:       decimal.NewFromInt(2000000),
+	},
+	{
+		resourceTypes:  []string{"google_cloudfunctions_function"},
+		costComponents: []string{"CPU"},
+		quantity:       decimal.NewFromInt(200000),
+	},
+	{
+		resourceTypes:  []string{"google_cloudfunctions_function"},
+		costComponents: []string{"Memory"},
+		quantity:       decimal.NewFromInt(400000),
+	},
+}
+
+// ApplyFreeTier deducts the always-free monthly allowances of the cloud
+// providers from the resources of project, so that costs are shown net of the
+// free tier. Each deduction is added as a cost component with a negative
+// quantity after the cost component it is deducted from. It must be called after
+// the prices have been populated and before the costs are calculated.
+func ApplyFreeTier(project *schema.Project) {
+	deductions := make(map[*schema.CostComponent]*schema.CostComponent)
+
+	applyFreeTier(project.Resources, deductions)
+	applyFreeTier(project.PastResources, deductions)
+}
+
+// applyFreeTier deducts the allowances from resources. The past and current
+// resources of a project can share the same resources, deductions holds the
+// deductions that have already been added to them so they aren't added twice.
+func applyFreeTier(resources []*schema.Resource, deductions map[*schema.CostComponent]*schema.CostComponent) {
+	// Sort the resources so the allowances are always deducted in the same order
+	sorted := make([]*schema.Resource, len(resources))
+	copy(sorted, resources)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	for _, allowance := range freeTierAllowances {
+		remaining := allowance.quantity
+
+		for _, r := range sorted {
+			if !containsString(allowance.resourceTypes, r.ResourceType) {
+				continue
+			}
+
+			for _, res := range allowance.matchingResources(r) {
+				for i := 0; i < len(res.CostComponents); i++ {
+					c := res.CostComponents[i]
+					if !containsString(allowance.costComponents, c.Name) {
+						continue
+					}
+
+					if d, ok := deductions[c]; ok {
+						remaining = remaining.Add(*d.MonthlyQuantity)
+						continue
+					}
+
+					if !remaining.IsPositive() || c.MonthlyQuantity == nil || !c.MonthlyQuantity.IsPositive() || c.SharedWith() != "" {
+						continue
+					}
+
+					quantity := decimal.Min(remaining, *c.MonthlyQuantity)
+					remaining = remaining.Sub(quantity)
+
+					d := freeTierCostComponent(c, quantity)
+					deductions[c] = d
+
+					res.CostComponents = append(res.CostComponents[:i+1], append([]*schema.CostComponent{d}, res.CostComponents[i+1:]...)...)
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (a freeTierAllowance) matchingResources(r *schema.Resource) []*schema.Resource {
+	if a.subResource == "" {
+		return []*schema.Resource{r}
+	}
+
+	var matching []*schema.Resource
+	for _, s := range r.SubResources {
+		if s.Name == a.subResource {
+			matching = append(matching, s)
+		}
+	}
+
+	return matching
+}
+
+// freeTierCostComponent returns the cost component that deducts quantity of c
+// at the price of c.
+func freeTierCostComponent(c *schema.CostComponent, quantity decimal.Decimal) *schema.CostComponent {
+	q := quantity.Neg()
+
+	d := &schema.CostComponent{
+		Name:                fmt.Sprintf("Free tier (%s)", c.Name),
+		Unit:                c.Unit,
+		UnitMultiplier:      c.UnitMultiplier,
+		MonthlyQuantity:     &q,
+		MonthlyDiscountPerc: c.MonthlyDiscountPerc,
+		UsageBased:          c.UsageBased,
+		// Keep the product of c so that the deduction gets the same discounts
+		ProductFilter: c.ProductFilter,
+	}
+	d.SetPrice(c.Price())
+	d.SetPriceHash(c.PriceHash())
+
+	return d
+}
+
+func containsString(arr []string, e string) bool {
+	for _, a := range arr {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
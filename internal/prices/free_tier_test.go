@@ -0,0 +1,50 @@
+package prices
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestApplyFreeTier(t *testing.T) {
+	lambda := func(name string, requests int64) *schema.Resource {
+		q := decimal.NewFromInt(requests)
+		c := &schema.CostComponent{
+			Name:            "Requests",
+			Unit:            "1M requests",
+			UnitMultiplier:  decimal.NewFromInt(1000000),
+			MonthlyQuantity: &q,
+		}
+		c.SetPrice(decimal.RequireFromString("0.0000002"))
+
+		return &schema.Resource{
+			Name:           name,
+			ResourceType:   "aws_lambda_function",
+			CostComponents: []*schema.CostComponent{c},
+		}
+	}
+
+	first := lambda("aws_lambda_function.a", 600000)
+	second := lambda("aws_lambda_function.b", 600000)
+	project := &schema.Project{
+		Resources:     []*schema.Resource{second, first},
+		PastResources: []*schema.Resource{first},
+	}
+
+	ApplyFreeTier(project)
+	schema.CalculateCosts(project)
+
+	// The allowance is shared between the functions and deducted in name order
+	require.Len(t, first.CostComponents, 2)
+	assert.Equal(t, "Free tier (Requests)", first.CostComponents[1].Name)
+	assert.True(t, decimal.NewFromInt(-600000).Equal(*first.CostComponents[1].MonthlyQuantity))
+	assert.True(t, decimal.Zero.Equal(*first.MonthlyCost))
+
+	require.Len(t, second.CostComponents, 2)
+	assert.True(t, decimal.NewFromInt(-400000).Equal(*second.CostComponents[1].MonthlyQuantity))
+	assert.True(t, decimal.RequireFromString("0.04").Equal(*second.MonthlyCost))
+}
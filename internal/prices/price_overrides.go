@@ -0,0 +1,130 @@
+package prices
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const priceOverridesFileVersion = "0.1"
+
+// PriceOverrides are fixed unit prices that replace the prices from the pricing
+// API, e.g. to reflect the rates that an organization negotiated with a cloud
+// provider. They are loaded from a price overrides file:
+//
+//	version: 0.1
+//	price_overrides:
+//	  - resource_type: aws_instance
+//	    cost_component: Instance usage (Linux/UNIX, on-demand, m5.large)
+//	    price: 0.08
+//	  - price_hash: 3a4c8a1b8f3d1f2e6f3e5bd4f1c0d7f3-d2c98780d7b6e36641b521f1f8145c6f
+//	    price: 0.08
+type PriceOverrides struct {
+	Version   string          `yaml:"version"`
+	Overrides []PriceOverride `yaml:"price_overrides"`
+}
+
+// PriceOverride sets the unit price of the cost components that it matches.
+// A cost component is matched either by the price hash of the price that the
+// pricing API returned for it, or by the resource type and the name of the
+// cost component. Prices are in the currency of the output.
+type PriceOverride struct {
+	ResourceType  string          `yaml:"resource_type,omitempty"`
+	CostComponent string          `yaml:"cost_component,omitempty"`
+	PriceHash     string          `yaml:"price_hash,omitempty"`
+	Price         decimal.Decimal `yaml:"price"`
+}
+
+// LoadPriceOverrides loads the price overrides file at path.
+func LoadPriceOverrides(path string) (*PriceOverrides, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading price overrides file")
+	}
+
+	var o PriceOverrides
+	err = yamlv3.Unmarshal(contents, &o)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing price overrides file")
+	}
+
+	err = o.validate()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error loading price overrides file")
+	}
+
+	return &o, nil
+}
+
+func (o *PriceOverrides) validate() error {
+	if o.Version != priceOverridesFileVersion {
+		return fmt.Errorf("invalid version %q, expected %s", o.Version, priceOverridesFileVersion)
+	}
+
+	for i, override := range o.Overrides {
+		if override.PriceHash == "" && (override.ResourceType == "" || override.CostComponent == "") {
+			return fmt.Errorf("price override %d must set price_hash, or resource_type and cost_component", i+1)
+		}
+
+		if override.Price.IsNegative() {
+			return fmt.Errorf("price override %d has a negative price", i+1)
+		}
+	}
+
+	return nil
+}
+
+// Apply sets the prices of the cost components of project that match an
+// override. It must be called after the prices have been populated and before
+// the costs are calculated. The first matching override is used.
+func (o *PriceOverrides) Apply(project *schema.Project) {
+	for _, r := range project.AllResources() {
+		o.applyToResource(r.ResourceType, r)
+
+		for _, alternative := range r.RecommendationAlternatives() {
+			o.applyToResource(r.ResourceType, alternative)
+		}
+	}
+}
+
+// applyToResource applies the overrides to r and its sub resources. Sub
+// resources are matched with the resource type of their top level resource.
+func (o *PriceOverrides) applyToResource(resourceType string, r *schema.Resource) {
+	for _, c := range r.CostComponents {
+		override := o.match(resourceType, c)
+		if override == nil {
+			continue
+		}
+
+		log.Debugf("Using price override %s for %s %s", override.Price, r.Name, c.Name)
+		c.SetPrice(override.Price)
+		c.SetPriceWarning("")
+	}
+
+	for _, s := range r.SubResources {
+		o.applyToResource(resourceType, s)
+	}
+}
+
+func (o *PriceOverrides) match(resourceType string, c *schema.CostComponent) *PriceOverride {
+	for i, override := range o.Overrides {
+		if override.PriceHash != "" {
+			if override.PriceHash == c.PriceHash() {
+				return &o.Overrides[i]
+			}
+			continue
+		}
+
+		if override.ResourceType == resourceType && override.CostComponent == c.Name {
+			return &o.Overrides[i]
+		}
+	}
+
+	return nil
+}
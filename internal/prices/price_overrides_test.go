@@ -0,0 +1,120 @@
+package prices
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestLoadPriceOverrides(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		err      string
+	}{
+		{
+			name: "valid",
+			contents: `version: 0.1
+price_overrides:
+  - resource_type: aws_instance
+    cost_component: Instance usage (Linux/UNIX, on-demand, m5.large)
+    price: 0.08
+  - price_hash: abc-def
+    price: 1.5
+`,
+		},
+		{
+			name:     "invalid version",
+			contents: "version: 0.2\n",
+			err:      `invalid version "0.2"`,
+		},
+		{
+			name: "missing cost component",
+			contents: `version: 0.1
+price_overrides:
+  - resource_type: aws_instance
+    price: 0.08
+`,
+			err: "price override 1 must set price_hash, or resource_type and cost_component",
+		},
+		{
+			name: "negative price",
+			contents: `version: 0.1
+price_overrides:
+  - price_hash: abc-def
+    price: -1
+`,
+			err: "price override 1 has a negative price",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "price-overrides.yml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.contents), 0600))
+
+			o, err := LoadPriceOverrides(path)
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, o.Overrides, 2)
+			assert.True(t, o.Overrides[0].Price.Equal(decimal.RequireFromString("0.08")))
+			assert.True(t, o.Overrides[1].Price.Equal(decimal.RequireFromString("1.5")))
+		})
+	}
+}
+
+func TestPriceOverridesApply(t *testing.T) {
+	instance := &schema.CostComponent{Name: "Instance usage"}
+	instance.SetPrice(decimal.NewFromFloat(0.1))
+	storage := &schema.CostComponent{Name: "Storage"}
+	storage.SetPrice(decimal.NewFromFloat(0.2))
+	storage.SetPriceHash("storage-hash")
+	volume := &schema.CostComponent{Name: "Storage (general purpose SSD, gp2)"}
+	volume.SetPrice(decimal.NewFromFloat(0.3))
+	other := &schema.CostComponent{Name: "Instance usage"}
+	other.SetPrice(decimal.NewFromFloat(0.4))
+
+	project := &schema.Project{
+		Resources: []*schema.Resource{
+			{
+				Name:           "aws_instance.web",
+				ResourceType:   "aws_instance",
+				CostComponents: []*schema.CostComponent{instance, storage},
+				SubResources: []*schema.Resource{
+					{Name: "root_block_device", CostComponents: []*schema.CostComponent{volume}},
+				},
+			},
+			{
+				Name:           "aws_db_instance.db",
+				ResourceType:   "aws_db_instance",
+				CostComponents: []*schema.CostComponent{other},
+			},
+		},
+	}
+
+	o := &PriceOverrides{
+		Version: "0.1",
+		Overrides: []PriceOverride{
+			{ResourceType: "aws_instance", CostComponent: "Instance usage", Price: decimal.NewFromFloat(0.05)},
+			{PriceHash: "storage-hash", Price: decimal.NewFromFloat(0.15)},
+			{ResourceType: "aws_instance", CostComponent: "Storage (general purpose SSD, gp2)", Price: decimal.NewFromFloat(0.25)},
+		},
+	}
+	o.Apply(project)
+
+	assert.Equal(t, "0.05", instance.Price().String())
+	assert.Equal(t, "0.15", storage.Price().String())
+	assert.Equal(t, "0.25", volume.Price().String())
+	assert.Equal(t, "0.4", other.Price().String())
+}
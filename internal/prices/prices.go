@@ -1,7 +1,7 @@
 package prices
 
 import (
-	"runtime"
+	"fmt"
 
 	"github.com/infracost/infracost/internal/apiclient"
 	"github.com/infracost/infracost/internal/config"
@@ -13,30 +13,45 @@ import (
 )
 
 func PopulatePrices(ctx *config.RunContext, project *schema.Project) error {
-	resources := project.AllResources()
+	return PopulatePricesFromSource(ctx, apiclient.NewPriceSource(ctx), project)
+}
 
-	c := apiclient.NewPricingAPIClient(ctx)
+// PopulatePricesFromSource populates the prices of project with the prices
+// from s instead of the price source configured for the run.
+func PopulatePricesFromSource(ctx *config.RunContext, s apiclient.PriceSource, project *schema.Project) error {
+	resources := project.ResourcesToPrice()
 
-	err := GetPricesConcurrent(ctx, c, resources)
-	if err != nil {
-		return err
+	if apiclient.BatchesLookups(s) {
+		return GetPricesBatched(ctx, s, resources)
 	}
-	return nil
+
+	return GetPricesConcurrent(ctx, s, resources)
 }
 
-// GetPricesConcurrent gets the prices of all resources concurrently.
-// Concurrency level is calculated using the following formula:
-// max(min(4, numCPU * 4), 16)
-func GetPricesConcurrent(ctx *config.RunContext, c *apiclient.PricingAPIClient, resources []*schema.Resource) error {
-	// Set the number of workers
-	numWorkers := 4
-	numCPU := runtime.NumCPU()
-	if numCPU*4 > numWorkers {
-		numWorkers = numCPU * 4
+// GetPricesBatched gets the prices of all resources with one lookup, so price
+// sources that batch lookups can send as few requests as possible.
+func GetPricesBatched(ctx *config.RunContext, s apiclient.PriceSource, resources []*schema.Resource) error {
+	var keys []apiclient.PriceQueryKey
+	for _, r := range resources {
+		if r.IsSkipped {
+			continue
+		}
+
+		keys = append(keys, apiclient.PriceQueryKeys(r)...)
 	}
-	if numWorkers > 16 {
-		numWorkers = 16
+
+	return getPrices(ctx, s, keys)
+}
+
+// GetPricesConcurrent gets the prices of all resources concurrently, with
+// up to the parallelism of the run context resources being looked up at the
+// same time.
+func GetPricesConcurrent(ctx *config.RunContext, s apiclient.PriceSource, resources []*schema.Resource) error {
+	numWorkers, err := ctx.GetParallelism()
+	if err != nil {
+		return err
 	}
+
 	numJobs := len(resources)
 	jobs := make(chan *schema.Resource, numJobs)
 	resultErrors := make(chan error, numJobs)
@@ -45,7 +60,7 @@ func GetPricesConcurrent(ctx *config.RunContext, c *apiclient.PricingAPIClient,
 	for i := 0; i < numWorkers; i++ {
 		go func(jobs <-chan *schema.Resource, resultErrors chan<- error) {
 			for r := range jobs {
-				err := GetPrices(ctx, c, r)
+				err := GetPrices(ctx, s, r)
 				resultErrors <- err
 			}
 		}(jobs, resultErrors)
@@ -66,18 +81,65 @@ func GetPricesConcurrent(ctx *config.RunContext, c *apiclient.PricingAPIClient,
 	return nil
 }
 
-func GetPrices(ctx *config.RunContext, c *apiclient.PricingAPIClient, r *schema.Resource) error {
+func GetPrices(ctx *config.RunContext, s apiclient.PriceSource, r *schema.Resource) error {
 	if r.IsSkipped {
 		return nil
 	}
 
-	results, err := c.RunQueries(r)
+	return getPrices(ctx, s, apiclient.PriceQueryKeys(r))
+}
+
+// getPrices looks up the prices of keys and sets them on their cost components.
+// Cost components that have no price are priced with their PriceFallback.
+func getPrices(ctx *config.RunContext, s apiclient.PriceSource, keys []apiclient.PriceQueryKey) error {
+	results, err := s.LookupPrices(keys)
 	if err != nil {
 		return err
 	}
 
+	currency := apiclient.PriceCurrency(ctx)
+	var fallbacks []apiclient.PriceQueryKey
 	for _, r := range results {
-		setCostComponentPrice(ctx, c.Currency, r.Resource, r.CostComponent, r.Result)
+		c := r.CostComponent
+		if c.PriceFallback != nil && c.CustomPrice() == nil && len(productsWithPrices(r.Result)) == 0 {
+			writePriceDiagnostic(ctx, currency, r.Resource, c, r.Result, fmt.Sprintf("no prices found, looking up the fallback price with a %s%% discount", decimal.NewFromFloat(c.PriceFallback.DiscountPerc*100).String()))
+			fallbacks = append(fallbacks, r.PriceQueryKey)
+			continue
+		}
+
+		setCostComponentPrice(ctx, currency, r.Resource, c, r.Result)
+	}
+
+	if len(fallbacks) == 0 {
+		return nil
+	}
+
+	return getFallbackPrices(ctx, s, currency, fallbacks)
+}
+
+// getFallbackPrices prices the cost components of keys, which have no price for
+// their own filters, with the filters of their PriceFallback. The price that is
+// found is discounted by the DiscountPerc of the PriceFallback.
+func getFallbackPrices(ctx *config.RunContext, s apiclient.PriceSource, currency string, keys []apiclient.PriceQueryKey) error {
+	for _, k := range keys {
+		c := k.CostComponent
+		log.Debugf("No prices found for %s %s, using the fallback price with a %s%% discount", k.Resource.Name, c.Name, decimal.NewFromFloat(c.PriceFallback.DiscountPerc*100).String())
+
+		if c.PriceFallback.ProductFilter != nil {
+			c.ProductFilter = c.PriceFallback.ProductFilter
+		}
+		c.PriceFilter = c.PriceFallback.PriceFilter
+	}
+
+	results, err := s.LookupPrices(keys)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		c := r.CostComponent
+		setCostComponentPrice(ctx, currency, r.Resource, c, r.Result)
+		c.SetPrice(c.Price().Mul(decimal.NewFromInt(1).Sub(decimal.NewFromFloat(c.PriceFallback.DiscountPerc))))
 	}
 
 	return nil
@@ -89,6 +151,7 @@ func setCostComponentPrice(ctx *config.RunContext, currency string, r *schema.Re
 	if c.CustomPrice() != nil {
 		log.Debugf("Using user-defined custom price %v for %s %s.", *c.CustomPrice(), r.Name, c.Name)
 		c.SetPrice(*c.CustomPrice())
+		writePriceDiagnostic(ctx, currency, r, c, res, fmt.Sprintf("using the custom price %s", c.CustomPrice()))
 		return
 	}
 
@@ -96,12 +159,14 @@ func setCostComponentPrice(ctx *config.RunContext, currency string, r *schema.Re
 	if len(products) == 0 {
 		if c.IgnoreIfMissingPrice {
 			log.Debugf("No products found for %s %s, ignoring since IgnoreIfMissingPrice is set.", r.Name, c.Name)
+			writePriceDiagnostic(ctx, currency, r, c, res, "no products match the product filter, removing the cost component as it's optional")
 			r.RemoveCostComponent(c)
 			return
 		}
 
 		log.Warnf("No products found for %s %s, using 0.00", r.Name, c.Name)
-		setResourceWarningEvent(ctx, r, "No products found")
+		setResourceWarningEvent(ctx, r, c, "No products found")
+		writePriceDiagnostic(ctx, currency, r, c, res, "no products match the product filter, using 0.00")
 		c.SetPrice(decimal.Zero)
 		return
 	}
@@ -110,56 +175,71 @@ func setCostComponentPrice(ctx *config.RunContext, currency string, r *schema.Re
 		log.Debugf("Multiple products found for %s %s, filtering those with prices", r.Name, c.Name)
 	}
 
-	// Some resources may have identical records in CPAPI for the same product
-	// filters, several products are always returned and they can only be
-	// distinguished by their prices. However if we pick the first product it may not
-	// have the price due to price filter and the lookup fails. Filtering the
-	// products with prices helps to solve that.
-	productsWithPrices := []gjson.Result{}
-	for _, product := range products {
-		if len(product.Get("prices").Array()) > 0 {
-			productsWithPrices = append(productsWithPrices, product)
-		}
-	}
-
-	if len(productsWithPrices) == 0 {
+	withPrices := productsWithPrices(res)
+	if len(withPrices) == 0 {
 		if c.IgnoreIfMissingPrice {
 			log.Debugf("No prices found for %s %s, ignoring since IgnoreIfMissingPrice is set.", r.Name, c.Name)
+			writePriceDiagnostic(ctx, currency, r, c, res, "no prices match the price filter, removing the cost component as it's optional")
 			r.RemoveCostComponent(c)
 			return
 		}
 
 		log.Warnf("No prices found for %s %s, using 0.00", r.Name, c.Name)
-		setResourceWarningEvent(ctx, r, "No prices found")
+		setResourceWarningEvent(ctx, r, c, "No prices found")
+		writePriceDiagnostic(ctx, currency, r, c, res, "no prices match the price filter, using 0.00")
 		c.SetPrice(decimal.Zero)
 		return
 	}
 
-	if len(productsWithPrices) > 1 {
+	if len(withPrices) > 1 {
 		log.Warnf("Multiple products with prices found for %s %s, using the first product", r.Name, c.Name)
-		setResourceWarningEvent(ctx, r, "Multiple products found")
+		setResourceWarningEvent(ctx, r, c, "Multiple products found")
 	}
 
-	prices := productsWithPrices[0].Get("prices").Array()
+	prices := withPrices[0].Get("prices").Array()
 	if len(prices) > 1 {
 		log.Warnf("Multiple prices found for %s %s, using the first price", r.Name, c.Name)
-		setResourceWarningEvent(ctx, r, "Multiple prices found")
+		setResourceWarningEvent(ctx, r, c, "Multiple prices found")
 	}
 
 	var err error
 	p, err = decimal.NewFromString(prices[0].Get(currency).String())
 	if err != nil {
 		log.Warnf("Error converting price to '%v' (using 0.00)  '%v': %s", currency, prices[0].Get(currency).String(), err.Error())
-		setResourceWarningEvent(ctx, r, "Error converting price")
+		setResourceWarningEvent(ctx, r, c, "Error converting price")
+		writePriceDiagnostic(ctx, currency, r, c, res, fmt.Sprintf("the price isn't a valid %s amount, using 0.00", currency))
 		c.SetPrice(decimal.Zero)
 		return
 	}
 
 	c.SetPrice(p)
 	c.SetPriceHash(prices[0].Get("priceHash").String())
+	log.Debugf("Using price %s with price hash %s for %s %s", p, c.PriceHash(), r.Name, c.Name)
+	writePriceDiagnostic(ctx, currency, r, c, res, chosenPriceReason(p, c.PriceHash(), len(withPrices), len(prices)))
+}
+
+// productsWithPrices returns the products of res that have prices. Some
+// resources may have identical records in CPAPI for the same product filters,
+// several products are always returned and they can only be distinguished by
+// their prices. However if we pick the first product it may not have the price
+// due to price filter and the lookup fails. Filtering the products with prices
+// helps to solve that.
+func productsWithPrices(res gjson.Result) []gjson.Result {
+	products := []gjson.Result{}
+	for _, product := range res.Get("data.products").Array() {
+		if len(product.Get("prices").Array()) > 0 {
+			products = append(products, product)
+		}
+	}
+
+	return products
 }
 
-func setResourceWarningEvent(ctx *config.RunContext, r *schema.Resource, msg string) {
+// setResourceWarningEvent records that the price of c couldn't be resolved to
+// a single price, both on c and in the warnings of the run by resource type.
+func setResourceWarningEvent(ctx *config.RunContext, r *schema.Resource, c *schema.CostComponent, msg string) {
+	c.SetPriceWarning(msg)
+
 	warnings := ctx.GetResourceWarnings()
 	if warnings == nil {
 		warnings = make(map[string]map[string]int)
@@ -174,3 +254,29 @@ func setResourceWarningEvent(ctx *config.RunContext, r *schema.Resource, msg str
 
 	resourceWarnings[msg] += 1
 }
+
+// CalculateCosts converts the prices of the project to the currency of the
+// run and applies the price overrides, which can be nil, the free tier and the
+// discounts of the run to them, then calculates the costs of the project and
+// how they change. The prices must have already been looked up.
+func CalculateCosts(ctx *config.RunContext, exchangeRates ExchangeRateSource, overrides *PriceOverrides, project *schema.Project) error {
+	err := ConvertCurrency(ctx, exchangeRates, project)
+	if err != nil {
+		return err
+	}
+
+	if overrides != nil {
+		overrides.Apply(project)
+	}
+
+	if ctx.Config.IncludeFreeTier {
+		ApplyFreeTier(project)
+	}
+
+	ApplyDiscounts(project, ctx.Config.Discounts)
+	schema.CalculateCosts(project)
+	project.CalculateProjections()
+	project.CalculateDiff()
+
+	return nil
+}
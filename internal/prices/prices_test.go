@@ -0,0 +1,90 @@
+package prices
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+// fakePriceSource returns the price of each purchase option in prices, or no
+// products if the purchase option has no price.
+type fakePriceSource struct {
+	prices  map[string]string
+	lookups int
+}
+
+func (s *fakePriceSource) LookupPrices(keys []apiclient.PriceQueryKey) ([]apiclient.PriceQueryResult, error) {
+	s.lookups++
+
+	results := make([]apiclient.PriceQueryResult, 0, len(keys))
+	for _, k := range keys {
+		res := `{"data": {"products": []}}`
+		if p, ok := s.prices[*k.CostComponent.PriceFilter.PurchaseOption]; ok {
+			res = `{"data": {"products": [{"prices": [{"priceHash": "hash", "USD": "` + p + `"}]}]}}`
+		}
+
+		results = append(results, apiclient.PriceQueryResult{PriceQueryKey: k, Result: gjson.Parse(res)})
+	}
+
+	return results, nil
+}
+
+func TestGetPricesFallback(t *testing.T) {
+	spot := func() *schema.CostComponent {
+		return &schema.CostComponent{
+			Name:        "Instance usage (spot)",
+			PriceFilter: &schema.PriceFilter{PurchaseOption: strPtr("spot")},
+			PriceFallback: &schema.PriceFallback{
+				PriceFilter:  &schema.PriceFilter{PurchaseOption: strPtr("on_demand")},
+				DiscountPerc: 0.7,
+			},
+		}
+	}
+
+	t.Run("uses the price when it is found", func(t *testing.T) {
+		c := spot()
+		s := &fakePriceSource{prices: map[string]string{"spot": "0.03", "on_demand": "0.1"}}
+
+		err := GetPrices(config.EmptyRunContext(), s, &schema.Resource{Name: "r", CostComponents: []*schema.CostComponent{c}})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, s.lookups)
+		assert.Equal(t, "0.03", c.Price().String())
+		assert.Equal(t, "spot", *c.PriceFilter.PurchaseOption)
+		assert.Empty(t, c.PriceWarning())
+	})
+
+	t.Run("uses the discounted fallback price when no price is found", func(t *testing.T) {
+		c := spot()
+		s := &fakePriceSource{prices: map[string]string{"on_demand": "0.1"}}
+
+		err := GetPrices(config.EmptyRunContext(), s, &schema.Resource{Name: "r", CostComponents: []*schema.CostComponent{c}})
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, s.lookups)
+		assert.True(t, decimal.RequireFromString("0.03").Equal(c.Price()), "got %s", c.Price())
+		assert.Equal(t, "on_demand", *c.PriceFilter.PurchaseOption)
+	})
+
+	t.Run("uses zero when the fallback has no price either", func(t *testing.T) {
+		c := spot()
+		s := &fakePriceSource{prices: map[string]string{}}
+
+		err := GetPrices(config.EmptyRunContext(), s, &schema.Resource{Name: "r", CostComponents: []*schema.CostComponent{c}})
+		require.NoError(t, err)
+
+		assert.True(t, c.Price().IsZero())
+		assert.Equal(t, "No products found", c.PriceWarning())
+	})
+}
@@ -0,0 +1,539 @@
+package ansible
+
+import (
+	"fmt"
+	"strings"
+)
+
+// collectionPrefixes are the collections of the cloud modules, which can be
+// used with or without the collection in the task.
+var collectionPrefixes = []string{
+	"amazon.aws.",
+	"community.aws.",
+	"azure.azcollection.",
+	"google.cloud.",
+}
+
+// modules are the Terraform resource types of the cloud modules, keyed by the
+// name of the module without its collection. The convert function sets the
+// attributes of the Terraform resource from the module arguments, since these
+// are named differently, and can add other resources, e.g. for the data disks
+// of Azure virtual machines.
+var modules = map[string]moduleMapping{
+	"ec2_instance":             {"aws_instance", convertEC2Instance},
+	"ec2":                      {"aws_instance", convertEC2},
+	"ec2_vol":                  {"aws_ebs_volume", convertEC2Volume},
+	"ec2_eip":                  {"aws_eip", nil},
+	"ec2_vpc_nat_gateway":      {"aws_nat_gateway", nil},
+	"rds_instance":             {"aws_db_instance", convertRDSInstance},
+	"s3_bucket":                {"aws_s3_bucket", convertS3Bucket},
+	"elb_application_lb":       {"aws_lb", convertLoadBalancer("application")},
+	"elb_network_lb":           {"aws_lb", convertLoadBalancer("network")},
+	"elb_classic_lb":           {"aws_elb", nil},
+	"lambda":                   {"aws_lambda_function", convertLambda},
+	"sqs_queue":                {"aws_sqs_queue", convertSQSQueue},
+	"sns_topic":                {"aws_sns_topic", convertSNSTopic},
+	"cloudwatchlogs_log_group": {"aws_cloudwatch_log_group", convertLogGroup},
+	"elasticache":              {"aws_elasticache_cluster", convertElastiCache},
+	"dynamodb_table":           {"aws_dynamodb_table", convertDynamoDBTable},
+	"eks_cluster":              {"aws_eks_cluster", nil},
+
+	"azure_rm_virtualmachine":         {"azurerm_linux_virtual_machine", convertAzureVM},
+	"azure_rm_virtualmachinescaleset": {"azurerm_linux_virtual_machine_scale_set", convertAzureVMSS},
+	"azure_rm_manageddisk":            {"azurerm_managed_disk", convertAzureManagedDisk},
+	"azure_rm_storageaccount":         {"azurerm_storage_account", convertAzureStorageAccount},
+	"azure_rm_publicipaddress":        {"azurerm_public_ip", convertAzurePublicIP},
+	"azure_rm_loadbalancer":           {"azurerm_lb", convertAzureLoadBalancer},
+	"azure_rm_rediscache":             {"azurerm_redis_cache", convertAzureRedisCache},
+	"azure_rm_appserviceplan":         {"azurerm_app_service_plan", convertAzureAppServicePlan},
+	"azure_rm_aks":                    {"azurerm_kubernetes_cluster", convertAzureAKS},
+
+	"gcp_compute_instance": {"google_compute_instance", convertGCPInstance},
+	"gcp_compute_disk":     {"google_compute_disk", convertGCPDisk},
+	"gcp_storage_bucket":   {"google_storage_bucket", convertGCPBucket},
+}
+
+type moduleMapping struct {
+	resType string
+	convert func(m *moduleResource)
+}
+
+// moduleResource is a resource created by a cloud module task, with the
+// arguments of the task rendered.
+type moduleResource struct {
+	resType string
+	args    map[string]interface{}
+	values  map[string]interface{}
+
+	// count is the number of resources the task creates, e.g. the exact_count
+	// of EC2 instances.
+	count int
+
+	// extra are any other resources created by the task, which are named
+	// after the resource with their suffix added.
+	extra []extraResource
+}
+
+type extraResource struct {
+	suffix  string
+	resType string
+	values  map[string]interface{}
+}
+
+// lookupModule returns the mapping of the module, which can be the name of the
+// module with or without its collection.
+func lookupModule(module string) (moduleMapping, bool) {
+	name := module
+	for _, prefix := range collectionPrefixes {
+		if strings.HasPrefix(module, prefix) {
+			name = strings.TrimPrefix(module, prefix)
+			break
+		}
+	}
+
+	if strings.Contains(name, ".") {
+		return moduleMapping{}, false
+	}
+
+	m, ok := modules[name]
+	return m, ok
+}
+
+// set sets the attribute of the resource from the first of the arguments that
+// is set.
+func (m *moduleResource) set(attr string, args ...string) {
+	for _, a := range args {
+		if v, ok := m.args[a]; ok && v != nil {
+			m.values[attr] = v
+			return
+		}
+	}
+}
+
+func (m *moduleResource) setDefault(attr string, v interface{}) {
+	if _, ok := m.values[attr]; !ok {
+		m.values[attr] = v
+	}
+}
+
+func (m *moduleResource) str(arg string) string {
+	return toString(m.args[arg])
+}
+
+func (m *moduleResource) setCount(args ...string) {
+	for _, a := range args {
+		if v, ok := m.args[a]; ok {
+			if i, ok := toInt(v); ok && i >= 0 {
+				m.count = i
+				return
+			}
+		}
+	}
+}
+
+func convertEC2Instance(m *moduleResource) {
+	m.set("instance_type", "instance_type")
+	m.setDefault("instance_type", "t2.micro")
+	m.set("ami", "image_id")
+	m.set("ebs_optimized", "ebs_optimized")
+	m.set("monitoring", "detailed_monitoring")
+	m.set("tenancy", "tenancy")
+
+	if placement, ok := m.args["placement"].(map[string]interface{}); ok {
+		if tenancy, ok := placement["tenancy"]; ok {
+			m.values["tenancy"] = tenancy
+		}
+	}
+
+	if credits := m.str("cpu_credit_specification"); credits != "" {
+		m.values["credit_specification"] = []interface{}{map[string]interface{}{"cpu_credits": credits}}
+	}
+
+	m.setVolumes(func(v map[string]interface{}) map[string]interface{} {
+		ebs, _ := v["ebs"].(map[string]interface{})
+		return ebs
+	})
+
+	m.setCount("exact_count", "count")
+}
+
+// convertEC2 converts the arguments of the ec2 module, which was replaced by
+// the ec2_instance module but is still used by older playbooks.
+func convertEC2(m *moduleResource) {
+	m.set("instance_type", "instance_type")
+	m.setDefault("instance_type", "m1.small")
+	m.set("ami", "image")
+	m.set("ebs_optimized", "ebs_optimized")
+	m.set("monitoring", "monitoring")
+	m.set("tenancy", "tenancy")
+
+	m.setVolumes(func(v map[string]interface{}) map[string]interface{} {
+		return v
+	})
+
+	m.setCount("exact_count", "count")
+}
+
+// rootDeviceNames are the device names of the root volume of the common AMIs,
+// so the volumes with these names are the root block device of the instance.
+var rootDeviceNames = map[string]bool{
+	"/dev/sda1": true,
+	"/dev/sda":  true,
+	"/dev/xvda": true,
+}
+
+func (m *moduleResource) setVolumes(ebs func(v map[string]interface{}) map[string]interface{}) {
+	volumes, ok := m.args["volumes"].([]interface{})
+	if !ok {
+		return
+	}
+
+	var root, additional []interface{}
+	for _, item := range volumes {
+		v, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		e := ebs(v)
+		if e == nil {
+			continue
+		}
+
+		device := map[string]interface{}{}
+		for _, attr := range []string{"volume_size", "volume_type", "iops", "throughput"} {
+			if val, ok := e[attr]; ok && val != nil {
+				device[attr] = val
+			}
+		}
+
+		if rootDeviceNames[toString(v["device_name"])] {
+			root = append(root, device)
+			continue
+		}
+
+		device["device_name"] = v["device_name"]
+		additional = append(additional, device)
+	}
+
+	if len(root) > 0 {
+		m.values["root_block_device"] = root[:1]
+	}
+
+	if len(additional) > 0 {
+		m.values["ebs_block_device"] = additional
+	}
+}
+
+func convertEC2Volume(m *moduleResource) {
+	m.set("size", "volume_size")
+	m.set("type", "volume_type")
+	m.set("iops", "iops")
+	m.set("throughput", "throughput")
+	m.set("availability_zone", "zone", "availability_zone", "aws_zone")
+}
+
+func convertRDSInstance(m *moduleResource) {
+	m.set("instance_class", "db_instance_class", "instance_type", "class")
+	m.set("engine", "engine")
+	m.set("allocated_storage", "allocated_storage")
+	m.set("storage_type", "storage_type")
+	m.set("iops", "iops")
+	m.set("storage_throughput", "storage_throughput")
+	m.set("multi_az", "multi_az")
+	m.set("license_model", "license_model")
+	m.set("backup_retention_period", "backup_retention_period")
+	m.set("performance_insights_enabled", "enable_performance_insights")
+	m.set("performance_insights_retention_period", "performance_insights_retention_period")
+}
+
+func convertS3Bucket(m *moduleResource) {
+	m.set("bucket", "name")
+}
+
+func convertLoadBalancer(lbType string) func(m *moduleResource) {
+	return func(m *moduleResource) {
+		m.values["load_balancer_type"] = lbType
+	}
+}
+
+func convertLambda(m *moduleResource) {
+	m.set("function_name", "name")
+	m.set("memory_size", "memory_size")
+	m.set("runtime", "runtime")
+	m.set("timeout", "timeout")
+
+	if arch := m.str("architecture"); arch != "" {
+		m.values["architectures"] = []interface{}{arch}
+	}
+}
+
+func convertSQSQueue(m *moduleResource) {
+	m.set("name", "name")
+	if strings.EqualFold(m.str("queue_type"), "fifo") {
+		m.values["fifo_queue"] = true
+	}
+}
+
+func convertSNSTopic(m *moduleResource) {
+	m.set("name", "name")
+	if strings.EqualFold(m.str("topic_type"), "fifo") {
+		m.values["fifo_topic"] = true
+	}
+}
+
+func convertLogGroup(m *moduleResource) {
+	m.set("name", "log_group_name")
+	m.set("retention_in_days", "retention")
+}
+
+func convertElastiCache(m *moduleResource) {
+	m.set("cluster_id", "name")
+	m.set("engine", "engine")
+	m.setDefault("engine", "memcached")
+	m.set("node_type", "node_type")
+	m.set("num_cache_nodes", "num_nodes")
+	m.setDefault("num_cache_nodes", 1)
+}
+
+func convertDynamoDBTable(m *moduleResource) {
+	m.set("name", "name")
+	m.set("hash_key", "hash_key_name")
+	m.set("billing_mode", "billing_mode")
+	m.setDefault("billing_mode", "PROVISIONED")
+
+	if m.values["billing_mode"] == "PROVISIONED" {
+		m.set("read_capacity", "read_capacity")
+		m.setDefault("read_capacity", 1)
+		m.set("write_capacity", "write_capacity")
+		m.setDefault("write_capacity", 1)
+	}
+}
+
+// convertAzureVM converts a virtual machine to a Linux or Windows virtual
+// machine, with its managed data disks as separate managed disks.
+func convertAzureVM(m *moduleResource) {
+	if strings.EqualFold(m.str("os_type"), "windows") {
+		m.resType = "azurerm_windows_virtual_machine"
+	}
+
+	m.set("size", "vm_size")
+	m.set("license_type", "license_type")
+	m.values["os_disk"] = []interface{}{azureOSDisk(m)}
+
+	if spot := m.str("priority"); strings.EqualFold(spot, "spot") {
+		m.values["priority"] = "Spot"
+	}
+
+	disks, _ := m.args["data_disks"].([]interface{})
+	for i, item := range disks {
+		d, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		lun := i
+		if l, ok := toInt(d["lun"]); ok {
+			lun = l
+		}
+
+		values := map[string]interface{}{
+			"storage_account_type": "Standard_LRS",
+		}
+		if v, ok := d["managed_disk_type"]; ok && v != nil {
+			values["storage_account_type"] = v
+		}
+		if v, ok := d["disk_size_gb"]; ok && v != nil {
+			values["disk_size_gb"] = v
+		}
+		if loc, ok := m.values["location"]; ok {
+			values["location"] = loc
+		}
+
+		m.extra = append(m.extra, extraResource{
+			suffix:  fmt.Sprintf("_data_disk_%d", lun),
+			resType: "azurerm_managed_disk",
+			values:  values,
+		})
+	}
+}
+
+func convertAzureVMSS(m *moduleResource) {
+	if strings.EqualFold(m.str("os_type"), "windows") {
+		m.resType = "azurerm_windows_virtual_machine_scale_set"
+	}
+
+	m.set("sku", "vm_size")
+	m.set("instances", "capacity")
+	m.set("license_type", "license_type")
+	m.values["os_disk"] = []interface{}{azureOSDisk(m)}
+}
+
+func azureOSDisk(m *moduleResource) map[string]interface{} {
+	disk := map[string]interface{}{
+		"storage_account_type": "Standard_LRS",
+	}
+
+	if v, ok := m.args["managed_disk_type"]; ok && v != nil {
+		disk["storage_account_type"] = v
+	}
+
+	if v, ok := m.args["os_disk_size_gb"]; ok && v != nil {
+		disk["disk_size_gb"] = v
+	}
+
+	return disk
+}
+
+func convertAzureManagedDisk(m *moduleResource) {
+	m.set("storage_account_type", "storage_account_type")
+	m.setDefault("storage_account_type", "Standard_LRS")
+	m.set("disk_size_gb", "disk_size_gb")
+}
+
+// convertAzureStorageAccount sets the tier and replication type of the storage
+// account from its account type, e.g. Standard_LRS.
+func convertAzureStorageAccount(m *moduleResource) {
+	if tier, replication, ok := strings.Cut(m.str("account_type"), "_"); ok {
+		m.values["account_tier"] = tier
+		m.values["account_replication_type"] = replication
+	}
+
+	m.set("account_kind", "kind")
+	m.setDefault("account_kind", "Storage")
+	m.set("access_tier", "access_tier")
+	if m.values["account_kind"] != "Storage" {
+		m.setDefault("access_tier", "Hot")
+	}
+}
+
+func convertAzurePublicIP(m *moduleResource) {
+	m.set("sku", "sku")
+	m.setDefault("sku", "Basic")
+	m.set("allocation_method", "allocation_method")
+	m.setDefault("allocation_method", "Dynamic")
+}
+
+func convertAzureLoadBalancer(m *moduleResource) {
+	m.set("sku", "sku")
+	m.setDefault("sku", "Basic")
+}
+
+// convertAzureRedisCache sets the SKU of the cache from the sku argument, e.g.
+// name: standard and size: C1.
+func convertAzureRedisCache(m *moduleResource) {
+	sku, _ := m.args["sku"].(map[string]interface{})
+
+	if name := toString(sku["name"]); name != "" {
+		m.values["sku_name"] = strings.ToUpper(name[:1]) + strings.ToLower(name[1:])
+	}
+
+	size := strings.ToUpper(toString(sku["size"]))
+	if len(size) > 1 {
+		m.values["family"] = size[:1]
+		m.values["capacity"] = size[1:]
+	}
+
+	m.set("shard_count", "shard_count")
+}
+
+// azureAppServiceTiers are the tiers of the App Service plan SKUs, which start
+// with the letter of their tier, e.g. S1 is a Standard plan.
+var azureAppServiceTiers = map[string]string{
+	"F": "Free",
+	"D": "Shared",
+	"B": "Basic",
+	"S": "Standard",
+	"P": "PremiumV2",
+	"I": "Isolated",
+}
+
+func convertAzureAppServicePlan(m *moduleResource) {
+	size := strings.ToUpper(m.str("sku"))
+	if size != "" {
+		sku := map[string]interface{}{
+			"size": size,
+			"tier": azureAppServiceTiers[size[:1]],
+		}
+		if capacity, ok := m.args["number_of_workers"]; ok && capacity != nil {
+			sku["capacity"] = capacity
+		}
+		m.values["sku"] = []interface{}{sku}
+	}
+
+	if toBool(m.args["is_linux"]) {
+		m.values["kind"] = "Linux"
+	} else {
+		m.values["kind"] = "Windows"
+	}
+}
+
+// convertAzureAKS sets the default node pool of the cluster from its first
+// agent pool. Any other agent pools aren't included.
+func convertAzureAKS(m *moduleResource) {
+	m.set("sku_tier", "sku_tier")
+
+	pools, _ := m.args["agent_pool_profiles"].([]interface{})
+	if len(pools) == 0 {
+		return
+	}
+
+	p, ok := pools[0].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	pool := map[string]interface{}{}
+	for attr, arg := range map[string]string{
+		"vm_size":         "vm_size",
+		"node_count":      "count",
+		"os_disk_size_gb": "os_disk_size_gb",
+	} {
+		if v, ok := p[arg]; ok && v != nil {
+			pool[attr] = v
+		}
+	}
+
+	m.values["default_node_pool"] = []interface{}{pool}
+}
+
+func convertGCPInstance(m *moduleResource) {
+	m.set("machine_type", "machine_type")
+	m.set("zone", "zone")
+
+	if scheduling, ok := m.args["scheduling"].(map[string]interface{}); ok {
+		if preemptible, ok := scheduling["preemptible"]; ok {
+			m.values["scheduling"] = []interface{}{map[string]interface{}{"preemptible": toBool(preemptible)}}
+		}
+	}
+
+	disks, _ := m.args["disks"].([]interface{})
+	for _, item := range disks {
+		d, ok := item.(map[string]interface{})
+		if !ok || !toBool(d["boot"]) {
+			continue
+		}
+
+		params, _ := d["initialize_params"].(map[string]interface{})
+		initParams := map[string]interface{}{}
+		if v, ok := params["disk_size_gb"]; ok && v != nil {
+			initParams["size"] = v
+		}
+		if v, ok := params["disk_type"]; ok && v != nil {
+			initParams["type"] = v
+		}
+
+		m.values["boot_disk"] = []interface{}{map[string]interface{}{"initialize_params": []interface{}{initParams}}}
+	}
+}
+
+func convertGCPDisk(m *moduleResource) {
+	m.set("size", "size_gb")
+	m.set("type", "type")
+	m.set("zone", "zone")
+}
+
+func convertGCPBucket(m *moduleResource) {
+	m.set("location", "location")
+	m.set("storage_class", "storage_class")
+}
@@ -0,0 +1,417 @@
+package ansible
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+)
+
+// providerNames are the names of the Terraform providers of the resource type
+// prefixes, as they appear in a Terraform plan.
+var providerNames = map[string]string{
+	"aws":     "registry.terraform.io/hashicorp/aws",
+	"azurerm": "registry.terraform.io/hashicorp/azurerm",
+	"google":  "registry.terraform.io/hashicorp/google",
+}
+
+// awsRegionArgs are the arguments and environment variables that set the
+// region of the AWS modules, in order of precedence.
+var (
+	awsRegionArgs = []string{"region", "aws_region", "ec2_region"}
+	awsRegionEnv  = []string{"AWS_REGION", "AWS_DEFAULT_REGION", "EC2_REGION"}
+)
+
+// absentStates are the states of the cloud modules that remove the resource.
+var absentStates = map[string]bool{
+	"absent":     true,
+	"terminated": true,
+	"deleted":    true,
+}
+
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+type Parser struct {
+	ctx *config.ProjectContext
+}
+
+func NewParser(ctx *config.ProjectContext) *Parser {
+	return &Parser{ctx}
+}
+
+// planResource is a resource of a Terraform plan JSON, along with the key of
+// the provider config it uses.
+type planResource struct {
+	resType     string
+	name        string
+	providerKey string
+	values      map[string]interface{}
+}
+
+func (r planResource) address() string {
+	return fmt.Sprintf("%s.%s", r.resType, r.name)
+}
+
+// plan is the Terraform plan that the tasks of the playbooks are converted to.
+type plan struct {
+	resources    []planResource
+	providerConf map[string]interface{}
+	seen         map[string]bool
+
+	// resourceGroups are the locations of the Azure resource groups created
+	// by the playbooks, keyed by their lower case name, so the resources in
+	// them that don't set a location use the location of the group.
+	resourceGroups map[string]string
+}
+
+// toPlanJSON converts the tasks of the playbooks at paths to a Terraform plan
+// JSON with the planned values of the resources created by their cloud
+// modules.
+func (p *Parser) toPlanJSON(paths []string) ([]byte, error) {
+	l := newLoader()
+	for _, path := range paths {
+		if err := l.loadPlaybook(path, vars{}); err != nil {
+			return nil, err
+		}
+	}
+
+	pl := &plan{
+		providerConf:   make(map[string]interface{}),
+		seen:           make(map[string]bool),
+		resourceGroups: make(map[string]string),
+	}
+
+	if len(l.tasks) == 0 {
+		return nil, errors.New("no tasks found, the path must be an Ansible playbook or a directory of playbooks")
+	}
+
+	for _, t := range l.tasks {
+		p.addTask(pl, t)
+	}
+
+	planJSON := map[string]interface{}{
+		"format_version":   "1.0",
+		"planned_values":   map[string]interface{}{"root_module": rootModule(pl.resources)},
+		"resource_changes": resourceChanges(pl.resources),
+		"configuration": map[string]interface{}{
+			"provider_config": pl.providerConf,
+			"root_module": map[string]interface{}{
+				"resources": resourceConfigs(pl.resources),
+			},
+		},
+	}
+
+	return json.Marshal(planJSON)
+}
+
+// addTask adds the resources created by the task, once for each item of its
+// loop. Tasks of modules that aren't supported are skipped.
+func (p *Parser) addTask(pl *plan, t task) {
+	short := moduleName(t.module)
+	if short == "azure_rm_resourcegroup" {
+		args, _ := t.vars.render(t.args).(map[string]interface{})
+		if name := toString(args["name"]); name != "" && toString(args["location"]) != "" {
+			pl.resourceGroups[strings.ToLower(name)] = toString(args["location"])
+		}
+		return
+	}
+
+	mapping, ok := lookupModule(t.module)
+	if !ok {
+		return
+	}
+
+	iterations := []vars{t.vars}
+	if t.loop != nil {
+		items, ok := loopItems(t.vars, t.loop)
+		if ok {
+			iterations = make([]vars, 0, len(items))
+			for _, item := range items {
+				iterations = append(iterations, t.vars.with(map[string]interface{}{t.loopVar: item}))
+			}
+		} else {
+			logging.Logger.Debugf("Adding a single %s resource for task %q as its loop could not be resolved", mapping.resType, t.name)
+		}
+	}
+
+	var resources []*moduleResource
+	for _, v := range iterations {
+		args, _ := v.render(t.args).(map[string]interface{})
+		if absentStates[strings.ToLower(toString(args["state"]))] {
+			continue
+		}
+
+		m := &moduleResource{
+			resType: mapping.resType,
+			args:    args,
+			values:  make(map[string]interface{}),
+			count:   1,
+		}
+		pl.setCommonValues(m)
+
+		if mapping.convert != nil {
+			mapping.convert(m)
+		}
+
+		resources = append(resources, m)
+	}
+
+	total := 0
+	for _, m := range resources {
+		total += m.count
+	}
+
+	name := resourceName(t, resources)
+	index := 0
+	for _, m := range resources {
+		providerKey := pl.providerKey(m, t)
+
+		for i := 0; i < m.count; i++ {
+			idx := -1
+			if total > 1 || t.loop != nil {
+				idx = index
+			}
+			index++
+
+			resName := pl.uniqueName(m.resType, name, idx)
+			pl.resources = append(pl.resources, planResource{
+				resType:     m.resType,
+				name:        resName,
+				providerKey: providerKey,
+				values:      m.values,
+			})
+
+			for _, e := range m.extra {
+				pl.resources = append(pl.resources, planResource{
+					resType:     e.resType,
+					name:        pl.uniqueName(e.resType, removeIndex(resName)+e.suffix, idx),
+					providerKey: providerPrefix(e.resType),
+					values:      e.values,
+				})
+			}
+		}
+	}
+}
+
+// setCommonValues sets the values that are set the same way for all the
+// modules of a cloud, e.g. the location of Azure resources.
+func (pl *plan) setCommonValues(m *moduleResource) {
+	if tags, ok := m.args["tags"].(map[string]interface{}); ok {
+		m.values["tags"] = tags
+	}
+
+	if providerPrefix(m.resType) == "azurerm" {
+		location := toString(m.args["location"])
+		if location == "" {
+			location = pl.resourceGroups[strings.ToLower(toString(m.args["resource_group"]))]
+		}
+
+		if location != "" {
+			m.values["location"] = location
+		}
+	}
+}
+
+// providerKey returns the key of the provider config of the resource. AWS
+// modules set the region of each task, so a provider config is added for each
+// region, as Terraform plans have for provider aliases.
+func (pl *plan) providerKey(m *moduleResource, t task) string {
+	prefix := providerPrefix(m.resType)
+	if prefix != "aws" {
+		return prefix
+	}
+
+	region := awsRegion(m.args, t)
+	if region == "" {
+		return prefix
+	}
+
+	key := prefix + "." + region
+	pl.providerConf[key] = map[string]interface{}{
+		"name":  prefix,
+		"alias": region,
+		"expressions": map[string]interface{}{
+			"region": map[string]interface{}{"constant_value": region},
+		},
+	}
+
+	return key
+}
+
+// awsRegion returns the region of an AWS module task from its arguments, the
+// module defaults or the environment of the task, play or block.
+func awsRegion(args map[string]interface{}, t task) string {
+	for _, a := range awsRegionArgs {
+		if r := toString(args[a]); r != "" {
+			return r
+		}
+	}
+
+	for _, key := range moduleDefaultsKeys(t.module) {
+		defaults, _ := t.vars.render(t.moduleDefaults[key]).(map[string]interface{})
+		for _, a := range awsRegionArgs {
+			if r := toString(defaults[a]); r != "" {
+				return r
+			}
+		}
+	}
+
+	env, _ := t.vars.render(t.environment).(map[string]interface{})
+	for _, e := range awsRegionEnv {
+		if r := toString(env[e]); r != "" {
+			return r
+		}
+	}
+
+	return ""
+}
+
+// moduleDefaultsKeys returns the keys of the module_defaults that apply to the
+// module: the module itself, with or without its collection, and the action
+// groups of the AWS collections.
+func moduleDefaultsKeys(module string) []string {
+	short := moduleName(module)
+
+	return []string{
+		module,
+		short,
+		"amazon.aws." + short,
+		"community.aws." + short,
+		"group/aws",
+		"group/amazon.aws.aws",
+		"group/community.aws.aws",
+	}
+}
+
+// moduleName returns the name of the module without its collection.
+func moduleName(module string) string {
+	parts := strings.Split(module, ".")
+	return parts[len(parts)-1]
+}
+
+// resourceName returns the name of the resources of a task in their Terraform
+// addresses, which is the name argument of the module if it doesn't change in
+// a loop, otherwise the name of the task.
+func resourceName(t task, resources []*moduleResource) string {
+	name := ""
+	for i, m := range resources {
+		n := toString(m.args["name"])
+		if i > 0 && n != name {
+			name = ""
+			break
+		}
+		name = n
+	}
+
+	if name == "" {
+		name = t.name
+	}
+
+	name = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return moduleName(t.module)
+	}
+
+	return name
+}
+
+// uniqueName returns the name of the resource in the Terraform address. The
+// name is indexed if the task creates several resources, or if the name has
+// already been used by another task.
+func (pl *plan) uniqueName(resType string, name string, index int) string {
+	candidate := name
+	if index >= 0 {
+		candidate = fmt.Sprintf("%s[%d]", name, index)
+	}
+
+	for i := 2; pl.seen[resType+"."+candidate]; i++ {
+		if index >= 0 {
+			candidate = fmt.Sprintf("%s_%d[%d]", name, i, index)
+		} else {
+			candidate = fmt.Sprintf("%s_%d", name, i)
+		}
+	}
+
+	pl.seen[resType+"."+candidate] = true
+	return candidate
+}
+
+func providerPrefix(resType string) string {
+	return strings.Split(resType, "_")[0]
+}
+
+func removeIndex(name string) string {
+	if i := strings.Index(name, "["); i != -1 {
+		return name[:i]
+	}
+
+	return name
+}
+
+func resourceJSON(r planResource) map[string]interface{} {
+	return map[string]interface{}{
+		"address":       r.address(),
+		"mode":          "managed",
+		"type":          r.resType,
+		"name":          r.name,
+		"provider_name": providerNames[providerPrefix(r.resType)],
+		"values":        r.values,
+	}
+}
+
+func rootModule(resources []planResource) map[string]interface{} {
+	values := make([]interface{}, 0, len(resources))
+	for _, r := range resources {
+		values = append(values, resourceJSON(r))
+	}
+
+	return map[string]interface{}{
+		"resources": values,
+	}
+}
+
+func resourceChanges(resources []planResource) []interface{} {
+	changes := make([]interface{}, 0, len(resources))
+	for _, r := range resources {
+		changes = append(changes, map[string]interface{}{
+			"address": r.address(),
+			"mode":    "managed",
+			"type":    r.resType,
+			"name":    r.name,
+			"change": map[string]interface{}{
+				"actions": []string{"create"},
+				"before":  nil,
+				"after":   r.values,
+			},
+		})
+	}
+
+	return changes
+}
+
+func resourceConfigs(resources []planResource) []interface{} {
+	configs := make([]interface{}, 0, len(resources))
+	added := make(map[string]bool)
+
+	for _, r := range resources {
+		addr := fmt.Sprintf("%s.%s", r.resType, removeIndex(r.name))
+		if added[addr] {
+			continue
+		}
+		added[addr] = true
+
+		configs = append(configs, map[string]interface{}{
+			"address":             addr,
+			"mode":                "managed",
+			"type":                r.resType,
+			"name":                removeIndex(r.name),
+			"provider_config_key": r.providerKey,
+		})
+	}
+
+	return configs
+}
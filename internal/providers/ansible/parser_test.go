@@ -0,0 +1,114 @@
+package ansible
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+func TestToPlanJSON(t *testing.T) {
+	paths, err := findPlaybooks("testdata")
+	require.NoError(t, err)
+
+	p := NewParser(config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{}))
+	j, err := p.toPlanJSON(paths)
+	require.NoError(t, err)
+
+	plan := gjson.ParseBytes(j)
+
+	// The elasticache task is skipped as its state is absent.
+	assert.Equal(t, []interface{}{
+		"azurerm_windows_virtual_machine.app-vm",
+		"azurerm_managed_disk.app-vm_data_disk_0",
+		"aws_s3_bucket.app-logs",
+		"azurerm_storage_account.appstorage",
+		"aws_instance.web[0]",
+		"aws_instance.web[1]",
+		"aws_ebs_volume.data_volumes[0]",
+		"aws_ebs_volume.data_volumes[1]",
+		"aws_db_instance.database",
+		"aws_instance.facts",
+		"aws_sqs_queue.orders_fifo",
+	}, plan.Get("resource_changes.#.address").Value())
+
+	resources := plan.Get("planned_values.root_module.resources")
+
+	vm := resources.Get(`#(type="azurerm_windows_virtual_machine").values`)
+	assert.Equal(t, "Standard_D2s_v3", vm.Get("size").String())
+	assert.Equal(t, "Premium_LRS", vm.Get("os_disk.0.storage_account_type").String())
+	assert.Equal(t, "westeurope", vm.Get("location").String(), "location of the resource group")
+
+	disk := resources.Get(`#(address="azurerm_managed_disk.app-vm_data_disk_0").values`)
+	assert.Equal(t, int64(128), disk.Get("disk_size_gb").Int())
+	assert.Equal(t, "westeurope", disk.Get("location").String())
+
+	storage := resources.Get(`#(type="azurerm_storage_account").values`)
+	assert.Equal(t, "Standard", storage.Get("account_tier").String())
+	assert.Equal(t, "GRS", storage.Get("account_replication_type").String())
+	assert.Equal(t, "northeurope", storage.Get("location").String(), "role vars override role defaults")
+
+	assert.Equal(t, "app-logs", resources.Get(`#(type="aws_s3_bucket").values.bucket`).String())
+
+	web := resources.Get(`#(address="aws_instance.web[1]").values`)
+	assert.Equal(t, "t3.large", web.Get("instance_type").String())
+	assert.Equal(t, int64(50), web.Get("root_block_device.0.volume_size").Int())
+	assert.Equal(t, "gp3", web.Get("root_block_device.0.volume_type").String())
+
+	assert.Equal(t, []interface{}{float64(100), float64(200)}, resources.Get(`#(type="aws_ebs_volume")#.values.size`).Value())
+	assert.Equal(t, "gp3", resources.Get(`#(type="aws_ebs_volume").values.type`).String())
+
+	// The instance type uses a fact, so the default instance type is used.
+	assert.Equal(t, "t2.micro", resources.Get(`#(address="aws_instance.facts").values.instance_type`).String())
+
+	assert.True(t, resources.Get(`#(type="aws_sqs_queue").values.fifo_queue`).Bool())
+
+	conf := plan.Get("configuration")
+	assert.Equal(t, "aws.eu-west-1", conf.Get(`root_module.resources.#(address="aws_instance.web").provider_config_key`).String())
+	assert.Equal(t, "aws.us-west-2", conf.Get(`root_module.resources.#(address="aws_db_instance.database").provider_config_key`).String())
+	assert.Equal(t, "us-west-2", conf.Get(`provider_config.aws\.us-west-2.expressions.region.constant_value`).String())
+}
+
+func TestToPlanJSONNoTasks(t *testing.T) {
+	p := NewParser(config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{}))
+	_, err := p.toPlanJSON(nil)
+	assert.Error(t, err)
+}
+
+func TestLookupModule(t *testing.T) {
+	tests := []struct {
+		module  string
+		resType string
+		ok      bool
+	}{
+		{"amazon.aws.ec2_instance", "aws_instance", true},
+		{"ec2_instance", "aws_instance", true},
+		{"community.aws.sqs_queue", "aws_sqs_queue", true},
+		{"azure.azcollection.azure_rm_storageaccount", "azurerm_storage_account", true},
+		{"azure_rm_storageaccount", "azurerm_storage_account", true},
+		{"google.cloud.gcp_compute_instance", "google_compute_instance", true},
+		{"ansible.builtin.copy", "", false},
+		{"other.collection.ec2_instance", "", false},
+	}
+
+	for _, tt := range tests {
+		m, ok := lookupModule(tt.module)
+		assert.Equal(t, tt.ok, ok, tt.module)
+		assert.Equal(t, tt.resType, m.resType, tt.module)
+	}
+}
+
+func TestParseFreeForm(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{
+		"name":          "web",
+		"instance_type": "t3.micro",
+	}, parseFreeForm("name=web instance_type='t3.micro'"))
+
+	assert.Equal(t, map[string]interface{}{
+		"_raw_params": "tasks/queues.yml",
+	}, parseFreeForm("tasks/queues.yml"))
+}
@@ -0,0 +1,638 @@
+package ansible
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/infracost/infracost/internal/logging"
+)
+
+// builtinPrefixes are the prefixes of the modules that are part of
+// ansible-core, e.g. ansible.builtin.include_tasks.
+var builtinPrefixes = []string{"ansible.builtin.", "ansible.legacy."}
+
+// taskKeywords are the keywords that can be set on a task, so the key of the
+// task that isn't a keyword is its module.
+var taskKeywords = map[string]bool{
+	"name": true, "action": true, "args": true, "any_errors_fatal": true, "async": true,
+	"become": true, "become_exe": true, "become_flags": true, "become_method": true, "become_user": true,
+	"changed_when": true, "check_mode": true, "collections": true, "connection": true, "debugger": true,
+	"delay": true, "delegate_facts": true, "delegate_to": true, "diff": true, "environment": true,
+	"failed_when": true, "ignore_errors": true, "ignore_unreachable": true, "listen": true, "local_action": true,
+	"loop": true, "loop_control": true, "module_defaults": true, "no_log": true, "notify": true,
+	"poll": true, "port": true, "register": true, "remote_user": true, "retries": true,
+	"run_once": true, "tags": true, "throttle": true, "timeout": true, "until": true,
+	"vars": true, "when": true, "with_items": true, "with_list": true, "with_sequence": true,
+	"with_dict": true, "with_nested": true, "with_fileglob": true, "with_together": true, "with_subelements": true,
+}
+
+// task is a task of a playbook that uses a module, with the variables, module
+// defaults and environment of the play, role and blocks it's in.
+type task struct {
+	name           string
+	module         string
+	args           map[string]interface{}
+	vars           vars
+	loop           interface{}
+	loopVar        string
+	moduleDefaults map[string]interface{}
+	environment    map[string]interface{}
+	file           string
+}
+
+// scope is what a task inherits from the play, roles, blocks and includes it's
+// in.
+type scope struct {
+	vars           vars
+	moduleDefaults map[string]interface{}
+	environment    map[string]interface{}
+	dir            string
+	playbookDir    string
+}
+
+func (s scope) child(item map[string]interface{}) scope {
+	c := s
+	if v, ok := item["vars"].(map[string]interface{}); ok {
+		c.vars = s.vars.with(v)
+	}
+
+	if d, ok := item["module_defaults"].(map[string]interface{}); ok {
+		c.moduleDefaults = merge(s.moduleDefaults, d)
+	}
+
+	if e, ok := item["environment"].(map[string]interface{}); ok {
+		c.environment = merge(s.environment, e)
+	}
+
+	return c
+}
+
+// loader collects the tasks of playbooks, including the tasks of the roles and
+// task files they include.
+type loader struct {
+	tasks   []task
+	loading map[string]bool
+}
+
+func newLoader() *loader {
+	return &loader{loading: make(map[string]bool)}
+}
+
+// loadPlaybook adds the tasks of the playbook at path. The tasks of each play
+// are added in the order Ansible runs them: pre_tasks, roles, tasks and then
+// post_tasks. Handlers are skipped as they only run when notified.
+func (l *loader) loadPlaybook(path string, parent vars) error {
+	if l.loading[path] {
+		return nil
+	}
+	l.loading[path] = true
+	defer delete(l.loading, path)
+
+	plays, err := readPlaybook(path)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+
+	for _, play := range plays {
+		if imported, ok := builtinValue(play, "import_playbook"); ok {
+			s := scope{vars: parent}.child(play)
+			p, ok := s.vars.render(imported).(string)
+			if !ok || p == "" {
+				logging.Logger.Debugf("Skipping playbook import in %s as the path could not be resolved", path)
+				continue
+			}
+
+			if err := l.loadPlaybook(resolvePath(dir, p), s.vars); err != nil {
+				return err
+			}
+			continue
+		}
+
+		s := scope{vars: parent, dir: dir, playbookDir: dir}.child(play)
+		s.vars = s.vars.with(loadVarsFiles(s, play["vars_files"]))
+
+		l.addTasks(s, play["pre_tasks"], path)
+
+		if roles, ok := play["roles"].([]interface{}); ok {
+			for _, r := range roles {
+				l.addRoleEntry(s, r)
+			}
+		}
+
+		l.addTasks(s, play["tasks"], path)
+		l.addTasks(s, play["post_tasks"], path)
+	}
+
+	return nil
+}
+
+// addRoleEntry adds the tasks of a role in the roles of a play, which is either
+// the name of the role or a map with the role and its parameters.
+func (l *loader) addRoleEntry(s scope, entry interface{}) {
+	switch r := entry.(type) {
+	case string:
+		l.addRole(s, r, "main", nil)
+	case map[string]interface{}:
+		name, _ := r["role"].(string)
+		if name == "" {
+			name, _ = r["name"].(string)
+		}
+
+		params := make(map[string]interface{})
+		for k, v := range r {
+			if k != "role" && k != "name" && !taskKeywords[k] {
+				params[k] = v
+			}
+		}
+
+		l.addRole(s.child(r), name, "main", params)
+	}
+}
+
+// addRole adds the tasks of the role, after the tasks of the roles it depends
+// on. The role defaults have the lowest precedence, and the role vars and
+// parameters override the variables of the play.
+func (l *loader) addRole(s scope, name string, tasksFrom string, params map[string]interface{}) {
+	rendered, _ := s.vars.render(name).(string)
+	dir := findRole(s.playbookDir, rendered)
+	if dir == "" {
+		logging.Logger.Debugf("Skipping Ansible role %s as it could not be found", name)
+		return
+	}
+
+	key := dir + ":" + tasksFrom
+	if l.loading[key] {
+		return
+	}
+	l.loading[key] = true
+	defer delete(l.loading, key)
+
+	defaults, _ := readYAMLMap(findYAMLFile(filepath.Join(dir, "defaults"), "main"))
+	roleVars, _ := readYAMLMap(findYAMLFile(filepath.Join(dir, "vars"), "main"))
+
+	roleScope := s
+	roleScope.vars = vars(defaults).with(s.vars).with(roleVars).with(params)
+
+	if meta, err := readYAMLMap(findYAMLFile(filepath.Join(dir, "meta"), "main")); err == nil {
+		if deps, ok := meta["dependencies"].([]interface{}); ok {
+			for _, d := range deps {
+				l.addRoleEntry(roleScope, d)
+			}
+		}
+	}
+
+	tasksPath := findYAMLFile(filepath.Join(dir, "tasks"), tasksFrom)
+	if tasksPath == "" {
+		return
+	}
+
+	roleScope.dir = filepath.Dir(tasksPath)
+	l.addTaskFile(roleScope, tasksPath)
+}
+
+// addTaskFile adds the tasks of a file included by a play or role.
+func (l *loader) addTaskFile(s scope, path string) {
+	if l.loading[path] {
+		return
+	}
+	l.loading[path] = true
+	defer delete(l.loading, path)
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		logging.Logger.Debugf("Skipping Ansible task file %s as it could not be read: %s", path, err)
+		return
+	}
+
+	var tasks []interface{}
+	if err := yaml.Unmarshal(b, &tasks); err != nil {
+		logging.Logger.Debugf("Skipping Ansible task file %s as it could not be parsed: %s", path, err)
+		return
+	}
+
+	s.dir = filepath.Dir(path)
+	l.addTasks(s, tasks, path)
+}
+
+// addTasks adds the tasks of a list of tasks, which can include blocks and
+// other task files or roles.
+func (l *loader) addTasks(s scope, list interface{}, file string) {
+	items, ok := list.([]interface{})
+	if !ok {
+		return
+	}
+
+	for _, item := range items {
+		t, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		ts := s.child(t)
+
+		// The rescue section of a block only runs if a task fails, so only the
+		// block and always sections are added.
+		if block, ok := t["block"]; ok {
+			l.addTasks(ts, block, file)
+			l.addTasks(ts, t["always"], file)
+			continue
+		}
+
+		module, args := taskModule(t)
+		if module == "" {
+			continue
+		}
+
+		short := builtinName(module)
+		switch short {
+		case "include_tasks", "import_tasks", "include":
+			l.addInclude(ts, t, args, func(is scope, a map[string]interface{}) {
+				p, _ := is.vars.render(a["file"]).(string)
+				if p == "" {
+					p, _ = is.vars.render(a["_raw_params"]).(string)
+				}
+				if p == "" {
+					logging.Logger.Debugf("Skipping task include in %s as the path could not be resolved", file)
+					return
+				}
+				l.addTaskFile(is, resolvePath(is.dir, p))
+			})
+			continue
+		case "include_role", "import_role":
+			l.addInclude(ts, t, args, func(is scope, a map[string]interface{}) {
+				name, _ := a["name"].(string)
+				tasksFrom, _ := is.vars.render(a["tasks_from"]).(string)
+				if tasksFrom == "" {
+					tasksFrom = "main"
+				}
+				l.addRole(is, name, strings.TrimSuffix(strings.TrimSuffix(tasksFrom, ".yml"), ".yaml"), nil)
+			})
+			continue
+		}
+
+		l.tasks = append(l.tasks, task{
+			name:           stringValue(t["name"]),
+			module:         module,
+			args:           args,
+			vars:           ts.vars,
+			loop:           taskLoop(t),
+			loopVar:        loopVar(t),
+			moduleDefaults: ts.moduleDefaults,
+			environment:    ts.environment,
+			file:           file,
+		})
+	}
+}
+
+// addInclude adds the tasks of an include, once for each item if it has a loop
+// that can be resolved.
+func (l *loader) addInclude(s scope, t map[string]interface{}, args map[string]interface{}, add func(scope, map[string]interface{})) {
+	loop := taskLoop(t)
+	if loop == nil {
+		add(s, args)
+		return
+	}
+
+	items, ok := loopItems(s.vars, loop)
+	if !ok {
+		add(s, args)
+		return
+	}
+
+	for _, item := range items {
+		is := s
+		is.vars = s.vars.with(map[string]interface{}{loopVar(t): item})
+		add(is, args)
+	}
+}
+
+// taskModule returns the module of a task and its arguments, which are either
+// a map or, in the free-form syntax, a string of key=value pairs.
+func taskModule(t map[string]interface{}) (string, map[string]interface{}) {
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if taskKeywords[k] || k == "block" || k == "rescue" || k == "always" {
+			continue
+		}
+
+		args := make(map[string]interface{})
+		switch v := t[k].(type) {
+		case map[string]interface{}:
+			for ak, av := range v {
+				args[ak] = av
+			}
+		case string:
+			args = parseFreeForm(v)
+		}
+
+		if extra, ok := t["args"].(map[string]interface{}); ok {
+			for ak, av := range extra {
+				args[ak] = av
+			}
+		}
+
+		return k, args
+	}
+
+	return "", nil
+}
+
+// parseFreeForm parses the free-form arguments of a module, e.g.
+// name=web instance_type=t3.micro. Any words that aren't key=value pairs are
+// the raw parameters, e.g. the path of include_tasks.
+func parseFreeForm(s string) map[string]interface{} {
+	args := make(map[string]interface{})
+	var raw []string
+
+	for _, field := range splitTopLevel(s, ' ') {
+		if field == "" {
+			continue
+		}
+
+		k, v, ok := strings.Cut(field, "=")
+		if !ok || strings.Contains(k, "{") {
+			raw = append(raw, field)
+			continue
+		}
+
+		args[k] = strings.Trim(v, `'"`)
+	}
+
+	if len(raw) > 0 {
+		args["_raw_params"] = strings.Join(raw, " ")
+	}
+
+	return args
+}
+
+// taskLoop returns the items of the loop of a task, either from loop or the
+// with_items, with_list and with_sequence lookups.
+func taskLoop(t map[string]interface{}) interface{} {
+	for _, k := range []string{"loop", "with_items", "with_list"} {
+		if v, ok := t[k]; ok {
+			return v
+		}
+	}
+
+	if v, ok := t["with_sequence"]; ok {
+		return sequence{spec: v}
+	}
+
+	return nil
+}
+
+func loopVar(t map[string]interface{}) string {
+	if lc, ok := t["loop_control"].(map[string]interface{}); ok {
+		if v, ok := lc["loop_var"].(string); ok && v != "" {
+			return v
+		}
+	}
+
+	return "item"
+}
+
+// sequence is the spec of a with_sequence loop, e.g. count=3 or start=1 end=3.
+type sequence struct {
+	spec interface{}
+}
+
+// loopItems returns the items of a loop, if they can be resolved.
+func loopItems(v vars, loop interface{}) ([]interface{}, bool) {
+	if seq, ok := loop.(sequence); ok {
+		return sequenceItems(v, seq)
+	}
+
+	rendered := v.render(loop)
+	items, ok := rendered.([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	// with_items flattens nested lists one level.
+	var flattened []interface{}
+	for _, item := range items {
+		if l, ok := item.([]interface{}); ok {
+			flattened = append(flattened, l...)
+			continue
+		}
+		flattened = append(flattened, item)
+	}
+
+	return flattened, true
+}
+
+func sequenceItems(v vars, seq sequence) ([]interface{}, bool) {
+	var args map[string]interface{}
+	switch spec := seq.spec.(type) {
+	case string:
+		args = parseFreeForm(spec)
+	case map[string]interface{}:
+		args = spec
+	default:
+		return nil, false
+	}
+
+	get := func(k string, def int) (int, bool) {
+		val, ok := args[k]
+		if !ok {
+			return def, true
+		}
+		return toInt(v.render(val))
+	}
+
+	start, ok := get("start", 1)
+	if !ok {
+		return nil, false
+	}
+
+	end, ok := get("end", start-1)
+	if !ok {
+		return nil, false
+	}
+
+	if _, hasCount := args["count"]; hasCount {
+		count, ok := get("count", 0)
+		if !ok {
+			return nil, false
+		}
+		end = start + count - 1
+	}
+
+	stride, ok := get("stride", 1)
+	if !ok || stride <= 0 {
+		return nil, false
+	}
+
+	var items []interface{}
+	for i := start; i <= end; i += stride {
+		items = append(items, fmt.Sprintf("%d", i))
+	}
+
+	return items, true
+}
+
+// loadVarsFiles returns the variables of the vars_files of a play. Files that
+// can't be found, e.g. because their path uses a variable that's only known
+// when the playbook runs, are skipped.
+func loadVarsFiles(s scope, files interface{}) map[string]interface{} {
+	merged := make(map[string]interface{})
+
+	list, ok := files.([]interface{})
+	if !ok {
+		return merged
+	}
+
+	for _, f := range list {
+		// An item can be a list of files, of which the first that exists is used.
+		candidates, ok := f.([]interface{})
+		if !ok {
+			candidates = []interface{}{f}
+		}
+
+		for _, c := range candidates {
+			p, _ := s.vars.render(c).(string)
+			if p == "" {
+				continue
+			}
+
+			m, err := readYAMLMap(resolvePath(s.dir, p))
+			if err != nil {
+				continue
+			}
+
+			for k, v := range m {
+				merged[k] = v
+			}
+			break
+		}
+	}
+
+	return merged
+}
+
+// findRole returns the directory of the role with the given name, looking in
+// the roles directory next to the playbook as Ansible does by default.
+func findRole(playbookDir string, name string) string {
+	if name == "" {
+		return ""
+	}
+
+	for _, dir := range []string{filepath.Join(playbookDir, "roles", name), filepath.Join(playbookDir, name)} {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+
+	return ""
+}
+
+// findYAMLFile returns the path of the YAML file in dir with the given name,
+// which can have either the .yml or .yaml extension, or no extension.
+func findYAMLFile(dir string, name string) string {
+	for _, ext := range []string{".yml", ".yaml", ""} {
+		p := filepath.Join(dir, name+ext)
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			return p
+		}
+	}
+
+	return ""
+}
+
+func resolvePath(dir string, p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+
+	return filepath.Join(dir, p)
+}
+
+func readPlaybook(path string) ([]map[string]interface{}, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading Ansible playbook %w", err)
+	}
+
+	var plays []map[string]interface{}
+	if err := yaml.Unmarshal(b, &plays); err != nil {
+		return nil, fmt.Errorf("Error parsing Ansible playbook %s %w", path, err)
+	}
+
+	return plays, nil
+}
+
+func readYAMLMap(path string) (map[string]interface{}, error) {
+	if path == "" {
+		return nil, os.ErrNotExist
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// builtinValue returns the value of the key of a builtin module or keyword,
+// which can also be set with its fully qualified name.
+func builtinValue(m map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+
+	for _, prefix := range builtinPrefixes {
+		if v, ok := m[prefix+key]; ok {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// builtinName returns the name of a builtin module without its collection,
+// e.g. include_tasks for ansible.builtin.include_tasks.
+func builtinName(module string) string {
+	for _, prefix := range builtinPrefixes {
+		if strings.HasPrefix(module, prefix) {
+			return strings.TrimPrefix(module, prefix)
+		}
+	}
+
+	return module
+}
+
+func merge(a map[string]interface{}, b map[string]interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, len(a)+len(b))
+	for k, v := range a {
+		m[k] = v
+	}
+
+	for k, v := range b {
+		m[k] = v
+	}
+
+	return m
+}
+
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,177 @@
+package ansible
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// PlaybookProvider estimates the cost of the cloud resources created by the
+// cloud modules of Ansible playbooks. The playbooks are only read, not run,
+// so the estimate is best-effort: arguments that depend on facts or variables
+// that are only known when the playbook runs are left unset, and the defaults
+// of the resources are used instead.
+type PlaybookProvider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+}
+
+func NewPlaybookProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &PlaybookProvider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *PlaybookProvider) Type() string {
+	return "ansible"
+}
+
+func (p *PlaybookProvider) DisplayType() string {
+	return "Ansible playbook"
+}
+
+func (p *PlaybookProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *PlaybookProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
+	spinner := ui.NewSpinner("Extracting only cost-related params from Ansible playbooks", ui.SpinnerOptions{
+		EnableLogging: p.ctx.RunContext.Config.IsLogging(),
+		NoColor:       p.ctx.RunContext.Config.NoColor,
+		Indent:        "  ",
+	})
+	defer spinner.Fail()
+
+	paths, err := findPlaybooks(p.Path)
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+
+	j, err := NewParser(p.ctx).toPlanJSON(paths)
+	if err != nil {
+		return []*schema.Project{}, fmt.Errorf("Error parsing Ansible playbooks %w", err)
+	}
+
+	project, err := terraform.NewPlanJSONProvider(p.ctx, p.includePastResources).LoadResourcesFromSrc(usage, j, nil)
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+
+	project.Metadata.Type = p.Type()
+	p.AddMetadata(project.Metadata)
+
+	spinner.Success()
+
+	return []*schema.Project{project}, nil
+}
+
+// IsPlaybook returns true if path is an Ansible playbook, or a directory that
+// has playbooks.
+func IsPlaybook(path string) bool {
+	paths, err := findPlaybooks(path)
+	return err == nil && len(paths) > 0
+}
+
+// findPlaybooks returns the playbooks at path, which is either a playbook or a
+// directory. Playbooks in a directory that are imported by other playbooks in
+// it aren't returned, so their tasks are only added once.
+func findPlaybooks(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		if !isPlaybookFile(path) {
+			return nil, fmt.Errorf("%s is not an Ansible playbook", path)
+		}
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var playbooks []string
+	imported := make(map[string]bool)
+
+	for _, e := range entries {
+		p := filepath.Join(path, e.Name())
+		if e.IsDir() || !isPlaybookFile(p) {
+			continue
+		}
+
+		playbooks = append(playbooks, p)
+		for _, i := range importedPlaybooks(p) {
+			imported[i] = true
+		}
+	}
+
+	var paths []string
+	for _, p := range playbooks {
+		if !imported[p] {
+			paths = append(paths, p)
+		}
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// isPlaybookFile returns true if the file at path is a YAML list of plays,
+// which either have hosts or import another playbook.
+func isPlaybookFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".yml" && ext != ".yaml" {
+		return false
+	}
+
+	plays, err := readPlaybook(path)
+	if err != nil || len(plays) == 0 {
+		return false
+	}
+
+	for _, play := range plays {
+		if _, ok := play["hosts"]; ok {
+			continue
+		}
+
+		if _, ok := builtinValue(play, "import_playbook"); ok {
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
+
+func importedPlaybooks(path string) []string {
+	plays, err := readPlaybook(path)
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	for _, play := range plays {
+		if p, ok := builtinValue(play, "import_playbook"); ok {
+			if s, ok := p.(string); ok {
+				paths = append(paths, resolvePath(filepath.Dir(path), s))
+			}
+		}
+	}
+
+	return paths
+}
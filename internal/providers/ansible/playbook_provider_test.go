@@ -0,0 +1,65 @@
+package ansible
+
+import (
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestPlaybookProviderLoadResources(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: "testdata", Name: "ansible", Ansible: true}, log.Fields{})
+
+	projects, err := NewPlaybookProvider(ctx, false).LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	project := projects[0]
+	assert.Equal(t, "ansible", project.Name)
+	assert.Equal(t, "ansible", project.Metadata.Type)
+
+	regions := make(map[string]string)
+	for _, r := range project.PartialResources {
+		// Azure resources are priced in the region of their location.
+		key := "region"
+		if strings.HasPrefix(r.ResourceData.Type, "azurerm_") {
+			key = "location"
+		}
+		regions[r.ResourceData.Address] = r.ResourceData.Get(key).String()
+	}
+
+	assert.Equal(t, map[string]string{
+		"azurerm_windows_virtual_machine.app-vm":  "westeurope",
+		"azurerm_managed_disk.app-vm_data_disk_0": "westeurope",
+		"azurerm_storage_account.appstorage":      "northeurope",
+		"aws_s3_bucket.app-logs":                  "eu-west-1",
+		"aws_instance.web[0]":                     "eu-west-1",
+		"aws_instance.web[1]":                     "eu-west-1",
+		"aws_ebs_volume.data_volumes[0]":          "eu-west-1",
+		"aws_ebs_volume.data_volumes[1]":          "eu-west-1",
+		"aws_db_instance.database":                "us-west-2",
+		"aws_instance.facts":                      "eu-west-1",
+		"aws_sqs_queue.orders_fifo":               "eu-west-1",
+	}, regions)
+}
+
+func TestFindPlaybooks(t *testing.T) {
+	paths, err := findPlaybooks("testdata")
+	require.NoError(t, err)
+
+	// azure.yml is imported by site.yml, so it's only loaded once.
+	assert.Equal(t, []string{"testdata/site.yml"}, paths)
+}
+
+func TestIsPlaybook(t *testing.T) {
+	assert.True(t, IsPlaybook("testdata"))
+	assert.True(t, IsPlaybook("testdata/azure.yml"))
+	assert.False(t, IsPlaybook("testdata/group_vars.yml"))
+	assert.False(t, IsPlaybook("testdata/tasks/queues.yml"))
+	assert.False(t, IsPlaybook("testdata/roles"))
+}
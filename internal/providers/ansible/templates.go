@@ -0,0 +1,380 @@
+package ansible
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxRenderDepth limits how deeply variables that reference other variables
+// are rendered, so variables that reference themselves can't loop forever.
+const maxRenderDepth = 10
+
+var (
+	templateExpr  = regexp.MustCompile(`{{(.*?)}}`)
+	singleExpr    = regexp.MustCompile(`^\s*{{([^{}]*)}}\s*$`)
+	varPathPart   = regexp.MustCompile(`^(?:\.([A-Za-z_][A-Za-z0-9_]*)|\[\s*(?:'([^']*)'|"([^"]*)"|(\d+))\s*\])`)
+	varName       = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*`)
+	filterCallArg = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*(?:\((.*)\))?$`)
+)
+
+// vars are the variables that are in scope for a task, from the play, its
+// roles, blocks and the task itself.
+type vars map[string]interface{}
+
+// with returns a copy of the variables with the other variables added, which
+// take precedence.
+func (v vars) with(other map[string]interface{}) vars {
+	merged := make(vars, len(v)+len(other))
+	for k, val := range v {
+		merged[k] = val
+	}
+
+	for k, val := range other {
+		merged[k] = val
+	}
+
+	return merged
+}
+
+// render returns the value with the Jinja2 templates of its strings rendered.
+// Only the templates that can be resolved statically are supported: variables,
+// attributes and indexes of variables, literals and a few filters. Strings
+// with templates that can't be rendered, e.g. because they use facts that are
+// only known when the playbook runs, are removed so the defaults of the
+// resource attributes are used instead.
+func (v vars) render(value interface{}) interface{} {
+	return v.renderDepth(value, 0)
+}
+
+func (v vars) renderDepth(value interface{}, depth int) interface{} {
+	switch val := value.(type) {
+	case string:
+		rendered, ok := v.renderString(val, depth)
+		if !ok {
+			return nil
+		}
+		return rendered
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			if rendered := v.renderDepth(item, depth); rendered != nil {
+				m[k] = rendered
+			}
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, 0, len(val))
+		for _, item := range val {
+			l = append(l, v.renderDepth(item, depth))
+		}
+		return l
+	}
+
+	return value
+}
+
+// renderString renders the templates of s. If s is a single expression, the
+// value of the expression is returned as it is, e.g. as a number or a list, as
+// Ansible does.
+func (v vars) renderString(s string, depth int) (interface{}, bool) {
+	if !strings.Contains(s, "{{") && !strings.Contains(s, "{%") {
+		return s, true
+	}
+
+	if depth > maxRenderDepth || strings.Contains(s, "{%") {
+		return nil, false
+	}
+
+	if m := singleExpr.FindStringSubmatch(s); m != nil {
+		return v.eval(m[1], depth)
+	}
+
+	ok := true
+	rendered := templateExpr.ReplaceAllStringFunc(s, func(match string) string {
+		val, valOK := v.eval(match[2:len(match)-2], depth)
+		if !valOK {
+			ok = false
+			return ""
+		}
+		return toString(val)
+	})
+
+	return rendered, ok
+}
+
+// eval returns the value of a Jinja2 expression, which is a variable or
+// literal followed by any number of filters.
+func (v vars) eval(expr string, depth int) (interface{}, bool) {
+	parts := splitTopLevel(expr, '|')
+
+	val, ok := v.evalTerm(strings.TrimSpace(parts[0]), depth)
+
+	for _, f := range parts[1:] {
+		m := filterCallArg.FindStringSubmatch(strings.TrimSpace(f))
+		if m == nil {
+			return nil, false
+		}
+
+		name := m[1]
+		var args []string
+		if strings.TrimSpace(m[2]) != "" {
+			args = splitTopLevel(m[2], ',')
+		}
+
+		switch name {
+		case "default", "d":
+			useIfFalsy := len(args) > 1 && strings.EqualFold(strings.TrimSpace(args[1]), "true")
+			if !ok || (useIfFalsy && isFalsy(val)) {
+				if len(args) == 0 {
+					return nil, false
+				}
+				val, ok = v.evalTerm(strings.TrimSpace(args[0]), depth)
+			}
+		case "int":
+			if ok {
+				val, ok = toInt(val)
+			}
+		case "float":
+			if ok {
+				val, ok = toFloat(val)
+			}
+		case "string":
+			if ok {
+				val = toString(val)
+			}
+		case "bool":
+			if ok {
+				val = toBool(val)
+			}
+		case "lower":
+			if ok {
+				val = strings.ToLower(toString(val))
+			}
+		case "upper":
+			if ok {
+				val = strings.ToUpper(toString(val))
+			}
+		case "trim":
+			if ok {
+				val = strings.TrimSpace(toString(val))
+			}
+		default:
+			return nil, false
+		}
+	}
+
+	return val, ok
+}
+
+// evalTerm returns the value of a literal or of a variable, including the
+// attributes and indexes of the variable, e.g. instance.volumes[0].size.
+func (v vars) evalTerm(term string, depth int) (interface{}, bool) {
+	if term == "" {
+		return nil, false
+	}
+
+	if (strings.HasPrefix(term, "'") && strings.HasSuffix(term, "'") ||
+		strings.HasPrefix(term, `"`) && strings.HasSuffix(term, `"`)) && len(term) >= 2 {
+		return term[1 : len(term)-1], true
+	}
+
+	switch term {
+	case "true", "True":
+		return true, true
+	case "false", "False":
+		return false, true
+	case "none", "None":
+		return nil, false
+	}
+
+	if i, err := strconv.ParseInt(term, 10, 64); err == nil {
+		return int(i), true
+	}
+
+	if f, err := strconv.ParseFloat(term, 64); err == nil {
+		return f, true
+	}
+
+	name := varName.FindString(term)
+	if name == "" {
+		return nil, false
+	}
+
+	val, ok := v[name]
+	if !ok {
+		return nil, false
+	}
+
+	rest := term[len(name):]
+	for rest != "" {
+		m := varPathPart.FindStringSubmatch(rest)
+		if m == nil {
+			return nil, false
+		}
+		rest = rest[len(m[0]):]
+
+		val, ok = v.renderVar(val, depth)
+		if !ok {
+			return nil, false
+		}
+
+		switch {
+		case m[4] != "":
+			l, isList := val.([]interface{})
+			i, _ := strconv.Atoi(m[4])
+			if !isList || i >= len(l) {
+				return nil, false
+			}
+			val = l[i]
+		default:
+			key := m[1] + m[2] + m[3]
+			obj, isMap := val.(map[string]interface{})
+			if !isMap {
+				return nil, false
+			}
+
+			if val, ok = obj[key]; !ok {
+				return nil, false
+			}
+		}
+	}
+
+	return v.renderVar(val, depth)
+}
+
+// renderVar renders the templates of the value of a variable, since variables
+// can be set from other variables.
+func (v vars) renderVar(val interface{}, depth int) (interface{}, bool) {
+	s, ok := val.(string)
+	if !ok {
+		return v.renderDepth(val, depth+1), true
+	}
+
+	return v.renderString(s, depth+1)
+}
+
+// splitTopLevel splits s on sep, ignoring separators in quotes or brackets.
+func splitTopLevel(s string, sep rune) []string {
+	var parts []string
+	var quote rune
+	level := 0
+	start := 0
+
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			level++
+		case r == ')' || r == ']' || r == '}':
+			level--
+		case r == sep && level == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+func isFalsy(val interface{}) bool {
+	switch v := val.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+func toString(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return fmt.Sprintf("%v", val)
+}
+
+func toInt(val interface{}) (int, bool) {
+	switch v := val.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	case string:
+		i, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return 0, false
+			}
+			return int(f), true
+		}
+		return i, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+func toFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+// toBool returns the boolean value of val, using the values that YAML 1.1 and
+// Ansible treat as booleans, e.g. yes and no.
+func toBool(val interface{}) bool {
+	switch v := val.(type) {
+	case bool:
+		return v
+	case int:
+		return v != 0
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "yes", "on", "y", "1":
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,78 @@
+package ansible
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	v := vars{
+		"instance_type": "t3.large",
+		"count":         2,
+		"size":          "100",
+		"env":           "prod",
+		"name":          "app-{{ env }}",
+		"loop":          "{{ loop }}",
+		"disks":         []interface{}{map[string]interface{}{"size": 50}},
+		"settings":      map[string]interface{}{"db": map[string]interface{}{"class": "db.t3.micro"}},
+		"empty":         "",
+	}
+
+	tests := []struct {
+		value    interface{}
+		expected interface{}
+	}{
+		{"t2.micro", "t2.micro"},
+		{"{{ instance_type }}", "t3.large"},
+		{"{{ count }}", 2},
+		{"{{ size | int }}", 100},
+		{"{{ env | upper }}", "PROD"},
+		{"{{ name }}", "app-prod"},
+		{"{{ name }}-web", "app-prod-web"},
+		{"{{ disks[0].size }}", 50},
+		{"{{ settings.db.class }}", "db.t3.micro"},
+		{"{{ settings['db']['class'] }}", "db.t3.micro"},
+		{"{{ missing | default('gp3') }}", "gp3"},
+		{"{{ missing | d(10) }}", 10},
+		{"{{ empty | default('gp3', true) }}", "gp3"},
+		{"{{ empty | default('gp3') }}", ""},
+		{"{{ missing }}", nil},
+		{"{{ loop }}", nil},
+		{"{{ missing }}-web", nil},
+		{"{% if env == 'prod' %}m5.large{% endif %}", nil},
+		{"{{ instance_type | regex_replace('t3', 't4g') }}", nil},
+		{
+			map[string]interface{}{"type": "{{ instance_type }}", "ami": "{{ ami_id }}"},
+			map[string]interface{}{"type": "t3.large"},
+		},
+		{[]interface{}{"{{ count }}", 3}, []interface{}{2, 3}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, v.render(tt.value), tt.value)
+	}
+}
+
+func TestLoopItems(t *testing.T) {
+	v := vars{"sizes": []interface{}{100, 200}}
+
+	items, ok := loopItems(v, "{{ sizes }}")
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{100, 200}, items)
+
+	items, ok = loopItems(v, []interface{}{[]interface{}{"a", "b"}, "c"})
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{"a", "b", "c"}, items)
+
+	items, ok = loopItems(v, sequence{spec: "count=3"})
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{"1", "2", "3"}, items)
+
+	items, ok = loopItems(v, sequence{spec: "start=0 end=4 stride=2"})
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{"0", "2", "4"}, items)
+
+	_, ok = loopItems(v, "{{ groups['web'] }}")
+	assert.False(t, ok)
+}
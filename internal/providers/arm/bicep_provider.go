@@ -0,0 +1,93 @@
+package arm
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/infracost/infracost/internal/clierror"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// BicepProvider compiles a Bicep file to an ARM template using the Bicep CLI,
+// either the standalone bicep binary or the one bundled with the Azure CLI.
+type BicepProvider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+}
+
+func NewBicepProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &BicepProvider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *BicepProvider) Type() string {
+	return "bicep"
+}
+
+func (p *BicepProvider) DisplayType() string {
+	return "Azure Bicep"
+}
+
+func (p *BicepProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *BicepProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
+	b, err := p.build()
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+
+	project, err := loadTemplate(p.ctx, b, p.Path, usage, p.includePastResources)
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+
+	project.Metadata.Type = p.Type()
+	p.AddMetadata(project.Metadata)
+
+	return []*schema.Project{project}, nil
+}
+
+// build compiles the Bicep file and returns the ARM template.
+func (p *BicepProvider) build() ([]byte, error) {
+	var cmd *exec.Cmd
+	if _, err := exec.LookPath("bicep"); err == nil {
+		cmd = exec.Command("bicep", "build", p.Path, "--stdout")
+	} else if _, err := exec.LookPath("az"); err == nil {
+		cmd = exec.Command("az", "bicep", "build", "--file", p.Path, "--stdout")
+	} else {
+		msg := "Bicep CLI could not be found. You have two options:\n"
+		msg += "1. Install the Bicep CLI, either standalone or with the Azure CLI using az bicep install.\n\n"
+		msg += fmt.Sprintf("2. Set --path to the ARM template generated by running bicep build %s.", filepath.Base(p.Path))
+		return nil, clierror.NewCLIError(errors.New(msg), "Bicep CLI could not be found")
+	}
+
+	logging.Logger.Debugf("Running command: %s", strings.Join(cmd.Args, " "))
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error building Bicep file %s: %s", p.Path, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
+
+// IsBicepFile returns true if path is a Bicep file.
+func IsBicepFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".bicep") && config.FileExists(path)
+}
@@ -0,0 +1,805 @@
+package arm
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/infracost/infracost/internal/logging"
+)
+
+var formatItem = regexp.MustCompile(`\{(\d+)(:[^}]*)?\}`)
+
+// evalContext is the scope that the expressions of a template are evaluated
+// in. Nested deployments with an inner scope have their own context.
+type evalContext struct {
+	params    map[string]interface{}
+	variables map[string]interface{}
+
+	// evaluated are the variables that have already been evaluated, and
+	// evaluating are the ones that are being evaluated, to stop cycles.
+	evaluated  map[string]interface{}
+	evaluating map[string]bool
+
+	copyName  string
+	copyIndex map[string]int
+}
+
+func newEvalContext(template map[string]interface{}, paramValues map[string]interface{}) *evalContext {
+	ctx := &evalContext{
+		params:     make(map[string]interface{}),
+		variables:  make(map[string]interface{}),
+		evaluated:  make(map[string]interface{}),
+		evaluating: make(map[string]bool),
+		copyIndex:  make(map[string]int),
+	}
+
+	if vars, ok := template["variables"].(map[string]interface{}); ok {
+		for k, v := range vars {
+			ctx.variables[strings.ToLower(k)] = v
+		}
+	}
+
+	params, _ := template["parameters"].(map[string]interface{})
+	for k, v := range params {
+		key := strings.ToLower(k)
+
+		if value, ok := lookupKey(paramValues, k); ok {
+			ctx.params[key] = value
+			continue
+		}
+
+		if p, ok := v.(map[string]interface{}); ok {
+			if def, ok := p["defaultValue"]; ok {
+				// Default values can reference other parameters, so these are
+				// evaluated when they're first used.
+				ctx.params[key] = deferredValue{def}
+			}
+		}
+	}
+
+	return ctx
+}
+
+// deferredValue is a parameter default value that hasn't been evaluated yet.
+type deferredValue struct {
+	value interface{}
+}
+
+// withCopy returns a copy of the context for the iteration i of the copy loop
+// name.
+func (c *evalContext) withCopy(name string, i int) *evalContext {
+	copied := *c
+	copied.copyName = name
+	copied.copyIndex = make(map[string]int, len(c.copyIndex)+1)
+	for k, v := range c.copyIndex {
+		copied.copyIndex[k] = v
+	}
+	copied.copyIndex[strings.ToLower(name)] = i
+
+	return &copied
+}
+
+func (c *evalContext) param(name string) interface{} {
+	key := strings.ToLower(name)
+
+	v := c.params[key]
+	if d, ok := v.(deferredValue); ok {
+		// Remove it first so a default value that references itself is nil.
+		delete(c.params, key)
+		v = c.evaluate(d.value)
+		c.params[key] = v
+	}
+
+	return v
+}
+
+func (c *evalContext) variable(name string) interface{} {
+	key := strings.ToLower(name)
+
+	if v, ok := c.evaluated[key]; ok {
+		return v
+	}
+
+	if c.evaluating[key] {
+		return nil
+	}
+
+	c.evaluating[key] = true
+	defer delete(c.evaluating, key)
+
+	var v interface{}
+	if raw, ok := c.variables[key]; ok {
+		v = c.evaluate(raw)
+	} else if loop := c.copyVariable(key); loop != nil {
+		// Variables can also be defined by copy loops.
+		v = c.evaluateLoop(loop)
+	} else {
+		return nil
+	}
+
+	c.evaluated[key] = v
+	return v
+}
+
+// copyVariable returns the copy loop that defines the variable key.
+func (c *evalContext) copyVariable(key string) map[string]interface{} {
+	loops, _ := c.variables["copy"].([]interface{})
+	for _, l := range loops {
+		loop, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if name, _ := loop["name"].(string); strings.EqualFold(name, key) {
+			return loop
+		}
+	}
+
+	return nil
+}
+
+// evaluate evaluates the expressions of a template value, which are strings
+// wrapped in square brackets.
+func (c *evalContext) evaluate(v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		if strings.HasPrefix(val, "[[") {
+			return val[1:]
+		}
+
+		if strings.HasPrefix(val, "[") && strings.HasSuffix(val, "]") {
+			result, err := c.evaluateExpression(val[1 : len(val)-1])
+			if err != nil {
+				logging.Logger.Debugf("Could not evaluate ARM template expression %s: %s", val, err)
+				return nil
+			}
+
+			return result
+		}
+
+		return val
+	case map[string]interface{}:
+		if loops, ok := val["copy"].([]interface{}); ok && isPropertyCopy(loops) {
+			return c.evaluatePropertyCopy(val, loops)
+		}
+
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[k] = c.evaluate(item)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, 0, len(val))
+		for _, item := range val {
+			l = append(l, c.evaluate(item))
+		}
+		return l
+	}
+
+	return v
+}
+
+func isPropertyCopy(loops []interface{}) bool {
+	for _, l := range loops {
+		loop, ok := l.(map[string]interface{})
+		if !ok {
+			return false
+		}
+
+		if _, ok := loop["input"]; !ok {
+			return false
+		}
+	}
+
+	return len(loops) > 0
+}
+
+// evaluatePropertyCopy expands the copy loops of an object, each of which sets
+// the property with the loop name to the list of its evaluated inputs.
+func (c *evalContext) evaluatePropertyCopy(obj map[string]interface{}, loops []interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, len(obj))
+	for k, item := range obj {
+		if k == "copy" {
+			continue
+		}
+		m[k] = c.evaluate(item)
+	}
+
+	for _, l := range loops {
+		loop := l.(map[string]interface{})
+		name, _ := loop["name"].(string)
+		m[name] = c.evaluateLoop(loop)
+	}
+
+	return m
+}
+
+// evaluateLoop returns the evaluated inputs of each iteration of a copy loop.
+func (c *evalContext) evaluateLoop(loop map[string]interface{}) []interface{} {
+	name, _ := loop["name"].(string)
+	count := toInt(c.evaluate(loop["count"]))
+
+	items := make([]interface{}, 0, count)
+	for i := 0; i < count; i++ {
+		items = append(items, c.withCopy(name, i).evaluate(loop["input"]))
+	}
+
+	return items
+}
+
+func (c *evalContext) evaluateExpression(expr string) (interface{}, error) {
+	p := &exprParser{src: expr}
+
+	node, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.src[p.pos:], p.pos)
+	}
+
+	return c.eval(node), nil
+}
+
+// exprNode is a node of a parsed expression. Exactly one of the fields is set,
+// apart from object, which is set for property and index accesses.
+type exprNode struct {
+	literal interface{}
+	isLit   bool
+
+	function string
+	args     []*exprNode
+
+	object   *exprNode
+	property string
+	index    *exprNode
+}
+
+type exprParser struct {
+	src string
+	pos int
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *exprParser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+
+	return p.src[p.pos]
+}
+
+func (p *exprParser) expect(b byte) error {
+	if p.peek() != b {
+		return fmt.Errorf("expected %q at position %d", b, p.pos)
+	}
+
+	p.pos++
+	return nil
+}
+
+func (p *exprParser) parseExpr() (*exprNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.peek() {
+		case '.':
+			p.pos++
+			p.skipSpace()
+			name := p.parseIdent()
+			if name == "" {
+				return nil, fmt.Errorf("expected property name at position %d", p.pos)
+			}
+			node = &exprNode{object: node, property: name}
+		case '[':
+			p.pos++
+			index, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(']'); err != nil {
+				return nil, err
+			}
+			node = &exprNode{object: node, index: index}
+		default:
+			return node, nil
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (*exprNode, error) {
+	c := p.peek()
+
+	switch {
+	case c == '\'':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &exprNode{literal: s, isLit: true}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		f, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+		if err != nil {
+			return nil, err
+		}
+		return &exprNode{literal: f, isLit: true}, nil
+	}
+
+	name := p.parseIdent()
+	if name == "" {
+		return nil, fmt.Errorf("unexpected %q at position %d", c, p.pos)
+	}
+
+	if p.peek() != '(' {
+		switch strings.ToLower(name) {
+		case "true":
+			return &exprNode{literal: true, isLit: true}, nil
+		case "false":
+			return &exprNode{literal: false, isLit: true}, nil
+		case "null":
+			return &exprNode{literal: nil, isLit: true}, nil
+		}
+
+		return nil, fmt.Errorf("expected ( after %s", name)
+	}
+	p.pos++
+
+	node := &exprNode{function: strings.ToLower(name)}
+	if p.peek() == ')' {
+		p.pos++
+		return node, nil
+	}
+
+	for {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		node.args = append(node.args, arg)
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return node, nil
+		default:
+			return nil, fmt.Errorf("expected , or ) at position %d", p.pos)
+		}
+	}
+}
+
+func (p *exprParser) parseIdent() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		r := rune(p.src[p.pos])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '$' {
+			break
+		}
+		p.pos++
+	}
+
+	return p.src[start:p.pos]
+}
+
+// parseString parses a string literal, in which quotes are escaped by doubling
+// them.
+func (p *exprParser) parseString() (string, error) {
+	p.pos++
+
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+
+		if c == '\'' {
+			if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+				b.WriteByte('\'')
+				p.pos++
+				continue
+			}
+			return b.String(), nil
+		}
+
+		b.WriteByte(c)
+	}
+
+	return "", fmt.Errorf("unterminated string")
+}
+
+func (c *evalContext) eval(n *exprNode) interface{} {
+	if n.isLit {
+		return n.literal
+	}
+
+	if n.object != nil {
+		obj := c.eval(n.object)
+
+		if n.index != nil {
+			return indexValue(obj, c.eval(n.index))
+		}
+
+		return indexValue(obj, n.property)
+	}
+
+	args := make([]interface{}, 0, len(n.args))
+	for _, a := range n.args {
+		args = append(args, c.eval(a))
+	}
+
+	return c.call(n.function, args)
+}
+
+// call calls the template function name. Functions that return values that are
+// only known when the template is deployed, like reference, return nil.
+func (c *evalContext) call(name string, args []interface{}) interface{} {
+	arg := func(i int) interface{} {
+		if i < len(args) {
+			return args[i]
+		}
+		return nil
+	}
+
+	switch name {
+	case "parameters":
+		return c.param(toString(arg(0)))
+	case "variables":
+		return c.variable(toString(arg(0)))
+	case "copyindex":
+		loop := c.copyName
+		offset := 0
+		for _, a := range args {
+			if s, ok := a.(string); ok {
+				loop = s
+			} else {
+				offset = toInt(a)
+			}
+		}
+		return float64(c.copyIndex[strings.ToLower(loop)] + offset)
+	case "resourcegroup":
+		return map[string]interface{}{"name": "", "location": "", "id": "", "properties": map[string]interface{}{}}
+	case "subscription":
+		return map[string]interface{}{"subscriptionId": "", "id": "", "tenantId": ""}
+	case "tenant":
+		return map[string]interface{}{"tenantId": ""}
+	case "deployment":
+		return map[string]interface{}{"name": "", "properties": map[string]interface{}{}}
+	case "environment":
+		return map[string]interface{}{"name": "AzureCloud"}
+	case "concat":
+		if _, ok := arg(0).([]interface{}); ok {
+			var l []interface{}
+			for _, a := range args {
+				if items, ok := a.([]interface{}); ok {
+					l = append(l, items...)
+				}
+			}
+			return l
+		}
+		var b strings.Builder
+		for _, a := range args {
+			b.WriteString(toString(a))
+		}
+		return b.String()
+	case "format":
+		return formatItem.ReplaceAllStringFunc(toString(arg(0)), func(m string) string {
+			i, _ := strconv.Atoi(formatItem.FindStringSubmatch(m)[1])
+			return toString(arg(i + 1))
+		})
+	case "tolower":
+		return strings.ToLower(toString(arg(0)))
+	case "toupper":
+		return strings.ToUpper(toString(arg(0)))
+	case "trim":
+		return strings.TrimSpace(toString(arg(0)))
+	case "replace":
+		return strings.ReplaceAll(toString(arg(0)), toString(arg(1)), toString(arg(2)))
+	case "split":
+		parts := strings.Split(toString(arg(0)), toString(arg(1)))
+		l := make([]interface{}, 0, len(parts))
+		for _, part := range parts {
+			l = append(l, part)
+		}
+		return l
+	case "substring":
+		s := toString(arg(0))
+		start := toInt(arg(1))
+		end := len(s)
+		if len(args) > 2 {
+			end = start + toInt(arg(2))
+		}
+		if start < 0 || start > len(s) || end < start || end > len(s) {
+			return nil
+		}
+		return s[start:end]
+	case "startswith":
+		return strings.HasPrefix(strings.ToLower(toString(arg(0))), strings.ToLower(toString(arg(1))))
+	case "endswith":
+		return strings.HasSuffix(strings.ToLower(toString(arg(0))), strings.ToLower(toString(arg(1))))
+	case "string":
+		return toString(arg(0))
+	case "int":
+		return float64(toInt(arg(0)))
+	case "bool":
+		return toBool(arg(0))
+	case "json":
+		var v interface{}
+		if err := json.Unmarshal([]byte(toString(arg(0))), &v); err != nil {
+			return nil
+		}
+		return v
+	case "uniquestring":
+		return uniqueString(args)
+	case "guid":
+		sum := hashArgs(args)
+		h := hex.EncodeToString(sum[:16])
+		return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32])
+	case "if":
+		if toBool(arg(0)) {
+			return arg(1)
+		}
+		return arg(2)
+	case "equals":
+		return valuesEqual(arg(0), arg(1))
+	case "not":
+		return !toBool(arg(0))
+	case "and":
+		for _, a := range args {
+			if !toBool(a) {
+				return false
+			}
+		}
+		return true
+	case "or":
+		for _, a := range args {
+			if toBool(a) {
+				return true
+			}
+		}
+		return false
+	case "greater":
+		return compare(arg(0), arg(1)) > 0
+	case "greaterorequals":
+		return compare(arg(0), arg(1)) >= 0
+	case "less":
+		return compare(arg(0), arg(1)) < 0
+	case "lessorequals":
+		return compare(arg(0), arg(1)) <= 0
+	case "coalesce":
+		for _, a := range args {
+			if a != nil {
+				return a
+			}
+		}
+		return nil
+	case "empty":
+		return length(arg(0)) == 0
+	case "length":
+		return float64(length(arg(0)))
+	case "contains":
+		return contains(arg(0), arg(1))
+	case "first":
+		if l, ok := arg(0).([]interface{}); ok && len(l) > 0 {
+			return l[0]
+		}
+		if s, ok := arg(0).(string); ok && s != "" {
+			return s[:1]
+		}
+		return nil
+	case "last":
+		if l, ok := arg(0).([]interface{}); ok && len(l) > 0 {
+			return l[len(l)-1]
+		}
+		if s, ok := arg(0).(string); ok && s != "" {
+			return s[len(s)-1:]
+		}
+		return nil
+	case "createarray":
+		return append([]interface{}{}, args...)
+	case "createobject":
+		m := make(map[string]interface{}, len(args)/2)
+		for i := 0; i+1 < len(args); i += 2 {
+			m[toString(args[i])] = args[i+1]
+		}
+		return m
+	case "union":
+		m := make(map[string]interface{})
+		for _, a := range args {
+			if obj, ok := a.(map[string]interface{}); ok {
+				for k, v := range obj {
+					m[k] = v
+				}
+			}
+		}
+		return m
+	case "resourceid", "subscriptionresourceid", "tenantresourceid", "extensionresourceid":
+		parts := make([]string, 0, len(args))
+		for _, a := range args {
+			parts = append(parts, toString(a))
+		}
+		return strings.Join(parts, "/")
+	}
+
+	logging.Logger.Debugf("ARM template function %s is not supported or only known when deployed", name)
+	return nil
+}
+
+// lookupKey returns the value of the key of m, ignoring case like ARM does.
+func lookupKey(m map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+func indexValue(obj interface{}, index interface{}) interface{} {
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		v, _ := lookupKey(o, toString(index))
+		return v
+	case []interface{}:
+		i := toInt(index)
+		if i >= 0 && i < len(o) {
+			return o[i]
+		}
+	}
+
+	return nil
+}
+
+func toString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+func toInt(v interface{}) int {
+	switch val := v.(type) {
+	case float64:
+		return int(val)
+	case int:
+		return val
+	case string:
+		i, _ := strconv.Atoi(val)
+		return i
+	case bool:
+		if val {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func toBool(v interface{}) bool {
+	switch val := v.(type) {
+	case bool:
+		return val
+	case string:
+		return strings.EqualFold(val, "true")
+	case float64:
+		return val != 0
+	}
+
+	return false
+}
+
+func valuesEqual(a interface{}, b interface{}) bool {
+	if sa, ok := a.(string); ok {
+		if sb, ok := b.(string); ok {
+			return strings.EqualFold(sa, sb)
+		}
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func compare(a interface{}, b interface{}) int {
+	if fa, ok := a.(float64); ok {
+		fb, _ := b.(float64)
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(toString(a), toString(b))
+}
+
+func length(v interface{}) int {
+	switch val := v.(type) {
+	case string:
+		return len(val)
+	case []interface{}:
+		return len(val)
+	case map[string]interface{}:
+		return len(val)
+	}
+
+	return 0
+}
+
+func contains(container interface{}, item interface{}) bool {
+	switch c := container.(type) {
+	case string:
+		return strings.Contains(c, toString(item))
+	case []interface{}:
+		for _, v := range c {
+			if valuesEqual(v, item) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		_, ok := lookupKey(c, toString(item))
+		return ok
+	}
+
+	return false
+}
+
+func hashArgs(args []interface{}) [32]byte {
+	parts := make([]string, 0, len(args))
+	for _, a := range args {
+		parts = append(parts, toString(a))
+	}
+
+	return sha256.Sum256([]byte(strings.Join(parts, "-")))
+}
+
+// uniqueString returns a deterministic 13 character string for args. It
+// doesn't match the value Azure generates, but only has to be stable so
+// resource names that use it don't change between runs.
+func uniqueString(args []interface{}) string {
+	sum := hashArgs(args)
+	s := strings.ToLower(base32.StdEncoding.EncodeToString(sum[:]))
+
+	return s[:13]
+}
@@ -0,0 +1,83 @@
+package arm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	template := map[string]interface{}{
+		"parameters": map[string]interface{}{
+			"env":      map[string]interface{}{"type": "string", "defaultValue": "dev"},
+			"name":     map[string]interface{}{"type": "string", "defaultValue": "[concat(parameters('env'), '-app')]"},
+			"sizes":    map[string]interface{}{"type": "object", "defaultValue": map[string]interface{}{"dev": "Standard_B1s", "prod": "Standard_D2s_v3"}},
+			"count":    map[string]interface{}{"type": "int"},
+			"location": map[string]interface{}{"type": "string", "defaultValue": "[resourceGroup().location]"},
+		},
+		"variables": map[string]interface{}{
+			"size":   "[parameters('sizes')[parameters('env')]]",
+			"isProd": "[equals(parameters('env'), 'PROD')]",
+			"copy": []interface{}{
+				map[string]interface{}{"name": "disks", "count": 2, "input": "[format('disk{0}', copyIndex('disks', 1))]"},
+			},
+		},
+	}
+
+	ctx := newEvalContext(template, map[string]interface{}{"Env": "prod", "count": float64(3)})
+
+	tests := []struct {
+		expr     string
+		expected interface{}
+	}{
+		{"plain", "plain"},
+		{"[[literal]", "[literal]"},
+		{"[parameters('env')]", "prod"},
+		{"[parameters('NAME')]", "prod-app"},
+		{"[parameters('count')]", float64(3)},
+		{"[parameters('location')]", ""},
+		{"[variables('size')]", "Standard_D2s_v3"},
+		{"[variables('isProd')]", true},
+		{"[variables('disks')]", []interface{}{"disk1", "disk2"}},
+		{"[format('{0}-{1:D3}', 'vm', 7)]", "vm-7"},
+		{"[toUpper(substring('storage', 0, 3))]", "STO"},
+		{"[if(greater(parameters('count'), 2), 'many', 'few')]", "many"},
+		{"[length(split('a,b,c', ','))]", float64(3)},
+		{"[contains(createArray('a', 'b'), 'b')]", true},
+		{"[union(createObject('a', 1), createObject('b', 2)).b]", float64(2)},
+		{"[coalesce(null(), 'default')]", "default"},
+		{"[concat(createArray(1), createArray(2))]", []interface{}{float64(1), float64(2)}},
+		{"[reference('app').properties]", nil},
+		{"[parameters('env']", nil},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, ctx.evaluate(tt.expr), tt.expr)
+	}
+
+	assert.Equal(t, ctx.evaluate("[uniqueString('a', 'b')]"), ctx.evaluate("[uniqueString('a', 'b')]"))
+	assert.Len(t, ctx.evaluate("[uniqueString('a', 'b')]"), 13)
+}
+
+func TestEvaluatePropertyCopy(t *testing.T) {
+	ctx := newEvalContext(map[string]interface{}{}, nil)
+
+	v := ctx.evaluate(map[string]interface{}{
+		"osDisk": map[string]interface{}{"createOption": "FromImage"},
+		"copy": []interface{}{
+			map[string]interface{}{
+				"name":  "dataDisks",
+				"count": 2,
+				"input": map[string]interface{}{"lun": "[copyIndex('dataDisks')]"},
+			},
+		},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"osDisk": map[string]interface{}{"createOption": "FromImage"},
+		"dataDisks": []interface{}{
+			map[string]interface{}{"lun": float64(0)},
+			map[string]interface{}{"lun": float64(1)},
+		},
+	}, v)
+}
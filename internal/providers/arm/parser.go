@@ -0,0 +1,769 @@
+package arm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/providers/terraform"
+)
+
+const (
+	providerName   = "registry.terraform.io/hashicorp/azurerm"
+	deploymentType = "microsoft.resources/deployments"
+)
+
+// resourceTypes are the azurerm resource types of the ARM resource types, keyed
+// by the lower case ARM type since these are case insensitive. The convert
+// function sets the attributes of the Terraform resource from the ARM resource,
+// since these are named differently, and can change the resource type, e.g.
+// for virtual machines that are either Linux or Windows.
+var resourceTypes = map[string]resourceMapping{
+	"microsoft.storage/storageaccounts":                                              {"azurerm_storage_account", convertStorageAccount},
+	"microsoft.storage/storageaccounts/encryptionscopes":                             {"azurerm_storage_encryption_scope", nil},
+	"microsoft.storage/storageaccounts/managementpolicies":                           {"azurerm_storage_management_policy", nil},
+	"microsoft.storage/storageaccounts/blobservices/containers":                      {"azurerm_storage_container", nil},
+	"microsoft.storage/storageaccounts/queueservices/queues":                         {"azurerm_storage_queue", nil},
+	"microsoft.storage/storageaccounts/fileservices/shares":                          {"azurerm_storage_share", convertStorageShare},
+	"microsoft.storage/storageaccounts/tableservices/tables":                         {"azurerm_storage_table", nil},
+	"microsoft.compute/disks":                                                        {"azurerm_managed_disk", convertManagedDisk},
+	"microsoft.compute/snapshots":                                                    {"azurerm_snapshot", nil},
+	"microsoft.compute/images":                                                       {"azurerm_image", nil},
+	"microsoft.compute/availabilitysets":                                             {"azurerm_availability_set", nil},
+	"microsoft.compute/sshpublickeys":                                                {"azurerm_ssh_public_key", nil},
+	"microsoft.compute/virtualmachines":                                              {"azurerm_linux_virtual_machine", convertVirtualMachine},
+	"microsoft.compute/virtualmachines/extensions":                                   {"azurerm_virtual_machine_extension", nil},
+	"microsoft.compute/virtualmachinescalesets":                                      {"azurerm_linux_virtual_machine_scale_set", convertVirtualMachineScaleSet},
+	"microsoft.compute/virtualmachinescalesets/extensions":                           {"azurerm_virtual_machine_scale_set_extension", nil},
+	"microsoft.storage/storageaccounts/blobservices":                                 {"", nil},
+	"microsoft.storage/storageaccounts/fileservices":                                 {"", nil},
+	"microsoft.storage/storageaccounts/queueservices":                                {"", nil},
+	"microsoft.storage/storageaccounts/tableservices":                                {"", nil},
+	"microsoft.storage/storageaccounts/blobservices/containers/immutabilitypolicies": {"", nil},
+}
+
+type resourceMapping struct {
+	resType string
+	convert func(r *armResource) (string, map[string]interface{}, []planResource)
+}
+
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+type Parser struct {
+	ctx *config.ProjectContext
+}
+
+func NewParser(ctx *config.ProjectContext) *Parser {
+	return &Parser{ctx}
+}
+
+// armResource is an ARM template resource with its expressions evaluated.
+type armResource struct {
+	armType  string
+	name     string
+	location string
+	sku      map[string]interface{}
+	kind     string
+	tags     map[string]interface{}
+	props    map[string]interface{}
+
+	// addrName and addrIndex are the name and index of the resource in its
+	// Terraform address.
+	addrName  string
+	addrIndex int
+}
+
+// planResource is a resource of a Terraform plan JSON.
+type planResource struct {
+	resType string
+	name    string
+	values  map[string]interface{}
+}
+
+// planModule is a module of a Terraform plan JSON, which nested deployments are
+// converted to.
+type planModule struct {
+	name      string
+	address   string
+	resources []planResource
+	children  []*planModule
+
+	seen map[string]bool
+}
+
+func (m *planModule) resourceAddress(r planResource) string {
+	addr := fmt.Sprintf("%s.%s", r.resType, r.name)
+	if m.address != "" {
+		addr = fmt.Sprintf("%s.%s", m.address, addr)
+	}
+
+	return addr
+}
+
+// toPlanJSON converts the ARM template, with the given parameter values, to a
+// Terraform plan JSON with the planned values of its resources.
+func (p *Parser) toPlanJSON(b []byte, paramValues map[string]interface{}) ([]byte, error) {
+	var template map[string]interface{}
+	if err := json.Unmarshal(b, &template); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if _, ok := template["resources"]; !ok {
+		return nil, errors.New("no resources found, the file must be an ARM template")
+	}
+
+	root := &planModule{seen: make(map[string]bool)}
+	p.addTemplate(root, template, newEvalContext(template, paramValues))
+
+	plan := map[string]interface{}{
+		"format_version": "1.0",
+		"planned_values": map[string]interface{}{
+			"root_module": moduleValues(root),
+		},
+		"resource_changes": resourceChanges(root),
+		"configuration": map[string]interface{}{
+			"root_module": moduleConfig(root),
+		},
+	}
+
+	return json.Marshal(plan)
+}
+
+// addTemplate adds the resources of the template to module, evaluating their
+// expressions with ctx.
+func (p *Parser) addTemplate(module *planModule, template map[string]interface{}, ctx *evalContext) {
+	locations := make(map[string]string)
+
+	var resources []*armResource
+	for _, entry := range templateResources(template) {
+		for _, r := range p.evaluateResource(entry.value, entry.symbolicName, "", "", ctx) {
+			if strings.EqualFold(r.raw.armType, deploymentType) {
+				p.addDeployment(module, r, ctx)
+				continue
+			}
+
+			resources = append(resources, r.raw)
+			locations[strings.ToLower(r.raw.armType+"/"+r.raw.name)] = r.raw.location
+
+			addr := r.symbolicName
+			if addr == "" && r.index >= 0 {
+				addr = r.loopName
+			}
+			if addr == "" {
+				addr = lastSegment(r.raw.name)
+			}
+
+			r.raw.addrName = addr
+			r.raw.addrIndex = r.index
+		}
+	}
+
+	for _, r := range resources {
+		// Child resources, e.g. storage queues, don't have a location so use the
+		// location of their parent resource.
+		if r.location == "" {
+			r.location = parentLocation(r, locations)
+		}
+
+		module.addResource(r)
+	}
+}
+
+// templateEntry is a resource of a template. Templates with symbolic names,
+// which Bicep generates for language version 2.0, have a map of resources
+// keyed by the symbolic name rather than a list.
+type templateEntry struct {
+	symbolicName string
+	value        map[string]interface{}
+}
+
+func templateResources(template map[string]interface{}) []templateEntry {
+	var entries []templateEntry
+
+	switch resources := template["resources"].(type) {
+	case []interface{}:
+		for _, r := range resources {
+			if m, ok := r.(map[string]interface{}); ok {
+				entries = append(entries, templateEntry{value: m})
+			}
+		}
+	case map[string]interface{}:
+		names := make([]string, 0, len(resources))
+		for name := range resources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if m, ok := resources[name].(map[string]interface{}); ok {
+				entries = append(entries, templateEntry{symbolicName: name, value: m})
+			}
+		}
+	}
+
+	return entries
+}
+
+// evaluatedResource is a resource of a template with its expressions evaluated.
+// Resources with a copy loop are evaluated once for each iteration.
+type evaluatedResource struct {
+	raw          *armResource
+	symbolicName string
+	loopName     string
+	index        int
+	template     map[string]interface{}
+	params       map[string]interface{}
+	innerScope   bool
+}
+
+func (p *Parser) evaluateResource(r map[string]interface{}, symbolicName string, parentType string, parentName string, ctx *evalContext) []evaluatedResource {
+	if existing, _ := r["existing"].(bool); existing {
+		return nil
+	}
+
+	count := -1
+	loopName := ""
+	if loop, ok := r["copy"].(map[string]interface{}); ok {
+		loopName, _ = loop["name"].(string)
+		count = toInt(ctx.evaluate(loop["count"]))
+	}
+
+	if count == -1 {
+		return p.evaluateIteration(r, symbolicName, parentType, parentName, -1, ctx)
+	}
+
+	var evaluated []evaluatedResource
+	for i := 0; i < count; i++ {
+		for _, e := range p.evaluateIteration(r, symbolicName, parentType, parentName, i, ctx.withCopy(loopName, i)) {
+			e.loopName = loopName
+			evaluated = append(evaluated, e)
+		}
+	}
+
+	return evaluated
+}
+
+func (p *Parser) evaluateIteration(r map[string]interface{}, symbolicName string, parentType string, parentName string, index int, ctx *evalContext) []evaluatedResource {
+	if cond, ok := r["condition"]; ok && !toBool(ctx.evaluate(cond)) {
+		return nil
+	}
+
+	armType, _ := r["type"].(string)
+	name := toString(ctx.evaluate(r["name"]))
+
+	// The type and name of nested child resources are relative to the parent,
+	// unless the type includes the namespace, e.g. Microsoft.Storage.
+	if parentType != "" && !strings.Contains(strings.Split(armType, "/")[0], ".") {
+		armType = parentType + "/" + armType
+		name = parentName + "/" + name
+	}
+
+	res := &armResource{
+		armType:  armType,
+		name:     name,
+		location: toString(ctx.evaluate(r["location"])),
+		kind:     toString(ctx.evaluate(r["kind"])),
+	}
+	res.sku, _ = ctx.evaluate(r["sku"]).(map[string]interface{})
+	res.tags, _ = ctx.evaluate(r["tags"]).(map[string]interface{})
+
+	e := evaluatedResource{raw: res, symbolicName: symbolicName, index: index}
+
+	if strings.EqualFold(armType, deploymentType) {
+		// The template of a nested deployment is evaluated in its own scope,
+		// unless it's set to use the outer scope.
+		props, _ := r["properties"].(map[string]interface{})
+		e.template, _ = props["template"].(map[string]interface{})
+		e.params = deploymentParams(props, ctx)
+
+		opts, _ := props["expressionEvaluationOptions"].(map[string]interface{})
+		scope, _ := opts["scope"].(string)
+		e.innerScope = strings.EqualFold(scope, "inner")
+
+		return []evaluatedResource{e}
+	}
+
+	res.props, _ = ctx.evaluate(r["properties"]).(map[string]interface{})
+
+	evaluated := []evaluatedResource{e}
+
+	if children, ok := r["resources"].([]interface{}); ok {
+		for _, c := range children {
+			if m, ok := c.(map[string]interface{}); ok {
+				for _, child := range p.evaluateResource(m, "", armType, name, ctx) {
+					if child.raw.location == "" {
+						child.raw.location = res.location
+					}
+					evaluated = append(evaluated, child)
+				}
+			}
+		}
+	}
+
+	return evaluated
+}
+
+func deploymentParams(props map[string]interface{}, ctx *evalContext) map[string]interface{} {
+	params := make(map[string]interface{})
+
+	raw, _ := props["parameters"].(map[string]interface{})
+	for k, v := range raw {
+		if m, ok := v.(map[string]interface{}); ok {
+			params[k] = ctx.evaluate(m["value"])
+		}
+	}
+
+	return params
+}
+
+// addDeployment adds the resources of a nested deployment, such as a Bicep
+// module, as a child module.
+func (p *Parser) addDeployment(module *planModule, r evaluatedResource, ctx *evalContext) {
+	if r.template == nil {
+		logging.Logger.Debugf("Skipping deployment %s as it links to a template that isn't included", r.raw.name)
+		return
+	}
+
+	name := r.symbolicName
+	if name == "" && r.index >= 0 {
+		name = r.loopName
+	}
+	if name == "" {
+		name = r.raw.name
+	}
+	name = invalidNameChars.ReplaceAllString(name, "_")
+	if r.index >= 0 {
+		name = fmt.Sprintf("%s_%d", name, r.index)
+	}
+
+	address := "module." + name
+	if module.address != "" {
+		address = module.address + "." + address
+	}
+
+	child := &planModule{
+		name:    name,
+		address: address,
+		seen:    make(map[string]bool),
+	}
+
+	childCtx := ctx
+	if r.innerScope {
+		childCtx = newEvalContext(r.template, r.params)
+	}
+
+	p.addTemplate(child, r.template, childCtx)
+	module.children = append(module.children, child)
+}
+
+func parentLocation(r *armResource, locations map[string]string) string {
+	typeParts := strings.Split(r.armType, "/")
+	nameParts := strings.Split(r.name, "/")
+
+	for i := len(nameParts) - 1; i > 0; i-- {
+		if len(typeParts) < i+1 {
+			continue
+		}
+
+		key := strings.ToLower(strings.Join(typeParts[:i+1], "/") + "/" + strings.Join(nameParts[:i], "/"))
+		if loc := locations[key]; loc != "" {
+			return loc
+		}
+	}
+
+	return ""
+}
+
+// addResource adds the Terraform resources of the ARM resource to the module.
+func (m *planModule) addResource(r *armResource) {
+	mapping, ok := resourceTypes[strings.ToLower(r.armType)]
+	if !ok {
+		mapping = resourceMapping{resType: fallbackType(r.armType)}
+	}
+
+	if mapping.resType == "" {
+		return
+	}
+
+	resType := mapping.resType
+	values := map[string]interface{}{
+		"name":     r.name,
+		"location": r.location,
+	}
+	if r.tags != nil {
+		values["tags"] = r.tags
+	}
+
+	var extra []planResource
+	if mapping.convert != nil {
+		var converted map[string]interface{}
+		resType, converted, extra = mapping.convert(r)
+		for k, v := range converted {
+			values[k] = v
+		}
+	}
+
+	m.add(planResource{resType: resType, name: m.uniqueName(resType, r.addrName, r.addrIndex), values: values})
+
+	for _, e := range extra {
+		e.name = m.uniqueName(e.resType, e.name, -1)
+		m.add(e)
+	}
+}
+
+func (m *planModule) add(r planResource) {
+	m.resources = append(m.resources, r)
+}
+
+// uniqueName returns the name of the resource in the Terraform address. ARM
+// resource names can contain characters that aren't valid in Terraform
+// addresses and only have to be unique for the resource type, so the name is
+// indexed if it's already been used.
+func (m *planModule) uniqueName(resType string, name string, index int) string {
+	name = invalidNameChars.ReplaceAllString(name, "_")
+	if name == "" {
+		name = "resource"
+	}
+
+	candidate := name
+	if index >= 0 {
+		candidate = fmt.Sprintf("%s[%d]", name, index)
+	}
+
+	for i := 1; m.seen[resType+"."+candidate]; i++ {
+		candidate = fmt.Sprintf("%s[%d]", name, i)
+	}
+
+	m.seen[resType+"."+candidate] = true
+	return candidate
+}
+
+// fallbackType returns the azurerm resource type for ARM resource types that
+// aren't mapped, e.g. azurerm_virtual_network for
+// Microsoft.Network/virtualNetworks, so free resources are reported as free and
+// others as unsupported. Types that match a resource that has a price are
+// skipped, since the ARM properties would be read as the wrong attributes.
+func fallbackType(armType string) string {
+	resType := "azurerm_" + toSnakeCase(singularName(lastSegment(armType)))
+
+	item, ok := (*terraform.GetResourceRegistryMap())[resType]
+	if ok && !item.NoPrice {
+		logging.Logger.Debugf("Skipping ARM resource type %s as it is not supported", armType)
+		return ""
+	}
+
+	return resType
+}
+
+// convertStorageAccount sets the tier and replication type of the storage
+// account from its SKU, e.g. Standard_LRS.
+func convertStorageAccount(r *armResource) (string, map[string]interface{}, []planResource) {
+	values := map[string]interface{}{
+		"account_kind": r.kind,
+		"access_tier":  stringValue(r.props, "accessTier", "Hot"),
+	}
+
+	if tier, replication, ok := strings.Cut(toString(r.sku["name"]), "_"); ok {
+		values["account_tier"] = tier
+		values["account_replication_type"] = replication
+	}
+
+	if v, ok := r.props["isNfsV3Enabled"]; ok {
+		values["nfsv3_enabled"] = toBool(v)
+	}
+
+	return "azurerm_storage_account", values, nil
+}
+
+func convertStorageShare(r *armResource) (string, map[string]interface{}, []planResource) {
+	values := map[string]interface{}{}
+	if v, ok := r.props["shareQuota"]; ok {
+		values["quota"] = v
+	}
+	if v, ok := r.props["accessTier"]; ok {
+		values["access_tier"] = v
+	}
+
+	return "azurerm_storage_share", values, nil
+}
+
+func convertManagedDisk(r *armResource) (string, map[string]interface{}, []planResource) {
+	values := map[string]interface{}{
+		"storage_account_type": stringValue(r.sku, "name", "Standard_LRS"),
+	}
+	setIfExists(values, "disk_size_gb", r.props, "diskSizeGB")
+	setIfExists(values, "disk_iops_read_write", r.props, "diskIOPSReadWrite")
+	setIfExists(values, "disk_mbps_read_write", r.props, "diskMBpsReadWrite")
+
+	return "azurerm_managed_disk", values, nil
+}
+
+// convertVirtualMachine converts the VM to either a Linux or Windows VM. Data
+// disks that are created with the VM are separate managed disks in Terraform,
+// so they're added as managed disks named after the VM.
+func convertVirtualMachine(r *armResource) (string, map[string]interface{}, []planResource) {
+	resType := "azurerm_linux_virtual_machine"
+	if isWindows(r.props) {
+		resType = "azurerm_windows_virtual_machine"
+	}
+
+	hardware, _ := r.props["hardwareProfile"].(map[string]interface{})
+	storage, _ := r.props["storageProfile"].(map[string]interface{})
+
+	values := map[string]interface{}{
+		"size":    toString(hardware["vmSize"]),
+		"os_disk": []interface{}{osDisk(storage)},
+	}
+	setIfExists(values, "license_type", r.props, "licenseType")
+	setAdditionalCapabilities(values, r.props)
+
+	var disks []planResource
+	dataDisks, _ := storage["dataDisks"].([]interface{})
+	for _, d := range dataDisks {
+		disk, ok := d.(map[string]interface{})
+		if !ok || !strings.EqualFold(toString(disk["createOption"]), "Empty") {
+			continue
+		}
+
+		name := fmt.Sprintf("%s_data_disk_%s", r.addrName, toString(disk["lun"]))
+		if s := toString(disk["name"]); s != "" {
+			name = s
+		}
+
+		managed, _ := disk["managedDisk"].(map[string]interface{})
+		diskValues := map[string]interface{}{
+			"name":                 name,
+			"location":             r.location,
+			"storage_account_type": stringValue(managed, "storageAccountType", "Standard_LRS"),
+		}
+		setIfExists(diskValues, "disk_size_gb", disk, "diskSizeGB")
+
+		disks = append(disks, planResource{
+			resType: "azurerm_managed_disk",
+			name:    name,
+			values:  diskValues,
+		})
+	}
+
+	return resType, values, disks
+}
+
+func convertVirtualMachineScaleSet(r *armResource) (string, map[string]interface{}, []planResource) {
+	profile, _ := r.props["virtualMachineProfile"].(map[string]interface{})
+	storage, _ := profile["storageProfile"].(map[string]interface{})
+
+	resType := "azurerm_linux_virtual_machine_scale_set"
+	if isWindows(profile) {
+		resType = "azurerm_windows_virtual_machine_scale_set"
+	}
+
+	values := map[string]interface{}{
+		"sku":       toString(r.sku["name"]),
+		"instances": r.sku["capacity"],
+		"os_disk":   []interface{}{osDisk(storage)},
+	}
+	setIfExists(values, "license_type", profile, "licenseType")
+	setAdditionalCapabilities(values, r.props)
+
+	return resType, values, nil
+}
+
+func osDisk(storage map[string]interface{}) map[string]interface{} {
+	disk, _ := storage["osDisk"].(map[string]interface{})
+	managed, _ := disk["managedDisk"].(map[string]interface{})
+
+	values := map[string]interface{}{
+		"storage_account_type": stringValue(managed, "storageAccountType", "Standard_LRS"),
+	}
+	setIfExists(values, "disk_size_gb", disk, "diskSizeGB")
+
+	return values
+}
+
+func setAdditionalCapabilities(values map[string]interface{}, props map[string]interface{}) {
+	capabilities, ok := props["additionalCapabilities"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	values["additional_capabilities"] = []interface{}{
+		map[string]interface{}{"ultra_ssd_enabled": toBool(capabilities["ultraSSDEnabled"])},
+	}
+}
+
+// isWindows returns true if the VM profile is for Windows, either from its OS
+// profile or the OS type of its disk.
+func isWindows(profile map[string]interface{}) bool {
+	osProfile, _ := profile["osProfile"].(map[string]interface{})
+	if _, ok := osProfile["windowsConfiguration"]; ok {
+		return true
+	}
+
+	storage, _ := profile["storageProfile"].(map[string]interface{})
+	disk, _ := storage["osDisk"].(map[string]interface{})
+	if strings.EqualFold(toString(disk["osType"]), "Windows") {
+		return true
+	}
+
+	image, _ := storage["imageReference"].(map[string]interface{})
+	return strings.Contains(strings.ToLower(toString(image["publisher"])), "windows")
+}
+
+func stringValue(m map[string]interface{}, key string, def string) string {
+	if s := toString(m[key]); s != "" {
+		return s
+	}
+
+	return def
+}
+
+func setIfExists(values map[string]interface{}, attr string, m map[string]interface{}, key string) {
+	if v, ok := m[key]; ok && v != nil {
+		values[attr] = v
+	}
+}
+
+func lastSegment(s string) string {
+	parts := strings.Split(s, "/")
+	return parts[len(parts)-1]
+}
+
+func singularName(name string) string {
+	if strings.HasSuffix(name, "ies") {
+		return strings.TrimSuffix(name, "ies") + "y"
+	}
+
+	if strings.HasSuffix(name, "sses") || strings.HasSuffix(name, "xes") {
+		return strings.TrimSuffix(name, "es")
+	}
+
+	return strings.TrimSuffix(name, "s")
+}
+
+// toSnakeCase converts a camel case ARM name to snake case, e.g.
+// virtualNetworks to virtual_networks.
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteRune('_')
+			}
+		}
+
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
+
+func resourceJSON(address string, r planResource) map[string]interface{} {
+	return map[string]interface{}{
+		"address":       address,
+		"mode":          "managed",
+		"type":          r.resType,
+		"name":          r.name,
+		"provider_name": providerName,
+		"values":        r.values,
+	}
+}
+
+func moduleValues(m *planModule) map[string]interface{} {
+	resources := make([]interface{}, 0, len(m.resources))
+	for _, r := range m.resources {
+		resources = append(resources, resourceJSON(m.resourceAddress(r), r))
+	}
+
+	values := map[string]interface{}{
+		"resources": resources,
+	}
+
+	if m.address != "" {
+		values["address"] = m.address
+	}
+
+	if len(m.children) > 0 {
+		children := make([]interface{}, 0, len(m.children))
+		for _, c := range m.children {
+			children = append(children, moduleValues(c))
+		}
+		values["child_modules"] = children
+	}
+
+	return values
+}
+
+func resourceChanges(m *planModule) []interface{} {
+	var changes []interface{}
+
+	for _, r := range m.resources {
+		changes = append(changes, map[string]interface{}{
+			"address": m.resourceAddress(r),
+			"mode":    "managed",
+			"type":    r.resType,
+			"name":    r.name,
+		})
+	}
+
+	for _, c := range m.children {
+		changes = append(changes, resourceChanges(c)...)
+	}
+
+	return changes
+}
+
+func moduleConfig(m *planModule) map[string]interface{} {
+	var resources []interface{}
+	added := make(map[string]bool)
+
+	for _, r := range m.resources {
+		addr := fmt.Sprintf("%s.%s", r.resType, removeIndex(r.name))
+		if added[addr] {
+			continue
+		}
+		added[addr] = true
+
+		resources = append(resources, map[string]interface{}{
+			"address":             addr,
+			"mode":                "managed",
+			"type":                r.resType,
+			"name":                removeIndex(r.name),
+			"provider_config_key": "azurerm",
+		})
+	}
+
+	conf := map[string]interface{}{
+		"resources": resources,
+	}
+
+	if len(m.children) > 0 {
+		calls := make(map[string]interface{}, len(m.children))
+		for _, c := range m.children {
+			calls[c.name] = map[string]interface{}{
+				"module": moduleConfig(c),
+			}
+		}
+		conf["module_calls"] = calls
+	}
+
+	return conf
+}
+
+func removeIndex(name string) string {
+	if i := strings.Index(name, "["); i != -1 {
+		return name[:i]
+	}
+
+	return name
+}
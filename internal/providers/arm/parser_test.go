@@ -0,0 +1,112 @@
+package arm
+
+import (
+	"os"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+func TestToPlanJSON(t *testing.T) {
+	b, err := os.ReadFile("testdata/main.json")
+	require.NoError(t, err)
+
+	params, err := loadParameters(parametersPath("testdata/main.json"))
+	require.NoError(t, err)
+
+	p := NewParser(config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{}))
+	j, err := p.toPlanJSON(b, params)
+	require.NoError(t, err)
+
+	plan := gjson.ParseBytes(j)
+
+	assert.Equal(t, []interface{}{
+		"azurerm_storage_account.appstorage4oymiquy7qobj",
+		"azurerm_storage_queue.jobs",
+		"azurerm_storage_container.assets",
+		"azurerm_managed_disk.data",
+		"azurerm_windows_virtual_machine.app-vm",
+		"azurerm_managed_disk.app-vm_data_disk_0",
+		"azurerm_managed_disk.app-vm_data_disk_1",
+		"azurerm_linux_virtual_machine_scale_set.workers[0]",
+		"azurerm_linux_virtual_machine_scale_set.workers[1]",
+		"azurerm_virtual_network.vnet",
+		"module.logs.azurerm_storage_account.applogs",
+	}, plan.Get("resource_changes.#.address").Value())
+
+	resources := plan.Get("planned_values.root_module.resources")
+
+	storage := resources.Get(`#(type="azurerm_storage_account").values`)
+	assert.Equal(t, "Standard", storage.Get("account_tier").String())
+	assert.Equal(t, "LRS", storage.Get("account_replication_type").String())
+	assert.Equal(t, "StorageV2", storage.Get("account_kind").String())
+	assert.Equal(t, "Cool", storage.Get("access_tier").String())
+	assert.Equal(t, "westeurope", storage.Get("location").String())
+	assert.Equal(t, "prod", storage.Get("tags.env").String())
+
+	// The queue is nested in the storage account so it has the same location.
+	assert.Equal(t, "westeurope", resources.Get(`#(type="azurerm_storage_queue").values.location`).String())
+
+	disk := resources.Get(`#(address="azurerm_managed_disk.data").values`)
+	assert.Equal(t, "Premium_LRS", disk.Get("storage_account_type").String())
+	assert.Equal(t, int64(256), disk.Get("disk_size_gb").Int())
+
+	vm := resources.Get(`#(type="azurerm_windows_virtual_machine").values`)
+	assert.Equal(t, "Standard_D2s_v3", vm.Get("size").String())
+	assert.Equal(t, "Premium_LRS", vm.Get("os_disk.0.storage_account_type").String())
+
+	dataDisk := resources.Get(`#(address="azurerm_managed_disk.app-vm_data_disk_1").values`)
+	assert.Equal(t, "StandardSSD_LRS", dataDisk.Get("storage_account_type").String())
+	assert.Equal(t, int64(64), dataDisk.Get("disk_size_gb").Int())
+
+	vmss := resources.Get(`#(address="azurerm_linux_virtual_machine_scale_set.workers[1]").values`)
+	assert.Equal(t, "app-workers-1", vmss.Get("name").String())
+	assert.Equal(t, "Standard_B2s", vmss.Get("sku").String())
+	assert.Equal(t, int64(3), vmss.Get("instances").Int())
+
+	module := plan.Get(`planned_values.root_module.child_modules.#(address="module.logs")`)
+	logs := module.Get("resources.0.values")
+	assert.Equal(t, "applogs", logs.Get("name").String())
+	assert.Equal(t, "GRS", logs.Get("account_replication_type").String())
+	assert.Equal(t, "westeurope", logs.Get("location").String())
+
+	assert.True(t, plan.Get("configuration.root_module.module_calls.logs.module.resources.#(address=\"azurerm_storage_account.applogs\")").Exists())
+}
+
+func TestToPlanJSONSymbolicNames(t *testing.T) {
+	b := []byte(`{
+		"$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#",
+		"languageVersion": "2.0",
+		"resources": {
+			"disk": {
+				"type": "Microsoft.Compute/disks",
+				"name": "disk-1",
+				"location": "eastus",
+				"sku": {"name": "Standard_LRS"}
+			},
+			"existingAccount": {
+				"existing": true,
+				"type": "Microsoft.Storage/storageAccounts",
+				"name": "existing"
+			}
+		}
+	}`)
+
+	p := NewParser(config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{}))
+	j, err := p.toPlanJSON(b, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []interface{}{"azurerm_managed_disk.disk"}, gjson.GetBytes(j, "resource_changes.#.address").Value())
+}
+
+func TestToPlanJSONInvalid(t *testing.T) {
+	p := NewParser(config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{}))
+
+	_, err := p.toPlanJSON([]byte(`{"parameters": {}}`), nil)
+	assert.Error(t, err)
+}
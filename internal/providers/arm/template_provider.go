@@ -0,0 +1,142 @@
+package arm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+type TemplateProvider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+}
+
+func NewTemplateProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &TemplateProvider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *TemplateProvider) Type() string {
+	return "arm"
+}
+
+func (p *TemplateProvider) DisplayType() string {
+	return "Azure ARM template"
+}
+
+func (p *TemplateProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *TemplateProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
+	b, err := os.ReadFile(p.Path)
+	if err != nil {
+		return []*schema.Project{}, fmt.Errorf("Error reading ARM template file %w", err)
+	}
+
+	project, err := loadTemplate(p.ctx, b, p.Path, usage, p.includePastResources)
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+
+	project.Metadata.Type = p.Type()
+	p.AddMetadata(project.Metadata)
+
+	return []*schema.Project{project}, nil
+}
+
+// loadTemplate converts the ARM template to a Terraform plan JSON so its
+// resources are parsed the same way as the resources of Terraform projects.
+// The template parameters are set from the parameters file next to the file at
+// path, e.g. main.parameters.json for main.json or main.bicep, if there is one.
+func loadTemplate(ctx *config.ProjectContext, b []byte, path string, usage schema.UsageMap, includePastResources bool) (*schema.Project, error) {
+	spinner := ui.NewSpinner("Extracting only cost-related params from ARM template", ui.SpinnerOptions{
+		EnableLogging: ctx.RunContext.Config.IsLogging(),
+		NoColor:       ctx.RunContext.Config.NoColor,
+		Indent:        "  ",
+	})
+	defer spinner.Fail()
+
+	params, err := loadParameters(parametersPath(path))
+	if err != nil {
+		return nil, err
+	}
+
+	j, err := NewParser(ctx).toPlanJSON(b, params)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing ARM template file %w", err)
+	}
+
+	project, err := terraform.NewPlanJSONProvider(ctx, includePastResources).LoadResourcesFromSrc(usage, j, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	spinner.Success()
+
+	return project, nil
+}
+
+func parametersPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".parameters.json"
+}
+
+// loadParameters returns the values of the ARM parameters file at path, or no
+// values if it doesn't exist.
+func loadParameters(path string) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+
+	if !config.FileExists(path) {
+		return params, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading ARM parameters file %w", err)
+	}
+
+	var file struct {
+		Parameters map[string]struct {
+			Value interface{} `json:"value"`
+		} `json:"parameters"`
+	}
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("Error parsing ARM parameters file %s %w", path, err)
+	}
+
+	for k, v := range file.Parameters {
+		params[k] = v.Value
+	}
+
+	return params, nil
+}
+
+// IsTemplate returns true if the file at path is an ARM template, which has a
+// deployment template schema.
+func IsTemplate(path string) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	var template struct {
+		Schema    string      `json:"$schema"`
+		Resources interface{} `json:"resources"`
+	}
+	if err := json.Unmarshal(b, &template); err != nil {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(template.Schema), "deploymenttemplate.json") && template.Resources != nil
+}
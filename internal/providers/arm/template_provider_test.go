@@ -0,0 +1,49 @@
+package arm
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestTemplateProviderLoadResources(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: "testdata/main.json", Name: "app"}, log.Fields{})
+
+	projects, err := NewTemplateProvider(ctx, false).LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	project := projects[0]
+	assert.Equal(t, "app", project.Name)
+	assert.Equal(t, "arm", project.Metadata.Type)
+
+	locations := make(map[string]string)
+	for _, r := range project.PartialResources {
+		locations[r.ResourceData.Address] = r.ResourceData.Get("location").String()
+	}
+
+	assert.Equal(t, map[string]string{
+		"azurerm_storage_account.appstorage4oymiquy7qobj":    "westeurope",
+		"azurerm_storage_queue.jobs":                         "westeurope",
+		"azurerm_storage_container.assets":                   "westeurope",
+		"azurerm_managed_disk.data":                          "westeurope",
+		"azurerm_windows_virtual_machine.app-vm":             "westeurope",
+		"azurerm_managed_disk.app-vm_data_disk_0":            "westeurope",
+		"azurerm_managed_disk.app-vm_data_disk_1":            "westeurope",
+		"azurerm_linux_virtual_machine_scale_set.workers[0]": "westeurope",
+		"azurerm_linux_virtual_machine_scale_set.workers[1]": "westeurope",
+		"azurerm_virtual_network.vnet":                       "westeurope",
+		"module.logs.azurerm_storage_account.applogs":        "westeurope",
+	}, locations)
+}
+
+func TestIsTemplate(t *testing.T) {
+	assert.True(t, IsTemplate("testdata/main.json"))
+	assert.False(t, IsTemplate("testdata/main.parameters.json"))
+	assert.False(t, IsTemplate("testdata"))
+}
@@ -0,0 +1,75 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/lambda"
+	"github.com/awslabs/goformation/v4/cloudformation/serverless"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetLambdaFunctionRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::Lambda::Function",
+		RFunc: NewLambdaFunction,
+	}
+}
+
+func NewLambdaFunction(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*lambda.Function)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	resource := newLambdaFunction(d, u, cfr.FunctionName, cfr.MemorySize)
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
+
+// GetServerlessFunctionRegistryItem returns the registry item of SAM functions,
+// which are transformed to AWS::Lambda::Function when the stack is deployed.
+func GetServerlessFunctionRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::Serverless::Function",
+		RFunc: NewServerlessFunction,
+	}
+}
+
+func NewServerlessFunction(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*serverless.Function)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	resource := newLambdaFunction(d, u, cfr.FunctionName, cfr.MemorySize)
+
+	resourceTags := make(map[string]string, len(cfr.Tags))
+	for k, v := range cfr.Tags {
+		resourceTags[k] = v
+	}
+	resource.Tags = resourceTags
+
+	return resource
+}
+
+func newLambdaFunction(d *schema.ResourceData, u *schema.UsageData, name string, memorySize int) *schema.Resource {
+	memory := int64(128)
+	if memorySize > 0 {
+		memory = int64(memorySize)
+	}
+
+	a := &aws.LambdaFunction{
+		Address:      d.Address,
+		Region:       d.Get("region").String(),
+		Name:         name,
+		MemorySize:   memory,
+		Architecture: "x86_64",
+		StorageSize:  512,
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
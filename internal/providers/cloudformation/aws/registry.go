@@ -12,7 +12,7 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	// GetCloudfrontDistributionRegistryItem(),
 	// GetCloudwatchDashboardRegistryItem(),
 	// GetCloudwatchEventBusItem(),
-	// GetCloudwatchLogGroupItem(),
+	GetCloudwatchLogGroupItem(),
 	// GetCloudwatchMetricAlarmRegistryItem(),
 	// GetCodebuildProjectRegistryItem(),
 	// GetConfigRuleItem(),
@@ -30,7 +30,7 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	GetDynamoDBTableRegistryItem(),
 	// GetEBSSnapshotCopyRegistryItem(),
 	// GetEBSSnapshotRegistryItem(),
-	// GetEBSVolumeRegistryItem(),
+	GetEBSVolumeRegistryItem(),
 	// GetEC2ClientVPNEndpointRegistryItem(),
 	// GetEC2ClientVPNNetworkAssociationRegistryItem(),
 	// GetEC2TrafficMirroSessionRegistryItem(),
@@ -46,13 +46,13 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	// GetELBRegistryItem(),
 	// GetFSXWindowsFSRegistryItem(),
 	// GetInstanceRegistryItem(),
-	// GetLambdaFunctionRegistryItem(),
+	GetLambdaFunctionRegistryItem(),
 	// GetLBRegistryItem(),
 	// GetLightsailInstanceRegistryItem(),
 	// GetMSKClusterRegistryItem(),
 	// GetALBRegistryItem(),
 	// GetMQBrokerRegistryItem(),
-	// GetNATGatewayRegistryItem(),
+	GetNATGatewayRegistryItem(),
 	// GetRDSClusterRegistryItem(),
 	// GetRDSClusterInstanceRegistryItem(),
 	// GetRedshiftClusterRegistryItem(),
@@ -64,11 +64,12 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	// GetS3BucketAnalyticsConfigurationRegistryItem(),
 	// GetS3BucketInventoryRegistryItem(),
 	// GetSecretsManagerSecret(),
+	GetServerlessFunctionRegistryItem(),
 	// GetSSMActivationRegistryItem(),
 	// GetSSMParameterRegistryItem(),
-	// GetSNSTopicRegistryItem(),
+	GetSNSTopicRegistryItem(),
 	// GetSNSTopicSubscriptionRegistryItem(),
-	// GetSQSQueueRegistryItem(),
+	GetSQSQueueRegistryItem(),
 	// GetNewEKSNodeGroupItem(),
 	// GetNewEKSFargateProfileItem(),
 	// GetNewEKSClusterItem(),
@@ -0,0 +1,197 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const (
+	cdkStackArtifact    = "aws:cloudformation:stack"
+	cdkAssemblyArtifact = "cdk:cloud-assembly"
+)
+
+// cdkManifest is the manifest.json of a CDK cloud assembly, the output of
+// `cdk synth`.
+type cdkManifest struct {
+	Artifacts map[string]cdkArtifact `json:"artifacts"`
+}
+
+type cdkArtifact struct {
+	Type        string `json:"type"`
+	Environment string `json:"environment"`
+	DisplayName string `json:"displayName"`
+	Properties  struct {
+		TemplateFile  string `json:"templateFile"`
+		DirectoryName string `json:"directoryName"`
+	} `json:"properties"`
+}
+
+// cdkStack is a stack of a CDK cloud assembly.
+type cdkStack struct {
+	name         string
+	templatePath string
+	region       string
+}
+
+// CDKProvider loads the stacks of a CDK cloud assembly, each of which is a
+// CloudFormation template, as separate projects.
+type CDKProvider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+}
+
+func NewCDKProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &CDKProvider{
+		ctx:                  ctx,
+		Path:                 CDKAssemblyDir(ctx.ProjectConfig.Path),
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *CDKProvider) Type() string {
+	return "cdk"
+}
+
+func (p *CDKProvider) DisplayType() string {
+	return "AWS CDK"
+}
+
+func (p *CDKProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *CDKProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
+	stacks, err := loadCDKStacks(p.Path)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading CDK cloud assembly")
+	}
+
+	if len(stacks) == 0 {
+		return []*schema.Project{}, fmt.Errorf("No stacks found in CDK cloud assembly %s, run cdk synth to generate them", p.Path)
+	}
+
+	projects := make([]*schema.Project, 0, len(stacks))
+	for _, stack := range stacks {
+		metadata := config.DetectProjectMetadata(stack.templatePath)
+		metadata.Type = p.Type()
+		p.AddMetadata(metadata)
+
+		name := stack.name
+		if p.ctx.ProjectConfig.Name != "" {
+			name = fmt.Sprintf("%s/%s", p.ctx.ProjectConfig.Name, stack.name)
+		}
+
+		project := schema.NewProject(name, metadata)
+		err := loadTemplate(p.ctx, project, stack.templatePath, stack.region, usage, p.includePastResources)
+		if err != nil {
+			return projects, errors.Wrapf(err, "Error loading CDK stack %s", stack.name)
+		}
+
+		projects = append(projects, project)
+	}
+
+	return projects, nil
+}
+
+// IsCDKAssembly returns true if path is a CDK cloud assembly or a CDK app that
+// has been synthesized to one.
+func IsCDKAssembly(path string) bool {
+	b, err := os.ReadFile(filepath.Join(CDKAssemblyDir(path), "manifest.json"))
+	if err != nil {
+		return false
+	}
+
+	var manifest cdkManifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return false
+	}
+
+	for _, artifact := range manifest.Artifacts {
+		if artifact.Type == cdkStackArtifact || artifact.Type == cdkAssemblyArtifact {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CDKAssemblyDir returns the cloud assembly directory of path, which is either
+// the cloud assembly itself or a CDK app with the default cdk.out output.
+func CDKAssemblyDir(path string) string {
+	if config.FileExists(filepath.Join(path, "cdk.json")) {
+		return filepath.Join(path, "cdk.out")
+	}
+
+	return path
+}
+
+// loadCDKStacks returns the stacks of the cloud assembly in dir, including the
+// stacks of nested assemblies, such as those of CDK stages, sorted by name.
+func loadCDKStacks(dir string) ([]cdkStack, error) {
+	b, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest cdkManifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return nil, errors.Wrap(err, "Error parsing manifest.json")
+	}
+
+	var stacks []cdkStack
+	for id, artifact := range manifest.Artifacts {
+		switch artifact.Type {
+		case cdkStackArtifact:
+			name := artifact.DisplayName
+			if name == "" {
+				name = id
+			}
+
+			templateFile := artifact.Properties.TemplateFile
+			if templateFile == "" {
+				templateFile = id + ".template.json"
+			}
+
+			stacks = append(stacks, cdkStack{
+				name:         name,
+				templatePath: filepath.Join(dir, templateFile),
+				region:       cdkEnvironmentRegion(artifact.Environment),
+			})
+		case cdkAssemblyArtifact:
+			nested, err := loadCDKStacks(filepath.Join(dir, artifact.Properties.DirectoryName))
+			if err != nil {
+				return nil, err
+			}
+
+			stacks = append(stacks, nested...)
+		}
+	}
+
+	sort.Slice(stacks, func(i, j int) bool {
+		return stacks[i].name < stacks[j].name
+	})
+
+	return stacks, nil
+}
+
+// cdkEnvironmentRegion returns the region of a stack environment, in the format
+// aws://<account>/<region>. Environment-agnostic stacks have the region
+// unknown-region, for which an empty string is returned.
+func cdkEnvironmentRegion(env string) string {
+	parts := strings.Split(strings.TrimPrefix(env, "aws://"), "/")
+	if len(parts) != 2 || parts[1] == "unknown-region" {
+		return ""
+	}
+
+	return parts[1]
+}
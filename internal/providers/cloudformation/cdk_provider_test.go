@@ -0,0 +1,69 @@
+package cloudformation
+
+import (
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestIsCDKAssembly(t *testing.T) {
+	assert.True(t, IsCDKAssembly("testdata/cdk_app"))
+	assert.True(t, IsCDKAssembly("testdata/cdk_app/cdk.out"))
+	assert.True(t, IsCDKAssembly("testdata/cdk_app/cdk.out/assembly-Prod"))
+	assert.False(t, IsCDKAssembly("testdata"))
+}
+
+func TestLoadCDKStacks(t *testing.T) {
+	dir := CDKAssemblyDir("testdata/cdk_app")
+	assert.Equal(t, filepath.Join("testdata", "cdk_app", "cdk.out"), dir)
+
+	stacks, err := loadCDKStacks(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []cdkStack{
+		{
+			name:         "DevStack",
+			templatePath: filepath.Join(dir, "DevStack.template.json"),
+			region:       "",
+		},
+		{
+			name:         "Prod/AppStack",
+			templatePath: filepath.Join(dir, "assembly-Prod", "ProdAppStack12345678.template.json"),
+			region:       "eu-west-1",
+		},
+	}, stacks)
+}
+
+func TestCDKEnvironmentRegion(t *testing.T) {
+	assert.Equal(t, "eu-west-1", cdkEnvironmentRegion("aws://123456789012/eu-west-1"))
+	assert.Equal(t, "", cdkEnvironmentRegion("aws://unknown-account/unknown-region"))
+	assert.Equal(t, "", cdkEnvironmentRegion(""))
+}
+
+func TestCDKProviderLoadResources(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: "testdata/cdk_app"}, log.Fields{})
+
+	projects, err := NewCDKProvider(ctx, false).LoadResources(schema.NewUsageMap(map[string]*schema.UsageData{}))
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+
+	assert.Equal(t, "DevStack", projects[0].Name)
+	assert.Equal(t, "cdk", projects[0].Metadata.Type)
+	require.Len(t, projects[0].Resources, 1)
+	assert.Equal(t, "Queue4A7E3555", projects[0].Resources[0].Name)
+	assert.Equal(t, "AWS::SQS::Queue", projects[0].Resources[0].ResourceType)
+
+	assert.Equal(t, "Prod/AppStack", projects[1].Name)
+	require.Len(t, projects[1].Resources, 1)
+	volume := projects[1].Resources[0]
+	assert.Equal(t, "Volume1A2B3C4D", volume.Name)
+	require.NotEmpty(t, volume.CostComponents)
+	assert.Equal(t, "Storage (general purpose SSD, gp3)", volume.CostComponents[0].Name)
+	assert.Equal(t, "eu-west-1", *volume.CostComponents[0].ProductFilter.Region)
+}
@@ -0,0 +1,126 @@
+package cloudformation
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/intrinsics"
+)
+
+// defaultRegion is the region used for templates that don't have one, either
+// from the CDK stack environment or the region override.
+const defaultRegion = "us-east-1"
+
+var subVariable = regexp.MustCompile(`\$\{(!?[\w.:]+)\}`)
+
+// intrinsicOptions returns the options used to resolve the intrinsic functions
+// of a template deployed to region. goformation resolves pseudo parameters such
+// as AWS::Region to fixed values, so Ref and Fn::Sub are overridden to use the
+// region of the template. Fn::FindInMap is resolved by goformation once its
+// arguments are resolved, so mappings by region use the right region too.
+func intrinsicOptions(region string) *intrinsics.ProcessorOptions {
+	ref := refHandler(region)
+
+	return &intrinsics.ProcessorOptions{
+		IntrinsicHandlerOverrides: map[string]intrinsics.IntrinsicHandler{
+			"Ref":     ref,
+			"Fn::Sub": subHandler(ref),
+		},
+	}
+}
+
+// refHandler resolves Ref to the pseudo parameters of region, the default value
+// of parameters or, for resources, their logical ID. The physical ID of
+// resources isn't known until the stack is deployed.
+func refHandler(region string) intrinsics.IntrinsicHandler {
+	return func(name string, input interface{}, template interface{}) interface{} {
+		s, ok := input.(string)
+		if !ok {
+			return nil
+		}
+
+		switch s {
+		case "AWS::Region":
+			return region
+		case "AWS::Partition":
+			if strings.HasPrefix(region, "cn-") {
+				return "aws-cn"
+			}
+			if strings.HasPrefix(region, "us-gov-") {
+				return "aws-us-gov"
+			}
+			return "aws"
+		case "AWS::URLSuffix":
+			if strings.HasPrefix(region, "cn-") {
+				return "amazonaws.com.cn"
+			}
+			return "amazonaws.com"
+		}
+
+		if v := intrinsics.Ref(name, input, template); v != nil {
+			return v
+		}
+
+		if t, ok := template.(map[string]interface{}); ok {
+			if resources, ok := t["Resources"].(map[string]interface{}); ok {
+				if _, ok := resources[s]; ok {
+					return s
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// subHandler resolves Fn::Sub, in either the string or the list form with a map
+// of variables. Variables that aren't in the map are resolved with ref, or
+// removed if they can't be resolved.
+func subHandler(ref intrinsics.IntrinsicHandler) intrinsics.IntrinsicHandler {
+	return func(name string, input interface{}, template interface{}) interface{} {
+		var src string
+		vars := map[string]interface{}{}
+
+		switch val := input.(type) {
+		case string:
+			src = val
+		case []interface{}:
+			if len(val) != 2 {
+				return nil
+			}
+
+			s, ok := val[0].(string)
+			if !ok {
+				return nil
+			}
+			src = s
+
+			if m, ok := val[1].(map[string]interface{}); ok {
+				vars = m
+			}
+		default:
+			return nil
+		}
+
+		return subVariable.ReplaceAllStringFunc(src, func(match string) string {
+			key := match[2 : len(match)-1]
+
+			// ${!Literal} is written as ${Literal}
+			if strings.HasPrefix(key, "!") {
+				return "${" + key[1:] + "}"
+			}
+
+			var v interface{}
+			if varValue, ok := vars[key]; ok {
+				v = varValue
+			} else if strings.Contains(key, ".") {
+				v = intrinsics.FnGetAtt("Fn::GetAtt", strings.Split(key, "."), template)
+			} else {
+				v = ref("Ref", key, template)
+			}
+
+			s, _ := v.(string)
+			return s
+		})
+	}
+}
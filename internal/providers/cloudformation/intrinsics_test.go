@@ -0,0 +1,69 @@
+package cloudformation
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4"
+	"github.com/awslabs/goformation/v4/cloudformation/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const intrinsicsTemplate = `{
+  "Parameters": {
+    "Env": {
+      "Type": "String",
+      "Default": "dev"
+    }
+  },
+  "Mappings": {
+    "RegionMap": {
+      "us-east-1": {"Suffix": "use1"},
+      "eu-west-1": {"Suffix": "euw1"}
+    }
+  },
+  "Resources": {
+    "Dlq": {
+      "Type": "AWS::SQS::Queue",
+      "Properties": {
+        "QueueName": {"Fn::Sub": "${Env}-${AWS::Region}-${Missing}-dlq-${!Literal}"}
+      }
+    },
+    "Queue": {
+      "Type": "AWS::SQS::Queue",
+      "Properties": {
+        "QueueName": {"Fn::Sub": ["${Prefix}-${Dlq}", {"Prefix": {"Ref": "AWS::Partition"}}]},
+        "KmsMasterKeyId": {"Fn::FindInMap": ["RegionMap", {"Ref": "AWS::Region"}, "Suffix"]}
+      }
+    }
+  }
+}`
+
+func TestIntrinsicOptions(t *testing.T) {
+	tests := []struct {
+		region   string
+		dlqName  string
+		name     string
+		kmsKeyID string
+	}{
+		{"us-east-1", "dev-us-east-1--dlq-${Literal}", "aws-Dlq", "use1"},
+		{"eu-west-1", "dev-eu-west-1--dlq-${Literal}", "aws-Dlq", "euw1"},
+		{"cn-north-1", "dev-cn-north-1--dlq-${Literal}", "aws-cn-Dlq", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			template, err := goformation.ParseJSONWithOptions([]byte(intrinsicsTemplate), intrinsicOptions(tt.region))
+			require.NoError(t, err)
+
+			dlq, ok := template.Resources["Dlq"].(*sqs.Queue)
+			require.True(t, ok)
+			assert.Equal(t, tt.dlqName, dlq.QueueName)
+
+			queue, ok := template.Resources["Queue"].(*sqs.Queue)
+			require.True(t, ok)
+			assert.Equal(t, tt.name, queue.QueueName)
+			assert.Equal(t, tt.kmsKeyID, queue.KmsMasterKeyId)
+		})
+	}
+}
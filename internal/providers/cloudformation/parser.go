@@ -60,7 +60,7 @@ func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *sc
 	}
 }
 
-func (p *Parser) parseTemplate(t *cloudformation.Template, usage schema.UsageMap) ([]*schema.Resource, []*schema.Resource, error) {
+func (p *Parser) parseTemplate(t *cloudformation.Template, region string, usage schema.UsageMap) ([]*schema.Resource, []*schema.Resource, error) {
 	baseResources := p.loadUsageFileResources(usage)
 
 	var resources []*schema.Resource
@@ -71,6 +71,7 @@ func (p *Parser) parseTemplate(t *cloudformation.Template, usage schema.UsageMap
 		usageData := usage.Get(name)
 
 		resourceData := schema.NewCFResourceData(d.AWSCloudFormationType(), "aws", name, tags, d)
+		resourceData.RawValues = schema.AddRawValue(resourceData.RawValues, "region", region)
 
 		if r := p.createResource(resourceData, usageData); r != nil {
 			resources = append(resources, r)
@@ -98,5 +99,5 @@ func (p *Parser) loadUsageFileResources(u schema.UsageMap) []*schema.Resource {
 }
 
 func isAwsChina(d *schema.ResourceData) bool {
-	return strings.HasPrefix(d.Type, "aws_") && strings.HasPrefix(d.Get("region").String(), "cn-")
+	return strings.HasPrefix(d.Get("region").String(), "cn-")
 }
@@ -35,11 +35,6 @@ func (p *TemplateProvider) AddMetadata(metadata *schema.ProjectMetadata) {
 }
 
 func (p *TemplateProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
-	template, err := goformation.Open(p.Path)
-	if err != nil {
-		return []*schema.Project{}, errors.Wrap(err, "Error reading CloudFormation template file")
-	}
-
 	metadata := config.DetectProjectMetadata(p.ctx.ProjectConfig.Path)
 	metadata.Type = p.Type()
 	p.AddMetadata(metadata)
@@ -49,18 +44,40 @@ func (p *TemplateProvider) LoadResources(usage schema.UsageMap) ([]*schema.Proje
 	}
 
 	project := schema.NewProject(name, metadata)
-	parser := NewParser(p.ctx)
-	pastResources, resources, err := parser.parseTemplate(template, usage)
+	err := loadTemplate(p.ctx, project, p.Path, "", usage, p.includePastResources)
+
+	return []*schema.Project{project}, err
+}
+
+// loadTemplate parses the resources of the CloudFormation template at path into
+// project. The intrinsic functions of the template are resolved for the region
+// override, the given region or the default region, in that order.
+func loadTemplate(ctx *config.ProjectContext, project *schema.Project, path string, region string, usage schema.UsageMap, includePastResources bool) error {
+	if ctx.RunContext.Config.AWSOverrideRegion != "" {
+		region = ctx.RunContext.Config.AWSOverrideRegion
+	}
+
+	if region == "" {
+		region = defaultRegion
+	}
+
+	template, err := goformation.OpenWithOptions(path, intrinsicOptions(region))
+	if err != nil {
+		return errors.Wrap(err, "Error reading CloudFormation template file")
+	}
+
+	parser := NewParser(ctx)
+	pastResources, resources, err := parser.parseTemplate(template, region, usage)
 	if err != nil {
-		return []*schema.Project{project}, errors.Wrap(err, "Error parsing CloudFormation template file")
+		return errors.Wrap(err, "Error parsing CloudFormation template file")
 	}
 
 	project.PastResources = pastResources
 	project.Resources = resources
 
-	if !p.includePastResources {
+	if !includePastResources {
 		project.PastResources = nil
 	}
 
-	return []*schema.Project{project}, nil
+	return nil
 }
@@ -13,7 +13,10 @@ import (
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/hcl"
 	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/providers/ansible"
+	"github.com/infracost/infracost/internal/providers/arm"
 	"github.com/infracost/infracost/internal/providers/cloudformation"
+	"github.com/infracost/infracost/internal/providers/pulumi"
 	"github.com/infracost/infracost/internal/providers/terraform"
 	"github.com/infracost/infracost/internal/schema"
 )
@@ -45,12 +48,20 @@ func (e *ValidationError) Error() string {
 func Detect(ctx *config.ProjectContext, includePastResources bool) (schema.Provider, error) {
 	path := ctx.ProjectConfig.Path
 
+	if terraform.IsCloudPlanProject(ctx.ProjectConfig) {
+		return terraform.NewCloudPlanProvider(ctx, includePastResources), nil
+	}
+
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return nil, fmt.Errorf("No such file or directory %s", path)
 	}
 
 	forceCLI := ctx.ProjectConfig.TerraformForceCLI
-	projectType := DetectProjectType(path, forceCLI)
+	projectType := DetectProjectType(path, forceCLI, ctx.ProjectConfig.Ansible)
+
+	if (projectType == "terragrunt_dir" || projectType == "terragrunt_cli") && ctx.RunContext.Config.DisableTerragrunt {
+		return nil, fmt.Errorf("Terragrunt projects are disabled by INFRACOST_DISABLE_TERRAGRUNT, could not estimate %s", path)
+	}
 
 	switch projectType {
 	case "terraform_dir":
@@ -88,6 +99,16 @@ func Detect(ctx *config.ProjectContext, includePastResources bool) (schema.Provi
 		return terraform.NewStateJSONProvider(ctx, includePastResources), nil
 	case "cloudformation":
 		return cloudformation.NewTemplateProvider(ctx, includePastResources), nil
+	case "cdk":
+		return cloudformation.NewCDKProvider(ctx, includePastResources), nil
+	case "pulumi_preview_json":
+		return pulumi.NewPreviewJSONProvider(ctx, includePastResources), nil
+	case "arm":
+		return arm.NewTemplateProvider(ctx, includePastResources), nil
+	case "bicep":
+		return arm.NewBicepProvider(ctx, includePastResources), nil
+	case "ansible":
+		return ansible.NewPlaybookProvider(ctx, includePastResources), nil
 	}
 
 	return nil, fmt.Errorf("could not detect path type for '%s'", path)
@@ -115,15 +136,40 @@ func validateProjectForHCL(ctx *config.ProjectContext) error {
 	return nil
 }
 
-func DetectProjectType(path string, forceCLI bool) string {
+func DetectProjectType(path string, forceCLI bool, includeAnsible bool) string {
+	// Ansible playbooks are only estimated when enabled, since the estimates
+	// are best-effort and YAML playbooks can't be told apart from other YAML
+	// files without reading their tasks.
+	if includeAnsible && ansible.IsPlaybook(path) {
+		return "ansible"
+	}
+
+	// ARM templates are checked first since goformation can parse templates
+	// with symbolic resource names as CloudFormation templates.
+	if arm.IsTemplate(path) {
+		return "arm"
+	}
+
+	if arm.IsBicepFile(path) {
+		return "bicep"
+	}
+
 	if isCloudFormationTemplate(path) {
 		return "cloudformation"
 	}
 
+	if cloudformation.IsCDKAssembly(path) {
+		return "cdk"
+	}
+
 	if isTerraformPlanJSON(path) {
 		return "terraform_plan_json"
 	}
 
+	if isPulumiPreviewJSON(path) {
+		return "pulumi_preview_json"
+	}
+
 	if isTerraformStateJSON(path) {
 		return "terraform_state_json"
 	}
@@ -170,6 +216,25 @@ func isTerraformPlanJSON(path string) bool {
 	return jsonFormat.FormatVersion != "" && jsonFormat.PlannedValues != nil
 }
 
+func isPulumiPreviewJSON(path string) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	var jsonFormat struct {
+		Steps         []interface{} `json:"steps"`
+		ChangeSummary interface{}   `json:"changeSummary"`
+	}
+
+	err = json.Unmarshal(b, &jsonFormat)
+	if err != nil {
+		return false
+	}
+
+	return jsonFormat.Steps != nil && jsonFormat.ChangeSummary != nil
+}
+
 func isTerraformStateJSON(path string) bool {
 	b, err := os.ReadFile(path)
 	if err != nil {
@@ -263,3 +328,34 @@ func isCloudFormationTemplate(path string) bool {
 
 	return false
 }
+
+// DetectConfigProjects adds the projects that are detected by the autodetect
+// section of the config file to the projects of cfg.
+func DetectConfigProjects(cfg *config.Config) error {
+	a := cfg.Autodetect
+	if a == nil {
+		return nil
+	}
+
+	base := a.BasePath()
+	if _, err := os.Stat(base); err != nil {
+		return fmt.Errorf("autodetect path %s does not exist", base)
+	}
+
+	locator := hcl.NewProjectLocator(logging.Logger.WithField("autodetect_path", base), &hcl.ProjectLocatorConfig{})
+
+	var dirs []string
+	for _, root := range locator.FindRootModules(base) {
+		dirs = append(dirs, root.Path)
+	}
+
+	detected := a.Projects(dirs, cfg.Projects)
+	logging.Logger.Debugf("autodetect found %d projects in %s", len(detected), base)
+
+	cfg.Projects = append(cfg.Projects, detected...)
+	if len(cfg.Projects) == 0 {
+		return fmt.Errorf("no projects were found in %s with the autodetect section of the config file", base)
+	}
+
+	return nil
+}
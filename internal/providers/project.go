@@ -0,0 +1,163 @@
+package providers
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+// LoadProjectUsage returns the usage of the project of ctx, from its usage
+// file and the defaults that are set in its config, and the past usage from
+// its usage_file_before, which is nil if it doesn't have one. Invalid keys of
+// the usage files are warned about to w.
+func LoadProjectUsage(ctx *config.ProjectContext, w io.Writer) (schema.UsageMap, *schema.UsageMap, error) {
+	usageFile := usage.NewBlankUsageFile()
+	if ctx.ProjectConfig.UsageFile != "" {
+		var err error
+		usageFile, err = LoadUsageFile(ctx.ProjectConfig.UsageFile, w)
+		if err != nil {
+			return schema.UsageMap{}, nil, err
+		}
+
+		ctx.SetContextValue("hasUsageFile", true)
+	}
+
+	var pastUsageData *schema.UsageMap
+	if ctx.ProjectConfig.UsageFileBefore != "" {
+		pastUsageFile, err := LoadUsageFile(ctx.ProjectConfig.UsageFileBefore, w)
+		if err != nil {
+			return schema.UsageMap{}, nil, err
+		}
+
+		m := withProjectUsageDefaults(ctx.ProjectConfig, pastUsageFile.ToUsageDataMap())
+		pastUsageData = &m
+		ctx.SetContextValue("hasUsageFileBefore", true)
+	}
+
+	return withProjectUsageDefaults(ctx.ProjectConfig, usageFile.ToUsageDataMap()), pastUsageData, nil
+}
+
+// LoadUsageFile loads the usage file at path, warns about any invalid keys to
+// w and merges the wildcard usages into the usages of the resources.
+func LoadUsageFile(path string, w io.Writer) (*usage.UsageFile, error) {
+	usageFile, err := usage.LoadUsageFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	invalidKeys, err := usageFile.InvalidKeys()
+	if err != nil {
+		logging.Logger.Errorf("Error checking usage file keys: %v", err)
+	} else if len(invalidKeys) > 0 {
+		ui.PrintWarningf(w,
+			"The following usage file parameters are invalid and will be ignored: %s\n",
+			strings.Join(invalidKeys, ", "),
+		)
+	}
+
+	usageFile.MergeWildcardUsages()
+
+	return usageFile, nil
+}
+
+// withProjectUsageDefaults returns usage with the defaults that are set in the
+// config file of the project added to it.
+func withProjectUsageDefaults(projectConfig *config.Project, usageData schema.UsageMap) schema.UsageMap {
+	if projectConfig.AzurePurchaseOption != "" {
+		usageData = usageData.WithResourceTypeDefault(azure.PurchaseOptionResourceTypes, "purchase_option", projectConfig.AzurePurchaseOption)
+	}
+
+	return usageData
+}
+
+// SetProjectConfig sets the settings of the projects loaded from projectCfg
+// that aren't set by their provider: the past usage, the projects that they
+// consume and their growth.
+func SetProjectConfig(projectCfg *config.Project, projects []*schema.Project, pastUsage *schema.UsageMap) {
+	for _, project := range projects {
+		if pastUsage != nil {
+			project.PastUsage = pastUsage
+		}
+
+		if len(projectCfg.ConsumesProjects) > 0 {
+			project.Metadata.ConsumesProjects = projectCfg.ConsumesProjects
+		}
+
+		if growth := projectCfg.Growth; growth != nil {
+			project.Growth = &schema.Growth{
+				MonthlyRate:       growth.MonthlyRate,
+				UsageMonthlyRates: growth.UsageMonthlyRates,
+			}
+		}
+	}
+}
+
+// ProjectOutputs are the outputs of the projects of a config that have been
+// run, which are used as the input vars of the projects that depend on them.
+// It's safe to use from multiple goroutines.
+type ProjectOutputs struct {
+	mu      sync.Mutex
+	outputs map[*config.Project]map[string]json.RawMessage
+}
+
+// Set saves the outputs of the projects of projectCfg so they can be used by
+// the projects that depend on it.
+func (o *ProjectOutputs) Set(projectCfg *config.Project, projects []*schema.Project) {
+	outputs := make(map[string]json.RawMessage)
+	for _, p := range projects {
+		for name, v := range p.Outputs {
+			outputs[name] = v
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.outputs == nil {
+		o.outputs = make(map[*config.Project]map[string]json.RawMessage)
+	}
+	o.outputs[projectCfg] = outputs
+}
+
+// Vars returns the input vars of projectCfg of cfg that are set to the
+// outputs of the projects it depends on. The projects must have already been
+// run.
+func (o *ProjectOutputs) Vars(cfg *config.Config, projectCfg *config.Project) (map[string]json.RawMessage, error) {
+	if len(projectCfg.TerraformOutputVars) == 0 {
+		return nil, nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	vars := make(map[string]json.RawMessage, len(projectCfg.TerraformOutputVars))
+	for name, s := range projectCfg.TerraformOutputVars {
+		ref, err := config.ParseOutputRef(s)
+		if err != nil {
+			return nil, err
+		}
+
+		dep, err := cfg.FindProject(ref.Project)
+		if err != nil {
+			return nil, err
+		}
+
+		v, ok := o.outputs[dep][ref.Output]
+		if !ok {
+			logging.Logger.Warnf("Output %s of project %s is unknown, so var %s isn't set from it", ref.Output, ref.Project, name)
+			continue
+		}
+
+		vars[name] = v
+	}
+
+	return vars, nil
+}
@@ -0,0 +1,594 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/providers/terraform"
+)
+
+// unknownValue is the value that Pulumi previews use for outputs that are only
+// known once the resource is created.
+const unknownValue = "04da6b54-80e4-46f7-96ec-b56ff0331ba9"
+
+// providerPrefixes are the prefixes of the Terraform resource types that the
+// Pulumi packages map to. The AWS, Azure Classic and Google Cloud packages are
+// bridged from the Terraform providers so their resources have the same
+// attributes as the Terraform resources.
+var providerPrefixes = map[string]string{
+	"aws":          "aws",
+	"azure":        "azurerm",
+	"azure-native": "azurerm",
+	"gcp":          "google",
+}
+
+// providerNames are the names of the Terraform providers of the Pulumi
+// packages, as they appear in a Terraform plan.
+var providerNames = map[string]string{
+	"aws":     "registry.terraform.io/hashicorp/aws",
+	"azurerm": "registry.terraform.io/hashicorp/azurerm",
+	"google":  "registry.terraform.io/hashicorp/google",
+}
+
+// typeOverrides are the Pulumi resource types that don't follow the naming of
+// the Terraform resource types they're bridged from or, for Azure Native
+// resources, that have a Terraform resource type with a different name.
+var typeOverrides = map[string]string{
+	"aws:rds/instance:Instance":         "aws_db_instance",
+	"aws:lb/loadBalancer:LoadBalancer":  "aws_lb",
+	"aws:alb/loadBalancer:LoadBalancer": "aws_alb",
+	"aws:elb/loadBalancer:LoadBalancer": "aws_elb",
+	"azure-native:storage:Queue":        "azurerm_storage_queue",
+	"azure-native:compute:Disk":         "azurerm_managed_disk",
+}
+
+// valueConverters convert the values of Azure Native resources, which aren't
+// bridged from Terraform so their attributes can differ, to the attributes of
+// the Terraform resource.
+var valueConverters = map[string]func(values map[string]interface{}){
+	"azure-native:storage:StorageAccount": convertStorageAccountValues,
+}
+
+// mapAttributes are the attributes that are maps, rather than nested blocks, so
+// their keys are kept as they are.
+var mapAttributes = map[string]bool{
+	"tags":            true,
+	"tagsAll":         true,
+	"labels":          true,
+	"effectiveLabels": true,
+	"resourceLabels":  true,
+	"userLabels":      true,
+	"metadata":        true,
+	"variables":       true,
+}
+
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+type Parser struct {
+	ctx *config.ProjectContext
+}
+
+func NewParser(ctx *config.ProjectContext) *Parser {
+	return &Parser{ctx}
+}
+
+// planResource is a resource of a Terraform plan JSON, along with the key of
+// the provider config it uses.
+type planResource struct {
+	address     string
+	resType     string
+	name        string
+	providerKey string
+	values      map[string]interface{}
+}
+
+func (r planResource) toJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"address":       r.address,
+		"mode":          "managed",
+		"type":          r.resType,
+		"name":          r.name,
+		"provider_name": providerNames[providerPrefix(r.resType)],
+		"values":        r.values,
+	}
+}
+
+// toPlanJSON converts the output of `pulumi preview --json` to a Terraform plan
+// JSON. The planned values are the new state of each resource and the prior
+// state is the old state of the resources that already exist.
+func (p *Parser) toPlanJSON(b []byte) ([]byte, error) {
+	if !gjson.ValidBytes(b) {
+		return nil, errors.New("invalid JSON")
+	}
+
+	parsed := gjson.ParseBytes(b)
+	if !parsed.Get("steps").Exists() {
+		return nil, errors.New("no steps found, the file must be the output of pulumi preview --json")
+	}
+
+	providerConf := p.providerConf(parsed)
+
+	var planned, prior []planResource
+	addresses := make(map[string]string)
+	seen := make(map[string]bool)
+
+	for _, step := range parsed.Get("steps").Array() {
+		op := step.Get("op").String()
+		if op == "read" || op == "refresh" {
+			continue
+		}
+
+		urn := step.Get("urn").String()
+		newState := step.Get("newState")
+		oldState := step.Get("oldState")
+
+		state := newState
+		if !state.Exists() {
+			state = oldState
+		}
+
+		// Skip component resources, they're only groups of other resources.
+		if state.Get("custom").Exists() && !state.Get("custom").Bool() {
+			continue
+		}
+
+		pulumiType := state.Get("type").String()
+		if pulumiType == "" {
+			pulumiType = urnType(urn)
+		}
+
+		resType := p.resourceType(pulumiType)
+		if resType == "" {
+			continue
+		}
+
+		addr, ok := addresses[urn]
+		if !ok {
+			addr = uniqueAddress(resType, urnName(urn), seen)
+			addresses[urn] = addr
+		}
+
+		providerKey := providerConfKey(resType, step.Get("provider").String(), state.Get("provider").String())
+
+		if op != "delete" && op != "delete-replaced" && op != "discard" && newState.Exists() {
+			planned = appendResource(planned, planResource{
+				address:     addr,
+				resType:     resType,
+				name:        strings.TrimPrefix(addr, resType+"."),
+				providerKey: providerKey,
+				values:      resourceValues(pulumiType, newState),
+			})
+		}
+
+		if oldState.Exists() {
+			prior = appendResource(prior, planResource{
+				address:     addr,
+				resType:     resType,
+				name:        strings.TrimPrefix(addr, resType+"."),
+				providerKey: providerKey,
+				values:      resourceValues(pulumiType, oldState),
+			})
+		}
+	}
+
+	plan := map[string]interface{}{
+		"format_version":   "1.0",
+		"planned_values":   rootModule(planned),
+		"resource_changes": resourceChanges(planned, prior),
+		"configuration": map[string]interface{}{
+			"provider_config": providerConf,
+			"root_module": map[string]interface{}{
+				"resources": resourceConfigs(planned, prior),
+			},
+		},
+	}
+
+	if len(prior) > 0 {
+		plan["prior_state"] = map[string]interface{}{
+			"values": rootModule(prior),
+		}
+	}
+
+	return json.Marshal(plan)
+}
+
+// resourceType returns the Terraform resource type of the Pulumi resource type,
+// e.g. aws_s3_bucket for aws:s3/bucket:Bucket. Pulumi resource types are
+// named <package>:<module>/<name>:<Name>, so the Terraform resource type is
+// either <prefix>_<module>_<name> or <prefix>_<name>, whichever is supported.
+func (p *Parser) resourceType(pulumiType string) string {
+	if t, ok := typeOverrides[pulumiType]; ok {
+		return t
+	}
+
+	parts := strings.Split(pulumiType, ":")
+	if len(parts) != 3 {
+		return ""
+	}
+
+	prefix, ok := providerPrefixes[parts[0]]
+	if !ok {
+		logging.Logger.Debugf("Skipping Pulumi resource type %s as the package is not supported", pulumiType)
+		return ""
+	}
+
+	module := strings.Split(parts[1], "/")[0]
+	name := toSnakeCase(parts[2])
+
+	candidates := []string{
+		fmt.Sprintf("%s_%s_%s", prefix, toSnakeCase(module), name),
+		fmt.Sprintf("%s_%s", prefix, name),
+	}
+
+	registryMap := terraform.GetRunResourceRegistryMap(p.ctx.RunContext)
+	for _, c := range candidates {
+		if _, ok := (*registryMap)[c]; ok {
+			return c
+		}
+	}
+
+	// Use the type that includes the module so it's reported as unsupported
+	// with a name that can be traced back to the Pulumi resource type.
+	return candidates[0]
+}
+
+// providerConf returns the Terraform provider config of the default providers,
+// set from the stack config, and of the explicit providers of the preview.
+func (p *Parser) providerConf(parsed gjson.Result) map[string]interface{} {
+	conf := make(map[string]interface{})
+
+	stackConfig := parsed.Get("config")
+	addProviderConf(conf, "aws", "aws", map[string]string{"region": stackConfig.Get(gjsonEscape("aws:region")).String()})
+	addProviderConf(conf, "google", "google", map[string]string{
+		"region":  stackConfig.Get(gjsonEscape("gcp:region")).String(),
+		"project": stackConfig.Get(gjsonEscape("gcp:project")).String(),
+	})
+
+	for _, step := range parsed.Get("steps").Array() {
+		state := step.Get("newState")
+		if !state.Exists() {
+			state = step.Get("oldState")
+		}
+
+		t := state.Get("type").String()
+		if !strings.HasPrefix(t, "pulumi:providers:") {
+			continue
+		}
+
+		prefix, ok := providerPrefixes[strings.TrimPrefix(t, "pulumi:providers:")]
+		if !ok {
+			continue
+		}
+
+		urn := step.Get("urn").String()
+		inputs := state.Get("inputs")
+		addProviderConf(conf, prefix+"."+urnName(urn), prefix, map[string]string{
+			"region":  inputs.Get("region").String(),
+			"project": inputs.Get("project").String(),
+		})
+	}
+
+	return conf
+}
+
+func addProviderConf(conf map[string]interface{}, key string, name string, attrs map[string]string) {
+	expressions := make(map[string]interface{})
+	for k, v := range attrs {
+		if v == "" || v == unknownValue {
+			continue
+		}
+
+		expressions[k] = map[string]interface{}{"constant_value": v}
+	}
+
+	if len(expressions) == 0 {
+		return
+	}
+
+	conf[key] = map[string]interface{}{
+		"name":        name,
+		"expressions": expressions,
+	}
+}
+
+// providerConfKey returns the key of the provider config of a resource from the
+// reference to its provider, in the format <provider URN>::<provider ID>.
+func providerConfKey(resType string, refs ...string) string {
+	prefix := providerPrefix(resType)
+
+	for _, ref := range refs {
+		if ref == "" {
+			continue
+		}
+
+		i := strings.LastIndex(ref, "::")
+		if i == -1 {
+			continue
+		}
+
+		return prefix + "." + urnName(ref[:i])
+	}
+
+	return prefix
+}
+
+// resourceValues returns the attributes of the Pulumi resource state as
+// Terraform resource values. The outputs are used for any attributes that
+// aren't in the inputs, e.g. the ARN of a resource that already exists.
+func resourceValues(pulumiType string, state gjson.Result) map[string]interface{} {
+	attrs := make(map[string]interface{})
+
+	if outputs, ok := state.Get("outputs").Value().(map[string]interface{}); ok {
+		for k, v := range outputs {
+			attrs[k] = v
+		}
+	}
+
+	if inputs, ok := state.Get("inputs").Value().(map[string]interface{}); ok {
+		for k, v := range inputs {
+			attrs[k] = v
+		}
+	}
+
+	delete(attrs, "__defaults")
+
+	values := convertObject(attrs)
+	if convert, ok := valueConverters[pulumiType]; ok {
+		convert(values)
+	}
+
+	return values
+}
+
+// convertStorageAccountValues sets the tier and replication type of the storage
+// account from its SKU, e.g. Standard_LRS.
+func convertStorageAccountValues(values map[string]interface{}) {
+	if sku, ok := values["sku"].([]interface{}); ok && len(sku) > 0 {
+		if m, ok := sku[0].(map[string]interface{}); ok {
+			name, _ := m["name"].(string)
+			if tier, replication, ok := strings.Cut(name, "_"); ok {
+				values["account_tier"] = tier
+				values["account_replication_type"] = replication
+			}
+		}
+	}
+
+	if kind, ok := values["kind"]; ok {
+		values["account_kind"] = kind
+	}
+}
+
+// convertObject converts the keys of the Pulumi object to the snake case used
+// by Terraform. Pulumi flattens nested blocks that can only be set once into
+// objects, so these are converted back to lists with a single item, and lists
+// of nested blocks are pluralized, so these are also added with the singular
+// name of the Terraform block.
+func convertObject(obj map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{}, len(obj))
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := obj[k]
+		key := toSnakeCase(k)
+
+		if mapAttributes[k] {
+			converted[key] = v
+			continue
+		}
+
+		switch val := v.(type) {
+		case map[string]interface{}:
+			converted[key] = []interface{}{convertObject(val)}
+		case []interface{}:
+			list := convertList(val)
+			converted[key] = list
+
+			if singular := singularName(key); singular != key && isObjectList(list) {
+				if _, ok := obj[singular]; !ok {
+					converted[singular] = list
+				}
+			}
+		default:
+			converted[key] = convertValue(val)
+		}
+	}
+
+	return converted
+}
+
+func convertList(list []interface{}) []interface{} {
+	converted := make([]interface{}, 0, len(list))
+
+	for _, item := range list {
+		switch val := item.(type) {
+		case map[string]interface{}:
+			converted = append(converted, convertObject(val))
+		case []interface{}:
+			converted = append(converted, convertList(val))
+		default:
+			converted = append(converted, convertValue(val))
+		}
+	}
+
+	return converted
+}
+
+func convertValue(v interface{}) interface{} {
+	if s, ok := v.(string); ok && s == unknownValue {
+		return nil
+	}
+
+	return v
+}
+
+func isObjectList(list []interface{}) bool {
+	if len(list) == 0 {
+		return false
+	}
+
+	for _, item := range list {
+		if _, ok := item.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func singularName(name string) string {
+	if strings.HasSuffix(name, "ies") {
+		return strings.TrimSuffix(name, "ies") + "y"
+	}
+
+	return strings.TrimSuffix(name, "s")
+}
+
+// toSnakeCase converts a camel case Pulumi name to snake case, e.g.
+// instanceType to instance_type.
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteRune('_')
+			}
+		}
+
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
+
+// urnType returns the resource type of a Pulumi URN, in the format
+// urn:pulumi:<stack>::<project>::<parent types$><type>::<name>.
+func urnType(urn string) string {
+	parts := strings.Split(urn, "::")
+	if len(parts) < 4 {
+		return ""
+	}
+
+	types := strings.Split(parts[2], "$")
+	return types[len(types)-1]
+}
+
+// urnName returns the name of the resource of a Pulumi URN.
+func urnName(urn string) string {
+	parts := strings.Split(urn, "::")
+	return parts[len(parts)-1]
+}
+
+// uniqueAddress returns the Terraform address of the resource with the given
+// name. Pulumi names can contain characters that aren't valid in Terraform
+// addresses and only have to be unique with the same parent, so the address is
+// indexed if it's already been used.
+func uniqueAddress(resType string, name string, seen map[string]bool) string {
+	name = invalidNameChars.ReplaceAllString(name, "_")
+	addr := fmt.Sprintf("%s.%s", resType, name)
+
+	for i := 1; seen[addr]; i++ {
+		addr = fmt.Sprintf("%s.%s[%d]", resType, name, i)
+	}
+
+	seen[addr] = true
+	return addr
+}
+
+func appendResource(resources []planResource, r planResource) []planResource {
+	for i, existing := range resources {
+		if existing.address == r.address {
+			resources[i] = r
+			return resources
+		}
+	}
+
+	return append(resources, r)
+}
+
+func rootModule(resources []planResource) map[string]interface{} {
+	arr := make([]interface{}, 0, len(resources))
+	for _, r := range resources {
+		arr = append(arr, r.toJSON())
+	}
+
+	return map[string]interface{}{
+		"root_module": map[string]interface{}{
+			"resources": arr,
+		},
+	}
+}
+
+func resourceChanges(planned []planResource, prior []planResource) []interface{} {
+	var changes []interface{}
+
+	for _, r := range mergeResources(planned, prior) {
+		changes = append(changes, map[string]interface{}{
+			"address": r.address,
+			"mode":    "managed",
+			"type":    r.resType,
+			"name":    r.name,
+		})
+	}
+
+	return changes
+}
+
+func resourceConfigs(planned []planResource, prior []planResource) []interface{} {
+	var configs []interface{}
+
+	for _, r := range mergeResources(planned, prior) {
+		configs = append(configs, map[string]interface{}{
+			"address":             removeIndex(r.address),
+			"mode":                "managed",
+			"type":                r.resType,
+			"name":                removeIndex(r.name),
+			"provider_config_key": r.providerKey,
+		})
+	}
+
+	return configs
+}
+
+func mergeResources(planned []planResource, prior []planResource) []planResource {
+	merged := append([]planResource{}, planned...)
+	for _, r := range prior {
+		merged = appendResource(merged, r)
+	}
+
+	return merged
+}
+
+func removeIndex(addr string) string {
+	if i := strings.Index(addr, "["); i != -1 {
+		return addr[:i]
+	}
+
+	return addr
+}
+
+func providerPrefix(resType string) string {
+	return strings.Split(resType, "_")[0]
+}
+
+func gjsonEscape(s string) string {
+	return strings.ReplaceAll(s, ".", `\.`)
+}
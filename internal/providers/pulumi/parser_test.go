@@ -0,0 +1,103 @@
+package pulumi
+
+import (
+	"os"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+func TestToPlanJSON(t *testing.T) {
+	b, err := os.ReadFile("testdata/preview.json")
+	require.NoError(t, err)
+
+	p := NewParser(config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{}))
+	j, err := p.toPlanJSON(b)
+	require.NoError(t, err)
+
+	plan := gjson.ParseBytes(j)
+
+	planned := plan.Get("planned_values.root_module.resources.#.address").Value()
+	assert.Equal(t, []interface{}{
+		"aws_s3_bucket.assets",
+		"aws_instance.web",
+		"azurerm_storage_account.storage",
+		"azurerm_storage_queue.jobs",
+	}, planned)
+
+	prior := plan.Get("prior_state.values.root_module.resources.#.address").Value()
+	assert.Equal(t, []interface{}{"aws_instance.web", "aws_db_instance.db"}, prior)
+
+	bucket := plan.Get(`planned_values.root_module.resources.#(address="aws_s3_bucket.assets").values`)
+	assert.Equal(t, "assets-4f2a1c", bucket.Get("bucket").String())
+	assert.Equal(t, gjson.Null, bucket.Get("arn").Type)
+	assert.Equal(t, "dev", bucket.Get("tags.Environment").String())
+	assert.False(t, bucket.Get("__defaults").Exists())
+
+	instance := plan.Get(`planned_values.root_module.resources.#(address="aws_instance.web").values`)
+	assert.Equal(t, "t3.large", instance.Get("instance_type").String())
+	assert.Equal(t, int64(20), instance.Get("root_block_device.0.volume_size").Int())
+	assert.Equal(t, int64(100), instance.Get("ebs_block_device.0.volume_size").Int())
+	assert.Equal(t, "t3.micro", plan.Get(`prior_state.values.root_module.resources.#(address="aws_instance.web").values.instance_type`).String())
+
+	storage := plan.Get(`planned_values.root_module.resources.#(address="azurerm_storage_account.storage").values`)
+	assert.Equal(t, "Standard", storage.Get("account_tier").String())
+	assert.Equal(t, "GRS", storage.Get("account_replication_type").String())
+	assert.Equal(t, "StorageV2", storage.Get("account_kind").String())
+
+	assert.Equal(t, "us-east-1", plan.Get(`configuration.provider_config.aws.expressions.region.constant_value`).String())
+	assert.Equal(t, "us-west-2", plan.Get(`configuration.provider_config.aws\.west.expressions.region.constant_value`).String())
+	assert.Equal(t, "aws.west", plan.Get(`configuration.root_module.resources.#(address="aws_instance.web").provider_config_key`).String())
+	assert.Equal(t, "aws.default_6_0_0", plan.Get(`configuration.root_module.resources.#(address="aws_s3_bucket.assets").provider_config_key`).String())
+
+	changes := plan.Get("resource_changes.#.address").Value()
+	assert.Len(t, changes, 5)
+}
+
+func TestToPlanJSONInvalid(t *testing.T) {
+	p := NewParser(config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{}))
+
+	_, err := p.toPlanJSON([]byte(`{"format_version": "1.0"}`))
+	assert.EqualError(t, err, "no steps found, the file must be the output of pulumi preview --json")
+}
+
+func TestResourceType(t *testing.T) {
+	p := NewParser(config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{}))
+
+	tests := []struct {
+		pulumiType string
+		expected   string
+	}{
+		{"aws:s3/bucket:Bucket", "aws_s3_bucket"},
+		{"aws:ec2/instance:Instance", "aws_instance"},
+		{"aws:lambda/function:Function", "aws_lambda_function"},
+		{"aws:ec2/natGateway:NatGateway", "aws_nat_gateway"},
+		{"aws:rds/instance:Instance", "aws_db_instance"},
+		{"azure:compute/linuxVirtualMachine:LinuxVirtualMachine", "azurerm_linux_virtual_machine"},
+		{"azure-native:storage:StorageAccount", "azurerm_storage_account"},
+		{"azure-native:storage:Queue", "azurerm_storage_queue"},
+		{"gcp:compute/instance:Instance", "google_compute_instance"},
+		{"gcp:sql/databaseInstance:DatabaseInstance", "google_sql_database_instance"},
+		{"aws:made/up:Thing", "aws_made_thing"},
+		{"pulumi:providers:aws", ""},
+		{"kubernetes:apps/v1:Deployment", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pulumiType, func(t *testing.T) {
+			assert.Equal(t, tt.expected, p.resourceType(tt.pulumiType))
+		})
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	assert.Equal(t, "instance_type", toSnakeCase("instanceType"))
+	assert.Equal(t, "ipv6_cidr_block", toSnakeCase("ipv6CidrBlock"))
+	assert.Equal(t, "storage_account", toSnakeCase("StorageAccount"))
+	assert.Equal(t, "http_endpoint", toSnakeCase("HTTPEndpoint"))
+}
@@ -0,0 +1,71 @@
+package pulumi
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+type PreviewJSONProvider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+}
+
+func NewPreviewJSONProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &PreviewJSONProvider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *PreviewJSONProvider) Type() string {
+	return "pulumi_preview_json"
+}
+
+func (p *PreviewJSONProvider) DisplayType() string {
+	return "Pulumi preview JSON file"
+}
+
+func (p *PreviewJSONProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+// LoadResources converts the Pulumi preview to a Terraform plan JSON so the
+// resources are parsed, and their past resources diffed, the same way as
+// resources of Terraform projects.
+func (p *PreviewJSONProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
+	spinner := ui.NewSpinner("Extracting only cost-related params from Pulumi", ui.SpinnerOptions{
+		EnableLogging: p.ctx.RunContext.Config.IsLogging(),
+		NoColor:       p.ctx.RunContext.Config.NoColor,
+		Indent:        "  ",
+	})
+	defer spinner.Fail()
+
+	b, err := os.ReadFile(p.Path)
+	if err != nil {
+		return []*schema.Project{}, fmt.Errorf("Error reading Pulumi preview JSON file %w", err)
+	}
+
+	j, err := NewParser(p.ctx).toPlanJSON(b)
+	if err != nil {
+		return []*schema.Project{}, fmt.Errorf("Error parsing Pulumi preview JSON file %w", err)
+	}
+
+	project, err := terraform.NewPlanJSONProvider(p.ctx, p.includePastResources).LoadResourcesFromSrc(usage, j, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	project.Metadata.Type = p.Type()
+	p.AddMetadata(project.Metadata)
+
+	spinner.Success()
+
+	return []*schema.Project{project}, nil
+}
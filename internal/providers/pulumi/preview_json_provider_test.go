@@ -0,0 +1,43 @@
+package pulumi
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestPreviewJSONProviderLoadResources(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: "testdata/preview.json", Name: "app"}, log.Fields{})
+
+	projects, err := NewPreviewJSONProvider(ctx, true).LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	project := projects[0]
+	assert.Equal(t, "app", project.Name)
+	assert.Equal(t, "pulumi_preview_json", project.Metadata.Type)
+
+	regions := make(map[string]string)
+	for _, r := range project.PartialResources {
+		regions[r.ResourceData.Address] = r.ResourceData.Get("region").String()
+	}
+
+	assert.Equal(t, map[string]string{
+		"aws_s3_bucket.assets":            "us-east-1",
+		"aws_instance.web":                "us-west-2",
+		"azurerm_storage_account.storage": "eastus",
+		"azurerm_storage_queue.jobs":      "eastus",
+	}, regions)
+
+	var pastAddresses []string
+	for _, r := range project.PartialPastResources {
+		pastAddresses = append(pastAddresses, r.ResourceData.Address)
+	}
+
+	assert.ElementsMatch(t, []string{"aws_instance.web", "aws_db_instance.db"}, pastAddresses)
+}
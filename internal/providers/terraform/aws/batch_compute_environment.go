@@ -0,0 +1,35 @@
+package aws
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getBatchComputeEnvironmentRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:      "aws_batch_compute_environment",
+		CoreRFunc: NewBatchComputeEnvironment,
+	}
+}
+
+func NewBatchComputeEnvironment(d *schema.ResourceData) schema.CoreResource {
+	// Instance types can be specific types, families like "c5" or "optimal".
+	// Only specific types can be priced without usage.
+	var instanceType string
+	for _, t := range d.Get("compute_resources.0.instance_type").Array() {
+		if strings.Contains(t.String(), ".") {
+			instanceType = t.String()
+			break
+		}
+	}
+
+	return &aws.BatchComputeEnvironment{
+		Address:      d.Address,
+		Region:       d.Get("region").String(),
+		Type:         strings.ToUpper(d.Get("compute_resources.0.type").String()),
+		InstanceType: instanceType,
+		Unmanaged:    strings.EqualFold(d.Get("type").String(), "UNMANAGED"),
+	}
+}
@@ -0,0 +1,36 @@
+package aws
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getBedrockRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_bedrock",
+		RFunc: newBedrock,
+	}
+}
+
+func newBedrock(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := "us-east-1"
+	if u != nil && u.Get("region").String() != "" {
+		region = strings.ToLower(u.Get("region").String())
+	}
+
+	model := ""
+	if u != nil {
+		model = u.Get("model").String()
+	}
+
+	r := &aws.BedrockModelInvocation{
+		Address: d.Address,
+		Region:  region,
+		Model:   model,
+	}
+	r.PopulateUsage(u)
+
+	return r.BuildResource()
+}
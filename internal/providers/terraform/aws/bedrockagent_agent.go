@@ -0,0 +1,24 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getBedrockAgentAgentRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_bedrockagent_agent",
+		RFunc: newBedrockAgentAgent,
+	}
+}
+
+func newBedrockAgentAgent(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &aws.BedrockModelInvocation{
+		Address: d.Address,
+		Region:  d.Get("region").String(),
+		Model:   d.Get("foundation_model").String(),
+	}
+	r.PopulateUsage(u)
+
+	return r.BuildResource()
+}
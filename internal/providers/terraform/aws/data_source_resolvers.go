@@ -0,0 +1,71 @@
+package aws
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// DataSourceResolvers resolve the pricing hints of the AWS data sources.
+var DataSourceResolvers = map[string]schema.DataSourceResolver{
+	"aws_ami": resolveAMIHints,
+}
+
+// amiFilterNames are the filters of aws_ami data sources with values that
+// describe the operating system of the image.
+var amiFilterNames = map[string]bool{
+	"name":             true,
+	"description":      true,
+	"platform":         true,
+	"platform-details": true,
+}
+
+// resolveAMIHints returns the operating system of the AMI. The platform details
+// are only known from a plan, so the name and filters of the data source are
+// used otherwise, e.g. when parsing HCL.
+func resolveAMIHints(d *schema.ResourceData) map[string]string {
+	candidates := []string{
+		d.Get("platform_details").String(),
+		d.Get("platform").String(),
+		d.Get("name").String(),
+		d.Get("name_regex").String(),
+	}
+
+	for _, f := range d.Get("filter").Array() {
+		if !amiFilterNames[f.Get("name").String()] {
+			continue
+		}
+
+		for _, v := range f.Get("values").Array() {
+			candidates = append(candidates, v.String())
+		}
+	}
+
+	for _, c := range candidates {
+		if os := amiOperatingSystem(c); os != "" {
+			return map[string]string{schema.OperatingSystemHint: os}
+		}
+	}
+
+	return nil
+}
+
+// amiOperatingSystem returns the operating_system usage value of the platform
+// details or name of an AMI, or an empty string if it isn't recognized.
+func amiOperatingSystem(s string) string {
+	s = strings.ToLower(s)
+
+	switch {
+	case strings.Contains(s, "windows"):
+		return "windows"
+	case strings.Contains(s, "red hat") || strings.Contains(s, "rhel"):
+		return "rhel"
+	case strings.Contains(s, "suse") || strings.Contains(s, "sles"):
+		return "suse"
+	case s == "linux/unix" || strings.Contains(s, "amzn") || strings.Contains(s, "amazon linux") ||
+		strings.Contains(s, "ubuntu") || strings.Contains(s, "debian"):
+		return "linux"
+	}
+
+	return ""
+}
@@ -27,6 +27,7 @@ func NewDBInstance(d *schema.ResourceData) schema.CoreResource {
 
 	r := &aws.DBInstance{
 		Address:                              d.Address,
+		Identifier:                           d.RawValues.Get("identifier").String(),
 		Region:                               d.Get("region").String(),
 		InstanceClass:                        d.Get("instance_class").String(),
 		Engine:                               engine,
@@ -2,6 +2,7 @@ package aws
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/infracost/infracost/internal/resources/aws"
 	"github.com/infracost/infracost/internal/schema"
@@ -14,7 +15,7 @@ func getInstanceRegistryItem() *schema.RegistryItem {
 		Name: "aws_instance",
 		Notes: []string{
 			"Costs associated with marketplace AMIs are not supported.",
-			"For non-standard Linux AMIs such as Windows and RHEL, the operating system should be specified in usage file.",
+			"For non-standard Linux AMIs such as Windows and RHEL, the operating system should be specified in usage file, unless the AMI is found by an aws_ami data source.",
 			"EC2 detailed monitoring assumes the standard 7 metrics and the lowest tier of prices for CloudWatch.",
 			"If a root volume is not specified then an 8Gi gp2 volume is assumed.",
 		},
@@ -92,6 +93,19 @@ func NewInstance(d *schema.ResourceData) schema.CoreResource {
 		HasHost:          hasHost,
 	}
 
+	// Only existing instances have utilization metrics, the IDs that are
+	// generated for instances parsed from HCL aren't real EC2 instance IDs. The
+	// ID is read from the raw values as it isn't an input of the cost.
+	if id := d.RawValues.Get("id").String(); strings.HasPrefix(id, "i-") {
+		a.InstanceID = id
+	}
+
+	// The operating system of the AMI data source, if any, is used unless it's
+	// set in the usage file.
+	if os, ok := d.PricingHints[schema.OperatingSystemHint]; ok {
+		a.OperatingSystem = strPtr(os)
+	}
+
 	a.RootBlockDevice = &aws.EBSVolume{
 		Address: "root_block_device",
 		Region:  region,
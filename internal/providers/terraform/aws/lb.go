@@ -39,3 +39,49 @@ func NewLB(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
 	r.PopulateUsage(u)
 	return r.BuildResource()
 }
+
+func getLBListenerRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "aws_lb_listener",
+		ReferenceAttributes: []string{
+			"load_balancer_arn",
+		},
+		RFunc: NewLBListener,
+	}
+}
+
+func getALBListenerRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "aws_alb_listener",
+		ReferenceAttributes: []string{
+			"load_balancer_arn",
+		},
+		RFunc: NewLBListener,
+	}
+}
+
+func NewLBListener(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &aws.LBListener{
+		Address: d.Address,
+		Region:  d.Get("region").String(),
+	}
+
+	lbRefs := d.References("load_balancer_arn")
+	if len(lbRefs) > 0 {
+		lb := lbRefs[0]
+
+		loadBalancerType := lb.Get("load_balancer_type").String()
+		if loadBalancerType == "" {
+			loadBalancerType = "application"
+		}
+
+		r.LoadBalancer = &aws.LB{
+			Address:          lb.Address,
+			Region:           lb.Get("region").String(),
+			LoadBalancerType: loadBalancerType,
+		}
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -0,0 +1,20 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getOpenSearchServerlessCollectionRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:      "aws_opensearchserverless_collection",
+		CoreRFunc: NewOpenSearchServerlessCollection,
+	}
+}
+
+func NewOpenSearchServerlessCollection(d *schema.ResourceData) schema.CoreResource {
+	return &aws.OpenSearchServerlessCollection{
+		Address: d.Address,
+		Region:  d.Get("region").String(),
+	}
+}
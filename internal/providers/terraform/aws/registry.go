@@ -11,6 +11,8 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	getACMCertificate(),
 	getACMPCACertificateAuthorityRegistryItem(),
 	getBackupVaultRegistryItem(),
+	getBedrockRegistryItem(),
+	getBedrockAgentAgentRegistryItem(),
 	getCloudFormationStackRegistryItem(),
 	getCloudFormationStackSetRegistryItem(),
 	getCloudfrontDistributionRegistryItem(),
@@ -54,6 +56,7 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	getElastiCacheClusterItem(),
 	getElastiCacheReplicationGroupItem(),
 	getElasticsearchDomainRegistryItem(),
+	getOpenSearchServerlessCollectionRegistryItem(),
 	getELBRegistryItem(),
 	getFlowLogRegistryItem(),
 	getFSxOpenZFSFSRegistryItem(),
@@ -71,6 +74,8 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	getLightsailInstanceRegistryItem(),
 	getMSKClusterRegistryItem(),
 	getALBRegistryItem(),
+	getLBListenerRegistryItem(),
+	getALBListenerRegistryItem(),
 	getMQBrokerRegistryItem(),
 	getMWAAEnvironmentRegistryItem(),
 	getNATGatewayRegistryItem(),
@@ -85,6 +90,8 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	getS3BucketInventoryRegistryItem(),
 	getS3BucketLifecycleConfigurationRegistryItem(),
 	getS3BucketRegistryItem(),
+	getS3BucketReplicationConfigurationRegistryItem(),
+	getS3BucketRequestPaymentConfigurationRegistryItem(),
 	getSecretsManagerSecret(),
 	getSSMActivationRegistryItem(),
 	getSSMParameterRegistryItem(),
@@ -111,6 +118,7 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	getGlobalacceleratorEndpointGroupRegistryItem(),
 	getEC2HostRegistryItem(),
 	getLambdaProvisionedConcurrencyConfigRegistryItem(),
+	getBatchComputeEnvironmentRegistryItem(),
 }
 
 // FreeResources grouped alphabetically
@@ -165,6 +173,18 @@ var FreeResources = []string{
 	"aws_backup_vault_notifications",
 	"aws_backup_vault_policy",
 
+	// AWS Batch
+	"aws_batch_job_definition",
+	"aws_batch_job_queue",
+	"aws_batch_scheduling_policy",
+
+	// Amazon Bedrock
+	"aws_bedrockagent_agent_action_group",
+	"aws_bedrockagent_agent_alias",
+	"aws_bedrockagent_agent_knowledge_base_association",
+	"aws_bedrockagent_data_source",
+	"aws_bedrockagent_knowledge_base",
+
 	// AWS DX Transit.
 	"aws_dx_bgp_peer",
 	"aws_dx_gateway",
@@ -228,12 +248,10 @@ var FreeResources = []string{
 	"aws_efs_mount_target",
 
 	// AWS Elastic Load Balancing
-	"aws_alb_listener",
 	"aws_alb_listener_certificate",
 	"aws_alb_listener_rule",
 	"aws_alb_target_group",
 	"aws_alb_target_group_attachment",
-	"aws_lb_listener",
 	"aws_lb_listener_certificate",
 	"aws_lb_listener_rule",
 	"aws_lb_target_group",
@@ -351,6 +369,10 @@ var FreeResources = []string{
 	"aws_lightsail_static_ip_attachment",
 	"aws_mq_configuration",
 	"aws_msk_configuration",
+	"aws_opensearchserverless_access_policy",
+	"aws_opensearchserverless_lifecycle_policy",
+	"aws_opensearchserverless_security_config",
+	"aws_opensearchserverless_security_policy",
 	"aws_rds_cluster_endpoint",
 	"aws_rds_cluster_parameter_group",
 	"aws_resourcegroups_group",
@@ -503,4 +525,5 @@ var FreeResources = []string{
 
 var UsageOnlyResources = []string{
 	"aws_data_transfer",
+	"aws_bedrock",
 }
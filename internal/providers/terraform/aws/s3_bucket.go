@@ -1,6 +1,10 @@
 package aws
 
 import (
+	"sort"
+
+	"github.com/tidwall/gjson"
+
 	"github.com/infracost/infracost/internal/resources/aws"
 
 	"github.com/infracost/infracost/internal/schema"
@@ -11,10 +15,14 @@ func getS3BucketRegistryItem() *schema.RegistryItem {
 		Name: "aws_s3_bucket",
 		Notes: []string{
 			"S3 replication time control data transfer, and batch operations are not supported by Terraform.",
+			"Replication is priced as cross-region for destination buckets that are not in the Terraform project.",
 		},
 		CoreRFunc: NewS3BucketResource,
 		ReferenceAttributes: []string{
 			"aws_s3_bucket_lifecycle_configuration.bucket",
+			"aws_s3_bucket_replication_configuration.bucket",
+			"aws_s3_bucket_request_payment_configuration.bucket",
+			"replication_configuration.0.rules.#.destination.0.bucket",
 			"aws_cloudfront_distribution.origin.0.domain_name",
 			"aws_cloudfront_distribution.origin.0.origin_id",
 		},
@@ -70,11 +78,82 @@ func NewS3BucketResource(d *schema.ResourceData) schema.CoreResource {
 		lifecycleStorageClasses = append(lifecycleStorageClasses, storageClass)
 	}
 
+	requesterPays := d.Get("request_payer").String() == "Requester"
+	for _, ref := range d.References("aws_s3_bucket_request_payment_configuration.bucket") {
+		requesterPays = ref.Get("payer").String() == "Requester"
+	}
+
+	replicationRegions := s3ReplicationDestinationRegions(
+		d.Get("replication_configuration.0.rules").Array(),
+		d.References("replication_configuration.0.rules.#.destination.0.bucket"),
+	)
+	for _, ref := range d.References("aws_s3_bucket_replication_configuration.bucket") {
+		replicationRegions = append(replicationRegions, s3ReplicationDestinationRegions(
+			ref.Get("rule").Array(),
+			ref.References("rule.#.destination.0.bucket"),
+		)...)
+	}
+
 	return &aws.S3Bucket{
-		Address:                 d.Address,
-		Region:                  d.Get("region").String(),
-		Name:                    d.Get("bucket").String(),
-		ObjectTagsEnabled:       objTagsEnabled,
-		LifecycleStorageClasses: lifecycleStorageClasses,
+		Address:                       d.Address,
+		Region:                        d.Get("region").String(),
+		Name:                          d.Get("bucket").String(),
+		ObjectTagsEnabled:             objTagsEnabled,
+		RequesterPays:                 requesterPays,
+		LifecycleStorageClasses:       lifecycleStorageClasses,
+		ReplicationDestinationRegions: uniqueReplicationRegions(replicationRegions),
+	}
+}
+
+// s3ReplicationDestinationRegions returns the regions of the destination
+// buckets that objects are replicated to by the rules of a replication
+// configuration. destinations are the buckets that the rules reference. An
+// empty region is returned if rules are enabled but none of their destination
+// buckets are known.
+func s3ReplicationDestinationRegions(rules []gjson.Result, destinations []*schema.ResourceData) []string {
+	enabled := false
+	for _, rule := range rules {
+		if rule.Get("status").String() == "Enabled" {
+			enabled = true
+			break
+		}
+	}
+
+	if !enabled {
+		return nil
+	}
+
+	if len(destinations) == 0 {
+		return []string{""}
 	}
+
+	regions := make([]string, 0, len(destinations))
+	for _, dest := range destinations {
+		regions = append(regions, dest.Get("region").String())
+	}
+
+	return regions
+}
+
+// uniqueReplicationRegions returns the distinct regions sorted so that any
+// unknown region is last.
+func uniqueReplicationRegions(regions []string) []string {
+	seen := make(map[string]bool, len(regions))
+	unique := make([]string, 0, len(regions))
+
+	for _, region := range regions {
+		if seen[region] {
+			continue
+		}
+
+		seen[region] = true
+		unique = append(unique, region)
+	}
+
+	sort.Strings(unique)
+	if len(unique) > 0 && unique[0] == "" {
+		unique = append(unique[1:], "")
+	}
+
+	return unique
 }
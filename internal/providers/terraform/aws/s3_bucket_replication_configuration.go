@@ -0,0 +1,17 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getS3BucketReplicationConfigurationRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "aws_s3_bucket_replication_configuration",
+		ReferenceAttributes: []string{
+			"bucket",
+			"rule.#.destination.0.bucket",
+		},
+		NoPrice: true,
+		Notes:   []string{"Free resource. Replication costs are shown on the source aws_s3_bucket."},
+	}
+}
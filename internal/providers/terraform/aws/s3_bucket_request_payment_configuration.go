@@ -0,0 +1,14 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getS3BucketRequestPaymentConfigurationRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:                "aws_s3_bucket_request_payment_configuration",
+		ReferenceAttributes: []string{"bucket"},
+		NoPrice:             true,
+		Notes:               []string{"Free resource."},
+	}
+}
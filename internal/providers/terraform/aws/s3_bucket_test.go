@@ -23,3 +23,12 @@ func TestS3BucketV3GoldenFile(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "s3_bucket_v3_test")
 }
+
+func TestS3BucketReplicationGoldenFile(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	tftest.GoldenFileResourceTests(t, "s3_bucket_replication_test")
+}
@@ -0,0 +1,88 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+  skip_get_ec2_platforms      = true
+  skip_region_validation      = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_batch_compute_environment" "ec2" {
+  compute_environment_name = "ec2"
+  service_role             = "arn:aws:iam::123456789012:role/batch"
+  type                     = "MANAGED"
+
+  compute_resources {
+    instance_role      = "arn:aws:iam::123456789012:instance-profile/ecs"
+    instance_type      = ["c5", "m5.large"]
+    max_vcpus          = 16
+    min_vcpus          = 0
+    security_group_ids = ["sg-123456"]
+    subnets            = ["subnet-123456"]
+    type               = "EC2"
+  }
+}
+
+resource "aws_batch_compute_environment" "ec2_with_usage" {
+  compute_environment_name = "ec2_with_usage"
+  service_role             = "arn:aws:iam::123456789012:role/batch"
+  type                     = "MANAGED"
+
+  compute_resources {
+    instance_role      = "arn:aws:iam::123456789012:instance-profile/ecs"
+    instance_type      = ["m5.large"]
+    max_vcpus          = 16
+    min_vcpus          = 0
+    security_group_ids = ["sg-123456"]
+    subnets            = ["subnet-123456"]
+    type               = "EC2"
+  }
+}
+
+resource "aws_batch_compute_environment" "optimal_with_usage" {
+  compute_environment_name = "optimal_with_usage"
+  service_role             = "arn:aws:iam::123456789012:role/batch"
+  type                     = "MANAGED"
+
+  compute_resources {
+    instance_role      = "arn:aws:iam::123456789012:instance-profile/ecs"
+    instance_type      = ["optimal"]
+    max_vcpus          = 16
+    min_vcpus          = 0
+    security_group_ids = ["sg-123456"]
+    subnets            = ["subnet-123456"]
+    type               = "EC2"
+  }
+}
+
+resource "aws_batch_compute_environment" "fargate_with_usage" {
+  compute_environment_name = "fargate_with_usage"
+  service_role             = "arn:aws:iam::123456789012:role/batch"
+  type                     = "MANAGED"
+
+  compute_resources {
+    max_vcpus          = 16
+    security_group_ids = ["sg-123456"]
+    subnets            = ["subnet-123456"]
+    type               = "FARGATE"
+  }
+}
+
+resource "aws_batch_compute_environment" "unmanaged" {
+  compute_environment_name = "unmanaged"
+  service_role             = "arn:aws:iam::123456789012:role/batch"
+  type                     = "UNMANAGED"
+}
+
+resource "aws_batch_job_queue" "queue" {
+  name     = "queue"
+  state    = "ENABLED"
+  priority = 1
+
+  compute_environments = [
+    aws_batch_compute_environment.ec2.arn,
+    aws_batch_compute_environment.fargate_with_usage.arn,
+  ]
+}
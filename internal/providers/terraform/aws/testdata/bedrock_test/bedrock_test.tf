@@ -0,0 +1,29 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+  skip_get_ec2_platforms      = true
+  skip_region_validation      = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_bedrockagent_agent" "with_usage" {
+  agent_name              = "with-usage"
+  agent_resource_role_arn = "arn:aws:iam::123456789012:role/AmazonBedrockExecutionRoleForAgents_example"
+  foundation_model        = "anthropic.claude-3-5-sonnet-20240620-v1:0"
+  instruction             = "You are a helpful assistant that answers questions about the weather."
+}
+
+resource "aws_bedrockagent_agent" "without_usage" {
+  agent_name              = "without-usage"
+  agent_resource_role_arn = "arn:aws:iam::123456789012:role/AmazonBedrockExecutionRoleForAgents_example"
+  foundation_model        = "anthropic.claude-3-haiku-20240307-v1:0"
+  instruction             = "You are a helpful assistant that answers questions about the weather."
+}
+
+resource "aws_bedrockagent_agent_alias" "example" {
+  agent_alias_name = "example"
+  agent_id         = aws_bedrockagent_agent.with_usage.agent_id
+}
@@ -115,6 +115,11 @@ resource "aws_instance" "instance1_detailedMonitoring" {
   monitoring    = true
 }
 
+resource "aws_instance" "savings_plan" {
+  ami           = "fake_ami"
+  instance_type = "t3.medium"
+}
+
 resource "aws_instance" "std_1yr_no_upfront" {
   ami           = "fake_ami"
   instance_type = "t3.medium"
@@ -0,0 +1,95 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+  skip_get_ec2_platforms      = true
+  skip_region_validation      = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+provider "aws" {
+  alias                       = "west"
+  region                      = "us-west-2"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+  skip_get_ec2_platforms      = true
+  skip_region_validation      = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_s3_bucket" "source" {
+  bucket = "source"
+}
+
+resource "aws_s3_bucket" "destination" {
+  provider = aws.west
+  bucket   = "destination"
+}
+
+resource "aws_s3_bucket_replication_configuration" "source" {
+  role   = "arn:aws:iam::123456789012:role/replication"
+  bucket = aws_s3_bucket.source.id
+
+  rule {
+    id     = "all"
+    status = "Enabled"
+
+    destination {
+      bucket        = aws_s3_bucket.destination.arn
+      storage_class = "STANDARD"
+    }
+  }
+}
+
+resource "aws_s3_bucket" "unknown_destination" {
+  bucket = "unknown_destination"
+}
+
+resource "aws_s3_bucket_replication_configuration" "unknown_destination" {
+  role   = "arn:aws:iam::123456789012:role/replication"
+  bucket = aws_s3_bucket.unknown_destination.id
+
+  rule {
+    id     = "all"
+    status = "Enabled"
+
+    destination {
+      bucket = "arn:aws:s3:::external-bucket"
+    }
+  }
+}
+
+resource "aws_s3_bucket" "disabled_replication" {
+  bucket = "disabled_replication"
+}
+
+resource "aws_s3_bucket_replication_configuration" "disabled_replication" {
+  role   = "arn:aws:iam::123456789012:role/replication"
+  bucket = aws_s3_bucket.disabled_replication.id
+
+  rule {
+    id     = "all"
+    status = "Disabled"
+
+    destination {
+      bucket = aws_s3_bucket.destination.arn
+    }
+  }
+}
+
+resource "aws_s3_bucket" "egress" {
+  bucket = "egress"
+}
+
+resource "aws_s3_bucket" "requester_pays" {
+  bucket = "requester_pays"
+}
+
+resource "aws_s3_bucket_request_payment_configuration" "requester_pays" {
+  bucket = aws_s3_bucket.requester_pays.id
+  payer  = "Requester"
+}
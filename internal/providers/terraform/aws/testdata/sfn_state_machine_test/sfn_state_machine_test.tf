@@ -122,3 +122,22 @@ resource "aws_sfn_state_machine" "standard" {
 }
 EOF
 }
+
+resource "aws_sfn_state_machine" "standardRequests" {
+  name       = "my-state-machine"
+  role_arn   = "arn:aws:lambda:us-east-1:123456789012:resource-id"
+  type       = "STANDARD"
+  definition = <<EOF
+{
+  "Comment": "A Hello World example of the Amazon States Language using an AWS Lambda Function",
+  "StartAt": "HelloWorld",
+  "States": {
+    "HelloWorld": {
+      "Type": "Task",
+      "Resource": "fake123",
+      "End": true
+    }
+  }
+}
+EOF
+}
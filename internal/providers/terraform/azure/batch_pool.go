@@ -0,0 +1,92 @@
+package azure
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getBatchPoolRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_batch_pool",
+		RFunc: NewAzureRMBatchPool,
+		ReferenceAttributes: []string{
+			"account_name",
+			"resource_group_name",
+		},
+		Notes: []string{
+			"Pools that use auto_scale need the dedicated_nodes and low_priority_nodes usage keys to be priced.",
+		},
+	}
+}
+
+// NewAzureRMBatchPool prices the virtual machines of the dedicated and
+// low-priority nodes of a Batch pool. Batch itself is free, as is CycleCloud,
+// which runs the clusters it manages on virtual machines that are priced as
+// their own resources.
+func NewAzureRMBatchPool(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := lookupRegion(d, []string{"account_name", "resource_group_name"})
+
+	instanceType := d.Get("vm_size").String()
+	windows := strings.Contains(strings.ToLower(d.Get("node_agent_sku_id").String()), "windows")
+
+	dedicatedNodes := batchPoolNodes(d, u, "dedicated_nodes", "fixed_scale.0.target_dedicated_nodes")
+	lowPriorityNodes := batchPoolNodes(d, u, "low_priority_nodes", "fixed_scale.0.target_low_priority_nodes")
+
+	var monthlyHours, lowPriorityDiscountRate *float64
+	if u != nil {
+		monthlyHours = u.GetFloat("monthly_hrs")
+		lowPriorityDiscountRate = u.GetFloat("low_priority_discount_rate")
+	}
+
+	costComponents := []*schema.CostComponent{
+		batchPoolNodesCostComponent(region, instanceType, windows, monthlyHours, dedicatedNodes, nil),
+	}
+
+	if lowPriorityNodes == nil || !lowPriorityNodes.IsZero() {
+		lowPriority := azure.NewLowPriorityPurchaseOption(d.Address, lowPriorityDiscountRate)
+		costComponents = append(costComponents, batchPoolNodesCostComponent(region, instanceType, windows, monthlyHours, lowPriorityNodes, lowPriority))
+	}
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
+
+// batchPoolNodes returns the number of nodes of a pool from the usage key, or
+// from the fixed scale attribute if it's not set. It returns nil if the pool
+// uses auto scaling and the usage key isn't set.
+func batchPoolNodes(d *schema.ResourceData, u *schema.UsageData, usageKey, attribute string) *decimal.Decimal {
+	if u != nil && u.Get(usageKey).Type != gjson.Null {
+		return decimalPtr(decimal.NewFromInt(u.Get(usageKey).Int()))
+	}
+
+	if d.IsEmpty("fixed_scale") {
+		return nil
+	}
+
+	return decimalPtr(decimal.NewFromInt(d.Get(attribute).Int()))
+}
+
+func batchPoolNodesCostComponent(region, instanceType string, windows bool, monthlyHours *float64, nodes *decimal.Decimal, commitment *azure.PurchaseOption) *schema.CostComponent {
+	var c *schema.CostComponent
+	if windows {
+		c = windowsVirtualMachineCostComponent(region, instanceType, "", monthlyHours, commitment)
+	} else {
+		c = linuxVirtualMachineCostComponent(region, instanceType, monthlyHours, commitment)
+	}
+
+	if nodes == nil {
+		c.MonthlyQuantity = nil
+		return c
+	}
+
+	c.MonthlyQuantity = decimalPtr(c.MonthlyQuantity.Mul(*nodes))
+
+	return c
+}
@@ -0,0 +1,53 @@
+package azure
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getContainerAppRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_container_app",
+		RFunc: newContainerApp,
+		ReferenceAttributes: []string{
+			"container_app_environment_id",
+			"resource_group_name",
+		},
+	}
+}
+
+func newContainerApp(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := lookupRegion(d, []string{"container_app_environment_id", "resource_group_name"})
+
+	var vCPU, memoryGiB float64
+	for _, c := range d.Get("template.0.container").Array() {
+		vCPU += c.Get("cpu").Float()
+		memoryGiB += parseContainerAppMemory(c.Get("memory").String())
+	}
+
+	r := &azure.ContainerApp{
+		Address:             d.Address,
+		Region:              region,
+		WorkloadProfileName: d.Get("workload_profile_name").String(),
+		VCPU:                vCPU,
+		MemoryGiB:           memoryGiB,
+		MinReplicas:         d.GetInt64OrDefault("template.0.min_replicas", 0),
+	}
+	r.PopulateUsage(u)
+
+	return r.BuildResource()
+}
+
+// parseContainerAppMemory parses the memory of a container, which is set in
+// the format '0.5Gi', into GiB.
+func parseContainerAppMemory(memory string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSuffix(memory, "Gi"), 64)
+	if err != nil {
+		return 0
+	}
+
+	return f
+}
@@ -0,0 +1,38 @@
+package azure
+
+import (
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getContainerAppEnvironmentRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_container_app_environment",
+		RFunc: newContainerAppEnvironment,
+		ReferenceAttributes: []string{
+			"resource_group_name",
+		},
+	}
+}
+
+func newContainerAppEnvironment(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := lookupRegion(d, []string{"resource_group_name"})
+
+	var profiles []azure.ContainerAppWorkloadProfile
+	for _, p := range d.Get("workload_profile").Array() {
+		profiles = append(profiles, azure.ContainerAppWorkloadProfile{
+			Name:         p.Get("name").String(),
+			Type:         p.Get("workload_profile_type").String(),
+			MinimumCount: p.Get("minimum_count").Int(),
+		})
+	}
+
+	r := &azure.ContainerAppEnvironment{
+		Address:          d.Address,
+		Region:           region,
+		WorkloadProfiles: profiles,
+	}
+	r.PopulateUsage(u)
+
+	return r.BuildResource()
+}
@@ -8,6 +8,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
 
+	"github.com/infracost/infracost/internal/resources/azure"
 	"github.com/infracost/infracost/internal/schema"
 )
 
@@ -87,12 +88,20 @@ func cosmosDBCostComponents(d *schema.ResourceData, u *schema.UsageData, account
 	}
 
 	if model == Provisioned || model == Autoscale {
+		// Savings plans don't apply to Cosmos DB, only reserved capacity
+		commitment := lookupPurchaseOption(d, u, false)
+		if commitment != nil && model == Autoscale {
+			log.Warnf("Reserved capacity is only supported for Cosmos DB resources with manual throughput, using pay as you go prices for %s", d.Address)
+			commitment = nil
+		}
+
 		costComponents = provisionedCosmosCostComponents(
 			model,
 			throughputs,
 			geoLocations,
 			skuName,
-			u)
+			u,
+			commitment)
 	}
 
 	costComponents = append(costComponents, storageCosmosCostComponents(account, u, geoLocations, skuName)...)
@@ -106,7 +115,7 @@ func cosmosDBCostComponents(d *schema.ResourceData, u *schema.UsageData, account
 	return costComponents
 }
 
-func provisionedCosmosCostComponents(model modelType, throughputs *decimal.Decimal, zones []gjson.Result, skuName string, u *schema.UsageData) []*schema.CostComponent {
+func provisionedCosmosCostComponents(model modelType, throughputs *decimal.Decimal, zones []gjson.Result, skuName string, u *schema.UsageData, commitment *azure.PurchaseOption) []*schema.CostComponent {
 	costComponents := []*schema.CostComponent{}
 
 	var meterName string
@@ -125,6 +134,8 @@ func provisionedCosmosCostComponents(model modelType, throughputs *decimal.Decim
 		} else {
 			throughputs = nil
 		}
+	} else if commitment != nil {
+		name = fmt.Sprintf("%s (%s", name, commitment.Label)
 	} else {
 		name = fmt.Sprintf("%s (provisioned", name)
 	}
@@ -152,7 +163,7 @@ func provisionedCosmosCostComponents(model modelType, throughputs *decimal.Decim
 
 		location := g.Get("location").String()
 		if l := locationNameMapping(location); l != "" {
-			costComponents = append(costComponents, &schema.CostComponent{
+			c := &schema.CostComponent{
 				Name:           fmt.Sprintf("%s, %s)", name, l),
 				Unit:           "RU/s x 100",
 				UnitMultiplier: schema.HourToMonthUnitMultiplier,
@@ -170,7 +181,13 @@ func provisionedCosmosCostComponents(model modelType, throughputs *decimal.Decim
 				PriceFilter: &schema.PriceFilter{
 					PurchaseOption: strPtr("Consumption"),
 				},
-			})
+			}
+
+			if commitment != nil && quantity != nil {
+				commitment.Apply(c, *quantity, decimal.NewFromInt(1))
+			}
+
+			costComponents = append(costComponents, c)
 		}
 	}
 
@@ -0,0 +1,31 @@
+package azure
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// DataSourceResolvers resolve the pricing hints of the Azure data sources.
+var DataSourceResolvers = map[string]schema.DataSourceResolver{
+	"azurerm_platform_image": resolvePlatformImageHints,
+}
+
+// resolvePlatformImageHints returns the operating system of the marketplace
+// image, which is Windows for the images published by Microsoft's Windows
+// publishers.
+func resolvePlatformImageHints(d *schema.ResourceData) map[string]string {
+	publisher := strings.ToLower(d.Get("publisher").String())
+	offer := strings.ToLower(d.Get("offer").String())
+
+	if publisher == "" && offer == "" {
+		return nil
+	}
+
+	os := "linux"
+	if strings.HasPrefix(publisher, "microsoftwindows") || strings.HasPrefix(offer, "windows") {
+		os = "windows"
+	}
+
+	return map[string]string{schema.OperatingSystemHint: os}
+}
@@ -78,6 +78,7 @@ func NewAzureRMKubernetesCluster(d *schema.ResourceData, u *schema.UsageData) *s
 			}
 			lbResource := schema.Resource{
 				Name:           "Load Balancer",
+				ResourceType:   "azurerm_lb",
 				CostComponents: []*schema.CostComponent{dataProcessedCostComponent(region, monthlyDataProcessedGb)},
 			}
 			subResources = append(subResources, &lbResource)
@@ -102,6 +103,7 @@ func NewAzureRMKubernetesCluster(d *schema.ResourceData, u *schema.UsageData) *s
 
 		dnsResource := schema.Resource{
 			Name:           "DNS",
+			ResourceType:   "azurerm_dns_zone",
 			CostComponents: []*schema.CostComponent{hostedPublicZoneCostComponent(region)},
 		}
 		subResources = append(subResources, &dnsResource)
@@ -45,10 +45,11 @@ func aksClusterNodePool(name, region string, n gjson.Result, nodeCount decimal.D
 	var subResources []*schema.Resource
 
 	mainResource := &schema.Resource{
-		Name: name,
+		Name:         name,
+		ResourceType: "azurerm_kubernetes_cluster_node_pool",
 	}
 	instanceType := n.Get("vm_size").String()
-	costComponents = append(costComponents, linuxVirtualMachineCostComponent(region, instanceType, nil))
+	costComponents = append(costComponents, linuxVirtualMachineCostComponent(region, instanceType, nil, nil))
 	mainResource.CostComponents = costComponents
 	schema.MultiplyQuantities(mainResource, nodeCount)
 
@@ -93,6 +94,7 @@ func aksOSDiskSubResource(region string, diskSize int) *schema.Resource {
 
 	return &schema.Resource{
 		Name:           "os_disk",
+		ResourceType:   "azurerm_managed_disk",
 		CostComponents: costComponent,
 	}
 }
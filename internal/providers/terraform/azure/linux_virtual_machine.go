@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/infracost/infracost/internal/resources/azure"
 	"github.com/infracost/infracost/internal/schema"
 
 	"github.com/shopspring/decimal"
@@ -15,7 +16,7 @@ func GetAzureRMLinuxVirtualMachineRegistryItem() *schema.RegistryItem {
 		RFunc: NewAzureRMLinuxVirtualMachine,
 		Notes: []string{
 			"Non-standard images such as RHEL are not supported.",
-			"Low priority, Spot and Reserved instances are not supported.",
+			"Low priority instances are not supported.",
 		},
 	}
 }
@@ -30,7 +31,9 @@ func NewAzureRMLinuxVirtualMachine(d *schema.ResourceData, u *schema.UsageData)
 		monthlyHours = u.GetFloat("monthly_hrs")
 	}
 
-	costComponents := []*schema.CostComponent{linuxVirtualMachineCostComponent(region, instanceType, monthlyHours)}
+	purchaseOption := lookupPurchaseOption(d, u, true)
+
+	costComponents := []*schema.CostComponent{linuxVirtualMachineCostComponent(region, instanceType, monthlyHours, purchaseOption)}
 
 	if d.Get("additional_capabilities.0.ultra_ssd_enabled").Bool() {
 		costComponents = append(costComponents, ultraSSDReservationCostComponent(region))
@@ -50,9 +53,12 @@ func NewAzureRMLinuxVirtualMachine(d *schema.ResourceData, u *schema.UsageData)
 	}
 }
 
-func linuxVirtualMachineCostComponent(region string, instanceType string, monthlyHours *float64) *schema.CostComponent {
+func linuxVirtualMachineCostComponent(region string, instanceType string, monthlyHours *float64, commitment *azure.PurchaseOption) *schema.CostComponent {
 	purchaseOption := "Consumption"
 	purchaseOptionLabel := "pay as you go"
+	if commitment != nil {
+		purchaseOptionLabel = commitment.Label
+	}
 
 	productNameRe := "/Virtual Machines .* Series$/"
 	if strings.HasPrefix(strings.ToLower(instanceType), "basic_") {
@@ -66,7 +72,7 @@ func linuxVirtualMachineCostComponent(region string, instanceType string, monthl
 		qty = decimal.NewFromFloat(*monthlyHours)
 	}
 
-	return &schema.CostComponent{
+	c := &schema.CostComponent{
 		Name:            fmt.Sprintf("Instance usage (%s, %s)", purchaseOptionLabel, instanceType),
 		Unit:            "hours",
 		UnitMultiplier:  decimal.NewFromInt(1),
@@ -88,4 +94,10 @@ func linuxVirtualMachineCostComponent(region string, instanceType string, monthl
 			Unit:           strPtr("1 Hour"),
 		},
 	}
+
+	if commitment != nil {
+		commitment.Apply(c, decimal.NewFromInt(1), schema.HourToMonthUnitMultiplier)
+	}
+
+	return c
 }
@@ -24,6 +24,8 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	getAutomationJobScheduleRegistryItem(),
 	GetAzureRMBastionHostRegistryItem(),
 	GetAzureRMCDNEndpointRegistryItem(),
+	getContainerAppRegistryItem(),
+	getContainerAppEnvironmentRegistryItem(),
 	getContainerRegistryRegistryItem(),
 	GetAzureRMCosmosdbCassandraKeyspaceRegistryItem(),
 	GetAzureRMCosmosdbCassandraTableRegistryItem(),
@@ -90,6 +92,7 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	GetAzureRMSearchServiceRegistryItem(),
 	GetAzureRMRedisCacheRegistryItem(),
 	getAzureRMStorageAccountRegistryItem(),
+	getStorageEncryptionScopeRegistryItem(),
 	getAzureRMSQLDatabaseRegistryItem(),
 	getAzureRMSQLManagedInstanceRegistryItem(),
 	GetAzureRMSynapseSparkPoolRegistryItem(),
@@ -102,6 +105,7 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	GetAzureRMVirtualNetworkGatewayRegistryItem(),
 	GetAzureRMWindowsVirtualMachineRegistryItem(),
 	GetAzureRMWindowsVirtualMachineScaleSetRegistryItem(),
+	getBatchPoolRegistryItem(),
 	getAzureRMVPNGatewayRegistryItem(),
 	getAzureRMVPNGatewayConnectionRegistryItem(),
 	getDataFactoryRegistryItem(),
@@ -214,6 +218,12 @@ var FreeResources = []string{
 	"azurerm_role_definition",
 	"azurerm_user_assigned_identity",
 
+	// Azure Batch
+	"azurerm_batch_account",
+	"azurerm_batch_application",
+	"azurerm_batch_certificate",
+	"azurerm_batch_job",
+
 	// Azure Blueprints
 	"azurerm_blueprint_assignment",
 
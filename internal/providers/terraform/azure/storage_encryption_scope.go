@@ -0,0 +1,24 @@
+package azure
+
+import (
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getStorageEncryptionScopeRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:      "azurerm_storage_encryption_scope",
+		CoreRFunc: newStorageEncryptionScope,
+		ReferenceAttributes: []string{
+			"storage_account_id",
+		},
+	}
+}
+
+func newStorageEncryptionScope(d *schema.ResourceData) schema.CoreResource {
+	region := lookupRegion(d, []string{"storage_account_id"})
+	return &azure.StorageEncryptionScope{
+		Address: d.Address,
+		Region:  region,
+	}
+}
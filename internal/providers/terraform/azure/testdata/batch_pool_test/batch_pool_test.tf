@@ -0,0 +1,94 @@
+provider "azurerm" {
+  skip_provider_registration = true
+  features {}
+}
+
+resource "azurerm_resource_group" "example" {
+  name     = "example-resources"
+  location = "eastus"
+}
+
+resource "azurerm_batch_account" "example" {
+  name                = "batchaccount"
+  resource_group_name = azurerm_resource_group.example.name
+  location            = azurerm_resource_group.example.location
+}
+
+resource "azurerm_batch_pool" "fixed" {
+  name                = "fixed"
+  resource_group_name = azurerm_resource_group.example.name
+  account_name        = azurerm_batch_account.example.name
+  vm_size             = "Standard_F2"
+  node_agent_sku_id   = "batch.node.ubuntu 20.04"
+
+  fixed_scale {
+    target_dedicated_nodes    = 2
+    target_low_priority_nodes = 3
+  }
+
+  storage_image_reference {
+    publisher = "canonical"
+    offer     = "0001-com-ubuntu-server-focal"
+    sku       = "20_04-lts"
+    version   = "latest"
+  }
+}
+
+resource "azurerm_batch_pool" "windows" {
+  name                = "windows"
+  resource_group_name = azurerm_resource_group.example.name
+  account_name        = azurerm_batch_account.example.name
+  vm_size             = "Standard_F2"
+  node_agent_sku_id   = "batch.node.windows amd64"
+
+  fixed_scale {
+    target_dedicated_nodes = 1
+  }
+
+  storage_image_reference {
+    publisher = "MicrosoftWindowsServer"
+    offer     = "WindowsServer"
+    sku       = "2022-datacenter"
+    version   = "latest"
+  }
+}
+
+resource "azurerm_batch_pool" "auto_scale" {
+  name                = "auto_scale"
+  resource_group_name = azurerm_resource_group.example.name
+  account_name        = azurerm_batch_account.example.name
+  vm_size             = "Standard_F2"
+  node_agent_sku_id   = "batch.node.ubuntu 20.04"
+
+  auto_scale {
+    evaluation_interval = "PT15M"
+    formula             = "$TargetDedicatedNodes = 1;"
+  }
+
+  storage_image_reference {
+    publisher = "canonical"
+    offer     = "0001-com-ubuntu-server-focal"
+    sku       = "20_04-lts"
+    version   = "latest"
+  }
+}
+
+resource "azurerm_batch_pool" "auto_scale_with_usage" {
+  name                = "auto_scale_with_usage"
+  resource_group_name = azurerm_resource_group.example.name
+  account_name        = azurerm_batch_account.example.name
+  vm_size             = "Standard_F2"
+  node_agent_sku_id   = "batch.node.ubuntu 20.04"
+
+  auto_scale {
+    evaluation_interval = "PT15M"
+    formula             = "$TargetDedicatedNodes = 1;"
+  }
+
+  storage_image_reference {
+    publisher = "canonical"
+    offer     = "0001-com-ubuntu-server-focal"
+    sku       = "20_04-lts"
+    version   = "latest"
+  }
+}
@@ -0,0 +1,107 @@
+provider "azurerm" {
+  skip_provider_registration = true
+  features {}
+}
+
+resource "azurerm_resource_group" "example" {
+  name     = "example-resources"
+  location = "eastus"
+}
+
+resource "azurerm_container_app_environment" "consumption" {
+  name                = "consumption-environment"
+  location            = azurerm_resource_group.example.location
+  resource_group_name = azurerm_resource_group.example.name
+}
+
+resource "azurerm_container_app_environment" "dedicated" {
+  name                = "dedicated-environment"
+  location            = azurerm_resource_group.example.location
+  resource_group_name = azurerm_resource_group.example.name
+
+  workload_profile {
+    name                  = "Consumption"
+    workload_profile_type = "Consumption"
+  }
+
+  workload_profile {
+    name                  = "general"
+    workload_profile_type = "D4"
+    minimum_count         = 2
+    maximum_count         = 4
+  }
+
+  workload_profile {
+    name                  = "memory"
+    workload_profile_type = "E8"
+    minimum_count         = 1
+    maximum_count         = 2
+  }
+}
+
+resource "azurerm_container_app" "with_usage" {
+  name                         = "with-usage"
+  container_app_environment_id = azurerm_container_app_environment.consumption.id
+  resource_group_name          = azurerm_resource_group.example.name
+  revision_mode                = "Single"
+
+  template {
+    container {
+      name   = "app"
+      image  = "mcr.microsoft.com/azuredocs/containerapps-helloworld:latest"
+      cpu    = 0.5
+      memory = "1Gi"
+    }
+  }
+}
+
+resource "azurerm_container_app" "min_replicas" {
+  name                         = "min-replicas"
+  container_app_environment_id = azurerm_container_app_environment.consumption.id
+  resource_group_name          = azurerm_resource_group.example.name
+  revision_mode                = "Single"
+
+  template {
+    min_replicas = 2
+
+    container {
+      name   = "app"
+      image  = "mcr.microsoft.com/azuredocs/containerapps-helloworld:latest"
+      cpu    = 0.25
+      memory = "0.5Gi"
+    }
+  }
+}
+
+resource "azurerm_container_app" "without_usage" {
+  name                         = "without-usage"
+  container_app_environment_id = azurerm_container_app_environment.consumption.id
+  resource_group_name          = azurerm_resource_group.example.name
+  revision_mode                = "Single"
+
+  template {
+    container {
+      name   = "app"
+      image  = "mcr.microsoft.com/azuredocs/containerapps-helloworld:latest"
+      cpu    = 0.5
+      memory = "1Gi"
+    }
+  }
+}
+
+resource "azurerm_container_app" "dedicated" {
+  name                         = "dedicated"
+  container_app_environment_id = azurerm_container_app_environment.dedicated.id
+  resource_group_name          = azurerm_resource_group.example.name
+  revision_mode                = "Single"
+  workload_profile_name        = "general"
+
+  template {
+    container {
+      name   = "app"
+      image  = "mcr.microsoft.com/azuredocs/containerapps-helloworld:latest"
+      cpu    = 2
+      memory = "4Gi"
+    }
+  }
+}
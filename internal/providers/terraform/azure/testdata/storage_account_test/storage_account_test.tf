@@ -62,6 +62,15 @@ resource "azurerm_storage_account" "bb_Standard_LRS_Hot" {
   account_replication_type = "LRS"
 }
 
+resource "azurerm_storage_account" "bb_Standard_LRS_Hot_reserved" {
+  name                     = "storageaccountname"
+  resource_group_name      = azurerm_resource_group.example.name
+  location                 = azurerm_resource_group.example.location
+  account_kind             = "BlockBlobStorage"
+  account_tier             = "Standard"
+  account_replication_type = "LRS"
+}
+
 resource "azurerm_storage_account" "bb_Standard_LRS_Cool" {
   name                     = "storageaccountname"
   resource_group_name      = azurerm_resource_group.example.name
@@ -0,0 +1,34 @@
+provider "azurerm" {
+  skip_provider_registration = true
+  features {}
+}
+
+resource "azurerm_resource_group" "example" {
+  name     = "example-resources"
+  location = "westus"
+}
+
+resource "azurerm_storage_account" "example" {
+  name                              = "storageaccountname"
+  resource_group_name               = azurerm_resource_group.example.name
+  location                          = azurerm_resource_group.example.location
+  account_kind                      = "BlockBlobStorage"
+  account_tier                      = "Premium"
+  account_replication_type          = "LRS"
+  infrastructure_encryption_enabled = true
+  queue_encryption_key_type         = "Account"
+  table_encryption_key_type         = "Account"
+}
+
+resource "azurerm_storage_encryption_scope" "microsoft_managed" {
+  name               = "microsoftmanaged"
+  storage_account_id = azurerm_storage_account.example.id
+  source             = "Microsoft.Storage"
+}
+
+resource "azurerm_storage_encryption_scope" "infrastructure_encryption" {
+  name                               = "infrastructureencryption"
+  storage_account_id                 = azurerm_storage_account.example.id
+  source                             = "Microsoft.Storage"
+  infrastructure_encryption_required = true
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/infracost/infracost/internal/resources/azure"
 	"github.com/infracost/infracost/internal/schema"
 )
 
@@ -130,3 +131,25 @@ func contains(arr []string, e string) bool {
 	}
 	return false
 }
+
+// lookupPurchaseOption returns the reservation, savings plan or spot capacity
+// that is set with the purchase_option usage key of a resource, or nil if the
+// resource is paid as you go. Virtual machines with a Spot priority use spot
+// capacity unless the usage key is set.
+func lookupPurchaseOption(d *schema.ResourceData, u *schema.UsageData, virtualMachine bool) *azure.PurchaseOption {
+	var value *string
+	if u != nil {
+		value = u.GetString("purchase_option")
+	}
+
+	if (value == nil || *value == "") && virtualMachine && strings.EqualFold(d.Get("priority").String(), "Spot") {
+		value = strPtr("spot")
+	}
+
+	p := azure.ParsePurchaseOption(d.Address, value, virtualMachine)
+	if p != nil && p.Spot && u != nil {
+		p.SpotDiscountRate = u.GetFloat("spot_discount_rate")
+	}
+
+	return p
+}
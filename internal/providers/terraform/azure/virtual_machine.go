@@ -31,17 +31,22 @@ func NewAzureRMVirtualMachine(d *schema.ResourceData, u *schema.UsageData) *sche
 	if strings.ToLower(d.Get("storage_os_disk.0.os_type").String()) == "windows" {
 		os = "Windows"
 	}
+	if d.PricingHints[schema.OperatingSystemHint] == "windows" {
+		os = "Windows"
+	}
 
 	var monthlyHours *float64 = nil
 	if u != nil {
 		monthlyHours = u.GetFloat("monthly_hrs")
 	}
 
+	purchaseOption := lookupPurchaseOption(d, u, true)
+
 	if strings.ToLower(os) == "windows" {
 		licenseType := d.Get("license_type").String()
-		costComponents = append(costComponents, windowsVirtualMachineCostComponent(region, instanceType, licenseType, monthlyHours))
+		costComponents = append(costComponents, windowsVirtualMachineCostComponent(region, instanceType, licenseType, monthlyHours, purchaseOption))
 	} else {
-		costComponents = append(costComponents, linuxVirtualMachineCostComponent(region, instanceType, monthlyHours))
+		costComponents = append(costComponents, linuxVirtualMachineCostComponent(region, instanceType, monthlyHours, purchaseOption))
 	}
 
 	// TODO: is this always assuming ultrassdreservation cost?
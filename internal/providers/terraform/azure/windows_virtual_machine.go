@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/infracost/infracost/internal/resources/azure"
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
 )
 
 func GetAzureRMWindowsVirtualMachineRegistryItem() *schema.RegistryItem {
@@ -13,7 +15,8 @@ func GetAzureRMWindowsVirtualMachineRegistryItem() *schema.RegistryItem {
 		Name:  "azurerm_windows_virtual_machine",
 		RFunc: NewAzureRMWindowsVirtualMachine,
 		Notes: []string{
-			"Low priority, Spot and Reserved instances are not supported.",
+			"Low priority instances are not supported.",
+			"Reserved instances and savings plans are only supported with Azure Hybrid Benefit.",
 		},
 	}
 }
@@ -29,7 +32,9 @@ func NewAzureRMWindowsVirtualMachine(d *schema.ResourceData, u *schema.UsageData
 		monthlyHours = u.GetFloat("monthly_hrs")
 	}
 
-	costComponents := []*schema.CostComponent{windowsVirtualMachineCostComponent(region, instanceType, licenseType, monthlyHours)}
+	purchaseOption := lookupPurchaseOption(d, u, true)
+
+	costComponents := []*schema.CostComponent{windowsVirtualMachineCostComponent(region, instanceType, licenseType, monthlyHours, purchaseOption)}
 
 	if d.Get("additional_capabilities.0.ultra_ssd_enabled").Bool() {
 		costComponents = append(costComponents, ultraSSDReservationCostComponent(region))
@@ -49,7 +54,7 @@ func NewAzureRMWindowsVirtualMachine(d *schema.ResourceData, u *schema.UsageData
 	}
 }
 
-func windowsVirtualMachineCostComponent(region string, instanceType string, licenseType string, monthlyHours *float64) *schema.CostComponent {
+func windowsVirtualMachineCostComponent(region string, instanceType string, licenseType string, monthlyHours *float64, commitment *azure.PurchaseOption) *schema.CostComponent {
 	purchaseOption := "Consumption"
 	purchaseOptionLabel := "pay as you go"
 
@@ -60,18 +65,38 @@ func windowsVirtualMachineCostComponent(region string, instanceType string, lice
 		instanceType = fmt.Sprintf("Standard_%s", instanceType)
 	}
 
+	hybridBenefit := strings.ToLower(licenseType) == "windows_client" || strings.ToLower(licenseType) == "windows_server"
+
 	// Handle Azure Hybrid Benefit
-	if strings.ToLower(licenseType) == "windows_client" || strings.ToLower(licenseType) == "windows_server" {
+	if hybridBenefit {
 		purchaseOption = "DevTestConsumption"
 		purchaseOptionLabel = "hybrid benefit"
 	}
 
+	// Reservations and savings plans only cover the compute of Windows virtual
+	// machines and not the Windows license, so they're priced like Linux virtual
+	// machines when the license is brought with Azure Hybrid Benefit. Spot
+	// capacity includes the license unless it's brought with Azure Hybrid Benefit.
+	if commitment != nil {
+		switch {
+		case hybridBenefit:
+			purchaseOption = "Consumption"
+			purchaseOptionLabel = fmt.Sprintf("%s, hybrid benefit", commitment.Label)
+			productNameRe = strings.Replace(productNameRe, " Windows$/", "$/", 1)
+		case commitment.Spot:
+			purchaseOptionLabel = commitment.Label
+		default:
+			log.Warnf("Reserved instances and savings plans are only supported for Windows virtual machines with Azure Hybrid Benefit, using pay as you go prices for %s", instanceType)
+			commitment = nil
+		}
+	}
+
 	qty := decimal.NewFromFloat(730)
 	if monthlyHours != nil {
 		qty = decimal.NewFromFloat(*monthlyHours)
 	}
 
-	return &schema.CostComponent{
+	c := &schema.CostComponent{
 		Name:            fmt.Sprintf("Instance usage (%s, %s)", purchaseOptionLabel, instanceType),
 		Unit:            "hours",
 		UnitMultiplier:  decimal.NewFromInt(1),
@@ -92,4 +117,10 @@ func windowsVirtualMachineCostComponent(region string, instanceType string, lice
 			Unit:           strPtr("1 Hour"),
 		},
 	}
+
+	if commitment != nil {
+		commitment.Apply(c, decimal.NewFromInt(1), schema.HourToMonthUnitMultiplier)
+	}
+
+	return c
 }
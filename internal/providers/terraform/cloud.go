@@ -1,9 +1,11 @@
 package terraform
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -11,9 +13,33 @@ import (
 	"github.com/infracost/infracost/internal/credentials"
 )
 
-func cloudAPI(host string, path string, token string) ([]byte, error) {
-	client := &http.Client{}
+const defaultTerraformCloudHost = "app.terraform.io"
+
+// cloudHTTPClient is the client used to call the Terraform Cloud API, so it can
+// be replaced in tests.
+var cloudHTTPClient = &http.Client{}
 
+// plannedRunStatuses are the statuses of the Terraform Cloud runs that have a
+// finished plan, whether or not the plan has been applied.
+var plannedRunStatuses = map[string]bool{
+	"planned":              true,
+	"planned_and_finished": true,
+	"planned_and_saved":    true,
+	"cost_estimating":      true,
+	"cost_estimated":       true,
+	"policy_checking":      true,
+	"policy_checked":       true,
+	"policy_override":      true,
+	"policy_soft_failed":   true,
+	"post_plan_running":    true,
+	"post_plan_completed":  true,
+	"confirmed":            true,
+	"apply_queued":         true,
+	"applying":             true,
+	"applied":              true,
+}
+
+func cloudAPI(host string, path string, token string) ([]byte, error) {
 	url := fmt.Sprintf("https://%s%s", host, path)
 	log.Debugf("Calling Terraform Cloud API: %s", url)
 	req, err := http.NewRequest("GET", url, nil)
@@ -22,7 +48,7 @@ func cloudAPI(host string, path string, token string) ([]byte, error) {
 	}
 	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	resp, err := client.Do(req)
+	resp, err := cloudHTTPClient.Do(req)
 	if err != nil {
 		return []byte{}, err
 	}
@@ -36,3 +62,74 @@ func cloudAPI(host string, path string, token string) ([]byte, error) {
 
 	return io.ReadAll(resp.Body)
 }
+
+// fetchRunPlanJSON returns the plan JSON of the Terraform Cloud run.
+func fetchRunPlanJSON(host string, token string, runID string) ([]byte, error) {
+	body, err := cloudAPI(host, fmt.Sprintf("/api/v2/runs/%s/plan", url.PathEscape(runID)), token)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	var parsedResp struct {
+		Data struct {
+			Links map[string]string
+		}
+	}
+	if err := json.Unmarshal(body, &parsedResp); err != nil {
+		return []byte{}, err
+	}
+
+	jsonPath, ok := parsedResp.Data.Links["json-output"]
+	if !ok || jsonPath == "" {
+		return []byte{}, errors.New("Could not parse path to plan JSON from remote")
+	}
+	return cloudAPI(host, jsonPath, token)
+}
+
+// latestPlannedRun returns the ID of the latest run of the Terraform Cloud
+// workspace that has a finished plan. Runs that errored, were canceled or are
+// still planning are skipped.
+func latestPlannedRun(host string, token string, organization string, workspace string) (string, error) {
+	body, err := cloudAPI(host, fmt.Sprintf("/api/v2/organizations/%s/workspaces/%s", url.PathEscape(organization), url.PathEscape(workspace)), token)
+	if err != nil {
+		return "", err
+	}
+
+	var workspaceResp struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &workspaceResp); err != nil {
+		return "", err
+	}
+
+	if workspaceResp.Data.ID == "" {
+		return "", errors.Errorf("Could not find Terraform Cloud workspace %s/%s", organization, workspace)
+	}
+
+	body, err = cloudAPI(host, fmt.Sprintf("/api/v2/workspaces/%s/runs?page%%5Bsize%%5D=20", workspaceResp.Data.ID), token)
+	if err != nil {
+		return "", err
+	}
+
+	var runsResp struct {
+		Data []struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				Status string `json:"status"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &runsResp); err != nil {
+		return "", err
+	}
+
+	for _, run := range runsResp.Data {
+		if plannedRunStatuses[run.Attributes.Status] {
+			return run.ID, nil
+		}
+	}
+
+	return "", errors.Errorf("No run with a finished plan found for Terraform Cloud workspace %s/%s", organization, workspace)
+}
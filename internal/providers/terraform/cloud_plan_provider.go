@@ -0,0 +1,132 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/credentials"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+)
+
+// CloudPlanProvider loads the plan JSON of a Terraform Cloud/Enterprise run,
+// either a given run or the latest run of a workspace that has a finished plan,
+// so workspaces that run remotely don't need to be planned locally.
+type CloudPlanProvider struct {
+	ctx                  *config.ProjectContext
+	Host                 string
+	Token                string
+	Workspace            string
+	RunID                string
+	includePastResources bool
+}
+
+func NewCloudPlanProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	host := ctx.ProjectConfig.TerraformCloudHost
+	if host == "" {
+		host = defaultTerraformCloudHost
+	}
+
+	return &CloudPlanProvider{
+		ctx:                  ctx,
+		Host:                 host,
+		Token:                ctx.ProjectConfig.TerraformCloudToken,
+		Workspace:            ctx.ProjectConfig.TerraformCloudWorkspace,
+		RunID:                ctx.ProjectConfig.TerraformCloudRun,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *CloudPlanProvider) Type() string {
+	return "terraform_cloud_plan"
+}
+
+func (p *CloudPlanProvider) DisplayType() string {
+	return "Terraform Cloud plan"
+}
+
+func (p *CloudPlanProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+
+	if _, workspace, ok := strings.Cut(p.Workspace, "/"); ok {
+		metadata.TerraformWorkspace = workspace
+	}
+}
+
+func (p *CloudPlanProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, error) {
+	spinner := ui.NewSpinner(fmt.Sprintf("Fetching plan JSON from %s", p.Host), ui.SpinnerOptions{
+		EnableLogging: p.ctx.RunContext.Config.IsLogging(),
+		NoColor:       p.ctx.RunContext.Config.NoColor,
+		Indent:        "  ",
+	})
+	defer spinner.Fail()
+
+	j, err := p.fetchPlanJSON()
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+
+	spinner.Success()
+
+	project, err := NewPlanJSONProvider(p.ctx, p.includePastResources).LoadResourcesFromSrc(usage, j, nil)
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+
+	project.Metadata.Type = p.Type()
+	p.AddMetadata(project.Metadata)
+
+	if p.ctx.ProjectConfig.Name == "" {
+		project.Name = p.projectName()
+	}
+
+	return []*schema.Project{project}, nil
+}
+
+func (p *CloudPlanProvider) fetchPlanJSON() ([]byte, error) {
+	token := p.Token
+	if token == "" {
+		token = credentials.FindTerraformCloudToken(p.Host)
+	}
+	if token == "" {
+		return nil, credentials.ErrMissingCloudToken
+	}
+
+	runID := p.RunID
+	if runID == "" {
+		organization, workspace, ok := strings.Cut(p.Workspace, "/")
+		if !ok || organization == "" || workspace == "" {
+			return nil, fmt.Errorf("Invalid Terraform Cloud workspace %q, it must be in the format <organization>/<workspace>", p.Workspace)
+		}
+
+		var err error
+		runID, err = latestPlannedRun(p.Host, token, organization, workspace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	j, err := fetchRunPlanJSON(p.Host, token, runID)
+	if err != nil {
+		return nil, fmt.Errorf("Error fetching plan JSON of Terraform Cloud run %s: %w", runID, err)
+	}
+
+	return j, nil
+}
+
+// projectName returns the name of the project, which is the workspace if it
+// is set, otherwise the run ID.
+func (p *CloudPlanProvider) projectName() string {
+	if p.Workspace != "" {
+		return p.Workspace
+	}
+
+	return p.RunID
+}
+
+// IsCloudPlanProject returns true if the project is configured to load its plan
+// from a Terraform Cloud workspace or run rather than from its path.
+func IsCloudPlanProject(projectCfg *config.Project) bool {
+	return projectCfg.TerraformCloudWorkspace != "" || projectCfg.TerraformCloudRun != ""
+}
@@ -0,0 +1,154 @@
+package terraform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/credentials"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const cloudPlanJSON = `{
+  "format_version": "1.1",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_instance.web",
+          "mode": "managed",
+          "type": "aws_instance",
+          "name": "web",
+          "provider_name": "registry.terraform.io/hashicorp/aws",
+          "values": {"instance_type": "t3.micro"}
+        }
+      ]
+    }
+  },
+  "configuration": {
+    "provider_config": {
+      "aws": {"name": "aws", "expressions": {"region": {"constant_value": "eu-west-2"}}}
+    },
+    "root_module": {
+      "resources": [
+        {"address": "aws_instance.web", "mode": "managed", "type": "aws_instance", "name": "web", "provider_config_key": "aws"}
+      ]
+    }
+  }
+}`
+
+// newCloudServer returns a fake Terraform Cloud API with the workspace acme/prod,
+// whose latest run errored and whose previous run was planned.
+func newCloudServer(t *testing.T) string {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/organizations/acme/workspaces/prod", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"id": "ws-123", "type": "workspaces"}}`))
+	})
+	mux.HandleFunc("/api/v2/workspaces/ws-123/runs", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": [
+			{"id": "run-errored", "attributes": {"status": "errored"}},
+			{"id": "run-planned", "attributes": {"status": "planned_and_finished"}},
+			{"id": "run-applied", "attributes": {"status": "applied"}}
+		]}`))
+	})
+	mux.HandleFunc("/api/v2/runs/run-planned/plan", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"id": "plan-1", "links": {"json-output": "/api/v2/plans/plan-1/json-output"}}}`))
+	})
+	mux.HandleFunc("/api/v2/plans/plan-1/json-output", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(cloudPlanJSON))
+	})
+
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+
+	orig := cloudHTTPClient
+	cloudHTTPClient = server.Client()
+	t.Cleanup(func() { cloudHTTPClient = orig })
+
+	return strings.TrimPrefix(server.URL, "https://")
+}
+
+func TestLatestPlannedRun(t *testing.T) {
+	host := newCloudServer(t)
+
+	runID, err := latestPlannedRun(host, "secret", "acme", "prod")
+	require.NoError(t, err)
+	assert.Equal(t, "run-planned", runID)
+
+	_, err = latestPlannedRun(host, "secret", "acme", "missing")
+	assert.Error(t, err)
+}
+
+func TestCloudPlanProviderLoadResources(t *testing.T) {
+	host := newCloudServer(t)
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		TerraformCloudHost:      host,
+		TerraformCloudToken:     "secret",
+		TerraformCloudWorkspace: "acme/prod",
+	}, log.Fields{})
+
+	projects, err := NewCloudPlanProvider(ctx, false).LoadResources(schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	project := projects[0]
+	assert.Equal(t, "acme/prod", project.Name)
+	assert.Equal(t, "terraform_cloud_plan", project.Metadata.Type)
+	assert.Equal(t, "prod", project.Metadata.TerraformWorkspace)
+
+	require.Len(t, project.PartialResources, 1)
+	r := project.PartialResources[0].ResourceData
+	assert.Equal(t, "aws_instance.web", r.Address)
+	assert.Equal(t, "eu-west-2", r.Get("region").String())
+}
+
+func TestCloudPlanProviderErrors(t *testing.T) {
+	host := newCloudServer(t)
+
+	tests := []struct {
+		name    string
+		project *config.Project
+		err     error
+	}{
+		{
+			name:    "invalid token",
+			project: &config.Project{TerraformCloudHost: host, TerraformCloudToken: "wrong", TerraformCloudRun: "run-planned"},
+			err:     credentials.ErrInvalidCloudToken,
+		},
+		{
+			name:    "invalid workspace",
+			project: &config.Project{TerraformCloudHost: host, TerraformCloudToken: "secret", TerraformCloudWorkspace: "prod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := config.NewProjectContext(config.EmptyRunContext(), tt.project, log.Fields{})
+
+			_, err := NewCloudPlanProvider(ctx, false).LoadResources(schema.UsageMap{})
+			require.Error(t, err)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestIsCloudPlanProject(t *testing.T) {
+	assert.True(t, IsCloudPlanProject(&config.Project{TerraformCloudWorkspace: "acme/prod"}))
+	assert.True(t, IsCloudPlanProject(&config.Project{TerraformCloudRun: "run-123"}))
+	assert.False(t, IsCloudPlanProject(&config.Project{Path: "plan.json"}))
+}
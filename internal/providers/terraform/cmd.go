@@ -175,7 +175,7 @@ func CreateConfigFile(dir string, terraformCloudHost string, terraformCloudToken
 
 	host := terraformCloudHost
 	if host == "" {
-		host = "app.terraform.io"
+		host = defaultTerraformCloudHost
 	}
 
 	contents := fmt.Sprintf(`credentials "%s" {
@@ -0,0 +1,93 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+var awsAccountIDRegex = regexp.MustCompile(`^\d{12}$`)
+
+// setCostScope adds the cost scope of the resource to its metadata if it can
+// be found.
+func setCostScope(d *schema.ResourceData, providerConf gjson.Result, vars gjson.Result, resConf gjson.Result) {
+	scope := resourceCostScope(d, providerConf, vars, resConf)
+	if scope == "" {
+		return
+	}
+
+	b, _ := json.Marshal(scope)
+
+	if d.Metadata == nil {
+		d.Metadata = make(map[string]gjson.Result)
+	}
+	d.Metadata[schema.CostScopeMetadataKey] = gjson.ParseBytes(b)
+}
+
+// resourceCostScope returns the AWS account, Azure resource group or GCP
+// project of the resource. AWS accounts are found from the ARN of the resource
+// or from the allowed accounts or assumed role of its provider. Resources that
+// use an aliased AWS provider without an account are scoped by the alias.
+func resourceCostScope(d *schema.ResourceData, providerConf gjson.Result, vars gjson.Result, resConf gjson.Result) string {
+	providerPrefix := getProviderPrefix(d.Type)
+
+	providerKey := parseProviderKey(resConf)
+	if providerKey == "" {
+		providerKey = providerPrefix
+	}
+
+	switch providerPrefix {
+	case "aws":
+		if account := awsAccountFromARN(d.Get("arn").String()); account != "" {
+			return fmt.Sprintf("AWS account %s", account)
+		}
+
+		account := providerConf.Get(fmt.Sprintf("%s.expressions.allowed_account_ids.constant_value.0", gjsonEscape(providerKey))).String()
+		if account == "" {
+			account = awsAccountFromARN(providerConfValue(providerConf, vars, providerKey, "assume_role.0.role_arn"))
+		}
+
+		if awsAccountIDRegex.MatchString(account) {
+			return fmt.Sprintf("AWS account %s", account)
+		}
+
+		if strings.Contains(providerKey, ".") {
+			return fmt.Sprintf("AWS provider %s", providerKey)
+		}
+	case "azurerm":
+		if rg := d.Get("resource_group_name").String(); rg != "" && !strings.Contains(rg, "mock") {
+			return fmt.Sprintf("Azure resource group %s", rg)
+		}
+	case "google":
+		project := d.Get("project").String()
+		if project == "" {
+			project = providerConfValue(providerConf, vars, providerKey, "project")
+		}
+
+		if project != "" && !strings.Contains(project, "mock") {
+			return fmt.Sprintf("GCP project %s", project)
+		}
+	}
+
+	return ""
+}
+
+// awsAccountFromARN returns the account ID in the ARN, or an empty string if
+// it isn't an ARN or doesn't have an account ID.
+func awsAccountFromARN(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 6 || parts[0] != "arn" {
+		return ""
+	}
+
+	if !awsAccountIDRegex.MatchString(parts[4]) {
+		return ""
+	}
+
+	return parts[4]
+}
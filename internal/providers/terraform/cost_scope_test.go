@@ -0,0 +1,70 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestResourceCostScope(t *testing.T) {
+	providerConf := gjson.Parse(`{
+		"aws": {
+			"name": "aws",
+			"expressions": {
+				"allowed_account_ids": {"constant_value": ["111111111111"]}
+			}
+		},
+		"aws.prod": {
+			"name": "aws",
+			"alias": "prod",
+			"expressions": {
+				"assume_role": [{"role_arn": {"references": ["var.prod_role"]}}]
+			}
+		},
+		"aws.dev": {
+			"name": "aws",
+			"alias": "dev"
+		},
+		"google": {
+			"name": "google",
+			"expressions": {
+				"project": {"constant_value": "my-project"}
+			}
+		}
+	}`)
+
+	vars := gjson.Parse(`{"prod_role": {"value": "arn:aws:iam::222222222222:role/deploy"}}`)
+
+	tests := []struct {
+		name     string
+		resType  string
+		provider string
+		values   string
+		expected string
+	}{
+		{"aws allowed account", "aws_instance", "", `{}`, "AWS account 111111111111"},
+		{"aws resource arn", "aws_instance", "", `{"arn": "arn:aws:ec2:us-east-1:333333333333:instance/i-1"}`, "AWS account 333333333333"},
+		{"aws assumed role", "aws_instance", "aws.prod", `{}`, "AWS account 222222222222"},
+		{"aws alias", "aws_instance", "aws.dev", `{}`, "AWS provider aws.dev"},
+		{"azure resource group", "azurerm_linux_virtual_machine", "", `{"resource_group_name": "app-rg"}`, "Azure resource group app-rg"},
+		{"azure missing resource group", "azurerm_linux_virtual_machine", "", `{}`, ""},
+		{"google provider project", "google_compute_instance", "", `{}`, "GCP project my-project"},
+		{"google resource project", "google_compute_instance", "", `{"project": "other-project"}`, "GCP project other-project"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.NewResourceData(tt.resType, "", tt.resType+".test", nil, gjson.Parse(tt.values))
+
+			resConf := gjson.Result{}
+			if tt.provider != "" {
+				resConf = gjson.Parse(`{"provider_config_key": "` + tt.provider + `"}`)
+			}
+
+			assert.Equal(t, tt.expected, resourceCostScope(d, providerConf, vars, resConf))
+		})
+	}
+}
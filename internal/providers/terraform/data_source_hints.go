@@ -0,0 +1,119 @@
+package terraform
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// dataSourceRefRegex matches the address of the data source in a reference to
+// it or one of its attributes, e.g. data.aws_ami.windows in
+// data.aws_ami.windows.id.
+var dataSourceRefRegex = regexp.MustCompile(`^data\.[^.\[]+\.[^.\[]+(\[[^\]]+\])?`)
+
+// setPricingHints sets the PricingHints of the resources from the data sources
+// that their config references and that have a DataSourceResolver, e.g. so an
+// aws_instance created from a Windows aws_ami is priced as a Windows instance.
+// The data sources are read from the planned values and the prior state, as
+// Terraform only adds the data sources that are read during apply to the
+// planned values.
+func setPricingHints(resData map[string]*schema.ResourceData, parsed gjson.Result, conf gjson.Result) {
+	dataSources := make(map[string]gjson.Result)
+	collectDataSources(parsed.Get("prior_state.values.root_module"), dataSources)
+	collectDataSources(parsed.Get("planned_values.root_module"), dataSources)
+
+	if len(dataSources) == 0 {
+		return
+	}
+
+	for _, d := range resData {
+		if strings.HasPrefix(addressResourcePart(d.Address), "data.") {
+			continue
+		}
+
+		var refs []string
+		collectReferences(getConfJSON(conf, d.Address).Get("expressions"), &refs)
+		sort.Strings(refs)
+
+		m := addressModulePart(d.Address)
+		for _, ref := range refs {
+			addr := dataSourceRefRegex.FindString(ref)
+			if addr == "" {
+				continue
+			}
+
+			v, ok := dataSources[m+addr]
+			if !ok {
+				v, ok = dataSources[m+addr+"[0]"]
+			}
+			if !ok {
+				continue
+			}
+
+			resolver := GetDataSourceResolver(v.Get("type").String())
+			if resolver == nil {
+				continue
+			}
+
+			data := schema.NewResourceData(v.Get("type").String(), v.Get("provider_name").String(), v.Get("address").String(), nil, v.Get("values"))
+			for k, hint := range resolver(data) {
+				if d.PricingHints == nil {
+					d.PricingHints = make(map[string]string)
+				}
+
+				// The first data source that gives a hint wins, so hints don't
+				// depend on the order that the references are in.
+				if _, exists := d.PricingHints[k]; !exists {
+					d.PricingHints[k] = hint
+				}
+			}
+		}
+	}
+}
+
+// collectDataSources adds the data sources of the module and its child modules
+// with a DataSourceResolver to dataSources, keyed by address.
+func collectDataSources(module gjson.Result, dataSources map[string]gjson.Result) {
+	for _, r := range module.Get("resources").Array() {
+		if r.Get("mode").String() != "data" || GetDataSourceResolver(r.Get("type").String()) == nil {
+			continue
+		}
+
+		dataSources[r.Get("address").String()] = r
+	}
+
+	for _, m := range module.Get("child_modules").Array() {
+		collectDataSources(m, dataSources)
+	}
+}
+
+// collectReferences adds all the references within the expressions of a
+// resource config to refs.
+func collectReferences(v gjson.Result, refs *[]string) {
+	if v.IsArray() {
+		for _, child := range v.Array() {
+			collectReferences(child, refs)
+		}
+		return
+	}
+
+	if !v.IsObject() {
+		return
+	}
+
+	v.ForEach(func(k, child gjson.Result) bool {
+		if k.String() == "references" && child.IsArray() {
+			for _, ref := range child.Array() {
+				*refs = append(*refs, ref.String())
+			}
+			return true
+		}
+
+		collectReferences(child, refs)
+		return true
+	})
+}
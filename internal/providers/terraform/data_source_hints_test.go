@@ -0,0 +1,166 @@
+package terraform
+
+import (
+	"io"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/hcl/modules"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/sync"
+)
+
+func pricingHints(partials []*schema.PartialResource) map[string]map[string]string {
+	hints := make(map[string]map[string]string)
+	for _, p := range partials {
+		hints[p.ResourceData.Address] = p.ResourceData.PricingHints
+	}
+
+	return hints
+}
+
+func TestSetPricingHintsHCL(t *testing.T) {
+	testPath := "testdata/data_source_hints"
+
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+	entry := log.NewEntry(logger)
+
+	parsers, err := hcl.LoadParsers(testPath, modules.NewModuleLoader(testPath, nil, entry, &sync.KeyMutex{}), nil, entry)
+	require.NoError(t, err)
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{})
+	p := HCLProvider{
+		parsers: parsers,
+		logger:  entry,
+		ctx:     ctx,
+	}
+	jsons := p.LoadPlanJSONs()
+	require.Len(t, jsons, 1)
+
+	_, partials, err := NewParser(ctx, false).parseJSON(jsons[0].JSON, schema.NewUsageMapFromInterface(nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]map[string]string{
+		"aws_instance.windows":        {schema.OperatingSystemHint: "windows"},
+		"aws_instance.ubuntu":         {schema.OperatingSystemHint: "linux"},
+		"aws_instance.fixed":          nil,
+		"module.app.aws_instance.app": {schema.OperatingSystemHint: "rhel"},
+	}, pricingHints(partials))
+
+	for _, partial := range partials {
+		if partial.ResourceData.Address != "aws_instance.windows" {
+			continue
+		}
+
+		r := schema.BuildResource(partial, nil)
+		assert.Equal(t, "Instance usage (Windows, on-demand, t3.medium)", r.CostComponents[0].Name)
+	}
+}
+
+func TestSetPricingHintsPlanJSON(t *testing.T) {
+	plan := `{
+		"format_version": "1.1",
+		"terraform_version": "1.5.0",
+		"prior_state": {
+			"values": {
+				"root_module": {
+					"resources": [
+						{
+							"address": "data.azurerm_platform_image.windows",
+							"mode": "data",
+							"type": "azurerm_platform_image",
+							"name": "windows",
+							"provider_name": "registry.terraform.io/hashicorp/azurerm",
+							"values": {"publisher": "MicrosoftWindowsServer", "offer": "WindowsServer", "sku": "2019-Datacenter"}
+						},
+						{
+							"address": "data.aws_ami.sles",
+							"mode": "data",
+							"type": "aws_ami",
+							"name": "sles",
+							"provider_name": "registry.terraform.io/hashicorp/aws",
+							"values": {"id": "ami-123", "name": "suse-sles-15-sp5-v20230620-hvm-ssd-x86_64", "platform_details": "SUSE Linux"}
+						}
+					]
+				}
+			}
+		},
+		"planned_values": {
+			"root_module": {
+				"resources": [
+					{
+						"address": "azurerm_virtual_machine.vm",
+						"mode": "managed",
+						"type": "azurerm_virtual_machine",
+						"name": "vm",
+						"provider_name": "registry.terraform.io/hashicorp/azurerm",
+						"values": {"location": "eastus", "vm_size": "Standard_D2s_v3", "storage_image_reference": [{"id": "/subscriptions/123/windows"}]}
+					},
+					{
+						"address": "aws_instance.sles",
+						"mode": "managed",
+						"type": "aws_instance",
+						"name": "sles",
+						"provider_name": "registry.terraform.io/hashicorp/aws",
+						"values": {"ami": "ami-123", "instance_type": "t3.medium"}
+					}
+				]
+			}
+		},
+		"configuration": {
+			"provider_config": {
+				"aws": {"name": "aws", "expressions": {"region": {"constant_value": "us-east-1"}}}
+			},
+			"root_module": {
+				"resources": [
+					{
+						"address": "azurerm_virtual_machine.vm",
+						"type": "azurerm_virtual_machine",
+						"name": "vm",
+						"expressions": {
+							"storage_image_reference": [
+								{"id": {"references": ["data.azurerm_platform_image.windows.id", "data.azurerm_platform_image.windows"]}}
+							]
+						}
+					},
+					{
+						"address": "aws_instance.sles",
+						"type": "aws_instance",
+						"name": "sles",
+						"expressions": {
+							"ami": {"references": ["data.aws_ami.sles.id", "data.aws_ami.sles"]}
+						}
+					}
+				]
+			}
+		}
+	}`
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{})
+	_, partials, err := NewParser(ctx, false).parseJSON([]byte(plan), schema.NewUsageMapFromInterface(nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]map[string]string{
+		"azurerm_virtual_machine.vm": {schema.OperatingSystemHint: "windows"},
+		"aws_instance.sles":          {schema.OperatingSystemHint: "suse"},
+	}, pricingHints(partials))
+}
+
+func TestRegisterDataSourceResolver(t *testing.T) {
+	assert.Nil(t, GetDataSourceResolver("test_image"))
+
+	RegisterDataSourceResolver("test_image", func(d *schema.ResourceData) map[string]string {
+		return map[string]string{schema.OperatingSystemHint: d.Get("os").String()}
+	})
+
+	resolver := GetDataSourceResolver("test_image")
+	require.NotNil(t, resolver)
+	assert.Equal(t, map[string]string{schema.OperatingSystemHint: "windows"}, resolver(schema.NewResourceData("test_image", "", "data.test_image.x", nil, gjson.Parse(`{"os": "windows"}`))))
+}
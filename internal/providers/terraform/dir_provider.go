@@ -2,7 +2,6 @@ package terraform
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
@@ -52,7 +51,7 @@ type RunShowOptions struct {
 func NewDirProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
 	terraformBinary := ctx.ProjectConfig.TerraformBinary
 	if terraformBinary == "" {
-		terraformBinary = defaultTerraformBinary
+		terraformBinary = defaultBinary(ctx.ProjectConfig.Path)
 	}
 
 	return &DirProvider{
@@ -83,6 +82,16 @@ func (p *DirProvider) DisplayType() string {
 	return "Terraform CLI"
 }
 
+// cliName returns the name of the CLI that runs the Terraform commands, for
+// showing in the progress messages.
+func (p *DirProvider) cliName() string {
+	if isOpenTofuBinary(p.TerraformBinary) {
+		return openTofuBinary
+	}
+
+	return "terraform"
+}
+
 func (p *DirProvider) checks() error {
 	binary := p.TerraformBinary
 
@@ -91,7 +100,7 @@ func (p *DirProvider) checks() error {
 	_, err := exec.LookPath(binary)
 	if err != nil {
 		msg := fmt.Sprintf("Terraform binary '%s' could not be found. You have two options:\n", binary)
-		msg += "1. Set a custom Terraform binary using the environment variable INFRACOST_TERRAFORM_BINARY.\n\n"
+		msg += "1. Set a custom Terraform or OpenTofu binary using the environment variable INFRACOST_TERRAFORM_BINARY.\n\n"
 		msg += fmt.Sprintf("2. Set --path to a Terraform plan JSON file. See %s for how to generate this.", ui.LinkString("https://infracost.io/troubleshoot"))
 		return clierror.NewCLIError(errors.Errorf(msg), "Terraform binary could not be found")
 	}
@@ -107,6 +116,9 @@ func (p *DirProvider) checks() error {
 
 	p.ctx.SetContextValue("terraformFullVersion", fullVersion)
 	p.ctx.SetContextValue("terraformVersion", version)
+	if isOpenTofuVersion(fullVersion) {
+		p.ctx.SetContextValue("isOpenTofu", true)
+	}
 
 	return checkTerraformVersion(version, fullVersion)
 }
@@ -233,7 +245,7 @@ func (p *DirProvider) generatePlanJSON() ([]byte, error) {
 		defer os.Remove(opts.TerraformConfigFile)
 	}
 
-	spinner := ui.NewSpinner("Running terraform plan", p.spinnerOpts)
+	spinner := ui.NewSpinner(fmt.Sprintf("Running %s plan", p.cliName()), p.spinnerOpts)
 	defer spinner.Fail()
 
 	planFile, planJSON, err := p.runPlan(opts, spinner, true)
@@ -247,7 +259,7 @@ func (p *DirProvider) generatePlanJSON() ([]byte, error) {
 		return planJSON, nil
 	}
 
-	spinner = ui.NewSpinner("Running terraform show", p.spinnerOpts)
+	spinner = ui.NewSpinner(fmt.Sprintf("Running %s show", p.cliName()), p.spinnerOpts)
 	j, err := p.runShow(opts, spinner, planFile, false)
 	if err == nil {
 		p.cachedPlanJSON = j
@@ -277,7 +289,7 @@ func (p *DirProvider) generateStateJSON() ([]byte, error) {
 		defer os.Remove(opts.TerraformConfigFile)
 	}
 
-	spinner := ui.NewSpinner("Running terraform show", p.spinnerOpts)
+	spinner := ui.NewSpinner(fmt.Sprintf("Running %s show", p.cliName()), p.spinnerOpts)
 	defer spinner.Fail()
 
 	j, err := p.runShow(opts, spinner, "", true)
@@ -339,7 +351,7 @@ func (p *DirProvider) runPlan(opts *CmdOptions, spinner *ui.Spinner, initOnFail
 			planJSON, err = p.runRemotePlan(opts, args)
 		} else if initOnFail && isTerraformInitErr(extractedErr) {
 			spinner.Stop()
-			err = p.runInit(opts, ui.NewSpinner("Running terraform init", p.spinnerOpts))
+			err = p.runInit(opts, ui.NewSpinner(fmt.Sprintf("Running %s init", p.cliName()), p.spinnerOpts))
 			if err != nil {
 				return "", planJSON, err
 			}
@@ -432,25 +444,7 @@ func (p *DirProvider) runRemotePlan(opts *CmdOptions, args []string) ([]byte, er
 		return []byte{}, credentials.ErrMissingCloudToken
 	}
 
-	body, err := cloudAPI(host, fmt.Sprintf("/api/v2/runs/%s/plan", runID), token)
-	if err != nil {
-		return []byte{}, err
-	}
-
-	var parsedResp struct {
-		Data struct {
-			Links map[string]string
-		}
-	}
-	if json.Unmarshal(body, &parsedResp) != nil {
-		return []byte{}, err
-	}
-
-	jsonPath, ok := parsedResp.Data.Links["json-output"]
-	if !ok || jsonPath == "" {
-		return []byte{}, errors.New("Could not parse path to plan JSON from remote")
-	}
-	return cloudAPI(host, jsonPath, token)
+	return fetchRunPlanJSON(host, token, runID)
 }
 
 func (p *DirProvider) runShow(opts *CmdOptions, spinner *ui.Spinner, planFile string, initOnFail bool) ([]byte, error) {
@@ -469,7 +463,7 @@ func (p *DirProvider) runShow(opts *CmdOptions, spinner *ui.Spinner, planFile st
 			log.Info("Terraform expected Remote Execution Mode")
 		} else if initOnFail && isTerraformInitErr(extractedErr) {
 			spinner.Stop()
-			err = p.runInit(opts, ui.NewSpinner("Running terraform init", p.spinnerOpts))
+			err = p.runInit(opts, ui.NewSpinner(fmt.Sprintf("Running %s init", p.cliName()), p.spinnerOpts))
 			if err != nil {
 				return out, err
 			}
@@ -536,6 +530,8 @@ func (p *DirProvider) buildTerraformErr(err error, isInit bool) error {
 	binName := "Terraform"
 	if p.IsTerragrunt {
 		binName = "Terragrunt"
+	} else if isOpenTofuBinary(p.TerraformBinary) {
+		binName = "OpenTofu"
 	}
 
 	msg := ""
@@ -0,0 +1,35 @@
+package google
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/resources/google"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getBigQueryReservationRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "google_bigquery_reservation",
+		RFunc: NewBigQueryReservation,
+	}
+}
+
+func NewBigQueryReservation(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+	if location := d.Get("location").String(); location != "" {
+		region = strings.ToLower(location)
+	}
+
+	// Reservations created without an edition use the Enterprise edition.
+	edition := d.GetStringOrDefault("edition", "ENTERPRISE")
+
+	r := &google.BigQueryReservation{
+		Address:      d.Address,
+		Region:       region,
+		Edition:      edition,
+		SlotCapacity: d.Get("slot_capacity").Int(),
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
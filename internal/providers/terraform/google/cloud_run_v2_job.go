@@ -0,0 +1,38 @@
+package google
+
+import (
+	"github.com/infracost/infracost/internal/resources/google"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getCloudRunV2JobRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "google_cloud_run_v2_job",
+		RFunc: newCloudRunV2Job,
+	}
+}
+
+func newCloudRunV2Job(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("location").String()
+	if region == "" {
+		region = d.Get("region").String()
+	}
+
+	limits := d.Get("template.0.template.0.containers.0.resources.0.limits")
+
+	taskCount := int64(1)
+	if !d.IsEmpty("template.0.task_count") {
+		taskCount = d.Get("template.0.task_count").Int()
+	}
+
+	r := &google.CloudRunV2Job{
+		Address:   d.Address,
+		Region:    region,
+		CPU:       parseCloudRunCPU(limits.Get("cpu").String()),
+		MemoryGiB: parseCloudRunMemoryGiB(limits.Get("memory").String()),
+		TaskCount: taskCount,
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
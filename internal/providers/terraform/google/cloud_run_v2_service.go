@@ -0,0 +1,104 @@
+package google
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/resources/google"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const (
+	cloudRunDefaultCPU       = 1.0
+	cloudRunDefaultMemoryGiB = 0.5
+)
+
+func getCloudRunV2ServiceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "google_cloud_run_v2_service",
+		RFunc: newCloudRunV2Service,
+	}
+}
+
+func newCloudRunV2Service(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("location").String()
+	if region == "" {
+		region = d.Get("region").String()
+	}
+
+	limits := d.Get("template.0.containers.0.resources.0.limits")
+	cpuIdle := d.Get("template.0.containers.0.resources.0.cpu_idle")
+
+	r := &google.CloudRunV2Service{
+		Address:            d.Address,
+		Region:             region,
+		CPU:                parseCloudRunCPU(limits.Get("cpu").String()),
+		MemoryGiB:          parseCloudRunMemoryGiB(limits.Get("memory").String()),
+		CPUAlwaysAllocated: cpuIdle.Type != gjson.Null && !cpuIdle.Bool(),
+		MinInstanceCount:   d.Get("template.0.scaling.0.min_instance_count").Int(),
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+// parseCloudRunCPU parses a Cloud Run CPU limit, e.g. 2 or 1000m, into a number of vCPUs.
+func parseCloudRunCPU(s string) float64 {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return cloudRunDefaultCPU
+	}
+
+	divisor := 1.0
+	if strings.HasSuffix(s, "m") {
+		s = strings.TrimSuffix(s, "m")
+		divisor = 1000
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v <= 0 {
+		return cloudRunDefaultCPU
+	}
+
+	return v / divisor
+}
+
+// parseCloudRunMemoryGiB parses a Cloud Run memory limit, e.g. 512Mi or 2G, into GiB.
+func parseCloudRunMemoryGiB(s string) float64 {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return cloudRunDefaultMemoryGiB
+	}
+
+	units := []struct {
+		suffix string
+		bytes  float64
+	}{
+		{"Ki", 1 << 10},
+		{"Mi", 1 << 20},
+		{"Gi", 1 << 30},
+		{"Ti", 1 << 40},
+		{"k", 1e3},
+		{"M", 1e6},
+		{"G", 1e9},
+		{"T", 1e12},
+	}
+
+	multiplier := 1.0
+	for _, unit := range units {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSuffix(s, unit.suffix)
+			multiplier = unit.bytes
+			break
+		}
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v <= 0 {
+		return cloudRunDefaultMemoryGiB
+	}
+
+	return v * multiplier / (1 << 30)
+}
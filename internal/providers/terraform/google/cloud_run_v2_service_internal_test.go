@@ -0,0 +1,22 @@
+package google
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCloudRunCPU(t *testing.T) {
+	assert.Equal(t, 1.0, parseCloudRunCPU(""))
+	assert.Equal(t, 2.0, parseCloudRunCPU("2"))
+	assert.Equal(t, 0.5, parseCloudRunCPU("500m"))
+	assert.Equal(t, 1.0, parseCloudRunCPU("invalid"))
+}
+
+func TestParseCloudRunMemoryGiB(t *testing.T) {
+	assert.Equal(t, 0.5, parseCloudRunMemoryGiB(""))
+	assert.Equal(t, 0.5, parseCloudRunMemoryGiB("512Mi"))
+	assert.Equal(t, 2.0, parseCloudRunMemoryGiB("2Gi"))
+	assert.InDelta(t, 0.9313, parseCloudRunMemoryGiB("1G"), 0.0001)
+	assert.Equal(t, 0.5, parseCloudRunMemoryGiB("invalid"))
+}
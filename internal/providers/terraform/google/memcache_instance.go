@@ -0,0 +1,26 @@
+package google
+
+import (
+	"github.com/infracost/infracost/internal/resources/google"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getMemcacheInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "google_memcache_instance",
+		RFunc: NewMemcacheInstance,
+	}
+}
+
+func NewMemcacheInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &google.MemcacheInstance{
+		Address:      d.Address,
+		Region:       d.Get("region").String(),
+		NodeCount:    d.Get("node_count").Int(),
+		CPUCount:     d.Get("node_config.0.cpu_count").Int(),
+		MemorySizeMB: d.Get("node_config.0.memory_size_mb").Int(),
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -13,11 +13,18 @@ func getRedisInstanceRegistryItem() *schema.RegistryItem {
 }
 
 func NewRedisInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	var replicaCount int64
+	if d.Get("read_replicas_mode").String() == "READ_REPLICAS_ENABLED" {
+		// Terraform defaults to 2 replicas when read replicas are enabled.
+		replicaCount = d.GetInt64OrDefault("replica_count", 2)
+	}
+
 	r := &google.RedisInstance{
 		Address:      d.Address,
 		Region:       d.Get("region").String(),
 		MemorySizeGB: d.Get("memory_size_gb").Float(),
 		Tier:         d.Get("tier").String(),
+		ReplicaCount: replicaCount,
 	}
 
 	r.PopulateUsage(u)
@@ -5,8 +5,11 @@ import "github.com/infracost/infracost/internal/schema"
 var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	getArtifactRegistryRepositoryRegistryItem(),
 	getBigQueryDatasetRegistryItem(),
+	getBigQueryReservationRegistryItem(),
 	getBigQueryTableRegistryItem(),
 	getCloudFunctionsRegistryItem(),
+	getCloudRunV2JobRegistryItem(),
+	getCloudRunV2ServiceRegistryItem(),
 	getComputeAddressRegistryItem(),
 	getComputeDiskRegistryItem(),
 	getComputeExternalVPNGatewayRegistryItem(),
@@ -44,6 +47,7 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	getLoggingOrganizationBucketConfigRegistryItem(),
 	getLoggingOrganizationSinkRegistryItem(),
 	getLoggingProjectSinkRegistryItem(),
+	getMemcacheInstanceRegistryItem(),
 	getMonitoringItem(),
 	getPubSubSubscriptionRegistryItem(),
 	getPubSubTopicRegistryItem(),
@@ -51,6 +55,7 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	getSecretManagerSecretRegistryItem(),
 	getSecretManagerSecretVersionRegistryItem(),
 	getServiceNetworkingConnectionRegistryItem(),
+	getSpannerInstanceRegistryItem(),
 	getSQLDatabaseInstanceRegistryItem(),
 	getStorageBucketRegistryItem(),
 	getComputePerInstanceConfigRegistryItem(),
@@ -0,0 +1,45 @@
+package google
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/resources/google"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getSpannerInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "google_spanner_instance",
+		RFunc: NewSpannerInstance,
+	}
+}
+
+func NewSpannerInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+	multiRegion := false
+
+	// Regional configs are named regional-<region>, all other configs are
+	// multi-region configs such as nam3 or nam-eur-asia1.
+	config := d.Get("config").String()
+	if strings.HasPrefix(config, "regional-") {
+		region = strings.TrimPrefix(config, "regional-")
+	} else if config != "" {
+		region = config
+		multiRegion = true
+	}
+
+	processingUnits := d.Get("processing_units").Int()
+	if processingUnits == 0 {
+		processingUnits = d.GetInt64OrDefault("num_nodes", 1) * 1000
+	}
+
+	r := &google.SpannerInstance{
+		Address:         d.Address,
+		Region:          region,
+		MultiRegion:     multiRegion,
+		ProcessingUnits: processingUnits,
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
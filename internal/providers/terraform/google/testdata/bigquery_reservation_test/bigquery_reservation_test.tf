@@ -0,0 +1,32 @@
+provider "google" {
+  credentials = "{\"type\":\"service_account\"}"
+  region      = "us-central1"
+}
+
+resource "google_bigquery_reservation" "standard" {
+  name          = "standard"
+  location      = "us-central1"
+  edition       = "STANDARD"
+  slot_capacity = 0
+
+  autoscale {
+    max_slots = 100
+  }
+}
+
+resource "google_bigquery_reservation" "enterprise" {
+  name          = "enterprise"
+  location      = "us-central1"
+  slot_capacity = 100
+}
+
+resource "google_bigquery_reservation" "enterprise_plus" {
+  name          = "enterprise_plus"
+  location      = "us-central1"
+  edition       = "ENTERPRISE_PLUS"
+  slot_capacity = 200
+
+  autoscale {
+    max_slots = 200
+  }
+}
@@ -0,0 +1,24 @@
+provider "google" {
+  credentials = "{\"type\":\"service_account\"}"
+  region      = "us-central1"
+}
+
+resource "google_memcache_instance" "single_node" {
+  name       = "single-node"
+  node_count = 1
+
+  node_config {
+    cpu_count      = 1
+    memory_size_mb = 1024
+  }
+}
+
+resource "google_memcache_instance" "multi_node" {
+  name       = "multi-node"
+  node_count = 3
+
+  node_config {
+    cpu_count      = 4
+    memory_size_mb = 8192
+  }
+}
@@ -57,3 +57,11 @@ resource "google_redis_instance" "standard_m5" {
   memory_size_gb = 105
   tier           = "STANDARD_HA"
 }
+
+resource "google_redis_instance" "standard_read_replicas" {
+  name               = "memory-cache"
+  memory_size_gb     = 5
+  tier               = "STANDARD_HA"
+  read_replicas_mode = "READ_REPLICAS_ENABLED"
+  replica_count      = 3
+}
@@ -0,0 +1,32 @@
+provider "google" {
+  credentials = "{\"type\":\"service_account\"}"
+  region      = "us-central1"
+}
+
+resource "google_spanner_instance" "regional" {
+  name         = "regional"
+  config       = "regional-us-central1"
+  display_name = "Regional"
+  num_nodes    = 2
+}
+
+resource "google_spanner_instance" "processing_units" {
+  name             = "processing-units"
+  config           = "regional-europe-west1"
+  display_name     = "Processing units"
+  processing_units = 300
+}
+
+resource "google_spanner_instance" "multi_region" {
+  name         = "multi-region"
+  config       = "nam3"
+  display_name = "Multi-region"
+  num_nodes    = 1
+}
+
+resource "google_spanner_instance" "with_usage" {
+  name         = "with-usage"
+  config       = "regional-us-central1"
+  display_name = "With usage"
+  num_nodes    = 1
+}
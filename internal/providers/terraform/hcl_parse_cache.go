@@ -0,0 +1,220 @@
+package terraform
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/version"
+)
+
+// hclModuleUsage is the usage that the modules of a project define with usage
+// annotations and metadata files. It's read from the blocks of the modules, so
+// it's cached with the plan JSON of the project.
+type hclModuleUsage struct {
+	Annotations map[string]*schema.UsageData
+	Defaults    map[string]*schema.UsageData
+	Unsupported map[string]string
+	Ignored     map[string]string
+	CostNotes   []schema.ProjectDiag
+}
+
+func collectModuleUsage(module *hcl.Module) *hclModuleUsage {
+	u := &hclModuleUsage{
+		Annotations: map[string]*schema.UsageData{},
+		Defaults:    map[string]*schema.UsageData{},
+		Unsupported: map[string]string{},
+		Ignored:     map[string]string{},
+	}
+
+	newUsageAnnotations().collect(module, u.Annotations, u.Ignored)
+	newModuleMetadataFiles().collect(module, &u.CostNotes, u.Defaults, u.Unsupported)
+
+	return u
+}
+
+// apply adds the cost notes of the modules to the warnings of project. It
+// returns a copy of usage with the usage of the annotations and then the
+// defaults of the metadata files, and the reasons of the resources that the
+// modules mark as unsupported.
+func (u *hclModuleUsage) apply(project *schema.Project, usage schema.UsageMap) (schema.UsageMap, map[string]string) {
+	usage = mergeModuleUsage(usage, u.Annotations)
+
+	project.Metadata.Warnings = append(project.Metadata.Warnings, u.CostNotes...)
+
+	return mergeModuleUsage(usage, u.Defaults), u.Unsupported
+}
+
+// hclParseCache stores the projects that the HCL provider evaluates on disk,
+// keyed by the content hash of their root module, see hcl.Parser.ContentHash.
+// Projects whose modules, vars and workspace haven't changed since a previous
+// run are loaded from the cache instead of being parsed and evaluated again.
+type hclParseCache struct {
+	dir       string
+	dirHashes *hcl.DirHashes
+}
+
+func newHCLParseCache(dir string) *hclParseCache {
+	return &hclParseCache{dir: dir, dirHashes: hcl.NewDirHashes()}
+}
+
+// hclParseCacheEntry is the part of an HCLProject that the provider uses to
+// load its resources.
+type hclParseCacheEntry struct {
+	JSON        json.RawMessage              `json:"json"`
+	Name        string                       `json:"name"`
+	Source      string                       `json:"source"`
+	RootPath    string                       `json:"rootPath"`
+	ModulePath  string                       `json:"modulePath"`
+	Warnings    []hcl.Warning                `json:"warnings,omitempty"`
+	Annotations map[string]map[string]string `json:"annotations,omitempty"`
+	Defaults    map[string]map[string]string `json:"defaults,omitempty"`
+	Unsupported map[string]string            `json:"unsupported,omitempty"`
+	Ignored     map[string]string            `json:"ignored,omitempty"`
+	CostNotes   []schema.ProjectDiag         `json:"costNotes,omitempty"`
+	Outputs     map[string]json.RawMessage   `json:"outputs,omitempty"`
+}
+
+// key returns the key that the project of the parser is cached with, and
+// false if it can't be cached.
+func (c *hclParseCache) key(parser *hcl.Parser) (string, bool) {
+	hash, err := parser.ContentHash(c.dirHashes)
+	if err != nil {
+		logging.Logger.WithError(err).Debugf("not caching the parsed project at %s", parser.Path())
+		return "", false
+	}
+
+	return hash, true
+}
+
+func (c *hclParseCache) path(key string) string {
+	return filepath.Join(c.dir, version.Version, key[:2], key+".json")
+}
+
+// get returns the cached project of the parser with the key.
+func (c *hclParseCache) get(key string, parser *hcl.Parser) (HCLProject, bool) {
+	b, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return HCLProject{}, false
+	}
+
+	var entry hclParseCacheEntry
+	err = json.Unmarshal(b, &entry)
+	if err != nil {
+		logging.Logger.WithError(err).Debugf("ignoring invalid parse cache entry for %s", parser.Path())
+		return HCLProject{}, false
+	}
+
+	return HCLProject{
+		JSON: entry.JSON,
+		Module: &hcl.Module{
+			Name:       entry.Name,
+			Source:     entry.Source,
+			RootPath:   entry.RootPath,
+			ModulePath: entry.ModulePath,
+			Warnings:   entry.Warnings,
+			HasChanges: parser.HasChanges(),
+		},
+		moduleUsage: &hclModuleUsage{
+			Annotations: usageDataFromRaw(entry.Annotations),
+			Defaults:    usageDataFromRaw(entry.Defaults),
+			Unsupported: entry.Unsupported,
+			Ignored:     entry.Ignored,
+			CostNotes:   entry.CostNotes,
+		},
+		outputs: entry.Outputs,
+	}, true
+}
+
+// set caches the project with the key. Projects that failed to be evaluated
+// aren't cached.
+func (c *hclParseCache) set(key string, project HCLProject) {
+	if project.Error != nil || project.Module == nil {
+		return
+	}
+
+	u := project.moduleUsage
+	if u == nil {
+		u = collectModuleUsage(project.Module)
+	}
+
+	b, err := json.Marshal(hclParseCacheEntry{
+		JSON:        project.JSON,
+		Name:        project.Module.Name,
+		Source:      project.Module.Source,
+		RootPath:    project.Module.RootPath,
+		ModulePath:  project.Module.ModulePath,
+		Warnings:    project.Module.Warnings,
+		Annotations: usageDataToRaw(u.Annotations),
+		Defaults:    usageDataToRaw(u.Defaults),
+		Unsupported: u.Unsupported,
+		Ignored:     u.Ignored,
+		CostNotes:   u.CostNotes,
+		Outputs:     project.Outputs(),
+	})
+	if err != nil {
+		logging.Logger.WithError(err).Debugf("error caching the parsed project at %s", project.Module.RootPath)
+		return
+	}
+
+	path := c.path(key)
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		logging.Logger.WithError(err).Debug("error creating parse cache directory")
+		return
+	}
+
+	// Write to a temporary file first so concurrent runs never read a
+	// partially written entry.
+	f, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		logging.Logger.WithError(err).Debug("error caching parsed project")
+		return
+	}
+
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+
+	if err != nil {
+		logging.Logger.WithError(err).Debug("error caching parsed project")
+		_ = os.Remove(f.Name())
+	}
+}
+
+func usageDataToRaw(data map[string]*schema.UsageData) map[string]map[string]string {
+	raw := make(map[string]map[string]string, len(data))
+	for address, u := range data {
+		attrs := make(map[string]string, len(u.Attributes))
+		for k, v := range u.Attributes {
+			attrs[k] = v.Raw
+		}
+
+		raw[address] = attrs
+	}
+
+	return raw
+}
+
+func usageDataFromRaw(raw map[string]map[string]string) map[string]*schema.UsageData {
+	data := make(map[string]*schema.UsageData, len(raw))
+	for address, attrs := range raw {
+		parsed := make(map[string]gjson.Result, len(attrs))
+		for k, v := range attrs {
+			parsed[k] = gjson.Parse(v)
+		}
+
+		data[address] = schema.NewUsageData(address, parsed)
+	}
+
+	return data
+}
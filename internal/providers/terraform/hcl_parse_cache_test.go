@@ -0,0 +1,107 @@
+package terraform
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/hcl/modules"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/sync"
+)
+
+func TestHCLParseCache(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.tf": `
+module "worker" {
+  source = "./modules/worker"
+}
+`,
+		"modules/worker/main.tf": `
+# infracost-usage: monthly_requests=200
+resource "aws_lambda_function" "worker" {
+  function_name = "worker"
+  role          = "arn:aws:iam::123456789012:role/worker"
+  runtime       = "nodejs18.x"
+  handler       = "index.handler"
+  memory_size   = 512
+}
+`,
+		"modules/worker/infracost-metadata.yml": `version: 0.1
+resource_type_default_usage:
+  aws_lambda_function:
+    request_duration_ms: 100
+cost_notes:
+  - Data transfer to the VPN peers isn't included.
+`,
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	entry := logrus.NewEntry(logger)
+
+	cacheDir := t.TempDir()
+	load := func() []HCLProject {
+		parsers, err := hcl.LoadParsers(dir, modules.NewModuleLoader(dir, nil, entry, &sync.KeyMutex{}), nil, entry)
+		require.NoError(t, err)
+
+		p := HCLProvider{
+			parsers:    parsers,
+			logger:     entry,
+			ctx:        &config.ProjectContext{RunContext: &config.RunContext{Config: &config.Config{}}},
+			parseCache: newHCLParseCache(cacheDir),
+		}
+
+		parsed := p.LoadPlanJSONs()
+		require.Len(t, parsed, 1)
+		require.NoError(t, parsed[0].Error)
+
+		return parsed
+	}
+
+	parsed := load()
+	assert.Nil(t, parsed[0].moduleUsage)
+
+	cached := load()
+	require.NotNil(t, cached[0].moduleUsage, "the unchanged project should be loaded from the cache")
+	assert.JSONEq(t, string(parsed[0].JSON), string(cached[0].JSON))
+	assert.Equal(t, parsed[0].Module.RootPath, cached[0].Module.RootPath)
+
+	project := schema.NewProject("test", &schema.ProjectMetadata{})
+	usage, _ := cached[0].moduleUsage.apply(project, schema.NewUsageMap(nil))
+
+	u := usage.Get("module.worker.aws_lambda_function.worker")
+	require.NotNil(t, u)
+	assert.Equal(t, int64(200), u.Get("monthly_requests").Int())
+	assert.Equal(t, int64(100), u.Get("request_duration_ms").Int())
+	require.Len(t, project.Metadata.Warnings, 1)
+	assert.Equal(t, "Data transfer to the VPN peers isn't included.", project.Metadata.Warnings[0].Message)
+
+	// Changing a module invalidates the projects that call it.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "modules/worker/main.tf"), []byte(`
+resource "aws_lambda_function" "worker" {
+  function_name = "worker"
+  role          = "arn:aws:iam::123456789012:role/worker"
+  runtime       = "nodejs18.x"
+  handler       = "index.handler"
+  memory_size   = 1024
+}
+`), 0600))
+
+	changed := load()
+	assert.Nil(t, changed[0].moduleUsage)
+	assert.Contains(t, string(changed[0].JSON), "1024")
+}
@@ -36,6 +36,9 @@ type HCLProvider struct {
 	ctx    *config.ProjectContext
 	cache  []HCLProject
 	config HCLProviderConfig
+	// parseCache caches the evaluated projects on disk between runs. It's nil
+	// if they aren't cached.
+	parseCache *hclParseCache
 }
 
 type HCLProviderConfig struct {
@@ -103,6 +106,12 @@ func NewHCLProvider(ctx *config.ProjectContext, config *HCLProviderConfig, opts
 		options = append(options, withFiles)
 	}
 
+	// Vars that are set to the outputs of other projects are overridden by the
+	// vars of the project.
+	if len(ctx.OutputVars) > 0 {
+		options = append(options, hcl.OptionWithRawCtyInput(outputVarsValue(ctx.OutputVars)))
+	}
+
 	if len(ctx.ProjectConfig.TerraformVars) > 0 {
 		withInputVars := hcl.OptionWithInputVars(ctx.ProjectConfig.TerraformVars)
 		options = append(options, withInputVars)
@@ -129,6 +138,10 @@ func NewHCLProvider(ctx *config.ProjectContext, config *HCLProviderConfig, opts
 
 	logger := ctx.Logger().WithFields(log.Fields{"provider": "terraform_dir"})
 	runCtx := ctx.RunContext
+	if runCtx.Config.TerraformRemoteState {
+		options = append(options, hcl.OptionWithRemoteStateLoader(hcl.NewRemoteStateLoader(ctx.ProjectConfig.Env, logger)))
+	}
+
 	locatorConfig := &hcl.ProjectLocatorConfig{ExcludedSubDirs: ctx.ProjectConfig.ExcludePaths, ChangedObjects: runCtx.VCSMetadata.Commit.ChangedObjects, UseAllPaths: ctx.ProjectConfig.IncludeAllPaths}
 
 	wd := ctx.RunContext.Config.RepoPath()
@@ -143,6 +156,9 @@ func NewHCLProvider(ctx *config.ProjectContext, config *HCLProviderConfig, opts
 	}
 
 	loader := modules.NewModuleLoader(wd, credsSource, logger, ctx.RunContext.ModuleMutex)
+	if !runCtx.Config.NoCache {
+		loader.SharedCacheDir = runCtx.Config.ModuleCacheDir
+	}
 	parsers, err := hcl.LoadParsers(
 		initialPath,
 		loader,
@@ -158,6 +174,11 @@ func NewHCLProvider(ctx *config.ProjectContext, config *HCLProviderConfig, opts
 		scanner = scan.NewTerraformPlanScanner(runCtx, ctx.Logger(), prices.GetPrices)
 	}
 
+	var parseCache *hclParseCache
+	if !runCtx.Config.NoCache && runCtx.Config.HCLCacheDir != "" {
+		parseCache = newHCLParseCache(runCtx.Config.HCLCacheDir)
+	}
+
 	return &HCLProvider{
 		scanner:        scanner,
 		parsers:        parsers,
@@ -165,6 +186,7 @@ func NewHCLProvider(ctx *config.ProjectContext, config *HCLProviderConfig, opts
 		ctx:            ctx,
 		config:         *config,
 		logger:         logger,
+		parseCache:     parseCache,
 	}, err
 }
 
@@ -201,6 +223,7 @@ func (p *HCLProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, e
 		}
 
 		project := p.parseResources(j, usage)
+		project.Outputs = j.Outputs()
 		if p.ctx.RunContext.VCSMetadata.HasChanges() {
 			project.Metadata.VCSCodeChanged = &j.Module.HasChanges
 		}
@@ -220,6 +243,17 @@ func (p *HCLProvider) LoadResources(usage schema.UsageMap) ([]*schema.Project, e
 func (p *HCLProvider) parseResources(parsed HCLProject, usage schema.UsageMap) *schema.Project {
 	project := p.newProject(parsed)
 
+	moduleUsage := parsed.moduleUsage
+	if moduleUsage == nil && parsed.Module != nil {
+		moduleUsage = collectModuleUsage(parsed.Module)
+	}
+
+	var unsupported, ignored map[string]string
+	if moduleUsage != nil {
+		usage, unsupported = moduleUsage.apply(project, usage)
+		ignored = moduleUsage.Ignored
+	}
+
 	partialPastResources, partialResources, err := p.planJSONParser.parseJSON(parsed.JSON, usage)
 	if err != nil {
 		project.Metadata.Errors = []schema.ProjectDiag{
@@ -232,6 +266,11 @@ func (p *HCLProvider) parseResources(parsed HCLProject, usage schema.UsageMap) *
 		return project
 	}
 
+	markUnsupportedResources(partialPastResources, unsupported)
+	markUnsupportedResources(partialResources, unsupported)
+	markIgnoredResources(partialPastResources, ignored)
+	markIgnoredResources(partialResources, ignored)
+
 	project.PartialPastResources = partialPastResources
 	project.PartialResources = partialResources
 
@@ -280,12 +319,91 @@ type HCLProject struct {
 	JSON   []byte
 	Module *hcl.Module
 	Error  error
+
+	// moduleUsage is set when the project is loaded from the parse cache, as
+	// its Module doesn't have the blocks that it's collected from.
+	moduleUsage *hclModuleUsage
+	// parser is the parser that the project was parsed with.
+	parser *hcl.Parser
+	// outputs is set when the project is loaded from the parse cache, as its
+	// Module doesn't have the blocks that they're evaluated from.
+	outputs map[string]json.RawMessage
+}
+
+// Outputs returns the evaluated outputs of the project as JSON values. Outputs
+// whose values aren't known are left out.
+func (p HCLProject) Outputs() map[string]json.RawMessage {
+	if p.outputs != nil || p.Module == nil {
+		return p.outputs
+	}
+
+	values := p.Module.Blocks.Outputs(true)
+	if values.IsNull() || !values.IsKnown() || !values.CanIterateElements() {
+		return nil
+	}
+
+	outputs := make(map[string]json.RawMessage)
+	for name, v := range values.AsValueMap() {
+		if !v.IsWhollyKnown() {
+			continue
+		}
+
+		b, err := ctyJson.Marshal(v, v.Type())
+		if err != nil {
+			continue
+		}
+
+		outputs[name] = b
+	}
+
+	return outputs
 }
 
 // LoadPlanJSONs parses the found directories and return the blocks in Terraform plan JSON format.
+// Projects that are in the parse cache are loaded from it instead of being parsed.
 func (p *HCLProvider) LoadPlanJSONs() []HCLProject {
-	var jsons = make([]HCLProject, len(p.parsers))
-	mods := p.Modules()
+	if p.parseCache == nil {
+		return p.modulesToPlanJSONs(p.Modules())
+	}
+
+	var (
+		jsons   []HCLProject
+		parsers []*hcl.Parser
+		keys    = make(map[*hcl.Parser]string, len(p.parsers))
+	)
+
+	for _, parser := range p.parsers {
+		key, ok := p.parseCache.key(parser)
+		if !ok {
+			parsers = append(parsers, parser)
+			continue
+		}
+
+		if cached, ok := p.parseCache.get(key, parser); ok {
+			p.logger.Debugf("using cached parse of the project at %s", parser.Path())
+			jsons = append(jsons, cached)
+			continue
+		}
+
+		keys[parser] = key
+		parsers = append(parsers, parser)
+	}
+
+	for _, module := range p.modulesToPlanJSONs(p.parseModules(parsers)) {
+		if key, ok := keys[module.parser]; ok {
+			p.parseCache.set(key, module)
+		}
+
+		jsons = append(jsons, module)
+	}
+
+	sortHCLProjects(jsons)
+
+	return jsons
+}
+
+func (p *HCLProvider) modulesToPlanJSONs(mods []HCLProject) []HCLProject {
+	var jsons = make([]HCLProject, len(mods))
 
 	for i, module := range mods {
 		if module.Error == nil {
@@ -312,10 +430,23 @@ func (p *HCLProvider) Modules() []HCLProject {
 		return p.cache
 	}
 
+	mods := p.parseModules(p.parsers)
+
+	if p.config.CacheParsingModules {
+		p.cache = mods
+	}
+
+	sortHCLProjects(mods)
+
+	return mods
+}
+
+// parseModules parses the directories of the parsers in parallel.
+func (p *HCLProvider) parseModules(parsers []*hcl.Parser) []HCLProject {
 	runCtx := p.ctx.RunContext
 	parallelism, _ := runCtx.GetParallelism()
 
-	numJobs := len(p.parsers)
+	numJobs := len(parsers)
 	runInParallel := parallelism > 1 && numJobs > 1
 	if runInParallel && !runCtx.Config.IsLogging() {
 		// set the config level to info so that the spinners don't report to the console.
@@ -331,7 +462,7 @@ func (p *HCLProvider) Modules() []HCLProject {
 	mu := &sync.Mutex{}
 	wg := &sync.WaitGroup{}
 
-	for _, parser := range p.parsers {
+	for _, parser := range parsers {
 		ch <- parser
 	}
 	close(ch)
@@ -351,7 +482,7 @@ func (p *HCLProvider) Modules() []HCLProject {
 				module, err := parser.ParseDirectory()
 
 				mu.Lock()
-				mods = append(mods, HCLProject{Module: module, Error: err})
+				mods = append(mods, HCLProject{Module: module, Error: err, parser: parser})
 				mu.Unlock()
 			}
 		}()
@@ -359,10 +490,10 @@ func (p *HCLProvider) Modules() []HCLProject {
 
 	wg.Wait()
 
-	if p.config.CacheParsingModules {
-		p.cache = mods
-	}
+	return mods
+}
 
+func sortHCLProjects(mods []HCLProject) {
 	sort.Slice(mods, func(i, j int) bool {
 		if mods[i].Module.Name != "" && mods[j].Module.Name != "" {
 			return mods[i].Module.Name < mods[j].Module.Name
@@ -370,8 +501,6 @@ func (p *HCLProvider) Modules() []HCLProject {
 
 		return mods[i].Module.ModulePath < mods[j].Module.ModulePath
 	})
-
-	return mods
 }
 
 // InvalidateCache removes the module cache from the prior hcl parse.
@@ -458,6 +587,12 @@ func (p *HCLProvider) marshalModule(module *hcl.Module) ModuleOut {
 			planModule.Resources = append(planModule.Resources, out.Planned)
 			p.schema.InfracostResourceChanges = append(p.schema.InfracostResourceChanges, out.Changes)
 		}
+
+		// Data sources are only added if they have a resolver, as their values are
+		// only used for the pricing hints of the resources that reference them.
+		if block.Type() == "data" && GetDataSourceResolver(block.TypeLabel()) != nil {
+			planModule.Resources = append(planModule.Resources, p.getDataSourceOutput(block))
+		}
 	}
 
 	for _, m := range module.Modules {
@@ -489,8 +624,9 @@ func (p *HCLProvider) getResourceOutput(block *hcl.Block) ResourceOutput {
 		Index:         block.Index(),
 		SchemaVersion: 0,
 		InfracostMetadata: map[string]interface{}{
-			"filename": block.Filename,
-			"calls":    block.CallDetails(),
+			"filename":  block.Filename,
+			"startLine": block.StartLine(),
+			"calls":     block.CallDetails(),
 		},
 	}
 
@@ -558,6 +694,22 @@ func (p *HCLProvider) getResourceOutput(block *hcl.Block) ResourceOutput {
 	}
 }
 
+// getDataSourceOutput returns the planned values of the data source, which are
+// the arguments of its config as the results are only known to Terraform.
+func (p *HCLProvider) getDataSourceOutput(block *hcl.Block) ResourceJSON {
+	jsonValues := marshalAttributeValues(block.Type(), block.Values())
+	p.marshalBlock(block, jsonValues)
+
+	return ResourceJSON{
+		Address: block.FullName(),
+		Mode:    "data",
+		Type:    block.TypeLabel(),
+		Name:    stripCount(block.NameLabel()),
+		Index:   block.Index(),
+		Values:  jsonValues,
+	}
+}
+
 func (p *HCLProvider) marshalProviderBlock(block *hcl.Block) string {
 	name := block.TypeLabel()
 	if a := block.GetAttribute("alias"); a != nil {
@@ -823,3 +975,26 @@ var countRegex = regexp.MustCompile(`\[\d+\]$`)
 func stripCount(s string) string {
 	return countRegex.ReplaceAllString(s, "")
 }
+
+// outputVarsValue returns the output vars of a project as an object of input
+// vars. Vars whose values can't be decoded are left out.
+func outputVarsValue(outputVars map[string]json.RawMessage) cty.Value {
+	vars := make(map[string]cty.Value, len(outputVars))
+	for name, raw := range outputVars {
+		t, err := ctyJson.ImpliedType(raw)
+		if err != nil {
+			logging.Logger.WithError(err).Debugf("could not decode the output used for var %s", name)
+			continue
+		}
+
+		v, err := ctyJson.Unmarshal(raw, t)
+		if err != nil {
+			logging.Logger.WithError(err).Debugf("could not decode the output used for var %s", name)
+			continue
+		}
+
+		vars[name] = v
+	}
+
+	return cty.ObjectVal(vars)
+}
@@ -2,9 +2,12 @@ package terraform
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"testing"
 	"text/template"
@@ -14,6 +17,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/infracost/infracost/internal/config"
@@ -232,3 +236,66 @@ func TestHCLProvider_LoadPlanJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestHCLProviderOutputVars(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"hub/main.tf": `
+output "vnet_id" {
+  value = "vnet-123"
+}
+
+output "subnets" {
+  value = ["a", "b"]
+}
+`,
+		"spoke/main.tf": `
+variable "hub_vnet_id" {}
+variable "subnets" {}
+
+resource "aws_instance" "web" {
+  ami           = var.hub_vnet_id
+  instance_type = "t3.micro"
+  count         = length(var.subnets)
+}
+`,
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	entry := logrus.NewEntry(logger)
+
+	load := func(path string, options ...hcl.Option) HCLProject {
+		parsers, err := hcl.LoadParsers(path, modules.NewModuleLoader(path, nil, entry, &sync.KeyMutex{}), nil, entry, options...)
+		require.NoError(t, err)
+
+		p := HCLProvider{
+			parsers: parsers,
+			logger:  entry,
+			ctx:     &config.ProjectContext{RunContext: &config.RunContext{Config: &config.Config{}}},
+		}
+
+		parsed := p.LoadPlanJSONs()
+		require.Len(t, parsed, 1)
+		require.NoError(t, parsed[0].Error)
+
+		return parsed[0]
+	}
+
+	outputs := load(filepath.Join(dir, "hub")).Outputs()
+	assert.JSONEq(t, `"vnet-123"`, string(outputs["vnet_id"]))
+	assert.JSONEq(t, `["a","b"]`, string(outputs["subnets"]))
+
+	spoke := load(filepath.Join(dir, "spoke"), hcl.OptionWithRawCtyInput(outputVarsValue(map[string]json.RawMessage{
+		"hub_vnet_id": outputs["vnet_id"],
+		"subnets":     outputs["subnets"],
+	})))
+
+	assert.Equal(t, "vnet-123", gjson.GetBytes(spoke.JSON, `planned_values.root_module.resources.0.values.ami`).String())
+	assert.Len(t, gjson.GetBytes(spoke.JSON, `planned_values.root_module.resources`).Array(), 2)
+}
@@ -0,0 +1,197 @@
+package terraform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const (
+	// moduleMetadataFilename is the file that module authors can publish with
+	// a module to improve the estimates of the projects that use it.
+	moduleMetadataFilename = "infracost-metadata.yml"
+
+	moduleMetadataVersion = "0.1"
+)
+
+// moduleMetadata is the infracost-metadata.yml file of a module, e.g:
+//
+//	version: 0.1
+//	resource_type_default_usage:
+//	  aws_lambda_function:
+//	    monthly_requests: 1000000
+//	resource_usage:
+//	  aws_lambda_function.worker:
+//	    request_duration_ms: 500
+//	cost_notes:
+//	  - Data transfer to the VPN peers isn't included.
+//	unsupported:
+//	  aws_instance.license_server: Marketplace license fees aren't included.
+//
+// Resources are referenced by their address inside the module, without the
+// module prefix or count and for_each keys.
+type moduleMetadata struct {
+	Version                  string                            `yaml:"version"`
+	ResourceTypeDefaultUsage map[string]map[string]interface{} `yaml:"resource_type_default_usage"`
+	ResourceUsage            map[string]map[string]interface{} `yaml:"resource_usage"`
+	// CostNotes are shown as warnings of the projects that use the module.
+	CostNotes []string `yaml:"cost_notes"`
+	// Unsupported maps resources that can't be estimated accurately to the
+	// reason why, they are shown as unsupported instead of with a cost.
+	Unsupported map[string]string `yaml:"unsupported"`
+}
+
+// moduleMetadataFiles loads the metadata files of modules. It caches the files
+// by module directory so modules that are called many times are only read once.
+type moduleMetadataFiles struct {
+	files map[string]*moduleMetadata
+	// noted holds the module directories whose cost notes have been added, so
+	// the notes of modules that are called many times are only added once.
+	noted map[string]bool
+}
+
+func newModuleMetadataFiles() *moduleMetadataFiles {
+	return &moduleMetadataFiles{files: map[string]*moduleMetadata{}, noted: map[string]bool{}}
+}
+
+// apply adds the cost notes of module and its child modules to the warnings of
+// project. It returns a copy of usage with the default usage of the modules and
+// the reasons of the resources that the modules mark as unsupported, by
+// address. Values from usage take precedence over the defaults of the modules.
+func (f *moduleMetadataFiles) apply(module *hcl.Module, project *schema.Project, usage schema.UsageMap) (schema.UsageMap, map[string]string) {
+	defaults := map[string]*schema.UsageData{}
+	unsupported := map[string]string{}
+	var notes []schema.ProjectDiag
+	f.collect(module, &notes, defaults, unsupported)
+
+	project.Metadata.Warnings = append(project.Metadata.Warnings, notes...)
+
+	return mergeModuleUsage(usage, defaults), unsupported
+}
+
+func (f *moduleMetadataFiles) collect(module *hcl.Module, notes *[]schema.ProjectDiag, defaults map[string]*schema.UsageData, unsupported map[string]string) {
+	for _, m := range module.Modules {
+		f.collect(m, notes, defaults, unsupported)
+	}
+
+	metadata := f.load(module.ModulePath)
+	if metadata == nil {
+		return
+	}
+
+	if !f.noted[module.ModulePath] {
+		f.noted[module.ModulePath] = true
+
+		for _, note := range metadata.CostNotes {
+			*notes = append(*notes, schema.ProjectDiag{
+				Code:    schema.DiagModuleCostNote,
+				Message: note,
+				Data:    moduleLabel(module),
+			})
+		}
+	}
+
+	for _, block := range module.Blocks {
+		if block.Type() != "resource" {
+			continue
+		}
+
+		address := block.FullName()
+		key := block.TypeLabel() + "." + block.NameLabel()
+
+		if reason, ok := metadata.Unsupported[key]; ok {
+			unsupported[address] = reason
+		}
+
+		attrs := map[string]interface{}{}
+		for k, v := range metadata.ResourceUsage[key] {
+			attrs[k] = v
+		}
+		for k, v := range metadata.ResourceTypeDefaultUsage[block.TypeLabel()] {
+			if _, ok := attrs[k]; !ok {
+				attrs[k] = v
+			}
+		}
+
+		if len(attrs) > 0 {
+			defaults[address] = schema.NewUsageData(address, schema.ParseAttributes(attrs))
+		}
+	}
+}
+
+// load returns the metadata file in dir, or nil if there isn't a valid one.
+func (f *moduleMetadataFiles) load(dir string) *moduleMetadata {
+	if metadata, ok := f.files[dir]; ok {
+		return metadata
+	}
+
+	metadata, err := readModuleMetadata(filepath.Join(dir, moduleMetadataFilename))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		logging.Logger.WithError(err).Warnf("Ignoring invalid %s of module %s", moduleMetadataFilename, dir)
+	}
+
+	f.files[dir] = metadata
+	return metadata
+}
+
+func readModuleMetadata(path string) (*moduleMetadata, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata moduleMetadata
+	err = yamlv3.Unmarshal(contents, &metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata.Version != moduleMetadataVersion {
+		return nil, fmt.Errorf("invalid version %q, expected %s", metadata.Version, moduleMetadataVersion)
+	}
+
+	return &metadata, nil
+}
+
+func moduleLabel(module *hcl.Module) string {
+	if module.Source != "" {
+		return module.Source
+	}
+
+	return module.Name
+}
+
+// markUnsupportedResources replaces the resources that module metadata marks as
+// unsupported with skipped resources, so they don't show an inaccurate cost.
+func markUnsupportedResources(partials []*schema.PartialResource, unsupported map[string]string) {
+	if len(unsupported) == 0 {
+		return
+	}
+
+	for i, partial := range partials {
+		if partial.ResourceData == nil {
+			continue
+		}
+
+		reason, ok := unsupported[partial.ResourceData.Address]
+		if !ok {
+			continue
+		}
+
+		partials[i] = &schema.PartialResource{
+			ResourceData: partial.ResourceData,
+			Resource: &schema.Resource{
+				Name:        partial.ResourceData.Address,
+				IsSkipped:   true,
+				SkipMessage: reason,
+			},
+		}
+	}
+}
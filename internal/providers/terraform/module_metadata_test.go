@@ -0,0 +1,114 @@
+package terraform
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/hcl/modules"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/sync"
+)
+
+func TestModuleMetadataFilesApply(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.tf": `
+module "worker" {
+  count  = 2
+  source = "./modules/worker"
+}
+`,
+		"modules/worker/main.tf": `
+resource "aws_lambda_function" "worker" {
+  function_name = "worker"
+  role          = "arn:aws:iam::123456789012:role/worker"
+  runtime       = "nodejs18.x"
+  handler       = "index.handler"
+}
+
+resource "aws_instance" "license_server" {
+  ami           = "ami-123"
+  instance_type = "m5.large"
+}
+`,
+		"modules/worker/infracost-metadata.yml": `version: 0.1
+resource_type_default_usage:
+  aws_lambda_function:
+    monthly_requests: 1000000
+    request_duration_ms: 100
+resource_usage:
+  aws_lambda_function.worker:
+    request_duration_ms: 500
+cost_notes:
+  - Data transfer to the VPN peers isn't included.
+unsupported:
+  aws_instance.license_server: Marketplace license fees aren't included.
+`,
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	entry := logrus.NewEntry(logger)
+
+	parsers, err := hcl.LoadParsers(dir, modules.NewModuleLoader(dir, nil, entry, &sync.KeyMutex{}), nil, entry)
+	require.NoError(t, err)
+
+	p := HCLProvider{
+		parsers: parsers,
+		logger:  entry,
+		ctx:     &config.ProjectContext{RunContext: &config.RunContext{Config: &config.Config{}}},
+	}
+	parsed := p.LoadPlanJSONs()
+	require.Len(t, parsed, 1)
+	require.NoError(t, parsed[0].Error)
+
+	project := schema.NewProject("test", &schema.ProjectMetadata{})
+	usage := schema.NewUsageMapFromInterface(map[string]interface{}{
+		"module.worker[1].aws_lambda_function.worker": map[string]interface{}{"monthly_requests": 5},
+	})
+
+	usage, unsupported := newModuleMetadataFiles().apply(parsed[0].Module, project, usage)
+
+	u := usage.Get("module.worker[0].aws_lambda_function.worker")
+	require.NotNil(t, u)
+	assert.Equal(t, int64(1000000), u.Get("monthly_requests").Int())
+	assert.Equal(t, int64(500), u.Get("request_duration_ms").Int())
+
+	u = usage.Get("module.worker[1].aws_lambda_function.worker")
+	require.NotNil(t, u)
+	assert.Equal(t, int64(5), u.Get("monthly_requests").Int(), "the usage file should take precedence over the module defaults")
+	assert.Equal(t, int64(500), u.Get("request_duration_ms").Int())
+
+	assert.Equal(t, map[string]string{
+		"module.worker[0].aws_instance.license_server": "Marketplace license fees aren't included.",
+		"module.worker[1].aws_instance.license_server": "Marketplace license fees aren't included.",
+	}, unsupported)
+
+	require.Len(t, project.Metadata.Warnings, 1, "notes of modules that are called many times should only be added once")
+	assert.Equal(t, schema.DiagModuleCostNote, project.Metadata.Warnings[0].Code)
+	assert.Equal(t, "Data transfer to the VPN peers isn't included.", project.Metadata.Warnings[0].Message)
+	assert.Equal(t, "./modules/worker", project.Metadata.Warnings[0].Data)
+
+	partials := []*schema.PartialResource{
+		{ResourceData: &schema.ResourceData{Address: "module.worker[0].aws_instance.license_server"}, Resource: &schema.Resource{Name: "module.worker[0].aws_instance.license_server"}},
+		{ResourceData: &schema.ResourceData{Address: "module.worker[0].aws_lambda_function.worker"}, Resource: &schema.Resource{Name: "module.worker[0].aws_lambda_function.worker"}},
+	}
+	markUnsupportedResources(partials, unsupported)
+
+	assert.True(t, partials[0].Resource.IsSkipped)
+	assert.Equal(t, "Marketplace license fees aren't included.", partials[0].Resource.SkipMessage)
+	assert.False(t, partials[1].Resource.IsSkipped)
+}
@@ -0,0 +1,159 @@
+package terraform
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	openTofuBinary       = "tofu"
+	openTofuRegistryHost = "registry.opentofu.org"
+	terraformRegistry    = "registry.terraform.io"
+	lockFileName         = ".terraform.lock.hcl"
+)
+
+// lookPath is used to find the Terraform and OpenTofu binaries, so it can be
+// replaced in tests.
+var lookPath = exec.LookPath
+
+// defaultBinary returns the binary used to run Terraform commands for the
+// project at path when no binary is configured. OpenTofu is used if the
+// project was initialized with OpenTofu, or if Terraform isn't installed.
+func defaultBinary(path string) string {
+	_, tofuErr := lookPath(openTofuBinary)
+	hasTofu := tofuErr == nil
+
+	if hasTofu && usesOpenTofu(path) {
+		return openTofuBinary
+	}
+
+	if _, err := lookPath(defaultTerraformBinary); err != nil && hasTofu {
+		return openTofuBinary
+	}
+
+	return defaultTerraformBinary
+}
+
+// usesOpenTofu returns true if the dependency lock file of the project at path,
+// or of the plan file at path, has providers from the OpenTofu registry.
+func usesOpenTofu(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	if !info.IsDir() {
+		if b, ok := planLockFile(path); ok {
+			return isOpenTofuLockFile(b)
+		}
+
+		path = filepath.Dir(path)
+	}
+
+	b, err := os.ReadFile(filepath.Join(path, lockFileName))
+	if err != nil {
+		return false
+	}
+
+	return isOpenTofuLockFile(b)
+}
+
+// planLockFile returns the dependency lock file that is included in a plan
+// file.
+func planLockFile(path string) ([]byte, bool) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, false
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.Name != lockFileName {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, false
+		}
+		defer rc.Close()
+
+		b, err := io.ReadAll(rc)
+		if err != nil {
+			return nil, false
+		}
+
+		return b, true
+	}
+
+	return nil, false
+}
+
+func isOpenTofuLockFile(b []byte) bool {
+	return strings.Contains(string(b), `provider "`+openTofuRegistryHost+"/")
+}
+
+// isOpenTofuBinary returns true if binary is the OpenTofu CLI.
+func isOpenTofuBinary(binary string) bool {
+	return strings.TrimSuffix(filepath.Base(binary), ".exe") == openTofuBinary
+}
+
+// isOpenTofuVersion returns true if the output of the version command is from
+// OpenTofu, e.g. OpenTofu v1.6.0.
+func isOpenTofuVersion(fullVersion string) bool {
+	return strings.HasPrefix(fullVersion, "OpenTofu ")
+}
+
+// isOpenTofuJSON returns true if the plan or state JSON was generated by
+// OpenTofu, which uses the OpenTofu registry for the provider addresses.
+// OpenTofu uses the same JSON format as Terraform, including the
+// terraform_version key, so the provider addresses are used to tell them apart.
+func isOpenTofuJSON(parsed gjson.Result) bool {
+	for _, conf := range parsed.Get("configuration.provider_config").Map() {
+		if strings.HasPrefix(conf.Get("full_name").String(), openTofuRegistryHost+"/") {
+			return true
+		}
+	}
+
+	for _, key := range []string{"planned_values.root_module", "values.root_module", "prior_state.values.root_module"} {
+		if moduleHasOpenTofuProvider(parsed.Get(key)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func moduleHasOpenTofuProvider(module gjson.Result) bool {
+	for _, r := range module.Get("resources").Array() {
+		if strings.HasPrefix(r.Get("provider_name").String(), openTofuRegistryHost+"/") {
+			return true
+		}
+	}
+
+	for _, m := range module.Get("child_modules").Array() {
+		if moduleHasOpenTofuProvider(m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeProviderName returns the provider address of OpenTofu providers as
+// the address of the Terraform provider they're forked from, e.g.
+// registry.opentofu.org/hashicorp/aws as registry.terraform.io/hashicorp/aws,
+// so resources are handled the same whichever tool generated the JSON.
+func normalizeProviderName(name string) string {
+	if strings.HasPrefix(name, openTofuRegistryHost+"/") {
+		return terraformRegistry + strings.TrimPrefix(name, openTofuRegistryHost)
+	}
+
+	return name
+}
@@ -0,0 +1,148 @@
+package terraform
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const openTofuLockFile = `provider "registry.opentofu.org/hashicorp/aws" {
+  version = "5.31.0"
+}
+`
+
+const terraformLockFile = `provider "registry.terraform.io/hashicorp/aws" {
+  version = "5.31.0"
+}
+`
+
+func TestDefaultBinary(t *testing.T) {
+	tofuDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tofuDir, lockFileName), []byte(openTofuLockFile), 0600))
+
+	terraformDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(terraformDir, lockFileName), []byte(terraformLockFile), 0600))
+
+	planPath := filepath.Join(t.TempDir(), "tfplan.binary")
+	writePlanFile(t, planPath, openTofuLockFile)
+
+	tests := []struct {
+		name     string
+		path     string
+		binaries []string
+		expected string
+	}{
+		{"OpenTofu project with both installed", tofuDir, []string{"terraform", "tofu"}, "tofu"},
+		{"OpenTofu project without tofu installed", tofuDir, []string{"terraform"}, "terraform"},
+		{"Terraform project with both installed", terraformDir, []string{"terraform", "tofu"}, "terraform"},
+		{"Terraform project with only tofu installed", terraformDir, []string{"tofu"}, "tofu"},
+		{"OpenTofu plan file", planPath, []string{"terraform", "tofu"}, "tofu"},
+		{"neither installed", terraformDir, nil, "terraform"},
+	}
+
+	orig := lookPath
+	defer func() { lookPath = orig }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookPath = fakeLookPath(tt.binaries...)
+			assert.Equal(t, tt.expected, defaultBinary(tt.path))
+		})
+	}
+}
+
+func fakeLookPath(binaries ...string) func(string) (string, error) {
+	return func(file string) (string, error) {
+		for _, b := range binaries {
+			if b == file {
+				return "/usr/bin/" + file, nil
+			}
+		}
+
+		return "", errors.New("not found")
+	}
+}
+
+func writePlanFile(t *testing.T, path string, lockFile string) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range map[string]string{"tfplan": "", lockFileName: lockFile} {
+		fw, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+}
+
+func TestIsOpenTofuJSON(t *testing.T) {
+	assert.True(t, isOpenTofuJSON(gjson.Parse(`{"configuration": {"provider_config": {"aws": {"name": "aws", "full_name": "registry.opentofu.org/hashicorp/aws"}}}}`)))
+	assert.True(t, isOpenTofuJSON(gjson.Parse(`{"values": {"root_module": {"child_modules": [{"resources": [{"provider_name": "registry.opentofu.org/hashicorp/aws"}]}]}}}`)))
+	assert.False(t, isOpenTofuJSON(gjson.Parse(`{"planned_values": {"root_module": {"resources": [{"provider_name": "registry.terraform.io/hashicorp/aws"}]}}}`)))
+}
+
+func TestCheckTerraformVersionOpenTofu(t *testing.T) {
+	assert.NoError(t, checkTerraformVersion(shortTerraformVersion("OpenTofu v1.6.0"), "OpenTofu v1.6.0"))
+	assert.True(t, isOpenTofuVersion("OpenTofu v1.6.0"))
+	assert.False(t, isOpenTofuVersion("Terraform v1.6.0"))
+	assert.True(t, isOpenTofuBinary("/usr/local/bin/tofu"))
+	assert.False(t, isOpenTofuBinary("terraform"))
+}
+
+func TestParseJSONOpenTofu(t *testing.T) {
+	j := []byte(`{
+		"format_version": "1.2",
+		"terraform_version": "1.6.0",
+		"planned_values": {
+			"root_module": {
+				"resources": [
+					{
+						"address": "aws_instance.web",
+						"mode": "managed",
+						"type": "aws_instance",
+						"name": "web",
+						"provider_name": "registry.opentofu.org/hashicorp/aws",
+						"values": {"instance_type": "t3.micro"}
+					}
+				]
+			}
+		},
+		"configuration": {
+			"provider_config": {
+				"aws": {
+					"name": "aws",
+					"full_name": "registry.opentofu.org/hashicorp/aws",
+					"expressions": {"region": {"constant_value": "eu-west-1"}}
+				}
+			},
+			"root_module": {
+				"resources": [
+					{"address": "aws_instance.web", "mode": "managed", "type": "aws_instance", "name": "web", "provider_config_key": "aws"}
+				]
+			}
+		}
+	}`)
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{})
+	_, resources, err := NewParser(ctx, false).parseJSON(j, schema.UsageMap{})
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+
+	d := resources[0].ResourceData
+	assert.Equal(t, "registry.terraform.io/hashicorp/aws", d.ProviderName)
+	assert.Equal(t, "eu-west-1", d.Get("region").String())
+	assert.Equal(t, true, ctx.ContextValues()["isOpenTofu"])
+}
@@ -0,0 +1,28 @@
+package terraform
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// OrphanedResources returns the partial resources of the state project that
+// have no matching resource address in any of the HCL projects. These are
+// resources that have been removed from the code, or were never defined in it,
+// but still exist in the Terraform state and are costing money until they are
+// destroyed.
+func OrphanedResources(hclProjects []*schema.Project, state *schema.Project) []*schema.PartialResource {
+	addresses := make(map[string]struct{})
+	for _, project := range hclProjects {
+		for _, partial := range project.PartialResources {
+			addresses[partial.ResourceData.Address] = struct{}{}
+		}
+	}
+
+	var orphaned []*schema.PartialResource
+	for _, partial := range state.PartialResources {
+		if _, ok := addresses[partial.ResourceData.Address]; !ok {
+			orphaned = append(orphaned, partial)
+		}
+	}
+
+	return orphaned
+}
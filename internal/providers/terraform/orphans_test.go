@@ -0,0 +1,42 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func newOrphanTestPartialResource(address string) *schema.PartialResource {
+	return &schema.PartialResource{ResourceData: &schema.ResourceData{Address: address}}
+}
+
+func TestOrphanedResources(t *testing.T) {
+	hclProjects := []*schema.Project{
+		{PartialResources: []*schema.PartialResource{
+			newOrphanTestPartialResource("aws_instance.web"),
+			newOrphanTestPartialResource("module.db.aws_db_instance.db"),
+		}},
+		{PartialResources: []*schema.PartialResource{
+			newOrphanTestPartialResource("aws_nat_gateway.nat[0]"),
+		}},
+	}
+
+	state := &schema.Project{PartialResources: []*schema.PartialResource{
+		newOrphanTestPartialResource("aws_instance.web"),
+		newOrphanTestPartialResource("aws_instance.old"),
+		newOrphanTestPartialResource("module.db.aws_db_instance.db"),
+		newOrphanTestPartialResource("aws_nat_gateway.nat[0]"),
+		newOrphanTestPartialResource("aws_nat_gateway.nat[1]"),
+	}}
+
+	orphaned := OrphanedResources(hclProjects, state)
+
+	addresses := make([]string, 0, len(orphaned))
+	for _, partial := range orphaned {
+		addresses = append(addresses, partial.ResourceData.Address)
+	}
+
+	assert.Equal(t, []string{"aws_instance.old", "aws_nat_gateway.nat[1]"}, addresses)
+}
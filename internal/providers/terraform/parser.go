@@ -20,7 +20,8 @@ import (
 	"github.com/infracost/infracost/internal/schema"
 )
 
-// These show differently in the plan JSON for Terraform 0.12 and 0.13.
+// These show differently in the plan JSON for Terraform 0.12 and 0.13. The
+// provider names of OpenTofu are normalized to these before they're checked.
 var infracostProviderNames = []string{"infracost", "registry.terraform.io/infracost/infracost"}
 
 type Parser struct {
@@ -37,7 +38,7 @@ func NewParser(ctx *config.ProjectContext, includePastResources bool) *Parser {
 }
 
 func (p *Parser) createPartialResource(d *schema.ResourceData, u *schema.UsageData) *schema.PartialResource {
-	registryMap := GetResourceRegistryMap()
+	registryMap := GetRunResourceRegistryMap(p.ctx.RunContext)
 
 	for cKey, cValue := range getSpecialContext(d) {
 		p.ctx.SetContextValue(cKey, cValue)
@@ -57,23 +58,31 @@ func (p *Parser) createPartialResource(d *schema.ResourceData, u *schema.UsageDa
 			}
 		}
 
+		// Record the keys that the resource reads so resources with costs
+		// that depend on unknown values can be flagged.
+		if len(d.UnknownKeys) > 0 {
+			d.TrackAccess()
+		}
+
 		// Use the CoreRFunc to generate a CoreResource if possible.  This is
 		// the new/preferred way to create provider-agnostic resources that
 		// support advanced features such as Infracost Cloud usage estimates
 		// and actual costs.
 		if registryItem.CoreRFunc != nil {
 			coreRes := registryItem.CoreRFunc(d)
+			d.StopTracking()
 			if coreRes != nil {
 				return &schema.PartialResource{ResourceData: d, CoreResource: coreRes, CloudResourceIDs: registryItem.CloudResourceIDFunc(d)}
 			}
 		} else {
 			res := registryItem.RFunc(d, u)
+			d.StopTracking()
 			if res != nil {
 				if u != nil {
 					res.EstimationSummary = u.CalcEstimationSummary()
 				}
 
-				return &schema.PartialResource{ResourceData: d, Resource: res, CloudResourceIDs: registryItem.CloudResourceIDFunc(d)}
+				return &schema.PartialResource{ResourceData: d, Resource: res, ResourceFunc: registryItem.RFunc, CloudResourceIDs: registryItem.CloudResourceIDFunc(d)}
 			}
 		}
 	}
@@ -106,8 +115,12 @@ func (p *Parser) parseJSONResources(parsePrior bool, baseResources []*schema.Par
 	}
 
 	resData := p.parseResourceData(isState, providerConf, vals, conf, vars)
+	if !isState {
+		setUnknownKeys(resData, parsed.Get("resource_changes"))
+	}
 
 	p.parseReferences(resData, conf)
+	setPricingHints(resData, parsed, conf)
 	p.stripDataResources(resData)
 	p.populateUsageData(resData, usage)
 
@@ -140,6 +153,9 @@ func (p *Parser) parseJSON(j []byte, usage schema.UsageMap) ([]*schema.PartialRe
 	parsed := gjson.ParseBytes(j)
 
 	p.terraformVersion = parsed.Get("terraform_version").String()
+	if isOpenTofuJSON(parsed) {
+		p.ctx.SetContextValue("isOpenTofu", true)
+	}
 	providerConf := parsed.Get("configuration.provider_config")
 	conf := parsed.Get("configuration.root_module")
 	vars := parsed.Get("variables")
@@ -232,7 +248,7 @@ func (p *Parser) parseResourceData(isState bool, providerConf, planVals gjson.Re
 
 	for _, r := range planVals.Get("resources").Array() {
 		t := r.Get("type").String()
-		provider := r.Get("provider_name").String()
+		provider := normalizeProviderName(r.Get("provider_name").String())
 		addr := r.Get("address").String()
 
 		// Terraform v0.12 files have a different format for the addresses of provisioned resources
@@ -275,6 +291,10 @@ func (p *Parser) parseResourceData(isState bool, providerConf, planVals gjson.Re
 
 		data := schema.NewResourceData(t, provider, addr, tags, v)
 		data.Metadata = r.Get("infracost_metadata").Map()
+		setCostScope(data, providerConf, vars, resConf)
+		if region != "" {
+			data.Metadata[schema.RegionMetadataKey] = gjson.Parse(strconv.Quote(region))
+		}
 		resources[addr] = data
 	}
 
@@ -288,6 +308,57 @@ func (p *Parser) parseResourceData(isState bool, providerConf, planVals gjson.Re
 	return resources
 }
 
+// setUnknownKeys sets the UnknownKeys of the resources, which are the values
+// that are unknown until the plan is applied, and the values of Terraform
+// directories that have mocked values as they depend on values that couldn't be
+// evaluated, e.g. from data sources or the outputs of other projects.
+func setUnknownKeys(resData map[string]*schema.ResourceData, resourceChanges gjson.Result) {
+	afterUnknown := make(map[string]gjson.Result)
+	for _, rc := range resourceChanges.Array() {
+		afterUnknown[rc.Get("address").String()] = rc.Get("change.after_unknown")
+	}
+
+	for addr, d := range resData {
+		var keys []string
+		collectUnknownKeys("", afterUnknown[addr], func(v gjson.Result) bool {
+			return v.Type == gjson.True
+		}, &keys)
+		collectUnknownKeys("", d.RawValues, func(v gjson.Result) bool {
+			return v.Type == gjson.String && strings.Contains(v.String(), "mock")
+		}, &keys)
+
+		d.UnknownKeys = keys
+	}
+}
+
+// collectUnknownKeys adds the paths of the values within v that are unknown
+// to keys.
+func collectUnknownKeys(prefix string, v gjson.Result, isUnknown func(gjson.Result) bool, keys *[]string) {
+	if v.IsObject() || v.IsArray() {
+		i := 0
+		v.ForEach(func(k, child gjson.Result) bool {
+			key := k.String()
+			if v.IsArray() {
+				key = strconv.Itoa(i)
+				i++
+			}
+
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+
+			collectUnknownKeys(key, child, isUnknown, keys)
+			return true
+		})
+
+		return
+	}
+
+	if prefix != "" && isUnknown(v) {
+		*keys = append(*keys, prefix)
+	}
+}
+
 func getSpecialContext(d *schema.ResourceData) map[string]interface{} {
 	providerPrefix := getProviderPrefix(d.Type)
 
@@ -413,29 +484,37 @@ func parseProviderKey(resConf gjson.Result) string {
 }
 
 func parseRegion(providerConf gjson.Result, vars gjson.Result, providerKey string) string {
+	region := providerConfValue(providerConf, vars, providerKey, "region")
+
+	if strings.Contains(region, "mock") {
+		return ""
+	}
+
+	return region
+}
+
+// providerConfValue returns the value of an attribute of the provider, either
+// set as a constant or referencing a variable.
+func providerConfValue(providerConf gjson.Result, vars gjson.Result, providerKey string, attr string) string {
 	// Try to get constant value
-	region := providerConf.Get(fmt.Sprintf("%s.expressions.region.constant_value", gjsonEscape(providerKey))).String()
-	if region == "" {
+	value := providerConf.Get(fmt.Sprintf("%s.expressions.%s.constant_value", gjsonEscape(providerKey), attr)).String()
+	if value == "" {
 		// Try to get reference
-		refName := providerConf.Get(fmt.Sprintf("%s.expressions.region.references.0", gjsonEscape(providerKey))).String()
+		refName := providerConf.Get(fmt.Sprintf("%s.expressions.%s.references.0", gjsonEscape(providerKey), attr)).String()
 		splitRef := strings.Split(refName, ".")
 
 		if splitRef[0] == "var" {
-			// Get the region from variables
+			// Get the value from variables
 			varName := strings.Join(splitRef[1:], ".")
 			varContent := vars.Get(fmt.Sprintf("%s.value", varName))
 
 			if !varContent.IsObject() && !varContent.IsArray() {
-				region = varContent.String()
+				value = varContent.String()
 			}
 		}
 	}
 
-	if strings.Contains(region, "mock") {
-		return ""
-	}
-
-	return region
+	return value
 }
 
 func (p *Parser) stripDataResources(resData map[string]*schema.ResourceData) {
@@ -447,7 +526,7 @@ func (p *Parser) stripDataResources(resData map[string]*schema.ResourceData) {
 }
 
 func (p *Parser) parseReferences(resData map[string]*schema.ResourceData, conf gjson.Result) {
-	registryMap := GetResourceRegistryMap()
+	registryMap := GetRunResourceRegistryMap(p.ctx.RunContext)
 
 	// Create a map of id -> resource data so we can lookup references
 	idMap := make(map[string][]*schema.ResourceData)
@@ -439,6 +439,84 @@ func TestParseJSONResources(t *testing.T) {
 	}
 }
 
+func TestParseJSONResourcesUnknownInputs(t *testing.T) {
+	testData := `{
+		"format_version": "0.1",
+		"terraform_version": "1.5.0",
+		"planned_values": {
+			"root_module": {
+				"resources": [
+					{
+						"address": "aws_instance.web",
+						"mode": "managed",
+						"type": "aws_instance",
+						"name": "web",
+						"provider_name": "registry.terraform.io/hashicorp/aws",
+						"values": {
+							"ami": "ami-674cbc1e",
+							"instance_type": "instance_type-mock",
+							"root_block_device": [{"volume_type": "gp3"}],
+							"tags": {"Name": "name-mock"}
+						}
+					},
+					{
+						"address": "aws_instance.app",
+						"mode": "managed",
+						"type": "aws_instance",
+						"name": "app",
+						"provider_name": "registry.terraform.io/hashicorp/aws",
+						"values": {
+							"ami": "ami-674cbc1e",
+							"instance_type": "t3.medium"
+						}
+					}
+				]
+			}
+		},
+		"resource_changes": [
+			{
+				"address": "aws_instance.web",
+				"change": {
+					"actions": ["create"],
+					"after_unknown": {
+						"arn": true,
+						"id": true,
+						"root_block_device": [{"volume_size": true}]
+					}
+				}
+			},
+			{
+				"address": "aws_instance.app",
+				"change": {
+					"actions": ["create"],
+					"after_unknown": {"arn": true}
+				}
+			}
+		],
+		"configuration": {
+			"provider_config": {
+				"aws": {"name": "aws", "expressions": {"region": {"constant_value": "us-east-1"}}}
+			},
+			"root_module": {}
+		}
+	}`
+
+	parsed := gjson.Parse(testData)
+
+	p := NewParser(config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{}), true)
+
+	partials := p.parseJSONResources(false, nil, schema.NewUsageMapFromInterface(nil), parsed, parsed.Get("configuration.provider_config"), parsed.Get("configuration.root_module"), parsed.Get("variables"))
+
+	actual := make(map[string][]string)
+	for _, partial := range partials {
+		r := schema.BuildResource(partial, nil)
+		actual[r.Name] = r.UnknownInputs
+	}
+
+	assert.Equal(t, []string{"instance_type", "root_block_device.0.volume_size"}, actual["aws_instance.web"])
+	assert.Empty(t, actual["aws_instance.app"])
+}
+
 func TestCreateResource(t *testing.T) {
 	tests := []struct {
 		data     *schema.ResourceData
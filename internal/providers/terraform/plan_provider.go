@@ -118,7 +118,7 @@ func (p *PlanProvider) generatePlanJSON() ([]byte, error) {
 		defer os.Remove(opts.TerraformConfigFile)
 	}
 
-	spinner := ui.NewSpinner("Running terraform show", p.spinnerOpts)
+	spinner := ui.NewSpinner(fmt.Sprintf("Running %s show", p.cliName()), p.spinnerOpts)
 	defer spinner.Fail()
 
 	j, err := p.runShow(opts, spinner, planPath, false)
@@ -1,8 +1,12 @@
 package terraform
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
+	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/schema"
 
 	"github.com/infracost/infracost/internal/providers/terraform/aws"
@@ -15,6 +19,10 @@ type ResourceRegistryMap map[string]*schema.RegistryItem
 var (
 	resourceRegistryMap ResourceRegistryMap
 	once                sync.Once
+
+	// builtInResourceTypes are the resource types that Infracost supports,
+	// which can't be replaced by WithResources.
+	builtInResourceTypes map[string]bool
 )
 
 func GetResourceRegistryMap() *ResourceRegistryMap {
@@ -54,11 +62,104 @@ func GetResourceRegistryMap() *ResourceRegistryMap {
 		for _, registryItem := range createFreeResources(google.FreeResources, google.GetDefaultRefIDFunc, google.DefaultCloudResourceIDFunc) {
 			resourceRegistryMap[registryItem.Name] = registryItem
 		}
+
+		builtInResourceTypes = make(map[string]bool, len(resourceRegistryMap))
+		for name := range resourceRegistryMap {
+			builtInResourceTypes[name] = true
+		}
 	})
 
 	return &resourceRegistryMap
 }
 
+// GetRunResourceRegistryMap returns the registry of the resource types that are
+// built in the run, which has the resource types registered for the run with
+// WithResources as well as the built-in ones.
+func GetRunResourceRegistryMap(ctx *config.RunContext) *ResourceRegistryMap {
+	if ctx == nil || ctx.ResourceRegistry == nil {
+		return GetResourceRegistryMap()
+	}
+
+	registryMap := ResourceRegistryMap(ctx.ResourceRegistry)
+	return &registryMap
+}
+
+// WithResources returns a copy of the registry with the registry items of
+// resources that aren't built in, e.g. from plugins, replacing any items of
+// the same types. It returns an error if any of the items are of built-in
+// resource types. The registry isn't changed, so the items are only used by
+// runs that are given the returned registry, see GetRunResourceRegistryMap.
+func (r ResourceRegistryMap) WithResources(items []*schema.RegistryItem) (ResourceRegistryMap, error) {
+	GetResourceRegistryMap()
+
+	var builtIn []string
+	for _, item := range items {
+		if builtInResourceTypes[item.Name] {
+			builtIn = append(builtIn, item.Name)
+		}
+	}
+
+	if len(builtIn) > 0 {
+		sort.Strings(builtIn)
+		return nil, fmt.Errorf("built-in resource types can't be replaced: %s", strings.Join(builtIn, ", "))
+	}
+
+	registryMap := make(ResourceRegistryMap, len(r)+len(items))
+	for name, item := range r {
+		registryMap[name] = item
+	}
+
+	for _, item := range items {
+		if item.CloudResourceIDFunc == nil {
+			item.CloudResourceIDFunc = func(d *schema.ResourceData) []string { return nil }
+		}
+
+		registryMap[item.Name] = item
+	}
+
+	return registryMap, nil
+}
+
+var (
+	dataSourceResolvers     map[string]schema.DataSourceResolver
+	dataSourceResolversOnce sync.Once
+	dataSourceResolversMu   sync.RWMutex
+)
+
+func initDataSourceResolvers() {
+	dataSourceResolversOnce.Do(func() {
+		dataSourceResolvers = make(map[string]schema.DataSourceResolver)
+
+		for _, resolvers := range []map[string]schema.DataSourceResolver{aws.DataSourceResolvers, azure.DataSourceResolvers} {
+			for dataSourceType, resolver := range resolvers {
+				dataSourceResolvers[dataSourceType] = resolver
+			}
+		}
+	})
+}
+
+// RegisterDataSourceResolver adds the resolver of the pricing hints of a data
+// source type, replacing any existing resolver of the type.
+func RegisterDataSourceResolver(dataSourceType string, resolver schema.DataSourceResolver) {
+	initDataSourceResolvers()
+
+	dataSourceResolversMu.Lock()
+	defer dataSourceResolversMu.Unlock()
+
+	dataSourceResolvers[dataSourceType] = resolver
+}
+
+// GetDataSourceResolver returns the resolver of the pricing hints of the data
+// source type, or nil if the type has none.
+func GetDataSourceResolver(dataSourceType string) schema.DataSourceResolver {
+	initDataSourceResolvers()
+
+	dataSourceResolversMu.RLock()
+	defer dataSourceResolversMu.RUnlock()
+
+	return dataSourceResolvers[dataSourceType]
+}
+
 func (r *ResourceRegistryMap) GetReferenceAttributes(resourceDataType string) []string {
 	var refAttrs []string
 	item, ok := (*r)[resourceDataType]
@@ -125,6 +125,9 @@ type terragruntWorkingDirInfo struct {
 	workingDir string
 	provider   *HCLProvider
 	error      error
+	// warnings are added to the projects of the working dir, e.g. for
+	// dependencies that couldn't be evaluated and whose outputs are mocked.
+	warnings []schema.ProjectDiag
 }
 
 // LoadResources finds any Terragrunt projects, prepares them by downloading any required source files, then
@@ -190,6 +193,7 @@ func (p *TerragruntHCLProvider) LoadResources(usage schema.UsageMap) ([]*schema.
 					}
 
 					metadata := p.newProjectMetadata(projectPath)
+					metadata.Warnings = append(metadata.Warnings, di.warnings...)
 					project.Metadata = metadata
 					project.Name = p.generateProjectName(metadata)
 					mu.Lock()
@@ -446,7 +450,14 @@ func (p *TerragruntHCLProvider) filterExcludedPaths(paths []string) []string {
 //     these for further runTerragrunt calls that use the dependency outputs.
 func (p *TerragruntHCLProvider) runTerragrunt(opts *tgoptions.TerragruntOptions) (info *terragruntWorkingDirInfo) {
 	info = &terragruntWorkingDirInfo{configDir: opts.WorkingDir, workingDir: opts.WorkingDir}
-	outputs := p.fetchDependencyOutputs(opts)
+	outputs, err := p.fetchDependencyOutputs(opts)
+	if err != nil {
+		info.warnings = append(info.warnings, schema.ProjectDiag{
+			Code:    schema.DiagTerragruntDependencyEvaluationFailure,
+			Message: err.Error(),
+		})
+	}
+
 	terragruntConfig, err := tgconfig.ParseConfigFile(opts.TerragruntConfigPath, opts, nil, &outputs)
 	if err != nil {
 		info.error = err
@@ -512,7 +523,7 @@ func (p *TerragruntHCLProvider) runTerragrunt(opts *tgoptions.TerragruntOptions)
 		}
 
 		if updatedTerragruntOptions != nil && updatedTerragruntOptions.WorkingDir != "" {
-			info = &terragruntWorkingDirInfo{configDir: opts.WorkingDir, workingDir: updatedTerragruntOptions.WorkingDir}
+			info.workingDir = updatedTerragruntOptions.WorkingDir
 		}
 	}
 
@@ -621,16 +632,19 @@ var (
 	mapRegexp   = regexp.MustCompile(`\["([\w\d]+)"]`)
 )
 
-func (p *TerragruntHCLProvider) fetchDependencyOutputs(opts *tgoptions.TerragruntOptions) cty.Value {
-	moduleOutputs, err := p.fetchModuleOutputs(opts)
-	if err != nil {
-		p.logger.WithError(err).Debug("failed to fetch real module outputs, defaulting to mocked outputs from file regexp")
+// fetchDependencyOutputs returns the outputs of the dependencies of the Terragrunt file provided in the opts
+// input, with mocked values for the outputs that are used by the file but couldn't be fetched. The error is
+// the error of the dependencies that couldn't be evaluated, whose outputs are all mocked.
+func (p *TerragruntHCLProvider) fetchDependencyOutputs(opts *tgoptions.TerragruntOptions) (cty.Value, error) {
+	moduleOutputs, moduleErr := p.fetchModuleOutputs(opts)
+	if moduleErr != nil {
+		p.logger.WithError(moduleErr).Debug("failed to fetch real module outputs, defaulting to mocked outputs from file regexp")
 	}
 
 	file, err := os.Open(opts.TerragruntConfigPath)
 	if err != nil {
 		p.logger.WithError(err).Debug("could not open Terragrunt file for dependency regexps")
-		return moduleOutputs
+		return moduleOutputs, moduleErr
 	}
 
 	var matches []string
@@ -660,7 +674,7 @@ func (p *TerragruntHCLProvider) fetchDependencyOutputs(opts *tgoptions.Terragrun
 	}
 
 	if len(matches) == 0 {
-		return moduleOutputs
+		return moduleOutputs, moduleErr
 	}
 
 	valueMap := moduleOutputs.AsValueMap()
@@ -670,7 +684,7 @@ func (p *TerragruntHCLProvider) fetchDependencyOutputs(opts *tgoptions.Terragrun
 		valueMap = mergeObjectWithDependencyMap(valueMap, pieces[1:])
 	}
 
-	return cty.ObjectVal(valueMap)
+	return cty.ObjectVal(valueMap), moduleErr
 }
 
 func mergeObjectWithDependencyMap(valueMap map[string]cty.Value, pieces []string) map[string]cty.Value {
@@ -843,7 +857,7 @@ func (p *TerragruntHCLProvider) fetchModuleOutputs(opts *tgoptions.TerragruntOpt
 				if !evaluated {
 					info := p.runTerragrunt(opts.Clone(dir))
 					if info != nil && info.error != nil {
-						return outputs, fmt.Errorf("could not evaluate dependency %s at dir %s err: %w", dep.Name, dir, err)
+						return outputs, fmt.Errorf("could not evaluate dependency %s at dir %s err: %w", dep.Name, dir, info.error)
 					}
 
 					value = p.outputs[dir]
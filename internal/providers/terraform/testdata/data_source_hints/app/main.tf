@@ -0,0 +1,13 @@
+data "aws_ami" "rhel" {
+  owners = ["309956199498"]
+
+  filter {
+    name   = "name"
+    values = ["RHEL-9.2.0_HVM-*"]
+  }
+}
+
+resource "aws_instance" "app" {
+  ami           = data.aws_ami.rhel.id
+  instance_type = "t3.medium"
+}
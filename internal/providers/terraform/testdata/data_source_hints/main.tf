@@ -0,0 +1,38 @@
+provider "aws" {
+  region = "us-east-1"
+}
+
+data "aws_ami" "windows" {
+  most_recent = true
+  owners      = ["amazon"]
+
+  filter {
+    name   = "name"
+    values = ["Windows_Server-2022-English-Full-Base-*"]
+  }
+}
+
+data "aws_ami" "ubuntu" {
+  most_recent = true
+  owners      = ["099720109477"]
+  name_regex  = "^ubuntu/images/hvm-ssd/ubuntu-jammy-22.04-amd64-server-.*"
+}
+
+resource "aws_instance" "windows" {
+  ami           = data.aws_ami.windows.id
+  instance_type = "t3.medium"
+}
+
+resource "aws_instance" "ubuntu" {
+  ami           = data.aws_ami.ubuntu.id
+  instance_type = "t3.medium"
+}
+
+resource "aws_instance" "fixed" {
+  ami           = "ami-674cbc1e"
+  instance_type = "t3.medium"
+}
+
+module "app" {
+  source = "./app"
+}
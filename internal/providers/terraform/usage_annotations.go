@@ -0,0 +1,265 @@
+package terraform
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/infracost/infracost/internal/hcl"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// usageAnnotationPrefix is the prefix of a Terraform comment that defines usage for the
+// resource that directly follows the comment, e.g:
+//
+//	# infracost-usage: monthly_requests=5000000 request_duration_ms=300
+//	resource "aws_lambda_function" "hello_world" {
+const usageAnnotationPrefix = "infracost-usage:"
+
+// ignoreAnnotation is a Terraform comment that excludes the resource that directly
+// follows the comment from the costs, optionally with the reason after a colon, e.g:
+//
+//	# infracost-ignore: billed to the shared services account
+//	resource "aws_nat_gateway" "shared" {
+const ignoreAnnotation = "infracost-ignore"
+
+// defaultIgnoreReason is the skip message of resources that are ignored without a reason.
+const defaultIgnoreReason = "Ignored with an infracost-ignore comment"
+
+// usageAnnotations parses usage annotation comments from Terraform files. It caches the
+// file contents so that files are only read once no matter how many blocks they hold.
+type usageAnnotations struct {
+	files map[string][]string
+}
+
+func newUsageAnnotations() *usageAnnotations {
+	return &usageAnnotations{files: map[string][]string{}}
+}
+
+// mergeUsage returns a copy of usage which includes the usage annotations for all the
+// resources in module and its child modules. Values from the usage file take precedence
+// over any values defined in annotations.
+func (a *usageAnnotations) mergeUsage(module *hcl.Module, usage schema.UsageMap) schema.UsageMap {
+	annotated := map[string]*schema.UsageData{}
+	a.collect(module, annotated, map[string]string{})
+
+	return mergeModuleUsage(usage, annotated)
+}
+
+// mergeModuleUsage returns a copy of usage which includes the usage that modules
+// define for their resources, by address. Values from usage take precedence.
+func mergeModuleUsage(usage schema.UsageMap, moduleUsage map[string]*schema.UsageData) schema.UsageMap {
+	if len(moduleUsage) == 0 {
+		return usage
+	}
+
+	data := make(map[string]*schema.UsageData, len(usage.Data())+len(moduleUsage))
+	for k, v := range usage.Data() {
+		data[k] = v
+	}
+
+	for address, u := range moduleUsage {
+		data[address] = usage.Get(address).Merge(u)
+	}
+
+	return schema.NewUsageMap(data)
+}
+
+// collect adds the usage annotations of the resources in module and its child modules
+// to annotated, and the reasons of the resources that are ignored to ignored, by address.
+func (a *usageAnnotations) collect(module *hcl.Module, annotated map[string]*schema.UsageData, ignored map[string]string) {
+	for _, block := range module.Blocks {
+		if block.Type() != "resource" {
+			continue
+		}
+
+		address := block.FullName()
+
+		if reason, ok := a.ignoreReason(block.Filename, block.StartLine()); ok {
+			ignored[address] = reason
+		}
+
+		attrs := a.forBlock(block.Filename, block.StartLine())
+		if len(attrs) == 0 {
+			continue
+		}
+
+		annotated[address] = schema.NewUsageData(address, schema.ParseAttributes(attrs))
+	}
+
+	for _, m := range module.Modules {
+		a.collect(m, annotated, ignored)
+	}
+}
+
+// forBlock returns the usage defined in the comments directly above the given line.
+func (a *usageAnnotations) forBlock(filename string, line int) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	for _, comment := range a.comments(filename, line) {
+		for k, v := range parseUsageAnnotation(comment) {
+			if _, exists := attrs[k]; !exists {
+				attrs[k] = v
+			}
+		}
+	}
+
+	return attrs
+}
+
+// ignoreReason returns the reason of the ignore annotation in the comments directly above
+// the given line, and true if there is one.
+func (a *usageAnnotations) ignoreReason(filename string, line int) (string, bool) {
+	for _, comment := range a.comments(filename, line) {
+		if reason, ok := parseIgnoreAnnotation(comment); ok {
+			return reason, true
+		}
+	}
+
+	return "", false
+}
+
+// comments returns the text of the comments directly above the given line, closest first.
+// Annotations are read from the contiguous comment lines before the block definition,
+// a blank or non-comment line ends the search.
+func (a *usageAnnotations) comments(filename string, line int) []string {
+	lines := a.lines(filename)
+	if line < 2 || line-1 > len(lines) {
+		return nil
+	}
+
+	var comments []string
+	for i := line - 2; i >= 0; i-- {
+		comment, ok := commentText(lines[i])
+		if !ok {
+			break
+		}
+
+		comments = append(comments, comment)
+	}
+
+	return comments
+}
+
+// markIgnoredResources replaces the partial resources that are ignored with annotations
+// with resources that are skipped and have no price, so they're excluded from the costs
+// and not reported as unsupported.
+func markIgnoredResources(partials []*schema.PartialResource, ignored map[string]string) {
+	if len(ignored) == 0 {
+		return
+	}
+
+	for i, partial := range partials {
+		if partial.ResourceData == nil {
+			continue
+		}
+
+		reason, ok := ignored[partial.ResourceData.Address]
+		if !ok {
+			continue
+		}
+
+		partials[i] = &schema.PartialResource{
+			ResourceData: partial.ResourceData,
+			Resource: &schema.Resource{
+				Name:        partial.ResourceData.Address,
+				IsSkipped:   true,
+				NoPrice:     true,
+				SkipMessage: reason,
+			},
+		}
+	}
+}
+
+func (a *usageAnnotations) lines(filename string) []string {
+	if lines, ok := a.files[filename]; ok {
+		return lines
+	}
+
+	var lines []string
+	f, err := os.Open(filename)
+	if err == nil {
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+	}
+
+	a.files[filename] = lines
+	return lines
+}
+
+// commentText returns the text of a single line comment and true if line is a comment.
+func commentText(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+
+	for _, prefix := range []string{"#", "//"} {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, prefix)), true
+		}
+	}
+
+	return "", false
+}
+
+// parseUsageAnnotation parses the key=value pairs of a usage annotation comment. Values
+// are parsed as numbers or booleans where possible, otherwise they are kept as strings.
+func parseUsageAnnotation(comment string) map[string]interface{} {
+	if !strings.HasPrefix(comment, usageAnnotationPrefix) {
+		return nil
+	}
+
+	attrs := map[string]interface{}{}
+	for _, field := range strings.Fields(strings.TrimPrefix(comment, usageAnnotationPrefix)) {
+		pieces := strings.SplitN(field, "=", 2)
+		if len(pieces) != 2 || pieces[0] == "" {
+			continue
+		}
+
+		attrs[pieces[0]] = parseUsageAnnotationValue(pieces[1])
+	}
+
+	return attrs
+}
+
+// parseIgnoreAnnotation returns the reason of an ignore annotation comment and true if
+// comment is one. The reason defaults to defaultIgnoreReason.
+func parseIgnoreAnnotation(comment string) (string, bool) {
+	rest := strings.TrimPrefix(comment, ignoreAnnotation)
+	if rest == comment {
+		return "", false
+	}
+
+	if rest == "" {
+		return defaultIgnoreReason, true
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return "", false
+	}
+
+	reason := strings.TrimSpace(strings.TrimPrefix(rest, ":"))
+	if reason == "" {
+		return defaultIgnoreReason, true
+	}
+
+	return reason, true
+}
+
+func parseUsageAnnotationValue(v string) interface{} {
+	if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return i
+	}
+
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		return f
+	}
+
+	if b, err := strconv.ParseBool(v); err == nil {
+		return b
+	}
+
+	return strings.Trim(v, `"'`)
+}
@@ -0,0 +1,127 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestParseUsageAnnotation(t *testing.T) {
+	tests := []struct {
+		comment  string
+		expected map[string]interface{}
+	}{
+		{comment: "not an annotation", expected: nil},
+		{comment: "infracost-usage: monthly_requests=5000000", expected: map[string]interface{}{"monthly_requests": int64(5000000)}},
+		{
+			comment: "infracost-usage: request_duration_ms=300.5 operating_system=linux enabled=true invalid",
+			expected: map[string]interface{}{
+				"request_duration_ms": 300.5,
+				"operating_system":    "linux",
+				"enabled":             true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.comment, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseUsageAnnotation(tt.comment))
+		})
+	}
+}
+
+func TestUsageAnnotationsForBlock(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "main.tf")
+	err := os.WriteFile(filename, []byte(`# infracost-usage: monthly_requests=1
+resource "aws_lambda_function" "ignored" {
+}
+
+# Lambda used for processing uploads
+# infracost-usage: monthly_requests=5000000
+// infracost-usage: request_duration_ms=300 monthly_requests=100
+resource "aws_lambda_function" "annotated" {
+}
+`), 0600)
+	require.NoError(t, err)
+
+	a := newUsageAnnotations()
+
+	assert.Equal(t, map[string]interface{}{"monthly_requests": int64(1)}, a.forBlock(filename, 2))
+	assert.Equal(t, map[string]interface{}{
+		"monthly_requests":    int64(100),
+		"request_duration_ms": int64(300),
+	}, a.forBlock(filename, 8))
+	assert.Empty(t, a.forBlock(filename, 1))
+}
+
+func TestParseIgnoreAnnotation(t *testing.T) {
+	tests := []struct {
+		comment  string
+		expected string
+		ok       bool
+	}{
+		{comment: "not an annotation"},
+		{comment: "infracost-ignored"},
+		{comment: "infracost-ignore", expected: defaultIgnoreReason, ok: true},
+		{comment: "infracost-ignore:", expected: defaultIgnoreReason, ok: true},
+		{comment: "infracost-ignore: billed to the shared account", expected: "billed to the shared account", ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.comment, func(t *testing.T) {
+			reason, ok := parseIgnoreAnnotation(tt.comment)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, reason)
+		})
+	}
+}
+
+func TestUsageAnnotationsIgnoreReason(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "main.tf")
+	err := os.WriteFile(filename, []byte(`# infracost-ignore
+resource "aws_nat_gateway" "ignored" {
+}
+
+# infracost-ignore: billed to the shared account
+# infracost-usage: monthly_data_processed_gb=100
+resource "aws_nat_gateway" "shared" {
+}
+
+resource "aws_nat_gateway" "priced" {
+}
+`), 0600)
+	require.NoError(t, err)
+
+	a := newUsageAnnotations()
+
+	reason, ok := a.ignoreReason(filename, 2)
+	assert.True(t, ok)
+	assert.Equal(t, defaultIgnoreReason, reason)
+
+	reason, ok = a.ignoreReason(filename, 7)
+	assert.True(t, ok)
+	assert.Equal(t, "billed to the shared account", reason)
+
+	_, ok = a.ignoreReason(filename, 10)
+	assert.False(t, ok)
+}
+
+func TestMarkIgnoredResources(t *testing.T) {
+	partials := []*schema.PartialResource{
+		{ResourceData: &schema.ResourceData{Address: "aws_nat_gateway.shared", Type: "aws_nat_gateway"}},
+		{ResourceData: &schema.ResourceData{Address: "aws_nat_gateway.priced", Type: "aws_nat_gateway"}},
+	}
+
+	markIgnoredResources(partials, map[string]string{"aws_nat_gateway.shared": "billed to the shared account"})
+
+	require.NotNil(t, partials[0].Resource)
+	assert.True(t, partials[0].Resource.IsSkipped)
+	assert.True(t, partials[0].Resource.NoPrice)
+	assert.Equal(t, "billed to the shared account", partials[0].Resource.SkipMessage)
+	assert.Nil(t, partials[1].Resource)
+}
@@ -75,6 +75,7 @@ func (a *AutoscalingGroup) BuildResource() *schema.Resource {
 		if lc == nil {
 			return nil
 		}
+		lc.ResourceType = "aws_launch_configuration"
 		subResources = append(subResources, lc)
 		estimateInstanceQualities = lc.EstimateUsage
 	} else if a.LaunchTemplate != nil {
@@ -83,6 +84,7 @@ func (a *AutoscalingGroup) BuildResource() *schema.Resource {
 		if lt == nil {
 			return nil
 		}
+		lt.ResourceType = "aws_launch_template"
 		subResources = append(subResources, lt)
 		estimateInstanceQualities = lt.EstimateUsage
 	}
@@ -0,0 +1,176 @@
+package aws
+
+import (
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// BatchComputeEnvironment is an AWS Batch compute environment. Batch itself is
+// free, managed environments are charged for the EC2 instances or Fargate
+// tasks that run the jobs. Unmanaged environments run jobs on instances that
+// are managed, and priced, separately.
+type BatchComputeEnvironment struct {
+	Address string
+	Region  string
+
+	// Type is the compute resources type: EC2, SPOT, FARGATE or FARGATE_SPOT.
+	Type string
+	// InstanceType is the first specific instance type that the environment
+	// can launch. It's empty if only instance families or "optimal" are set.
+	InstanceType string
+	Unmanaged    bool
+
+	InstanceTypeOverride *string  `infracost_usage:"instance_type"`
+	MonthlyInstanceHrs   *float64 `infracost_usage:"monthly_instance_hrs"`
+	OperatingSystem      *string  `infracost_usage:"operating_system"`
+	SpotDiscountRate     *float64 `infracost_usage:"spot_discount_rate"`
+	MonthlyVCPUHrs       *float64 `infracost_usage:"monthly_vcpu_hrs"`
+	MonthlyGBHrs         *float64 `infracost_usage:"monthly_gb_hrs"`
+}
+
+func (r *BatchComputeEnvironment) CoreType() string {
+	return "BatchComputeEnvironment"
+}
+
+func (r *BatchComputeEnvironment) UsageSchema() []*schema.UsageItem {
+	return []*schema.UsageItem{
+		{Key: "instance_type", DefaultValue: "", ValueType: schema.String},
+		{Key: "monthly_instance_hrs", DefaultValue: 0, ValueType: schema.Float64},
+		{Key: "operating_system", DefaultValue: "linux", ValueType: schema.String},
+		{Key: "spot_discount_rate", DefaultValue: 0, ValueType: schema.Float64},
+		{Key: "monthly_vcpu_hrs", DefaultValue: 0, ValueType: schema.Float64},
+		{Key: "monthly_gb_hrs", DefaultValue: 0, ValueType: schema.Float64},
+	}
+}
+
+func (r *BatchComputeEnvironment) PopulateUsage(u *schema.UsageData) {
+	resources.PopulateArgsWithUsage(r, u)
+}
+
+func (r *BatchComputeEnvironment) BuildResource() *schema.Resource {
+	if r.Unmanaged {
+		return &schema.Resource{
+			Name:        r.Address,
+			NoPrice:     true,
+			IsSkipped:   true,
+			UsageSchema: r.UsageSchema(),
+		}
+	}
+
+	var costComponents []*schema.CostComponent
+
+	switch r.Type {
+	case "FARGATE", "FARGATE_SPOT":
+		costComponents = append(costComponents, r.fargateVCPUCostComponent(), r.fargateMemoryCostComponent())
+	default:
+		if c := r.instanceCostComponent(); c != nil {
+			costComponents = append(costComponents, c)
+		}
+	}
+
+	return &schema.Resource{
+		Name:           r.Address,
+		CostComponents: costComponents,
+		UsageSchema:    r.UsageSchema(),
+	}
+}
+
+// instanceCostComponent returns the cost of the instance hours that the
+// environment runs, priced as the instance type that Batch launches. Batch
+// picks the instance types when only families or "optimal" are set, so the
+// instance_type usage key has to be given to price them.
+func (r *BatchComputeEnvironment) instanceCostComponent() *schema.CostComponent {
+	instanceType := r.InstanceType
+	if r.InstanceTypeOverride != nil && *r.InstanceTypeOverride != "" {
+		instanceType = *r.InstanceTypeOverride
+	}
+
+	if instanceType == "" {
+		log.Warnf("Skipping instance usage for %s. Set the instance_type usage key to price the instances Batch launches.", r.Address)
+		return nil
+	}
+
+	operatingSystem := r.OperatingSystem
+	if operatingSystem == nil || *operatingSystem == "" {
+		operatingSystem = strPtr("linux")
+	}
+
+	purchaseOption := "on_demand"
+	if r.Type == "SPOT" {
+		purchaseOption = "spot"
+	}
+
+	instance := &Instance{
+		Address:          r.Address,
+		Region:           r.Region,
+		Tenancy:          "Shared",
+		PurchaseOption:   purchaseOption,
+		InstanceType:     instanceType,
+		OperatingSystem:  operatingSystem,
+		SpotDiscountRate: r.SpotDiscountRate,
+	}
+
+	c := instance.computeCostComponent()
+	c.MonthlyQuantity = floatPtrToDecimalPtr(r.MonthlyInstanceHrs)
+
+	return c
+}
+
+func (r *BatchComputeEnvironment) fargateVCPUCostComponent() *schema.CostComponent {
+	usageType := "/^([A-Z0-9]+-)?Fargate-vCPU-Hours:perCPU$/"
+	name := "Fargate vCPU"
+	if r.Type == "FARGATE_SPOT" {
+		usageType = "/^([A-Z0-9]+-)?SpotUsage-Fargate-vCPU-Hours:perCPU$/"
+		name = "Fargate Spot vCPU"
+	}
+
+	return &schema.CostComponent{
+		Name:            name,
+		Unit:            "vCPU-hours",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: floatPtrToDecimalPtr(r.MonthlyVCPUHrs),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Region:        strPtr(r.Region),
+			Service:       strPtr("AmazonECS"),
+			ProductFamily: strPtr("Compute"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: strPtr(usageType)},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("on_demand"),
+		},
+	}
+}
+
+func (r *BatchComputeEnvironment) fargateMemoryCostComponent() *schema.CostComponent {
+	usageType := "/^([A-Z0-9]+-)?Fargate-GB-Hours$/"
+	name := "Fargate memory"
+	if r.Type == "FARGATE_SPOT" {
+		usageType = "/^([A-Z0-9]+-)?SpotUsage-Fargate-GB-Hours$/"
+		name = "Fargate Spot memory"
+	}
+
+	return &schema.CostComponent{
+		Name:            name,
+		Unit:            "GB-hours",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: floatPtrToDecimalPtr(r.MonthlyGBHrs),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Region:        strPtr(r.Region),
+			Service:       strPtr("AmazonECS"),
+			ProductFamily: strPtr("Compute"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: strPtr(usageType)},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("on_demand"),
+		},
+	}
+}
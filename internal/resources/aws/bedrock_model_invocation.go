@@ -0,0 +1,147 @@
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/resources"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// bedrockModelNames maps the prefixes of Bedrock foundation model IDs to the
+// model names used in the AWS pricing data. Model IDs have a version suffix,
+// e.g. anthropic.claude-3-haiku-20240307-v1:0, so they are matched by prefix.
+var bedrockModelNames = map[string]string{
+	"ai21.j2-mid":                   "Jurassic-2 Mid",
+	"ai21.j2-ultra":                 "Jurassic-2 Ultra",
+	"amazon.titan-embed-text":       "Titan Embeddings G1 - Text",
+	"amazon.titan-text-express":     "Titan Text G1 - Express",
+	"amazon.titan-text-lite":        "Titan Text G1 - Lite",
+	"amazon.titan-text-premier":     "Titan Text G1 - Premier",
+	"anthropic.claude-3-5-sonnet":   "Claude 3.5 Sonnet",
+	"anthropic.claude-3-haiku":      "Claude 3 Haiku",
+	"anthropic.claude-3-opus":       "Claude 3 Opus",
+	"anthropic.claude-3-sonnet":     "Claude 3 Sonnet",
+	"anthropic.claude-instant":      "Claude Instant",
+	"anthropic.claude-v2":           "Claude",
+	"cohere.command-light-text":     "Command Light",
+	"cohere.command-r-plus":         "Command R+",
+	"cohere.command-r":              "Command R",
+	"cohere.command-text":           "Command",
+	"meta.llama3-70b-instruct":      "Llama 3 70B Instruct",
+	"meta.llama3-8b-instruct":       "Llama 3 8B Instruct",
+	"mistral.mistral-7b-instruct":   "Mistral 7B Instruct",
+	"mistral.mistral-large":         "Mistral Large",
+	"mistral.mixtral-8x7b-instruct": "Mixtral 8x7B Instruct",
+}
+
+// BedrockModelInvocation struct represents the on-demand invocations of an
+// Amazon Bedrock foundation model. It is used for the usage-only aws_bedrock
+// resource and for Bedrock agents, which invoke their foundation model for
+// each request.
+//
+// On-demand invocations are billed per 1K input tokens and per 1K output
+// tokens, each model has its own prices.
+//
+// Resource information: https://aws.amazon.com/bedrock/
+// Pricing information: https://aws.amazon.com/bedrock/pricing/
+type BedrockModelInvocation struct {
+	Address string
+	Region  string
+	Model   string
+
+	MonthlyInputTokens  *int64 `infracost_usage:"monthly_input_tokens"`
+	MonthlyOutputTokens *int64 `infracost_usage:"monthly_output_tokens"`
+}
+
+// BedrockModelInvocationUsageSchema defines a list which represents the usage schema of BedrockModelInvocation.
+var BedrockModelInvocationUsageSchema = []*schema.UsageItem{
+	{Key: "monthly_input_tokens", DefaultValue: 0, ValueType: schema.Int64},
+	{Key: "monthly_output_tokens", DefaultValue: 0, ValueType: schema.Int64},
+}
+
+// PopulateUsage parses the u schema.UsageData into the BedrockModelInvocation.
+// It uses the `infracost_usage` struct tags to populate data into the BedrockModelInvocation.
+func (r *BedrockModelInvocation) PopulateUsage(u *schema.UsageData) {
+	resources.PopulateArgsWithUsage(r, u)
+}
+
+// BuildResource builds a schema.Resource from a valid BedrockModelInvocation.
+// This method is called after the resource is initialised by an IaC provider.
+// See providers folder for more information.
+func (r *BedrockModelInvocation) BuildResource() *schema.Resource {
+	modelName := bedrockModelName(r.Model)
+
+	return &schema.Resource{
+		Name:        r.Address,
+		UsageSchema: BedrockModelInvocationUsageSchema,
+		CostComponents: []*schema.CostComponent{
+			r.tokensCostComponent(fmt.Sprintf("Input tokens (%s)", modelName), "input tokens", modelName, r.MonthlyInputTokens),
+			r.tokensCostComponent(fmt.Sprintf("Output tokens (%s)", modelName), "output tokens", modelName, r.MonthlyOutputTokens),
+		},
+	}
+}
+
+func (r *BedrockModelInvocation) tokensCostComponent(name, inferenceType, modelName string, tokens *int64) *schema.CostComponent {
+	// The prices are per 1K tokens
+	var quantity *decimal.Decimal
+	if tokens != nil {
+		quantity = decimalPtr(decimal.NewFromInt(*tokens).Div(decimal.NewFromInt(1000)))
+	}
+
+	return &schema.CostComponent{
+		Name:            name,
+		Unit:            "1K tokens",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: quantity,
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr(r.Region),
+			Service:    strPtr("AmazonBedrock"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "model", Value: strPtr(modelName)},
+				{Key: "inferenceType", ValueRegex: regexPtr(fmt.Sprintf("^%s$", inferenceType))},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("on_demand"),
+		},
+	}
+}
+
+// bedrockModelName returns the pricing model name for a Bedrock foundation
+// model ID or ARN. Values that aren't a known model ID are returned as they
+// are, so the pricing model name can also be used directly.
+func bedrockModelName(model string) string {
+	id := model
+	if i := strings.LastIndex(id, "/"); i != -1 {
+		id = id[i+1:]
+	}
+
+	// Cross-region inference profiles prefix the model ID with a geography
+	// e.g. us.anthropic.claude-3-haiku-20240307-v1:0
+	for _, geo := range []string{"us.", "eu.", "apac."} {
+		id = strings.TrimPrefix(id, geo)
+	}
+
+	// Check the longest prefixes first so that e.g. cohere.command-r-plus
+	// isn't matched by cohere.command-r.
+	prefixes := make([]string, 0, len(bedrockModelNames))
+	for p := range bedrockModelNames {
+		prefixes = append(prefixes, p)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+
+	for _, p := range prefixes {
+		if strings.HasPrefix(id, p) {
+			return bedrockModelNames[p]
+		}
+	}
+
+	return model
+}
@@ -2,6 +2,7 @@ package aws
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
@@ -23,6 +24,10 @@ type DataTransfer struct {
 	MonthlyOutboundInternetGB       *float64 `infracost_usage:"monthly_outbound_internet_gb"`
 	MonthlyOutboundUsEastToUsEastGB *float64 `infracost_usage:"monthly_outbound_us_east_to_us_east_gb"`
 	MonthlyOutboundOtherRegionsGB   *float64 `infracost_usage:"monthly_outbound_other_regions_gb"`
+
+	// MonthlyOutboundToRegionGB splits the outbound inter-region transfer by
+	// destination region, since each source/destination pair has its own price.
+	MonthlyOutboundToRegionGB *RegionsUsage `infracost_usage:"monthly_outbound_to_region_gb"`
 }
 
 // DataTransferUsageSchema defines a list which represents the usage schema of DataTransfer.
@@ -31,6 +36,11 @@ var DataTransferUsageSchema = []*schema.UsageItem{
 	{Key: "monthly_outbound_internet_gb", DefaultValue: 0, ValueType: schema.Float64},
 	{Key: "monthly_outbound_us_east_to_us_east_gb", DefaultValue: 0, ValueType: schema.Float64},
 	{Key: "monthly_outbound_other_regions_gb", DefaultValue: 0, ValueType: schema.Float64},
+	{
+		Key:          "monthly_outbound_to_region_gb",
+		ValueType:    schema.SubResourceUsage,
+		DefaultValue: &usage.ResourceUsage{Name: "monthly_outbound_to_region_gb", Items: RegionUsageSchema},
+	},
 }
 
 // PopulateUsage parses the u schema.UsageData into the DataTransfer.
@@ -55,6 +65,7 @@ func (r *DataTransfer) BuildResource() *schema.Resource {
 	costComponents = append(costComponents, r.intraRegionCostComponents()...)
 	costComponents = append(costComponents, r.outboundInternetCostComponents()...)
 	costComponents = append(costComponents, r.outboundUsEastCostComponents()...)
+	costComponents = append(costComponents, r.outboundToRegionCostComponents()...)
 	costComponents = append(costComponents, r.outboundOtherRegionsCostComponents()...)
 
 	return &schema.Resource{
@@ -210,8 +221,49 @@ func (r *DataTransfer) outboundUsEastCostComponents() []*schema.CostComponent {
 	return costComponents
 }
 
+// outboundToRegionCostComponents returns a cost component for each destination
+// region in the outbound data transfer usage, priced for that region pair.
+func (r *DataTransfer) outboundToRegionCostComponents() []*schema.CostComponent {
+	costComponents := []*schema.CostComponent{}
+
+	if r.MonthlyOutboundToRegionGB == nil {
+		return costComponents
+	}
+
+	regionUsages := r.MonthlyOutboundToRegionGB.Values()
+	sort.Slice(regionUsages, func(i, j int) bool {
+		return regionUsages[i].Key < regionUsages[j].Key
+	})
+
+	for _, regionUsage := range regionUsages {
+		toRegion := regionUsage.Key
+
+		if toRegion == r.Region {
+			log.Warnf("Skipping outbound data transfer to %s for %s, use monthly_intra_region_gb for data transferred within the region", toRegion, r.Address)
+			continue
+		}
+
+		if _, ok := RegionMapping[toRegion]; !ok {
+			log.Warnf("Skipping outbound data transfer to %s for %s. Could not find mapping for region %s", toRegion, r.Address, toRegion)
+			continue
+		}
+
+		costComponents = append(costComponents, &schema.CostComponent{
+			Name:            fmt.Sprintf("Outbound data transfer to %s", toRegion),
+			Unit:            "GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: decimalPtr(decimal.NewFromFloat(regionUsage.Value)),
+			ProductFilter:   r.buildProductFilter("InterRegion Outbound", &toRegion, ""),
+		})
+	}
+
+	return costComponents
+}
+
 // outboundOtherRegionsCostComponents returns a cost component for outbound data
-// transfer to other regions only when its usage is specified.
+// transfer to other regions only when its usage is specified. This is a blended
+// estimate using a single destination, monthly_outbound_to_region_gb should be
+// used to price each destination region separately.
 func (r *DataTransfer) outboundOtherRegionsCostComponents() []*schema.CostComponent {
 	costComponents := []*schema.CostComponent{}
 
@@ -219,18 +271,7 @@ func (r *DataTransfer) outboundOtherRegionsCostComponents() []*schema.CostCompon
 		return costComponents
 	}
 
-	toRegion := "us-west-1"
-
-	switch r.Region {
-	case "us-east-1":
-		toRegion = "us-west-2"
-	case "us-west-1":
-		toRegion = "us-west-2"
-	case "cn-north-1":
-		toRegion = "cn-northwest-1"
-	case "cn-northwest-1":
-		toRegion = "cn-north-1"
-	}
+	toRegion := r.otherRegion()
 
 	costComponents = append(costComponents, &schema.CostComponent{
 		Name:            "Outbound data transfer to other regions",
@@ -243,6 +284,23 @@ func (r *DataTransfer) outboundOtherRegionsCostComponents() []*schema.CostCompon
 	return costComponents
 }
 
+// otherRegion returns the destination region that is used to estimate data
+// transfer to other regions when the destination region isn't known.
+func (r *DataTransfer) otherRegion() string {
+	switch r.Region {
+	case "us-east-1":
+		return "us-west-2"
+	case "us-west-1":
+		return "us-west-2"
+	case "cn-north-1":
+		return "cn-northwest-1"
+	case "cn-northwest-1":
+		return "cn-north-1"
+	}
+
+	return "us-west-1"
+}
+
 // buildProductFilter returns a filter for data transfer products. Desctination
 // region is optional.
 func (r *DataTransfer) buildProductFilter(transferType string, toRegion *string, usageTypeSuffix string) *schema.ProductFilter {
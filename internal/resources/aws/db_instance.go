@@ -1,10 +1,13 @@
 package aws
 
 import (
+	"context"
+
 	log "github.com/sirupsen/logrus"
 
 	"github.com/infracost/infracost/internal/resources"
 	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/usage/aws"
 
 	"fmt"
 	"strings"
@@ -14,6 +17,7 @@ import (
 
 type DBInstance struct {
 	Address                                      string
+	Identifier                                   string
 	Region                                       string
 	LicenseModel                                 string
 	StorageType                                  string
@@ -30,6 +34,8 @@ type DBInstance struct {
 	MonthlyAdditionalPerformanceInsightsRequests *int64   `infracost_usage:"monthly_additional_performance_insights_requests"`
 	ReservedInstanceTerm                         *string  `infracost_usage:"reserved_instance_term"`
 	ReservedInstancePaymentOption                *string  `infracost_usage:"reserved_instance_payment_option"`
+	CPUUtilizationPercent                        *float64 `infracost_usage:"cpu_utilization_percent"`
+	MemoryUtilizationPercent                     *float64 `infracost_usage:"memory_utilization_percent"`
 }
 
 func (r *DBInstance) CoreType() string {
@@ -46,6 +52,8 @@ var DBInstanceUsageSchema = []*schema.UsageItem{
 	{Key: "monthly_additional_performance_insights_requests", ValueType: schema.Int64, DefaultValue: 0},
 	{Key: "reserved_instance_term", DefaultValue: "", ValueType: schema.String},
 	{Key: "reserved_instance_payment_option", DefaultValue: "", ValueType: schema.String},
+	{Key: "cpu_utilization_percent", DefaultValue: 0, ValueType: schema.Float64},
+	{Key: "memory_utilization_percent", DefaultValue: 0, ValueType: schema.Float64},
 }
 
 func (r *DBInstance) PopulateUsage(u *schema.UsageData) {
@@ -131,7 +139,6 @@ func (r *DBInstance) BuildResource() *schema.Resource {
 	}
 
 	instanceAttributeFilters := []*schema.AttributeFilter{
-		{Key: "instanceType", Value: strPtr(r.InstanceClass)},
 		{Key: "deploymentOption", Value: strPtr(deploymentOption)},
 		{Key: "databaseEngine", Value: strPtr(databaseEngine)},
 	}
@@ -166,9 +173,13 @@ func (r *DBInstance) BuildResource() *schema.Resource {
 		purchaseOptionLabel = "reserved"
 	}
 
-	costComponents := []*schema.CostComponent{
-		{
-			Name:           fmt.Sprintf("Database instance (%s, %s, %s)", purchaseOptionLabel, deploymentOption, r.InstanceClass),
+	instanceCostComponent := func(instanceClass string) *schema.CostComponent {
+		attributeFilters := append([]*schema.AttributeFilter{
+			{Key: "instanceType", Value: strPtr(instanceClass)},
+		}, instanceAttributeFilters...)
+
+		return &schema.CostComponent{
+			Name:           fmt.Sprintf("Database instance (%s, %s, %s)", purchaseOptionLabel, deploymentOption, instanceClass),
 			Unit:           "hours",
 			UnitMultiplier: decimal.NewFromInt(1),
 			HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
@@ -177,10 +188,24 @@ func (r *DBInstance) BuildResource() *schema.Resource {
 				Region:           strPtr(r.Region),
 				Service:          strPtr("AmazonRDS"),
 				ProductFamily:    strPtr("Database Instance"),
-				AttributeFilters: instanceAttributeFilters,
+				AttributeFilters: attributeFilters,
 			},
 			PriceFilter: priceFilter,
-		},
+		}
+	}
+
+	databaseInstanceCostComponent := instanceCostComponent(r.InstanceClass)
+
+	var recommendations []*schema.Recommendation
+	if rightsized := rightsizedInstanceType(r.InstanceClass, r.CPUUtilizationPercent, r.MemoryUtilizationPercent); rightsized != "" {
+		recommendations = append(recommendations, rightsizingRecommendation(r.Address, r.InstanceClass, rightsized, r.CPUUtilizationPercent, r.MemoryUtilizationPercent, databaseInstanceCostComponent, instanceCostComponent(rightsized)))
+	}
+	if currentGeneration := currentGenerationInstanceType(r.InstanceClass); currentGeneration != "" {
+		recommendations = append(recommendations, currentGenerationRecommendation(r.Address, r.InstanceClass, currentGeneration, databaseInstanceCostComponent, instanceCostComponent(currentGeneration)))
+	}
+
+	costComponents := []*schema.CostComponent{
+		databaseInstanceCostComponent,
 		{
 			Name:            storageName,
 			Unit:            "GB",
@@ -287,10 +312,27 @@ func (r *DBInstance) BuildResource() *schema.Resource {
 		}
 	}
 
+	estimate := func(ctx context.Context, values map[string]interface{}) error {
+		if r.Identifier == "" {
+			return nil
+		}
+
+		cpu, err := aws.RDSGetCPUUtilization(ctx, r.Region, r.Identifier)
+		if err != nil {
+			return err
+		}
+		if cpu > 0 {
+			values["cpu_utilization_percent"] = cpu
+		}
+		return nil
+	}
+
 	return &schema.Resource{
-		Name:           r.Address,
-		CostComponents: costComponents,
-		UsageSchema:    DBInstanceUsageSchema,
+		Name:            r.Address,
+		CostComponents:  costComponents,
+		UsageSchema:     DBInstanceUsageSchema,
+		Recommendations: recommendations,
+		EstimateUsage:   estimate,
 	}
 }
 
@@ -41,8 +41,14 @@ func (a *EBSVolume) BuildResource() *schema.Resource {
 
 	costComponents := make([]*schema.CostComponent, 0)
 	subResources := make([]*schema.Resource, 0)
+	var recommendations []*schema.Recommendation
 
-	costComponents = append(costComponents, a.storageCostComponent())
+	storageCostComponent := a.storageCostComponent()
+	costComponents = append(costComponents, storageCostComponent)
+
+	if strings.ToLower(a.Type) == "gp2" {
+		recommendations = append(recommendations, a.gp3Recommendation(storageCostComponent))
+	}
 
 	if strings.ToLower(a.Type) == "gp3" && a.Throughput > 125 {
 		costComponents = append(costComponents, a.provisionedThroughputCostComponent())
@@ -61,11 +67,58 @@ func (a *EBSVolume) BuildResource() *schema.Resource {
 	}
 
 	return &schema.Resource{
-		Name:           a.Address,
-		UsageSchema:    InstanceUsageSchema,
-		CostComponents: costComponents,
-		SubResources:   subResources,
+		Name:            a.Address,
+		UsageSchema:     InstanceUsageSchema,
+		CostComponents:  costComponents,
+		SubResources:    subResources,
+		Recommendations: recommendations,
+	}
+}
+
+// gp3Recommendation returns the quick win to replace the storage cost
+// component of a gp2 volume with a gp3 volume. gp3 volumes include 3000 IOPS,
+// so IOPS are provisioned for the gp3 volume when the gp2 volume has a higher
+// baseline of 3 IOPS per GB.
+func (a *EBSVolume) gp3Recommendation(storage *schema.CostComponent) *schema.Recommendation {
+	gp3 := *a
+	gp3.Type = "gp3"
+
+	costComponents := []*schema.CostComponent{gp3.storageCostComponent()}
+
+	size := defaultVolumeSize
+	if a.Size != nil {
+		size = *a.Size
 	}
+
+	if iops := size * 3; iops > 3000 {
+		if iops > 16000 {
+			iops = 16000
+		}
+		costComponents = append(costComponents, gp3.provisionedIOPSCostComponent("VolumeP-IOPS.gp3", iops-3000))
+	}
+
+	for _, c := range costComponents {
+		c.IgnoreIfMissingPrice = true
+	}
+
+	return &schema.Recommendation{
+		Type:           schema.RecommendationTypeQuickWin,
+		Description:    "Use gp3 instead of gp2, which costs less for the same baseline performance",
+		CostComponents: []*schema.CostComponent{storage},
+		Alternative: &schema.Resource{
+			Name:           a.Address,
+			CostComponents: costComponents,
+		},
+	}
+}
+
+// BuildBlockDeviceResource builds the volume as a child resource of an instance,
+// e.g. a root or EBS block device, typed as an aws_ebs_volume.
+func (a *EBSVolume) BuildBlockDeviceResource() *schema.Resource {
+	r := a.BuildResource()
+	r.ResourceType = "aws_ebs_volume"
+
+	return r
 }
 
 func (a *EBSVolume) storageCostComponent() *schema.CostComponent {
@@ -31,27 +31,31 @@ func (r *EIP) BuildResource() *schema.Resource {
 		}
 	}
 
-	return &schema.Resource{
-		Name: r.Address,
-		CostComponents: []*schema.CostComponent{
-			{
-				Name:           "IP address (if unused)",
-				Unit:           "hours",
-				UnitMultiplier: decimal.NewFromInt(1),
-				HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
-				ProductFilter: &schema.ProductFilter{
-					VendorName:    strPtr("aws"),
-					Region:        strPtr(r.Region),
-					Service:       strPtr("AmazonEC2"),
-					ProductFamily: strPtr("IP Address"),
-					AttributeFilters: []*schema.AttributeFilter{
-						{Key: "usagetype", ValueRegex: strPtr("/ElasticIP:IdleAddress/")},
-					},
-				},
-				PriceFilter: &schema.PriceFilter{
-					StartUsageAmount: strPtr("1"),
-				},
+	ipAddressCostComponent := &schema.CostComponent{
+		Name:           "IP address (if unused)",
+		Unit:           "hours",
+		UnitMultiplier: decimal.NewFromInt(1),
+		HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Region:        strPtr(r.Region),
+			Service:       strPtr("AmazonEC2"),
+			ProductFamily: strPtr("IP Address"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: strPtr("/ElasticIP:IdleAddress/")},
 			},
-		}, UsageSchema: EIPUsageSchema,
+		},
+		PriceFilter: &schema.PriceFilter{
+			StartUsageAmount: strPtr("1"),
+		},
+	}
+
+	return &schema.Resource{
+		Name:           r.Address,
+		CostComponents: []*schema.CostComponent{ipAddressCostComponent},
+		Recommendations: []*schema.Recommendation{
+			removalRecommendation("Release the Elastic IP as it isn't associated with an instance, network interface, NAT gateway or load balancer", ipAddressCostComponent),
+		},
+		UsageSchema: EIPUsageSchema,
 	}
 }
@@ -10,32 +10,57 @@ import (
 type EKSFargateProfile struct {
 	Address string
 	Region  string
+
+	Pods                 *int64   `infracost_usage:"pods"`
+	MonthlyVCPUHrsPerPod *float64 `infracost_usage:"monthly_vcpu_hrs_per_pod"`
+	MonthlyGBHrsPerPod   *float64 `infracost_usage:"monthly_gb_hrs_per_pod"`
+}
+
+func (r *EKSFargateProfile) CoreType() string {
+	return "EKSFargateProfile"
 }
 
-var EKSFargateProfileUsageSchema = []*schema.UsageItem{}
+func (r *EKSFargateProfile) UsageSchema() []*schema.UsageItem {
+	return []*schema.UsageItem{
+		{Key: "pods", DefaultValue: 0, ValueType: schema.Int64},
+		{Key: "monthly_vcpu_hrs_per_pod", DefaultValue: 0, ValueType: schema.Float64},
+		{Key: "monthly_gb_hrs_per_pod", DefaultValue: 0, ValueType: schema.Float64},
+	}
+}
 
 func (r *EKSFargateProfile) PopulateUsage(u *schema.UsageData) {
 	resources.PopulateArgsWithUsage(r, u)
 }
 
 func (r *EKSFargateProfile) BuildResource() *schema.Resource {
-	costComponents := make([]*schema.CostComponent, 0)
-	costComponents = append(costComponents, r.memoryCostComponent())
-	costComponents = append(costComponents, r.vcpuCostComponent())
+	costComponents := []*schema.CostComponent{
+		r.vcpuCostComponent(),
+		r.memoryCostComponent(),
+	}
 
 	return &schema.Resource{
 		Name:           r.Address,
 		CostComponents: costComponents,
-		UsageSchema:    EKSFargateProfileUsageSchema,
+		UsageSchema:    r.UsageSchema(),
 	}
 }
 
+// podHours returns the total monthly hours across all pods scheduled on the
+// profile, or nil if the usage hasn't been provided.
+func (r *EKSFargateProfile) podHours(hrsPerPod *float64) *decimal.Decimal {
+	if r.Pods == nil || hrsPerPod == nil {
+		return nil
+	}
+
+	return decimalPtr(decimal.NewFromInt(*r.Pods).Mul(decimal.NewFromFloat(*hrsPerPod)))
+}
+
 func (r *EKSFargateProfile) memoryCostComponent() *schema.CostComponent {
 	return &schema.CostComponent{
-		Name:           "Per GB per hour",
-		Unit:           "GB",
-		UnitMultiplier: schema.HourToMonthUnitMultiplier,
-		HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		Name:            "Memory",
+		Unit:            "GB-hours",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: r.podHours(r.MonthlyGBHrsPerPod),
 		ProductFilter: &schema.ProductFilter{
 			VendorName:    strPtr("aws"),
 			Region:        strPtr(r.Region),
@@ -53,10 +78,10 @@ func (r *EKSFargateProfile) memoryCostComponent() *schema.CostComponent {
 
 func (r *EKSFargateProfile) vcpuCostComponent() *schema.CostComponent {
 	return &schema.CostComponent{
-		Name:           "Per vCPU per hour",
-		Unit:           "CPU",
-		UnitMultiplier: schema.HourToMonthUnitMultiplier,
-		HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		Name:            "vCPU",
+		Unit:            "vCPU-hours",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: r.podHours(r.MonthlyVCPUHrsPerPod),
 		ProductFilter: &schema.ProductFilter{
 			VendorName:    strPtr("aws"),
 			Region:        strPtr(r.Region),
@@ -27,13 +27,16 @@ type EKSNodeGroup struct {
 	LaunchTemplate  *LaunchTemplate
 
 	// "usage" args
-	InstanceCount                 *int64  `infracost_usage:"instances"`
-	OperatingSystem               *string `infracost_usage:"operating_system"`
-	ReservedInstanceType          *string `infracost_usage:"reserved_instance_type"`
-	ReservedInstanceTerm          *string `infracost_usage:"reserved_instance_term"`
-	ReservedInstancePaymentOption *string `infracost_usage:"reserved_instance_payment_option"`
-	MonthlyCPUCreditHours         *int64  `infracost_usage:"monthly_cpu_credit_hrs"`
-	VCPUCount                     *int64  `infracost_usage:"vcpu_count"`
+	InstanceCount                 *int64   `infracost_usage:"instances"`
+	OperatingSystem               *string  `infracost_usage:"operating_system"`
+	ReservedInstanceType          *string  `infracost_usage:"reserved_instance_type"`
+	ReservedInstanceTerm          *string  `infracost_usage:"reserved_instance_term"`
+	ReservedInstancePaymentOption *string  `infracost_usage:"reserved_instance_payment_option"`
+	SavingsPlanRate               *float64 `infracost_usage:"savings_plan_rate"`
+	UsagePurchaseOption           *string  `infracost_usage:"purchase_option"`
+	SpotDiscountRate              *float64 `infracost_usage:"spot_discount_rate"`
+	MonthlyCPUCreditHours         *int64   `infracost_usage:"monthly_cpu_credit_hrs"`
+	VCPUCount                     *int64   `infracost_usage:"vcpu_count"`
 }
 
 func (a *EKSNodeGroup) CoreType() string {
@@ -94,10 +97,12 @@ func (a *EKSNodeGroup) BuildResource() *schema.Resource {
 		if lt == nil {
 			return nil
 		}
+		lt.ResourceType = "aws_launch_template"
 		r.SubResources = append(r.SubResources, lt)
 		estimateInstanceQualities = lt.EstimateUsage
 	} else {
 		instance := &Instance{
+			Address:                       a.Address,
 			Region:                        a.Region,
 			Tenancy:                       "Shared",
 			InstanceType:                  a.InstanceType,
@@ -106,6 +111,9 @@ func (a *EKSNodeGroup) BuildResource() *schema.Resource {
 			ReservedInstanceType:          a.ReservedInstanceType,
 			ReservedInstanceTerm:          a.ReservedInstanceTerm,
 			ReservedInstancePaymentOption: a.ReservedInstancePaymentOption,
+			SavingsPlanRate:               a.SavingsPlanRate,
+			UsagePurchaseOption:           a.UsagePurchaseOption,
+			SpotDiscountRate:              a.SpotDiscountRate,
 			MonthlyCPUCreditHours:         a.MonthlyCPUCreditHours,
 			VCPUCount:                     a.VCPUCount,
 		}
@@ -90,22 +90,32 @@ func (r *ElasticBeanstalkEnvironment) BuildResource() *schema.Resource {
 		UsageSchema: ElasticBeanstalkEnvironmentUsageSchema,
 	}
 
-	a.SubResources = append(a.SubResources, r.LaunchConfiguration.BuildResource())
+	a.SubResources = append(a.SubResources, typedSubResource(r.LaunchConfiguration.BuildResource(), "aws_launch_configuration"))
 
 	if r.DBInstance != nil {
-		a.SubResources = append(a.SubResources, r.DBInstance.BuildResource())
+		a.SubResources = append(a.SubResources, typedSubResource(r.DBInstance.BuildResource(), "aws_db_instance"))
 	}
 
 	if r.CloudwatchLogGroup != nil {
-		a.SubResources = append(a.SubResources, r.CloudwatchLogGroup.BuildResource())
+		a.SubResources = append(a.SubResources, typedSubResource(r.CloudwatchLogGroup.BuildResource(), "aws_cloudwatch_log_group"))
 	}
 
 	if r.LoadBalancerType == "classic" {
-		a.SubResources = append(a.SubResources, r.ElasticLoadBalancer.BuildResource())
+		a.SubResources = append(a.SubResources, typedSubResource(r.ElasticLoadBalancer.BuildResource(), "aws_elb"))
 	} else {
-		a.SubResources = append(a.SubResources, r.LoadBalancer.BuildResource())
+		a.SubResources = append(a.SubResources, typedSubResource(r.LoadBalancer.BuildResource(), "aws_lb"))
 	}
 
 	return a
 
 }
+
+// typedSubResource sets the type of a sub resource that is built from another
+// resource type, e.g. the launch configuration of an environment.
+func typedSubResource(r *schema.Resource, resourceType string) *schema.Resource {
+	if r != nil {
+		r.ResourceType = resourceType
+	}
+
+	return r
+}
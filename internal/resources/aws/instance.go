@@ -28,6 +28,9 @@ type Instance struct {
 	EnableMonitoring bool
 	CPUCredits       string
 	HasHost          bool
+	// InstanceID is the ID of an instance that already exists, which its
+	// utilization metrics are looked up with.
+	InstanceID string
 
 	// "optional" args, that may be empty depending on the resource config
 	ElasticInferenceAcceleratorType *string
@@ -39,9 +42,14 @@ type Instance struct {
 	ReservedInstanceType          *string  `infracost_usage:"reserved_instance_type"`
 	ReservedInstanceTerm          *string  `infracost_usage:"reserved_instance_term"`
 	ReservedInstancePaymentOption *string  `infracost_usage:"reserved_instance_payment_option"`
+	SavingsPlanRate               *float64 `infracost_usage:"savings_plan_rate"`
+	UsagePurchaseOption           *string  `infracost_usage:"purchase_option"`
+	SpotDiscountRate              *float64 `infracost_usage:"spot_discount_rate"`
 	MonthlyCPUCreditHours         *int64   `infracost_usage:"monthly_cpu_credit_hrs"`
 	VCPUCount                     *int64   `infracost_usage:"vcpu_count"`
 	MonthlyHours                  *float64 `infracost_usage:"monthly_hrs"`
+	CPUUtilizationPercent         *float64 `infracost_usage:"cpu_utilization_percent"`
+	MemoryUtilizationPercent      *float64 `infracost_usage:"memory_utilization_percent"`
 }
 
 func (a *Instance) CoreType() string {
@@ -57,9 +65,14 @@ var InstanceUsageSchema = []*schema.UsageItem{
 	{Key: "reserved_instance_type", DefaultValue: "", ValueType: schema.String},
 	{Key: "reserved_instance_term", DefaultValue: "", ValueType: schema.String},
 	{Key: "reserved_instance_payment_option", DefaultValue: "", ValueType: schema.String},
+	{Key: "savings_plan_rate", DefaultValue: 0, ValueType: schema.Float64},
+	{Key: "purchase_option", DefaultValue: "", ValueType: schema.String},
+	{Key: "spot_discount_rate", DefaultValue: 0, ValueType: schema.Float64},
 	{Key: "monthly_cpu_credit_hrs", DefaultValue: 0, ValueType: schema.Int64},
 	{Key: "vcpu_count", DefaultValue: 0, ValueType: schema.Int64},
 	{Key: "monthly_hrs", DefaultValue: 730, ValueType: schema.Float64},
+	{Key: "cpu_utilization_percent", DefaultValue: 0, ValueType: schema.Float64},
+	{Key: "memory_utilization_percent", DefaultValue: 0, ValueType: schema.Float64},
 }
 
 func (a *Instance) PopulateUsage(u *schema.UsageData) {
@@ -92,19 +105,39 @@ func (a *Instance) BuildResource() *schema.Resource {
 		a.PurchaseOption = "on_demand"
 	}
 
+	if purchaseOption := ec2UsagePurchaseOption(a.Address, a.UsagePurchaseOption); purchaseOption != "" {
+		a.PurchaseOption = purchaseOption
+	}
+
 	costComponents := make([]*schema.CostComponent, 0)
 	subResources := make([]*schema.Resource, 0)
+	var recommendations []*schema.Recommendation
 
 	if a.RootBlockDevice != nil {
-		subResources = append(subResources, a.RootBlockDevice.BuildResource())
+		subResources = append(subResources, a.RootBlockDevice.BuildBlockDeviceResource())
 	}
 
 	for _, ebs := range a.EBSBlockDevices {
-		subResources = append(subResources, ebs.BuildResource())
+		subResources = append(subResources, ebs.BuildBlockDeviceResource())
 	}
 
 	if !a.HasHost {
-		costComponents = append(costComponents, a.computeCostComponent())
+		computeCostComponent := a.computeCostComponent()
+		costComponents = append(costComponents, computeCostComponent)
+
+		if rightsized := rightsizedInstanceType(a.InstanceType, a.CPUUtilizationPercent, a.MemoryUtilizationPercent); rightsized != "" {
+			alternative := *a
+			alternative.InstanceType = rightsized
+
+			recommendations = append(recommendations, rightsizingRecommendation(a.Address, a.InstanceType, rightsized, a.CPUUtilizationPercent, a.MemoryUtilizationPercent, computeCostComponent, alternative.computeCostComponent()))
+		}
+
+		if currentGeneration := currentGenerationInstanceType(a.InstanceType); currentGeneration != "" {
+			alternative := *a
+			alternative.InstanceType = currentGeneration
+
+			recommendations = append(recommendations, currentGenerationRecommendation(a.Address, a.InstanceType, currentGeneration, computeCostComponent, alternative.computeCostComponent()))
+		}
 	}
 
 	if a.EBSOptimized {
@@ -135,15 +168,34 @@ func (a *Instance) BuildResource() *schema.Resource {
 				values["operating_system"] = platform
 			}
 		}
+
+		if a.InstanceID != "" {
+			cpu, err := aws.EC2GetCPUUtilization(ctx, a.Region, a.InstanceID)
+			if err != nil {
+				return err
+			}
+			if cpu > 0 {
+				values["cpu_utilization_percent"] = cpu
+			}
+
+			memory, err := aws.EC2GetMemoryUtilization(ctx, a.Region, a.InstanceID)
+			if err != nil {
+				return err
+			}
+			if memory > 0 {
+				values["memory_utilization_percent"] = memory
+			}
+		}
 		return nil
 	}
 
 	return &schema.Resource{
-		Name:           a.Address,
-		UsageSchema:    a.UsageSchema(),
-		CostComponents: costComponents,
-		SubResources:   subResources,
-		EstimateUsage:  estimate,
+		Name:            a.Address,
+		UsageSchema:     a.UsageSchema(),
+		CostComponents:  costComponents,
+		SubResources:    subResources,
+		Recommendations: recommendations,
+		EstimateUsage:   estimate,
 	}
 }
 
@@ -191,16 +243,29 @@ func (a *Instance) computeCostComponent() *schema.CostComponent {
 		purchaseOptionLabel = "reserved"
 	}
 
+	// Savings Plans discount the on-demand price, they don't apply to Reserved
+	// Instances or spot instances.
+	var discount float64
+	if a.SavingsPlanRate != nil && *a.SavingsPlanRate != 0 && a.ReservedInstanceType == nil && a.PurchaseOption == "on_demand" {
+		if *a.SavingsPlanRate < 0 || *a.SavingsPlanRate >= 1 {
+			log.Warnf("Invalid savings_plan_rate for %s, ignoring Savings Plan. Expected a value between 0 and 1. Got: %v", a.Address, *a.SavingsPlanRate)
+		} else {
+			discount = *a.SavingsPlanRate
+			purchaseOptionLabel = "savings plan"
+		}
+	}
+
 	qty := decimal.NewFromFloat(730)
 	if a.MonthlyHours != nil {
 		qty = decimal.NewFromFloat(*a.MonthlyHours)
 	}
 
-	return &schema.CostComponent{
-		Name:            fmt.Sprintf("Instance usage (%s, %s, %s)", osLabel, purchaseOptionLabel, a.InstanceType),
-		Unit:            "hours",
-		UnitMultiplier:  decimal.NewFromInt(1),
-		MonthlyQuantity: decimalPtr(qty),
+	c := &schema.CostComponent{
+		Name:                fmt.Sprintf("Instance usage (%s, %s, %s)", osLabel, purchaseOptionLabel, a.InstanceType),
+		Unit:                "hours",
+		UnitMultiplier:      decimal.NewFromInt(1),
+		MonthlyQuantity:     decimalPtr(qty),
+		MonthlyDiscountPerc: discount,
 		ProductFilter: &schema.ProductFilter{
 			VendorName:    strPtr("aws"),
 			Region:        strPtr(a.Region),
@@ -217,6 +282,51 @@ func (a *Instance) computeCostComponent() *schema.CostComponent {
 		},
 		PriceFilter: priceFilter,
 	}
+
+	if a.PurchaseOption == "spot" && a.ReservedInstanceType == nil {
+		c.PriceFallback = spotPriceFallback(a.Address, a.SpotDiscountRate, &schema.PriceFilter{
+			PurchaseOption: strPtr("on_demand"),
+		})
+	}
+
+	return c
+}
+
+// ec2UsagePurchaseOption returns the purchase option that is set with the
+// purchase_option usage key, which overrides the purchase option from the
+// resource config, e.g. for spot instances that are requested by an external
+// autoscaler. An empty string is returned if it's not set or isn't valid.
+func ec2UsagePurchaseOption(address string, value *string) string {
+	if value == nil || *value == "" {
+		return ""
+	}
+
+	if *value != "on_demand" && *value != "spot" {
+		log.Warnf("Invalid purchase_option for %s, ignoring it. Expected: on_demand, spot. Got: %s", address, *value)
+		return ""
+	}
+
+	return *value
+}
+
+// spotPriceFallback returns the fallback that prices spot instances at the
+// on-demand price found with priceFilter, discounted by the historical spot
+// discount in rate, when the pricing service has no spot price. It returns nil
+// if rate isn't set or isn't valid.
+func spotPriceFallback(address string, rate *float64, priceFilter *schema.PriceFilter) *schema.PriceFallback {
+	if rate == nil || *rate == 0 {
+		return nil
+	}
+
+	if *rate < 0 || *rate >= 1 {
+		log.Warnf("Invalid spot_discount_rate for %s, ignoring it. Expected a value between 0 and 1. Got: %v", address, *rate)
+		return nil
+	}
+
+	return &schema.PriceFallback{
+		PriceFilter:  priceFilter,
+		DiscountPerc: *rate,
+	}
 }
 
 func (a *Instance) ebsOptimizedCostComponent() *schema.CostComponent {
@@ -28,13 +28,16 @@ type LaunchConfiguration struct {
 
 	// "usage" args
 	// These are populated from the Autoscaling Group resource
-	InstanceCount                 *int64  `infracost_usage:"instances"`
-	OperatingSystem               *string `infracost_usage:"operating_system"`
-	ReservedInstanceType          *string `infracost_usage:"reserved_instance_type"`
-	ReservedInstanceTerm          *string `infracost_usage:"reserved_instance_term"`
-	ReservedInstancePaymentOption *string `infracost_usage:"reserved_instance_payment_option"`
-	MonthlyCPUCreditHours         *int64  `infracost_usage:"monthly_cpu_credit_hrs"`
-	VCPUCount                     *int64  `infracost_usage:"vcpu_count"`
+	InstanceCount                 *int64   `infracost_usage:"instances"`
+	OperatingSystem               *string  `infracost_usage:"operating_system"`
+	ReservedInstanceType          *string  `infracost_usage:"reserved_instance_type"`
+	ReservedInstanceTerm          *string  `infracost_usage:"reserved_instance_term"`
+	ReservedInstancePaymentOption *string  `infracost_usage:"reserved_instance_payment_option"`
+	SavingsPlanRate               *float64 `infracost_usage:"savings_plan_rate"`
+	UsagePurchaseOption           *string  `infracost_usage:"purchase_option"`
+	SpotDiscountRate              *float64 `infracost_usage:"spot_discount_rate"`
+	MonthlyCPUCreditHours         *int64   `infracost_usage:"monthly_cpu_credit_hrs"`
+	VCPUCount                     *int64   `infracost_usage:"vcpu_count"`
 }
 
 var LaunchConfigurationUsageSchema = InstanceUsageSchema
@@ -54,6 +57,7 @@ func (a *LaunchConfiguration) BuildResource() *schema.Resource {
 	}
 
 	instance := &Instance{
+		Address:                         a.Address,
 		Region:                          a.Region,
 		Tenancy:                         a.Tenancy,
 		PurchaseOption:                  a.PurchaseOption,
@@ -69,6 +73,9 @@ func (a *LaunchConfiguration) BuildResource() *schema.Resource {
 		ReservedInstanceType:            a.ReservedInstanceType,
 		ReservedInstanceTerm:            a.ReservedInstanceTerm,
 		ReservedInstancePaymentOption:   a.ReservedInstancePaymentOption,
+		SavingsPlanRate:                 a.SavingsPlanRate,
+		UsagePurchaseOption:             a.UsagePurchaseOption,
+		SpotDiscountRate:                a.SpotDiscountRate,
 		MonthlyCPUCreditHours:           a.MonthlyCPUCreditHours,
 		VCPUCount:                       a.VCPUCount,
 	}
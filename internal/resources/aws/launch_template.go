@@ -29,13 +29,16 @@ type LaunchTemplate struct {
 
 	// "usage" args
 	// These are populated from the Autoscaling Group/EKS Node Group resource
-	InstanceCount                 *int64  `infracost_usage:"instances"`
-	OperatingSystem               *string `infracost_usage:"operating_system"`
-	ReservedInstanceType          *string `infracost_usage:"reserved_instance_type"`
-	ReservedInstanceTerm          *string `infracost_usage:"reserved_instance_term"`
-	ReservedInstancePaymentOption *string `infracost_usage:"reserved_instance_payment_option"`
-	MonthlyCPUCreditHours         *int64  `infracost_usage:"monthly_cpu_credit_hrs"`
-	VCPUCount                     *int64  `infracost_usage:"vcpu_count"`
+	InstanceCount                 *int64   `infracost_usage:"instances"`
+	OperatingSystem               *string  `infracost_usage:"operating_system"`
+	ReservedInstanceType          *string  `infracost_usage:"reserved_instance_type"`
+	ReservedInstanceTerm          *string  `infracost_usage:"reserved_instance_term"`
+	ReservedInstancePaymentOption *string  `infracost_usage:"reserved_instance_payment_option"`
+	SavingsPlanRate               *float64 `infracost_usage:"savings_plan_rate"`
+	UsagePurchaseOption           *string  `infracost_usage:"purchase_option"`
+	SpotDiscountRate              *float64 `infracost_usage:"spot_discount_rate"`
+	MonthlyCPUCreditHours         *int64   `infracost_usage:"monthly_cpu_credit_hrs"`
+	VCPUCount                     *int64   `infracost_usage:"vcpu_count"`
 }
 
 var LaunchTemplateUsageSchema = InstanceUsageSchema
@@ -57,6 +60,7 @@ func (a *LaunchTemplate) BuildResource() *schema.Resource {
 	costComponents := make([]*schema.CostComponent, 0)
 
 	instance := &Instance{
+		Address:                         a.Address,
 		Region:                          a.Region,
 		Tenancy:                         a.Tenancy,
 		AMI:                             a.AMI,
@@ -71,6 +75,8 @@ func (a *LaunchTemplate) BuildResource() *schema.Resource {
 		ReservedInstanceType:            a.ReservedInstanceType,
 		ReservedInstanceTerm:            a.ReservedInstanceTerm,
 		ReservedInstancePaymentOption:   a.ReservedInstancePaymentOption,
+		SavingsPlanRate:                 a.SavingsPlanRate,
+		SpotDiscountRate:                a.SpotDiscountRate,
 		MonthlyCPUCreditHours:           a.MonthlyCPUCreditHours,
 		VCPUCount:                       a.VCPUCount,
 	}
@@ -123,6 +129,15 @@ func (a *LaunchTemplate) calculateOnDemandAndSpotInstanceCounts() (int64, int64)
 		instanceCount = *a.InstanceCount
 	}
 
+	// The purchase_option usage key overrides the on-demand and spot split of
+	// the resource config.
+	switch ec2UsagePurchaseOption(a.Address, a.UsagePurchaseOption) {
+	case "on_demand":
+		return instanceCount, 0
+	case "spot":
+		return 0, instanceCount
+	}
+
 	onDemandInstanceCount := a.OnDemandBaseCount
 	remainingCount := instanceCount - onDemandInstanceCount
 	percMultiplier := decimal.NewFromInt(a.OnDemandPercentageAboveBaseCount).Div(decimal.NewFromInt(100))
@@ -80,22 +80,7 @@ func (r *LB) applicationLBCostComponents(maxLCU *decimal.Decimal) []*schema.Cost
 	productFamily := "Load Balancer-Application"
 
 	return []*schema.CostComponent{
-		{
-			Name:           "Application load balancer",
-			Unit:           "hours",
-			HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
-			UnitMultiplier: decimal.NewFromInt(1),
-			ProductFilter: &schema.ProductFilter{
-				VendorName:    strPtr("aws"),
-				Region:        strPtr(r.Region),
-				Service:       strPtr("AWSELB"),
-				ProductFamily: strPtr("Load Balancer-Application"),
-				AttributeFilters: []*schema.AttributeFilter{
-					{Key: "locationType", Value: strPtr("AWS Region")},
-					{Key: "usagetype", ValueRegex: strPtr("/LoadBalancerUsage/")},
-				},
-			},
-		},
+		r.hoursCostComponent(),
 		r.capacityUnitsCostComponent(productFamily, maxLCU),
 	}
 }
@@ -104,23 +89,38 @@ func (r *LB) networkLBCostComponents(maxLCU *decimal.Decimal) []*schema.CostComp
 	productFamily := "Load Balancer-Network"
 
 	return []*schema.CostComponent{
-		{
-			Name:           "Network load balancer",
-			Unit:           "hours",
-			HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
-			UnitMultiplier: decimal.NewFromInt(1),
-			ProductFilter: &schema.ProductFilter{
-				VendorName:    strPtr("aws"),
-				Region:        strPtr(r.Region),
-				Service:       strPtr("AWSELB"),
-				ProductFamily: strPtr("Load Balancer-Network"),
-				AttributeFilters: []*schema.AttributeFilter{
-					{Key: "locationType", Value: strPtr("AWS Region")},
-					{Key: "usagetype", ValueRegex: strPtr("/LoadBalancerUsage/")},
-				},
+		r.hoursCostComponent(),
+		r.capacityUnitsCostComponent(productFamily, maxLCU),
+	}
+}
+
+// hoursCostComponent returns the hourly cost of the load balancer. It's shared
+// with the listeners of the load balancer, see LBListener, so it's keyed by the
+// address of the load balancer.
+func (r *LB) hoursCostComponent() *schema.CostComponent {
+	name := "Network load balancer"
+	productFamily := "Load Balancer-Network"
+	if strings.ToLower(r.LoadBalancerType) == "application" {
+		name = "Application load balancer"
+		productFamily = "Load Balancer-Application"
+	}
+
+	return &schema.CostComponent{
+		Name:           name,
+		Unit:           "hours",
+		HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		UnitMultiplier: decimal.NewFromInt(1),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Region:        strPtr(r.Region),
+			Service:       strPtr("AWSELB"),
+			ProductFamily: strPtr(productFamily),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "locationType", Value: strPtr("AWS Region")},
+				{Key: "usagetype", ValueRegex: strPtr("/LoadBalancerUsage/")},
 			},
 		},
-		r.capacityUnitsCostComponent(productFamily, maxLCU),
+		SharedCostKey: r.Address,
 	}
 }
 
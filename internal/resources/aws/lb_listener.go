@@ -0,0 +1,38 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/resources"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// LBListener is free itself, but it references the hourly cost of its load
+// balancer so that the cost is shown with each listener. The cost is shared
+// with the load balancer, so it's only counted once, see DeduplicateSharedCosts.
+type LBListener struct {
+	Address      string
+	Region       string
+	LoadBalancer *LB
+}
+
+var LBListenerUsageSchema = []*schema.UsageItem{}
+
+func (r *LBListener) PopulateUsage(u *schema.UsageData) {
+	resources.PopulateArgsWithUsage(r, u)
+}
+
+func (r *LBListener) BuildResource() *schema.Resource {
+	if r.LoadBalancer == nil {
+		return &schema.Resource{
+			Name:        r.Address,
+			NoPrice:     true,
+			IsSkipped:   true,
+			UsageSchema: LBListenerUsageSchema,
+		}
+	}
+
+	return &schema.Resource{
+		Name:           r.Address,
+		CostComponents: []*schema.CostComponent{r.LoadBalancer.hoursCostComponent()},
+		UsageSchema:    LBListenerUsageSchema,
+	}
+}
@@ -27,25 +27,35 @@ func (a *NATGateway) BuildResource() *schema.Resource {
 		gbDataProcessed = decimalPtr(decimal.NewFromFloat(*a.MonthlyDataProcessedGB))
 	}
 
+	natGatewayCostComponent := &schema.CostComponent{
+		Name:           "NAT gateway",
+		Unit:           "hours",
+		UnitMultiplier: decimal.NewFromInt(1),
+		HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Region:        strPtr(a.Region),
+			Service:       strPtr("AmazonEC2"),
+			ProductFamily: strPtr("NAT Gateway"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: strPtr("/NatGateway-Hours/")},
+			},
+		},
+	}
+
+	// A NAT gateway is only known to be idle if the data it processes is set to
+	// 0 in the usage, not if it has no usage.
+	var recommendations []*schema.Recommendation
+	if a.MonthlyDataProcessedGB != nil && *a.MonthlyDataProcessedGB == 0 {
+		recommendations = append(recommendations, removalRecommendation("Remove the NAT gateway as it processes no data", natGatewayCostComponent))
+	}
+
 	return &schema.Resource{
-		Name:        a.Address,
-		UsageSchema: NATGatewayUsageSchema,
+		Name:            a.Address,
+		UsageSchema:     NATGatewayUsageSchema,
+		Recommendations: recommendations,
 		CostComponents: []*schema.CostComponent{
-			{
-				Name:           "NAT gateway",
-				Unit:           "hours",
-				UnitMultiplier: decimal.NewFromInt(1),
-				HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
-				ProductFilter: &schema.ProductFilter{
-					VendorName:    strPtr("aws"),
-					Region:        strPtr(a.Region),
-					Service:       strPtr("AmazonEC2"),
-					ProductFamily: strPtr("NAT Gateway"),
-					AttributeFilters: []*schema.AttributeFilter{
-						{Key: "usagetype", ValueRegex: strPtr("/NatGateway-Hours/")},
-					},
-				},
-			},
+			natGatewayCostComponent,
 			{
 				Name:            "Data processed",
 				Unit:            "GB",
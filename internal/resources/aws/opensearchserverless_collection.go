@@ -0,0 +1,104 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/resources"
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+)
+
+// OpenSearchServerlessCollection struct represents an Amazon OpenSearch Serverless collection.
+//
+// Serverless collections are billed for the OpenSearch Compute Units (OCUs) used for
+// indexing and search, and for the managed storage used by the indexed data.
+//
+// Resource information: https://aws.amazon.com/opensearch-service/features/serverless/
+// Pricing information: https://aws.amazon.com/opensearch-service/pricing/#Amazon_OpenSearch_Serverless
+type OpenSearchServerlessCollection struct {
+	Address string
+	Region  string
+
+	MonthlyIndexingOCUHrs *float64 `infracost_usage:"monthly_indexing_ocu_hrs"`
+	MonthlySearchOCUHrs   *float64 `infracost_usage:"monthly_search_ocu_hrs"`
+	StorageGB             *float64 `infracost_usage:"storage_gb"`
+}
+
+func (r *OpenSearchServerlessCollection) CoreType() string {
+	return "OpenSearchServerlessCollection"
+}
+
+func (r *OpenSearchServerlessCollection) UsageSchema() []*schema.UsageItem {
+	return []*schema.UsageItem{
+		{Key: "monthly_indexing_ocu_hrs", DefaultValue: 0, ValueType: schema.Float64},
+		{Key: "monthly_search_ocu_hrs", DefaultValue: 0, ValueType: schema.Float64},
+		{Key: "storage_gb", DefaultValue: 0, ValueType: schema.Float64},
+	}
+}
+
+func (r *OpenSearchServerlessCollection) PopulateUsage(u *schema.UsageData) {
+	resources.PopulateArgsWithUsage(r, u)
+}
+
+func (r *OpenSearchServerlessCollection) BuildResource() *schema.Resource {
+	costComponents := []*schema.CostComponent{
+		r.ocuCostComponent("Indexing", "IndexingOCU", r.MonthlyIndexingOCUHrs),
+		r.ocuCostComponent("Search", "SearchOCU", r.MonthlySearchOCUHrs),
+		r.storageCostComponent(),
+	}
+
+	return &schema.Resource{
+		Name:           r.Address,
+		CostComponents: costComponents,
+		UsageSchema:    r.UsageSchema(),
+	}
+}
+
+func (r *OpenSearchServerlessCollection) ocuCostComponent(name, usageType string, hours *float64) *schema.CostComponent {
+	var quantity *decimal.Decimal
+	if hours != nil {
+		quantity = decimalPtr(decimal.NewFromFloat(*hours))
+	}
+
+	return &schema.CostComponent{
+		Name:            name,
+		Unit:            "OCU-hours",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: quantity,
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr(r.Region),
+			Service:    strPtr("AmazonES"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: regexPtr(usageType + "$")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("on_demand"),
+		},
+	}
+}
+
+func (r *OpenSearchServerlessCollection) storageCostComponent() *schema.CostComponent {
+	var quantity *decimal.Decimal
+	if r.StorageGB != nil {
+		quantity = decimalPtr(decimal.NewFromFloat(*r.StorageGB))
+	}
+
+	return &schema.CostComponent{
+		Name:            "Managed storage",
+		Unit:            "GB",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: quantity,
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr(r.Region),
+			Service:    strPtr("AmazonES"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: regexPtr("ES:ServerlessStorage$")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("on_demand"),
+		},
+	}
+}
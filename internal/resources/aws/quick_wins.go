@@ -0,0 +1,79 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// currentGenerationFamilies maps the previous generation EC2 and RDS instance
+// families to the current generation family that replaces them, which has
+// better performance for the same or lower price. Instance sizes that the
+// current generation family doesn't have, e.g. m5.medium, have no price, so
+// their recommendations are removed when the prices are looked up.
+var currentGenerationFamilies = map[string]string{
+	"t2": "t3",
+	"m1": "m5",
+	"m3": "m5",
+	"m4": "m5",
+	"c1": "c5",
+	"c3": "c5",
+	"c4": "c5",
+	"r3": "r5",
+	"r4": "r5",
+	"i2": "i3",
+	"d2": "d3",
+	"p2": "p3",
+}
+
+// currentGenerationInstanceType returns the instance type of the current
+// generation family with the same size as instanceType, e.g. m5.large for
+// m4.large or db.m5.large for db.m4.large. It returns an empty string if
+// instanceType isn't a previous generation instance type.
+func currentGenerationInstanceType(instanceType string) string {
+	prefix := ""
+	if strings.HasPrefix(instanceType, "db.") {
+		prefix = "db."
+	}
+
+	family, size, ok := strings.Cut(strings.TrimPrefix(instanceType, prefix), ".")
+	if !ok {
+		return ""
+	}
+
+	current, ok := currentGenerationFamilies[family]
+	if !ok {
+		return ""
+	}
+
+	return prefix + current + "." + size
+}
+
+// currentGenerationRecommendation returns the quick win to replace the
+// instance cost component c of a previous generation instance type with
+// alternative, which is the cost component of the current generation
+// instance type.
+func currentGenerationRecommendation(address string, instanceType string, currentGeneration string, c *schema.CostComponent, alternative *schema.CostComponent) *schema.Recommendation {
+	alternative.IgnoreIfMissingPrice = true
+
+	return &schema.Recommendation{
+		Type:           schema.RecommendationTypeQuickWin,
+		Description:    fmt.Sprintf("Use %s instead of the previous generation %s", currentGeneration, instanceType),
+		CostComponents: []*schema.CostComponent{c},
+		Alternative: &schema.Resource{
+			Name:           address,
+			CostComponents: []*schema.CostComponent{alternative},
+		},
+	}
+}
+
+// removalRecommendation returns the quick win to remove the cost components
+// of a resource that isn't used, e.g. an Elastic IP that isn't attached.
+func removalRecommendation(description string, costComponents ...*schema.CostComponent) *schema.Recommendation {
+	return &schema.Recommendation{
+		Type:           schema.RecommendationTypeQuickWin,
+		Description:    description,
+		CostComponents: costComponents,
+	}
+}
@@ -0,0 +1,91 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	resources "github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestEBSVolumeGP3QuickWin(t *testing.T) {
+	size := int64(2000)
+	args := resources.EBSVolume{
+		Address: "aws_ebs_volume.data",
+		Region:  "us-east-1",
+		Size:    &size,
+	}
+	resource := args.BuildResource()
+
+	require.Len(t, resource.Recommendations, 1)
+	rec := resource.Recommendations[0]
+	assert.Equal(t, schema.RecommendationTypeQuickWin, rec.Type)
+	assert.Equal(t, resource.CostComponents, rec.CostComponents)
+	require.Len(t, rec.Alternative.CostComponents, 2)
+	assert.Equal(t, "Storage (general purpose SSD, gp3)", rec.Alternative.CostComponents[0].Name)
+	// A 2000 GB gp2 volume has a baseline of 6000 IOPS, 3000 more than gp3.
+	assert.Equal(t, "Provisioned IOPS", rec.Alternative.CostComponents[1].Name)
+	assert.Equal(t, "3000", rec.Alternative.CostComponents[1].MonthlyQuantity.String())
+
+	args.Type = "gp3"
+	assert.Empty(t, args.BuildResource().Recommendations)
+}
+
+func TestInstanceCurrentGenerationQuickWin(t *testing.T) {
+	args := resources.Instance{
+		Address:      "aws_instance.web",
+		Region:       "us-east-1",
+		InstanceType: "m4.large",
+	}
+	resource := args.BuildResource()
+
+	require.Len(t, resource.Recommendations, 1)
+	rec := resource.Recommendations[0]
+	assert.Equal(t, "Use m5.large instead of the previous generation m4.large", rec.Description)
+	assert.Equal(t, resource.CostComponents[0], rec.CostComponents[0])
+	assert.Equal(t, "Instance usage (Linux/UNIX, on-demand, m5.large)", rec.Alternative.CostComponents[0].Name)
+	assert.True(t, rec.Alternative.CostComponents[0].IgnoreIfMissingPrice)
+
+	args.InstanceType = "m5.large"
+	assert.Empty(t, args.BuildResource().Recommendations)
+}
+
+func TestDBInstanceCurrentGenerationQuickWin(t *testing.T) {
+	args := resources.DBInstance{
+		Address:       "aws_db_instance.db",
+		Region:        "us-east-1",
+		InstanceClass: "db.t2.micro",
+		Engine:        "mysql",
+	}
+	resource := args.BuildResource()
+
+	require.Len(t, resource.Recommendations, 1)
+	assert.Equal(t, "Use db.t3.micro instead of the previous generation db.t2.micro", resource.Recommendations[0].Description)
+	assert.Equal(t, "Database instance (on-demand, Single-AZ, db.t3.micro)", resource.Recommendations[0].Alternative.CostComponents[0].Name)
+}
+
+func TestEIPQuickWin(t *testing.T) {
+	resource := (&resources.EIP{Address: "aws_eip.unused", Region: "us-east-1"}).BuildResource()
+
+	require.Len(t, resource.Recommendations, 1)
+	assert.Nil(t, resource.Recommendations[0].Alternative)
+	assert.Equal(t, resource.CostComponents, resource.Recommendations[0].CostComponents)
+
+	resource = (&resources.EIP{Address: "aws_eip.used", Region: "us-east-1", Allocated: true}).BuildResource()
+	assert.Empty(t, resource.Recommendations)
+}
+
+func TestNATGatewayQuickWin(t *testing.T) {
+	args := resources.NATGateway{Address: "aws_nat_gateway.nat", Region: "us-east-1"}
+	assert.Empty(t, args.BuildResource().Recommendations)
+
+	dataProcessed := 0.0
+	args.MonthlyDataProcessedGB = &dataProcessed
+	resource := args.BuildResource()
+
+	require.Len(t, resource.Recommendations, 1)
+	assert.Equal(t, "Remove the NAT gateway as it processes no data", resource.Recommendations[0].Description)
+	assert.Equal(t, []*schema.CostComponent{resource.CostComponents[0]}, resource.Recommendations[0].CostComponents)
+}
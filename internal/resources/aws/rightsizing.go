@@ -0,0 +1,93 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// rightsizingTargetUtilization is the highest average CPU and memory
+// utilization, as a percentage, that an instance is expected to run at after
+// it's downsized.
+const rightsizingTargetUtilization = 80.0
+
+// smallerInstanceSizes maps the instance sizes to the next smaller size, which
+// has half the vCPUs or memory in most instance families. Families that don't
+// have the smaller size have no price for it, so their recommendations are
+// removed when the prices are looked up.
+var smallerInstanceSizes = map[string]string{
+	"micro":    "nano",
+	"small":    "micro",
+	"medium":   "small",
+	"large":    "medium",
+	"xlarge":   "large",
+	"2xlarge":  "xlarge",
+	"4xlarge":  "2xlarge",
+	"8xlarge":  "4xlarge",
+	"16xlarge": "8xlarge",
+	"24xlarge": "12xlarge",
+	"32xlarge": "16xlarge",
+	"48xlarge": "24xlarge",
+}
+
+// rightsizedInstanceType returns the smallest instance type in the family of
+// instanceType that the average CPU and memory utilization fit in below the
+// rightsizing target. It returns an empty string if the instance can't be
+// downsized or the CPU utilization isn't known. The memory utilization is
+// optional as it's only reported by instances that run the CloudWatch agent.
+func rightsizedInstanceType(instanceType string, cpuUtilization *float64, memoryUtilization *float64) string {
+	if cpuUtilization == nil || *cpuUtilization <= 0 {
+		return ""
+	}
+
+	i := strings.LastIndex(instanceType, ".")
+	if i == -1 {
+		return ""
+	}
+
+	family, size := instanceType[:i], instanceType[i+1:]
+
+	cpu := *cpuUtilization
+	memory := 0.0
+	if memoryUtilization != nil {
+		memory = *memoryUtilization
+	}
+
+	rightsized := ""
+	for {
+		smaller, ok := smallerInstanceSizes[size]
+		if !ok || cpu*2 > rightsizingTargetUtilization || memory*2 > rightsizingTargetUtilization {
+			break
+		}
+
+		size = smaller
+		cpu *= 2
+		memory *= 2
+		rightsized = family + "." + size
+	}
+
+	return rightsized
+}
+
+// rightsizingRecommendation returns the recommendation to replace the instance
+// cost component c with alternative, which is the cost component of the
+// rightsized instance type.
+func rightsizingRecommendation(address string, instanceType string, rightsized string, cpuUtilization *float64, memoryUtilization *float64, c *schema.CostComponent, alternative *schema.CostComponent) *schema.Recommendation {
+	utilization := fmt.Sprintf("CPU utilization is %.0f%%", *cpuUtilization)
+	if memoryUtilization != nil {
+		utilization += fmt.Sprintf(" and memory utilization is %.0f%%", *memoryUtilization)
+	}
+
+	alternative.IgnoreIfMissingPrice = true
+
+	return &schema.Recommendation{
+		Type:           schema.RecommendationTypeRightsizing,
+		Description:    fmt.Sprintf("Downsize from %s to %s as the average %s", instanceType, rightsized, utilization),
+		CostComponents: []*schema.CostComponent{c},
+		Alternative: &schema.Resource{
+			Name:           address,
+			CostComponents: []*schema.CostComponent{alternative},
+		},
+	}
+}
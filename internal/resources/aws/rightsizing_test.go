@@ -0,0 +1,96 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	resources "github.com/infracost/infracost/internal/resources/aws"
+)
+
+func utilization(v float64) *float64 {
+	return &v
+}
+
+func TestInstanceRightsizing(t *testing.T) {
+	args := resources.Instance{
+		Address:                  "aws_instance.web",
+		Region:                   "us-east-1",
+		InstanceType:             "m5.4xlarge",
+		CPUUtilizationPercent:    utilization(15),
+		MemoryUtilizationPercent: utilization(18),
+	}
+	resource := args.BuildResource()
+
+	require.Len(t, resource.Recommendations, 1)
+	rec := resource.Recommendations[0]
+	assert.Equal(t, "Downsize from m5.4xlarge to m5.xlarge as the average CPU utilization is 15% and memory utilization is 18%", rec.Description)
+	assert.Equal(t, resource.CostComponents[0], rec.CostComponents[0])
+	require.Len(t, rec.Alternative.CostComponents, 1)
+	assert.Equal(t, "Instance usage (Linux/UNIX, on-demand, m5.xlarge)", rec.Alternative.CostComponents[0].Name)
+	assert.True(t, rec.Alternative.CostComponents[0].IgnoreIfMissingPrice)
+
+	args.CPUUtilizationPercent = utilization(50)
+	assert.Empty(t, args.BuildResource().Recommendations)
+
+	args.CPUUtilizationPercent = nil
+	assert.Empty(t, args.BuildResource().Recommendations)
+}
+
+func TestDBInstanceRightsizing(t *testing.T) {
+	args := resources.DBInstance{
+		Address:               "aws_db_instance.db",
+		Region:                "us-east-1",
+		InstanceClass:         "db.r5.2xlarge",
+		Engine:                "postgres",
+		CPUUtilizationPercent: utilization(25),
+	}
+	resource := args.BuildResource()
+
+	require.Len(t, resource.Recommendations, 1)
+	rec := resource.Recommendations[0]
+	assert.Equal(t, "Downsize from db.r5.2xlarge to db.r5.xlarge as the average CPU utilization is 25%", rec.Description)
+	assert.Equal(t, "Database instance (on-demand, Single-AZ, db.r5.xlarge)", rec.Alternative.CostComponents[0].Name)
+}
+
+func TestInstanceUtilization(t *testing.T) {
+	stub := stubAWS(t)
+	defer stub.Close()
+
+	stub.WhenBody("GetMetricStatistics", "MetricName=CPUUtilization", "Value=i-0123456789").Then(200, `
+		<GetMetricStatisticsResponse xmlns="http://monitoring.amazonaws.com/doc/2010-08-01/">
+		  <GetMetricStatisticsResult>
+		    <Datapoints>
+		      <member>
+		        <Average>12.5</Average>
+		        <Unit>Percent</Unit>
+		        <Timestamp>1970-01-01T00:00:00Z</Timestamp>
+		      </member>
+		    </Datapoints>
+		    <Label>CPUUtilization</Label>
+		  </GetMetricStatisticsResult>
+		  <ResponseMetadata>
+		    <RequestId>00000000-0000-0000-0000-000000000000</RequestId>
+		  </ResponseMetadata>
+		</GetMetricStatisticsResponse>
+	`)
+	stub.WhenBody("GetMetricStatistics", "MetricName=mem_used_percent").Then(200, `
+		<GetMetricStatisticsResponse xmlns="http://monitoring.amazonaws.com/doc/2010-08-01/">
+		  <GetMetricStatisticsResult>
+		    <Datapoints>
+		    </Datapoints>
+		    <Label>mem_used_percent</Label>
+		  </GetMetricStatisticsResult>
+		  <ResponseMetadata>
+		    <RequestId>00000000-0000-0000-0000-000000000000</RequestId>
+		  </ResponseMetadata>
+		</GetMetricStatisticsResponse>
+	`)
+
+	args := resources.Instance{Region: "us-east-1", InstanceType: "m5.large", InstanceID: "i-0123456789"}
+	resource := args.BuildResource()
+	estimates := newEstimates(stub.ctx, t, resource)
+	assert.Equal(t, 12.5, estimates.usage["cpu_utilization_percent"])
+	assert.Nil(t, estimates.usage["memory_utilization_percent"])
+}
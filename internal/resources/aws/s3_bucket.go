@@ -19,12 +19,22 @@ type S3Bucket struct {
 	Region            string
 	Name              string
 	ObjectTagsEnabled bool
+	// RequesterPays is true if the requester, not the bucket owner, pays for
+	// the data transferred out of the bucket.
+	RequesterPays bool
 
 	// "optional" args, that may be empty depending on the resource config
 	LifecycleStorageClasses []string
+	// ReplicationDestinationRegions are the regions of the buckets that objects
+	// are replicated to. A region is empty if the destination bucket isn't
+	// known, e.g. because it's not managed by the same Terraform project.
+	ReplicationDestinationRegions []string
 
 	// "usage" args
-	ObjectTags *int64 `infracost_usage:"object_tags"`
+	ObjectTags                *int64   `infracost_usage:"object_tags"`
+	MonthlyOutboundInternetGB *float64 `infracost_usage:"monthly_outbound_internet_gb"`
+	MonthlyReplicatedGB       *float64 `infracost_usage:"monthly_replicated_gb"`
+	MonthlyReplicatedObjects  *int64   `infracost_usage:"monthly_replicated_objects"`
 
 	// "derived" attributes, that are constructed from the other arguments
 	storageClasses    []S3StorageClass
@@ -38,6 +48,9 @@ func (a *S3Bucket) CoreType() string {
 func (a *S3Bucket) UsageSchema() []*schema.UsageItem {
 	return []*schema.UsageItem{
 		{Key: "object_tags", DefaultValue: 0, ValueType: schema.Int64},
+		{Key: "monthly_outbound_internet_gb", DefaultValue: 0.0, ValueType: schema.Float64},
+		{Key: "monthly_replicated_gb", DefaultValue: 0.0, ValueType: schema.Float64},
+		{Key: "monthly_replicated_objects", DefaultValue: 0, ValueType: schema.Int64},
 		{Key: "standard", DefaultValue: &usage.ResourceUsage{Name: "standard", Items: S3StandardStorageClassUsageSchema}, ValueType: schema.SubResourceUsage},
 		{Key: "intelligent_tiering", DefaultValue: &usage.ResourceUsage{Name: "intelligent_tiering", Items: S3IntelligentTieringStorageClassUsageSchema}, ValueType: schema.SubResourceUsage},
 		{Key: "standard_infrequent_access", DefaultValue: &usage.ResourceUsage{Name: "standard_infrequent_access", Items: S3StandardInfrequentAccessStorageClassUsageSchema}, ValueType: schema.SubResourceUsage},
@@ -92,6 +105,8 @@ func (a *S3Bucket) BuildResource() *schema.Resource {
 	if a.ObjectTagsEnabled {
 		costComponents = append(costComponents, a.objectTagsCostComponent())
 	}
+	costComponents = append(costComponents, a.outboundInternetCostComponents()...)
+	costComponents = append(costComponents, a.replicationCostComponents()...)
 
 	subResources := make([]*schema.Resource, 0, len(a.storageClasses))
 	for _, storageClass := range a.storageClasses {
@@ -218,3 +233,64 @@ func (a *S3Bucket) objectTagsCostComponent() *schema.CostComponent {
 		},
 	}
 }
+
+// outboundInternetCostComponents returns the cost components for the data
+// transferred out of the bucket to the Internet. They are only added when the
+// usage is specified, and are skipped for requester pays buckets since the
+// requester is charged for the transfer.
+func (a *S3Bucket) outboundInternetCostComponents() []*schema.CostComponent {
+	if a.MonthlyOutboundInternetGB == nil {
+		return nil
+	}
+
+	if a.RequesterPays {
+		log.Debugf("Skipping outbound data transfer for %s as requester pays is enabled", a.Address)
+		return nil
+	}
+
+	dataTransfer := &DataTransfer{
+		Address:                   a.Address,
+		Region:                    a.Region,
+		MonthlyOutboundInternetGB: a.MonthlyOutboundInternetGB,
+	}
+
+	return dataTransfer.outboundInternetCostComponents()
+}
+
+// replicationCostComponents returns the cost components for replicating the
+// objects of the bucket to each of its destination buckets. The replicated
+// objects are PUT requests in the destination region, and replicating to
+// another region also transfers the replicated data out of this region. The
+// storage of the replicas is part of the destination buckets' costs.
+func (a *S3Bucket) replicationCostComponents() []*schema.CostComponent {
+	costComponents := []*schema.CostComponent{}
+	dataTransfer := &DataTransfer{Address: a.Address, Region: a.Region}
+
+	for _, region := range a.ReplicationDestinationRegions {
+		name := region
+		toRegion := region
+		requestsRegion := region
+
+		// Replication is usually used to copy objects to another region, so
+		// assume that's the case for destination buckets that aren't known.
+		if region == "" {
+			name = "other regions"
+			toRegion = dataTransfer.otherRegion()
+			requestsRegion = a.Region
+		}
+
+		if toRegion != a.Region {
+			costComponents = append(costComponents, &schema.CostComponent{
+				Name:            fmt.Sprintf("Replication data transfer to %s", name),
+				Unit:            "GB",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: floatPtrToDecimalPtr(a.MonthlyReplicatedGB),
+				ProductFilter:   dataTransfer.buildProductFilter("InterRegion Outbound", &toRegion, ""),
+			})
+		}
+
+		costComponents = append(costComponents, s3ApiCostComponent(fmt.Sprintf("Replication PUT requests to %s", name), "AmazonS3", requestsRegion, "Requests-Tier1", a.MonthlyReplicatedObjects))
+	}
+
+	return costComponents
+}
@@ -18,13 +18,22 @@ type SFnStateMachine struct {
 	WorkflowDurationMs *int64 `infracost_usage:"workflow_duration_ms"`
 	MemoryMB           *int64 `infracost_usage:"memory_mb"`
 	MonthlyTransitions *int64 `infracost_usage:"monthly_transitions"`
+
+	TransitionsPerRequest *int64 `infracost_usage:"transitions_per_request"`
+}
+
+func (r *SFnStateMachine) CoreType() string {
+	return "SFnStateMachine"
 }
 
-var SFnStateMachineUsageSchema = []*schema.UsageItem{
-	{Key: "monthly_requests", ValueType: schema.Int64, DefaultValue: 0},
-	{Key: "workflow_duration_ms", ValueType: schema.Int64, DefaultValue: 0},
-	{Key: "memory_mb", ValueType: schema.Int64, DefaultValue: 0},
-	{Key: "monthly_transitions", ValueType: schema.Int64, DefaultValue: 0},
+func (r *SFnStateMachine) UsageSchema() []*schema.UsageItem {
+	return []*schema.UsageItem{
+		{Key: "monthly_requests", ValueType: schema.Int64, DefaultValue: 0},
+		{Key: "workflow_duration_ms", ValueType: schema.Int64, DefaultValue: 0},
+		{Key: "memory_mb", ValueType: schema.Int64, DefaultValue: 0},
+		{Key: "monthly_transitions", ValueType: schema.Int64, DefaultValue: 0},
+		{Key: "transitions_per_request", ValueType: schema.Int64, DefaultValue: 0},
+	}
 }
 
 func (r *SFnStateMachine) PopulateUsage(u *schema.UsageData) {
@@ -43,6 +52,10 @@ func (r *SFnStateMachine) BuildResource() *schema.Resource {
 		var transitions *decimal.Decimal
 		if r.MonthlyTransitions != nil {
 			transitions = decimalPtr(decimal.NewFromInt(*r.MonthlyTransitions))
+		} else if r.MonthlyRequests != nil && r.TransitionsPerRequest != nil {
+			// Standard workflows are billed per state transition, so estimate
+			// these from the number of executions if they aren't given directly.
+			transitions = decimalPtr(decimal.NewFromInt(*r.MonthlyRequests * *r.TransitionsPerRequest))
 		}
 		costComponents = append(costComponents, r.transistionsCostComponent(transitions))
 	}
@@ -79,7 +92,7 @@ func (r *SFnStateMachine) BuildResource() *schema.Resource {
 	return &schema.Resource{
 		Name:           r.Address,
 		CostComponents: costComponents,
-		UsageSchema:    SFnStateMachineUsageSchema,
+		UsageSchema:    r.UsageSchema(),
 	}
 }
 
@@ -0,0 +1,153 @@
+package azure
+
+import (
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/resources"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// ContainerApp struct represents an Azure Container App. Apps running on the
+// Consumption workload profile are billed per second for the vCPU and memory
+// allocated to their replicas, plus the requests they receive. Apps running on
+// a dedicated workload profile are billed through the instances of the
+// profile, which are priced in ContainerAppEnvironment.
+//
+// Resource information: https://learn.microsoft.com/en-us/azure/container-apps/overview
+// Pricing information: https://azure.microsoft.com/en-us/pricing/details/container-apps/
+type ContainerApp struct {
+	Address string
+	Region  string
+
+	WorkloadProfileName string
+	VCPU                float64
+	MemoryGiB           float64
+	MinReplicas         int64
+
+	// "usage" args
+	MonthlyVCPUSeconds *float64 `infracost_usage:"monthly_vcpu_seconds"`
+	MonthlyGiBSeconds  *float64 `infracost_usage:"monthly_gib_seconds"`
+	MonthlyRequests    *int64   `infracost_usage:"monthly_requests"`
+}
+
+// ContainerAppUsageSchema defines a list which represents the usage schema of ContainerApp.
+var ContainerAppUsageSchema = []*schema.UsageItem{
+	{Key: "monthly_vcpu_seconds", DefaultValue: 0, ValueType: schema.Float64},
+	{Key: "monthly_gib_seconds", DefaultValue: 0, ValueType: schema.Float64},
+	{Key: "monthly_requests", DefaultValue: 0, ValueType: schema.Int64},
+}
+
+// PopulateUsage parses the u schema.UsageData into the ContainerApp.
+// It uses the `infracost_usage` struct tags to populate data into the ContainerApp.
+func (r *ContainerApp) PopulateUsage(u *schema.UsageData) {
+	resources.PopulateArgsWithUsage(r, u)
+}
+
+// BuildResource builds a schema.Resource from a valid ContainerApp struct.
+// This method is called after the resource is initialised by an IaC provider.
+// See providers folder for more information.
+func (r *ContainerApp) BuildResource() *schema.Resource {
+	if !r.isConsumption() {
+		return &schema.Resource{
+			Name:        r.Address,
+			NoPrice:     true,
+			IsSkipped:   true,
+			UsageSchema: ContainerAppUsageSchema,
+		}
+	}
+
+	return &schema.Resource{
+		Name:        r.Address,
+		UsageSchema: ContainerAppUsageSchema,
+		CostComponents: []*schema.CostComponent{
+			r.vCPUCostComponent(),
+			r.memoryCostComponent(),
+			r.requestsCostComponent(),
+		},
+	}
+}
+
+func (r *ContainerApp) isConsumption() bool {
+	return r.WorkloadProfileName == "" || r.WorkloadProfileName == "Consumption"
+}
+
+// minReplicaSeconds returns the seconds that the minimum replicas of the app
+// run for in a month. These are used when no usage is given since they are
+// always running, even when the app isn't receiving requests.
+func (r *ContainerApp) minReplicaSeconds() *decimal.Decimal {
+	if r.MinReplicas <= 0 {
+		return nil
+	}
+
+	return decimalPtr(decimal.NewFromInt(r.MinReplicas).Mul(schema.HourToMonthUnitMultiplier).Mul(decimal.NewFromInt(3600)))
+}
+
+func (r *ContainerApp) vCPUCostComponent() *schema.CostComponent {
+	var quantity *decimal.Decimal
+	if r.MonthlyVCPUSeconds != nil {
+		quantity = decimalPtr(decimal.NewFromFloat(*r.MonthlyVCPUSeconds))
+	} else if seconds := r.minReplicaSeconds(); seconds != nil {
+		quantity = decimalPtr(seconds.Mul(decimal.NewFromFloat(r.VCPU)))
+	}
+
+	return &schema.CostComponent{
+		Name:            "vCPU usage",
+		Unit:            "vCPU-seconds",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: quantity,
+		ProductFilter:   r.productFilter("Standard vCPU Active Usage"),
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("Consumption"),
+		},
+	}
+}
+
+func (r *ContainerApp) memoryCostComponent() *schema.CostComponent {
+	var quantity *decimal.Decimal
+	if r.MonthlyGiBSeconds != nil {
+		quantity = decimalPtr(decimal.NewFromFloat(*r.MonthlyGiBSeconds))
+	} else if seconds := r.minReplicaSeconds(); seconds != nil {
+		quantity = decimalPtr(seconds.Mul(decimal.NewFromFloat(r.MemoryGiB)))
+	}
+
+	return &schema.CostComponent{
+		Name:            "Memory usage",
+		Unit:            "GiB-seconds",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: quantity,
+		ProductFilter:   r.productFilter("Standard Memory Active Usage"),
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("Consumption"),
+		},
+	}
+}
+
+func (r *ContainerApp) requestsCostComponent() *schema.CostComponent {
+	// Azure's pricing API returns prices per 1M requests
+	var quantity *decimal.Decimal
+	if r.MonthlyRequests != nil {
+		quantity = decimalPtr(decimal.NewFromInt(*r.MonthlyRequests).Div(decimal.NewFromInt(1000000)))
+	}
+
+	return &schema.CostComponent{
+		Name:            "Requests",
+		Unit:            "1M requests",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: quantity,
+		ProductFilter:   r.productFilter("Standard Requests"),
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("Consumption"),
+		},
+	}
+}
+
+func (r *ContainerApp) productFilter(meterName string) *schema.ProductFilter {
+	return &schema.ProductFilter{
+		VendorName: strPtr("azure"),
+		Region:     strPtr(r.Region),
+		Service:    strPtr("Azure Container Apps"),
+		AttributeFilters: []*schema.AttributeFilter{
+			{Key: "meterName", Value: strPtr(meterName)},
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package azure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+type containerAppWorkloadProfileSpec struct {
+	vCPU      int64
+	memoryGiB int64
+	category  string
+}
+
+var containerAppWorkloadProfileSpecs = map[string]containerAppWorkloadProfileSpec{
+	"D4":  {vCPU: 4, memoryGiB: 16, category: "General Purpose"},
+	"D8":  {vCPU: 8, memoryGiB: 32, category: "General Purpose"},
+	"D16": {vCPU: 16, memoryGiB: 64, category: "General Purpose"},
+	"D32": {vCPU: 32, memoryGiB: 128, category: "General Purpose"},
+	"E4":  {vCPU: 4, memoryGiB: 32, category: "Memory Optimized"},
+	"E8":  {vCPU: 8, memoryGiB: 64, category: "Memory Optimized"},
+	"E16": {vCPU: 16, memoryGiB: 128, category: "Memory Optimized"},
+	"E32": {vCPU: 32, memoryGiB: 256, category: "Memory Optimized"},
+}
+
+// ContainerAppWorkloadProfile is a dedicated workload profile of a Container
+// App Environment.
+type ContainerAppWorkloadProfile struct {
+	Name         string
+	Type         string
+	MinimumCount int64
+}
+
+// ContainerAppEnvironment struct represents an Azure Container App
+// Environment. Environments are free unless they have dedicated workload
+// profiles, in which case they are billed an hourly management fee plus the
+// vCPU and memory of the running profile instances.
+//
+// Resource information: https://learn.microsoft.com/en-us/azure/container-apps/workload-profiles-overview
+// Pricing information: https://azure.microsoft.com/en-us/pricing/details/container-apps/
+type ContainerAppEnvironment struct {
+	Address string
+	Region  string
+
+	WorkloadProfiles []ContainerAppWorkloadProfile
+}
+
+// ContainerAppEnvironmentUsageSchema defines a list which represents the usage schema of ContainerAppEnvironment.
+var ContainerAppEnvironmentUsageSchema = []*schema.UsageItem{}
+
+// PopulateUsage parses the u schema.UsageData into the ContainerAppEnvironment.
+// It uses the `infracost_usage` struct tags to populate data into the ContainerAppEnvironment.
+func (r *ContainerAppEnvironment) PopulateUsage(u *schema.UsageData) {
+	resources.PopulateArgsWithUsage(r, u)
+}
+
+// BuildResource builds a schema.Resource from a valid ContainerAppEnvironment struct.
+// This method is called after the resource is initialised by an IaC provider.
+// See providers folder for more information.
+func (r *ContainerAppEnvironment) BuildResource() *schema.Resource {
+	costComponents := []*schema.CostComponent{}
+
+	for _, profile := range r.WorkloadProfiles {
+		if profile.Type == "Consumption" {
+			continue
+		}
+
+		spec, ok := containerAppWorkloadProfileSpecs[strings.ToUpper(profile.Type)]
+		if !ok {
+			log.Warnf("Skipping workload profile %s for resource %s. Unsupported workload profile type %s", profile.Name, r.Address, profile.Type)
+			continue
+		}
+
+		costComponents = append(costComponents, r.workloadProfileCostComponents(profile, spec)...)
+	}
+
+	if len(costComponents) == 0 {
+		return &schema.Resource{
+			Name:        r.Address,
+			NoPrice:     true,
+			IsSkipped:   true,
+			UsageSchema: ContainerAppEnvironmentUsageSchema,
+		}
+	}
+
+	costComponents = append([]*schema.CostComponent{r.managementCostComponent()}, costComponents...)
+
+	return &schema.Resource{
+		Name:           r.Address,
+		UsageSchema:    ContainerAppEnvironmentUsageSchema,
+		CostComponents: costComponents,
+	}
+}
+
+func (r *ContainerAppEnvironment) managementCostComponent() *schema.CostComponent {
+	return &schema.CostComponent{
+		Name:           "Dedicated plan management",
+		Unit:           "hours",
+		UnitMultiplier: decimal.NewFromInt(1),
+		HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		ProductFilter:  r.productFilter("Dedicated Plan Management"),
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("Consumption"),
+		},
+	}
+}
+
+// workloadProfileCostComponents returns the vCPU and memory cost components
+// for the minimum number of instances of a dedicated workload profile.
+func (r *ContainerAppEnvironment) workloadProfileCostComponents(profile ContainerAppWorkloadProfile, spec containerAppWorkloadProfileSpec) []*schema.CostComponent {
+	instances := decimal.NewFromInt(profile.MinimumCount)
+
+	return []*schema.CostComponent{
+		{
+			Name:           fmt.Sprintf("Workload profile vCPU (%s, %s)", profile.Name, strings.ToUpper(profile.Type)),
+			Unit:           "vCPU",
+			UnitMultiplier: schema.HourToMonthUnitMultiplier,
+			HourlyQuantity: decimalPtr(instances.Mul(decimal.NewFromInt(spec.vCPU))),
+			ProductFilter:  r.productFilter(fmt.Sprintf("Dedicated %s vCPU Usage", spec.category)),
+			PriceFilter: &schema.PriceFilter{
+				PurchaseOption: strPtr("Consumption"),
+			},
+		},
+		{
+			Name:           fmt.Sprintf("Workload profile memory (%s, %s)", profile.Name, strings.ToUpper(profile.Type)),
+			Unit:           "GiB",
+			UnitMultiplier: schema.HourToMonthUnitMultiplier,
+			HourlyQuantity: decimalPtr(instances.Mul(decimal.NewFromInt(spec.memoryGiB))),
+			ProductFilter:  r.productFilter(fmt.Sprintf("Dedicated %s Memory Usage", spec.category)),
+			PriceFilter: &schema.PriceFilter{
+				PurchaseOption: strPtr("Consumption"),
+			},
+		},
+	}
+}
+
+func (r *ContainerAppEnvironment) productFilter(meterName string) *schema.ProductFilter {
+	return &schema.ProductFilter{
+		VendorName: strPtr("azure"),
+		Region:     strPtr(r.Region),
+		Service:    strPtr("Azure Container Apps"),
+		AttributeFilters: []*schema.AttributeFilter{
+			{Key: "meterName", Value: strPtr(meterName)},
+		},
+	}
+}
@@ -0,0 +1,193 @@
+package azure
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// PurchaseOptionResourceTypes are the resource types that support the
+// purchase_option usage key. The azure_purchase_option in the config file is
+// applied as the default purchase_option of these resource types.
+var PurchaseOptionResourceTypes = []string{
+	"azurerm_cosmosdb_cassandra_keyspace",
+	"azurerm_cosmosdb_cassandra_table",
+	"azurerm_cosmosdb_gremlin_database",
+	"azurerm_cosmosdb_gremlin_graph",
+	"azurerm_cosmosdb_mongo_collection",
+	"azurerm_cosmosdb_mongo_database",
+	"azurerm_cosmosdb_sql_container",
+	"azurerm_cosmosdb_sql_database",
+	"azurerm_cosmosdb_table",
+	"azurerm_linux_virtual_machine",
+	"azurerm_linux_virtual_machine_scale_set",
+	"azurerm_mssql_database",
+	"azurerm_sql_database",
+	"azurerm_virtual_machine",
+	"azurerm_virtual_machine_scale_set",
+	"azurerm_windows_virtual_machine",
+	"azurerm_windows_virtual_machine_scale_set",
+}
+
+// PurchaseOption is how the compute of a resource is bought instead of paying
+// as you go, i.e. a reservation, a savings plan or spot capacity.
+type PurchaseOption struct {
+	Label       string
+	SavingsPlan bool
+	Spot        bool
+	termLength  string
+	months      int64
+
+	// SpotDiscountRate is the historical discount of spot prices that is used
+	// if the pricing service has no spot price, e.g. 0.7 for 70%.
+	SpotDiscountRate *float64
+	address          string
+
+	// spotSkuNameRe matches the SKU of the discounted capacity and
+	// discountRateKey is the usage key of SpotDiscountRate. They default to the
+	// spot SKU and spot_discount_rate.
+	spotSkuNameRe   string
+	discountRateKey string
+}
+
+var purchaseOptions = map[string]PurchaseOption{
+	"reserved_1_year":      {Label: "reserved 1 year", termLength: "1 Year", months: 12},
+	"reserved_3_years":     {Label: "reserved 3 years", termLength: "3 Years", months: 36},
+	"savings_plan_1_year":  {Label: "savings plan 1 year", SavingsPlan: true, termLength: "1 Year", months: 12},
+	"savings_plan_3_years": {Label: "savings plan 3 years", SavingsPlan: true, termLength: "3 Years", months: 36},
+	"spot":                 {Label: "spot", Spot: true},
+}
+
+// PayAsYouGoPurchaseOption is the purchase_option value of resources that
+// aren't bought with a commitment. It can be used to opt a resource out of
+// the azure_purchase_option set in the config file.
+const PayAsYouGoPurchaseOption = "pay_as_you_go"
+
+// ParsePurchaseOption returns the PurchaseOption for the purchase_option
+// usage key of a resource. It returns nil if the resource is paid as you go,
+// if value isn't valid or if value is a savings plan or spot and the resource
+// isn't a virtual machine, since they only apply to virtual machines.
+func ParsePurchaseOption(address string, value *string, virtualMachine bool) *PurchaseOption {
+	if value == nil || *value == "" || *value == PayAsYouGoPurchaseOption {
+		return nil
+	}
+
+	p, ok := purchaseOptions[*value]
+	if !ok {
+		valid := []string{PayAsYouGoPurchaseOption}
+		for k := range purchaseOptions {
+			valid = append(valid, k)
+		}
+		sort.Strings(valid)
+
+		log.Warnf("Invalid purchase_option for %s, using pay as you go prices. Expected: %s. Got: %s", address, strings.Join(valid, ", "), *value)
+		return nil
+	}
+
+	if p.SavingsPlan && !virtualMachine {
+		log.Warnf("Savings plans don't apply to %s, using pay as you go prices", address)
+		return nil
+	}
+
+	if p.Spot && !virtualMachine {
+		log.Warnf("Spot capacity doesn't apply to %s, using pay as you go prices", address)
+		return nil
+	}
+
+	p.address = address
+
+	return &p
+}
+
+// NewLowPriorityPurchaseOption returns the purchase option of low-priority
+// Batch nodes. They're priced like spot capacity but with the Low Priority SKU
+// of the virtual machine, with a fallback to the pay as you go SKU discounted
+// by discountRate.
+func NewLowPriorityPurchaseOption(address string, discountRate *float64) *PurchaseOption {
+	return &PurchaseOption{
+		Label:            "low priority",
+		Spot:             true,
+		SpotDiscountRate: discountRate,
+		address:          address,
+		spotSkuNameRe:    "/ Low Priority$/i",
+		discountRateKey:  "low_priority_discount_rate",
+	}
+}
+
+// Apply prices c with the purchase option. Spot capacity and savings plans
+// have discounted hourly prices so only the filters of c are changed.
+//
+// Reservations are paid for the whole term whether the resource is running or
+// not. units is the number of units that are reserved, e.g. the number of
+// instances, and unitQuantity is the monthly quantity of one unit that c shows,
+// e.g. 730 hours. The reservation price of the term is spread over its months.
+func (p *PurchaseOption) Apply(c *schema.CostComponent, units, unitQuantity decimal.Decimal) {
+	if p.Spot {
+		p.applySpot(c)
+		return
+	}
+
+	priceFilter := schema.PriceFilter{}
+	if c.PriceFilter != nil {
+		priceFilter = *c.PriceFilter
+	}
+	priceFilter.TermLength = strPtr(p.termLength)
+	c.PriceFilter = &priceFilter
+
+	if p.SavingsPlan {
+		priceFilter.PurchaseOption = strPtr("SavingsPlan")
+		return
+	}
+
+	months := decimal.NewFromInt(p.months)
+
+	priceFilter.PurchaseOption = strPtr("Reservation")
+	c.HourlyQuantity = nil
+	c.MonthlyQuantity = decimalPtr(units.Div(months))
+	c.UnitMultiplier = decimal.NewFromInt(1).Div(months.Mul(unitQuantity))
+}
+
+// applySpot prices c with the spot SKU of its product, with a fallback to the
+// pay as you go SKU discounted by SpotDiscountRate.
+func (p *PurchaseOption) applySpot(c *schema.CostComponent) {
+	if p.SpotDiscountRate != nil && *p.SpotDiscountRate != 0 {
+		if *p.SpotDiscountRate < 0 || *p.SpotDiscountRate >= 1 {
+			key := "spot_discount_rate"
+			if p.discountRateKey != "" {
+				key = p.discountRateKey
+			}
+
+			log.Warnf("Invalid %s for %s, ignoring it. Expected a value between 0 and 1. Got: %v", key, p.address, *p.SpotDiscountRate)
+		} else {
+			c.PriceFallback = &schema.PriceFallback{
+				ProductFilter: c.ProductFilter,
+				PriceFilter:   c.PriceFilter,
+				DiscountPerc:  *p.SpotDiscountRate,
+			}
+		}
+	}
+
+	productFilter := schema.ProductFilter{}
+	if c.ProductFilter != nil {
+		productFilter = *c.ProductFilter
+	}
+
+	skuNameRe := "/ Spot$/i"
+	if p.spotSkuNameRe != "" {
+		skuNameRe = p.spotSkuNameRe
+	}
+
+	spotFilter := &schema.AttributeFilter{Key: "skuName", ValueRegex: strPtr(skuNameRe)}
+	attributeFilters := make([]*schema.AttributeFilter, 0, len(productFilter.AttributeFilters)+1)
+	for _, f := range productFilter.AttributeFilters {
+		if f.Key != "skuName" {
+			attributeFilters = append(attributeFilters, f)
+		}
+	}
+	productFilter.AttributeFilters = append(attributeFilters, spotFilter)
+	c.ProductFilter = &productFilter
+}
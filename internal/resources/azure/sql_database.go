@@ -66,6 +66,9 @@ type SQLDatabase struct {
 	LongTermRetentionStorageGB *int64 `infracost_usage:"long_term_retention_storage_gb"`
 	// BackupStorageGB defines a usage param that allows users to define how many GB Point-In-Time Restore (PITR) backup storage the database uses.
 	BackupStorageGB *int64 `infracost_usage:"backup_storage_gb"`
+	// PurchaseOption defines a usage param that allows users to price the provisioned compute of vCore databases
+	// with a reservation, e.g. reserved_1_year.
+	PurchaseOption *string `infracost_usage:"purchase_option"`
 }
 
 // PopulateUsage parses the u schema.UsageData into the SQLDatabase.
@@ -78,6 +81,7 @@ var SQLDatabaseUsageSchema = []*schema.UsageItem{
 	{Key: "monthly_vcore_hours", DefaultValue: 0, ValueType: schema.Int64},
 	{Key: "long_term_retention_storage_gb", DefaultValue: 0, ValueType: schema.Int64},
 	{Key: "backup_storage_gb", DefaultValue: 0, ValueType: schema.Int64},
+	{Key: "purchase_option", DefaultValue: "", ValueType: schema.String},
 }
 
 // BuildResource builds a schema.Resource from a valid SQLDatabase.
@@ -265,24 +269,37 @@ func (r *SQLDatabase) provisionedComputeCostComponents() []*schema.CostComponent
 	}
 
 	productNameRegex := fmt.Sprintf("/%s - %s/", r.Tier, r.Family)
-	name := fmt.Sprintf("Compute (provisioned, %s)", r.SKU)
+
+	// Savings plans don't apply to SQL databases, only reservations
+	commitment := ParsePurchaseOption(r.Address, r.PurchaseOption, false)
+
+	purchaseOptionLabel := "provisioned"
+	if commitment != nil {
+		purchaseOptionLabel = commitment.Label
+	}
+
+	name := fmt.Sprintf("Compute (%s, %s)", purchaseOptionLabel, r.SKU)
 
 	log.Warnf("'Multiple products found' are safe to ignore for '%s' due to limitations in the Azure API.", name)
 
-	costComponents := []*schema.CostComponent{
-		{
-			Name:           name,
-			Unit:           "hours",
-			UnitMultiplier: decimal.NewFromInt(1),
-			HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
-			ProductFilter: r.productFilter([]*schema.AttributeFilter{
-				{Key: "productName", ValueRegex: strPtr(productNameRegex)},
-				{Key: "skuName", Value: strPtr(fmt.Sprintf("%d vCore", cores))},
-			}),
-			PriceFilter: priceFilterConsumption,
-		},
+	compute := &schema.CostComponent{
+		Name:           name,
+		Unit:           "hours",
+		UnitMultiplier: decimal.NewFromInt(1),
+		HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		ProductFilter: r.productFilter([]*schema.AttributeFilter{
+			{Key: "productName", ValueRegex: strPtr(productNameRegex)},
+			{Key: "skuName", Value: strPtr(fmt.Sprintf("%d vCore", cores))},
+		}),
+		PriceFilter: priceFilterConsumption,
 	}
 
+	if commitment != nil {
+		commitment.Apply(compute, decimal.NewFromInt(1), schema.HourToMonthUnitMultiplier)
+	}
+
+	costComponents := []*schema.CostComponent{compute}
+
 	if r.ZoneRedundant {
 		costComponents = append(costComponents, &schema.CostComponent{
 			Name:           fmt.Sprintf("Zone redundancy (provisioned, %s)", r.SKU),
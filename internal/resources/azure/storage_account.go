@@ -32,6 +32,8 @@ type StorageAccount struct {
 	AccountTier            string
 	NFSv3                  bool
 
+	productName string
+
 	// "usage" args
 	MonthlyStorageGB                        *float64 `infracost_usage:"storage_gb"`
 	MonthlyIterativeReadOperations          *int64   `infracost_usage:"monthly_iterative_read_operations"`
@@ -47,8 +49,33 @@ type StorageAccount struct {
 	SnapshotsStorageGB                      *float64 `infracost_usage:"snapshots_storage_gb"`
 	MetadataAtRestStorageGB                 *float64 `infracost_usage:"metadata_at_rest_storage_gb"`
 	EarlyDeletionGB                         *float64 `infracost_usage:"early_deletion_gb"`
+	ReservedCapacityTerm                    *string  `infracost_usage:"reserved_capacity_term"`
+	ReservedCapacity100TBBlocks             *int64   `infracost_usage:"reserved_capacity_100tb_blocks"`
+	ReservedCapacity1PBBlocks               *int64   `infracost_usage:"reserved_capacity_1pb_blocks"`
+}
+
+// storageReservedCapacityTerms maps the reserved_capacity_term usage values to
+// the term lengths of the reservations and the number of months they last.
+var storageReservedCapacityTerms = map[string]struct {
+	termLength string
+	months     int64
+}{
+	"1_year":  {termLength: "1 Year", months: 12},
+	"3_years": {termLength: "3 Years", months: 36},
 }
 
+// storageReservedCapacityBlock is a block of capacity that can be reserved.
+type storageReservedCapacityBlock struct {
+	name string
+	unit string
+	gb   int64
+}
+
+var (
+	storageReservedCapacity100TB = storageReservedCapacityBlock{name: "100TB", unit: "100 TB/Month", gb: 102400}
+	storageReservedCapacity1PB   = storageReservedCapacityBlock{name: "1PB", unit: "1 PB/Month", gb: 1048576}
+)
+
 // StorageAccountUsageSchema defines a list which represents the usage schema of StorageAccount.
 var StorageAccountUsageSchema = []*schema.UsageItem{
 	{Key: "storage_gb", DefaultValue: 0, ValueType: schema.Float64},
@@ -65,6 +92,9 @@ var StorageAccountUsageSchema = []*schema.UsageItem{
 	{Key: "snapshots_storage_gb", DefaultValue: 0, ValueType: schema.Float64},
 	{Key: "metadata_at_rest_storage_gb", DefaultValue: 0, ValueType: schema.Float64},
 	{Key: "early_deletion_gb", DefaultValue: 0, ValueType: schema.Float64},
+	{Key: "reserved_capacity_term", DefaultValue: "", ValueType: schema.String},
+	{Key: "reserved_capacity_100tb_blocks", DefaultValue: 0, ValueType: schema.Int64},
+	{Key: "reserved_capacity_1pb_blocks", DefaultValue: 0, ValueType: schema.Int64},
 }
 
 // PopulateUsage parses the u schema.UsageData into the StorageAccount.
@@ -92,8 +122,28 @@ func (r *StorageAccount) BuildResource() *schema.Resource {
 		r.AccessTier = "Premium"
 	}
 
+	r.productName = r.storageAccountProductName()
+
+	costComponents := r.costComponents()
+
+	var recommendations []*schema.Recommendation
+	if rec := r.replicationRecommendation(costComponents); rec != nil {
+		recommendations = append(recommendations, rec)
+	}
+
+	return &schema.Resource{
+		Name:            r.Address,
+		UsageSchema:     StorageAccountUsageSchema,
+		CostComponents:  costComponents,
+		Recommendations: recommendations,
+	}
+}
+
+// costComponents returns the cost components of the Storage Account.
+func (r *StorageAccount) costComponents() []*schema.CostComponent {
 	costComponents := []*schema.CostComponent{}
 
+	costComponents = append(costComponents, r.reservedCapacityCostComponents()...)
 	costComponents = append(costComponents, r.storageCostComponents()...)
 
 	costComponents = append(costComponents, r.dataAtRestCostComponents()...)
@@ -112,42 +162,70 @@ func (r *StorageAccount) BuildResource() *schema.Resource {
 
 	costComponents = append(costComponents, r.earlyDeletionCostComponents()...)
 
-	return &schema.Resource{
-		Name:           r.Address,
-		UsageSchema:    StorageAccountUsageSchema,
-		CostComponents: costComponents,
-	}
+	return costComponents
 }
 
-// buildProductFilter returns a product filter for the Storage Account's products.
-func (r *StorageAccount) buildProductFilter(meterName string) *schema.ProductFilter {
-	var productName string
+// localReplicationTypes maps the geo-redundant replication types to the
+// replication type that only keeps copies of the data in the primary region.
+var localReplicationTypes = map[string]string{
+	"GRS":     "LRS",
+	"RA-GRS":  "LRS",
+	"GZRS":    "ZRS",
+	"RA-GZRS": "ZRS",
+}
 
-	switch {
-	case r.isBlockBlobStorage():
-		productName = map[string]string{
-			"Standard": "Blob Storage",
-			"Premium":  "Premium Block Blob",
-		}[r.AccountTier]
-	case r.isStorageV2():
-		if r.NFSv3 {
-			productName = map[string]string{
-				"Standard": "General Block Blob v2 Hierarchical Namespace",
-				"Premium":  "Premium Block Blob v2 Hierarchical Namespace",
-			}[r.AccountTier]
-		} else {
-			productName = map[string]string{
-				"Standard": "General Block Blob v2",
-				"Premium":  "Premium Block Blob",
-			}[r.AccountTier]
+// replicationRecommendation returns the quick win to replace the cost
+// components of a geo-redundant Storage Account with the cost components of
+// the same account replicated in the primary region only. Only the cost
+// components that have a quantity are replaced, as the others have no cost.
+// It returns nil if the account isn't geo-redundant.
+func (r *StorageAccount) replicationRecommendation(costComponents []*schema.CostComponent) *schema.Recommendation {
+	replicationType, ok := localReplicationTypes[strings.ToUpper(r.AccountReplicationType)]
+	if !ok {
+		return nil
+	}
+
+	alternative := *r
+	alternative.AccountReplicationType = replicationType
+	if !alternative.isReplicationTypeSupported() {
+		return nil
+	}
+	alternative.productName = alternative.storageAccountProductName()
+
+	var alternativeCostComponents []*schema.CostComponent
+	for _, c := range alternative.costComponents() {
+		if hasQuantity(c) {
+			c.IgnoreIfMissingPrice = true
+			alternativeCostComponents = append(alternativeCostComponents, c)
 		}
-	case r.isFileStorage():
-		productName = map[string]string{
-			"Standard": "Files v2",
-			"Premium":  "Premium Files",
-		}[r.AccountTier]
 	}
 
+	var replacedCostComponents []*schema.CostComponent
+	for _, c := range costComponents {
+		if hasQuantity(c) {
+			replacedCostComponents = append(replacedCostComponents, c)
+		}
+	}
+
+	return &schema.Recommendation{
+		Type:           schema.RecommendationTypeQuickWin,
+		Description:    fmt.Sprintf("Use %s instead of %s replication if the data doesn't need to be available in a secondary region", replicationType, r.AccountReplicationType),
+		CostComponents: replacedCostComponents,
+		Alternative: &schema.Resource{
+			Name:           r.Address,
+			CostComponents: alternativeCostComponents,
+		},
+	}
+}
+
+// hasQuantity returns whether the cost component has a quantity, which is
+// missing if the usage it's based on isn't known.
+func hasQuantity(c *schema.CostComponent) bool {
+	return c.MonthlyQuantity != nil || c.HourlyQuantity != nil
+}
+
+// buildProductFilter returns a product filter for the Storage Account's products.
+func (r *StorageAccount) buildProductFilter(meterName string) *schema.ProductFilter {
 	skuName := fmt.Sprintf("%s %s", r.AccessTier, r.AccountReplicationType)
 
 	return &schema.ProductFilter{
@@ -156,9 +234,9 @@ func (r *StorageAccount) buildProductFilter(meterName string) *schema.ProductFil
 		Service:       strPtr("Storage"),
 		ProductFamily: strPtr("Storage"),
 		AttributeFilters: []*schema.AttributeFilter{
-			{Key: "productName", Value: strPtr(productName)},
+			{Key: "productName", Value: strPtr(r.productName)},
 			{Key: "skuName", Value: strPtr(skuName)},
-			{Key: "meterName", ValueRegex: regexPtr(fmt.Sprintf("%s$", meterName))},
+			storageMeterNameFilter(storageMeterNames(r.AccessTier, r.AccountReplicationType, meterName)),
 		},
 	}
 }
@@ -201,7 +279,7 @@ func (r *StorageAccount) storageCostComponents() []*schema.CostComponent {
 		return costComponents
 	}
 
-	quantity = decimalPtr(decimal.NewFromFloat(*r.MonthlyStorageGB))
+	quantity = r.unreservedCapacityGB(decimal.NewFromFloat(*r.MonthlyStorageGB))
 
 	// Only Hot storage has pricing tiers, others have a single price for any
 	// amount.
@@ -241,6 +319,96 @@ func (r *StorageAccount) storageCostComponents() []*schema.CostComponent {
 	return costComponents
 }
 
+// reservedCapacityCostComponents returns the cost components for the blocks of
+// reserved capacity. Reserved capacity is billed whether it is used or not, and
+// it is deducted from the capacity in Blob Storage, or the data at rest in File
+// Storage, that is billed at the pay-as-you-go price.
+//
+// The reservation prices are for the whole term, so they're spread over the
+// months of the term.
+func (r *StorageAccount) reservedCapacityCostComponents() []*schema.CostComponent {
+	costComponents := []*schema.CostComponent{}
+
+	if r.ReservedCapacityTerm == nil || *r.ReservedCapacityTerm == "" {
+		return costComponents
+	}
+
+	term, ok := storageReservedCapacityTerms[*r.ReservedCapacityTerm]
+	if !ok {
+		log.Warnf("Invalid reserved_capacity_term for %s, ignoring reserved capacity. Expected: 1_year, 3_years. Got: %s", r.Address, *r.ReservedCapacityTerm)
+		return costComponents
+	}
+
+	blocks := []struct {
+		block storageReservedCapacityBlock
+		count *int64
+	}{
+		{block: storageReservedCapacity100TB, count: r.ReservedCapacity100TBBlocks},
+		{block: storageReservedCapacity1PB, count: r.ReservedCapacity1PBBlocks},
+	}
+
+	months := decimal.NewFromInt(term.months)
+
+	for _, b := range blocks {
+		if b.count == nil || *b.count <= 0 {
+			continue
+		}
+
+		costComponents = append(costComponents, &schema.CostComponent{
+			Name:            fmt.Sprintf("Reserved capacity (%s, %s)", b.block.name, strings.ToLower(term.termLength)),
+			Unit:            b.block.name,
+			UnitMultiplier:  decimal.NewFromInt(1).Div(months),
+			MonthlyQuantity: decimalPtr(decimal.NewFromInt(*b.count).Div(months)),
+			ProductFilter:   r.buildProductFilter(r.capacityMeterName()),
+			PriceFilter: &schema.PriceFilter{
+				PurchaseOption: strPtr("Reservation"),
+				TermLength:     strPtr(term.termLength),
+				Unit:           strPtr(b.block.unit),
+			},
+		})
+	}
+
+	return costComponents
+}
+
+// reservedCapacityGB returns the amount of capacity in GB that is covered by
+// the blocks of reserved capacity.
+func (r *StorageAccount) reservedCapacityGB() decimal.Decimal {
+	if r.ReservedCapacityTerm == nil {
+		return decimal.Zero
+	}
+
+	if _, ok := storageReservedCapacityTerms[*r.ReservedCapacityTerm]; !ok {
+		return decimal.Zero
+	}
+
+	reserved := decimal.Zero
+	if r.ReservedCapacity100TBBlocks != nil && *r.ReservedCapacity100TBBlocks > 0 {
+		reserved = reserved.Add(decimal.NewFromInt(*r.ReservedCapacity100TBBlocks * storageReservedCapacity100TB.gb))
+	}
+	if r.ReservedCapacity1PBBlocks != nil && *r.ReservedCapacity1PBBlocks > 0 {
+		reserved = reserved.Add(decimal.NewFromInt(*r.ReservedCapacity1PBBlocks * storageReservedCapacity1PB.gb))
+	}
+
+	return reserved
+}
+
+// unreservedCapacityGB returns the part of capacity that isn't covered by
+// reserved capacity.
+func (r *StorageAccount) unreservedCapacityGB(capacity decimal.Decimal) *decimal.Decimal {
+	return decimalPtr(decimal.Max(capacity.Sub(r.reservedCapacityGB()), decimal.Zero))
+}
+
+// capacityMeterName returns the meter name of the capacity that reservations
+// apply to.
+func (r *StorageAccount) capacityMeterName() string {
+	if r.isFileStorage() && r.isPremium() {
+		return "Provisioned"
+	}
+
+	return "Data Stored"
+}
+
 // iterativeWriteOperationsCostComponents returns a cost component for Iterative
 // Write Operations.
 //
@@ -325,9 +493,6 @@ func (r *StorageAccount) writeOperationsCostComponents() []*schema.CostComponent
 	}
 
 	meterName := "Write Operations"
-	if r.isStorageV2() && r.NFSv3 {
-		meterName = "(?<!Iterative) Write Operations"
-	}
 
 	costComponents = append(costComponents, &schema.CostComponent{
 		Name:                 "Write operations",
@@ -488,9 +653,6 @@ func (r *StorageAccount) readOperationsCostComponents() []*schema.CostComponent
 	}
 
 	meterName := "Read Operations"
-	if r.isStorageV2() && r.NFSv3 {
-		meterName = "(?<!Iterative) Read Operations"
-	}
 
 	costComponents = append(costComponents, &schema.CostComponent{
 		Name:                 "Read operations",
@@ -743,13 +905,10 @@ func (r *StorageAccount) dataAtRestCostComponents() []*schema.CostComponent {
 	var quantity *decimal.Decimal
 
 	if r.DataAtRestStorageGB != nil {
-		quantity = decimalPtr(decimal.NewFromFloat(*r.DataAtRestStorageGB))
+		quantity = r.unreservedCapacityGB(decimal.NewFromFloat(*r.DataAtRestStorageGB))
 	}
 
-	meterName := "Data Stored"
-	if r.isPremium() {
-		meterName = "Provisioned"
-	}
+	meterName := r.capacityMeterName()
 
 	costComponents = append(costComponents, &schema.CostComponent{
 		Name:            "Data at rest",
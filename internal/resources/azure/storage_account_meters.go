@@ -0,0 +1,94 @@
+package azure
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// storageAccountProductNames maps the account kinds of storage accounts to the
+// product names of their meters in each account tier. StorageV2 accounts with
+// NFSv3 enabled use the hierarchical namespace products.
+var storageAccountProductNames = map[string]map[string]string{
+	"blockblobstorage": {
+		"Standard": "Blob Storage",
+		"Premium":  "Premium Block Blob",
+	},
+	"storagev2": {
+		"Standard": "General Block Blob v2",
+		"Premium":  "Premium Block Blob",
+	},
+	"storagev2_nfsv3": {
+		"Standard": "General Block Blob v2 Hierarchical Namespace",
+		"Premium":  "Premium Block Blob v2 Hierarchical Namespace",
+	},
+	"filestorage": {
+		"Standard": "Files v2",
+		"Premium":  "Premium Files",
+	},
+}
+
+// storageMeterAliases are the other names that Azure uses for a meter in some
+// products, e.g. the other operations of General Block Blob v2 are metered as
+// "All Other Operations".
+var storageMeterAliases = map[string][]string{
+	"Other Operations": {"All Other Operations"},
+}
+
+// storageMeterNames returns the exact meter names that meter can have for the
+// SKU of a storage account. Meters are named with the access tier and the
+// replication type of the SKU when their price differs between them, e.g.
+// "Hot LRS Write Operations", with only the access tier when all the
+// replication types share the price, e.g. "Hot Read Operations", or without
+// either in older products.
+func storageMeterNames(accessTier, replicationType, meter string) []string {
+	meters := append([]string{meter}, storageMeterAliases[meter]...)
+
+	names := make([]string, 0, len(meters)*4)
+	for _, m := range meters {
+		names = append(names,
+			fmt.Sprintf("%s %s %s", accessTier, replicationType, m),
+			fmt.Sprintf("%s %s", accessTier, m),
+			fmt.Sprintf("%s %s", replicationType, m),
+			m,
+		)
+	}
+
+	return names
+}
+
+// storageMeterNameFilter returns an attribute filter that matches any of names
+// exactly, so that meters that end with the same words, e.g. "Write
+// Operations" and "Iterative Write Operations", aren't confused.
+func storageMeterNameFilter(names []string) *schema.AttributeFilter {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = regexp.QuoteMeta(n)
+	}
+
+	return &schema.AttributeFilter{
+		Key:        "meterName",
+		ValueRegex: regexPtr(fmt.Sprintf("^(%s)$", strings.Join(quoted, "|"))),
+	}
+}
+
+// storageAccountProductName returns the product name of the meters of the
+// storage account. It warns if there is no product for the account, since its
+// cost components would otherwise silently have no price.
+func (r *StorageAccount) storageAccountProductName() string {
+	kind := strings.ToLower(r.AccountKind)
+	if kind == "storagev2" && r.NFSv3 {
+		kind = "storagev2_nfsv3"
+	}
+
+	productName, ok := storageAccountProductNames[kind][r.AccountTier]
+	if !ok {
+		log.Warnf("No storage meters found for %s with account kind %s and tier %s, its costs will be 0.00", r.Address, r.AccountKind, r.AccountTier)
+	}
+
+	return productName
+}
@@ -0,0 +1,65 @@
+package azure
+
+import (
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/resources"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// StorageEncryptionScope struct represents an encryption scope of a Storage
+// Account. Each scope is charged a monthly fee, whether it uses Microsoft or
+// customer-managed keys and whether infrastructure encryption is required.
+//
+// Resource information: https://learn.microsoft.com/en-us/azure/storage/blobs/encryption-scope-overview
+// Pricing information: https://azure.microsoft.com/en-us/pricing/details/storage/blobs/
+type StorageEncryptionScope struct {
+	Address string
+	Region  string
+}
+
+func (r *StorageEncryptionScope) CoreType() string {
+	return "StorageEncryptionScope"
+}
+
+func (r *StorageEncryptionScope) UsageSchema() []*schema.UsageItem {
+	return []*schema.UsageItem{}
+}
+
+// PopulateUsage parses the u schema.UsageData into the StorageEncryptionScope.
+// It uses the `infracost_usage` struct tags to populate data into the StorageEncryptionScope.
+func (r *StorageEncryptionScope) PopulateUsage(u *schema.UsageData) {
+	resources.PopulateArgsWithUsage(r, u)
+}
+
+// BuildResource builds a schema.Resource from a valid StorageEncryptionScope struct.
+// This method is called after the resource is initialised by an IaC provider.
+// See providers folder for more information.
+func (r *StorageEncryptionScope) BuildResource() *schema.Resource {
+	return &schema.Resource{
+		Name:           r.Address,
+		UsageSchema:    r.UsageSchema(),
+		CostComponents: []*schema.CostComponent{r.encryptionScopeCostComponent()},
+	}
+}
+
+func (r *StorageEncryptionScope) encryptionScopeCostComponent() *schema.CostComponent {
+	return &schema.CostComponent{
+		Name:            "Encryption scope",
+		Unit:            "months",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("azure"),
+			Region:        strPtr(r.Region),
+			Service:       strPtr("Storage"),
+			ProductFamily: strPtr("Storage"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "meterName", ValueRegex: regexPtr("^Encryption Scopes?$")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("Consumption"),
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package google
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/resources"
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+)
+
+// BigQueryReservation struct represents a BigQuery slot reservation.
+//
+// Baseline slots are billed for every hour that the reservation exists, autoscaled
+// slots are billed for the slot-hours they are used for. The price per slot-hour
+// depends on the BigQuery edition of the reservation.
+//
+// Resource information: https://cloud.google.com/bigquery/docs/reservations-intro
+// Pricing information: https://cloud.google.com/bigquery/pricing#capacity_compute_analysis_pricing
+type BigQueryReservation struct {
+	Address      string
+	Region       string
+	Edition      string
+	SlotCapacity int64
+
+	MonthlyAutoscaleSlotHrs *float64 `infracost_usage:"monthly_autoscale_slot_hrs"`
+}
+
+var BigQueryReservationUsageSchema = []*schema.UsageItem{
+	{Key: "monthly_autoscale_slot_hrs", ValueType: schema.Float64, DefaultValue: 0},
+}
+
+func (r *BigQueryReservation) PopulateUsage(u *schema.UsageData) {
+	resources.PopulateArgsWithUsage(r, u)
+}
+
+func (r *BigQueryReservation) BuildResource() *schema.Resource {
+	costComponents := make([]*schema.CostComponent, 0, 2)
+
+	if r.SlotCapacity > 0 {
+		c := r.slotsCostComponent("Slots (baseline)")
+		c.HourlyQuantity = decimalPtr(decimal.NewFromInt(r.SlotCapacity))
+		costComponents = append(costComponents, c)
+	}
+
+	c := r.slotsCostComponent("Slots (autoscale)")
+	if r.MonthlyAutoscaleSlotHrs != nil {
+		c.MonthlyQuantity = decimalPtr(decimal.NewFromFloat(*r.MonthlyAutoscaleSlotHrs))
+	}
+	costComponents = append(costComponents, c)
+
+	return &schema.Resource{
+		Name:           r.Address,
+		CostComponents: costComponents,
+		UsageSchema:    BigQueryReservationUsageSchema,
+	}
+}
+
+func (r *BigQueryReservation) slotsCostComponent(name string) *schema.CostComponent {
+	edition := strings.ReplaceAll(strings.ToLower(r.Edition), "_", " ")
+
+	return &schema.CostComponent{
+		Name:           fmt.Sprintf("%s, %s", name, edition),
+		Unit:           "slot-hours",
+		UnitMultiplier: decimal.NewFromInt(1),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("gcp"),
+			Region:        strPtr(r.Region),
+			Service:       strPtr("BigQuery Reservation API"),
+			ProductFamily: strPtr("ApplicationServices"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "description", ValueRegex: regexPtr(fmt.Sprintf("^%s edition", edition))},
+			},
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package google
+
+import (
+	"github.com/infracost/infracost/internal/resources"
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+)
+
+// CloudRunV2Job struct represents a Cloud Run job.
+//
+// Jobs always have CPU allocated so each task is billed per vCPU-second and
+// GiB-second for the time that it runs.
+//
+// Resource information: https://cloud.google.com/run/docs/create-jobs
+// Pricing information: https://cloud.google.com/run/pricing
+type CloudRunV2Job struct {
+	Address   string
+	Region    string
+	CPU       float64
+	MemoryGiB float64
+	TaskCount int64
+
+	MonthlyExecutions *int64 `infracost_usage:"monthly_executions"`
+	TaskDurationSecs  *int64 `infracost_usage:"task_duration_secs"`
+}
+
+var CloudRunV2JobUsageSchema = []*schema.UsageItem{
+	{Key: "monthly_executions", ValueType: schema.Int64, DefaultValue: 0},
+	{Key: "task_duration_secs", ValueType: schema.Int64, DefaultValue: 0},
+}
+
+func (r *CloudRunV2Job) PopulateUsage(u *schema.UsageData) {
+	resources.PopulateArgsWithUsage(r, u)
+}
+
+func (r *CloudRunV2Job) BuildResource() *schema.Resource {
+	var seconds *decimal.Decimal
+	if r.MonthlyExecutions != nil && r.TaskDurationSecs != nil {
+		seconds = decimalPtr(decimal.NewFromInt(*r.MonthlyExecutions).
+			Mul(decimal.NewFromInt(r.TaskCount)).
+			Mul(decimal.NewFromInt(*r.TaskDurationSecs)))
+	}
+
+	return &schema.Resource{
+		Name: r.Address,
+		CostComponents: []*schema.CostComponent{
+			cloudRunCPUCostComponent("CPU", r.Region, r.CPU, seconds, true),
+			cloudRunMemoryCostComponent("Memory", r.Region, r.MemoryGiB, seconds, true),
+		},
+		UsageSchema: CloudRunV2JobUsageSchema,
+	}
+}
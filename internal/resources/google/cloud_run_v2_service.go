@@ -0,0 +1,168 @@
+package google
+
+import (
+	"github.com/infracost/infracost/internal/resources"
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+)
+
+// CloudRunV2Service struct represents a Cloud Run service.
+//
+// Services that only allocate CPU during request processing are billed per vCPU-second
+// and GiB-second of instance time while requests are being handled, plus a fee per request.
+// Services with CPU always allocated are billed for the whole lifetime of their instances
+// at a lower rate and aren't charged per request.
+//
+// Resource information: https://cloud.google.com/run/docs/overview/what-is-cloud-run
+// Pricing information: https://cloud.google.com/run/pricing
+type CloudRunV2Service struct {
+	Address            string
+	Region             string
+	CPU                float64
+	MemoryGiB          float64
+	CPUAlwaysAllocated bool
+	MinInstanceCount   int64
+
+	MonthlyRequests   *int64   `infracost_usage:"monthly_requests"`
+	RequestDurationMs *int64   `infracost_usage:"request_duration_ms"`
+	Instances         *float64 `infracost_usage:"instances"`
+}
+
+var CloudRunV2ServiceUsageSchema = []*schema.UsageItem{
+	{Key: "monthly_requests", ValueType: schema.Int64, DefaultValue: 0},
+	{Key: "request_duration_ms", ValueType: schema.Int64, DefaultValue: 0},
+	{Key: "instances", ValueType: schema.Float64, DefaultValue: 0},
+}
+
+func (r *CloudRunV2Service) PopulateUsage(u *schema.UsageData) {
+	resources.PopulateArgsWithUsage(r, u)
+}
+
+func (r *CloudRunV2Service) BuildResource() *schema.Resource {
+	var costComponents []*schema.CostComponent
+
+	if r.CPUAlwaysAllocated {
+		var seconds *decimal.Decimal
+
+		instances := r.Instances
+		if instances == nil && r.MinInstanceCount > 0 {
+			minInstances := float64(r.MinInstanceCount)
+			instances = &minInstances
+		}
+
+		if instances != nil {
+			seconds = decimalPtr(decimal.NewFromFloat(*instances).Mul(schema.HourToMonthUnitMultiplier).Mul(decimal.NewFromInt(3600)))
+		}
+
+		costComponents = append(costComponents,
+			cloudRunCPUCostComponent("CPU (always allocated)", r.Region, r.CPU, seconds, true),
+			cloudRunMemoryCostComponent("Memory (always allocated)", r.Region, r.MemoryGiB, seconds, true),
+		)
+	} else {
+		var requests, seconds *decimal.Decimal
+
+		if r.MonthlyRequests != nil {
+			requests = decimalPtr(decimal.NewFromInt(*r.MonthlyRequests))
+
+			if r.RequestDurationMs != nil {
+				seconds = decimalPtr(requests.Mul(decimal.NewFromInt(*r.RequestDurationMs)).Div(decimal.NewFromInt(1000)))
+			}
+		}
+
+		costComponents = append(costComponents,
+			cloudRunCPUCostComponent("CPU", r.Region, r.CPU, seconds, false),
+			cloudRunMemoryCostComponent("Memory", r.Region, r.MemoryGiB, seconds, false),
+			&schema.CostComponent{
+				Name:            "Requests",
+				Unit:            "1M requests",
+				UnitMultiplier:  decimal.NewFromInt(1000000),
+				MonthlyQuantity: requests,
+				ProductFilter: &schema.ProductFilter{
+					VendorName:    strPtr("gcp"),
+					Region:        strPtr("global"),
+					Service:       strPtr("Cloud Run"),
+					ProductFamily: strPtr("ApplicationServices"),
+					AttributeFilters: []*schema.AttributeFilter{
+						{Key: "description", ValueRegex: regexPtr("^Requests")},
+					},
+				},
+				PriceFilter: &schema.PriceFilter{
+					StartUsageAmount: strPtr("2000000"),
+				},
+			},
+		)
+	}
+
+	return &schema.Resource{
+		Name:           r.Address,
+		CostComponents: costComponents,
+		UsageSchema:    CloudRunV2ServiceUsageSchema,
+	}
+}
+
+// cloudRunCPUCostComponent returns the cost component for the vCPU time of Cloud Run
+// instances that run for the given number of seconds.
+func cloudRunCPUCostComponent(name, region string, cpu float64, seconds *decimal.Decimal, alwaysAllocated bool) *schema.CostComponent {
+	var quantity *decimal.Decimal
+	if seconds != nil {
+		quantity = decimalPtr(seconds.Mul(decimal.NewFromFloat(cpu)))
+	}
+
+	description := "^CPU Allocation Time$"
+	if alwaysAllocated {
+		description = "^CPU Allocation Time \\(always-on CPU\\)$"
+	}
+
+	return &schema.CostComponent{
+		Name:            name,
+		Unit:            "vCPU-seconds",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: quantity,
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("gcp"),
+			Region:        strPtr(region),
+			Service:       strPtr("Cloud Run"),
+			ProductFamily: strPtr("ApplicationServices"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "description", ValueRegex: regexPtr(description)},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			EndUsageAmount: strPtr(""),
+		},
+	}
+}
+
+// cloudRunMemoryCostComponent returns the cost component for the memory of Cloud Run
+// instances that run for the given number of seconds.
+func cloudRunMemoryCostComponent(name, region string, memoryGiB float64, seconds *decimal.Decimal, alwaysAllocated bool) *schema.CostComponent {
+	var quantity *decimal.Decimal
+	if seconds != nil {
+		quantity = decimalPtr(seconds.Mul(decimal.NewFromFloat(memoryGiB)))
+	}
+
+	description := "^Memory Allocation Time$"
+	if alwaysAllocated {
+		description = "^Memory Allocation Time \\(always-on CPU\\)$"
+	}
+
+	return &schema.CostComponent{
+		Name:            name,
+		Unit:            "GiB-seconds",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: quantity,
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("gcp"),
+			Region:        strPtr(region),
+			Service:       strPtr("Cloud Run"),
+			ProductFamily: strPtr("ApplicationServices"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "description", ValueRegex: regexPtr(description)},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			EndUsageAmount: strPtr(""),
+		},
+	}
+}
@@ -367,3 +367,45 @@ func storageImageCostComponent(region string, description string, storageSize *d
 		},
 	}
 }
+
+// computeUsagePurchaseOption returns purchaseOption overridden by the
+// purchase_option usage key. Spot VMs are priced the same as preemptible VMs.
+func computeUsagePurchaseOption(address, purchaseOption string, value *string) string {
+	if value == nil || *value == "" {
+		return purchaseOption
+	}
+
+	switch strings.ToLower(*value) {
+	case "on_demand":
+		return "on_demand"
+	case "spot", "preemptible":
+		return "preemptible"
+	}
+
+	logging.Logger.Warnf("Invalid purchase_option for %s, ignoring it. Expected: on_demand, spot. Got: %s", address, *value)
+	return purchaseOption
+}
+
+// setSpotPriceFallbacks prices each preemptible cost component with the
+// on-demand cost component at the same index of onDemand, discounted by rate,
+// if the pricing service has no preemptible price for it. Cost components that
+// don't depend on the purchase option, e.g. disks, always have a price so their
+// fallback isn't used.
+func setSpotPriceFallbacks(address string, rate *float64, costComponents, onDemand []*schema.CostComponent) {
+	if rate == nil || *rate == 0 || len(costComponents) != len(onDemand) {
+		return
+	}
+
+	if *rate < 0 || *rate >= 1 {
+		logging.Logger.Warnf("Invalid spot_discount_rate for %s, ignoring it. Expected a value between 0 and 1. Got: %v", address, *rate)
+		return
+	}
+
+	for i, c := range costComponents {
+		c.PriceFallback = &schema.PriceFallback{
+			ProductFilter: onDemand[i].ProductFilter,
+			PriceFilter:   onDemand[i].PriceFilter,
+			DiscountPerc:  *rate,
+		}
+	}
+}
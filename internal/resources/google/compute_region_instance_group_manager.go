@@ -18,10 +18,17 @@ type ComputeRegionInstanceGroupManager struct {
 	ScratchDisks      int
 	Disks             []*ComputeDisk
 	GuestAccelerators []*ComputeGuestAccelerator
+
+	// "usage" args
+	UsagePurchaseOption *string  `infracost_usage:"purchase_option"`
+	SpotDiscountRate    *float64 `infracost_usage:"spot_discount_rate"`
 }
 
 // ComputeRegionInstanceGroupManagerUsageSchema defines a list which represents the usage schema of ComputeRegionInstanceGroupManager.
-var ComputeRegionInstanceGroupManagerUsageSchema = []*schema.UsageItem{}
+var ComputeRegionInstanceGroupManagerUsageSchema = []*schema.UsageItem{
+	{Key: "purchase_option", DefaultValue: "", ValueType: schema.String},
+	{Key: "spot_discount_rate", DefaultValue: 0, ValueType: schema.Float64},
+}
 
 // PopulateUsage parses the u schema.UsageData into the ComputeRegionInstanceGroupManager.
 // It uses the `infracost_usage` struct tags to populate data into the ComputeRegionInstanceGroupManager.
@@ -33,29 +40,47 @@ func (r *ComputeRegionInstanceGroupManager) PopulateUsage(u *schema.UsageData) {
 // This method is called after the resource is initialised by an IaC provider.
 // See providers folder for more information.
 func (r *ComputeRegionInstanceGroupManager) BuildResource() *schema.Resource {
-	costComponents, err := computeCostComponents(r.Region, r.MachineType, r.PurchaseOption, r.TargetSize, nil)
+	purchaseOption := computeUsagePurchaseOption(r.Address, r.PurchaseOption, r.UsagePurchaseOption)
+
+	costComponents, err := r.costComponents(purchaseOption)
 	if err != nil {
 		logging.Logger.Warnf("Skipping resource %s. %s", r.Address, err)
 		return nil
 	}
 
+	if purchaseOption == "preemptible" && r.SpotDiscountRate != nil {
+		onDemand, err := r.costComponents("on_demand")
+		if err == nil {
+			setSpotPriceFallbacks(r.Address, r.SpotDiscountRate, costComponents, onDemand)
+		}
+	}
+
+	return &schema.Resource{
+		Name:           r.Address,
+		UsageSchema:    ComputeRegionInstanceGroupManagerUsageSchema,
+		CostComponents: costComponents,
+	}
+}
+
+func (r *ComputeRegionInstanceGroupManager) costComponents(purchaseOption string) ([]*schema.CostComponent, error) {
+	costComponents, err := computeCostComponents(r.Region, r.MachineType, purchaseOption, r.TargetSize, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, disk := range r.Disks {
 		costComponents = append(costComponents, computeDiskCostComponent(r.Region, disk.Type, disk.Size, r.TargetSize))
 	}
 
 	if r.ScratchDisks > 0 {
-		costComponents = append(costComponents, scratchDiskCostComponent(r.Region, r.PurchaseOption, r.ScratchDisks*int(r.TargetSize)))
+		costComponents = append(costComponents, scratchDiskCostComponent(r.Region, purchaseOption, r.ScratchDisks*int(r.TargetSize)))
 	}
 
 	for _, guestAccel := range r.GuestAccelerators {
-		if component := guestAcceleratorCostComponent(r.Region, r.PurchaseOption, guestAccel.Type, guestAccel.Count, r.TargetSize, nil); component != nil {
+		if component := guestAcceleratorCostComponent(r.Region, purchaseOption, guestAccel.Type, guestAccel.Count, r.TargetSize, nil); component != nil {
 			costComponents = append(costComponents, component)
 		}
 	}
 
-	return &schema.Resource{
-		Name:           r.Address,
-		UsageSchema:    ComputeRegionInstanceGroupManagerUsageSchema,
-		CostComponents: costComponents,
-	}
+	return costComponents, nil
 }
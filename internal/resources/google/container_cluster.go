@@ -73,6 +73,7 @@ func (r *ContainerCluster) BuildResource() *schema.Resource {
 	if r.DefaultNodePool != nil {
 		poolResource := r.DefaultNodePool.BuildResource()
 		if poolResource != nil {
+			poolResource.ResourceType = "google_container_node_pool"
 			subresources = append(subresources, poolResource)
 		}
 	}
@@ -80,6 +81,7 @@ func (r *ContainerCluster) BuildResource() *schema.Resource {
 	for _, nodePool := range r.NodePools {
 		poolResource := nodePool.BuildResource()
 		if poolResource != nil {
+			poolResource.ResourceType = "google_container_node_pool"
 			subresources = append(subresources, poolResource)
 		}
 	}
@@ -0,0 +1,66 @@
+package google
+
+import (
+	"github.com/infracost/infracost/internal/resources"
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+)
+
+// MemcacheInstance struct represents a Memorystore for Memcached instance.
+//
+// Each node of the instance is billed for its vCPUs and memory per hour.
+//
+// Resource information: https://cloud.google.com/memorystore/docs/memcached/memcached-overview
+// Pricing information: https://cloud.google.com/memorystore/docs/memcached/pricing
+type MemcacheInstance struct {
+	Address      string
+	Region       string
+	NodeCount    int64
+	CPUCount     int64
+	MemorySizeMB int64
+}
+
+var MemcacheInstanceUsageSchema = []*schema.UsageItem{}
+
+func (r *MemcacheInstance) PopulateUsage(u *schema.UsageData) {
+	resources.PopulateArgsWithUsage(r, u)
+}
+
+func (r *MemcacheInstance) BuildResource() *schema.Resource {
+	nodes := decimal.NewFromInt(r.NodeCount)
+	memoryGB := decimal.NewFromInt(r.MemorySizeMB).Div(decimal.NewFromInt(1024))
+
+	return &schema.Resource{
+		Name: r.Address,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:           "vCPU",
+				Unit:           "vCPU",
+				UnitMultiplier: schema.HourToMonthUnitMultiplier,
+				HourlyQuantity: decimalPtr(nodes.Mul(decimal.NewFromInt(r.CPUCount))),
+				ProductFilter:  r.productFilter("/Memcached Core/"),
+			},
+			{
+				Name:           "Memory",
+				Unit:           "GB",
+				UnitMultiplier: schema.HourToMonthUnitMultiplier,
+				HourlyQuantity: decimalPtr(nodes.Mul(memoryGB)),
+				ProductFilter:  r.productFilter("/Memcached RAM/"),
+			},
+		},
+		UsageSchema: MemcacheInstanceUsageSchema,
+	}
+}
+
+func (r *MemcacheInstance) productFilter(description string) *schema.ProductFilter {
+	return &schema.ProductFilter{
+		VendorName:    strPtr("gcp"),
+		Region:        strPtr(r.Region),
+		Service:       strPtr("Cloud Memorystore for Memcached"),
+		ProductFamily: strPtr("ApplicationServices"),
+		AttributeFilters: []*schema.AttributeFilter{
+			{Key: "description", ValueRegex: strPtr(description)},
+		},
+	}
+}
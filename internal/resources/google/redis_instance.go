@@ -10,11 +10,20 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// RedisInstance struct represents a Memorystore for Redis instance.
+//
+// Standard tier instances are made of a primary and a replica node. When read
+// replicas are enabled every replica after the first is an additional node that
+// is billed at the Basic tier price for the capacity of the instance.
+//
+// Resource information: https://cloud.google.com/memorystore/docs/redis/redis-tiers
+// Pricing information: https://cloud.google.com/memorystore/docs/redis/pricing
 type RedisInstance struct {
 	Address      string
 	Region       string
 	Tier         string
 	MemorySizeGB float64
+	ReplicaCount int64
 }
 
 var RedisInstanceUsageSchema = []*schema.UsageItem{}
@@ -50,27 +59,47 @@ func (r *RedisInstance) BuildResource() *schema.Resource {
 		capacityTier = "M5"
 	}
 
-	description := fmt.Sprintf("/Redis Capacity %s %s/", serviceTier, capacityTier)
-	name := fmt.Sprintf("Redis instance (%s, %s)", strings.ToLower(serviceTier), capacityTier)
+	costComponents := []*schema.CostComponent{
+		r.capacityCostComponent(
+			fmt.Sprintf("Redis instance (%s, %s)", strings.ToLower(serviceTier), capacityTier),
+			serviceTier,
+			capacityTier,
+			memorySize,
+		),
+	}
+
+	if serviceTier == "Standard" && r.ReplicaCount > 1 {
+		costComponents = append(costComponents, r.capacityCostComponent(
+			fmt.Sprintf("Read replica nodes (%s)", capacityTier),
+			"Basic",
+			capacityTier,
+			memorySize*float64(r.ReplicaCount-1),
+		))
+	}
 
 	return &schema.Resource{
-		Name: r.Address,
-		CostComponents: []*schema.CostComponent{
-			{
-				Name:           name,
-				Unit:           "GB",
-				UnitMultiplier: schema.HourToMonthUnitMultiplier,
-				HourlyQuantity: decimalPtr(decimal.NewFromFloat(memorySize)),
-				ProductFilter: &schema.ProductFilter{
-					VendorName:    strPtr("gcp"),
-					Region:        strPtr(r.Region),
-					Service:       strPtr("Cloud Memorystore for Redis"),
-					ProductFamily: strPtr("ApplicationServices"),
-					AttributeFilters: []*schema.AttributeFilter{
-						{Key: "description", ValueRegex: strPtr(description)},
-					},
-				},
+		Name:           r.Address,
+		CostComponents: costComponents,
+		UsageSchema:    RedisInstanceUsageSchema,
+	}
+}
+
+func (r *RedisInstance) capacityCostComponent(name, serviceTier, capacityTier string, memorySizeGB float64) *schema.CostComponent {
+	description := fmt.Sprintf("/Redis Capacity %s %s/", serviceTier, capacityTier)
+
+	return &schema.CostComponent{
+		Name:           name,
+		Unit:           "GB",
+		UnitMultiplier: schema.HourToMonthUnitMultiplier,
+		HourlyQuantity: decimalPtr(decimal.NewFromFloat(memorySizeGB)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("gcp"),
+			Region:        strPtr(r.Region),
+			Service:       strPtr("Cloud Memorystore for Redis"),
+			ProductFamily: strPtr("ApplicationServices"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "description", ValueRegex: strPtr(description)},
 			},
-		}, UsageSchema: RedisInstanceUsageSchema,
+		},
 	}
 }
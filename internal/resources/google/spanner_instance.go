@@ -0,0 +1,86 @@
+package google
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/resources"
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+)
+
+// SpannerInstance struct represents a Cloud Spanner instance.
+//
+// Compute capacity is billed per node-hour, where 1 node is equal to 1000
+// processing units. Multi-region configurations are priced using the name of
+// the configuration as the region, e.g. nam3.
+//
+// Resource information: https://cloud.google.com/spanner/docs/instances
+// Pricing information: https://cloud.google.com/spanner/pricing
+type SpannerInstance struct {
+	Address         string
+	Region          string
+	MultiRegion     bool
+	ProcessingUnits int64
+
+	StorageGB       *float64 `infracost_usage:"storage_gb"`
+	BackupStorageGB *float64 `infracost_usage:"backup_storage_gb"`
+}
+
+var SpannerInstanceUsageSchema = []*schema.UsageItem{
+	{Key: "storage_gb", ValueType: schema.Float64, DefaultValue: 0},
+	{Key: "backup_storage_gb", ValueType: schema.Float64, DefaultValue: 0},
+}
+
+func (r *SpannerInstance) PopulateUsage(u *schema.UsageData) {
+	resources.PopulateArgsWithUsage(r, u)
+}
+
+func (r *SpannerInstance) BuildResource() *schema.Resource {
+	configType := "regional"
+	if r.MultiRegion {
+		configType = "multi-region"
+	}
+
+	nodes := decimal.NewFromInt(r.ProcessingUnits).Div(decimal.NewFromInt(1000))
+
+	return &schema.Resource{
+		Name: r.Address,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:           fmt.Sprintf("Compute (%s, %d PUs)", configType, r.ProcessingUnits),
+				Unit:           "nodes",
+				UnitMultiplier: schema.HourToMonthUnitMultiplier,
+				HourlyQuantity: decimalPtr(nodes),
+				ProductFilter:  r.productFilter("Spanner Instance"),
+			},
+			{
+				Name:            "Storage",
+				Unit:            "GB",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: floatPtrToDecimalPtr(r.StorageGB),
+				ProductFilter:   r.productFilter("Spanner Storage"),
+			},
+			{
+				Name:            "Backup storage",
+				Unit:            "GB",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: floatPtrToDecimalPtr(r.BackupStorageGB),
+				ProductFilter:   r.productFilter("Spanner Backup Storage"),
+			},
+		},
+		UsageSchema: SpannerInstanceUsageSchema,
+	}
+}
+
+func (r *SpannerInstance) productFilter(description string) *schema.ProductFilter {
+	return &schema.ProductFilter{
+		VendorName:    strPtr("gcp"),
+		Region:        strPtr(r.Region),
+		Service:       strPtr("Cloud Spanner"),
+		ProductFamily: strPtr("ApplicationServices"),
+		AttributeFilters: []*schema.AttributeFilter{
+			{Key: "description", ValueRegex: regexPtr(fmt.Sprintf("^%s", description))},
+		},
+	}
+}
@@ -56,7 +56,7 @@ func TestTerraformPlanScanner_ScanPlan(t *testing.T) {
 	newCost := decimal.NewFromInt(5)
 
 	var called int
-	ps := scan.NewTerraformPlanScanner(runCtx, newDiscardLogger(), func(ctx *config.RunContext, c *apiclient.PricingAPIClient, r *schema.Resource) error {
+	ps := scan.NewTerraformPlanScanner(runCtx, newDiscardLogger(), func(ctx *config.RunContext, s apiclient.PriceSource, r *schema.Resource) error {
 		t.Helper()
 
 		if called == 0 {
@@ -1,5 +1,11 @@
 package schema
 
+import (
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+)
+
 type CoreResourceFunc func(*ResourceData) CoreResource
 
 // CoreResource is the new/preferred way to represent provider-agnostic resources that
@@ -58,6 +64,10 @@ type PartialResource struct {
 	// that have not yet been converted to build CoreResource's
 	Resource *Resource
 
+	// ResourceFunc is the resource builder that built Resource, so that the resource can be
+	// built again with other usage, e.g. the usage of the current state in diffs.
+	ResourceFunc ResourceFunc
+
 	// CloudResourceIDs are collected during parsing in case they need to be uploaded to the
 	// Cloud Usage API to be used in the usage estimate calculations.
 	CloudResourceIDs []string
@@ -67,14 +77,40 @@ type PartialResource struct {
 // a previously built Resource
 func BuildResource(partial *PartialResource, fetchedUsage *UsageData) *Resource {
 	var res *Resource
+	u := partial.ResourceData.UsageData
+	u = u.Merge(fetchedUsage)
+
+	// The resource is built again without its usage to find the cost
+	// components whose quantities depend on usage. Without usage, or if it
+	// can't be built again, the resource itself is used.
+	var noUsage *Resource
+	hasUsage := u != nil && len(u.Attributes) > 0
+
 	if partial.CoreResource != nil {
-		u := partial.ResourceData.UsageData
-		u = u.Merge(fetchedUsage)
+		// Usage ranges are estimated by building copies of the resource with
+		// the min and max values of the ranges. They're built first as building
+		// a copy can change the sub resources that it shares with the resource.
+		var low, high *Resource
+		if lowUsage, highUsage := u.RangeBounds(); lowUsage != nil {
+			low = buildCoreResourceWithUsage(partial.CoreResource, lowUsage)
+			high = buildCoreResourceWithUsage(partial.CoreResource, highUsage)
+		}
+		if hasUsage {
+			noUsage = buildCoreResourceWithUsage(partial.CoreResource, &UsageData{Address: partial.ResourceData.Address})
+		}
 
 		partial.CoreResource.PopulateUsage(u)
 		res = partial.CoreResource.BuildResource()
+		if res != nil {
+			res.SetQuantityRanges(low, high)
+		}
 	} else {
 		res = partial.Resource
+		if res != nil && partial.ResourceFunc != nil && hasUsage {
+			d := *partial.ResourceData
+			d.UsageData = &UsageData{Address: d.Address}
+			noUsage = partial.ResourceFunc(&d, d.UsageData)
+		}
 	}
 
 	if res == nil {
@@ -86,15 +122,92 @@ func BuildResource(partial *PartialResource, fetchedUsage *UsageData) *Resource
 	}
 
 	res.ResourceType = partial.ResourceData.Type
+	setSubResourceTypes(res)
 	res.Tags = partial.ResourceData.Tags
 	res.Metadata = partial.ResourceData.Metadata
+	res.UnsetUsageKeys = unsetUsageKeys(res.UsageSchema, u)
+	res.UnknownInputs = partial.ResourceData.UnknownInputs()
+
+	if noUsage == nil {
+		noUsage = res
+	}
+	res.SetUsageBased(noUsage)
+
 	return res
 }
 
-func BuildResources(projects []*Project, projectPtrToUsageMap map[*Project]UsageMap) {
+// unsetUsageKeys returns the keys of the usage schema that have no value in u.
+func unsetUsageKeys(items []*UsageItem, u *UsageData) []string {
+	var keys []string
+
+	for _, item := range items {
+		if u == nil || u.IsEmpty(item.Key) {
+			keys = append(keys, item.Key)
+		}
+	}
+
+	return keys
+}
+
+// BuildResourceWithUsage creates a new Resource from the CoreResource using the given
+// usage instead of the usage the resource was parsed with. The CoreResource is copied
+// so that building it doesn't affect other resources built from the same partial.
+// Resources that have not been converted to CoreResources are built again with their
+// ResourceFunc. Resources that have neither can't be built with different usage, so they
+// are returned as previously built and a warning is logged if u has usage for them.
+func BuildResourceWithUsage(partial *PartialResource, u *UsageData, fetchedUsage *UsageData) *Resource {
+	var c CoreResource
+	if partial.CoreResource != nil {
+		c = copyCoreResource(partial.CoreResource)
+	}
+
+	if c == nil && partial.ResourceFunc == nil {
+		if u != nil && len(u.Attributes) > 0 {
+			log.Warnf("Ignoring past usage for %s. %s doesn't support a separate usage file for the current state, so its usage file values are used for both states.", partial.ResourceData.Address, partial.ResourceData.Type)
+		}
+
+		return BuildResource(partial, fetchedUsage)
+	}
+
+	d := *partial.ResourceData
+	d.UsageData = u
+
+	copied := *partial
+	copied.CoreResource = c
+	copied.ResourceData = &d
+
+	if c == nil {
+		merged := u.Merge(fetchedUsage)
+
+		copied.Resource = partial.ResourceFunc(&d, merged)
+		if copied.Resource != nil && merged != nil {
+			copied.Resource.EstimationSummary = merged.CalcEstimationSummary()
+		}
+	}
+
+	return BuildResource(&copied, fetchedUsage)
+}
+
+// copyCoreResource returns a shallow copy of the CoreResource, or nil if it
+// isn't a pointer to a struct.
+func copyCoreResource(coreResource CoreResource) CoreResource {
+	v := reflect.ValueOf(coreResource)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	c := reflect.New(v.Elem().Type())
+	c.Elem().Set(v.Elem())
+
+	return c.Interface().(CoreResource)
+}
+
+// BuildResources builds the resources of the projects, with up to parallelism
+// resources of each project being built at the same time.
+func BuildResources(projects []*Project, projectPtrToUsageMap map[*Project]UsageMap, parallelism int) {
 	for _, project := range projects {
 		usageMap := projectPtrToUsageMap[project]
 
-		project.BuildResources(usageMap)
+		project.BuildResourcesInParallel(usageMap, parallelism)
 	}
 }
@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestBuildResourceSubResourceTypes(t *testing.T) {
+	partial := &PartialResource{
+		ResourceData: NewResourceData("aws_elastic_beanstalk_environment", "aws", "aws_elastic_beanstalk_environment.env", nil, gjson.Result{}),
+		Resource: &Resource{
+			Name: "aws_elastic_beanstalk_environment.env",
+			SubResources: []*Resource{
+				{
+					Name:         "aws_launch_configuration",
+					ResourceType: "aws_launch_configuration",
+					SubResources: []*Resource{
+						{Name: "root_block_device", ResourceType: "aws_ebs_volume"},
+						{Name: "untyped"},
+					},
+				},
+				{Name: "untyped"},
+			},
+		},
+	}
+
+	r := BuildResource(partial, nil)
+
+	assert.Equal(t, "aws_elastic_beanstalk_environment", r.ResourceType)
+	assert.Equal(t, "aws_launch_configuration", r.SubResources[0].ResourceType)
+	assert.Equal(t, "aws_ebs_volume", r.SubResources[0].SubResources[0].ResourceType)
+	assert.Equal(t, "aws_launch_configuration", r.SubResources[0].SubResources[1].ResourceType)
+	assert.Equal(t, "aws_elastic_beanstalk_environment", r.SubResources[1].ResourceType)
+}
+
+type testInstanceResource struct {
+	testUsageResource
+}
+
+func (r *testInstanceResource) BuildResource() *Resource {
+	res := r.testUsageResource.BuildResource()
+	res.CostComponents = append(res.CostComponents, &CostComponent{
+		Name:            "Instance usage",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)),
+	})
+
+	return res
+}
+
+func TestBuildResourceUsageBased(t *testing.T) {
+	for _, usage := range []map[string]interface{}{nil, {"monthly_requests": 200}} {
+		partial := &PartialResource{
+			ResourceData: &ResourceData{
+				Address:   "aws_instance.test",
+				UsageData: NewUsageData("aws_instance.test", ParseAttributes(usage)),
+			},
+			CoreResource: &testInstanceResource{testUsageResource{Address: "aws_instance.test"}},
+		}
+
+		r := BuildResource(partial, nil)
+
+		assert.Equal(t, "Requests", r.CostComponents[0].Name)
+		assert.True(t, r.CostComponents[0].UsageBased)
+		assert.Equal(t, "Instance usage", r.CostComponents[1].Name)
+		assert.False(t, r.CostComponents[1].UsageBased)
+	}
+}
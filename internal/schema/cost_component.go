@@ -19,10 +19,60 @@ type CostComponent struct {
 	priceHash            string
 	HourlyCost           *decimal.Decimal
 	MonthlyCost          *decimal.Decimal
+
+	// LowMonthlyQuantity and HighMonthlyQuantity are the bounds of the monthly
+	// quantity when the usage it's based on is a range, see
+	// Resource.SetQuantityRanges. They're nil if the quantity is a point
+	// estimate.
+	LowMonthlyQuantity  *decimal.Decimal
+	HighMonthlyQuantity *decimal.Decimal
+	LowMonthlyCost      *decimal.Decimal
+	HighMonthlyCost     *decimal.Decimal
+
+	// ProjectedMonthlyQuantities are the average monthly quantities over the
+	// ProjectionMonths when the usage it's based on grows, see
+	// Resource.SetProjectedQuantities. Months without a projected quantity
+	// have the same quantity as MonthlyQuantity.
+	ProjectedMonthlyQuantities map[int]*decimal.Decimal
+
+	// PriceFallback is used to price the cost component if no price is found
+	// with its filters.
+	PriceFallback *PriceFallback
+
+	// SharedCostKey identifies a cost that can be referenced by multiple resources,
+	// e.g. a load balancer that is referenced by several listeners. Only one resource
+	// is attributed the cost, the others reference it, see DeduplicateSharedCosts.
+	SharedCostKey string
+	sharedWith    string
+
+	// UsageBased is true if the quantity of the cost component depends on
+	// usage, see Resource.SetUsageBased.
+	UsageBased bool
+
+	// priceWarning is why the price of the cost component couldn't be resolved
+	// to a single price, e.g. "No prices found".
+	priceWarning string
+}
+
+// PriceFallback prices a cost component with other filters when the pricing
+// service has no price for its own filters, e.g. an on-demand price that is
+// discounted by the historical spot discount when live spot prices are missing.
+type PriceFallback struct {
+	ProductFilter *ProductFilter
+	PriceFilter   *PriceFilter
+	// DiscountPerc is the fraction that the fallback price is discounted by.
+	DiscountPerc float64
 }
 
 func (c *CostComponent) CalculateCosts() {
 	c.fillQuantities()
+	if c.sharedWith != "" {
+		c.HourlyCost = decimalPtr(decimal.Zero)
+		c.MonthlyCost = decimalPtr(decimal.Zero)
+		c.LowMonthlyCost, c.HighMonthlyCost = nil, nil
+		return
+	}
+
 	if c.HourlyQuantity != nil {
 		c.HourlyCost = decimalPtr(c.price.Mul(*c.HourlyQuantity))
 	}
@@ -30,6 +80,17 @@ func (c *CostComponent) CalculateCosts() {
 		discountMul := decimal.NewFromFloat(1.0 - c.MonthlyDiscountPerc)
 		c.MonthlyCost = decimalPtr(c.price.Mul(*c.MonthlyQuantity).Mul(discountMul))
 	}
+	if c.LowMonthlyQuantity != nil && c.HighMonthlyQuantity != nil {
+		discountMul := decimal.NewFromFloat(1.0 - c.MonthlyDiscountPerc)
+		c.LowMonthlyCost = decimalPtr(c.price.Mul(*c.LowMonthlyQuantity).Mul(discountMul))
+		c.HighMonthlyCost = decimalPtr(c.price.Mul(*c.HighMonthlyQuantity).Mul(discountMul))
+	}
+}
+
+// HasCostRange returns whether the monthly cost of the cost component is a
+// range as the usage it's based on is a range.
+func (c *CostComponent) HasCostRange() bool {
+	return c.LowMonthlyCost != nil && c.HighMonthlyCost != nil
 }
 
 func (c *CostComponent) fillQuantities() {
@@ -40,6 +101,18 @@ func (c *CostComponent) fillQuantities() {
 	}
 }
 
+// SetSharedWith marks the cost component as a reference to a shared cost that
+// is attributed to the resource with the given name.
+func (c *CostComponent) SetSharedWith(name string) {
+	c.sharedWith = name
+}
+
+// SharedWith returns the name of the resource that the shared cost is attributed
+// to, or an empty string if the cost component is not a reference.
+func (c *CostComponent) SharedWith() string {
+	return c.sharedWith
+}
+
 func (c *CostComponent) SetPrice(price decimal.Decimal) {
 	c.price = price
 }
@@ -64,6 +137,18 @@ func (c *CostComponent) CustomPrice() *decimal.Decimal {
 	return c.customPrice
 }
 
+// SetPriceWarning sets why the price of the cost component couldn't be
+// resolved to a single price. An empty warning clears it.
+func (c *CostComponent) SetPriceWarning(warning string) {
+	c.priceWarning = warning
+}
+
+// PriceWarning returns why the price of the cost component couldn't be resolved
+// to a single price, or an empty string if it was.
+func (c *CostComponent) PriceWarning() string {
+	return c.priceWarning
+}
+
 func (c *CostComponent) UnitMultiplierPrice() decimal.Decimal {
 	// Round the final number to 16 decimal places to avoid floating point issues.
 	return c.Price().Mul(c.UnitMultiplier)
@@ -87,7 +172,13 @@ func (c *CostComponent) UnitMultiplierHourlyQuantity() *decimal.Decimal {
 }
 
 func (c *CostComponent) UnitMultiplierMonthlyQuantity() *decimal.Decimal {
-	if c.MonthlyQuantity == nil {
+	return c.UnitMultiplierQuantity(c.MonthlyQuantity)
+}
+
+// UnitMultiplierQuantity returns the quantity in the unit of the cost
+// component, e.g. for the bounds of the monthly quantity.
+func (c *CostComponent) UnitMultiplierQuantity(quantity *decimal.Decimal) *decimal.Decimal {
+	if quantity == nil {
 		return nil
 	}
 
@@ -97,7 +188,7 @@ func (c *CostComponent) UnitMultiplierMonthlyQuantity() *decimal.Decimal {
 		m = decimal.Zero
 	} else {
 		// Round the final number to 16 decimal places to avoid floating point issues.
-		m = c.MonthlyQuantity.Div(c.UnitMultiplier)
+		m = quantity.Div(c.UnitMultiplier)
 	}
 
 	return &m
@@ -0,0 +1,245 @@
+package schema
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+// ProjectionMonths are the numbers of months that the cumulative costs of
+// projects with a growth are projected over.
+var ProjectionMonths = []int{12, 36}
+
+// Growth is the expected monthly growth of a project, used to project its
+// cumulative cost over ProjectionMonths.
+type Growth struct {
+	// MonthlyRate is the percentage the cost of the project grows by each
+	// month, on top of the growth of its usage.
+	MonthlyRate float64
+	// UsageMonthlyRates are the percentages that the values of usage keys
+	// grow by each month.
+	UsageMonthlyRates map[string]float64
+}
+
+// cumulativeGrowthFactor returns the sum of the monthly growth factors over
+// the months, starting at 1 for the first month, so that the cumulative cost
+// over the months is the monthly cost times the factor.
+func cumulativeGrowthFactor(rate float64, months int) float64 {
+	if rate == 0 {
+		return float64(months)
+	}
+
+	r := 1 + rate/100
+	return (math.Pow(r, float64(months)) - 1) / (r - 1)
+}
+
+// WithGrowth returns a copy of the usage data with the values of the usage
+// keys that have a rate replaced by their average value over the months given
+// that they grow by the rate each month. It returns nil if none of the usage
+// keys have a rate.
+func (u *UsageData) WithGrowth(rates map[string]float64, months int) *UsageData {
+	if u == nil || len(rates) == 0 {
+		return nil
+	}
+
+	grown := u.Copy()
+	hasGrowth := false
+
+	for k, v := range u.Attributes {
+		var g gjson.Result
+		var ok bool
+		if rate, hasRate := rates[k]; hasRate {
+			g, ok = scaleUsage(v, cumulativeGrowthFactor(rate, months)/float64(months)), true
+		} else {
+			g, ok = growUsage(v, rates, months)
+		}
+
+		if ok {
+			grown.Attributes[k] = g
+			hasGrowth = true
+		}
+	}
+
+	if !hasGrowth {
+		return nil
+	}
+
+	return grown
+}
+
+// growUsage returns the usage of a sub resource with the values of its usage
+// keys that have a rate grown as in UsageData.WithGrowth, and whether any of
+// them have a rate.
+func growUsage(v gjson.Result, rates map[string]float64, months int) (gjson.Result, bool) {
+	if !v.IsObject() || isUsageRange(v) {
+		return v, false
+	}
+
+	hasGrowth := false
+	m := make(map[string]json.RawMessage)
+	v.ForEach(func(key, val gjson.Result) bool {
+		if rate, ok := rates[key.String()]; ok {
+			val = scaleUsage(val, cumulativeGrowthFactor(rate, months)/float64(months))
+			hasGrowth = true
+		} else if g, ok := growUsage(val, rates, months); ok {
+			val = g
+			hasGrowth = true
+		}
+
+		m[key.String()] = json.RawMessage(val.Raw)
+		return true
+	})
+
+	if !hasGrowth {
+		return v, false
+	}
+
+	j, _ := json.Marshal(m)
+	return gjson.ParseBytes(j), true
+}
+
+// scaleUsage returns the usage value with its numbers, including the bounds of
+// usage ranges, multiplied by the factor.
+func scaleUsage(v gjson.Result, factor float64) gjson.Result {
+	if v.Type == gjson.Number {
+		return gjson.Parse(strconv.FormatFloat(v.Float()*factor, 'f', -1, 64))
+	}
+
+	if !v.IsObject() {
+		return v
+	}
+
+	m := make(map[string]json.RawMessage)
+	v.ForEach(func(key, val gjson.Result) bool {
+		m[key.String()] = json.RawMessage(scaleUsage(val, factor).Raw)
+		return true
+	})
+
+	j, _ := json.Marshal(m)
+	return gjson.ParseBytes(j)
+}
+
+// buildProjectedResources builds copies of the resource with its usage grown
+// over each of the ProjectionMonths. It returns nil if the resource has no
+// usage that grows or it isn't a CoreResource.
+func (g *Growth) buildProjectedResources(partial *PartialResource, fetchedUsage *UsageData) map[int]*Resource {
+	if partial.CoreResource == nil || len(g.UsageMonthlyRates) == 0 {
+		return nil
+	}
+
+	u := partial.ResourceData.UsageData.Merge(fetchedUsage)
+
+	projected := make(map[int]*Resource, len(ProjectionMonths))
+	for _, months := range ProjectionMonths {
+		grown := u.WithGrowth(g.UsageMonthlyRates, months)
+		if grown == nil {
+			return nil
+		}
+
+		projected[months] = buildCoreResourceWithUsage(partial.CoreResource, grown)
+	}
+
+	return projected
+}
+
+// SetProjectedQuantities sets the projected monthly quantities of the cost
+// components of the resource and its sub resources from the same resource
+// built with its usage grown over the months. As with SetQuantityRanges, cost
+// components are matched by name.
+func (r *Resource) SetProjectedQuantities(months int, projected *Resource) {
+	if projected == nil {
+		return
+	}
+
+	projectedComponents := costComponentsByName(projected.CostComponents)
+
+	for _, c := range r.CostComponents {
+		p := projectedComponents[c.Name]
+		if p == nil {
+			continue
+		}
+
+		c.fillQuantities()
+		p.fillQuantities()
+
+		if c.MonthlyQuantity == nil || p.MonthlyQuantity == nil || c.MonthlyQuantity.Equal(*p.MonthlyQuantity) {
+			continue
+		}
+
+		if c.ProjectedMonthlyQuantities == nil {
+			c.ProjectedMonthlyQuantities = make(map[int]*decimal.Decimal, len(ProjectionMonths))
+		}
+		c.ProjectedMonthlyQuantities[months] = decimalPtr(*p.MonthlyQuantity)
+	}
+
+	projectedSubResources := resourcesByName(projected.SubResources)
+
+	for _, s := range r.SubResources {
+		s.SetProjectedQuantities(months, projectedSubResources[s.Name])
+	}
+}
+
+// CalculateProjections sets the projected costs of the resource and its sub
+// resources over each of the ProjectionMonths, given that their cost grows by
+// the monthly rate on top of the growth of their usage. It must be called
+// after the costs are calculated.
+func (r *Resource) CalculateProjections(monthlyRate float64) {
+	r.ProjectedCosts = nil
+	if r.IsSkipped || r.MonthlyCost == nil {
+		return
+	}
+
+	r.ProjectedCosts = make(map[int]*decimal.Decimal, len(ProjectionMonths))
+	for _, months := range ProjectionMonths {
+		cost := r.projectedMonthlyCost(months).Mul(decimal.NewFromFloat(cumulativeGrowthFactor(monthlyRate, months)))
+		r.ProjectedCosts[months] = &cost
+	}
+
+	for _, s := range r.SubResources {
+		s.CalculateProjections(monthlyRate)
+	}
+}
+
+// projectedMonthlyCost returns the average monthly cost of the resource over
+// the months given the growth of its usage.
+func (r *Resource) projectedMonthlyCost(months int) decimal.Decimal {
+	total := decimal.Zero
+
+	for _, c := range r.CostComponents {
+		if c.MonthlyCost == nil {
+			continue
+		}
+
+		q, ok := c.ProjectedMonthlyQuantities[months]
+		if !ok || c.sharedWith != "" {
+			total = total.Add(*c.MonthlyCost)
+			continue
+		}
+
+		discountMul := decimal.NewFromFloat(1.0 - c.MonthlyDiscountPerc)
+		total = total.Add(c.price.Mul(*q).Mul(discountMul))
+	}
+
+	for _, s := range r.SubResources {
+		if s.MonthlyCost != nil {
+			total = total.Add(s.projectedMonthlyCost(months))
+		}
+	}
+
+	return total
+}
+
+// CalculateProjections sets the projected costs of the resources of the
+// project if it has a growth.
+func (p *Project) CalculateProjections() {
+	if p.Growth == nil {
+		return
+	}
+
+	for _, r := range p.Resources {
+		r.CalculateProjections(p.Growth.MonthlyRate)
+	}
+}
@@ -0,0 +1,60 @@
+package schema
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// forEachInParallel calls fn with each index from 0 to n, with up to
+// parallelism calls running at the same time. If any of the calls panic the
+// panic is raised again in the calling goroutine once the others are done, so
+// it's recovered from the same way as if fn was called serially.
+func forEachInParallel(n int, parallelism int, fn func(i int)) {
+	if parallelism <= 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	if parallelism > n {
+		parallelism = n
+	}
+
+	indexes := make(chan int, n)
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		panicked interface{}
+	)
+
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if e := recover(); e != nil {
+					once.Do(func() {
+						panicked = fmt.Sprintf("%v\n%s", e, debug.Stack())
+					})
+				}
+			}()
+
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if panicked != nil {
+		panic(panicked)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"crypto/md5" // nolint:gosec
 	"encoding/base32"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -17,6 +18,13 @@ const (
 	DiagJSONParsingFailure = iota + 1
 	DiagModuleEvaluationFailure
 	DiagTerragruntEvaluationFailure
+	// DiagModuleCostNote is a note about the costs of a module from the
+	// infracost-metadata.yml file of the module.
+	DiagModuleCostNote
+	// DiagTerragruntDependencyEvaluationFailure is a warning that a
+	// dependency of a Terragrunt module couldn't be evaluated, so the
+	// module was evaluated with mocked dependency outputs.
+	DiagTerragruntDependencyEvaluationFailure
 )
 
 // ProjectDiag holds information about all diagnostics associated with a project.
@@ -35,6 +43,7 @@ type ProjectMetadata struct {
 	TerraformWorkspace  string        `json:"terraformWorkspace,omitempty"`
 	VCSSubPath          string        `json:"vcsSubPath,omitempty"`
 	VCSCodeChanged      *bool         `json:"vcsCodeChanged,omitempty"`
+	ConsumesProjects    []string      `json:"consumesProjects,omitempty"`
 	Errors              []ProjectDiag `json:"errors,omitempty"`
 	Warnings            []ProjectDiag `json:"warnings,omitempty"`
 	Policies            Policies      `json:"policies,omitempty"`
@@ -100,6 +109,20 @@ type Project struct {
 	Resources            []*Resource
 	Diff                 []*Resource
 	HasDiff              bool
+	// PastUsage is the usage used to build the past resources. When this is nil the
+	// past resources are built with the same usage as the planned resources.
+	PastUsage *UsageMap
+	// Growth is the expected monthly growth of the project. When it's set the
+	// resources have their cumulative costs projected, see CalculateProjections.
+	Growth *Growth
+	// ResourceCache has the resources of the previous run of the project. When
+	// it's set the resources that haven't changed since are used from it
+	// instead of being built and priced again.
+	ResourceCache *ResourceCache
+	// Outputs are the evaluated outputs of the project as JSON values. They're
+	// set for Terraform directory projects so that projects which depend on
+	// them can use them as input vars.
+	Outputs map[string]json.RawMessage
 }
 
 func NewProject(name string, metadata *ProjectMetadata) *Project {
@@ -164,32 +187,103 @@ func (p *Project) AllPartialResources() []*PartialResource {
 // BuildResources builds the resources from the partial resources
 // and sets the PastResources and Resources fields.
 func (p *Project) BuildResources(usageMap UsageMap) {
-	pastResources := make([]*Resource, 0, len(p.PartialPastResources))
-	resources := make([]*Resource, 0, len(p.PartialResources))
-
-	seen := make(map[*PartialResource]*Resource)
+	p.BuildResourcesInParallel(usageMap, 1)
+}
 
-	for _, p := range p.PartialPastResources {
-		u := usageMap.Get(p.ResourceData.Address)
-		r := BuildResource(p, u)
-		seen[p] = r
-		pastResources = append(pastResources, r)
+// BuildResourcesInParallel builds the resources like BuildResources, with up to
+// parallelism partial resources being built at the same time. The past and
+// planned resources of a partial resource are built by the same goroutine, as
+// building a copy can change the sub resources that it shares with the
+// resource.
+func (p *Project) BuildResourcesInParallel(usageMap UsageMap, parallelism int) {
+	type buildJob struct {
+		partial      *PartialResource
+		past         bool
+		planned      bool
+		pastResource *Resource
+		resource     *Resource
 	}
 
-	for _, p := range p.PartialResources {
-		r, ok := seen[p]
+	var jobs []*buildJob
+	jobsByPartial := make(map[*PartialResource]*buildJob)
+	addJob := func(partial *PartialResource) *buildJob {
+		j, ok := jobsByPartial[partial]
 		if !ok {
-			u := usageMap.Get(p.ResourceData.Address)
-			r = BuildResource(p, u)
-			seen[p] = r
+			j = &buildJob{partial: partial}
+			jobsByPartial[partial] = j
+			jobs = append(jobs, j)
 		}
-		resources = append(resources, r)
+
+		return j
+	}
+
+	for _, partial := range p.PartialPastResources {
+		addJob(partial).past = true
+	}
+	for _, partial := range p.PartialResources {
+		addJob(partial).planned = true
+	}
+
+	forEachInParallel(len(jobs), parallelism, func(i int) {
+		j := jobs[i]
+		u := usageMap.Get(j.partial.ResourceData.Address)
+
+		if j.past && p.PastUsage != nil {
+			// Build a separate resource for the past state so that the planned
+			// resource is still built with the planned usage below.
+			pastUsage := p.PastUsage.Get(j.partial.ResourceData.Address)
+			j.pastResource = p.ResourceCache.build(resourceCacheKindPast, j.partial, pastUsage.Merge(u), nil, func() *Resource {
+				return BuildResourceWithUsage(j.partial, pastUsage, u)
+			})
+			if !j.planned {
+				return
+			}
+		}
+
+		r := p.ResourceCache.build(resourceCacheKindPlanned, j.partial, j.partial.ResourceData.UsageData.Merge(u), p.Growth, func() *Resource {
+			return p.buildResource(j.partial, u)
+		})
+		if j.past && j.pastResource == nil {
+			j.pastResource = r
+		}
+		if j.planned {
+			j.resource = r
+		}
+	})
+
+	pastResources := make([]*Resource, 0, len(p.PartialPastResources))
+	for _, partial := range p.PartialPastResources {
+		pastResources = append(pastResources, jobsByPartial[partial].pastResource)
+	}
+
+	resources := make([]*Resource, 0, len(p.PartialResources))
+	for _, partial := range p.PartialResources {
+		resources = append(resources, jobsByPartial[partial].resource)
 	}
 
 	p.PastResources = pastResources
 	p.Resources = resources
 }
 
+// buildResource builds the resource from the partial resource. If the project
+// has a growth the cost components also have their quantities projected from
+// the growth of their usage. The projected resources are built first as
+// building a copy can change the sub resources that it shares with the
+// resource.
+func (p *Project) buildResource(partial *PartialResource, fetchedUsage *UsageData) *Resource {
+	var projected map[int]*Resource
+	if p.Growth != nil {
+		projected = p.Growth.buildProjectedResources(partial, fetchedUsage)
+	}
+
+	r := BuildResource(partial, fetchedUsage)
+	for _, months := range ProjectionMonths {
+		r.SetProjectedQuantities(months, projected[months])
+	}
+
+	return r
+}
+
 // CalculateDiff calculates the diff of past and current resources
 func (p *Project) CalculateDiff() {
 	if p.HasDiff {
@@ -1,11 +1,17 @@
 package schema
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
-	"github.com/infracost/infracost/internal/vcs"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/vcs"
 )
 
 func TestGenerateProjectName(t *testing.T) {
@@ -69,3 +75,274 @@ func TestGenerateProjectName(t *testing.T) {
 		assert.True(t, strings.HasPrefix(result, "project_"))
 	})
 }
+
+type testUsageResource struct {
+	Address  string
+	Requests *int64
+}
+
+func (r *testUsageResource) CoreType() string           { return "TestUsageResource" }
+func (r *testUsageResource) UsageSchema() []*UsageItem  { return nil }
+func (r *testUsageResource) PopulateUsage(u *UsageData) { r.Requests = u.GetInt("monthly_requests") }
+func (r *testUsageResource) BuildResource() *Resource {
+	c := &CostComponent{Name: "Requests", UnitMultiplier: decimal.NewFromInt(1)}
+	if r.Requests != nil {
+		c.MonthlyQuantity = decimalPtr(decimal.NewFromInt(*r.Requests))
+	}
+
+	return &Resource{
+		Name:           r.Address,
+		CostComponents: []*CostComponent{c},
+		UsageSchema: []*UsageItem{
+			{Key: "monthly_requests", ValueType: Int64, DefaultValue: 0},
+			{Key: "request_duration_ms", ValueType: Int64, DefaultValue: 0},
+		},
+	}
+}
+
+func TestBuildResourcesWithPastUsage(t *testing.T) {
+	partial := &PartialResource{
+		ResourceData: &ResourceData{
+			Address:   "aws_lambda_function.test",
+			UsageData: NewUsageData("aws_lambda_function.test", ParseAttributes(map[string]interface{}{"monthly_requests": 200})),
+		},
+		CoreResource: &testUsageResource{Address: "aws_lambda_function.test"},
+	}
+
+	pastUsage := NewUsageMapFromInterface(map[string]interface{}{
+		"aws_lambda_function.test": map[string]interface{}{"monthly_requests": 100},
+	})
+
+	project := &Project{
+		PartialPastResources: []*PartialResource{partial},
+		PartialResources:     []*PartialResource{partial},
+		PastUsage:            &pastUsage,
+	}
+	project.BuildResources(UsageMap{})
+
+	assert.Equal(t, "100", project.PastResources[0].CostComponents[0].MonthlyQuantity.String())
+	assert.Equal(t, "200", project.Resources[0].CostComponents[0].MonthlyQuantity.String())
+}
+
+func TestBuildResourcesWithPastUsageLegacyResource(t *testing.T) {
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+
+	partial := &PartialResource{
+		ResourceData: &ResourceData{
+			Type:    "aws_instance",
+			Address: "aws_instance.test",
+		},
+		Resource: &Resource{Name: "aws_instance.test"},
+	}
+
+	pastUsage := NewUsageMapFromInterface(map[string]interface{}{
+		"aws_instance.test": map[string]interface{}{"operating_system": "windows"},
+	})
+
+	project := &Project{
+		PartialPastResources: []*PartialResource{partial},
+		PartialResources:     []*PartialResource{partial},
+		PastUsage:            &pastUsage,
+	}
+	project.BuildResources(UsageMap{})
+
+	assert.Same(t, partial.Resource, project.PastResources[0])
+	if assert.NotNil(t, hook.LastEntry()) {
+		assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+		assert.Contains(t, hook.LastEntry().Message, "Ignoring past usage for aws_instance.test")
+	}
+}
+
+func TestBuildResourcesWithPastUsageResourceFunc(t *testing.T) {
+	rFunc := func(d *ResourceData, u *UsageData) *Resource {
+		r := &testUsageResource{Address: d.Address}
+		r.PopulateUsage(u)
+		return r.BuildResource()
+	}
+
+	d := &ResourceData{
+		Type:      "aws_sqs_queue",
+		Address:   "aws_sqs_queue.test",
+		UsageData: NewUsageData("aws_sqs_queue.test", ParseAttributes(map[string]interface{}{"monthly_requests": 200})),
+	}
+	partial := &PartialResource{
+		ResourceData: d,
+		Resource:     rFunc(d, d.UsageData),
+		ResourceFunc: rFunc,
+	}
+
+	pastUsage := NewUsageMapFromInterface(map[string]interface{}{
+		"aws_sqs_queue.test": map[string]interface{}{"monthly_requests": 100},
+	})
+
+	project := &Project{
+		PartialPastResources: []*PartialResource{partial},
+		PartialResources:     []*PartialResource{partial},
+		PastUsage:            &pastUsage,
+	}
+	project.BuildResources(UsageMap{})
+
+	assert.Equal(t, "aws_sqs_queue", project.PastResources[0].ResourceType)
+	assert.Equal(t, "100", project.PastResources[0].CostComponents[0].MonthlyQuantity.String())
+	assert.Equal(t, "200", project.Resources[0].CostComponents[0].MonthlyQuantity.String())
+}
+
+func TestBuildResourcesInParallel(t *testing.T) {
+	var partials []*PartialResource
+	usage := make(map[string]interface{})
+	for i := 0; i < 50; i++ {
+		address := fmt.Sprintf("aws_lambda_function.test_%d", i)
+		partials = append(partials, &PartialResource{
+			ResourceData: &ResourceData{
+				Address:   address,
+				UsageData: NewUsageData(address, map[string]gjson.Result{}),
+			},
+			CoreResource: &testUsageResource{Address: address},
+		})
+		usage[address] = map[string]interface{}{"monthly_requests": i}
+	}
+
+	pastUsage := NewUsageMapFromInterface(map[string]interface{}{
+		"aws_lambda_function.test_0": map[string]interface{}{"monthly_requests": 100},
+	})
+
+	// The past resources are a subset of the resources, with one that's
+	// removed in the planned state.
+	project := &Project{
+		PartialPastResources: append([]*PartialResource{partials[49]}, partials[:10]...),
+		PartialResources:     partials[:49],
+		PastUsage:            &pastUsage,
+	}
+	project.BuildResourcesInParallel(NewUsageMapFromInterface(usage), 8)
+
+	assert.Len(t, project.Resources, 49)
+	for i, r := range project.Resources {
+		assert.Equal(t, partials[i].ResourceData.Address, r.Name)
+		assert.Equal(t, fmt.Sprintf("%d", i), r.CostComponents[0].MonthlyQuantity.String())
+	}
+
+	assert.Len(t, project.PastResources, 11)
+	assert.Equal(t, "aws_lambda_function.test_49", project.PastResources[0].Name)
+	assert.Equal(t, "aws_lambda_function.test_0", project.PastResources[1].Name)
+	assert.Equal(t, "100", project.PastResources[1].CostComponents[0].MonthlyQuantity.String())
+	assert.Equal(t, "aws_lambda_function.test_9", project.PastResources[10].Name)
+}
+
+func TestForEachInParallelPanic(t *testing.T) {
+	var recovered interface{}
+	func() {
+		defer func() { recovered = recover() }()
+		forEachInParallel(10, 4, func(i int) {
+			if i == 5 {
+				panic("failed to build resource")
+			}
+		})
+	}()
+
+	assert.Contains(t, fmt.Sprintf("%v", recovered), "failed to build resource")
+}
+
+func TestBuildResourcesUnsetUsageKeys(t *testing.T) {
+	withUsage := &PartialResource{
+		ResourceData: &ResourceData{
+			Address:   "aws_lambda_function.with_usage",
+			UsageData: NewUsageData("aws_lambda_function.with_usage", map[string]gjson.Result{}),
+		},
+		CoreResource: &testUsageResource{Address: "aws_lambda_function.with_usage"},
+	}
+	withoutUsage := &PartialResource{
+		ResourceData: &ResourceData{
+			Address:   "aws_lambda_function.without_usage",
+			UsageData: NewUsageData("aws_lambda_function.without_usage", map[string]gjson.Result{}),
+		},
+		CoreResource: &testUsageResource{Address: "aws_lambda_function.without_usage"},
+	}
+
+	usageMap := NewUsageMapFromInterface(map[string]interface{}{
+		"aws_lambda_function.with_usage": map[string]interface{}{"monthly_requests": 100},
+	})
+
+	project := &Project{PartialResources: []*PartialResource{withUsage, withoutUsage}}
+	project.BuildResources(usageMap)
+
+	assert.Equal(t, []string{"request_duration_ms"}, project.Resources[0].UnsetUsageKeys)
+	assert.Equal(t, []string{"monthly_requests", "request_duration_ms"}, project.Resources[1].UnsetUsageKeys)
+}
+
+func TestBuildResourcesUsageRanges(t *testing.T) {
+	partial := &PartialResource{
+		ResourceData: &ResourceData{
+			Address:   "aws_lambda_function.test",
+			UsageData: NewUsageData("aws_lambda_function.test", map[string]gjson.Result{}),
+		},
+		CoreResource: &testUsageResource{Address: "aws_lambda_function.test"},
+	}
+
+	usageMap := NewUsageMapFromInterface(map[string]interface{}{
+		"aws_lambda_function.test": map[string]interface{}{
+			"monthly_requests": map[string]interface{}{"min": 100, "max": 500},
+		},
+	})
+
+	project := &Project{PartialResources: []*PartialResource{partial}}
+	project.BuildResources(usageMap)
+
+	r := project.Resources[0]
+	c := r.CostComponents[0]
+	assert.Equal(t, "300", c.MonthlyQuantity.String())
+	assert.Equal(t, "100", c.LowMonthlyQuantity.String())
+	assert.Equal(t, "500", c.HighMonthlyQuantity.String())
+
+	c.SetPrice(decimal.NewFromFloat(0.5))
+	r.CalculateCosts()
+	assert.Equal(t, "150", r.MonthlyCost.String())
+	assert.Equal(t, "50", r.LowMonthlyCost.String())
+	assert.Equal(t, "250", r.HighMonthlyCost.String())
+
+	// The resource is built with the expected usage so the range bounds don't
+	// affect the core resource.
+	assert.Equal(t, int64(300), *partial.CoreResource.(*testUsageResource).Requests)
+}
+
+func TestBuildResourcesGrowth(t *testing.T) {
+	newPartial := func(address string) *PartialResource {
+		return &PartialResource{
+			ResourceData: &ResourceData{
+				Address:   address,
+				UsageData: NewUsageData(address, map[string]gjson.Result{}),
+			},
+			CoreResource: &testUsageResource{Address: address},
+		}
+	}
+
+	usageMap := NewUsageMapFromInterface(map[string]interface{}{
+		"aws_lambda_function.test": map[string]interface{}{"monthly_requests": 100},
+	})
+
+	project := &Project{
+		PartialResources: []*PartialResource{newPartial("aws_lambda_function.test")},
+		Growth:           &Growth{UsageMonthlyRates: map[string]float64{"monthly_requests": 100}},
+	}
+	project.BuildResources(usageMap)
+
+	r := project.Resources[0]
+	c := r.CostComponents[0]
+	assert.Equal(t, "100", c.MonthlyQuantity.String())
+	assert.Equal(t, "34125", c.ProjectedMonthlyQuantities[12].String())
+
+	c.SetPrice(decimal.NewFromFloat(0.5))
+	r.CalculateCosts()
+	project.CalculateProjections()
+	assert.Equal(t, "204750", r.ProjectedCosts[12].String())
+
+	// The resource is built with the usage without growth.
+	assert.Equal(t, int64(100), *project.PartialResources[0].CoreResource.(*testUsageResource).Requests)
+
+	// The monthly rate of the project applies to the whole cost, so the
+	// cost of $50 doubling each month adds up to $50 * (2^36 - 1).
+	project.Growth = &Growth{MonthlyRate: 100}
+	c.ProjectedMonthlyQuantities = nil
+	project.CalculateProjections()
+	assert.Equal(t, "3435973836750", r.ProjectedCosts[36].String())
+}
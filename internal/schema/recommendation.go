@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// RecommendationTypeRightsizing recommends smaller instance types based on
+	// the utilization of the resource.
+	RecommendationTypeRightsizing = "rightsizing"
+	// RecommendationTypeQuickWin recommends well-known cheaper alternatives to
+	// the configuration of the resource, e.g. gp3 instead of gp2 volumes.
+	RecommendationTypeQuickWin = "quick_win"
+)
+
+// Recommendation is a cheaper alternative to some of the cost components of a
+// resource, e.g. a smaller instance type for an instance that uses little of
+// its CPU and memory.
+type Recommendation struct {
+	// Type is the kind of recommendation, e.g. RecommendationTypeRightsizing.
+	Type string
+	// Description explains the recommendation and why it applies.
+	Description string
+	// CostComponents are the cost components of the resource that the
+	// recommendation replaces.
+	CostComponents []*CostComponent
+	// Alternative has the cost components that replace them. Its prices are
+	// looked up with the prices of the resource, and cost components that have
+	// no price are removed. It's nil if the recommendation removes the cost
+	// components, e.g. for resources that aren't used.
+	Alternative *Resource
+}
+
+// MonthlySavings returns how much less the alternative costs per month than
+// the cost components it replaces, or nil if either has no monthly cost or
+// the alternative couldn't be priced.
+func (r *Recommendation) MonthlySavings() *decimal.Decimal {
+	alternative := decimal.Zero
+	if r.Alternative != nil {
+		if len(r.Alternative.CostComponents) == 0 || r.Alternative.MonthlyCost == nil {
+			return nil
+		}
+
+		alternative = *r.Alternative.MonthlyCost
+	}
+
+	current := decimal.Zero
+	for _, c := range r.CostComponents {
+		if c.MonthlyCost == nil {
+			return nil
+		}
+
+		current = current.Add(*c.MonthlyCost)
+	}
+
+	savings := current.Sub(alternative)
+	return &savings
+}
+
+// RecommendationAlternatives returns the alternatives of the recommendations
+// of the resource and its sub resources, whose prices are looked up with the
+// prices of the resource.
+func (r *Resource) RecommendationAlternatives() []*Resource {
+	var alternatives []*Resource
+	for _, res := range append([]*Resource{r}, r.FlattenedSubResources()...) {
+		for _, rec := range res.Recommendations {
+			if rec.Alternative != nil {
+				alternatives = append(alternatives, rec.Alternative)
+			}
+		}
+	}
+
+	return alternatives
+}
@@ -6,6 +6,11 @@ type ReferenceIDFunc func(d *ResourceData) []string
 // CloudResourceIDFunc is used to calculate the cloud resource ids (AWS ARN, Google HREF, etc...) associated with the resource
 type CloudResourceIDFunc func(d *ResourceData) []string
 
+// DataSourceResolver returns the pricing hints of a data source, keyed by the
+// hint name, e.g. the operating system of the image that an aws_ami data source
+// finds. Resources that reference the data source are given its hints.
+type DataSourceResolver func(d *ResourceData) map[string]string
+
 type RegistryItem struct {
 	Name                string
 	Notes               []string
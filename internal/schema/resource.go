@@ -10,6 +10,15 @@ import (
 
 var HourToMonthUnitMultiplier = decimal.NewFromInt(730)
 
+// CostScopeMetadataKey is the resource metadata key of the scope that the cloud
+// reports the cost of the resource in, i.e. the AWS account, Azure resource
+// group or GCP project.
+const CostScopeMetadataKey = "costScope"
+
+// RegionMetadataKey is the resource metadata key of the region of the
+// resource.
+const RegionMetadataKey = "region"
+
 type ResourceFunc func(*ResourceData, *UsageData) *Resource
 
 type Resource struct {
@@ -27,10 +36,28 @@ type Resource struct {
 	UsageSchema       []*UsageItem
 	EstimateUsage     EstimateFunc
 	EstimationSummary map[string]bool
-	Metadata          map[string]gjson.Result
+	// UnsetUsageKeys are the keys of the UsageSchema that the resource was
+	// built without a value for.
+	UnsetUsageKeys []string
+	// UnknownInputs are the attributes that the resource was built without a
+	// known value for, so their defaults were used to estimate its cost.
+	UnknownInputs []string
+	// Recommendations are cheaper alternatives to the resource, e.g. smaller
+	// instance types, based on its usage.
+	Recommendations []*Recommendation
+	// LowMonthlyCost and HighMonthlyCost are the bounds of the monthly cost
+	// if any of the cost components has a cost range, otherwise they're nil.
+	LowMonthlyCost  *decimal.Decimal
+	HighMonthlyCost *decimal.Decimal
+	Metadata        map[string]gjson.Result
+	// ProjectedCosts are the cumulative costs over each of the
+	// ProjectionMonths if the project of the resource has a growth.
+	ProjectedCosts map[int]*decimal.Decimal
 }
 
 func CalculateCosts(project *Project) {
+	DeduplicateSharedCosts(project)
+
 	for _, r := range project.AllResources() {
 		r.CalculateCosts()
 	}
@@ -39,7 +66,10 @@ func CalculateCosts(project *Project) {
 func (r *Resource) CalculateCosts() {
 	h := decimal.Zero
 	m := decimal.Zero
+	low := decimal.Zero
+	high := decimal.Zero
 	hasCost := false
+	hasCostRange := false
 
 	for _, c := range r.CostComponents {
 		c.CalculateCosts()
@@ -52,6 +82,14 @@ func (r *Resource) CalculateCosts() {
 		if c.MonthlyCost != nil {
 			m = m.Add(*c.MonthlyCost)
 		}
+		if c.HasCostRange() {
+			hasCostRange = true
+			low = low.Add(*c.LowMonthlyCost)
+			high = high.Add(*c.HighMonthlyCost)
+		} else if c.MonthlyCost != nil {
+			low = low.Add(*c.MonthlyCost)
+			high = high.Add(*c.MonthlyCost)
+		}
 	}
 
 	for _, s := range r.SubResources {
@@ -65,12 +103,33 @@ func (r *Resource) CalculateCosts() {
 		if s.MonthlyCost != nil {
 			m = m.Add(*s.MonthlyCost)
 		}
+		if s.LowMonthlyCost != nil && s.HighMonthlyCost != nil {
+			hasCostRange = true
+			low = low.Add(*s.LowMonthlyCost)
+			high = high.Add(*s.HighMonthlyCost)
+		} else if s.MonthlyCost != nil {
+			low = low.Add(*s.MonthlyCost)
+			high = high.Add(*s.MonthlyCost)
+		}
 	}
 
 	if hasCost {
 		r.HourlyCost = &h
 		r.MonthlyCost = &m
 	}
+
+	r.LowMonthlyCost, r.HighMonthlyCost = nil, nil
+	if hasCostRange {
+		r.LowMonthlyCost = &low
+		r.HighMonthlyCost = &high
+	}
+
+	for _, rec := range r.Recommendations {
+		if rec.Alternative != nil {
+			rec.Alternative.CalculateCosts()
+		}
+	}
+
 	if r.NoPrice {
 		log.Debugf("Skipping free resource %s", r.Name)
 	}
@@ -90,6 +149,25 @@ func (r *Resource) FlattenedSubResources() []*Resource {
 	return resources
 }
 
+// setSubResourceTypes sets the type of the sub resources that aren't built
+// from another resource type, e.g. the storage classes of an S3 bucket, to the
+// type of their parent so every resource in the hierarchy has a type. Sub
+// resources that are typed already, e.g. the aws_ebs_volume block devices of
+// an instance, keep their type.
+func setSubResourceTypes(r *Resource) {
+	for _, s := range r.SubResources {
+		if s == nil {
+			continue
+		}
+
+		if s.ResourceType == "" {
+			s.ResourceType = r.ResourceType
+		}
+
+		setSubResourceTypes(s)
+	}
+}
+
 func (r *Resource) RemoveCostComponent(costComponent *CostComponent) {
 	n := make([]*CostComponent, 0, len(r.CostComponents)-1)
 	for _, c := range r.CostComponents {
@@ -115,6 +193,15 @@ func MultiplyQuantities(resource *Resource, multiplier decimal.Decimal) {
 		if costComponent.MonthlyQuantity != nil {
 			costComponent.MonthlyQuantity = decimalPtr(costComponent.MonthlyQuantity.Mul(multiplier))
 		}
+		if costComponent.LowMonthlyQuantity != nil {
+			costComponent.LowMonthlyQuantity = decimalPtr(costComponent.LowMonthlyQuantity.Mul(multiplier))
+		}
+		if costComponent.HighMonthlyQuantity != nil {
+			costComponent.HighMonthlyQuantity = decimalPtr(costComponent.HighMonthlyQuantity.Mul(multiplier))
+		}
+		for months, q := range costComponent.ProjectedMonthlyQuantities {
+			costComponent.ProjectedMonthlyQuantities[months] = decimalPtr(q.Mul(multiplier))
+		}
 	}
 
 	for _, subResource := range resource.SubResources {
@@ -0,0 +1,510 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/logging"
+)
+
+const (
+	resourceCacheKindPlanned = "planned"
+	resourceCacheKindPast    = "past"
+)
+
+// ResourceCache stores the resources of the previous run of a project once
+// their prices are looked up, keyed by a hash of everything that they're built
+// from: their attributes, references, usage and the growth of the project.
+// Resources with the same hash as one from the previous run are used from the
+// cache instead of being built and priced again, so runs of small changes only
+// build and price the resources that changed.
+//
+// Cached resources are stored before their prices are converted to another
+// currency or have overrides, free tiers or discounts applied, as those are
+// applied to all the resources of each run. They expire after the TTL, the
+// same as cached prices, so new prices are eventually looked up.
+type ResourceCache struct {
+	path string
+	// namespace separates the resources of runs that would build or price
+	// them differently, e.g. with another version, currency or plugins.
+	namespace string
+	ttl       time.Duration
+	prior     map[string]resourceCacheEntry
+
+	mu      sync.Mutex
+	keys    map[*Resource]string
+	cached  map[*Resource]bool
+	entries map[string]resourceCacheEntry
+}
+
+// resourceCacheEntry is a cached resource and when its prices were looked up.
+type resourceCacheEntry struct {
+	CachedAt time.Time       `json:"cachedAt"`
+	Resource json.RawMessage `json:"resource"`
+}
+
+// LoadResourceCache returns a ResourceCache with the resources stored in the
+// file at path that were cached less than ttl ago. The cache is empty if the
+// file doesn't exist or can't be read.
+func LoadResourceCache(path string, namespace string, ttl time.Duration) *ResourceCache {
+	c := &ResourceCache{
+		path:      path,
+		namespace: namespace,
+		ttl:       ttl,
+		prior:     make(map[string]resourceCacheEntry),
+		keys:      make(map[*Resource]string),
+		cached:    make(map[*Resource]bool),
+		entries:   make(map[string]resourceCacheEntry),
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+
+	var prior map[string]resourceCacheEntry
+	err = json.Unmarshal(b, &prior)
+	if err != nil {
+		logging.Logger.Debugf("Ignoring invalid resource cache %s: %s", path, err)
+		return c
+	}
+
+	for key, entry := range prior {
+		if time.Since(entry.CachedAt) < ttl {
+			c.prior[key] = entry
+		}
+	}
+
+	return c
+}
+
+// build returns the resource from the cache if one was built from the same
+// inputs in the previous run, otherwise it builds the resource with fn. It's
+// safe to call from multiple goroutines, and calls fn directly if c is nil.
+func (c *ResourceCache) build(kind string, partial *PartialResource, usage *UsageData, growth *Growth, fn func() *Resource) *Resource {
+	if c == nil {
+		return fn()
+	}
+
+	key, ok := c.key(kind, partial, usage, growth)
+	if !ok {
+		return fn()
+	}
+
+	if entry, ok := c.prior[key]; ok {
+		var cr cachedResource
+		if err := json.Unmarshal(entry.Resource, &cr); err == nil {
+			r := cr.toResource()
+
+			c.mu.Lock()
+			c.keys[r] = key
+			c.cached[r] = true
+			c.entries[key] = entry
+			c.mu.Unlock()
+
+			return r
+		}
+	}
+
+	r := fn()
+
+	c.mu.Lock()
+	c.keys[r] = key
+	c.mu.Unlock()
+
+	return r
+}
+
+// resourceCacheInputs are everything that a resource is built from, which is
+// hashed to get the key that the resource is cached with.
+type resourceCacheInputs struct {
+	Namespace    string
+	Kind         string
+	Type         string
+	ProviderName string
+	Address      string
+	Tags         map[string]string
+	Attributes   string
+	References   map[string][]string
+	Metadata     map[string]string
+	PricingHints map[string]string
+	UnknownKeys  []string
+	CFResource   interface{}
+	CoreResource CoreResource
+	Usage        map[string]string
+	Growth       *Growth
+}
+
+// key returns the key of the resource built from the inputs, and false if
+// they can't be hashed. The attributes of the resources that a resource
+// references are included transitively, as resource builders can use the
+// references of references, e.g. the volume of the snapshot that an
+// aws_ebs_snapshot_copy copies.
+func (c *ResourceCache) key(kind string, partial *PartialResource, usage *UsageData, growth *Growth) (string, bool) {
+	d := partial.ResourceData
+	if d == nil {
+		return "", false
+	}
+
+	inputs := resourceCacheInputs{
+		Namespace:    c.namespace,
+		Kind:         kind,
+		Type:         d.Type,
+		ProviderName: d.ProviderName,
+		Address:      d.Address,
+		Tags:         d.Tags,
+		Attributes:   d.RawValues.Raw,
+		References:   make(map[string][]string, len(d.ReferencesMap)),
+		Metadata:     rawValues(d.Metadata),
+		PricingHints: d.PricingHints,
+		UnknownKeys:  d.UnknownKeys,
+		CFResource:   d.CFResource,
+		CoreResource: partial.CoreResource,
+		Growth:       growth,
+	}
+
+	addReferenceInputs(inputs.References, "", d, map[*ResourceData]bool{d: true})
+
+	if usage != nil {
+		inputs.Usage = rawValues(usage.Attributes)
+	}
+
+	b, err := json.Marshal(inputs)
+	if err != nil {
+		return "", false
+	}
+
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), true
+}
+
+// addReferenceInputs adds the addresses and attributes of the resources that d
+// references to refs, keyed by prefix and the reference attribute, then adds
+// the references of those resources keyed by the path to them. Each resource's
+// references are only added once, so reference cycles end, and the attributes
+// are visited in order so the same resources always get the same keys.
+func addReferenceInputs(refs map[string][]string, prefix string, d *ResourceData, seen map[*ResourceData]bool) {
+	keys := make([]string, 0, len(d.ReferencesMap))
+	for k := range d.ReferencesMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var next []*ResourceData
+	var nextPrefixes []string
+
+	for _, k := range keys {
+		for _, ref := range d.ReferencesMap[k] {
+			refs[prefix+k] = append(refs[prefix+k], ref.Address+" "+ref.RawValues.Raw)
+
+			if !seen[ref] {
+				seen[ref] = true
+				next = append(next, ref)
+				nextPrefixes = append(nextPrefixes, prefix+k+"."+ref.Address+".")
+			}
+		}
+	}
+
+	for i, ref := range next {
+		addReferenceInputs(refs, nextPrefixes[i], ref, seen)
+	}
+}
+
+func rawValues(m map[string]gjson.Result) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	raw := make(map[string]string, len(m))
+	for k, v := range m {
+		raw[k] = v.Raw
+	}
+
+	return raw
+}
+
+// IsCached returns true if the resource was used from the cache.
+func (c *ResourceCache) IsCached(r *Resource) bool {
+	if c == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cached[r]
+}
+
+// Save replaces the cached resources with the resources of this run. It must
+// be called once the prices of the resources are looked up. Resources whose
+// prices couldn't all be found aren't cached, so they're looked up again in
+// the next run. Resources used from the cache keep the time that they were
+// first cached, so they still expire.
+func (c *ResourceCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	built := 0
+	for r, key := range c.keys {
+		if c.cached[r] {
+			continue
+		}
+
+		built++
+		if hasPriceWarnings(r) {
+			continue
+		}
+
+		b, err := json.Marshal(newCachedResource(r))
+		if err != nil {
+			continue
+		}
+
+		c.entries[key] = resourceCacheEntry{CachedAt: now, Resource: b}
+	}
+
+	logging.Logger.Debugf("Used %d cached resources, built %d resources", len(c.cached), built)
+
+	b, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.path), 0700)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a concurrent run never loads a
+	// partially written cache.
+	f, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), c.path)
+}
+
+func hasPriceWarnings(r *Resource) bool {
+	for _, res := range append(append([]*Resource{r}, r.FlattenedSubResources()...), r.RecommendationAlternatives()...) {
+		for _, c := range res.CostComponents {
+			if c.priceWarning != "" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ResourcesToPrice returns the resources of the project that need their
+// prices looked up, which are all of them except those used from the
+// ResourceCache.
+func (p *Project) ResourcesToPrice() []*Resource {
+	all := p.AllResources()
+	if p.ResourceCache == nil {
+		return all
+	}
+
+	resources := make([]*Resource, 0, len(all))
+	for _, r := range all {
+		if !p.ResourceCache.IsCached(r) {
+			resources = append(resources, r)
+		}
+	}
+
+	return resources
+}
+
+// cachedResource is the part of a Resource that's set when it's built and its
+// prices are looked up, in a form that can be stored as JSON.
+type cachedResource struct {
+	Name              string                  `json:"name"`
+	ResourceType      string                  `json:"resourceType"`
+	CostComponents    []*cachedCostComponent  `json:"costComponents,omitempty"`
+	SubResources      []*cachedResource       `json:"subResources,omitempty"`
+	IsSkipped         bool                    `json:"isSkipped,omitempty"`
+	NoPrice           bool                    `json:"noPrice,omitempty"`
+	SkipMessage       string                  `json:"skipMessage,omitempty"`
+	Tags              map[string]string       `json:"tags,omitempty"`
+	EstimationSummary map[string]bool         `json:"estimationSummary,omitempty"`
+	UnsetUsageKeys    []string                `json:"unsetUsageKeys,omitempty"`
+	UnknownInputs     []string                `json:"unknownInputs,omitempty"`
+	Recommendations   []*cachedRecommendation `json:"recommendations,omitempty"`
+	Metadata          map[string]string       `json:"metadata,omitempty"`
+}
+
+type cachedCostComponent struct {
+	Name                       string                   `json:"name"`
+	Unit                       string                   `json:"unit"`
+	UnitMultiplier             decimal.Decimal          `json:"unitMultiplier"`
+	IgnoreIfMissingPrice       bool                     `json:"ignoreIfMissingPrice,omitempty"`
+	ProductFilter              *ProductFilter           `json:"productFilter,omitempty"`
+	PriceFilter                *PriceFilter             `json:"priceFilter,omitempty"`
+	HourlyQuantity             *decimal.Decimal         `json:"hourlyQuantity,omitempty"`
+	MonthlyQuantity            *decimal.Decimal         `json:"monthlyQuantity,omitempty"`
+	MonthlyDiscountPerc        float64                  `json:"monthlyDiscountPerc,omitempty"`
+	Price                      decimal.Decimal          `json:"price"`
+	CustomPrice                *decimal.Decimal         `json:"customPrice,omitempty"`
+	PriceHash                  string                   `json:"priceHash,omitempty"`
+	LowMonthlyQuantity         *decimal.Decimal         `json:"lowMonthlyQuantity,omitempty"`
+	HighMonthlyQuantity        *decimal.Decimal         `json:"highMonthlyQuantity,omitempty"`
+	ProjectedMonthlyQuantities map[int]*decimal.Decimal `json:"projectedMonthlyQuantities,omitempty"`
+	PriceFallback              *PriceFallback           `json:"priceFallback,omitempty"`
+	SharedCostKey              string                   `json:"sharedCostKey,omitempty"`
+	UsageBased                 bool                     `json:"usageBased,omitempty"`
+}
+
+// cachedRecommendation has the indexes of the cost components of the resource
+// that it replaces, so they're still the same cost components when it's
+// loaded.
+type cachedRecommendation struct {
+	Type           string          `json:"type"`
+	Description    string          `json:"description"`
+	CostComponents []int           `json:"costComponents,omitempty"`
+	Alternative    *cachedResource `json:"alternative,omitempty"`
+}
+
+func newCachedResource(r *Resource) *cachedResource {
+	cr := &cachedResource{
+		Name:              r.Name,
+		ResourceType:      r.ResourceType,
+		IsSkipped:         r.IsSkipped,
+		NoPrice:           r.NoPrice,
+		SkipMessage:       r.SkipMessage,
+		Tags:              r.Tags,
+		EstimationSummary: r.EstimationSummary,
+		UnsetUsageKeys:    r.UnsetUsageKeys,
+		UnknownInputs:     r.UnknownInputs,
+		Metadata:          rawValues(r.Metadata),
+	}
+
+	indexes := make(map[*CostComponent]int, len(r.CostComponents))
+	for i, c := range r.CostComponents {
+		indexes[c] = i
+		cr.CostComponents = append(cr.CostComponents, &cachedCostComponent{
+			Name:                       c.Name,
+			Unit:                       c.Unit,
+			UnitMultiplier:             c.UnitMultiplier,
+			IgnoreIfMissingPrice:       c.IgnoreIfMissingPrice,
+			ProductFilter:              c.ProductFilter,
+			PriceFilter:                c.PriceFilter,
+			HourlyQuantity:             c.HourlyQuantity,
+			MonthlyQuantity:            c.MonthlyQuantity,
+			MonthlyDiscountPerc:        c.MonthlyDiscountPerc,
+			Price:                      c.price,
+			CustomPrice:                c.customPrice,
+			PriceHash:                  c.priceHash,
+			LowMonthlyQuantity:         c.LowMonthlyQuantity,
+			HighMonthlyQuantity:        c.HighMonthlyQuantity,
+			ProjectedMonthlyQuantities: c.ProjectedMonthlyQuantities,
+			PriceFallback:              c.PriceFallback,
+			SharedCostKey:              c.SharedCostKey,
+			UsageBased:                 c.UsageBased,
+		})
+	}
+
+	for _, s := range r.SubResources {
+		cr.SubResources = append(cr.SubResources, newCachedResource(s))
+	}
+
+	for _, rec := range r.Recommendations {
+		crec := &cachedRecommendation{Type: rec.Type, Description: rec.Description}
+		for _, c := range rec.CostComponents {
+			if i, ok := indexes[c]; ok {
+				crec.CostComponents = append(crec.CostComponents, i)
+			}
+		}
+		if rec.Alternative != nil {
+			crec.Alternative = newCachedResource(rec.Alternative)
+		}
+
+		cr.Recommendations = append(cr.Recommendations, crec)
+	}
+
+	return cr
+}
+
+func (cr *cachedResource) toResource() *Resource {
+	r := &Resource{
+		Name:              cr.Name,
+		ResourceType:      cr.ResourceType,
+		IsSkipped:         cr.IsSkipped,
+		NoPrice:           cr.NoPrice,
+		SkipMessage:       cr.SkipMessage,
+		Tags:              cr.Tags,
+		EstimationSummary: cr.EstimationSummary,
+		UnsetUsageKeys:    cr.UnsetUsageKeys,
+		UnknownInputs:     cr.UnknownInputs,
+	}
+
+	if cr.Metadata != nil {
+		r.Metadata = make(map[string]gjson.Result, len(cr.Metadata))
+		for k, v := range cr.Metadata {
+			r.Metadata[k] = gjson.Parse(v)
+		}
+	}
+
+	for _, cc := range cr.CostComponents {
+		r.CostComponents = append(r.CostComponents, &CostComponent{
+			Name:                       cc.Name,
+			Unit:                       cc.Unit,
+			UnitMultiplier:             cc.UnitMultiplier,
+			IgnoreIfMissingPrice:       cc.IgnoreIfMissingPrice,
+			ProductFilter:              cc.ProductFilter,
+			PriceFilter:                cc.PriceFilter,
+			HourlyQuantity:             cc.HourlyQuantity,
+			MonthlyQuantity:            cc.MonthlyQuantity,
+			MonthlyDiscountPerc:        cc.MonthlyDiscountPerc,
+			price:                      cc.Price,
+			customPrice:                cc.CustomPrice,
+			priceHash:                  cc.PriceHash,
+			LowMonthlyQuantity:         cc.LowMonthlyQuantity,
+			HighMonthlyQuantity:        cc.HighMonthlyQuantity,
+			ProjectedMonthlyQuantities: cc.ProjectedMonthlyQuantities,
+			PriceFallback:              cc.PriceFallback,
+			SharedCostKey:              cc.SharedCostKey,
+			UsageBased:                 cc.UsageBased,
+		})
+	}
+
+	for _, s := range cr.SubResources {
+		r.SubResources = append(r.SubResources, s.toResource())
+	}
+
+	for _, crec := range cr.Recommendations {
+		rec := &Recommendation{Type: crec.Type, Description: crec.Description}
+		for _, i := range crec.CostComponents {
+			if i >= 0 && i < len(r.CostComponents) {
+				rec.CostComponents = append(rec.CostComponents, r.CostComponents[i])
+			}
+		}
+		if crec.Alternative != nil {
+			rec.Alternative = crec.Alternative.toResource()
+		}
+
+		r.Recommendations = append(r.Recommendations, rec)
+	}
+
+	return r
+}
@@ -0,0 +1,231 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestResourceCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project.json")
+
+	newPartial := func(address string, attributes string) *PartialResource {
+		return &PartialResource{
+			ResourceData: &ResourceData{
+				Type:      "aws_lambda_function",
+				Address:   address,
+				RawValues: gjson.Parse(attributes),
+				UsageData: NewUsageData(address, map[string]gjson.Result{}),
+			},
+			CoreResource: &testUsageResource{Address: address},
+		}
+	}
+
+	usageMap := NewUsageMapFromInterface(map[string]interface{}{
+		"aws_lambda_function.unchanged": map[string]interface{}{"monthly_requests": 100},
+		"aws_lambda_function.changed":   map[string]interface{}{"monthly_requests": 100},
+		"aws_lambda_function.missing":   map[string]interface{}{"monthly_requests": 100},
+	})
+
+	prior := &Project{
+		PartialResources: []*PartialResource{
+			newPartial("aws_lambda_function.unchanged", `{"memory_size":128}`),
+			newPartial("aws_lambda_function.changed", `{"memory_size":128}`),
+			newPartial("aws_lambda_function.missing", `{"memory_size":128}`),
+		},
+		ResourceCache: LoadResourceCache(path, "USD", time.Hour),
+	}
+	prior.BuildResources(usageMap)
+	assert.Len(t, prior.ResourcesToPrice(), 3)
+
+	prior.Resources[0].CostComponents[0].SetPrice(decimal.NewFromFloat(0.2))
+	prior.Resources[0].CostComponents[0].SetPriceHash("abc")
+	prior.Resources[1].CostComponents[0].SetPrice(decimal.NewFromFloat(0.2))
+	prior.Resources[2].CostComponents[0].SetPriceWarning("No prices found")
+	require.NoError(t, prior.ResourceCache.Save())
+
+	project := &Project{
+		PartialResources: []*PartialResource{
+			newPartial("aws_lambda_function.unchanged", `{"memory_size":128}`),
+			newPartial("aws_lambda_function.changed", `{"memory_size":256}`),
+			newPartial("aws_lambda_function.missing", `{"memory_size":128}`),
+		},
+		ResourceCache: LoadResourceCache(path, "USD", time.Hour),
+	}
+	project.BuildResourcesInParallel(usageMap, 4)
+
+	unchanged := project.Resources[0]
+	assert.True(t, project.ResourceCache.IsCached(unchanged))
+	assert.Equal(t, "aws_lambda_function.unchanged", unchanged.Name)
+	assert.Equal(t, "aws_lambda_function", unchanged.ResourceType)
+	assert.Equal(t, "100", unchanged.CostComponents[0].MonthlyQuantity.String())
+	assert.Equal(t, "0.2", unchanged.CostComponents[0].Price().String())
+	assert.Equal(t, "abc", unchanged.CostComponents[0].PriceHash())
+
+	// Resources that changed, and those whose prices weren't found, are built
+	// and priced again.
+	toPrice := project.ResourcesToPrice()
+	sort.Slice(toPrice, func(i, j int) bool { return toPrice[i].Name < toPrice[j].Name })
+	assert.Equal(t, []*Resource{project.Resources[1], project.Resources[2]}, toPrice)
+	assert.Equal(t, "0", project.Resources[1].CostComponents[0].Price().String())
+
+	// The cache is keyed by the usage of the resources too.
+	changedUsage := NewUsageMapFromInterface(map[string]interface{}{
+		"aws_lambda_function.unchanged": map[string]interface{}{"monthly_requests": 200},
+	})
+	withUsage := &Project{
+		PartialResources: []*PartialResource{newPartial("aws_lambda_function.unchanged", `{"memory_size":128}`)},
+		ResourceCache:    LoadResourceCache(path, "USD", time.Hour),
+	}
+	withUsage.BuildResources(changedUsage)
+	assert.False(t, withUsage.ResourceCache.IsCached(withUsage.Resources[0]))
+
+	// Resources cached in another namespace aren't used.
+	otherNamespace := &Project{
+		PartialResources: []*PartialResource{newPartial("aws_lambda_function.unchanged", `{"memory_size":128}`)},
+		ResourceCache:    LoadResourceCache(path, "EUR", time.Hour),
+	}
+	otherNamespace.BuildResources(usageMap)
+	assert.False(t, otherNamespace.ResourceCache.IsCached(otherNamespace.Resources[0]))
+}
+
+func TestResourceCacheNestedReferences(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project.json")
+	usageMap := NewUsageMapFromInterface(map[string]interface{}{})
+
+	newProject := func(volumeSize int) *Project {
+		volume := &ResourceData{
+			Type:      "aws_ebs_volume",
+			Address:   "aws_ebs_volume.volume",
+			RawValues: gjson.Parse(fmt.Sprintf(`{"size":%d}`, volumeSize)),
+		}
+		snapshot := &ResourceData{
+			Type:          "aws_ebs_snapshot",
+			Address:       "aws_ebs_snapshot.snapshot",
+			RawValues:     gjson.Parse(`{}`),
+			ReferencesMap: map[string][]*ResourceData{"volume_id": {volume}},
+		}
+		// The volume references the snapshot copy too, so the references
+		// have a cycle.
+		copied := &ResourceData{
+			Type:          "aws_ebs_snapshot_copy",
+			Address:       "aws_ebs_snapshot_copy.copy",
+			RawValues:     gjson.Parse(`{}`),
+			ReferencesMap: map[string][]*ResourceData{"source_snapshot_id": {snapshot}},
+			UsageData:     NewUsageData("aws_ebs_snapshot_copy.copy", map[string]gjson.Result{}),
+		}
+		volume.ReferencesMap = map[string][]*ResourceData{"snapshot_id": {copied}}
+
+		return &Project{
+			PartialResources: []*PartialResource{{
+				ResourceData: copied,
+				CoreResource: &testUsageResource{Address: copied.Address},
+			}},
+			ResourceCache: LoadResourceCache(path, "USD", time.Hour),
+		}
+	}
+
+	prior := newProject(10)
+	prior.BuildResources(usageMap)
+	prior.Resources[0].CostComponents[0].SetPrice(decimal.NewFromFloat(0.05))
+	require.NoError(t, prior.ResourceCache.Save())
+
+	cached := newProject(10)
+	cached.BuildResources(usageMap)
+	assert.True(t, cached.ResourceCache.IsCached(cached.Resources[0]))
+
+	// The volume of the snapshot is a reference of a reference of the
+	// snapshot copy, so the copy is built again when it changes.
+	changed := newProject(20)
+	changed.BuildResources(usageMap)
+	assert.False(t, changed.ResourceCache.IsCached(changed.Resources[0]))
+}
+
+func TestResourceCacheExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project.json")
+	usageMap := NewUsageMapFromInterface(map[string]interface{}{})
+
+	newProject := func(namespace string) *Project {
+		return &Project{
+			PartialResources: []*PartialResource{{
+				ResourceData: &ResourceData{
+					Type:      "acme_database",
+					Address:   "acme_database.main",
+					RawValues: gjson.Parse(`{"size":"large"}`),
+					UsageData: NewUsageData("acme_database.main", map[string]gjson.Result{}),
+				},
+				CoreResource: &testUsageResource{Address: "acme_database.main"},
+			}},
+			ResourceCache: LoadResourceCache(path, namespace, 24*time.Hour),
+		}
+	}
+
+	prior := newProject(`{"plugins":"mappings-v1"}`)
+	prior.BuildResources(usageMap)
+	prior.Resources[0].CostComponents[0].SetPrice(decimal.NewFromFloat(0.2))
+	require.NoError(t, prior.ResourceCache.Save())
+
+	cached := newProject(`{"plugins":"mappings-v1"}`)
+	cached.BuildResources(usageMap)
+	assert.True(t, cached.ResourceCache.IsCached(cached.Resources[0]))
+
+	// Resources built with other resource mappings or plugins are built again.
+	otherMappings := newProject(`{"plugins":"mappings-v2"}`)
+	otherMappings.BuildResources(usageMap)
+	assert.False(t, otherMappings.ResourceCache.IsCached(otherMappings.Resources[0]))
+
+	// Resources cached longer ago than the TTL are built again, and resources
+	// used from the cache keep the time that they were first cached.
+	require.NoError(t, cached.ResourceCache.Save())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var entries map[string]resourceCacheEntry
+	require.NoError(t, json.Unmarshal(b, &entries))
+	require.Len(t, entries, 1)
+	for key, entry := range entries {
+		entry.CachedAt = entry.CachedAt.Add(-25 * time.Hour)
+		entries[key] = entry
+	}
+
+	b, err = json.Marshal(entries)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, b, 0600))
+
+	expired := newProject(`{"plugins":"mappings-v1"}`)
+	expired.BuildResources(usageMap)
+	assert.False(t, expired.ResourceCache.IsCached(expired.Resources[0]))
+	assert.Equal(t, []*Resource{expired.Resources[0]}, expired.ResourcesToPrice())
+}
+
+func TestResourceCacheRecommendations(t *testing.T) {
+	c := &CostComponent{Name: "Instance usage", UnitMultiplier: decimal.NewFromInt(1)}
+	alternative := &CostComponent{Name: "Instance usage (t3.small)", UnitMultiplier: decimal.NewFromInt(1)}
+	alternative.SetPrice(decimal.NewFromFloat(0.02))
+
+	r := &Resource{
+		Name:           "aws_instance.web",
+		CostComponents: []*CostComponent{c},
+		Recommendations: []*Recommendation{{
+			Type:           RecommendationTypeRightsizing,
+			CostComponents: []*CostComponent{c},
+			Alternative:    &Resource{Name: "aws_instance.web", CostComponents: []*CostComponent{alternative}},
+		}},
+		Metadata: map[string]gjson.Result{"calls": gjson.Parse(`[{"blockName":"web"}]`)},
+	}
+
+	loaded := newCachedResource(r).toResource()
+	assert.Same(t, loaded.CostComponents[0], loaded.Recommendations[0].CostComponents[0])
+	assert.Equal(t, "0.02", loaded.Recommendations[0].Alternative.CostComponents[0].Price().String())
+	assert.Equal(t, "web", loaded.Metadata["calls"].Get("0.blockName").String())
+}
@@ -2,11 +2,17 @@ package schema
 
 import (
 	"encoding/json"
+	"sort"
+	"strings"
 
 	"github.com/awslabs/goformation/v4/cloudformation"
 	"github.com/tidwall/gjson"
 )
 
+// OperatingSystemHint is the pricing hint of the operating system of the image
+// that a resource is created from, i.e. linux, windows, rhel or suse.
+const OperatingSystemHint = "operating_system"
+
 type ResourceData struct {
 	Type          string
 	ProviderName  string
@@ -17,6 +23,18 @@ type ResourceData struct {
 	CFResource    cloudformation.Resource
 	UsageData     *UsageData
 	Metadata      map[string]gjson.Result
+	// PricingHints are resolved from the data sources that the resource
+	// references, e.g. the operating system of the image of a VM, so costs
+	// that depend on them don't have to assume a default.
+	PricingHints map[string]string
+
+	// UnknownKeys are the paths of the values that weren't known when the
+	// resource was parsed, e.g. values computed by the provider or that depend
+	// on data sources, so the resource has a placeholder or no value for them.
+	UnknownKeys []string
+	// accessedKeys are the keys read between TrackAccess and StopTracking.
+	accessedKeys map[string]bool
+	tracking     bool
 }
 
 func NewResourceData(resourceType string, providerName string, address string, tags map[string]string, rawValues gjson.Result) *ResourceData {
@@ -44,9 +62,62 @@ func NewCFResourceData(resourceType string, providerName string, address string,
 }
 
 func (d *ResourceData) Get(key string) gjson.Result {
+	d.recordAccess(key)
 	return d.RawValues.Get(key)
 }
 
+// TrackAccess starts recording the keys that are read from the ResourceData,
+// so UnknownInputs can return the unknown values that the cost of the resource
+// depends on.
+func (d *ResourceData) TrackAccess() {
+	d.accessedKeys = make(map[string]bool)
+	d.tracking = true
+}
+
+// StopTracking stops recording the keys that are read, so the keys that other
+// resources read through their references aren't recorded.
+func (d *ResourceData) StopTracking() {
+	d.tracking = false
+}
+
+func (d *ResourceData) recordAccess(key string) {
+	if d.tracking {
+		d.accessedKeys[key] = true
+	}
+}
+
+// UnknownInputs returns the UnknownKeys that were read between TrackAccess
+// and StopTracking. A key counts as read if it, a value within it or the value it is
+// within has been read.
+func (d *ResourceData) UnknownInputs() []string {
+	var inputs []string
+
+	for _, unknown := range d.UnknownKeys {
+		for key := range d.accessedKeys {
+			if pathsOverlap(strings.Split(unknown, "."), strings.Split(key, ".")) {
+				inputs = append(inputs, unknown)
+				break
+			}
+		}
+	}
+
+	sort.Strings(inputs)
+
+	return inputs
+}
+
+// pathsOverlap returns true if one of the paths is within the other. The #
+// wildcard of gjson paths matches any index.
+func pathsOverlap(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] && a[i] != "#" && b[i] != "#" {
+			return false
+		}
+	}
+
+	return true
+}
+
 // GetStringOrDefault returns the value of key within ResourceData as a string.
 // If the retrieved value is not set GetStringOrDefault will return def.
 func (d *ResourceData) GetStringOrDefault(key, def string) string {
@@ -88,6 +159,7 @@ func (d *ResourceData) GetBoolOrDefault(key string, def bool) bool {
 // Return true if the key doesn't exist, is null, or is an empty string.
 // Needed because gjson.Exists returns true as long as a key exists, even if it's empty or null.
 func (d *ResourceData) IsEmpty(key string) bool {
+	d.recordAccess(key)
 	g := d.RawValues.Get(key)
 	return g.Type == gjson.Null || len(g.Raw) == 0 || g.Raw == "\"\"" || emptyObjectOrArray(g)
 }
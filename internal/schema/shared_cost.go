@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"sort"
+)
+
+// DeduplicateSharedCosts makes sure that cost components with the same SharedCostKey
+// are only counted once per project state. The cost is attributed to the resource whose
+// name is the SharedCostKey, e.g. the load balancer of the listeners that reference its
+// cost, or to the first resource (ordered by name) that has the component if that
+// resource isn't in the project. All other components are marked as references to that
+// resource and have their costs zeroed when costs are calculated.
+func DeduplicateSharedCosts(project *Project) {
+	current := make(map[*Resource]bool, len(project.Resources))
+	for _, r := range project.Resources {
+		current[r] = true
+	}
+
+	owners := map[string]string{}
+	deduplicateSharedCosts(project.Resources, owners)
+
+	// Past and current resources can be the same pointer if the resource has not
+	// changed. These have already been processed so we seed the owners from them
+	// and only process the past-only resources.
+	pastOwners := map[string]string{}
+	pastOnly := make([]*Resource, 0, len(project.PastResources))
+	for _, r := range project.PastResources {
+		if !current[r] {
+			pastOnly = append(pastOnly, r)
+			continue
+		}
+
+		for _, c := range sharedCostComponents(r) {
+			if c.SharedWith() == "" {
+				pastOwners[c.SharedCostKey] = r.Name
+			}
+		}
+	}
+
+	deduplicateSharedCosts(pastOnly, pastOwners)
+}
+
+func deduplicateSharedCosts(resources []*Resource, owners map[string]string) {
+	sorted := make([]*Resource, len(resources))
+	copy(sorted, resources)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	for _, r := range sorted {
+		for _, c := range sharedCostComponents(r) {
+			if _, ok := owners[c.SharedCostKey]; !ok && c.SharedCostKey == r.Name {
+				owners[c.SharedCostKey] = r.Name
+			}
+		}
+	}
+
+	for _, r := range sorted {
+		for _, c := range sharedCostComponents(r) {
+			owner, ok := owners[c.SharedCostKey]
+			if !ok || owner == r.Name {
+				owners[c.SharedCostKey] = r.Name
+				c.SetSharedWith("")
+				continue
+			}
+
+			c.SetSharedWith(owner)
+		}
+	}
+}
+
+// sharedCostComponents returns all the cost components of the resource and its
+// sub resources that have a SharedCostKey set.
+func sharedCostComponents(r *Resource) []*CostComponent {
+	var components []*CostComponent
+
+	for _, c := range r.CostComponents {
+		if c.SharedCostKey != "" {
+			components = append(components, c)
+		}
+	}
+
+	for _, s := range r.FlattenedSubResources() {
+		for _, c := range s.CostComponents {
+			if c.SharedCostKey != "" {
+				components = append(components, c)
+			}
+		}
+	}
+
+	return components
+}
@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSharedCostComponent(key string) *CostComponent {
+	c := &CostComponent{
+		Name:            "Shared",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		SharedCostKey:   key,
+	}
+	c.SetPrice(decimal.NewFromInt(10))
+
+	return c
+}
+
+func TestDeduplicateSharedCosts(t *testing.T) {
+	a := &Resource{Name: "aws_lb_listener.b", CostComponents: []*CostComponent{newSharedCostComponent("lb")}}
+	b := &Resource{Name: "aws_lb_listener.a", CostComponents: []*CostComponent{newSharedCostComponent("lb")}}
+	c := &Resource{Name: "aws_lb_listener.c", SubResources: []*Resource{
+		{Name: "sub", CostComponents: []*CostComponent{newSharedCostComponent("lb")}},
+	}}
+	other := &Resource{Name: "aws_lb_listener.d", CostComponents: []*CostComponent{newSharedCostComponent("other")}}
+
+	project := &Project{Resources: []*Resource{a, b, c, other}}
+	CalculateCosts(project)
+
+	assert.Equal(t, "", b.CostComponents[0].SharedWith())
+	assert.Equal(t, "aws_lb_listener.a", a.CostComponents[0].SharedWith())
+	assert.Equal(t, "aws_lb_listener.a", c.SubResources[0].CostComponents[0].SharedWith())
+	assert.Equal(t, "", other.CostComponents[0].SharedWith())
+
+	assert.Equal(t, "10", b.MonthlyCost.String())
+	assert.Equal(t, "0", a.MonthlyCost.String())
+	assert.Equal(t, "0", c.MonthlyCost.String())
+	assert.Equal(t, "10", other.MonthlyCost.String())
+}
+
+func TestDeduplicateSharedCostsPastResources(t *testing.T) {
+	unchanged := &Resource{Name: "aws_lb_listener.b", CostComponents: []*CostComponent{newSharedCostComponent("lb")}}
+	removed := &Resource{Name: "aws_lb_listener.a", CostComponents: []*CostComponent{newSharedCostComponent("lb")}}
+
+	project := &Project{
+		PastResources: []*Resource{removed, unchanged},
+		Resources:     []*Resource{unchanged},
+	}
+	CalculateCosts(project)
+
+	assert.Equal(t, "", unchanged.CostComponents[0].SharedWith())
+	assert.Equal(t, "aws_lb_listener.b", removed.CostComponents[0].SharedWith())
+}
+
+func TestDeduplicateSharedCostsKeyedResourceOwnsCost(t *testing.T) {
+	lb := &Resource{Name: "module.lb.aws_lb.this", CostComponents: []*CostComponent{newSharedCostComponent("module.lb.aws_lb.this")}}
+	listener := &Resource{Name: "aws_lb_listener.http", CostComponents: []*CostComponent{newSharedCostComponent("module.lb.aws_lb.this")}}
+	other := &Resource{Name: "aws_lb_listener.a", CostComponents: []*CostComponent{newSharedCostComponent("aws_lb.removed")}}
+	otherB := &Resource{Name: "aws_lb_listener.b", CostComponents: []*CostComponent{newSharedCostComponent("aws_lb.removed")}}
+
+	project := &Project{Resources: []*Resource{listener, lb, otherB, other}}
+	CalculateCosts(project)
+
+	assert.Equal(t, "", lb.CostComponents[0].SharedWith())
+	assert.Equal(t, "module.lb.aws_lb.this", listener.CostComponents[0].SharedWith())
+	assert.Equal(t, "10", lb.MonthlyCost.String())
+	assert.Equal(t, "0", listener.MonthlyCost.String())
+
+	// The keyed resource isn't in the project, so the first resource by name
+	// owns the cost.
+	assert.Equal(t, "", other.CostComponents[0].SharedWith())
+	assert.Equal(t, "aws_lb_listener.a", otherB.CostComponents[0].SharedWith())
+}
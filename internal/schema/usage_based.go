@@ -0,0 +1,25 @@
+package schema
+
+// SetUsageBased sets whether the cost components of the resource and its sub
+// resources are usage based from the same resource built without usage. Cost
+// components are matched by name. A cost component is usage based if it has
+// no quantity without usage, or is only built with usage, e.g. the higher
+// tiers of tiered prices. Cost components whose quantities have a default,
+// e.g. instance hours, aren't usage based even if the usage changes them.
+func (r *Resource) SetUsageBased(noUsage *Resource) {
+	var noUsageComponents map[string]*CostComponent
+	var noUsageSubResources map[string]*Resource
+	if noUsage != nil {
+		noUsageComponents = costComponentsByName(noUsage.CostComponents)
+		noUsageSubResources = resourcesByName(noUsage.SubResources)
+	}
+
+	for _, c := range r.CostComponents {
+		n := noUsageComponents[c.Name]
+		c.UsageBased = n == nil || (n.HourlyQuantity == nil && n.MonthlyQuantity == nil)
+	}
+
+	for _, s := range r.SubResources {
+		s.SetUsageBased(noUsageSubResources[s.Name])
+	}
+}
@@ -76,14 +76,16 @@ func (u *UsageData) Merge(other *UsageData) *UsageData {
 	return newU
 }
 
+// Get returns the usage value of the key. Usage ranges are resolved to their
+// expected value, see RangeBounds for their min and max values.
 func (u *UsageData) Get(key string) gjson.Result {
 	if u.Attributes[key].Type != gjson.Null {
-		return u.Attributes[key]
+		return resolveUsageRanges(u.Attributes[key], usageRangeExpected)
 	} else if strings.Contains(key, "[") && strings.Contains(key, "]") {
 		key = convertArrayKeyToWildcard(key)
 	}
 
-	return u.Attributes[key]
+	return resolveUsageRanges(u.Attributes[key], usageRangeExpected)
 }
 
 func (u *UsageData) GetFloat(key string) *float64 {
@@ -158,7 +160,8 @@ func convertArrayKeyToWildcard(key string) string {
 // it is using the defaults
 func (u *UsageData) CalcEstimationSummary() map[string]bool {
 	estimationMap := make(map[string]bool)
-	for k, v := range u.Attributes {
+	for k := range u.Attributes {
+		v := u.Get(k)
 		// figure out if the attribute has estimated value or if it is just using the defaults
 		hasEstimate := false
 		switch v.Type {
@@ -270,6 +273,34 @@ func (usage UsageMap) Get(address string) *UsageData {
 	return data
 }
 
+// WithResourceTypeDefault returns a copy of usage with key set to value in the
+// resource type usage of each of resourceTypes, unless the resource type usage
+// already sets key. Resource type usage has the lowest priority so the key can
+// still be overridden for each resource.
+func (usage UsageMap) WithResourceTypeDefault(resourceTypes []string, key string, value interface{}) UsageMap {
+	data := make(map[string]*UsageData, len(usage.data)+len(resourceTypes))
+	for k, v := range usage.data {
+		data[k] = v
+	}
+
+	attr := ParseAttributes(map[string]interface{}{key: value})[key]
+
+	for _, resourceType := range resourceTypes {
+		d := data[resourceType].Copy()
+		if d == nil {
+			d = NewUsageData(resourceType, map[string]gjson.Result{})
+		}
+
+		if _, ok := d.Attributes[key]; !ok {
+			d.Attributes[key] = attr
+		}
+
+		data[resourceType] = d
+	}
+
+	return UsageMap{data: data, wildcards: usage.wildcards}
+}
+
 var wildCardRegxp = regexp.MustCompile(`\[.*?]`)
 
 // wildcard contains information about a wildcard specified usage key.
@@ -213,3 +213,92 @@ func TestUsageMap_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestUsageMap_WithResourceTypeDefault(t *testing.T) {
+	usage := NewUsageMapFromInterface(map[string]interface{}{
+		"azurerm_linux_virtual_machine": map[string]interface{}{
+			"monthly_hrs": 100,
+		},
+		"azurerm_mssql_database": map[string]interface{}{
+			"purchase_option": "pay_as_you_go",
+		},
+		"azurerm_linux_virtual_machine.override": map[string]interface{}{
+			"purchase_option": "savings_plan_3_years",
+		},
+	})
+
+	got := usage.WithResourceTypeDefault([]string{"azurerm_linux_virtual_machine", "azurerm_mssql_database", "azurerm_sql_database"}, "purchase_option", "reserved_1_year")
+
+	assert.Equal(t, "reserved_1_year", got.Get("azurerm_linux_virtual_machine.vm").Attributes["purchase_option"].String())
+	assert.Equal(t, int64(100), got.Get("azurerm_linux_virtual_machine.vm").Attributes["monthly_hrs"].Int())
+	assert.Equal(t, "savings_plan_3_years", got.Get("azurerm_linux_virtual_machine.override").Attributes["purchase_option"].String())
+	assert.Equal(t, "pay_as_you_go", got.Get("azurerm_mssql_database.db").Attributes["purchase_option"].String())
+	assert.Equal(t, "reserved_1_year", got.Get("azurerm_sql_database.db").Attributes["purchase_option"].String())
+
+	assert.Nil(t, usage.Get("azurerm_sql_database.db"), "usage should not be modified")
+	assert.False(t, usage.Get("azurerm_linux_virtual_machine.vm").Attributes["purchase_option"].Exists(), "usage should not be modified")
+}
+
+func TestUsageDataRanges(t *testing.T) {
+	u := NewUsageData("aws_lambda_function.test", ParseAttributes(map[string]interface{}{
+		"monthly_requests":    map[string]interface{}{"min": 1000000, "max": 5000000},
+		"request_duration_ms": map[string]interface{}{"min": 100, "max": 500, "expected": 200},
+		"storage_gb":          10,
+		"standard": map[string]interface{}{
+			"storage_gb": map[string]interface{}{"min": 10, "max": 20},
+			"tier":       "hot",
+		},
+		// Other maps are the usage of sub resources, not ranges.
+		"not_a_range": map[string]interface{}{"min": 1, "count": 2},
+	}))
+
+	assert.True(t, u.HasRanges())
+	assert.Equal(t, int64(3000000), *u.GetInt("monthly_requests"))
+	assert.Equal(t, int64(200), *u.GetInt("request_duration_ms"))
+	assert.Equal(t, int64(10), *u.GetInt("storage_gb"))
+	assert.Equal(t, float64(15), u.Get("standard").Get("storage_gb").Float())
+	assert.Equal(t, "hot", u.Get("standard").Get("tier").String())
+	assert.Equal(t, int64(2), u.Get("not_a_range").Get("count").Int())
+
+	low, high := u.RangeBounds()
+	require.NotNil(t, low)
+	require.NotNil(t, high)
+	assert.Equal(t, int64(1000000), *low.GetInt("monthly_requests"))
+	assert.Equal(t, int64(5000000), *high.GetInt("monthly_requests"))
+	assert.Equal(t, int64(100), *low.GetInt("request_duration_ms"))
+	assert.Equal(t, int64(10), *high.GetInt("storage_gb"))
+	assert.Equal(t, float64(20), high.Get("standard").Get("storage_gb").Float())
+
+	noRanges := NewUsageData("aws_lambda_function.test", ParseAttributes(map[string]interface{}{"monthly_requests": 100}))
+	assert.False(t, noRanges.HasRanges())
+	low, high = noRanges.RangeBounds()
+	assert.Nil(t, low)
+	assert.Nil(t, high)
+}
+
+func TestUsageDataWithGrowth(t *testing.T) {
+	u := NewUsageData("aws_lambda_function.test", ParseAttributes(map[string]interface{}{
+		"monthly_requests": 100,
+		"storage_gb":       10,
+		"standard": map[string]interface{}{
+			"monthly_requests": map[string]interface{}{"min": 100, "max": 200},
+			"tier":             "hot",
+		},
+	}))
+
+	// Doubling each month, the average over 12 months is (2^12 - 1) / 12 times
+	// the first month.
+	grown := u.WithGrowth(map[string]float64{"monthly_requests": 100}, 12)
+	require.NotNil(t, grown)
+	assert.Equal(t, float64(34125), grown.Get("monthly_requests").Float())
+	assert.Equal(t, float64(10), grown.Get("storage_gb").Float())
+	assert.Equal(t, float64(34125), grown.Attributes["standard"].Get("monthly_requests.min").Float())
+	assert.Equal(t, float64(68250), grown.Attributes["standard"].Get("monthly_requests.max").Float())
+	assert.Equal(t, "hot", grown.Attributes["standard"].Get("tier").String())
+
+	// The usage data isn't changed.
+	assert.Equal(t, int64(100), *u.GetInt("monthly_requests"))
+
+	assert.Nil(t, u.WithGrowth(map[string]float64{"monthly_data_processed_gb": 5}, 12))
+	assert.Equal(t, float64(10), u.WithGrowth(map[string]float64{"storage_gb": 0}, 36).Get("storage_gb").Float())
+}
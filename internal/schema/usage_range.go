@@ -0,0 +1,209 @@
+package schema
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+// usageRangeBound is the value of a usage range that a usage value resolves
+// to, see resolveUsageRanges.
+type usageRangeBound int
+
+const (
+	usageRangeExpected usageRangeBound = iota
+	usageRangeMin
+	usageRangeMax
+)
+
+// isUsageRange returns whether the usage value is a range of the form
+// {min: 1000000, max: 5000000}, with an optional expected value. Any other
+// keys mean the value is the usage of a sub resource instead.
+func isUsageRange(v gjson.Result) bool {
+	if !v.IsObject() {
+		return false
+	}
+
+	isRange := true
+	v.ForEach(func(key, val gjson.Result) bool {
+		switch key.String() {
+		case "min", "max", "expected":
+			isRange = val.Type == gjson.Number
+		default:
+			isRange = false
+		}
+		return isRange
+	})
+
+	return isRange && v.Get("min").Exists() && v.Get("max").Exists()
+}
+
+// containsUsageRange returns whether the usage value is a range or is the
+// usage of a sub resource that contains a range.
+func containsUsageRange(v gjson.Result) bool {
+	if isUsageRange(v) {
+		return true
+	}
+
+	if !v.IsObject() {
+		return false
+	}
+
+	contains := false
+	v.ForEach(func(_, val gjson.Result) bool {
+		contains = containsUsageRange(val)
+		return !contains
+	})
+
+	return contains
+}
+
+// resolveUsageRanges returns the usage value with its ranges, including the
+// ranges in the usage of sub resources, replaced by the given bound. The
+// expected value of a range without one is halfway between its min and max.
+func resolveUsageRanges(v gjson.Result, bound usageRangeBound) gjson.Result {
+	if isUsageRange(v) {
+		switch bound {
+		case usageRangeMin:
+			return v.Get("min")
+		case usageRangeMax:
+			return v.Get("max")
+		}
+
+		if expected := v.Get("expected"); expected.Exists() {
+			return expected
+		}
+
+		midpoint := (v.Get("min").Float() + v.Get("max").Float()) / 2
+		return gjson.Parse(strconv.FormatFloat(midpoint, 'f', -1, 64))
+	}
+
+	if !containsUsageRange(v) {
+		return v
+	}
+
+	m := make(map[string]json.RawMessage)
+	v.ForEach(func(key, val gjson.Result) bool {
+		m[key.String()] = json.RawMessage(resolveUsageRanges(val, bound).Raw)
+		return true
+	})
+
+	j, _ := json.Marshal(m)
+	return gjson.ParseBytes(j)
+}
+
+// HasRanges returns whether any of the usage values is a range.
+func (u *UsageData) HasRanges() bool {
+	if u == nil {
+		return false
+	}
+
+	for _, v := range u.Attributes {
+		if containsUsageRange(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RangeBounds returns copies of the usage data with the usage ranges
+// replaced by their min and max values respectively. It returns nil if the
+// usage data has no ranges.
+func (u *UsageData) RangeBounds() (*UsageData, *UsageData) {
+	if !u.HasRanges() {
+		return nil, nil
+	}
+
+	low := u.Copy()
+	high := u.Copy()
+
+	for k, v := range u.Attributes {
+		low.Attributes[k] = resolveUsageRanges(v, usageRangeMin)
+		high.Attributes[k] = resolveUsageRanges(v, usageRangeMax)
+	}
+
+	return low, high
+}
+
+// buildCoreResourceWithUsage builds a copy of the CoreResource with the given
+// usage, so that building it doesn't affect the CoreResource.
+func buildCoreResourceWithUsage(coreResource CoreResource, u *UsageData) *Resource {
+	c := copyCoreResource(coreResource)
+	if c == nil {
+		return nil
+	}
+
+	c.PopulateUsage(u)
+	return c.BuildResource()
+}
+
+// SetQuantityRanges sets the low and high monthly quantities of the cost
+// components of the resource and its sub resources from the same resource
+// built with the min and max values of its usage ranges. Cost components are
+// matched by name, so the cost components that are only built for one of the
+// bounds, e.g. the higher tiers of tiered prices, aren't included in the
+// ranges.
+func (r *Resource) SetQuantityRanges(low *Resource, high *Resource) {
+	if low == nil || high == nil {
+		return
+	}
+
+	lowComponents := costComponentsByName(low.CostComponents)
+	highComponents := costComponentsByName(high.CostComponents)
+
+	for _, c := range r.CostComponents {
+		l, h := lowComponents[c.Name], highComponents[c.Name]
+		if l == nil || h == nil {
+			continue
+		}
+
+		c.fillQuantities()
+		l.fillQuantities()
+		h.fillQuantities()
+
+		if c.MonthlyQuantity == nil || l.MonthlyQuantity == nil || h.MonthlyQuantity == nil {
+			continue
+		}
+
+		if c.MonthlyQuantity.Equal(*l.MonthlyQuantity) && c.MonthlyQuantity.Equal(*h.MonthlyQuantity) {
+			continue
+		}
+
+		minQty := decimal.Min(*c.MonthlyQuantity, *l.MonthlyQuantity, *h.MonthlyQuantity)
+		maxQty := decimal.Max(*c.MonthlyQuantity, *l.MonthlyQuantity, *h.MonthlyQuantity)
+		c.LowMonthlyQuantity = &minQty
+		c.HighMonthlyQuantity = &maxQty
+	}
+
+	lowSubResources := resourcesByName(low.SubResources)
+	highSubResources := resourcesByName(high.SubResources)
+
+	for _, s := range r.SubResources {
+		s.SetQuantityRanges(lowSubResources[s.Name], highSubResources[s.Name])
+	}
+}
+
+func costComponentsByName(costComponents []*CostComponent) map[string]*CostComponent {
+	m := make(map[string]*CostComponent, len(costComponents))
+	for _, c := range costComponents {
+		if _, ok := m[c.Name]; !ok {
+			m[c.Name] = c
+		}
+	}
+
+	return m
+}
+
+func resourcesByName(resources []*Resource) map[string]*Resource {
+	m := make(map[string]*Resource, len(resources))
+	for _, r := range resources {
+		if _, ok := m[r.Name]; !ok {
+			m[r.Name] = r
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,209 @@
+package tracing
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	runSpanName     = "run"
+	projectSpanName = "project"
+)
+
+// reportPhases are the spans that the timing report breaks a run down by, in
+// the order they run.
+var reportPhases = []struct {
+	name  string
+	label string
+}{
+	{"parse", "Parse"},
+	{"usage", "Usage merge"},
+	{"build_resources", "Build"},
+	{"price_lookup", "Pricing"},
+	{"output", "Output"},
+}
+
+// Report records how long the phases of a run take and how much memory they
+// allocate, in total and for each project, so users can share where a run
+// spends its time without profiling it.
+type Report struct {
+	start     time.Time
+	total     reportTiming
+	phases    map[string]*reportTiming
+	projects  []string
+	byProject map[string]*projectTiming
+}
+
+type reportTiming struct {
+	duration time.Duration
+	alloc    uint64
+}
+
+type projectTiming struct {
+	reportTiming
+	phases map[string]time.Duration
+}
+
+// EnableReport starts recording the spans of the run for the timing report,
+// which is written with WriteReport.
+func EnableReport() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	report = &Report{
+		start:     time.Now(),
+		phases:    make(map[string]*reportTiming),
+		byProject: make(map[string]*projectTiming),
+	}
+}
+
+func reportEnabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return report != nil
+}
+
+// spanProject returns the path of the project that span s is part of.
+func spanProject(s *Span, parent *Span) string {
+	if s.name == projectSpanName {
+		for _, a := range s.attributes {
+			if a.Key == "path" {
+				return fmt.Sprintf("%v", a.Value)
+			}
+		}
+	}
+
+	if parent != nil {
+		return parent.project
+	}
+
+	return ""
+}
+
+func totalAlloc() uint64 {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	return m.TotalAlloc
+}
+
+func (r *Report) add(s *Span, end time.Time, alloc uint64) {
+	d := end.Sub(s.start)
+
+	switch s.name {
+	case runSpanName:
+		r.total = reportTiming{duration: d, alloc: alloc}
+	case projectSpanName:
+		p := r.project(s.project)
+		p.duration += d
+		p.alloc += alloc
+	default:
+		t, ok := r.phases[s.name]
+		if !ok {
+			t = &reportTiming{}
+			r.phases[s.name] = t
+		}
+
+		t.duration += d
+		t.alloc += alloc
+
+		if s.project != "" {
+			r.project(s.project).phases[s.name] += d
+		}
+	}
+}
+
+func (r *Report) project(path string) *projectTiming {
+	p, ok := r.byProject[path]
+	if !ok {
+		p = &projectTiming{phases: make(map[string]time.Duration)}
+		r.byProject[path] = p
+		r.projects = append(r.projects, path)
+	}
+
+	return p
+}
+
+// WriteReport writes the timing report of the run to w and stops recording
+// it. It does nothing if the report isn't enabled.
+func WriteReport(w io.Writer) error {
+	mu.Lock()
+	r := report
+	report = nil
+	mu.Unlock()
+
+	if r == nil {
+		return nil
+	}
+
+	if r.total.duration == 0 {
+		r.total = reportTiming{duration: time.Since(r.start), alloc: totalAlloc()}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "Timing report")
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "PHASE\tTIME\tALLOCATED")
+
+	for _, phase := range reportPhases {
+		t, ok := r.phases[phase.name]
+		if !ok {
+			continue
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", phase.label, formatDuration(t.duration), humanize.IBytes(t.alloc))
+	}
+
+	fmt.Fprintf(tw, "Total\t%s\t%s\n", formatDuration(r.total.duration), humanize.IBytes(r.total.alloc))
+
+	if len(r.projects) > 0 {
+		header := []string{"PROJECT"}
+		for _, phase := range reportPhases {
+			header = append(header, strings.ToUpper(phase.label))
+		}
+		header = append(header, "TOTAL", "ALLOCATED")
+
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+		for _, path := range r.projects {
+			p := r.byProject[path]
+
+			row := []string{path}
+			for _, phase := range reportPhases {
+				d, ok := p.phases[phase.name]
+				if !ok {
+					row = append(row, "-")
+					continue
+				}
+
+				row = append(row, formatDuration(d))
+			}
+			row = append(row, formatDuration(p.duration), humanize.IBytes(p.alloc))
+
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+	}
+
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	_, err = fmt.Fprintf(w, "\nMemory obtained from the OS: %s, garbage collections: %d.\nPhase times and allocations are summed over projects, which overlap if they run in parallel.\n", humanize.IBytes(m.Sys), m.NumGC)
+	return err
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteReport(t *testing.T) {
+	EnableReport()
+
+	ctx, run := Start(context.Background(), "run")
+	require.NotNil(t, run)
+
+	for _, path := range []string{"infra/dev", "infra/prod"} {
+		projectCtx, project := Start(ctx, "project", String("path", path))
+		for _, phase := range []string{"usage", "parse", "build_resources", "price_lookup"} {
+			_, span := Start(projectCtx, phase)
+			span.End()
+		}
+		project.End()
+	}
+
+	_, output := Start(ctx, "output")
+	output.End()
+	run.End()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf))
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Equal(t, "Timing report", lines[0])
+	assert.Regexp(t, `^PHASE\s+TIME\s+ALLOCATED$`, lines[2])
+	assert.Regexp(t, `^Parse\s+\S+\s+\S+ [KMG]?i?B$`, lines[3])
+	assert.Regexp(t, `^Usage merge\s`, lines[4])
+	assert.Regexp(t, `^Build\s`, lines[5])
+	assert.Regexp(t, `^Pricing\s`, lines[6])
+	assert.Regexp(t, `^Output\s`, lines[7])
+	assert.Regexp(t, `^Total\s`, lines[8])
+	assert.Regexp(t, `^PROJECT\s+PARSE\s+USAGE MERGE\s+BUILD\s+PRICING\s+OUTPUT\s+TOTAL\s+ALLOCATED$`, lines[10])
+	assert.Regexp(t, `^infra/dev\s+(\S+\s+){4}-\s+\S+\s+\S+ [KMG]?i?B$`, lines[11])
+	assert.Regexp(t, `^infra/prod\s`, lines[12])
+	assert.Contains(t, buf.String(), "Memory obtained from the OS: ")
+
+	// The report stops recording once it's written.
+	assert.False(t, Enabled())
+
+	buf.Reset()
+	require.NoError(t, WriteReport(&buf))
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,381 @@
+// Package tracing records spans of the stages of a run and exports them to an
+// OpenTelemetry collector with the OTLP/HTTP JSON protocol. It is configured
+// with the standard OpenTelemetry environment variables and does nothing if
+// no OTLP endpoint is set, so it has no cost for runs that aren't traced.
+//
+// Only the subset of the OpenTelemetry API that Infracost needs is
+// implemented, so that tracing doesn't add the OpenTelemetry SDK to the
+// dependencies of the CLI.
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultServiceName = "infracost"
+	tracesPath         = "/v1/traces"
+	exportTimeout      = 10 * time.Second
+
+	spanKindInternal = 1
+	statusCodeError  = 2
+)
+
+var (
+	mu       sync.Mutex
+	exporter *otlpExporter
+	report   *Report
+)
+
+type spanContextKey struct{}
+
+// Attribute is a key value pair that is added to a span.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is a stage of a run. A nil Span is valid and does nothing, which is
+// what Start returns when tracing isn't enabled.
+type Span struct {
+	name       string
+	traceID    string
+	spanID     string
+	parentID   string
+	start      time.Time
+	attributes []Attribute
+	err        error
+	once       sync.Once
+
+	// project is the path of the project span that the span is part of, and
+	// startAlloc the bytes allocated when it started. They're only set if the
+	// timing report is enabled.
+	project    string
+	startAlloc uint64
+}
+
+// Init enables tracing if an OTLP endpoint is set in the environment. The
+// endpoint is read from OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or, with /v1/traces
+// appended, from OTEL_EXPORTER_OTLP_ENDPOINT. Headers such as API keys are
+// read from OTEL_EXPORTER_OTLP_TRACES_HEADERS or OTEL_EXPORTER_OTLP_HEADERS,
+// and the service name from OTEL_SERVICE_NAME. Tracing is disabled if
+// OTEL_SDK_DISABLED is true or OTEL_TRACES_EXPORTER is none.
+func Init(serviceVersion string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	exporter = newExporterFromEnv(os.Getenv, serviceVersion)
+}
+
+func newExporterFromEnv(getenv func(string) string, serviceVersion string) *otlpExporter {
+	if disabled, _ := strconv.ParseBool(getenv("OTEL_SDK_DISABLED")); disabled {
+		return nil
+	}
+
+	if strings.EqualFold(getenv("OTEL_TRACES_EXPORTER"), "none") {
+		return nil
+	}
+
+	endpoint := getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint == "" {
+		base := getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+		if base == "" {
+			return nil
+		}
+
+		endpoint = strings.TrimSuffix(base, "/") + tracesPath
+	}
+
+	headers := getenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS")
+	if headers == "" {
+		headers = getenv("OTEL_EXPORTER_OTLP_HEADERS")
+	}
+
+	serviceName := getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	return &otlpExporter{
+		endpoint:       endpoint,
+		headers:        parseHeaders(headers),
+		serviceName:    serviceName,
+		serviceVersion: serviceVersion,
+		client:         &http.Client{Timeout: exportTimeout},
+	}
+}
+
+// parseHeaders parses headers in the format of the OpenTelemetry environment
+// variables, e.g. api-key=abc,x-team=platform. Values are URL encoded.
+func parseHeaders(s string) map[string]string {
+	headers := make(map[string]string)
+
+	for _, pair := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			continue
+		}
+
+		if unescaped, err := url.QueryUnescape(strings.TrimSpace(v)); err == nil {
+			v = unescaped
+		}
+
+		headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+
+	return headers
+}
+
+// Enabled returns true if spans are being recorded.
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return exporter != nil || report != nil
+}
+
+// Start starts a span that is a child of the span in ctx, or a new trace if
+// ctx has no span. The span must be ended with End.
+func Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, *Span) {
+	if !Enabled() {
+		return ctx, nil
+	}
+
+	s := &Span{
+		name:       name,
+		spanID:     randomHex(8),
+		start:      time.Now(),
+		attributes: attributes,
+	}
+
+	parent, _ := ctx.Value(spanContextKey{}).(*Span)
+	if parent != nil {
+		s.traceID = parent.traceID
+		s.parentID = parent.spanID
+	} else {
+		s.traceID = randomHex(16)
+	}
+
+	if reportEnabled() {
+		s.project = spanProject(s, parent)
+		s.startAlloc = totalAlloc()
+	}
+
+	return context.WithValue(ctx, spanContextKey{}, s), s
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+
+	s.attributes = append(s.attributes, attributes...)
+}
+
+// RecordError marks the span as failed with the error.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.err = err
+}
+
+// End ends the span so it's exported by Shutdown. Only the first call has an
+// effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.once.Do(func() {
+		end := time.Now()
+
+		var alloc uint64
+		if reportEnabled() {
+			alloc = totalAlloc() - s.startAlloc
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if exporter != nil {
+			exporter.add(s, end)
+		}
+
+		if report != nil {
+			report.add(s, end, alloc)
+		}
+	})
+}
+
+// Shutdown exports the ended spans. Spans are exported once at the end of the
+// run, as runs are short lived. Errors are returned so they can be logged,
+// they shouldn't fail the run.
+func Shutdown(ctx context.Context) error {
+	mu.Lock()
+	e := exporter
+	exporter = nil
+	mu.Unlock()
+
+	if e == nil {
+		return nil
+	}
+
+	return e.export(ctx)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+type otlpExporter struct {
+	endpoint       string
+	headers        map[string]string
+	serviceName    string
+	serviceVersion string
+	client         *http.Client
+	spans          []otlpSpan
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            *otlpStatus     `json:"status,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func (e *otlpExporter) add(s *Span, end time.Time) {
+	span := otlpSpan{
+		TraceID:           s.traceID,
+		SpanID:            s.spanID,
+		ParentSpanID:      s.parentID,
+		Name:              s.name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+		Attributes:        otlpAttributes(s.attributes),
+	}
+
+	if s.err != nil {
+		span.Status = &otlpStatus{Code: statusCodeError, Message: s.err.Error()}
+	}
+
+	e.spans = append(e.spans, span)
+}
+
+func otlpAttributes(attributes []Attribute) []otlpAttribute {
+	out := make([]otlpAttribute, 0, len(attributes))
+
+	for _, a := range attributes {
+		var v map[string]interface{}
+		switch t := a.Value.(type) {
+		case int:
+			// OTLP JSON encodes 64 bit integers as strings.
+			v = map[string]interface{}{"intValue": strconv.Itoa(t)}
+		default:
+			v = map[string]interface{}{"stringValue": fmt.Sprintf("%v", t)}
+		}
+
+		out = append(out, otlpAttribute{Key: a.Key, Value: v})
+	}
+
+	return out
+}
+
+func (e *otlpExporter) payload() ([]byte, error) {
+	resource := map[string]interface{}{
+		"attributes": otlpAttributes([]Attribute{
+			String("service.name", e.serviceName),
+			String("service.version", e.serviceVersion),
+		}),
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": resource,
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{"name": "github.com/infracost/infracost", "version": e.serviceVersion},
+						"spans": e.spans,
+					},
+				},
+			},
+		},
+	})
+}
+
+func (e *otlpExporter) export(ctx context.Context) error {
+	if len(e.spans) == 0 {
+		return nil
+	}
+
+	body, err := e.payload()
+	if err != nil {
+		return fmt.Errorf("error encoding spans: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating OTLP request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error exporting spans to %s: %w", e.endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("error exporting spans to %s: %s", e.endpoint, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewExporterFromEnv(t *testing.T) {
+	env := func(vals map[string]string) func(string) string {
+		return func(k string) string { return vals[k] }
+	}
+
+	assert.Nil(t, newExporterFromEnv(env(nil), "v1"))
+	assert.Nil(t, newExporterFromEnv(env(map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318", "OTEL_SDK_DISABLED": "true"}), "v1"))
+	assert.Nil(t, newExporterFromEnv(env(map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318", "OTEL_TRACES_EXPORTER": "none"}), "v1"))
+
+	e := newExporterFromEnv(env(map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318/",
+		"OTEL_EXPORTER_OTLP_HEADERS":  "api-key=abc%3D, x-team = platform",
+	}), "v1")
+	require.NotNil(t, e)
+	assert.Equal(t, "http://localhost:4318/v1/traces", e.endpoint)
+	assert.Equal(t, map[string]string{"api-key": "abc=", "x-team": "platform"}, e.headers)
+	assert.Equal(t, "infracost", e.serviceName)
+
+	e = newExporterFromEnv(env(map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT":        "http://localhost:4318",
+		"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://collector/traces",
+		"OTEL_SERVICE_NAME":                  "ci-infracost",
+	}), "v1")
+	require.NotNil(t, e)
+	assert.Equal(t, "http://collector/traces", e.endpoint)
+	assert.Equal(t, "ci-infracost", e.serviceName)
+}
+
+func TestStartNotEnabled(t *testing.T) {
+	ctx, span := Start(context.Background(), "run")
+	assert.Nil(t, span)
+	assert.Nil(t, ctx.Value(spanContextKey{}))
+
+	// A nil span does nothing.
+	span.SetAttributes(String("a", "b"))
+	span.RecordError(errors.New("failed"))
+	span.End()
+	assert.NoError(t, Shutdown(context.Background()))
+}
+
+func TestShutdownExportsSpans(t *testing.T) {
+	var body []byte
+	var apiKey string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		apiKey = r.Header.Get("api-key")
+	}))
+	defer ts.Close()
+
+	mu.Lock()
+	exporter = newExporterFromEnv(func(k string) string {
+		return map[string]string{
+			"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": ts.URL,
+			"OTEL_EXPORTER_OTLP_HEADERS":         "api-key=secret",
+		}[k]
+	}, "v1.2.3")
+	mu.Unlock()
+
+	ctx, run := Start(context.Background(), "run", String("command", "breakdown"))
+	_, parse := Start(ctx, "parse", Int("resources", 3))
+	parse.RecordError(errors.New("invalid HCL"))
+	parse.End()
+	run.End()
+
+	require.NoError(t, Shutdown(context.Background()))
+	assert.False(t, Enabled())
+	assert.Equal(t, "secret", apiKey)
+	require.True(t, json.Valid(body))
+
+	res := gjson.ParseBytes(body).Get("resourceSpans.0")
+	assert.Equal(t, "infracost", res.Get(`resource.attributes.#(key=="service.name").value.stringValue`).String())
+	assert.Equal(t, "v1.2.3", res.Get(`resource.attributes.#(key=="service.version").value.stringValue`).String())
+
+	spans := res.Get("scopeSpans.0.spans").Array()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "parse", spans[0].Get("name").String())
+	assert.Equal(t, "3", spans[0].Get(`attributes.#(key=="resources").value.intValue`).String())
+	assert.Equal(t, int64(2), spans[0].Get("status.code").Int())
+	assert.Equal(t, "invalid HCL", spans[0].Get("status.message").String())
+
+	assert.Equal(t, "run", spans[1].Get("name").String())
+	assert.Equal(t, "breakdown", spans[1].Get(`attributes.#(key=="command").value.stringValue`).String())
+	assert.False(t, spans[1].Get("status").Exists())
+
+	assert.Equal(t, spans[1].Get("traceId").String(), spans[0].Get("traceId").String())
+	assert.Equal(t, spans[1].Get("spanId").String(), spans[0].Get("parentSpanId").String())
+	assert.Len(t, spans[1].Get("traceId").String(), 32)
+	assert.Len(t, spans[1].Get("spanId").String(), 16)
+}
@@ -3,6 +3,7 @@ package aws
 import (
 	"context"
 
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	log "github.com/sirupsen/logrus"
@@ -61,3 +62,38 @@ func EC2DescribeOS(ctx context.Context, region string, ami string) (string, erro
 		return "linux", nil
 	}
 }
+
+// EC2GetCPUUtilization returns the average CPU utilization percentage of the
+// instance over the last month.
+func EC2GetCPUUtilization(ctx context.Context, region string, instanceID string) (float64, error) {
+	log.Debugf("Querying AWS CloudWatch: AWS/EC2 CPUUtilization (region: %s, InstanceId: %s)", region, instanceID)
+	return ec2GetAverageUtilization(ctx, region, "AWS/EC2", "CPUUtilization", instanceID)
+}
+
+// EC2GetMemoryUtilization returns the average memory utilization percentage of
+// the instance over the last month. The memory utilization is only reported by
+// the CloudWatch agent, it's 0 if the agent doesn't publish the metric with
+// the InstanceId dimension.
+func EC2GetMemoryUtilization(ctx context.Context, region string, instanceID string) (float64, error) {
+	log.Debugf("Querying AWS CloudWatch: CWAgent mem_used_percent (region: %s, InstanceId: %s)", region, instanceID)
+	return ec2GetAverageUtilization(ctx, region, "CWAgent", "mem_used_percent", instanceID)
+}
+
+func ec2GetAverageUtilization(ctx context.Context, region string, namespace string, metric string, instanceID string) (float64, error) {
+	stats, err := cloudwatchGetMonthlyStats(ctx, statsRequest{
+		region:    region,
+		namespace: namespace,
+		metric:    metric,
+		statistic: cwtypes.StatisticAverage,
+		unit:      cwtypes.StandardUnitPercent,
+		dimensions: map[string]string{
+			"InstanceId": instanceID,
+		},
+	})
+	if err != nil {
+		return 0, err
+	} else if len(stats.Datapoints) == 0 {
+		return 0, nil
+	}
+	return *stats.Datapoints[0].Average, nil
+}
@@ -0,0 +1,30 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// RDSGetCPUUtilization returns the average CPU utilization percentage of the
+// DB instance over the last month.
+func RDSGetCPUUtilization(ctx context.Context, region string, identifier string) (float64, error) {
+	log.Debugf("Querying AWS CloudWatch: AWS/RDS CPUUtilization (region: %s, DBInstanceIdentifier: %s)", region, identifier)
+	stats, err := cloudwatchGetMonthlyStats(ctx, statsRequest{
+		region:    region,
+		namespace: "AWS/RDS",
+		metric:    "CPUUtilization",
+		statistic: types.StatisticAverage,
+		unit:      types.StandardUnitPercent,
+		dimensions: map[string]string{
+			"DBInstanceIdentifier": identifier,
+		},
+	})
+	if err != nil {
+		return 0, err
+	} else if len(stats.Datapoints) == 0 {
+		return 0, nil
+	}
+	return *stats.Datapoints[0].Average, nil
+}
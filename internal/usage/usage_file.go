@@ -181,6 +181,34 @@ See https://infracost.io/usage-file/ for docs`,
 	return os.WriteFile(path, b, 0600)
 }
 
+// MergeWildcardUsages merges the usage of wildcard resources, e.g.
+// aws_instance.web[*], into the usage of each of their instances that is set,
+// e.g. aws_instance.web[0].
+func (u *UsageFile) MergeWildcardUsages() {
+	wildCardUsage := make(map[string]*ResourceUsage)
+	for _, us := range u.ResourceUsages {
+		if strings.HasSuffix(us.Name, "[*]") {
+			lastIndexOfOpenBracket := strings.LastIndex(us.Name, "[")
+			prefixName := us.Name[:lastIndexOfOpenBracket]
+			wildCardUsage[prefixName] = us
+		}
+	}
+
+	for _, us := range u.ResourceUsages {
+		if strings.HasSuffix(us.Name, "[*]") {
+			continue
+		}
+
+		if !strings.HasSuffix(us.Name, "]") {
+			continue
+		}
+		lastIndexOfOpenBracket := strings.LastIndex(us.Name, "[")
+		prefixName := us.Name[:lastIndexOfOpenBracket]
+
+		us.MergeResourceUsage(wildCardUsage[prefixName])
+	}
+}
+
 func (u *UsageFile) ToUsageDataMap() schema.UsageMap {
 	m := make(map[string]interface{})
 
@@ -271,14 +299,39 @@ func findInvalidKeys(item *schema.UsageItem, refMap map[string]interface{}) []st
 	if refVal, ok := refMap[item.Key]; !ok {
 		invalidKeys = append(invalidKeys, item.Key)
 	} else if item.ValueType == schema.SubResourceUsage && item.Value != nil {
+		refSubMap, ok := refVal.(map[string]interface{})
+		if !ok {
+			// Keys that aren't sub resources can only have a map value if it's
+			// a range, e.g. {min: 1000000, max: 5000000}.
+			if !isUsageRangeItem(item) {
+				invalidKeys = append(invalidKeys, item.Key)
+			}
+
+			return invalidKeys
+		}
+
 		for _, subItem := range item.Value.(*ResourceUsage).Items {
-			invalidKeys = append(invalidKeys, findInvalidKeys(subItem, refVal.(map[string]interface{}))...)
+			invalidKeys = append(invalidKeys, findInvalidKeys(subItem, refSubMap)...)
 		}
 	}
 
 	return invalidKeys
 }
 
+// isUsageRangeItem returns whether the usage item is a range, which only has
+// min, max and expected values.
+func isUsageRangeItem(item *schema.UsageItem) bool {
+	for _, subItem := range item.Value.(*ResourceUsage).Items {
+		switch subItem.Key {
+		case "min", "max", "expected":
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
 func (u *UsageFile) parseResourceUsages() error {
 	var err error
 	u.ResourceUsages, err = ResourceUsagesFromYAML(u.RawResourceUsage)
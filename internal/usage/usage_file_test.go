@@ -107,3 +107,22 @@ resource_usage:
 	}
 
 }
+
+func TestUsageFileInvalidKeysRanges(t *testing.T) {
+	usageFile, err := usage.LoadUsageFileFromString(`
+version: 0.1
+resource_usage:
+  aws_lambda_function.my_function:
+    monthly_requests:
+      min: 1000000
+      max: 5000000
+    request_duration_ms:
+      min: 100
+      average: 200
+`)
+	assert.NoError(t, err)
+
+	invalidKeys, err := usageFile.InvalidKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"request_duration_ms"}, invalidKeys)
+}
@@ -533,6 +533,22 @@ func (f *metadataFetcher) getLocalGitMetadata(path string, gitDiffTarget *string
 	}, nil
 }
 
+// ResolveCommit returns the SHA of the commit that revision refers to in the git
+// repository at path, e.g. HEAD~1, a branch name or a short SHA.
+func ResolveCommit(path string, revision string) (string, error) {
+	r, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return "", fmt.Errorf("could not open git directory %w", err)
+	}
+
+	h, err := r.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return "", fmt.Errorf("could not resolve revision %s %w", revision, err)
+	}
+
+	return h.String(), nil
+}
+
 func (f *metadataFetcher) getFileChanges(path string, r *git.Repository, currentCommit *object.Commit, gitDiffTarget *string) []string {
 	changedMap := make(map[string]struct{})
 	tree, err := currentCommit.Tree()
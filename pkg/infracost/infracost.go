@@ -0,0 +1,550 @@
+// Package infracost estimates the costs of infrastructure as code in-process,
+// so tools can embed Infracost instead of running the CLI and parsing its
+// JSON output.
+//
+// The exported API of this package follows semantic versioning: it only
+// changes in backwards compatible ways within a major version of Infracost.
+// None of the internal packages that it's built on have this guarantee, so
+// the types of this package don't expose them.
+//
+//	projects, err := infracost.LoadProjects("infracost.yml")
+//	if err != nil {
+//		return err
+//	}
+//
+//	estimate, err := infracost.Breakdown(ctx, projects, infracost.Options{APIKey: apiKey})
+//	if err != nil {
+//		return err
+//	}
+//
+//	fmt.Println(estimate.TotalMonthlyCost)
+package infracost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/plugins"
+	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/providers"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// Options configure how the costs are estimated. Options that aren't set use
+// the same environment variables and credentials file as the CLI, e.g.
+// INFRACOST_API_KEY.
+type Options struct {
+	// APIKey is the Infracost API key that prices are looked up with.
+	APIKey string
+	// PricingAPIEndpoint is the endpoint of a self-hosted Cloud Pricing API.
+	PricingAPIEndpoint string
+	// Currency is the ISO 4217 currency code of the costs, e.g. EUR.
+	Currency string
+	// ExchangeRatesSource is where the rates that convert USD prices to the
+	// currency come from, either ecb or the path to an exchange rates file.
+	// If it's empty the pricing API converts the prices.
+	ExchangeRatesSource string
+	// IncludeFreeTier deducts the always-free allowances of the cloud
+	// providers from the costs.
+	IncludeFreeTier bool
+	// Parallelism is the number of resources and price lookups that are
+	// processed at the same time.
+	Parallelism int
+	// PriceOverridesFile is the path to a file of fixed unit prices that
+	// replace the prices of matching cost components.
+	PriceOverridesFile string
+	// Plugins are the paths to the plugin executables that build the costs of
+	// resource types that aren't supported.
+	Plugins []string
+	// ResourceMappings is the directory or git URL of the YAML files that map
+	// resource types that aren't supported to cost components.
+	ResourceMappings string
+}
+
+// Project is a path of infrastructure as code to estimate the costs of.
+type Project struct {
+	// Path is the Terraform directory or plan JSON file, or the
+	// CloudFormation, Pulumi or ARM template, of the project.
+	Path string
+	// Name is the name of the project in the output. Defaults to Path.
+	Name string
+	// UsageFile is the path to the Infracost usage file of the project.
+	UsageFile string
+	// ExcludePaths are the directories of Path that aren't evaluated.
+	ExcludePaths []string
+	// TerraformVarFiles are the paths to the variable files of the project,
+	// relative to Path.
+	TerraformVarFiles []string
+	// TerraformVars are the input variables of the project.
+	TerraformVars map[string]string
+	// TerraformWorkspace is the Terraform workspace of the project.
+	TerraformWorkspace string
+	// UsageFileBefore is the path to the usage file of the prior state of the
+	// project, which Diff estimates the past costs with.
+	UsageFileBefore string
+	// Env are the environment variables that the project is evaluated with.
+	Env map[string]string
+	// DependsOn are the names or paths of the projects that are estimated
+	// before this project.
+	DependsOn []string
+	// TerraformOutputVars sets input vars of the project to the outputs of
+	// other projects, given as <project name or path>.<output name>.
+	TerraformOutputVars map[string]string
+
+	// configFile is the path to the config file that the project was loaded
+	// from, whose settings that apply to all its projects, e.g. discounts, are
+	// used to estimate the project.
+	configFile string
+	// projectConfig is the project in the config file, with the settings that
+	// aren't fields of Project, e.g. its growth.
+	projectConfig *config.Project
+}
+
+// Estimate is the estimated costs of the projects.
+type Estimate struct {
+	Currency             string
+	TotalHourlyCost      *decimal.Decimal
+	TotalMonthlyCost     *decimal.Decimal
+	PastTotalMonthlyCost *decimal.Decimal
+	DiffTotalMonthlyCost *decimal.Decimal
+	Projects             []ProjectEstimate
+
+	root output.Root
+}
+
+// ProjectEstimate is the estimated costs of a project. PastResources is
+// only set by Diff.
+type ProjectEstimate struct {
+	Name                 string
+	Path                 string
+	TotalMonthlyCost     *decimal.Decimal
+	PastTotalMonthlyCost *decimal.Decimal
+	DiffTotalMonthlyCost *decimal.Decimal
+	Resources            []Resource
+	PastResources        []Resource
+}
+
+// Resource is the estimated cost of a resource.
+type Resource struct {
+	Name           string
+	ResourceType   string
+	Tags           map[string]string
+	HourlyCost     *decimal.Decimal
+	MonthlyCost    *decimal.Decimal
+	CostComponents []CostComponent
+	SubResources   []Resource
+}
+
+// CostComponent is a priced part of a resource, e.g. the instance hours of
+// a VM.
+type CostComponent struct {
+	Name            string
+	Unit            string
+	Price           decimal.Decimal
+	HourlyQuantity  *decimal.Decimal
+	MonthlyQuantity *decimal.Decimal
+	HourlyCost      *decimal.Decimal
+	MonthlyCost     *decimal.Decimal
+}
+
+// OutputOptions configure how Output writes an estimate.
+type OutputOptions struct {
+	// NoColor writes the table and diff formats without colors.
+	NoColor bool
+	// ShowSkipped lists the unsupported and free resources.
+	ShowSkipped bool
+	// Fields are the columns of the table format, see the --fields flag of
+	// the breakdown command. Defaults to monthlyQuantity, unit and
+	// monthlyCost.
+	Fields []string
+}
+
+// LoadProjects returns the projects of the Infracost config file at path,
+// including the projects that its autodetect section finds. Like with the
+// CLI, the paths of the projects are relative to the working directory. The
+// projects are estimated with all the settings of the config file, e.g. its
+// discounts, the same as the CLI estimates them.
+func LoadProjects(path string) ([]Project, error) {
+	cfg := config.DefaultConfig()
+	if err := cfg.LoadFromConfigFile(path); err != nil {
+		return nil, err
+	}
+
+	if err := providers.DetectConfigProjects(cfg); err != nil {
+		return nil, err
+	}
+
+	projects := make([]Project, 0, len(cfg.Projects))
+	for _, p := range cfg.Projects {
+		projects = append(projects, Project{
+			Path:                p.Path,
+			Name:                p.Name,
+			UsageFile:           p.UsageFile,
+			ExcludePaths:        p.ExcludePaths,
+			TerraformVarFiles:   p.TerraformVarFiles,
+			TerraformVars:       p.TerraformVars,
+			TerraformWorkspace:  p.TerraformWorkspace,
+			UsageFileBefore:     p.UsageFileBefore,
+			Env:                 p.Env,
+			DependsOn:           p.DependsOn,
+			TerraformOutputVars: p.TerraformOutputVars,
+			configFile:          path,
+			projectConfig:       p,
+		})
+	}
+
+	return projects, nil
+}
+
+// Breakdown estimates the costs of the projects.
+func Breakdown(ctx context.Context, projects []Project, opts Options) (*Estimate, error) {
+	return estimate(ctx, projects, opts, nil)
+}
+
+// Diff estimates the costs of the projects and how they change. If prior is
+// nil the changes are from the prior state of Terraform plan JSON files,
+// otherwise they're from the prior estimate, e.g. of the base branch.
+func Diff(ctx context.Context, projects []Project, prior *Estimate, opts Options) (*Estimate, error) {
+	return estimate(ctx, projects, opts, prior)
+}
+
+// Output writes the estimate to w in the format, which is one of the formats
+// of the --format flag of the output command, e.g. table, diff or json.
+func Output(w io.Writer, e *Estimate, format string, opts OutputOptions) error {
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = config.DefaultConfig().Fields
+	}
+
+	b, err := output.FormatOutput(format, e.root, output.Options{
+		NoColor:     opts.NoColor,
+		ShowSkipped: opts.ShowSkipped,
+		Fields:      fields,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+// estimate estimates the costs of the projects the same way as the CLI: the
+// projects are loaded and priced with the shared code of the runs of the CLI,
+// so they get the same plugins, usage, price overrides, free tier and
+// discounts.
+func estimate(ctx context.Context, projects []Project, opts Options, prior *Estimate) (*Estimate, error) {
+	if len(projects) == 0 {
+		return nil, errors.New("no projects to estimate")
+	}
+
+	projectCfgs := make([]*config.Project, 0, len(projects))
+	for _, p := range projects {
+		projectCfg, err := p.config()
+		if err != nil {
+			return nil, err
+		}
+
+		projectCfgs = append(projectCfgs, projectCfg)
+	}
+
+	runCtx, err := newRunContext(ctx, projects, opts)
+	if err != nil {
+		return nil, err
+	}
+	runCtx.Config.Projects = projectCfgs
+
+	parallelism, err := runCtx.GetParallelism()
+	if err != nil {
+		return nil, err
+	}
+
+	var exchangeRates prices.ExchangeRateSource
+	if runCtx.Config.ExchangeRatesSource != "" {
+		exchangeRates, err = prices.NewExchangeRateSource(runCtx.Config.ExchangeRatesSource)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var priceOverrides *prices.PriceOverrides
+	if runCtx.Config.PriceOverridesFile != "" {
+		priceOverrides, err = prices.LoadPriceOverrides(runCtx.Config.PriceOverridesFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, closePlugins, err := plugins.Register(runCtx)
+	if err != nil {
+		return nil, err
+	}
+	defer closePlugins()
+
+	// Projects are loaded in levels so that the projects they depend on, and
+	// whose outputs they use, have been loaded first.
+	levels, err := runCtx.Config.ProjectLevels()
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs providers.ProjectOutputs
+	loaded := make([][]*schema.Project, len(projectCfgs))
+	for _, level := range levels {
+		for _, i := range level {
+			loaded[i], err = loadProject(runCtx, projectCfgs[i], &outputs, prior == nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var all []*schema.Project
+	for _, l := range loaded {
+		all = append(all, l...)
+	}
+
+	schema.BuildResources(all, nil, parallelism)
+
+	for _, project := range all {
+		if err := prices.PopulatePrices(runCtx, project); err != nil {
+			return nil, err
+		}
+
+		if err := prices.CalculateCosts(runCtx, exchangeRates, priceOverrides, project); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := output.ToOutputFormat(all)
+	if err != nil {
+		return nil, err
+	}
+
+	if prior != nil {
+		r, err = output.CompareTo(r, prior.root)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r.Currency = runCtx.Config.Currency
+	r.Metadata = output.NewMetadata(runCtx)
+
+	return newEstimate(r), nil
+}
+
+// newRunContext returns the run context of the projects, with the settings of
+// the config file that they were loaded from and opts.
+func newRunContext(ctx context.Context, projects []Project, opts Options) (*config.RunContext, error) {
+	runCtx, err := config.NewRunContextFromEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := runCtx.Config
+	cfg.EventsDisabled = true
+	cfg.SkipUpdateCheck = true
+
+	configFile := ""
+	for _, p := range projects {
+		if p.configFile == "" {
+			continue
+		}
+
+		if configFile != "" && p.configFile != configFile {
+			return nil, fmt.Errorf("projects are loaded from more than one config file, %s and %s", configFile, p.configFile)
+		}
+		configFile = p.configFile
+	}
+
+	if configFile != "" {
+		err = cfg.LoadFromConfigFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ConfigFilePath = configFile
+	}
+
+	if opts.APIKey != "" {
+		cfg.APIKey = opts.APIKey
+	}
+	if opts.PricingAPIEndpoint != "" {
+		cfg.PricingAPIEndpoint = opts.PricingAPIEndpoint
+	}
+	if opts.Currency != "" {
+		cfg.Currency = opts.Currency
+	}
+	if opts.ExchangeRatesSource != "" {
+		cfg.ExchangeRatesSource = opts.ExchangeRatesSource
+	}
+	if opts.IncludeFreeTier {
+		cfg.IncludeFreeTier = true
+	}
+	if opts.Parallelism > 0 {
+		cfg.Parallelism = &opts.Parallelism
+	}
+	if opts.PriceOverridesFile != "" {
+		cfg.PriceOverridesFile = opts.PriceOverridesFile
+	}
+	if len(opts.Plugins) > 0 {
+		cfg.Plugins = opts.Plugins
+	}
+	if opts.ResourceMappings != "" {
+		cfg.ResourceMappings = opts.ResourceMappings
+	}
+
+	if cfg.APIKey == "" && cfg.PricingAPIEndpoint == "" {
+		return nil, errors.New("no Infracost API key, set Options.APIKey or INFRACOST_API_KEY")
+	}
+
+	// Progress messages of the providers are written to ErrWriter, which
+	// callers of the SDK don't expect.
+	runCtx.OutWriter = io.Discard
+	runCtx.ErrWriter = io.Discard
+
+	return runCtx, nil
+}
+
+// config returns the config of the project, which has the settings of the
+// project in its config file that aren't fields of Project.
+func (p Project) config() (*config.Project, error) {
+	if p.Path == "" {
+		return nil, errors.New("project has no path")
+	}
+
+	if _, err := os.Stat(p.Path); err != nil {
+		return nil, fmt.Errorf("project path %s does not exist", p.Path)
+	}
+
+	var projectCfg config.Project
+	if p.projectConfig != nil {
+		projectCfg = *p.projectConfig
+	}
+
+	projectCfg.Path = p.Path
+	projectCfg.Name = p.Name
+	projectCfg.UsageFile = p.UsageFile
+	projectCfg.ExcludePaths = p.ExcludePaths
+	projectCfg.TerraformVarFiles = p.TerraformVarFiles
+	projectCfg.TerraformVars = p.TerraformVars
+	projectCfg.TerraformWorkspace = p.TerraformWorkspace
+	projectCfg.UsageFileBefore = p.UsageFileBefore
+	projectCfg.Env = p.Env
+	projectCfg.DependsOn = p.DependsOn
+	projectCfg.TerraformOutputVars = p.TerraformOutputVars
+
+	return &projectCfg, nil
+}
+
+func loadProject(runCtx *config.RunContext, projectCfg *config.Project, outputs *providers.ProjectOutputs, includePastResources bool) ([]*schema.Project, error) {
+	ctx := config.NewProjectContext(runCtx, projectCfg, logrus.Fields{})
+
+	outputVars, err := outputs.Vars(runCtx.Config, projectCfg)
+	if err != nil {
+		return nil, err
+	}
+	ctx.OutputVars = outputVars
+
+	provider, err := providers.Detect(ctx, includePastResources)
+	if v, ok := err.(*providers.ValidationError); ok {
+		if v.Warn() == nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("could not detect the type of project %s: %w", projectCfg.Path, err)
+	}
+
+	usageData, pastUsageData, err := providers.LoadProjectUsage(ctx, runCtx.ErrWriter)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := provider.LoadResources(usageData)
+	if err != nil {
+		return nil, fmt.Errorf("could not load the resources of project %s: %w", projectCfg.Path, err)
+	}
+
+	providers.SetProjectConfig(projectCfg, projects, pastUsageData)
+	outputs.Set(projectCfg, projects)
+
+	return projects, nil
+}
+
+func newEstimate(r output.Root) *Estimate {
+	e := &Estimate{
+		Currency:             r.Currency,
+		TotalHourlyCost:      r.TotalHourlyCost,
+		TotalMonthlyCost:     r.TotalMonthlyCost,
+		PastTotalMonthlyCost: r.PastTotalMonthlyCost,
+		DiffTotalMonthlyCost: r.DiffTotalMonthlyCost,
+		root:                 r,
+	}
+
+	for _, p := range r.Projects {
+		pe := ProjectEstimate{Name: p.Name}
+		if p.Metadata != nil {
+			pe.Path = p.Metadata.Path
+		}
+
+		if p.Breakdown != nil {
+			pe.TotalMonthlyCost = p.Breakdown.TotalMonthlyCost
+			pe.Resources = newResources(p.Breakdown.Resources)
+		}
+
+		if p.PastBreakdown != nil {
+			pe.PastTotalMonthlyCost = p.PastBreakdown.TotalMonthlyCost
+			pe.PastResources = newResources(p.PastBreakdown.Resources)
+		}
+
+		if p.Diff != nil {
+			pe.DiffTotalMonthlyCost = p.Diff.TotalMonthlyCost
+		}
+
+		e.Projects = append(e.Projects, pe)
+	}
+
+	return e
+}
+
+func newResources(resources []output.Resource) []Resource {
+	if len(resources) == 0 {
+		return nil
+	}
+
+	out := make([]Resource, 0, len(resources))
+	for _, r := range resources {
+		res := Resource{
+			Name:         r.Name,
+			ResourceType: r.ResourceType(),
+			Tags:         r.Tags,
+			HourlyCost:   r.HourlyCost,
+			MonthlyCost:  r.MonthlyCost,
+			SubResources: newResources(r.SubResources),
+		}
+
+		for _, c := range r.CostComponents {
+			res.CostComponents = append(res.CostComponents, CostComponent{
+				Name:            c.Name,
+				Unit:            c.Unit,
+				Price:           c.Price,
+				HourlyQuantity:  c.HourlyQuantity,
+				MonthlyQuantity: c.MonthlyQuantity,
+				HourlyCost:      c.HourlyCost,
+				MonthlyCost:     c.MonthlyCost,
+			})
+		}
+
+		out = append(out, res)
+	}
+
+	return out
+}
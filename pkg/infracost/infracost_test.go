@@ -0,0 +1,160 @@
+package infracost
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func decimalPtr(f float64) *decimal.Decimal {
+	d := decimal.NewFromFloat(f)
+	return &d
+}
+
+func TestLoadProjects(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "infracost.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`version: 0.1
+discounts:
+  aws: 10%
+projects:
+  - path: prod
+    name: production
+    usage_file: prod-usage.yml
+    usage_file_before: prod-usage-before.yml
+    terraform_var_files: [prod.tfvars]
+    terraform_vars:
+      instance_count: "3"
+    terraform_output_vars:
+      vpc_id: dev.vpc_id
+    env:
+      AWS_REGION: us-east-1
+    growth:
+      monthly_rate: 2
+  - path: dev
+    terraform_workspace: dev
+`), 0600))
+
+	projects, err := LoadProjects(path)
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+
+	prod := projects[0]
+	assert.Equal(t, "prod", prod.Path)
+	assert.Equal(t, "production", prod.Name)
+	assert.Equal(t, "prod-usage.yml", prod.UsageFile)
+	assert.Equal(t, "prod-usage-before.yml", prod.UsageFileBefore)
+	assert.Equal(t, []string{"prod.tfvars"}, prod.TerraformVarFiles)
+	assert.Equal(t, map[string]string{"instance_count": "3"}, prod.TerraformVars)
+	assert.Equal(t, map[string]string{"vpc_id": "dev.vpc_id"}, prod.TerraformOutputVars)
+	assert.Equal(t, map[string]string{"AWS_REGION": "us-east-1"}, prod.Env)
+
+	// The settings of the config file that aren't fields of Project are
+	// still used to estimate the projects.
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "prod"), 0700))
+	prod.Path = filepath.Join(dir, "prod")
+	projectCfg, err := prod.config()
+	require.NoError(t, err)
+	assert.Equal(t, prod.Path, projectCfg.Path)
+	require.NotNil(t, projectCfg.Growth)
+
+	runCtx, err := newRunContext(context.Background(), projects, Options{APIKey: "test"})
+	require.NoError(t, err)
+	require.Len(t, runCtx.Config.Discounts, 1)
+	assert.Equal(t, "aws", runCtx.Config.Discounts[0].Vendor)
+
+	dev := projects[1]
+	assert.Equal(t, "dev", dev.Path)
+	assert.Equal(t, "dev", dev.TerraformWorkspace)
+
+	_, err = newRunContext(context.Background(), []Project{prod, {Path: "other", configFile: "other.yml"}}, Options{APIKey: "test"})
+	assert.EqualError(t, err, "projects are loaded from more than one config file, "+path+" and other.yml")
+
+	_, err = LoadProjects(filepath.Join(dir, "missing.yml"))
+	assert.Error(t, err)
+}
+
+func TestBreakdownInvalid(t *testing.T) {
+	opts := Options{APIKey: "test"}
+
+	_, err := Breakdown(context.Background(), nil, opts)
+	assert.EqualError(t, err, "no projects to estimate")
+
+	_, err = Breakdown(context.Background(), []Project{{}}, opts)
+	assert.EqualError(t, err, "project has no path")
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	_, err = Breakdown(context.Background(), []Project{{Path: missing}}, opts)
+	assert.EqualError(t, err, "project path "+missing+" does not exist")
+}
+
+func TestEstimateOutput(t *testing.T) {
+	r := output.Root{
+		Version:          "0.2",
+		Currency:         "USD",
+		TotalHourlyCost:  decimalPtr(0.1),
+		TotalMonthlyCost: decimalPtr(73),
+		Projects: output.Projects{
+			{
+				Name:     "infracost/infracost/prod",
+				Metadata: &schema.ProjectMetadata{Path: "prod"},
+				Breakdown: &output.Breakdown{
+					TotalMonthlyCost: decimalPtr(73),
+					Resources: []output.Resource{
+						{
+							Name:        "aws_instance.web",
+							Tags:        map[string]string{"team": "platform"},
+							HourlyCost:  decimalPtr(0.1),
+							MonthlyCost: decimalPtr(73),
+							CostComponents: []output.CostComponent{
+								{
+									Name:            "Instance usage (Linux/UNIX, on-demand, t3.large)",
+									Unit:            "hours",
+									Price:           decimal.NewFromFloat(0.1),
+									HourlyQuantity:  decimalPtr(1),
+									MonthlyQuantity: decimalPtr(730),
+									HourlyCost:      decimalPtr(0.1),
+									MonthlyCost:     decimalPtr(73),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	e := newEstimate(r)
+
+	assert.Equal(t, "USD", e.Currency)
+	assert.Equal(t, "73", e.TotalMonthlyCost.String())
+	require.Len(t, e.Projects, 1)
+
+	p := e.Projects[0]
+	assert.Equal(t, "infracost/infracost/prod", p.Name)
+	assert.Equal(t, "prod", p.Path)
+	assert.Nil(t, p.PastResources)
+	require.Len(t, p.Resources, 1)
+	assert.Equal(t, "aws_instance", p.Resources[0].ResourceType)
+	assert.Equal(t, map[string]string{"team": "platform"}, p.Resources[0].Tags)
+	require.Len(t, p.Resources[0].CostComponents, 1)
+	assert.Equal(t, "730", p.Resources[0].CostComponents[0].MonthlyQuantity.String())
+
+	var buf bytes.Buffer
+	require.NoError(t, Output(&buf, e, "json", OutputOptions{}))
+	assert.Contains(t, buf.String(), `"name":"aws_instance.web"`)
+
+	buf.Reset()
+	require.NoError(t, Output(&buf, e, "table", OutputOptions{NoColor: true}))
+	assert.Contains(t, buf.String(), "aws_instance.web")
+	assert.Contains(t, buf.String(), "$73.00")
+}
@@ -0,0 +1,218 @@
+// Package plugin is used to write plugin executables that build the costs of
+// resources of Terraform providers that Infracost doesn't support, e.g. the
+// proprietary providers of an organization. A plugin implements
+// ResourceBuilder and serves it from its main func:
+//
+//	func main() {
+//		plugin.Serve(&acmeBuilder{})
+//	}
+//
+// Plugins are run with hashicorp/go-plugin over net/rpc. Infracost starts the
+// executable once per run, checks the Handshake, calls Describe and then calls
+// Build for each resource of the types that the plugin describes. Build isn't
+// called concurrently. Anything that the plugin writes to stderr is written to
+// the debug log of Infracost.
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/rpc"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/shopspring/decimal"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// Name is the name that the ResourceBuilder of a plugin is served as.
+const Name = "resource_builder"
+
+// Handshake is checked by Infracost and the plugin before the plugin is used,
+// so only executables that are built with a compatible version of this package
+// are run. The protocol version changes when the calls or their types change
+// in a way that isn't compatible.
+var Handshake = goplugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "INFRACOST_PLUGIN",
+	MagicCookieValue: "resource_builder",
+}
+
+// ResourceBuilder builds the costs of the resources of some resource types.
+type ResourceBuilder interface {
+	// Describe returns the name of the plugin and the resource types that it
+	// builds the costs of. Plugins can't build the costs of resource types
+	// that Infracost supports.
+	Describe() (*Description, error)
+	// Build returns the resource and cost components of d. The resource is
+	// skipped if an error is returned.
+	Build(d *ResourceData) (*Resource, error)
+}
+
+// Description describes a plugin.
+type Description struct {
+	// Name is shown in the notes of the resource types. It defaults to the
+	// name of the executable.
+	Name          string
+	ResourceTypes []string
+}
+
+// ResourceData is the parsed data of a resource that a plugin builds the
+// costs of.
+type ResourceData struct {
+	Address      string
+	Type         string
+	ProviderName string
+	// Values is the JSON object of the attributes of the resource.
+	Values json.RawMessage
+	Tags   map[string]string
+	// Usage holds the JSON values of the usage of the resource in the usage
+	// file, keyed by their usage key.
+	Usage map[string]json.RawMessage
+}
+
+// Resource is a resource that a plugin builds. It's converted to a resource of
+// Infracost, so it has the same fields apart from the ones that Infracost sets
+// once it's built or that need Go funcs: the hourly, monthly and ranged
+// costs, usage estimation, actual costs, recommendations, metadata, shared
+// cost keys and price fallbacks can't be set by a plugin.
+type Resource struct {
+	// Name defaults to the address of the resource data.
+	Name string
+	// ResourceType defaults to the type of the resource data. Sub resources
+	// should set it so they can be grouped and filtered by type.
+	ResourceType   string
+	Tags           map[string]string
+	CostComponents []*CostComponent
+	SubResources   []*Resource
+	// UsageSchema lists the usage keys that the resource uses, which are
+	// added to usage files that are synced with the resource.
+	UsageSchema []*UsageItem
+	NoPrice     bool
+	IsSkipped   bool
+	SkipMessage string
+}
+
+// CostComponent is a cost component of a Resource. It must have either a
+// Price or a ProductFilter to look up its price with.
+type CostComponent struct {
+	Name string
+	Unit string
+	// UnitMultiplier defaults to 1.
+	UnitMultiplier       *decimal.Decimal
+	HourlyQuantity       *decimal.Decimal
+	MonthlyQuantity      *decimal.Decimal
+	Price                *decimal.Decimal
+	ProductFilter        *ProductFilter
+	PriceFilter          *PriceFilter
+	IgnoreIfMissingPrice bool
+	MonthlyDiscountPerc  float64
+}
+
+// UsageItem is a usage key of a resource. DefaultValue must be a string,
+// int64, float64 or []string.
+type UsageItem struct {
+	Key          string
+	ValueType    UsageVariableType
+	DefaultValue interface{}
+	Description  string
+}
+
+// The filters and usage value types are the same as the ones of Infracost's
+// own resources.
+type (
+	ProductFilter     = schema.ProductFilter
+	PriceFilter       = schema.PriceFilter
+	AttributeFilter   = schema.AttributeFilter
+	UsageVariableType = schema.UsageVariableType
+)
+
+const (
+	Int64       = schema.Int64
+	String      = schema.String
+	Float64     = schema.Float64
+	StringArray = schema.StringArray
+)
+
+// Serve serves b to Infracost. It must be called from the main func of the
+// plugin executable, and only returns once Infracost has closed the plugin.
+func Serve(b ResourceBuilder) {
+	goplugin.Serve(&goplugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins:         goplugin.PluginSet{Name: &builderPlugin{impl: b}},
+	})
+}
+
+// ClientPlugins returns the plugin set that Infracost dispenses the
+// ResourceBuilder of a plugin from.
+func ClientPlugins() goplugin.PluginSet {
+	return goplugin.PluginSet{Name: &builderPlugin{}}
+}
+
+// builderPlugin serves a ResourceBuilder over net/rpc.
+type builderPlugin struct {
+	impl ResourceBuilder
+}
+
+func (p *builderPlugin) Server(*goplugin.MuxBroker) (interface{}, error) {
+	return &rpcServer{impl: p.impl}, nil
+}
+
+func (p *builderPlugin) Client(_ *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &rpcClient{client: c}, nil
+}
+
+type rpcServer struct {
+	impl ResourceBuilder
+}
+
+func (s *rpcServer) Describe(_ interface{}, resp *Description) error {
+	d, err := s.impl.Describe()
+	if err != nil {
+		return err
+	}
+
+	if d != nil {
+		*resp = *d
+	}
+
+	return nil
+}
+
+func (s *rpcServer) Build(d *ResourceData, resp *Resource) error {
+	r, err := s.impl.Build(d)
+	if err != nil {
+		return err
+	}
+
+	if r == nil {
+		return errors.New("no resource was built")
+	}
+
+	*resp = *r
+	return nil
+}
+
+type rpcClient struct {
+	client *rpc.Client
+}
+
+func (c *rpcClient) Describe() (*Description, error) {
+	var resp Description
+	err := c.client.Call("Plugin.Describe", new(interface{}), &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (c *rpcClient) Build(d *ResourceData) (*Resource, error) {
+	var resp Resource
+	err := c.client.Call("Plugin.Build", d, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}